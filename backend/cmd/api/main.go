@@ -1,9 +1,11 @@
 package main
 
 import (
+	"context"
 	"log"
 	"todo-app/internal/db"
 	"todo-app/internal/router"
+	"todo-app/internal/services"
 	"todo-app/internal/utils"
 )
 
@@ -26,7 +28,18 @@ func main() {
 		log.Fatal(err)
 	}
 
-	r := router.SetupRouter(sqlClient, redisStore)
+	r, err := router.SetupRouter(sqlClient, redisStore)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	blobStore, err := router.SetupBlobStore()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Running it on every instance is fine, as concurrent purges skip the rows locked by each other
+	go runAccountPurger(context.Background(), services.NewAccountPurger(db.NewWrappedQuerier(sqlClient), blobStore))
 
 	if err := r.Run(":8080"); err != nil {
 		log.Fatalf("Server failed to start: %v", err)
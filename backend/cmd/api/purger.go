@@ -0,0 +1,39 @@
+package main
+
+import (
+	"context"
+	"log"
+	"time"
+	"todo-app/internal/services"
+)
+
+// Purges the accounts whose deletion grace period has passed, right away and then every interval until ctx is done
+func runAccountPurger(ctx context.Context, purger *services.AccountPurger) {
+	ticker := time.NewTicker(purger.Interval)
+	defer ticker.Stop()
+
+	for {
+		purgeAccounts(ctx, purger)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func purgeAccounts(ctx context.Context, purger *services.AccountPurger) {
+	start := time.Now()
+
+	summary, err := purger.Purge(ctx)
+	if err != nil {
+		log.Printf("account purge failed after purging %d accounts: %v", summary.Purged, err)
+		return
+	}
+
+	// Nothing is logged on the usual runs with nothing to purge
+	if summary.Purged > 0 {
+		log.Printf("account purge: purged %d accounts in %d batches in %s", summary.Purged, summary.Batches, time.Since(start).Round(time.Millisecond))
+	}
+}
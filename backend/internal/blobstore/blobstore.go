@@ -0,0 +1,17 @@
+// Storage for uploaded files; the implementations behind services.IBlobStore are LocalStore.
+// Keys are slash separated paths such as avatars/<user id>, and Get and Delete return an error wrapping fs.ErrNotExist when nothing is stored under the key.
+package blobstore
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+func validateKey(key string) error {
+	if key == "" || path.IsAbs(key) || path.Clean(key) != key || key == ".." || strings.HasPrefix(key, "../") {
+		return fmt.Errorf("invalid blob key %q", key)
+	}
+
+	return nil
+}
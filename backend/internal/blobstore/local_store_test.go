@@ -0,0 +1,43 @@
+package blobstore_test
+
+import (
+	"context"
+	"io"
+	"io/fs"
+	"strings"
+	"testing"
+	"todo-app/internal/blobstore"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalStore(t *testing.T) {
+	store := blobstore.NewLocalStore(t.TempDir())
+	ctx := context.Background()
+
+	t.Run("PutGetDelete", func(t *testing.T) {
+		require.NoError(t, store.Put(ctx, "avatars/user-1", strings.NewReader("first")))
+		// A second put replaces the blob
+		require.NoError(t, store.Put(ctx, "avatars/user-1", strings.NewReader("second")))
+
+		r, err := store.Get(ctx, "avatars/user-1")
+		require.NoError(t, err)
+		b, err := io.ReadAll(r)
+		r.Close()
+		require.NoError(t, err)
+		assert.Equal(t, "second", string(b))
+
+		require.NoError(t, store.Delete(ctx, "avatars/user-1"))
+
+		_, err = store.Get(ctx, "avatars/user-1")
+		assert.ErrorIs(t, err, fs.ErrNotExist)
+		assert.ErrorIs(t, store.Delete(ctx, "avatars/user-1"), fs.ErrNotExist)
+	})
+
+	t.Run("InvalidKey", func(t *testing.T) {
+		for _, key := range []string{"", "/etc/passwd", "../outside", "avatars/../../outside", "avatars//user-1"} {
+			assert.Error(t, store.Put(ctx, key, strings.NewReader("blob")), key)
+		}
+	})
+}
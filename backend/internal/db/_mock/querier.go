@@ -43,6 +43,197 @@ func (m *MockWrappedQuerier) EXPECT() *MockWrappedQuerierMockRecorder {
 	return m.recorder
 }
 
+// AttachTodoTag mocks base method.
+func (m *MockWrappedQuerier) AttachTodoTag(ctx context.Context, arg db.AttachTodoTagParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AttachTodoTag", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AttachTodoTag indicates an expected call of AttachTodoTag.
+func (mr *MockWrappedQuerierMockRecorder) AttachTodoTag(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AttachTodoTag", reflect.TypeOf((*MockWrappedQuerier)(nil).AttachTodoTag), ctx, arg)
+}
+
+// ClearUserAvatar mocks base method.
+func (m *MockWrappedQuerier) ClearUserAvatar(ctx context.Context, userID int32) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClearUserAvatar", ctx, userID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClearUserAvatar indicates an expected call of ClearUserAvatar.
+func (mr *MockWrappedQuerierMockRecorder) ClearUserAvatar(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClearUserAvatar", reflect.TypeOf((*MockWrappedQuerier)(nil).ClearUserAvatar), ctx, userID)
+}
+
+// CompleteTodoItems mocks base method.
+func (m *MockWrappedQuerier) CompleteTodoItems(ctx context.Context, todoID int32) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompleteTodoItems", ctx, todoID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CompleteTodoItems indicates an expected call of CompleteTodoItems.
+func (mr *MockWrappedQuerierMockRecorder) CompleteTodoItems(ctx, todoID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteTodoItems", reflect.TypeOf((*MockWrappedQuerier)(nil).CompleteTodoItems), ctx, todoID)
+}
+
+// ConsumePasswordResetToken mocks base method.
+func (m *MockWrappedQuerier) ConsumePasswordResetToken(ctx context.Context, tokenHash string) (db.PasswordResetToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConsumePasswordResetToken", ctx, tokenHash)
+	ret0, _ := ret[0].(db.PasswordResetToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ConsumePasswordResetToken indicates an expected call of ConsumePasswordResetToken.
+func (mr *MockWrappedQuerierMockRecorder) ConsumePasswordResetToken(ctx, tokenHash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConsumePasswordResetToken", reflect.TypeOf((*MockWrappedQuerier)(nil).ConsumePasswordResetToken), ctx, tokenHash)
+}
+
+// CopyTodoItems mocks base method.
+func (m *MockWrappedQuerier) CopyTodoItems(ctx context.Context, arg db.CopyTodoItemsParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CopyTodoItems", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CopyTodoItems indicates an expected call of CopyTodoItems.
+func (mr *MockWrappedQuerierMockRecorder) CopyTodoItems(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CopyTodoItems", reflect.TypeOf((*MockWrappedQuerier)(nil).CopyTodoItems), ctx, arg)
+}
+
+// CopyTodoTags mocks base method.
+func (m *MockWrappedQuerier) CopyTodoTags(ctx context.Context, arg db.CopyTodoTagsParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CopyTodoTags", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CopyTodoTags indicates an expected call of CopyTodoTags.
+func (mr *MockWrappedQuerierMockRecorder) CopyTodoTags(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CopyTodoTags", reflect.TypeOf((*MockWrappedQuerier)(nil).CopyTodoTags), ctx, arg)
+}
+
+// CountTodos mocks base method.
+func (m *MockWrappedQuerier) CountTodos(ctx context.Context, userID int32) (db.CountTodosRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountTodos", ctx, userID)
+	ret0, _ := ret[0].(db.CountTodosRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountTodos indicates an expected call of CountTodos.
+func (mr *MockWrappedQuerierMockRecorder) CountTodos(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountTodos", reflect.TypeOf((*MockWrappedQuerier)(nil).CountTodos), ctx, userID)
+}
+
+// CountUsers mocks base method.
+func (m *MockWrappedQuerier) CountUsers(ctx context.Context, pattern string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountUsers", ctx, pattern)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountUsers indicates an expected call of CountUsers.
+func (mr *MockWrappedQuerierMockRecorder) CountUsers(ctx, pattern any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountUsers", reflect.TypeOf((*MockWrappedQuerier)(nil).CountUsers), ctx, pattern)
+}
+
+// CreateList mocks base method.
+func (m *MockWrappedQuerier) CreateList(ctx context.Context, arg db.CreateListParams) (db.List, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateList", ctx, arg)
+	ret0, _ := ret[0].(db.List)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateList indicates an expected call of CreateList.
+func (mr *MockWrappedQuerierMockRecorder) CreateList(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateList", reflect.TypeOf((*MockWrappedQuerier)(nil).CreateList), ctx, arg)
+}
+
+// CreateOIDCUser mocks base method.
+func (m *MockWrappedQuerier) CreateOIDCUser(ctx context.Context, email string) (db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateOIDCUser", ctx, email)
+	ret0, _ := ret[0].(db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateOIDCUser indicates an expected call of CreateOIDCUser.
+func (mr *MockWrappedQuerierMockRecorder) CreateOIDCUser(ctx, email any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOIDCUser", reflect.TypeOf((*MockWrappedQuerier)(nil).CreateOIDCUser), ctx, email)
+}
+
+// CreatePasswordResetToken mocks base method.
+func (m *MockWrappedQuerier) CreatePasswordResetToken(ctx context.Context, arg db.CreatePasswordResetTokenParams) (db.PasswordResetToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePasswordResetToken", ctx, arg)
+	ret0, _ := ret[0].(db.PasswordResetToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreatePasswordResetToken indicates an expected call of CreatePasswordResetToken.
+func (mr *MockWrappedQuerierMockRecorder) CreatePasswordResetToken(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePasswordResetToken", reflect.TypeOf((*MockWrappedQuerier)(nil).CreatePasswordResetToken), ctx, arg)
+}
+
+// CreatePersonalAccessToken mocks base method.
+func (m *MockWrappedQuerier) CreatePersonalAccessToken(ctx context.Context, arg db.CreatePersonalAccessTokenParams) (db.PersonalAccessToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePersonalAccessToken", ctx, arg)
+	ret0, _ := ret[0].(db.PersonalAccessToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreatePersonalAccessToken indicates an expected call of CreatePersonalAccessToken.
+func (mr *MockWrappedQuerierMockRecorder) CreatePersonalAccessToken(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePersonalAccessToken", reflect.TypeOf((*MockWrappedQuerier)(nil).CreatePersonalAccessToken), ctx, arg)
+}
+
+// CreateTag mocks base method.
+func (m *MockWrappedQuerier) CreateTag(ctx context.Context, arg db.CreateTagParams) (db.Tag, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTag", ctx, arg)
+	ret0, _ := ret[0].(db.Tag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTag indicates an expected call of CreateTag.
+func (mr *MockWrappedQuerierMockRecorder) CreateTag(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTag", reflect.TypeOf((*MockWrappedQuerier)(nil).CreateTag), ctx, arg)
+}
+
 // CreateTodo mocks base method.
 func (m *MockWrappedQuerier) CreateTodo(ctx context.Context, arg db.CreateTodoParams) (db.Todo, error) {
 	m.ctrl.T.Helper()
@@ -58,6 +249,21 @@ func (mr *MockWrappedQuerierMockRecorder) CreateTodo(ctx, arg any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTodo", reflect.TypeOf((*MockWrappedQuerier)(nil).CreateTodo), ctx, arg)
 }
 
+// CreateTodoItem mocks base method.
+func (m *MockWrappedQuerier) CreateTodoItem(ctx context.Context, arg db.CreateTodoItemParams) (db.TodoItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTodoItem", ctx, arg)
+	ret0, _ := ret[0].(db.TodoItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTodoItem indicates an expected call of CreateTodoItem.
+func (mr *MockWrappedQuerierMockRecorder) CreateTodoItem(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTodoItem", reflect.TypeOf((*MockWrappedQuerier)(nil).CreateTodoItem), ctx, arg)
+}
+
 // CreateUser mocks base method.
 func (m *MockWrappedQuerier) CreateUser(ctx context.Context, arg db.CreateUserParams) (db.User, error) {
 	m.ctrl.T.Helper()
@@ -73,6 +279,66 @@ func (mr *MockWrappedQuerierMockRecorder) CreateUser(ctx, arg any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUser", reflect.TypeOf((*MockWrappedQuerier)(nil).CreateUser), ctx, arg)
 }
 
+// CreateUserIdentity mocks base method.
+func (m *MockWrappedQuerier) CreateUserIdentity(ctx context.Context, arg db.CreateUserIdentityParams) (db.UserIdentity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateUserIdentity", ctx, arg)
+	ret0, _ := ret[0].(db.UserIdentity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateUserIdentity indicates an expected call of CreateUserIdentity.
+func (mr *MockWrappedQuerierMockRecorder) CreateUserIdentity(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUserIdentity", reflect.TypeOf((*MockWrappedQuerier)(nil).CreateUserIdentity), ctx, arg)
+}
+
+// DeleteList mocks base method.
+func (m *MockWrappedQuerier) DeleteList(ctx context.Context, arg db.DeleteListParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteList", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteList indicates an expected call of DeleteList.
+func (mr *MockWrappedQuerierMockRecorder) DeleteList(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteList", reflect.TypeOf((*MockWrappedQuerier)(nil).DeleteList), ctx, arg)
+}
+
+// DeletePersonalAccessToken mocks base method.
+func (m *MockWrappedQuerier) DeletePersonalAccessToken(ctx context.Context, arg db.DeletePersonalAccessTokenParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeletePersonalAccessToken", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeletePersonalAccessToken indicates an expected call of DeletePersonalAccessToken.
+func (mr *MockWrappedQuerierMockRecorder) DeletePersonalAccessToken(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePersonalAccessToken", reflect.TypeOf((*MockWrappedQuerier)(nil).DeletePersonalAccessToken), ctx, arg)
+}
+
+// DeleteTag mocks base method.
+func (m *MockWrappedQuerier) DeleteTag(ctx context.Context, arg db.DeleteTagParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTag", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteTag indicates an expected call of DeleteTag.
+func (mr *MockWrappedQuerierMockRecorder) DeleteTag(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTag", reflect.TypeOf((*MockWrappedQuerier)(nil).DeleteTag), ctx, arg)
+}
+
 // DeleteTodo mocks base method.
 func (m *MockWrappedQuerier) DeleteTodo(ctx context.Context, arg db.DeleteTodoParams) (db.Todo, error) {
 	m.ctrl.T.Helper()
@@ -88,19 +354,180 @@ func (mr *MockWrappedQuerierMockRecorder) DeleteTodo(ctx, arg any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTodo", reflect.TypeOf((*MockWrappedQuerier)(nil).DeleteTodo), ctx, arg)
 }
 
-// DeleteUser mocks base method.
-func (m *MockWrappedQuerier) DeleteUser(ctx context.Context, userID pgtype.UUID) (db.User, error) {
+// DeleteTodoItem mocks base method.
+func (m *MockWrappedQuerier) DeleteTodoItem(ctx context.Context, arg db.DeleteTodoItemParams) (int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteUser", ctx, userID)
-	ret0, _ := ret[0].(db.User)
+	ret := m.ctrl.Call(m, "DeleteTodoItem", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteTodoItem indicates an expected call of DeleteTodoItem.
+func (mr *MockWrappedQuerierMockRecorder) DeleteTodoItem(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTodoItem", reflect.TypeOf((*MockWrappedQuerier)(nil).DeleteTodoItem), ctx, arg)
+}
+
+// DetachTodoTag mocks base method.
+func (m *MockWrappedQuerier) DetachTodoTag(ctx context.Context, arg db.DetachTodoTagParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DetachTodoTag", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DetachTodoTag indicates an expected call of DetachTodoTag.
+func (mr *MockWrappedQuerierMockRecorder) DetachTodoTag(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DetachTodoTag", reflect.TypeOf((*MockWrappedQuerier)(nil).DetachTodoTag), ctx, arg)
+}
+
+// DisableTOTP mocks base method.
+func (m *MockWrappedQuerier) DisableTOTP(ctx context.Context, userID pgtype.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DisableTOTP", ctx, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DisableTOTP indicates an expected call of DisableTOTP.
+func (mr *MockWrappedQuerierMockRecorder) DisableTOTP(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisableTOTP", reflect.TypeOf((*MockWrappedQuerier)(nil).DisableTOTP), ctx, userID)
+}
+
+// DisableUser mocks base method.
+func (m *MockWrappedQuerier) DisableUser(ctx context.Context, userID pgtype.UUID) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DisableUser", ctx, userID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DisableUser indicates an expected call of DisableUser.
+func (mr *MockWrappedQuerierMockRecorder) DisableUser(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisableUser", reflect.TypeOf((*MockWrappedQuerier)(nil).DisableUser), ctx, userID)
+}
+
+// EnableTOTP mocks base method.
+func (m *MockWrappedQuerier) EnableTOTP(ctx context.Context, arg db.EnableTOTPParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnableTOTP", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EnableTOTP indicates an expected call of EnableTOTP.
+func (mr *MockWrappedQuerierMockRecorder) EnableTOTP(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnableTOTP", reflect.TypeOf((*MockWrappedQuerier)(nil).EnableTOTP), ctx, arg)
+}
+
+// EnableUser mocks base method.
+func (m *MockWrappedQuerier) EnableUser(ctx context.Context, userID pgtype.UUID) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnableUser", ctx, userID)
+	ret0, _ := ret[0].(int64)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// DeleteUser indicates an expected call of DeleteUser.
-func (mr *MockWrappedQuerierMockRecorder) DeleteUser(ctx, userID any) *gomock.Call {
+// EnableUser indicates an expected call of EnableUser.
+func (mr *MockWrappedQuerierMockRecorder) EnableUser(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnableUser", reflect.TypeOf((*MockWrappedQuerier)(nil).EnableUser), ctx, userID)
+}
+
+// ExecTx mocks base method.
+func (m *MockWrappedQuerier) ExecTx(ctx context.Context, fn func(db.WrappedQuerier) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExecTx", ctx, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExecTx indicates an expected call of ExecTx.
+func (mr *MockWrappedQuerierMockRecorder) ExecTx(ctx, fn any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUser", reflect.TypeOf((*MockWrappedQuerier)(nil).DeleteUser), ctx, userID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExecTx", reflect.TypeOf((*MockWrappedQuerier)(nil).ExecTx), ctx, fn)
+}
+
+// GetInboxList mocks base method.
+func (m *MockWrappedQuerier) GetInboxList(ctx context.Context, userID int32) (db.List, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetInboxList", ctx, userID)
+	ret0, _ := ret[0].(db.List)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetInboxList indicates an expected call of GetInboxList.
+func (mr *MockWrappedQuerierMockRecorder) GetInboxList(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInboxList", reflect.TypeOf((*MockWrappedQuerier)(nil).GetInboxList), ctx, userID)
+}
+
+// GetList mocks base method.
+func (m *MockWrappedQuerier) GetList(ctx context.Context, arg db.GetListParams) (db.List, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetList", ctx, arg)
+	ret0, _ := ret[0].(db.List)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetList indicates an expected call of GetList.
+func (mr *MockWrappedQuerierMockRecorder) GetList(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetList", reflect.TypeOf((*MockWrappedQuerier)(nil).GetList), ctx, arg)
+}
+
+// GetTag mocks base method.
+func (m *MockWrappedQuerier) GetTag(ctx context.Context, arg db.GetTagParams) (db.Tag, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTag", ctx, arg)
+	ret0, _ := ret[0].(db.Tag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTag indicates an expected call of GetTag.
+func (mr *MockWrappedQuerierMockRecorder) GetTag(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTag", reflect.TypeOf((*MockWrappedQuerier)(nil).GetTag), ctx, arg)
+}
+
+// GetTodo mocks base method.
+func (m *MockWrappedQuerier) GetTodo(ctx context.Context, arg db.GetTodoParams) (db.Todo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTodo", ctx, arg)
+	ret0, _ := ret[0].(db.Todo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTodo indicates an expected call of GetTodo.
+func (mr *MockWrappedQuerierMockRecorder) GetTodo(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTodo", reflect.TypeOf((*MockWrappedQuerier)(nil).GetTodo), ctx, arg)
+}
+
+// GetTodoForUpdate mocks base method.
+func (m *MockWrappedQuerier) GetTodoForUpdate(ctx context.Context, arg db.GetTodoForUpdateParams) (db.Todo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTodoForUpdate", ctx, arg)
+	ret0, _ := ret[0].(db.Todo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTodoForUpdate indicates an expected call of GetTodoForUpdate.
+func (mr *MockWrappedQuerierMockRecorder) GetTodoForUpdate(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTodoForUpdate", reflect.TypeOf((*MockWrappedQuerier)(nil).GetTodoForUpdate), ctx, arg)
 }
 
 // GetUserByEmail mocks base method.
@@ -148,26 +575,263 @@ func (mr *MockWrappedQuerierMockRecorder) GetUserByUserID(ctx, userID any) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByUserID", reflect.TypeOf((*MockWrappedQuerier)(nil).GetUserByUserID), ctx, userID)
 }
 
+// GetUserIdentity mocks base method.
+func (m *MockWrappedQuerier) GetUserIdentity(ctx context.Context, arg db.GetUserIdentityParams) (db.UserIdentity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserIdentity", ctx, arg)
+	ret0, _ := ret[0].(db.UserIdentity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserIdentity indicates an expected call of GetUserIdentity.
+func (mr *MockWrappedQuerierMockRecorder) GetUserIdentity(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserIdentity", reflect.TypeOf((*MockWrappedQuerier)(nil).GetUserIdentity), ctx, arg)
+}
+
+// GetUserPreferences mocks base method.
+func (m *MockWrappedQuerier) GetUserPreferences(ctx context.Context, userID int32) (db.UserPreference, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserPreferences", ctx, userID)
+	ret0, _ := ret[0].(db.UserPreference)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserPreferences indicates an expected call of GetUserPreferences.
+func (mr *MockWrappedQuerierMockRecorder) GetUserPreferences(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserPreferences", reflect.TypeOf((*MockWrappedQuerier)(nil).GetUserPreferences), ctx, userID)
+}
+
+// InvalidatePasswordResetTokens mocks base method.
+func (m *MockWrappedQuerier) InvalidatePasswordResetTokens(ctx context.Context, userID int32) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InvalidatePasswordResetTokens", ctx, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InvalidatePasswordResetTokens indicates an expected call of InvalidatePasswordResetTokens.
+func (mr *MockWrappedQuerierMockRecorder) InvalidatePasswordResetTokens(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InvalidatePasswordResetTokens", reflect.TypeOf((*MockWrappedQuerier)(nil).InvalidatePasswordResetTokens), ctx, userID)
+}
+
+// ListLists mocks base method.
+func (m *MockWrappedQuerier) ListLists(ctx context.Context, arg db.ListListsParams) ([]db.List, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListLists", ctx, arg)
+	ret0, _ := ret[0].([]db.List)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListLists indicates an expected call of ListLists.
+func (mr *MockWrappedQuerierMockRecorder) ListLists(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListLists", reflect.TypeOf((*MockWrappedQuerier)(nil).ListLists), ctx, arg)
+}
+
+// ListPersonalAccessTokens mocks base method.
+func (m *MockWrappedQuerier) ListPersonalAccessTokens(ctx context.Context, userID int32) ([]db.PersonalAccessToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPersonalAccessTokens", ctx, userID)
+	ret0, _ := ret[0].([]db.PersonalAccessToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPersonalAccessTokens indicates an expected call of ListPersonalAccessTokens.
+func (mr *MockWrappedQuerierMockRecorder) ListPersonalAccessTokens(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPersonalAccessTokens", reflect.TypeOf((*MockWrappedQuerier)(nil).ListPersonalAccessTokens), ctx, userID)
+}
+
+// ListTags mocks base method.
+func (m *MockWrappedQuerier) ListTags(ctx context.Context, userID int32) ([]db.Tag, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTags", ctx, userID)
+	ret0, _ := ret[0].([]db.Tag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTags indicates an expected call of ListTags.
+func (mr *MockWrappedQuerierMockRecorder) ListTags(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTags", reflect.TypeOf((*MockWrappedQuerier)(nil).ListTags), ctx, userID)
+}
+
+// ListTodoItems mocks base method.
+func (m *MockWrappedQuerier) ListTodoItems(ctx context.Context, todoID int32) ([]db.TodoItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTodoItems", ctx, todoID)
+	ret0, _ := ret[0].([]db.TodoItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTodoItems indicates an expected call of ListTodoItems.
+func (mr *MockWrappedQuerierMockRecorder) ListTodoItems(ctx, todoID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTodoItems", reflect.TypeOf((*MockWrappedQuerier)(nil).ListTodoItems), ctx, todoID)
+}
+
+// ListTodoItemsByUser mocks base method.
+func (m *MockWrappedQuerier) ListTodoItemsByUser(ctx context.Context, userID int32) ([]db.TodoItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTodoItemsByUser", ctx, userID)
+	ret0, _ := ret[0].([]db.TodoItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTodoItemsByUser indicates an expected call of ListTodoItemsByUser.
+func (mr *MockWrappedQuerierMockRecorder) ListTodoItemsByUser(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTodoItemsByUser", reflect.TypeOf((*MockWrappedQuerier)(nil).ListTodoItemsByUser), ctx, userID)
+}
+
 // ListTodos mocks base method.
-func (m *MockWrappedQuerier) ListTodos(ctx context.Context, userID int32) ([]db.Todo, error) {
+func (m *MockWrappedQuerier) ListTodos(ctx context.Context, arg db.ListTodosParams) ([]db.Todo, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListTodos", ctx, userID)
+	ret := m.ctrl.Call(m, "ListTodos", ctx, arg)
 	ret0, _ := ret[0].([]db.Todo)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // ListTodos indicates an expected call of ListTodos.
-func (mr *MockWrappedQuerierMockRecorder) ListTodos(ctx, userID any) *gomock.Call {
+func (mr *MockWrappedQuerierMockRecorder) ListTodos(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTodos", reflect.TypeOf((*MockWrappedQuerier)(nil).ListTodos), ctx, arg)
+}
+
+// ListTodosFiltered mocks base method.
+func (m *MockWrappedQuerier) ListTodosFiltered(ctx context.Context, arg db.ListTodosParams, filter db.TodoFilter, sort []db.TodoSortKey, page db.TodoPageParams) ([]db.Todo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTodosFiltered", ctx, arg, filter, sort, page)
+	ret0, _ := ret[0].([]db.Todo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTodosFiltered indicates an expected call of ListTodosFiltered.
+func (mr *MockWrappedQuerierMockRecorder) ListTodosFiltered(ctx, arg, filter, sort, page any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTodosFiltered", reflect.TypeOf((*MockWrappedQuerier)(nil).ListTodosFiltered), ctx, arg, filter, sort, page)
+}
+
+// ListUsers mocks base method.
+func (m *MockWrappedQuerier) ListUsers(ctx context.Context, arg db.ListUsersParams) ([]db.ListUsersRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUsers", ctx, arg)
+	ret0, _ := ret[0].([]db.ListUsersRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUsers indicates an expected call of ListUsers.
+func (mr *MockWrappedQuerierMockRecorder) ListUsers(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUsers", reflect.TypeOf((*MockWrappedQuerier)(nil).ListUsers), ctx, arg)
+}
+
+// LockUser mocks base method.
+func (m *MockWrappedQuerier) LockUser(ctx context.Context, id int32) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockUser", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LockUser indicates an expected call of LockUser.
+func (mr *MockWrappedQuerierMockRecorder) LockUser(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockUser", reflect.TypeOf((*MockWrappedQuerier)(nil).LockUser), ctx, id)
+}
+
+// MarkEmailVerified mocks base method.
+func (m *MockWrappedQuerier) MarkEmailVerified(ctx context.Context, arg db.MarkEmailVerifiedParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkEmailVerified", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkEmailVerified indicates an expected call of MarkEmailVerified.
+func (mr *MockWrappedQuerierMockRecorder) MarkEmailVerified(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkEmailVerified", reflect.TypeOf((*MockWrappedQuerier)(nil).MarkEmailVerified), ctx, arg)
+}
+
+// MoveTodo mocks base method.
+func (m *MockWrappedQuerier) MoveTodo(ctx context.Context, arg db.MoveTodoParams) (db.Todo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MoveTodo", ctx, arg)
+	ret0, _ := ret[0].(db.Todo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MoveTodo indicates an expected call of MoveTodo.
+func (mr *MockWrappedQuerierMockRecorder) MoveTodo(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MoveTodo", reflect.TypeOf((*MockWrappedQuerier)(nil).MoveTodo), ctx, arg)
+}
+
+// PurgeDeletedUsers mocks base method.
+func (m *MockWrappedQuerier) PurgeDeletedUsers(ctx context.Context, arg db.PurgeDeletedUsersParams) ([]pgtype.UUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeDeletedUsers", ctx, arg)
+	ret0, _ := ret[0].([]pgtype.UUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeDeletedUsers indicates an expected call of PurgeDeletedUsers.
+func (mr *MockWrappedQuerierMockRecorder) PurgeDeletedUsers(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeDeletedUsers", reflect.TypeOf((*MockWrappedQuerier)(nil).PurgeDeletedUsers), ctx, arg)
+}
+
+// RequestUserDeletion mocks base method.
+func (m *MockWrappedQuerier) RequestUserDeletion(ctx context.Context, userID pgtype.UUID) (pgtype.Timestamptz, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RequestUserDeletion", ctx, userID)
+	ret0, _ := ret[0].(pgtype.Timestamptz)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RequestUserDeletion indicates an expected call of RequestUserDeletion.
+func (mr *MockWrappedQuerierMockRecorder) RequestUserDeletion(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequestUserDeletion", reflect.TypeOf((*MockWrappedQuerier)(nil).RequestUserDeletion), ctx, userID)
+}
+
+// RestoreUser mocks base method.
+func (m *MockWrappedQuerier) RestoreUser(ctx context.Context, arg db.RestoreUserParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreUser", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestoreUser indicates an expected call of RestoreUser.
+func (mr *MockWrappedQuerierMockRecorder) RestoreUser(ctx, arg any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTodos", reflect.TypeOf((*MockWrappedQuerier)(nil).ListTodos), ctx, userID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreUser", reflect.TypeOf((*MockWrappedQuerier)(nil).RestoreUser), ctx, arg)
 }
 
 // SearchTodos mocks base method.
-func (m *MockWrappedQuerier) SearchTodos(ctx context.Context, arg db.SearchTodosParams) ([]db.Todo, error) {
+func (m *MockWrappedQuerier) SearchTodos(ctx context.Context, arg db.SearchTodosParams) ([]db.SearchTodosRow, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "SearchTodos", ctx, arg)
-	ret0, _ := ret[0].([]db.Todo)
+	ret0, _ := ret[0].([]db.SearchTodosRow)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -178,6 +842,138 @@ func (mr *MockWrappedQuerierMockRecorder) SearchTodos(ctx, arg any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchTodos", reflect.TypeOf((*MockWrappedQuerier)(nil).SearchTodos), ctx, arg)
 }
 
+// SearchTodosPage mocks base method.
+func (m *MockWrappedQuerier) SearchTodosPage(ctx context.Context, arg db.SearchTodosParams, page db.TodoPageParams) ([]db.SearchTodosRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchTodosPage", ctx, arg, page)
+	ret0, _ := ret[0].([]db.SearchTodosRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchTodosPage indicates an expected call of SearchTodosPage.
+func (mr *MockWrappedQuerierMockRecorder) SearchTodosPage(ctx, arg, page any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchTodosPage", reflect.TypeOf((*MockWrappedQuerier)(nil).SearchTodosPage), ctx, arg, page)
+}
+
+// SetListArchived mocks base method.
+func (m *MockWrappedQuerier) SetListArchived(ctx context.Context, arg db.SetListArchivedParams) (db.List, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetListArchived", ctx, arg)
+	ret0, _ := ret[0].(db.List)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetListArchived indicates an expected call of SetListArchived.
+func (mr *MockWrappedQuerierMockRecorder) SetListArchived(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetListArchived", reflect.TypeOf((*MockWrappedQuerier)(nil).SetListArchived), ctx, arg)
+}
+
+// StartTOTPEnrollment mocks base method.
+func (m *MockWrappedQuerier) StartTOTPEnrollment(ctx context.Context, arg db.StartTOTPEnrollmentParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StartTOTPEnrollment", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StartTOTPEnrollment indicates an expected call of StartTOTPEnrollment.
+func (mr *MockWrappedQuerierMockRecorder) StartTOTPEnrollment(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartTOTPEnrollment", reflect.TypeOf((*MockWrappedQuerier)(nil).StartTOTPEnrollment), ctx, arg)
+}
+
+// StreamTodos mocks base method.
+func (m *MockWrappedQuerier) StreamTodos(ctx context.Context, userID int32, fn func(db.Todo) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StreamTodos", ctx, userID, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StreamTodos indicates an expected call of StreamTodos.
+func (mr *MockWrappedQuerierMockRecorder) StreamTodos(ctx, userID, fn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StreamTodos", reflect.TypeOf((*MockWrappedQuerier)(nil).StreamTodos), ctx, userID, fn)
+}
+
+// UpdateEmail mocks base method.
+func (m *MockWrappedQuerier) UpdateEmail(ctx context.Context, arg db.UpdateEmailParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateEmail", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateEmail indicates an expected call of UpdateEmail.
+func (mr *MockWrappedQuerierMockRecorder) UpdateEmail(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateEmail", reflect.TypeOf((*MockWrappedQuerier)(nil).UpdateEmail), ctx, arg)
+}
+
+// UpdateList mocks base method.
+func (m *MockWrappedQuerier) UpdateList(ctx context.Context, arg db.UpdateListParams) (db.List, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateList", ctx, arg)
+	ret0, _ := ret[0].(db.List)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateList indicates an expected call of UpdateList.
+func (mr *MockWrappedQuerierMockRecorder) UpdateList(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateList", reflect.TypeOf((*MockWrappedQuerier)(nil).UpdateList), ctx, arg)
+}
+
+// UpdatePasswordHash mocks base method.
+func (m *MockWrappedQuerier) UpdatePasswordHash(ctx context.Context, arg db.UpdatePasswordHashParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePasswordHash", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdatePasswordHash indicates an expected call of UpdatePasswordHash.
+func (mr *MockWrappedQuerierMockRecorder) UpdatePasswordHash(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePasswordHash", reflect.TypeOf((*MockWrappedQuerier)(nil).UpdatePasswordHash), ctx, arg)
+}
+
+// UpdateTOTPLastUsedStep mocks base method.
+func (m *MockWrappedQuerier) UpdateTOTPLastUsedStep(ctx context.Context, arg db.UpdateTOTPLastUsedStepParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTOTPLastUsedStep", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateTOTPLastUsedStep indicates an expected call of UpdateTOTPLastUsedStep.
+func (mr *MockWrappedQuerierMockRecorder) UpdateTOTPLastUsedStep(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTOTPLastUsedStep", reflect.TypeOf((*MockWrappedQuerier)(nil).UpdateTOTPLastUsedStep), ctx, arg)
+}
+
+// UpdateTag mocks base method.
+func (m *MockWrappedQuerier) UpdateTag(ctx context.Context, arg db.UpdateTagParams) (db.Tag, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTag", ctx, arg)
+	ret0, _ := ret[0].(db.Tag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateTag indicates an expected call of UpdateTag.
+func (mr *MockWrappedQuerierMockRecorder) UpdateTag(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTag", reflect.TypeOf((*MockWrappedQuerier)(nil).UpdateTag), ctx, arg)
+}
+
 // UpdateTodo mocks base method.
 func (m *MockWrappedQuerier) UpdateTodo(ctx context.Context, arg db.UpdateTodoParams) (db.Todo, error) {
 	m.ctrl.T.Helper()
@@ -193,6 +989,36 @@ func (mr *MockWrappedQuerierMockRecorder) UpdateTodo(ctx, arg any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTodo", reflect.TypeOf((*MockWrappedQuerier)(nil).UpdateTodo), ctx, arg)
 }
 
+// UpdateTodoItem mocks base method.
+func (m *MockWrappedQuerier) UpdateTodoItem(ctx context.Context, arg db.UpdateTodoItemParams) (db.TodoItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTodoItem", ctx, arg)
+	ret0, _ := ret[0].(db.TodoItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateTodoItem indicates an expected call of UpdateTodoItem.
+func (mr *MockWrappedQuerierMockRecorder) UpdateTodoItem(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTodoItem", reflect.TypeOf((*MockWrappedQuerier)(nil).UpdateTodoItem), ctx, arg)
+}
+
+// UpdateTodoItemPosition mocks base method.
+func (m *MockWrappedQuerier) UpdateTodoItemPosition(ctx context.Context, arg db.UpdateTodoItemPositionParams) (db.TodoItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTodoItemPosition", ctx, arg)
+	ret0, _ := ret[0].(db.TodoItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateTodoItemPosition indicates an expected call of UpdateTodoItemPosition.
+func (mr *MockWrappedQuerierMockRecorder) UpdateTodoItemPosition(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTodoItemPosition", reflect.TypeOf((*MockWrappedQuerier)(nil).UpdateTodoItemPosition), ctx, arg)
+}
+
 // UpdateTodoPosition mocks base method.
 func (m *MockWrappedQuerier) UpdateTodoPosition(ctx context.Context, arg db.UpdateTodoPositionParams) (db.Todo, error) {
 	m.ctrl.T.Helper()
@@ -208,6 +1034,21 @@ func (mr *MockWrappedQuerierMockRecorder) UpdateTodoPosition(ctx, arg any) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTodoPosition", reflect.TypeOf((*MockWrappedQuerier)(nil).UpdateTodoPosition), ctx, arg)
 }
 
+// UpdateUserAvatar mocks base method.
+func (m *MockWrappedQuerier) UpdateUserAvatar(ctx context.Context, arg db.UpdateUserAvatarParams) (db.UserPreference, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUserAvatar", ctx, arg)
+	ret0, _ := ret[0].(db.UserPreference)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateUserAvatar indicates an expected call of UpdateUserAvatar.
+func (mr *MockWrappedQuerierMockRecorder) UpdateUserAvatar(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserAvatar", reflect.TypeOf((*MockWrappedQuerier)(nil).UpdateUserAvatar), ctx, arg)
+}
+
 // UpdateUsername mocks base method.
 func (m *MockWrappedQuerier) UpdateUsername(ctx context.Context, arg db.UpdateUsernameParams) error {
 	m.ctrl.T.Helper()
@@ -222,6 +1063,51 @@ func (mr *MockWrappedQuerierMockRecorder) UpdateUsername(ctx, arg any) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUsername", reflect.TypeOf((*MockWrappedQuerier)(nil).UpdateUsername), ctx, arg)
 }
 
+// UpsertUserPreferences mocks base method.
+func (m *MockWrappedQuerier) UpsertUserPreferences(ctx context.Context, arg db.UpsertUserPreferencesParams) (db.UserPreference, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertUserPreferences", ctx, arg)
+	ret0, _ := ret[0].(db.UserPreference)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertUserPreferences indicates an expected call of UpsertUserPreferences.
+func (mr *MockWrappedQuerierMockRecorder) UpsertUserPreferences(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertUserPreferences", reflect.TypeOf((*MockWrappedQuerier)(nil).UpsertUserPreferences), ctx, arg)
+}
+
+// UsePersonalAccessToken mocks base method.
+func (m *MockWrappedQuerier) UsePersonalAccessToken(ctx context.Context, tokenHash string) (db.UsePersonalAccessTokenRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UsePersonalAccessToken", ctx, tokenHash)
+	ret0, _ := ret[0].(db.UsePersonalAccessTokenRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UsePersonalAccessToken indicates an expected call of UsePersonalAccessToken.
+func (mr *MockWrappedQuerierMockRecorder) UsePersonalAccessToken(ctx, tokenHash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UsePersonalAccessToken", reflect.TypeOf((*MockWrappedQuerier)(nil).UsePersonalAccessToken), ctx, tokenHash)
+}
+
+// UseTOTPRecoveryCode mocks base method.
+func (m *MockWrappedQuerier) UseTOTPRecoveryCode(ctx context.Context, arg db.UseTOTPRecoveryCodeParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UseTOTPRecoveryCode", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UseTOTPRecoveryCode indicates an expected call of UseTOTPRecoveryCode.
+func (mr *MockWrappedQuerierMockRecorder) UseTOTPRecoveryCode(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UseTOTPRecoveryCode", reflect.TypeOf((*MockWrappedQuerier)(nil).UseTOTPRecoveryCode), ctx, arg)
+}
+
 // WithTx mocks base method.
 func (m *MockWrappedQuerier) WithTx(tx pgx.Tx) db.WrappedQuerier {
 	m.ctrl.T.Helper()
@@ -0,0 +1,77 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	redigo "github.com/gomodule/redigo/redis"
+)
+
+/*
+Failed login attempts are counted per key (e.g. an email or a client IP) within a sliding window
+A locked key keeps the unix time until which it is locked, so that the remaining time can be told to the client
+*/
+const loginFailuresKeyPrefix = "login_failures:"
+const loginLockKeyPrefix = "login_lock:"
+
+type RedisLoginAttemptStore struct {
+	Pool *redigo.Pool
+}
+
+func NewRedisLoginAttemptStore(pool *redigo.Pool) *RedisLoginAttemptStore {
+	return &RedisLoginAttemptStore{Pool: pool}
+}
+
+// Returns the zero time if the key is not locked
+func (s *RedisLoginAttemptStore) LockedUntil(ctx context.Context, key string) (time.Time, error) {
+	conn := s.Pool.Get()
+	defer conn.Close()
+
+	until, err := redigo.Int64(conn.Do("GET", loginLockKeyPrefix+key))
+	if err != nil {
+		if err == redigo.ErrNil {
+			return time.Time{}, nil
+		}
+		return time.Time{}, err
+	}
+
+	return time.Unix(until, 0), nil
+}
+
+// Returns the number of failures within the window including this one
+func (s *RedisLoginAttemptStore) RecordFailure(ctx context.Context, key string, window time.Duration) (int, error) {
+	conn := s.Pool.Get()
+	defer conn.Close()
+
+	failuresKey := loginFailuresKeyPrefix + key
+	conn.Send("MULTI")
+	conn.Send("INCR", failuresKey)
+	conn.Send("EXPIRE", failuresKey, int64(window.Seconds()))
+	replies, err := redigo.Values(conn.Do("EXEC"))
+	if err != nil {
+		return 0, err
+	}
+
+	return redigo.Int(replies[0], nil)
+}
+
+func (s *RedisLoginAttemptStore) Lock(ctx context.Context, key string, until time.Time) error {
+	ttl := int64(time.Until(until).Seconds())
+	if ttl <= 0 {
+		return nil
+	}
+
+	conn := s.Pool.Get()
+	defer conn.Close()
+
+	_, err := conn.Do("SET", loginLockKeyPrefix+key, until.Unix(), "EX", ttl)
+	return err
+}
+
+func (s *RedisLoginAttemptStore) Reset(ctx context.Context, key string) error {
+	conn := s.Pool.Get()
+	defer conn.Close()
+
+	_, err := conn.Do("DEL", loginFailuresKeyPrefix+key, loginLockKeyPrefix+key)
+	return err
+}
@@ -0,0 +1,93 @@
+package db
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// In-process counterpart of RedisLoginAttemptStore for a single instance deployment and tests
+// Expired entries are only swept once the map grows past this size
+const memoryLoginAttemptsPruneSize = 1024
+
+type MemoryLoginAttemptStore struct {
+	mu       sync.Mutex
+	attempts map[string]*loginAttempts
+}
+
+type loginAttempts struct {
+	failures         int
+	failuresExpireAt time.Time
+	lockedUntil      time.Time
+}
+
+func NewMemoryLoginAttemptStore() *MemoryLoginAttemptStore {
+	return &MemoryLoginAttemptStore{attempts: make(map[string]*loginAttempts)}
+}
+
+func (s *MemoryLoginAttemptStore) LockedUntil(ctx context.Context, key string) (time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	a, ok := s.attempts[key]
+	if !ok || !a.lockedUntil.After(time.Now()) {
+		return time.Time{}, nil
+	}
+
+	return a.lockedUntil, nil
+}
+
+func (s *MemoryLoginAttemptStore) RecordFailure(ctx context.Context, key string, window time.Duration) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if len(s.attempts) >= memoryLoginAttemptsPruneSize {
+		s.prune(now)
+	}
+
+	a, ok := s.attempts[key]
+	if !ok {
+		a = &loginAttempts{}
+		s.attempts[key] = a
+	}
+	if !a.failuresExpireAt.After(now) {
+		a.failures = 0
+	}
+
+	a.failures++
+	a.failuresExpireAt = now.Add(window)
+
+	return a.failures, nil
+}
+
+func (s *MemoryLoginAttemptStore) Lock(ctx context.Context, key string, until time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	a, ok := s.attempts[key]
+	if !ok {
+		a = &loginAttempts{}
+		s.attempts[key] = a
+	}
+	a.lockedUntil = until
+
+	return nil
+}
+
+func (s *MemoryLoginAttemptStore) Reset(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.attempts, key)
+
+	return nil
+}
+
+func (s *MemoryLoginAttemptStore) prune(now time.Time) {
+	for key, a := range s.attempts {
+		if !a.failuresExpireAt.After(now) && !a.lockedUntil.After(now) {
+			delete(s.attempts, key)
+		}
+	}
+}
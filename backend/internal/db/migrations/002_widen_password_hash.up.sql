@@ -0,0 +1,2 @@
+-- Argon2id hashes in the PHC string format are longer than the 60 bytes of bcrypt
+ALTER TABLE users ALTER COLUMN password_hash TYPE BYTEA;
@@ -0,0 +1,11 @@
+CREATE TABLE password_reset_tokens (
+  id INTEGER GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+  user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  token_hash TEXT UNIQUE NOT NULL,  -- SHA-256 of the token sent by email; the token itself is never stored
+  expires_at TIMESTAMPTZ NOT NULL,
+  used_at TIMESTAMPTZ,  -- Set once consumed so that a token can only be used once
+  created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Index on user_id for invalidating the outstanding tokens of a user
+CREATE INDEX idx_password_reset_tokens_user_id ON password_reset_tokens(user_id);
@@ -0,0 +1,5 @@
+-- NULL until the user opens the link in the verification email
+ALTER TABLE users ADD COLUMN email_verified_at TIMESTAMPTZ;
+
+-- Accounts from before verification existed keep their access; assigning updated_at keeps it as it was
+UPDATE users SET email_verified_at = created_at, updated_at = updated_at;
@@ -0,0 +1,5 @@
+ALTER TABLE users
+  ADD COLUMN totp_secret TEXT,  -- Base32 TOTP secret; set when enrolment starts
+  ADD COLUMN totp_enabled_at TIMESTAMPTZ,  -- NULL until enrolment is confirmed with a first code
+  ADD COLUMN totp_recovery_codes TEXT[] NOT NULL DEFAULT '{}',  -- SHA-256 of the unused one-time recovery codes
+  ADD COLUMN totp_last_used_step BIGINT;  -- Time step of the last accepted code, so that a code cannot be replayed
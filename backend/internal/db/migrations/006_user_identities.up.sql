@@ -0,0 +1,12 @@
+CREATE TABLE user_identities (
+  id INTEGER GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+  user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  provider TEXT NOT NULL,  -- Name of the OIDC provider as configured in OIDC_PROVIDERS
+  subject TEXT NOT NULL,  -- "sub" claim of the ID token, which is stable per provider unlike the email
+  email TEXT NOT NULL,  -- Email reported by the provider when the identity was linked
+  created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+  UNIQUE (provider, subject)
+);
+
+-- Index on user_id for listing and deleting the identities of a user
+CREATE INDEX idx_user_identities_user_id ON user_identities(user_id);
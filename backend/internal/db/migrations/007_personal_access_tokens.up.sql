@@ -0,0 +1,14 @@
+CREATE TABLE personal_access_tokens (
+  id INTEGER GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+  user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  name VARCHAR(100) NOT NULL,
+  token_hash TEXT UNIQUE NOT NULL,  -- SHA-256 of the token shown once on creation; the token itself is never stored
+  scopes TEXT[] NOT NULL,  -- e.g. todos:read, todos:write
+  expires_at TIMESTAMPTZ NOT NULL,
+  last_used_at TIMESTAMPTZ,
+  created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+  CHECK (LENGTH(TRIM(name)) > 0)
+);
+
+-- Index on user_id for listing the tokens of a user
+CREATE INDEX idx_personal_access_tokens_user_id ON personal_access_tokens(user_id);
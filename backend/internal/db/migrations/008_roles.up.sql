@@ -0,0 +1,3 @@
+ALTER TABLE users ADD COLUMN role TEXT NOT NULL DEFAULT 'user' CHECK (role IN ('user', 'admin'));
+-- Set by an admin; disabled users cannot log in and their sessions and personal access tokens stop working
+ALTER TABLE users ADD COLUMN disabled_at TIMESTAMPTZ;
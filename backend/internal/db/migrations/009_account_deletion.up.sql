@@ -0,0 +1,4 @@
+-- Set when the user deletes the account; the account is purged once the grace period has passed unless it is restored
+ALTER TABLE users ADD COLUMN deletion_requested_at TIMESTAMPTZ;
+
+CREATE INDEX idx_users_deletion_requested_at ON users(deletion_requested_at) WHERE deletion_requested_at IS NOT NULL;
@@ -0,0 +1,13 @@
+-- One row per user, created on the first update; users without a row get the column defaults
+CREATE TABLE user_preferences (
+  user_id INTEGER PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+  display_name VARCHAR(50),  -- Shown instead of the username when set
+  time_zone TEXT NOT NULL DEFAULT 'UTC',  -- IANA time zone name
+  locale TEXT NOT NULL DEFAULT 'en',  -- BCP 47 language tag
+  default_todo_sort TEXT NOT NULL DEFAULT 'position',
+  week_start TEXT NOT NULL DEFAULT 'monday',
+  avatar_key TEXT,  -- Key of the avatar image in the blob store
+  avatar_content_type TEXT,
+  updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+  CHECK (LENGTH(TRIM(display_name)) > 0)
+);
@@ -0,0 +1,5 @@
+ALTER TABLE todos ADD COLUMN due_at TIMESTAMPTZ;
+ALTER TABLE todos ADD COLUMN remind_at TIMESTAMPTZ;
+
+-- Supports the due date range and overdue filters; todos without a due date never match them, so they are left out of the index
+CREATE INDEX idx_todos_user_id_due_at ON todos(user_id, due_at) WHERE due_at IS NOT NULL;
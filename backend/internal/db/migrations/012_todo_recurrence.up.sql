@@ -0,0 +1,2 @@
+-- iCalendar RRULE (RFC 5545) without DTSTART; the due date of the todo is the start of the recurrence
+ALTER TABLE todos ADD COLUMN recurrence TEXT;
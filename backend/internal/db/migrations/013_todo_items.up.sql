@@ -0,0 +1,85 @@
+-- Checklist items under a todo, ordered by a fractional position like the todos themselves
+CREATE TABLE todo_items (
+  id INTEGER GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+  todo_id INTEGER NOT NULL REFERENCES todos(id) ON DELETE CASCADE,
+  description TEXT NOT NULL,
+  position NUMERIC NOT NULL DEFAULT 0,
+  completed BOOLEAN NOT NULL DEFAULT FALSE,
+  created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+  CHECK (LENGTH(TRIM(description)) > 0),
+  CHECK (position >= 0)
+);
+
+CREATE INDEX idx_todo_items_todo_id_position ON todo_items(todo_id, position);
+
+CREATE TRIGGER refresh_todo_items_updated_at_step1
+  BEFORE UPDATE ON todo_items FOR EACH ROW
+  EXECUTE PROCEDURE refresh_updated_at_step1();
+CREATE TRIGGER refresh_todo_items_updated_at_step2
+  BEFORE UPDATE OF updated_at ON todo_items FOR EACH ROW
+  EXECUTE PROCEDURE refresh_updated_at_step2();
+CREATE TRIGGER refresh_todo_items_updated_at_step3
+  BEFORE UPDATE ON todo_items FOR EACH ROW
+  EXECUTE PROCEDURE refresh_updated_at_step3();
+
+-- Same as rebalance_todo_positions, within the items of one todo
+CREATE FUNCTION rebalance_todo_item_positions() RETURNS TRIGGER AS $$
+DECLARE
+    gap NUMERIC;
+    min_gap NUMERIC := 1; -- Minimum allowed gap
+BEGIN
+    -- The rebalancing below fires this trigger again for every item it moves
+    IF pg_trigger_depth() > 1 THEN
+        RETURN NEW;
+    END IF;
+
+    SELECT MIN(i2.position - i1.position) INTO gap
+    FROM todo_items i1
+    JOIN todo_items i2 ON i1.todo_id = i2.todo_id AND i1.position < i2.position
+    WHERE i1.todo_id = NEW.todo_id;
+
+    IF gap IS NOT NULL AND gap < min_gap THEN
+        UPDATE todo_items
+        SET position = ranked.rank * 100
+        FROM (
+            SELECT id, ROW_NUMBER() OVER (ORDER BY position) AS rank
+            FROM todo_items WHERE todo_id = NEW.todo_id
+        ) AS ranked
+        WHERE todo_items.id = ranked.id;
+    END IF;
+
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trigger_rebalance_todo_item_positions
+AFTER UPDATE ON todo_items
+FOR EACH ROW
+WHEN (OLD.position IS DISTINCT FROM NEW.position)
+EXECUTE FUNCTION rebalance_todo_item_positions();
+
+-- Progress of the checklist, kept on the todo so that every query returning todos has it without a join
+ALTER TABLE todos ADD COLUMN items_total INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE todos ADD COLUMN items_completed INTEGER NOT NULL DEFAULT 0;
+
+CREATE FUNCTION refresh_todo_item_counts() RETURNS TRIGGER AS $$
+DECLARE
+    target_todo_id INTEGER := COALESCE(NEW.todo_id, OLD.todo_id);
+BEGIN
+    UPDATE todos
+    SET items_total = counts.total, items_completed = counts.completed
+    FROM (
+        SELECT COUNT(*) AS total, COUNT(*) FILTER (WHERE completed) AS completed
+        FROM todo_items WHERE todo_id = target_todo_id
+    ) AS counts
+    WHERE todos.id = target_todo_id;
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trigger_refresh_todo_item_counts
+AFTER INSERT OR DELETE OR UPDATE OF completed ON todo_items
+FOR EACH ROW
+EXECUTE FUNCTION refresh_todo_item_counts();
@@ -0,0 +1,95 @@
+-- Named lists of todos. Every user has exactly one inbox, which takes the todos not filed anywhere else
+CREATE TABLE lists (
+  id INTEGER GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+  user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  name VARCHAR(100) NOT NULL,
+  is_inbox BOOLEAN NOT NULL DEFAULT FALSE,
+  archived_at TIMESTAMPTZ,
+  created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+  CHECK (LENGTH(TRIM(name)) > 0),
+  CHECK (NOT (is_inbox AND archived_at IS NOT NULL))
+);
+
+CREATE INDEX idx_lists_user_id ON lists(user_id);
+
+CREATE UNIQUE INDEX idx_lists_user_id_inbox ON lists(user_id) WHERE is_inbox;
+
+CREATE TRIGGER refresh_lists_updated_at_step1
+  BEFORE UPDATE ON lists FOR EACH ROW
+  EXECUTE PROCEDURE refresh_updated_at_step1();
+CREATE TRIGGER refresh_lists_updated_at_step2
+  BEFORE UPDATE OF updated_at ON lists FOR EACH ROW
+  EXECUTE PROCEDURE refresh_updated_at_step2();
+CREATE TRIGGER refresh_lists_updated_at_step3
+  BEFORE UPDATE ON lists FOR EACH ROW
+  EXECUTE PROCEDURE refresh_updated_at_step3();
+
+-- Users signing up by password or through a provider alike get their inbox right away
+CREATE FUNCTION create_inbox_list() RETURNS TRIGGER AS $$
+BEGIN
+    INSERT INTO lists (user_id, name, is_inbox) VALUES (NEW.id, 'Inbox', TRUE);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trigger_create_inbox_list
+AFTER INSERT ON users
+FOR EACH ROW
+EXECUTE FUNCTION create_inbox_list();
+
+INSERT INTO lists (user_id, name, is_inbox)
+SELECT id, 'Inbox', TRUE FROM users;
+
+-- Existing todos go to the inbox of their user, keeping their order
+ALTER TABLE todos ADD COLUMN list_id INTEGER REFERENCES lists(id) ON DELETE CASCADE;
+
+UPDATE todos
+SET list_id = lists.id
+FROM lists
+WHERE lists.user_id = todos.user_id AND lists.is_inbox;
+
+ALTER TABLE todos ALTER COLUMN list_id SET NOT NULL;
+
+-- Positions are now compared within a list
+CREATE INDEX idx_todos_list_id_position ON todos(list_id, position);
+
+CREATE OR REPLACE FUNCTION rebalance_todo_positions()
+RETURNS TRIGGER AS $$
+DECLARE
+    gap NUMERIC;
+    min_gap NUMERIC := 1; -- Minimum allowed gap
+BEGIN
+    -- The rebalancing below fires this trigger again for every todo it moves
+    IF pg_trigger_depth() > 1 THEN
+        RETURN NEW;
+    END IF;
+
+    -- Find the smallest gap between consecutive todos of the list
+    SELECT MIN(t2.position - t1.position) INTO gap
+    FROM todos t1
+    JOIN todos t2 ON t1.list_id = t2.list_id AND t1.position < t2.position
+    WHERE t1.list_id = NEW.list_id;
+
+    -- If the smallest gap is too small, rebalance
+    IF gap IS NOT NULL AND gap < min_gap THEN
+        UPDATE todos
+        SET position = ranked.rank * 100
+        FROM (
+            SELECT id, ROW_NUMBER() OVER (ORDER BY position) AS rank
+            FROM todos WHERE list_id = NEW.list_id
+        ) AS ranked
+        WHERE todos.id = ranked.id;
+    END IF;
+
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER trigger_rebalance_positions ON todos;
+
+CREATE TRIGGER trigger_rebalance_positions
+AFTER UPDATE ON todos
+FOR EACH ROW
+WHEN (OLD.position IS DISTINCT FROM NEW.position OR OLD.list_id IS DISTINCT FROM NEW.list_id)
+EXECUTE FUNCTION rebalance_todo_positions();
@@ -0,0 +1,75 @@
+-- Tags cut across lists; a todo can have any number of them
+CREATE TABLE tags (
+  id INTEGER GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+  user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  name VARCHAR(50) NOT NULL,
+  color CHAR(7) NOT NULL DEFAULT '#808080',  -- #rrggbb
+  created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+  CHECK (LENGTH(TRIM(name)) > 0),
+  CHECK (color ~ '^#[0-9a-f]{6}$')
+);
+
+-- Tags are filtered by name, so the names of a user are unique
+CREATE UNIQUE INDEX idx_tags_user_id_name ON tags(user_id, name);
+
+CREATE TRIGGER refresh_tags_updated_at_step1
+  BEFORE UPDATE ON tags FOR EACH ROW
+  EXECUTE PROCEDURE refresh_updated_at_step1();
+CREATE TRIGGER refresh_tags_updated_at_step2
+  BEFORE UPDATE OF updated_at ON tags FOR EACH ROW
+  EXECUTE PROCEDURE refresh_updated_at_step2();
+CREATE TRIGGER refresh_tags_updated_at_step3
+  BEFORE UPDATE ON tags FOR EACH ROW
+  EXECUTE PROCEDURE refresh_updated_at_step3();
+
+CREATE TABLE todo_tags (
+  todo_id INTEGER NOT NULL REFERENCES todos(id) ON DELETE CASCADE,
+  tag_id INTEGER NOT NULL REFERENCES tags(id) ON DELETE CASCADE,
+  PRIMARY KEY (todo_id, tag_id)
+);
+
+CREATE INDEX idx_todo_tags_tag_id ON todo_tags(tag_id);
+
+-- The tags of a todo as [{"id", "name", "color"}] ordered by name, kept on the todo like the checklist progress,
+-- so that every query returning todos has them without a join
+ALTER TABLE todos ADD COLUMN tags JSONB NOT NULL DEFAULT '[]';
+
+CREATE FUNCTION refresh_todo_tags(target_todo_id INTEGER) RETURNS VOID AS $$
+BEGIN
+    UPDATE todos
+    SET tags = COALESCE((
+        SELECT jsonb_agg(jsonb_build_object('id', tags.id, 'name', tags.name, 'color', tags.color) ORDER BY tags.name)
+        FROM todo_tags
+        JOIN tags ON tags.id = todo_tags.tag_id
+        WHERE todo_tags.todo_id = target_todo_id
+    ), '[]')
+    WHERE id = target_todo_id;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE FUNCTION refresh_todo_tags_on_link() RETURNS TRIGGER AS $$
+BEGIN
+    PERFORM refresh_todo_tags(COALESCE(NEW.todo_id, OLD.todo_id));
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Deleting a tag deletes its links, which refreshes the todos through this trigger as well
+CREATE TRIGGER trigger_refresh_todo_tags_on_link
+AFTER INSERT OR DELETE ON todo_tags
+FOR EACH ROW
+EXECUTE FUNCTION refresh_todo_tags_on_link();
+
+CREATE FUNCTION refresh_todo_tags_on_tag() RETURNS TRIGGER AS $$
+BEGIN
+    PERFORM refresh_todo_tags(todo_tags.todo_id)
+    FROM todo_tags WHERE todo_tags.tag_id = NEW.id;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trigger_refresh_todo_tags_on_tag
+AFTER UPDATE OF name, color ON tags
+FOR EACH ROW
+EXECUTE FUNCTION refresh_todo_tags_on_tag();
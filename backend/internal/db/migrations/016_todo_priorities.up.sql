@@ -0,0 +1,2 @@
+-- 0 none, 1 low, 2 medium, 3 high, 4 urgent; stored as a rank so that sorting by it follows the importance
+ALTER TABLE todos ADD COLUMN priority SMALLINT NOT NULL DEFAULT 0 CHECK (priority BETWEEN 0 AND 4);
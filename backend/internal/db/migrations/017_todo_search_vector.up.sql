@@ -0,0 +1,11 @@
+-- Postgres keeps the generated column in sync with the description, so the index no longer has to match the exact expression searched
+ALTER TABLE todos ADD COLUMN search_vector TSVECTOR GENERATED ALWAYS AS (to_tsvector('english', description)) STORED;
+
+DROP INDEX idx_todos_description_search;
+CREATE INDEX idx_todos_search_vector ON todos USING GIN (search_vector);
+
+-- Search headlines mark the matched words with \x02 and \x03, so no description may hold control characters other than
+-- line breaks and tabs. Existing ones are dropped; assigning updated_at keeps it as it was.
+UPDATE todos SET description = regexp_replace(description, '[\x01-\x08\x0B\x0C\x0E-\x1F\x7F-\x9F]', '', 'g'), updated_at = updated_at
+WHERE description ~ '[\x01-\x08\x0B\x0C\x0E-\x1F\x7F-\x9F]';
+ALTER TABLE todos ADD CONSTRAINT todos_description_no_control_chars CHECK (description !~ '[\x01-\x08\x0B\x0C\x0E-\x1F\x7F-\x9F]');
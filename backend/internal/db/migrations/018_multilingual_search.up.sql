@@ -0,0 +1,22 @@
+-- Trigrams find what text search misses: typos, and words within text the parser cannot split, such as Japanese
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- Postgres ships no Japanese parser, so this starts out as a copy of simple, which keeps runs of Japanese text whole
+-- and leaves matching within them to the trigrams. A morphological parser (e.g. from textsearch_ja) can replace it later.
+CREATE TEXT SEARCH CONFIGURATION japanese (COPY = simple);
+
+-- The language a todo is written in, which picks the text search configuration of its search vector
+ALTER TABLE todos ADD COLUMN language TEXT NOT NULL DEFAULT 'english' CHECK (language IN ('english', 'japanese'));
+
+-- Existing todos with kana or kanji; assigning updated_at keeps it as it was
+UPDATE todos SET language = 'japanese', updated_at = updated_at WHERE description ~ '[ぁ-ゖァ-ヺ一-鿿]';
+
+-- Generated columns cannot change their expression, so the search vector is added again (and its index with it)
+ALTER TABLE todos DROP COLUMN search_vector;
+ALTER TABLE todos ADD COLUMN search_vector TSVECTOR GENERATED ALWAYS AS (
+  to_tsvector(CASE language WHEN 'japanese' THEN 'japanese'::REGCONFIG ELSE 'english'::REGCONFIG END, description)
+) STORED;
+CREATE INDEX idx_todos_search_vector ON todos USING GIN (search_vector);
+
+-- Serves both the similarity operators and ILIKE
+CREATE INDEX idx_todos_description_trgm ON todos USING GIN (description gin_trgm_ops);
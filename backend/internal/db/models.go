@@ -8,22 +8,115 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+type List struct {
+	ID         int32
+	UserID     int32
+	Name       string
+	IsInbox    bool
+	ArchivedAt pgtype.Timestamptz
+	CreatedAt  pgtype.Timestamptz
+	UpdatedAt  pgtype.Timestamptz
+}
+
+type PasswordResetToken struct {
+	ID        int32
+	UserID    int32
+	TokenHash string
+	ExpiresAt pgtype.Timestamptz
+	UsedAt    pgtype.Timestamptz
+	CreatedAt pgtype.Timestamptz
+}
+
+type PersonalAccessToken struct {
+	ID         int32
+	UserID     int32
+	Name       string
+	TokenHash  string
+	Scopes     []string
+	ExpiresAt  pgtype.Timestamptz
+	LastUsedAt pgtype.Timestamptz
+	CreatedAt  pgtype.Timestamptz
+}
+
+type Tag struct {
+	ID        int32
+	UserID    int32
+	Name      string
+	Color     string
+	CreatedAt pgtype.Timestamptz
+	UpdatedAt pgtype.Timestamptz
+}
+
 type Todo struct {
+	ID             int32
+	UserID         int32
+	Description    string
+	Position       pgtype.Numeric
+	Completed      pgtype.Bool
+	CreatedAt      pgtype.Timestamptz
+	UpdatedAt      pgtype.Timestamptz
+	DueAt          pgtype.Timestamptz
+	RemindAt       pgtype.Timestamptz
+	Recurrence     pgtype.Text
+	ItemsTotal     int32
+	ItemsCompleted int32
+	ListID         int32
+	Tags           []byte
+	Priority       int16
+	Language       string
+	SearchVector   interface{}
+}
+
+type TodoItem struct {
 	ID          int32
-	UserID      int32
+	TodoID      int32
 	Description string
 	Position    pgtype.Numeric
-	Completed   pgtype.Bool
+	Completed   bool
 	CreatedAt   pgtype.Timestamptz
 	UpdatedAt   pgtype.Timestamptz
 }
 
+type TodoTag struct {
+	TodoID int32
+	TagID  int32
+}
+
 type User struct {
-	ID           int32
-	UserID       pgtype.UUID
-	Username     string
-	Email        string
-	PasswordHash []byte
-	CreatedAt    pgtype.Timestamptz
-	UpdatedAt    pgtype.Timestamptz
+	ID                  int32
+	UserID              pgtype.UUID
+	Username            string
+	Email               string
+	PasswordHash        []byte
+	CreatedAt           pgtype.Timestamptz
+	UpdatedAt           pgtype.Timestamptz
+	EmailVerifiedAt     pgtype.Timestamptz
+	TotpSecret          pgtype.Text
+	TotpEnabledAt       pgtype.Timestamptz
+	TotpRecoveryCodes   []string
+	TotpLastUsedStep    pgtype.Int8
+	Role                string
+	DisabledAt          pgtype.Timestamptz
+	DeletionRequestedAt pgtype.Timestamptz
+}
+
+type UserIdentity struct {
+	ID        int32
+	UserID    int32
+	Provider  string
+	Subject   string
+	Email     string
+	CreatedAt pgtype.Timestamptz
+}
+
+type UserPreference struct {
+	UserID            int32
+	DisplayName       pgtype.Text
+	TimeZone          string
+	Locale            string
+	DefaultTodoSort   string
+	WeekStart         string
+	AvatarKey         pgtype.Text
+	AvatarContentType pgtype.Text
+	UpdatedAt         pgtype.Timestamptz
 }
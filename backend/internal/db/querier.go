@@ -11,18 +11,105 @@ import (
 )
 
 type Querier interface {
+	// Links nothing unless both the todo and the tag are the user's; attaching a tag twice is a no-op
+	AttachTodoTag(ctx context.Context, arg AttachTodoTagParams) error
+	ClearUserAvatar(ctx context.Context, userID int32) (int64, error)
+	ConsumePasswordResetToken(ctx context.Context, tokenHash string) (PasswordResetToken, error)
+	CompleteTodoItems(ctx context.Context, todoID int32) error
+	// Copies the checklist of a todo to another one, with every item not completed yet
+	CopyTodoItems(ctx context.Context, arg CopyTodoItemsParams) error
+	// Attaches the tags of a todo to another one
+	CopyTodoTags(ctx context.Context, arg CopyTodoTagsParams) error
+	CountTodos(ctx context.Context, userID int32) (CountTodosRow, error)
+	CountUsers(ctx context.Context, pattern string) (int64, error)
+	CreateList(ctx context.Context, arg CreateListParams) (List, error)
+	CreatePasswordResetToken(ctx context.Context, arg CreatePasswordResetTokenParams) (PasswordResetToken, error)
+	// Users created through an OIDC provider have no password until they set one via the password reset flow
+	CreateOIDCUser(ctx context.Context, email string) (User, error)
+	CreatePersonalAccessToken(ctx context.Context, arg CreatePersonalAccessTokenParams) (PersonalAccessToken, error)
+	CreateTag(ctx context.Context, arg CreateTagParams) (Tag, error)
 	CreateTodo(ctx context.Context, arg CreateTodoParams) (Todo, error)
+	CreateTodoItem(ctx context.Context, arg CreateTodoItemParams) (TodoItem, error)
 	CreateUser(ctx context.Context, arg CreateUserParams) (User, error)
+	CreateUserIdentity(ctx context.Context, arg CreateUserIdentityParams) (UserIdentity, error)
+	// Deletes the todos of the list as well; the inbox cannot be deleted
+	DeleteList(ctx context.Context, arg DeleteListParams) (int64, error)
+	DeletePersonalAccessToken(ctx context.Context, arg DeletePersonalAccessTokenParams) (int64, error)
+	// Detaches the tag from every todo as well
+	DeleteTag(ctx context.Context, arg DeleteTagParams) (int64, error)
 	DeleteTodo(ctx context.Context, arg DeleteTodoParams) (Todo, error)
-	DeleteUser(ctx context.Context, userID pgtype.UUID) (User, error)
+	DeleteTodoItem(ctx context.Context, arg DeleteTodoItemParams) (int64, error)
+	DetachTodoTag(ctx context.Context, arg DetachTodoTagParams) error
+	DisableTOTP(ctx context.Context, userID pgtype.UUID) error
+	DisableUser(ctx context.Context, userID pgtype.UUID) (int64, error)
+	EnableTOTP(ctx context.Context, arg EnableTOTPParams) error
+	EnableUser(ctx context.Context, userID pgtype.UUID) (int64, error)
+	GetInboxList(ctx context.Context, userID int32) (List, error)
+	GetList(ctx context.Context, arg GetListParams) (List, error)
+	GetTag(ctx context.Context, arg GetTagParams) (Tag, error)
+	GetTodo(ctx context.Context, arg GetTodoParams) (Todo, error)
+	// Locks the todo until the end of the transaction, so that concurrent updates see each other's changes
+	GetTodoForUpdate(ctx context.Context, arg GetTodoForUpdateParams) (Todo, error)
+	GetUserPreferences(ctx context.Context, userID int32) (UserPreference, error)
 	GetUserByEmail(ctx context.Context, email string) (User, error)
 	GetUserByID(ctx context.Context, id int32) (User, error)
 	GetUserByUserID(ctx context.Context, userID pgtype.UUID) (User, error)
-	ListTodos(ctx context.Context, userID int32) ([]Todo, error)
-	SearchTodos(ctx context.Context, arg SearchTodosParams) ([]Todo, error)
+	GetUserIdentity(ctx context.Context, arg GetUserIdentityParams) (UserIdentity, error)
+	InvalidatePasswordResetTokens(ctx context.Context, userID int32) error
+	// The inbox comes first, then the other lists in the order they were created
+	ListLists(ctx context.Context, arg ListListsParams) ([]List, error)
+	ListPersonalAccessTokens(ctx context.Context, userID int32) ([]PersonalAccessToken, error)
+	ListTags(ctx context.Context, userID int32) ([]Tag, error)
+	ListTodoItems(ctx context.Context, todoID int32) ([]TodoItem, error)
+	// Every checklist item of the user, grouped by todo in list order
+	ListTodoItemsByUser(ctx context.Context, userID int32) ([]TodoItem, error)
+	// Without a list, the todos of every list not archived, list by list.
+	// The due date and tag filters are skipped when null; overdue means due in the past and not completed yet.
+	// Todos match either all of the tags or any of them
+	ListTodos(ctx context.Context, arg ListTodosParams) ([]Todo, error)
+	// Users whose email or username matches the pattern, along with their todo counts
+	ListUsers(ctx context.Context, arg ListUsersParams) ([]ListUsersRow, error)
+	// Makes the other transactions locking the user wait until this one ends
+	LockUser(ctx context.Context, id int32) error
+	MarkEmailVerified(ctx context.Context, arg MarkEmailVerifiedParams) error
+	// Moves the todo into another list of the user, between the given positions there.
+	// Without the next position it goes after the previous one, and without either at the end of the list
+	MoveTodo(ctx context.Context, arg MoveTodoParams) (Todo, error)
+	// Deletes a batch of accounts whose deletion was requested before the cutoff; their data goes along by ON DELETE CASCADE.
+	// Returns the user_id of each purged account so that the data kept outside the database can be removed too
+	PurgeDeletedUsers(ctx context.Context, arg PurgeDeletedUsersParams) ([]pgtype.UUID, error)
+	// Keeps the original request time when the account is deleted again, so that the grace period is not extended
+	RequestUserDeletion(ctx context.Context, userID pgtype.UUID) (pgtype.Timestamptz, error)
+	// Only accounts whose deletion was requested after the cutoff (i.e. still within the grace period) can be restored
+	RestoreUser(ctx context.Context, arg RestoreUserParams) (int64, error)
+	// The keyword follows the web search syntax of websearch_to_tsquery, which takes any input, and is matched in the language
+	// of each todo. For type-ahead, its last word also matches as a prefix: prefix is that word when it is being typed,
+	// and prefix_of the keyword before it.
+	// Trigrams find the todos text search misses: those containing fuzzy (the plain words of the keyword, which pattern
+	// is an ILIKE pattern for) or a word similar to it. Text search matches rank first, then the todos containing fuzzy,
+	// then those similar to it, each by their score.
+	// Headlines mark the matched words, or else fuzzy where it appears as given, with \x02 and \x03.
+	// The tag filter works the same as in ListTodos
+	SearchTodos(ctx context.Context, arg SearchTodosParams) ([]SearchTodosRow, error)
+	// Archiving an archived list keeps the original time; the inbox cannot be archived
+	SetListArchived(ctx context.Context, arg SetListArchivedParams) (List, error)
+	StartTOTPEnrollment(ctx context.Context, arg StartTOTPEnrollmentParams) error
+	UpdateList(ctx context.Context, arg UpdateListParams) (List, error)
+	UpdateTOTPLastUsedStep(ctx context.Context, arg UpdateTOTPLastUsedStepParams) (int64, error)
+	UpdateTag(ctx context.Context, arg UpdateTagParams) (Tag, error)
 	UpdateTodo(ctx context.Context, arg UpdateTodoParams) (Todo, error)
+	UpdateTodoItem(ctx context.Context, arg UpdateTodoItemParams) (TodoItem, error)
+	UpdateTodoItemPosition(ctx context.Context, arg UpdateTodoItemPositionParams) (TodoItem, error)
 	UpdateTodoPosition(ctx context.Context, arg UpdateTodoPositionParams) (Todo, error)
+	// Swaps the email only if it is still the one the change was requested from. The new email counts as verified, as the change was confirmed through it.
+	UpdateEmail(ctx context.Context, arg UpdateEmailParams) (int64, error)
+	UpdatePasswordHash(ctx context.Context, arg UpdatePasswordHashParams) error
+	UpdateUserAvatar(ctx context.Context, arg UpdateUserAvatarParams) (UserPreference, error)
 	UpdateUsername(ctx context.Context, arg UpdateUsernameParams) error
+	UpsertUserPreferences(ctx context.Context, arg UpsertUserPreferencesParams) (UserPreference, error)
+	// Looks up an unexpired token by its hash and records its use in the same statement
+	UsePersonalAccessToken(ctx context.Context, tokenHash string) (UsePersonalAccessTokenRow, error)
+	UseTOTPRecoveryCode(ctx context.Context, arg UseTOTPRecoveryCodeParams) (int64, error)
 }
 
 var _ Querier = (*Queries)(nil)
@@ -0,0 +1,36 @@
+-- name: CreateList :one
+INSERT INTO lists (user_id, name)
+VALUES ($1, $2)
+RETURNING *;
+
+-- name: GetList :one
+SELECT * FROM lists WHERE id = $1 AND user_id = $2;
+
+-- name: GetInboxList :one
+SELECT * FROM lists WHERE user_id = $1 AND is_inbox;
+
+-- name: ListLists :many
+-- The inbox comes first, then the other lists in the order they were created
+SELECT * FROM lists
+WHERE user_id = $1
+  AND (sqlc.arg(include_archived)::BOOLEAN OR archived_at IS NULL)
+ORDER BY is_inbox DESC, id;
+
+-- name: UpdateList :one
+UPDATE lists
+SET name = $3,
+    updated_at = NOW()
+WHERE id = $1 AND user_id = $2
+RETURNING *;
+
+-- name: SetListArchived :one
+-- Archiving an archived list keeps the original time; the inbox cannot be archived
+UPDATE lists
+SET archived_at = CASE WHEN sqlc.arg(archived)::BOOLEAN THEN COALESCE(archived_at, NOW()) END,
+    updated_at = NOW()
+WHERE id = $1 AND user_id = $2 AND NOT is_inbox
+RETURNING *;
+
+-- name: DeleteList :execrows
+-- Deletes the todos of the list as well; the inbox cannot be deleted
+DELETE FROM lists WHERE id = $1 AND user_id = $2 AND NOT is_inbox;
\ No newline at end of file
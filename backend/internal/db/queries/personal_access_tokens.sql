@@ -0,0 +1,20 @@
+-- name: CreatePersonalAccessToken :one
+INSERT INTO personal_access_tokens (user_id, name, token_hash, scopes, expires_at) VALUES ($1, $2, $3, $4, $5) RETURNING *;
+
+-- name: ListPersonalAccessTokens :many
+SELECT * FROM personal_access_tokens WHERE user_id = $1 ORDER BY created_at DESC, id DESC;
+
+-- name: DeletePersonalAccessToken :execrows
+DELETE FROM personal_access_tokens WHERE id = $1 AND user_id = $2;
+
+-- name: UsePersonalAccessToken :one
+-- Looks up an unexpired token by its hash and records its use in the same statement
+UPDATE personal_access_tokens
+SET last_used_at = CURRENT_TIMESTAMP
+FROM users
+WHERE personal_access_tokens.token_hash = $1
+  AND personal_access_tokens.expires_at > CURRENT_TIMESTAMP
+  AND users.id = personal_access_tokens.user_id
+  AND users.disabled_at IS NULL
+  AND users.deletion_requested_at IS NULL
+RETURNING users.user_id, personal_access_tokens.scopes;
\ No newline at end of file
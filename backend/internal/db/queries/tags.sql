@@ -0,0 +1,47 @@
+-- name: CreateTag :one
+INSERT INTO tags (user_id, name, color)
+VALUES ($1, $2, $3)
+RETURNING *;
+
+-- name: GetTag :one
+SELECT * FROM tags WHERE id = $1 AND user_id = $2;
+
+-- name: ListTags :many
+SELECT * FROM tags
+WHERE user_id = $1
+ORDER BY name;
+
+-- name: UpdateTag :one
+UPDATE tags
+SET name = $3,
+    color = $4,
+    updated_at = NOW()
+WHERE id = $1 AND user_id = $2
+RETURNING *;
+
+-- name: DeleteTag :execrows
+-- Detaches the tag from every todo as well
+DELETE FROM tags WHERE id = $1 AND user_id = $2;
+
+-- name: AttachTodoTag :exec
+-- Links nothing unless both the todo and the tag are the user's; attaching a tag twice is a no-op
+INSERT INTO todo_tags (todo_id, tag_id)
+SELECT todos.id, tags.id
+FROM todos
+JOIN tags ON tags.user_id = todos.user_id
+WHERE todos.id = sqlc.arg(todo_id) AND tags.id = sqlc.arg(tag_id) AND todos.user_id = sqlc.arg(user_id)
+ON CONFLICT DO NOTHING;
+
+-- name: DetachTodoTag :exec
+DELETE FROM todo_tags
+USING todos
+WHERE todos.id = todo_tags.todo_id
+  AND todo_tags.todo_id = sqlc.arg(todo_id) AND todo_tags.tag_id = sqlc.arg(tag_id) AND todos.user_id = sqlc.arg(user_id);
+
+-- name: CopyTodoTags :exec
+-- Attaches the tags of a todo to another one
+INSERT INTO todo_tags (todo_id, tag_id)
+SELECT sqlc.arg(to_todo_id), tag_id
+FROM todo_tags
+WHERE todo_id = sqlc.arg(from_todo_id)
+ON CONFLICT DO NOTHING;
\ No newline at end of file
@@ -0,0 +1,49 @@
+-- name: CreateTodoItem :one
+INSERT INTO todo_items (todo_id, description, position)
+VALUES ($1, $2,
+    COALESCE((SELECT MAX(position) FROM todo_items WHERE todo_id = $1) + 100, 100)  -- default gap of 100
+)
+RETURNING *;
+
+-- name: ListTodoItems :many
+SELECT * FROM todo_items
+WHERE todo_id = $1
+ORDER BY position;
+
+-- name: ListTodoItemsByUser :many
+-- Every checklist item of the user, grouped by todo in list order
+SELECT todo_items.* FROM todo_items
+JOIN todos ON todos.id = todo_items.todo_id
+WHERE todos.user_id = $1
+ORDER BY todos.list_id, todos.position, todos.id, todo_items.position;
+
+-- name: UpdateTodoItem :one
+UPDATE todo_items
+SET description = $3,
+    completed = $4,
+    updated_at = NOW()
+WHERE id = $1 AND todo_id = $2
+RETURNING *;
+
+-- name: UpdateTodoItemPosition :one
+UPDATE todo_items
+SET position = (sqlc.arg(prevPos)::NUMERIC + sqlc.arg(nextPos)::NUMERIC) / 2,
+    updated_at = NOW()
+WHERE todo_items.id = $1 AND todo_items.todo_id = $2
+RETURNING *;
+
+-- name: CompleteTodoItems :exec
+UPDATE todo_items
+SET completed = TRUE,
+    updated_at = NOW()
+WHERE todo_id = $1 AND NOT completed;
+
+-- name: CopyTodoItems :exec
+-- Copies the checklist of a todo to another one, with every item not completed yet
+INSERT INTO todo_items (todo_id, description, position)
+SELECT sqlc.arg(to_todo_id), description, position
+FROM todo_items
+WHERE todo_id = sqlc.arg(from_todo_id);
+
+-- name: DeleteTodoItem :execrows
+DELETE FROM todo_items WHERE id = $1 AND todo_id = $2;
\ No newline at end of file
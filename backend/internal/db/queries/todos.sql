@@ -1,25 +1,92 @@
 -- name: CreateTodo :one
-INSERT INTO todos (user_id, description, position)
-VALUES ($1, $2, 
-    COALESCE((SELECT MAX(position) FROM todos WHERE user_id = $1) + 100, 100)  -- default gap of 100
+INSERT INTO todos (user_id, list_id, description, position, due_at, remind_at, recurrence, priority, language)
+VALUES ($1, $2, $3, 
+    COALESCE((SELECT MAX(position) FROM todos WHERE list_id = $2) + 100, 100),  -- default gap of 100
+    $4, $5, $6, $7, $8
 )
 RETURNING *;
 
+-- name: GetTodo :one
+SELECT * FROM todos WHERE id = $1 AND user_id = $2;
+
+-- name: GetTodoForUpdate :one
+-- Locks the todo until the end of the transaction, so that concurrent updates see each other's changes
+SELECT * FROM todos WHERE id = $1 AND user_id = $2
+FOR UPDATE;
+
 -- name: ListTodos :many
-SELECT * FROM todos WHERE user_id = $1 ORDER BY position;
+-- Without a list, the todos of every list not archived, list by list.
+-- The due date and tag filters are skipped when null; overdue means due in the past and not completed yet.
+-- Todos match either all of the tags or any of them
+SELECT * FROM todos
+WHERE user_id = $1
+  AND (CASE WHEN sqlc.narg(list_id)::INTEGER IS NULL
+         THEN list_id IN (SELECT id FROM lists WHERE lists.user_id = $1 AND archived_at IS NULL)
+         ELSE list_id = sqlc.narg(list_id) END)
+  AND (sqlc.narg(due_before)::TIMESTAMPTZ IS NULL OR due_at < sqlc.narg(due_before))
+  AND (sqlc.narg(due_after)::TIMESTAMPTZ IS NULL OR due_at >= sqlc.narg(due_after))
+  AND (NOT sqlc.arg(overdue)::BOOLEAN OR (due_at < CURRENT_TIMESTAMP AND NOT completed))
+  AND (sqlc.narg(tags)::TEXT[] IS NULL OR (
+    SELECT COUNT(*) FROM todo_tags
+    JOIN tags ON tags.id = todo_tags.tag_id
+    WHERE todo_tags.todo_id = todos.id AND tags.name = ANY(sqlc.narg(tags)::TEXT[])
+  ) >= CASE WHEN sqlc.arg(match_all_tags)::BOOLEAN THEN cardinality(sqlc.narg(tags)::TEXT[]) ELSE 1 END)
+ORDER BY list_id, position;
+
+-- name: CountTodos :one
+SELECT COUNT(*) AS total, COUNT(*) FILTER (WHERE completed) AS completed
+FROM todos WHERE user_id = $1;
 
 -- name: SearchTodos :many
-SELECT *
+-- The keyword follows the web search syntax of websearch_to_tsquery, which takes any input, and is matched in the language
+-- of each todo. For type-ahead, its last word also matches as a prefix: prefix is that word when it is being typed,
+-- and prefix_of the keyword before it.
+-- Trigrams find the todos text search misses: those containing fuzzy (the plain words of the keyword, which pattern
+-- is an ILIKE pattern for) or a word similar to it. Text search matches rank first, then the todos containing fuzzy,
+-- then those similar to it, each by their score.
+-- Headlines mark the matched words, or else fuzzy where it appears as given, with \x02 and \x03.
+-- The tag filter works the same as in ListTodos
+WITH search AS (
+  SELECT websearch_to_tsquery('english', sqlc.arg(keyword)::TEXT) || CASE WHEN sqlc.arg(prefix)::TEXT = '' THEN ''::TSQUERY
+      ELSE websearch_to_tsquery('english', sqlc.arg(prefix_of)::TEXT) && to_tsquery('english', sqlc.arg(prefix)::TEXT || ':*') END AS english,
+    websearch_to_tsquery('japanese', sqlc.arg(keyword)::TEXT) || CASE WHEN sqlc.arg(prefix)::TEXT = '' THEN ''::TSQUERY
+      ELSE websearch_to_tsquery('japanese', sqlc.arg(prefix_of)::TEXT) && to_tsquery('japanese', sqlc.arg(prefix)::TEXT || ':*') END AS japanese
+)
+SELECT sqlc.embed(todos),
+  matched.rank,
+  CASE WHEN search_vector @@ matched.query
+    THEN ts_headline(language::REGCONFIG, description, matched.query, 'StartSel=' || chr(2) || ', StopSel=' || chr(3))
+    ELSE replace(description, sqlc.arg(fuzzy)::TEXT, chr(2) || sqlc.arg(fuzzy)::TEXT || chr(3)) END AS headline
 FROM todos
+CROSS JOIN search
+CROSS JOIN LATERAL (
+  SELECT query, (CASE WHEN todos.search_vector @@ query THEN 2 + ts_rank(todos.search_vector, query, 32)
+      WHEN todos.description ILIKE sqlc.arg(pattern)::TEXT THEN 1 + word_similarity(sqlc.arg(fuzzy)::TEXT, todos.description)
+      ELSE word_similarity(sqlc.arg(fuzzy)::TEXT, todos.description) END)::REAL AS rank
+  FROM (SELECT CASE todos.language WHEN 'japanese' THEN search.japanese ELSE search.english END AS query) AS todo_search
+) AS matched
 WHERE user_id = $1
-  AND description @@ to_tsquery('english', $2)
-ORDER BY position;
+  AND ((language = 'english' AND search_vector @@ search.english)
+    OR (language = 'japanese' AND search_vector @@ search.japanese)
+    OR sqlc.arg(fuzzy)::TEXT <% description
+    OR description ILIKE sqlc.arg(pattern)::TEXT)
+  AND (sqlc.narg(tags)::TEXT[] IS NULL OR (
+    SELECT COUNT(*) FROM todo_tags
+    JOIN tags ON tags.id = todo_tags.tag_id
+    WHERE todo_tags.todo_id = todos.id AND tags.name = ANY(sqlc.narg(tags)::TEXT[])
+  ) >= CASE WHEN sqlc.arg(match_all_tags)::BOOLEAN THEN cardinality(sqlc.narg(tags)::TEXT[]) ELSE 1 END)
+ORDER BY rank DESC, id;
 
 -- name: UpdateTodo :one
 UPDATE todos
 SET description = $2, 
     completed = $3, 
     position = $4,
+    due_at = $6,
+    remind_at = $7,
+    recurrence = $8,
+    priority = $9,
+    language = $10,
     updated_at = NOW()
 WHERE id = $1 AND user_id = $5
 RETURNING *;
@@ -31,6 +98,23 @@ SET position = (sqlc.arg(prevPos)::NUMERIC + sqlc.arg(nextPos)::NUMERIC) / 2,
 WHERE todos.id = $1 AND todos.user_id = $2
 RETURNING *;
 
+-- name: MoveTodo :one
+-- Moves the todo into another list of the user, between the given positions there.
+-- Without the next position it goes after the previous one, and without either at the end of the list
+UPDATE todos
+SET list_id = sqlc.arg(list_id),
+    position = CASE
+        WHEN sqlc.narg(prevPos)::NUMERIC IS NULL AND sqlc.narg(nextPos)::NUMERIC IS NULL
+            THEN COALESCE((SELECT MAX(t.position) FROM todos t WHERE t.list_id = sqlc.arg(list_id) AND t.id <> $1) + 100, 100)
+        WHEN sqlc.narg(nextPos)::NUMERIC IS NULL THEN sqlc.narg(prevPos)::NUMERIC + 100
+        WHEN sqlc.narg(prevPos)::NUMERIC IS NULL THEN sqlc.narg(nextPos)::NUMERIC / 2
+        ELSE (sqlc.narg(prevPos)::NUMERIC + sqlc.narg(nextPos)::NUMERIC) / 2
+    END,
+    updated_at = NOW()
+WHERE todos.id = $1 AND todos.user_id = $2
+  AND EXISTS (SELECT 1 FROM lists WHERE lists.id = sqlc.arg(list_id) AND lists.user_id = $2)
+RETURNING *;
+
 -- name: DeleteTodo :one
 DELETE FROM todos WHERE id = $1 AND user_id = $2
 RETURNING *;
\ No newline at end of file
@@ -0,0 +1,28 @@
+-- name: GetUserPreferences :one
+SELECT * FROM user_preferences WHERE user_id = $1;
+
+-- name: UpsertUserPreferences :one
+INSERT INTO user_preferences (user_id, display_name, time_zone, locale, default_todo_sort, week_start)
+VALUES ($1, $2, $3, $4, $5, $6)
+ON CONFLICT (user_id) DO UPDATE
+SET display_name = EXCLUDED.display_name,
+    time_zone = EXCLUDED.time_zone,
+    locale = EXCLUDED.locale,
+    default_todo_sort = EXCLUDED.default_todo_sort,
+    week_start = EXCLUDED.week_start,
+    updated_at = CURRENT_TIMESTAMP
+RETURNING *;
+
+-- name: UpdateUserAvatar :one
+INSERT INTO user_preferences (user_id, avatar_key, avatar_content_type)
+VALUES ($1, $2, $3)
+ON CONFLICT (user_id) DO UPDATE
+SET avatar_key = EXCLUDED.avatar_key,
+    avatar_content_type = EXCLUDED.avatar_content_type,
+    updated_at = CURRENT_TIMESTAMP
+RETURNING *;
+
+-- name: ClearUserAvatar :execrows
+UPDATE user_preferences
+SET avatar_key = NULL, avatar_content_type = NULL, updated_at = CURRENT_TIMESTAMP
+WHERE user_id = $1 AND avatar_key IS NOT NULL;
\ No newline at end of file
@@ -15,6 +15,103 @@ UPDATE users
 SET username = $1, updated_at = CURRENT_TIMESTAMP
 WHERE user_id = $2;
 
--- name: DeleteUser :one
-DELETE FROM users WHERE user_id = $1
-RETURNING *;
\ No newline at end of file
+-- name: UpdateEmail :execrows
+-- Swaps the email only if it is still the one the change was requested from. The new email counts as verified, as the change was confirmed through it.
+UPDATE users
+SET email = sqlc.arg(new_email), email_verified_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+WHERE user_id = sqlc.arg(user_id) AND email = sqlc.arg(email);
+
+-- name: UpdatePasswordHash :exec
+UPDATE users
+SET password_hash = $1, updated_at = CURRENT_TIMESTAMP
+WHERE user_id = $2;
+
+-- name: MarkEmailVerified :exec
+UPDATE users
+SET email_verified_at = COALESCE(email_verified_at, CURRENT_TIMESTAMP)
+WHERE user_id = $1 AND email = $2;
+
+-- name: StartTOTPEnrollment :exec
+UPDATE users
+SET totp_secret = $1, totp_recovery_codes = '{}', totp_last_used_step = NULL
+WHERE user_id = $2 AND totp_enabled_at IS NULL;
+
+-- name: EnableTOTP :exec
+UPDATE users
+SET totp_enabled_at = CURRENT_TIMESTAMP, totp_recovery_codes = $1, totp_last_used_step = $2
+WHERE user_id = $3 AND totp_secret IS NOT NULL;
+
+-- name: DisableTOTP :exec
+UPDATE users
+SET totp_secret = NULL, totp_enabled_at = NULL, totp_recovery_codes = '{}', totp_last_used_step = NULL
+WHERE user_id = $1;
+
+-- name: UpdateTOTPLastUsedStep :execrows
+UPDATE users
+SET totp_last_used_step = $1
+WHERE user_id = $2 AND (totp_last_used_step IS NULL OR totp_last_used_step < $1);
+
+-- name: UseTOTPRecoveryCode :execrows
+UPDATE users
+SET totp_recovery_codes = array_remove(totp_recovery_codes, sqlc.arg(code_hash)::text)
+WHERE user_id = sqlc.arg(user_id) AND sqlc.arg(code_hash)::text = ANY(totp_recovery_codes);
+
+-- name: CreateOIDCUser :one
+-- Users created through an OIDC provider have no password until they set one via the password reset flow
+INSERT INTO users (email, password_hash, email_verified_at) VALUES ($1, '', CURRENT_TIMESTAMP) RETURNING *;
+
+-- name: ListUsers :many
+-- Users whose email or username matches the pattern, along with their todo counts
+SELECT users.user_id, users.username, users.email, users.role, users.email_verified_at, users.totp_enabled_at, users.disabled_at, users.created_at,
+  COUNT(todos.id) AS todo_count,
+  COUNT(todos.id) FILTER (WHERE todos.completed) AS completed_todo_count
+FROM users
+LEFT JOIN todos ON todos.user_id = users.id
+WHERE users.email ILIKE sqlc.arg(pattern)::text OR users.username ILIKE sqlc.arg(pattern)::text
+GROUP BY users.id
+ORDER BY users.created_at, users.id
+LIMIT sqlc.arg(row_limit) OFFSET sqlc.arg(row_offset);
+
+-- name: CountUsers :one
+SELECT COUNT(*) FROM users
+WHERE email ILIKE sqlc.arg(pattern)::text OR username ILIKE sqlc.arg(pattern)::text;
+
+-- name: DisableUser :execrows
+UPDATE users
+SET disabled_at = COALESCE(disabled_at, CURRENT_TIMESTAMP)
+WHERE user_id = $1;
+
+-- name: EnableUser :execrows
+UPDATE users
+SET disabled_at = NULL
+WHERE user_id = $1;
+
+-- name: RequestUserDeletion :one
+-- Keeps the original request time when the account is deleted again, so that the grace period is not extended
+UPDATE users
+SET deletion_requested_at = COALESCE(deletion_requested_at, CURRENT_TIMESTAMP)
+WHERE user_id = $1
+RETURNING deletion_requested_at;
+
+-- name: RestoreUser :execrows
+-- Only accounts whose deletion was requested after the cutoff (i.e. still within the grace period) can be restored
+UPDATE users
+SET deletion_requested_at = NULL
+WHERE user_id = sqlc.arg(user_id) AND deletion_requested_at > sqlc.arg(cutoff);
+
+-- name: PurgeDeletedUsers :many
+-- Deletes a batch of accounts whose deletion was requested before the cutoff; their data goes along by ON DELETE CASCADE.
+-- Returns the user_id of each purged account so that the data kept outside the database can be removed too
+DELETE FROM users
+WHERE id IN (
+  SELECT id FROM users
+  WHERE deletion_requested_at < sqlc.arg(cutoff)
+  ORDER BY deletion_requested_at
+  LIMIT sqlc.arg(batch_size)
+  FOR UPDATE SKIP LOCKED
+)
+RETURNING user_id;
+
+-- name: LockUser :exec
+-- Makes the other transactions locking the user wait until this one ends
+SELECT id FROM users WHERE id = $1 FOR UPDATE;
\ No newline at end of file
@@ -9,8 +9,12 @@ import (
 
 	"github.com/gin-contrib/sessions"
 	"github.com/gin-contrib/sessions/redis"
+	redigo "github.com/gomodule/redigo/redis"
 )
 
+// Redis key prefix of the gin-contrib sessions; other stores rely on it to revoke a session directly
+const SessionKeyPrefix = "session_"
+
 func SetupRedisStore(runningEnv string) (redis.Store, error) {
 	log.Println("opening connection to redis...")
 
@@ -32,7 +36,12 @@ func SetupRedisStore(runningEnv string) (redis.Store, error) {
 
 	log.Println("connected to redis")
 
-	tokenLifeSpanHour, err := strconv.Atoi(os.Getenv("JWT_ACCESS_TOKEN_EXP_HOUR"))
+	if err := redis.SetKeyPrefix(store, SessionKeyPrefix); err != nil {
+		return nil, fmt.Errorf("failed to set session key prefix: %w", err)
+	}
+
+	// The session has to outlive the short-lived access tokens since it backs the refresh tokens as well
+	tokenLifeSpanHour, err := strconv.Atoi(os.Getenv("JWT_REFRESH_TOKEN_EXP_HOUR"))
 	if err != nil {
 		return nil, fmt.Errorf("failed to obtain required parameter for store options: %w", err)
 	}
@@ -47,3 +56,13 @@ func SetupRedisStore(runningEnv string) (redis.Store, error) {
 
 	return store, nil
 }
+
+// Expose the underlying connection pool of the session store so that other Redis-backed stores can share it
+func GetRedisPool(store redis.Store) (*redigo.Pool, error) {
+	err, rediStore := redis.GetRedisStore(store)
+	if err != nil {
+		return nil, err
+	}
+
+	return rediStore.Pool, nil
+}
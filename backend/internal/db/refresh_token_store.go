@@ -0,0 +1,118 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	redigo "github.com/gomodule/redigo/redis"
+)
+
+/*
+Refresh tokens are opaque and only their hash is stored in Redis
+Every refresh token issued for the same session belongs to the same family, so that a reused token can revoke the whole session
+*/
+const refreshTokenKeyPrefix = "refresh_token:"
+const refreshTokenUsedKeyPrefix = "refresh_token_used:"
+const refreshFamilyKeyPrefix = "refresh_family:"
+
+var ErrRefreshTokenNotFound = errors.New("refresh token not found")
+
+type RefreshToken struct {
+	UserID    string    `json:"user_id"`
+	SessionID string    `json:"session_id"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+type RedisRefreshTokenStore struct {
+	Pool *redigo.Pool
+}
+
+func NewRedisRefreshTokenStore(pool *redigo.Pool) *RedisRefreshTokenStore {
+	return &RedisRefreshTokenStore{Pool: pool}
+}
+
+func (s *RedisRefreshTokenStore) Save(ctx context.Context, tokenHash string, token RefreshToken) error {
+	ttl := int64(time.Until(token.ExpiresAt).Seconds())
+	if ttl <= 0 {
+		return ErrRefreshTokenNotFound
+	}
+
+	b, err := json.Marshal(token)
+	if err != nil {
+		return err
+	}
+
+	conn := s.Pool.Get()
+	defer conn.Close()
+
+	familyKey := refreshFamilyKeyPrefix + token.SessionID
+	conn.Send("MULTI")
+	conn.Send("SETEX", refreshTokenKeyPrefix+tokenHash, ttl, b)
+	conn.Send("SADD", familyKey, tokenHash)
+	conn.Send("EXPIRE", familyKey, ttl)
+	_, err = conn.Do("EXEC")
+	return err
+}
+
+func (s *RedisRefreshTokenStore) Get(ctx context.Context, tokenHash string) (*RefreshToken, error) {
+	conn := s.Pool.Get()
+	defer conn.Close()
+
+	b, err := redigo.Bytes(conn.Do("GET", refreshTokenKeyPrefix+tokenHash))
+	if err != nil {
+		if err == redigo.ErrNil {
+			return nil, ErrRefreshTokenNotFound
+		}
+		return nil, err
+	}
+
+	var token RefreshToken
+	if err := json.Unmarshal(b, &token); err != nil {
+		return nil, err
+	}
+
+	return &token, nil
+}
+
+// Returns false if the token had already been marked as used, which means it is being reused
+func (s *RedisRefreshTokenStore) MarkUsed(ctx context.Context, tokenHash string, expiresAt time.Time) (bool, error) {
+	ttl := int64(time.Until(expiresAt).Seconds())
+	if ttl <= 0 {
+		return false, ErrRefreshTokenNotFound
+	}
+
+	conn := s.Pool.Get()
+	defer conn.Close()
+
+	_, err := redigo.String(conn.Do("SET", refreshTokenUsedKeyPrefix+tokenHash, 1, "EX", ttl, "NX"))
+	if err != nil {
+		if err == redigo.ErrNil {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return true, nil
+}
+
+// Deletes every refresh token of the family along with the session itself
+func (s *RedisRefreshTokenStore) RevokeFamily(ctx context.Context, sessionID string) error {
+	conn := s.Pool.Get()
+	defer conn.Close()
+
+	familyKey := refreshFamilyKeyPrefix + sessionID
+	tokenHashes, err := redigo.Strings(conn.Do("SMEMBERS", familyKey))
+	if err != nil {
+		return err
+	}
+
+	keys := []interface{}{familyKey, SessionKeyPrefix + sessionID}
+	for _, tokenHash := range tokenHashes {
+		keys = append(keys, refreshTokenKeyPrefix+tokenHash, refreshTokenUsedKeyPrefix+tokenHash)
+	}
+
+	_, err = conn.Do("DEL", keys...)
+	return err
+}
@@ -0,0 +1,176 @@
+package db
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"time"
+
+	redigo "github.com/gomodule/redigo/redis"
+)
+
+/*
+Per-user index of the sessions so that a user can list and revoke their logins
+The session ID itself is the key of the gin-contrib session, so only a hash of it (public ID) is exposed to the client
+*/
+const userSessionsKeyPrefix = "user_sessions:"
+const sessionMetaKeyPrefix = "session_meta:"
+
+var ErrSessionNotFound = errors.New("session not found")
+
+type Session struct {
+	ID         string
+	PublicID   string
+	UserID     string
+	UserAgent  string
+	IP         string
+	CreatedAt  time.Time
+	LastSeenAt time.Time
+}
+
+// Only update the last-seen time of a session which has not been revoked yet
+var touchSessionScript = redigo.NewScript(1, `
+if redis.call("EXISTS", KEYS[1]) == 1 then
+  redis.call("HSET", KEYS[1], "last_seen_at", ARGV[1])
+  return 1
+end
+return 0
+`)
+
+func SessionPublicID(sessionID string) string {
+	sum := sha256.Sum256([]byte(sessionID))
+	return hex.EncodeToString(sum[:12])
+}
+
+type RedisSessionStore struct {
+	Pool *redigo.Pool
+}
+
+func NewRedisSessionStore(pool *redigo.Pool) *RedisSessionStore {
+	return &RedisSessionStore{Pool: pool}
+}
+
+func (s *RedisSessionStore) Create(ctx context.Context, session Session, expiresAt time.Time) error {
+	ttl := int64(time.Until(expiresAt).Seconds())
+	if ttl <= 0 {
+		return ErrSessionNotFound
+	}
+
+	conn := s.Pool.Get()
+	defer conn.Close()
+
+	metaKey := sessionMetaKeyPrefix + session.ID
+	indexKey := userSessionsKeyPrefix + session.UserID
+	conn.Send("MULTI")
+	conn.Send("HSET", metaKey,
+		"user_id", session.UserID,
+		"user_agent", session.UserAgent,
+		"ip", session.IP,
+		"created_at", session.CreatedAt.Unix(),
+		"last_seen_at", session.LastSeenAt.Unix(),
+	)
+	conn.Send("EXPIRE", metaKey, ttl)
+	conn.Send("HSET", indexKey, SessionPublicID(session.ID), session.ID)
+	// Sessions have an absolute lifetime, so the newest one always outlives the others
+	conn.Send("EXPIRE", indexKey, ttl)
+	_, err := conn.Do("EXEC")
+	return err
+}
+
+// Returns false if the session is no longer in the index, i.e. it has been revoked or has expired
+func (s *RedisSessionStore) Touch(ctx context.Context, sessionID string, lastSeenAt time.Time) (bool, error) {
+	conn := s.Pool.Get()
+	defer conn.Close()
+
+	return redigo.Bool(touchSessionScript.Do(conn, sessionMetaKeyPrefix+sessionID, lastSeenAt.Unix()))
+}
+
+func (s *RedisSessionStore) List(ctx context.Context, userID string) ([]Session, error) {
+	conn := s.Pool.Get()
+	defer conn.Close()
+
+	indexKey := userSessionsKeyPrefix + userID
+	index, err := redigo.StringMap(conn.Do("HGETALL", indexKey))
+	if err != nil {
+		return nil, err
+	}
+
+	sessions := make([]Session, 0, len(index))
+	for publicID, sessionID := range index {
+		meta, err := redigo.StringMap(conn.Do("HGETALL", sessionMetaKeyPrefix+sessionID))
+		if err != nil {
+			return nil, err
+		}
+
+		// The metadata expires along with the session, so drop the stale index entry
+		if len(meta) == 0 {
+			if _, err := conn.Do("HDEL", indexKey, publicID); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		sessions = append(sessions, sessionFromMeta(sessionID, meta))
+	}
+
+	return sessions, nil
+}
+
+func (s *RedisSessionStore) FindByPublicID(ctx context.Context, userID, publicID string) (*Session, error) {
+	conn := s.Pool.Get()
+	defer conn.Close()
+
+	sessionID, err := redigo.String(conn.Do("HGET", userSessionsKeyPrefix+userID, publicID))
+	if err != nil {
+		if err == redigo.ErrNil {
+			return nil, ErrSessionNotFound
+		}
+		return nil, err
+	}
+
+	meta, err := redigo.StringMap(conn.Do("HGETALL", sessionMetaKeyPrefix+sessionID))
+	if err != nil {
+		return nil, err
+	}
+	if len(meta) == 0 {
+		return nil, ErrSessionNotFound
+	}
+
+	session := sessionFromMeta(sessionID, meta)
+	return &session, nil
+}
+
+// Deletes the session itself as well as its metadata so that it cannot be used anymore
+func (s *RedisSessionStore) Delete(ctx context.Context, userID, sessionID string) error {
+	conn := s.Pool.Get()
+	defer conn.Close()
+
+	conn.Send("MULTI")
+	conn.Send("DEL", SessionKeyPrefix+sessionID, sessionMetaKeyPrefix+sessionID)
+	conn.Send("HDEL", userSessionsKeyPrefix+userID, SessionPublicID(sessionID))
+	_, err := conn.Do("EXEC")
+	return err
+}
+
+func sessionFromMeta(sessionID string, meta map[string]string) Session {
+	return Session{
+		ID:         sessionID,
+		PublicID:   SessionPublicID(sessionID),
+		UserID:     meta["user_id"],
+		UserAgent:  meta["user_agent"],
+		IP:         meta["ip"],
+		CreatedAt:  unixStrToTime(meta["created_at"]),
+		LastSeenAt: unixStrToTime(meta["last_seen_at"]),
+	}
+}
+
+func unixStrToTime(s string) time.Time {
+	sec, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return time.Time{}
+	}
+
+	return time.Unix(sec, 0).UTC()
+}
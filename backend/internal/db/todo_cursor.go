@@ -0,0 +1,83 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Number of todos fetched from the cursor at a time
+const todoCursorFetchSize = 500
+
+const declareTodoCursor = `DECLARE todo_cursor NO SCROLL CURSOR FOR
+SELECT id, user_id, description, position, completed, created_at, updated_at, due_at, remind_at, recurrence, items_total, items_completed, list_id, tags, priority, language FROM todos WHERE user_id = $1 ORDER BY list_id, position, id
+`
+
+var fetchTodoCursor = fmt.Sprintf("FETCH FORWARD %d FROM todo_cursor", todoCursorFetchSize)
+
+// Calls fn for each todo of the user in list and position order. The todos are fetched in batches from a server-side cursor,
+// so that only one batch is held in memory however many todos the user has. sqlc does not support cursors, so this is written by hand.
+func (q *WrappedQueries) StreamTodos(ctx context.Context, userID int32, fn func(Todo) error) error {
+	// A cursor has to live in a transaction
+	tx, err := q.begin(ctx)
+	if err != nil {
+		return err
+	}
+	// Nothing is written, so the transaction is only rolled back to close the cursor
+	defer tx.Rollback(ctx)
+
+	if _, err = tx.Exec(ctx, declareTodoCursor, userID); err != nil {
+		return err
+	}
+
+	for {
+		fetched, err := fetchTodos(ctx, tx, fn)
+		if err != nil {
+			return err
+		}
+		if fetched < todoCursorFetchSize {
+			return nil
+		}
+	}
+}
+
+func fetchTodos(ctx context.Context, tx pgx.Tx, fn func(Todo) error) (int, error) {
+	rows, err := tx.Query(ctx, fetchTodoCursor)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	fetched := 0
+	for rows.Next() {
+		var i Todo
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Description,
+			&i.Position,
+			&i.Completed,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DueAt,
+			&i.RemindAt,
+			&i.Recurrence,
+			&i.ItemsTotal,
+			&i.ItemsCompleted,
+			&i.ListID,
+			&i.Tags,
+			&i.Priority,
+			&i.Language,
+		); err != nil {
+			return fetched, err
+		}
+		fetched++
+
+		if err := fn(i); err != nil {
+			return fetched, err
+		}
+	}
+
+	return fetched, rows.Err()
+}
@@ -0,0 +1,324 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// A column to sort todos by, descending when Desc is set
+type TodoSortKey struct {
+	Column string
+	Desc   bool
+}
+
+// The columns todos can be sorted by. The sort keys come from the request, so only these ever make it into the SQL.
+var TodoSortColumns = []string{"position", "priority", "due_at", "created_at", "updated_at", "description"}
+
+// The manual order, which the sqlc queries are written for
+var TodoManualSort = []TodoSortKey{{Column: "position"}}
+
+// Keyset pagination: a page holds up to Limit todos following After in the sort order. The zero value means every todo.
+type TodoPageParams struct {
+	// Only the sorted columns and the id are read
+	After *Todo
+	// The rank of After, when paging through search results
+	AfterRank float32
+	Limit     int32
+}
+
+// Filters of ListTodosFiltered on top of those of ListTodosParams. Only the ones set make it into the query, as plain conditions
+// the planner can match against the indexes, such as user_id and completed against idx_todos_user_id_completed_position.
+type TodoFilter struct {
+	Completed     pgtype.Bool
+	CreatedAfter  pgtype.Timestamptz
+	CreatedBefore pgtype.Timestamptz
+	UpdatedAfter  pgtype.Timestamptz
+	UpdatedBefore pgtype.Timestamptz
+	// Text the description contains, ignoring case
+	Query string
+}
+
+// The sqlc queries without their ORDER BY, which is built from the sort keys instead
+var (
+	listTodosUnordered   = withoutOrderBy(listTodos, "ORDER BY list_id, position\n")
+	searchTodosUnordered = withoutOrderBy(searchTodos, "ORDER BY rank DESC, id\n")
+)
+
+// Panics at startup if the query no longer ends with the ORDER BY, rather than sending two of them with every request
+func withoutOrderBy(query, orderBy string) string {
+	unordered, found := strings.CutSuffix(query, orderBy)
+	if !found {
+		panic(fmt.Sprintf("query does not end with %q: %s", orderBy, query))
+	}
+	return unordered
+}
+
+// The rank of SearchTodos as WHERE can refer to it, unlike its alias
+const searchTodosRank = "matched.rank"
+
+// Runs ListTodos narrowed down by the filter and ordered by the given keys, with the id breaking ties, one page at a time.
+// sqlc cannot leave out conditions or parameterize ORDER BY, so this is written by hand.
+func (q *WrappedQueries) ListTodosFiltered(ctx context.Context, arg ListTodosParams, filter TodoFilter, sort []TodoSortKey, page TodoPageParams) ([]Todo, error) {
+	terms, err := todoSortTerms(sort)
+	if err != nil {
+		return nil, err
+	}
+
+	query, args := todosPageQuery(listTodosUnordered, []any{
+		arg.UserID,
+		arg.ListID,
+		arg.DueBefore,
+		arg.DueAfter,
+		arg.Overdue,
+		arg.Tags,
+		arg.MatchAllTags,
+	}, filter, terms, page)
+
+	rows, err := q.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Todo
+	for rows.Next() {
+		var i Todo
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Description,
+			&i.Position,
+			&i.Completed,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DueAt,
+			&i.RemindAt,
+			&i.Recurrence,
+			&i.ItemsTotal,
+			&i.ItemsCompleted,
+			&i.ListID,
+			&i.Tags,
+			&i.Priority,
+			&i.Language,
+			&i.SearchVector,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// SearchTodos one page at a time, the most relevant first
+func (q *WrappedQueries) SearchTodosPage(ctx context.Context, arg SearchTodosParams, page TodoPageParams) ([]SearchTodosRow, error) {
+	terms := []todoSortTerm{
+		{column: searchTodosRank, desc: true, value: func(*Todo) (any, bool) { return page.AfterRank, true }},
+		todoIDSortTerm,
+	}
+
+	query, args := todosPageQuery(searchTodosUnordered, []any{
+		arg.UserID,
+		arg.Keyword,
+		arg.Prefix,
+		arg.PrefixOf,
+		arg.Fuzzy,
+		arg.Pattern,
+		arg.Tags,
+		arg.MatchAllTags,
+	}, TodoFilter{}, terms, page)
+
+	rows, err := q.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SearchTodosRow
+	for rows.Next() {
+		var i SearchTodosRow
+		if err := rows.Scan(
+			&i.Todo.ID,
+			&i.Todo.UserID,
+			&i.Todo.Description,
+			&i.Todo.Position,
+			&i.Todo.Completed,
+			&i.Todo.CreatedAt,
+			&i.Todo.UpdatedAt,
+			&i.Todo.DueAt,
+			&i.Todo.RemindAt,
+			&i.Todo.Recurrence,
+			&i.Todo.ItemsTotal,
+			&i.Todo.ItemsCompleted,
+			&i.Todo.ListID,
+			&i.Todo.Tags,
+			&i.Todo.Priority,
+			&i.Todo.Language,
+			&i.Todo.SearchVector,
+			&i.Rank,
+			&i.Headline,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// Appends the filter and keyset conditions, ORDER BY and LIMIT to a query selecting todos whose WHERE clause comes last
+func todosPageQuery(query string, args []any, filter TodoFilter, terms []todoSortTerm, page TodoPageParams) (string, []any) {
+	b := &todoQueryBuilder{args: args}
+	filter.apply(b)
+	if page.After != nil {
+		b.where(keysetCondition(terms, page.After, b))
+	}
+	for _, condition := range b.conditions {
+		query += "  AND " + condition + "\n"
+	}
+	query += "ORDER BY " + orderBy(terms) + "\n"
+	if page.Limit > 0 {
+		query += "LIMIT " + b.arg(page.Limit) + "\n"
+	}
+
+	return query, b.args
+}
+
+// Collects the conditions appended to a query along with their args, numbering the placeholders after the args the query already takes.
+// Values only ever become args, so the SQL stays the same whatever the request holds.
+type todoQueryBuilder struct {
+	conditions []string
+	args       []any
+}
+
+// Adds the value as an arg and returns its placeholder
+func (b *todoQueryBuilder) arg(value any) string {
+	b.args = append(b.args, value)
+	return fmt.Sprintf("$%d", len(b.args))
+}
+
+func (b *todoQueryBuilder) where(condition string) {
+	b.conditions = append(b.conditions, condition)
+}
+
+// Ranges include their start and exclude their end, like the due date filters of ListTodos
+func (f TodoFilter) apply(b *todoQueryBuilder) {
+	if f.Completed.Valid {
+		b.where("completed = " + b.arg(f.Completed.Bool))
+	}
+	if f.CreatedAfter.Valid {
+		b.where("created_at >= " + b.arg(f.CreatedAfter))
+	}
+	if f.CreatedBefore.Valid {
+		b.where("created_at < " + b.arg(f.CreatedBefore))
+	}
+	if f.UpdatedAfter.Valid {
+		b.where("updated_at >= " + b.arg(f.UpdatedAfter))
+	}
+	if f.UpdatedBefore.Valid {
+		b.where("updated_at < " + b.arg(f.UpdatedBefore))
+	}
+	if f.Query != "" {
+		b.where("description ILIKE " + b.arg(ContainsPattern(f.Query)))
+	}
+}
+
+// Makes the wildcards of LIKE match themselves, with backslash being its default escape character
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// A LIKE pattern matching text that contains s
+func ContainsPattern(s string) string {
+	return "%" + likeEscaper.Replace(s) + "%"
+}
+
+// A column of the ORDER BY along with how to read its value off a todo
+type todoSortTerm struct {
+	column string
+	desc   bool
+	// The value of the column and whether it is set, as NULL sorts last in either direction
+	value func(todo *Todo) (any, bool)
+}
+
+// Positions only make sense within a list, so sorting by position keeps the todos grouped by list.
+// The id comes last so that the order, and thus the keyset, is unique.
+func todoSortTerms(sort []TodoSortKey) ([]todoSortTerm, error) {
+	terms := make([]todoSortTerm, 0, len(sort)+2)
+	for _, key := range sort {
+		if !slices.Contains(TodoSortColumns, key.Column) {
+			return nil, fmt.Errorf("cannot sort todos by %q", key.Column)
+		}
+
+		if key.Column == "position" {
+			terms = append(terms, todoSortTerm{column: "list_id", value: func(t *Todo) (any, bool) { return t.ListID, true }})
+		}
+		terms = append(terms, todoSortTerm{column: key.Column, desc: key.Desc, value: todoColumnValue(key.Column)})
+	}
+	terms = append(terms, todoIDSortTerm)
+
+	return terms, nil
+}
+
+var todoIDSortTerm = todoSortTerm{column: "id", value: func(t *Todo) (any, bool) { return t.ID, true }}
+
+func todoColumnValue(column string) func(todo *Todo) (any, bool) {
+	switch column {
+	case "position":
+		return func(t *Todo) (any, bool) { return t.Position, t.Position.Valid }
+	case "priority":
+		return func(t *Todo) (any, bool) { return t.Priority, true }
+	case "due_at":
+		return func(t *Todo) (any, bool) { return t.DueAt, t.DueAt.Valid }
+	case "created_at":
+		return func(t *Todo) (any, bool) { return t.CreatedAt, t.CreatedAt.Valid }
+	case "updated_at":
+		return func(t *Todo) (any, bool) { return t.UpdatedAt, t.UpdatedAt.Valid }
+	default:
+		return func(t *Todo) (any, bool) { return t.Description, true }
+	}
+}
+
+func orderBy(terms []todoSortTerm) string {
+	columns := make([]string, len(terms))
+	for i, term := range terms {
+		columns[i] = term.column
+		if term.desc {
+			columns[i] += " DESC NULLS LAST"
+		} else if !slices.Contains([]string{"list_id", "id"}, term.column) {
+			columns[i] += " ASC NULLS LAST"
+		}
+	}
+	return strings.Join(columns, ", ")
+}
+
+// The todos after the given one: those past it in the first term, or tied in it and past it in the next, and so on.
+// Row comparisons such as (a, b) > ($1, $2) cannot mix directions or place NULL, hence the expanded form.
+func keysetCondition(terms []todoSortTerm, after *Todo, b *todoQueryBuilder) string {
+	var alternatives, ties []string
+	for _, term := range terms {
+		value, set := term.value(after)
+		if !set {
+			// Nothing sorts after NULL but more NULLs
+			ties = append(ties, term.column+" IS NULL")
+			continue
+		}
+
+		placeholder := b.arg(value)
+
+		operator := ">"
+		if term.desc {
+			operator = "<"
+		}
+		past := fmt.Sprintf("(%s %s %s OR %s IS NULL)", term.column, operator, placeholder, term.column)
+		alternatives = append(alternatives, strings.Join(append(slices.Clone(ties), past), " AND "))
+
+		ties = append(ties, term.column+" = "+placeholder)
+	}
+
+	return "(" + strings.Join(alternatives, "\n    OR ") + ")"
+}
@@ -0,0 +1,264 @@
+package db_test
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+	"todo-app/internal/db"
+	"todo-app/internal/utils/testutils"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errRecorded = errors.New("recorded")
+
+// Records the statement sent instead of running it
+type recordingDB struct {
+	query string
+	args  []any
+}
+
+func (r *recordingDB) Exec(ctx context.Context, query string, args ...interface{}) (pgconn.CommandTag, error) {
+	return pgconn.CommandTag{}, errRecorded
+}
+
+func (r *recordingDB) Query(ctx context.Context, query string, args ...interface{}) (pgx.Rows, error) {
+	r.query, r.args = query, args
+	return nil, errRecorded
+}
+
+func (r *recordingDB) QueryRow(ctx context.Context, query string, args ...interface{}) pgx.Row {
+	return nil
+}
+
+// The args of ListTodosParams{UserID: 1}, which come before those of the filter, the keyset and the limit
+var listTodosArgs = []any{int32(1), pgtype.Int4{}, pgtype.Timestamptz{}, pgtype.Timestamptz{}, false, []string(nil), false}
+
+func TestListTodosFiltered_Pages(t *testing.T) {
+	createdAt := pgtype.Timestamptz{Time: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), Valid: true}
+	dueAt := pgtype.Timestamptz{Time: time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC), Valid: true}
+	position := pgtype.Numeric{Int: big.NewInt(100), Valid: true}
+
+	tests := []struct {
+		name     string
+		sort     []db.TodoSortKey
+		page     db.TodoPageParams
+		wantFile string
+		wantArgs []any
+	}{
+		{
+			name:     "every todo",
+			sort:     db.TodoManualSort,
+			wantFile: "testdata/list_todos_filtered/every_todo.sql.golden",
+			wantArgs: listTodosArgs,
+		},
+		{
+			name:     "first page",
+			sort:     db.TodoManualSort,
+			page:     db.TodoPageParams{Limit: 51},
+			wantFile: "testdata/list_todos_filtered/first_page.sql.golden",
+			wantArgs: append(listTodosArgs, int32(51)),
+		},
+		{
+			name:     "after position",
+			sort:     db.TodoManualSort,
+			page:     db.TodoPageParams{After: &db.Todo{ID: 7, ListID: 3, Position: position}, Limit: 51},
+			wantFile: "testdata/list_todos_filtered/after_position.sql.golden",
+			wantArgs: append(listTodosArgs, int32(3), position, int32(7), int32(51)),
+		},
+		{
+			name:     "after priority",
+			sort:     []db.TodoSortKey{{Column: "priority", Desc: true}},
+			page:     db.TodoPageParams{After: &db.Todo{ID: 7, Priority: 3}, Limit: 51},
+			wantFile: "testdata/list_todos_filtered/after_priority.sql.golden",
+			wantArgs: append(listTodosArgs, int16(3), int32(7), int32(51)),
+		},
+		{
+			name:     "after due date",
+			sort:     []db.TodoSortKey{{Column: "due_at"}},
+			page:     db.TodoPageParams{After: &db.Todo{ID: 7, DueAt: dueAt}, Limit: 51},
+			wantFile: "testdata/list_todos_filtered/after_due_at.sql.golden",
+			wantArgs: append(listTodosArgs, dueAt, int32(7), int32(51)),
+		},
+		{
+			name:     "after no due date",
+			sort:     []db.TodoSortKey{{Column: "due_at"}},
+			page:     db.TodoPageParams{After: &db.Todo{ID: 7}, Limit: 51},
+			wantFile: "testdata/list_todos_filtered/after_null_due_at.sql.golden",
+			wantArgs: append(listTodosArgs, int32(7), int32(51)),
+		},
+		{
+			name:     "after no due date descending",
+			sort:     []db.TodoSortKey{{Column: "due_at", Desc: true}},
+			page:     db.TodoPageParams{After: &db.Todo{ID: 7}, Limit: 51},
+			wantFile: "testdata/list_todos_filtered/after_null_due_at_desc.sql.golden",
+			wantArgs: append(listTodosArgs, int32(7), int32(51)),
+		},
+		{
+			name:     "after created_at",
+			sort:     []db.TodoSortKey{{Column: "created_at", Desc: true}},
+			page:     db.TodoPageParams{After: &db.Todo{ID: 7, CreatedAt: createdAt}, Limit: 51},
+			wantFile: "testdata/list_todos_filtered/after_created_at.sql.golden",
+			wantArgs: append(listTodosArgs, createdAt, int32(7), int32(51)),
+		},
+		{
+			name:     "after updated_at",
+			sort:     []db.TodoSortKey{{Column: "updated_at"}},
+			page:     db.TodoPageParams{After: &db.Todo{ID: 7, UpdatedAt: createdAt}, Limit: 51},
+			wantFile: "testdata/list_todos_filtered/after_updated_at.sql.golden",
+			wantArgs: append(listTodosArgs, createdAt, int32(7), int32(51)),
+		},
+		{
+			name:     "after description",
+			sort:     []db.TodoSortKey{{Column: "description"}},
+			page:     db.TodoPageParams{After: &db.Todo{ID: 7, Description: "buy milk"}, Limit: 51},
+			wantFile: "testdata/list_todos_filtered/after_description.sql.golden",
+			wantArgs: append(listTodosArgs, "buy milk", int32(7), int32(51)),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := &recordingDB{}
+			q := db.NewWrappedQuerier(db.New(recorder))
+
+			_, err := q.ListTodosFiltered(context.Background(), db.ListTodosParams{UserID: 1}, db.TodoFilter{}, tt.sort, tt.page)
+
+			require.ErrorIs(t, err, errRecorded)
+			assert.Equal(t, string(testutils.LoadFile(t, tt.wantFile)), recorder.query)
+			assert.Equal(t, tt.wantArgs, recorder.args)
+		})
+	}
+}
+
+func TestSearchTodosPage(t *testing.T) {
+	arg := db.SearchTodosParams{UserID: 1, Keyword: "milk", Prefix: "milk", Fuzzy: "milk", Pattern: "%milk%"}
+	searchTodosArgs := []any{int32(1), "milk", "milk", "", "milk", "%milk%", []string(nil), false}
+
+	tests := []struct {
+		name     string
+		page     db.TodoPageParams
+		wantFile string
+		wantArgs []any
+	}{
+		{
+			name:     "first page",
+			page:     db.TodoPageParams{Limit: 51},
+			wantFile: "testdata/search_todos_page/first_page.sql.golden",
+			wantArgs: append(searchTodosArgs, int32(51)),
+		},
+		{
+			// Ties in rank are broken by id, as in SearchTodos
+			name:     "after rank",
+			page:     db.TodoPageParams{After: &db.Todo{ID: 7}, AfterRank: 2.5, Limit: 51},
+			wantFile: "testdata/search_todos_page/after_rank.sql.golden",
+			wantArgs: append(searchTodosArgs, float32(2.5), int32(7), int32(51)),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := &recordingDB{}
+			q := db.NewWrappedQuerier(db.New(recorder))
+
+			_, err := q.SearchTodosPage(context.Background(), arg, tt.page)
+
+			require.ErrorIs(t, err, errRecorded)
+			assert.Equal(t, string(testutils.LoadFile(t, tt.wantFile)), recorder.query)
+			assert.Equal(t, tt.wantArgs, recorder.args)
+		})
+	}
+}
+
+func TestListTodosFiltered_Filters(t *testing.T) {
+	after := pgtype.Timestamptz{Time: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), Valid: true}
+	before := pgtype.Timestamptz{Time: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), Valid: true}
+	position := pgtype.Numeric{Int: big.NewInt(100), Valid: true}
+
+	tests := []struct {
+		name     string
+		filter   db.TodoFilter
+		sort     []db.TodoSortKey
+		page     db.TodoPageParams
+		wantFile string
+		wantArgs []any
+	}{
+		{
+			name: "every filter",
+			filter: db.TodoFilter{
+				Completed:     pgtype.Bool{Bool: false, Valid: true},
+				CreatedAfter:  after,
+				CreatedBefore: before,
+				UpdatedAfter:  after,
+				UpdatedBefore: before,
+				Query:         `50%_off\`,
+			},
+			sort:     db.TodoManualSort,
+			wantFile: "testdata/list_todos_filtered/every_filter.sql.golden",
+			wantArgs: append(listTodosArgs, false, after, before, after, before, `%50\%\_off\\%`),
+		},
+		{
+			// The keyset and the limit are numbered after the filters
+			name:     "filtered page",
+			filter:   db.TodoFilter{Completed: pgtype.Bool{Bool: true, Valid: true}, Query: "milk"},
+			sort:     []db.TodoSortKey{{Column: "priority", Desc: true}},
+			page:     db.TodoPageParams{After: &db.Todo{ID: 7, Priority: 3}, Limit: 51},
+			wantFile: "testdata/list_todos_filtered/filtered_page.sql.golden",
+			wantArgs: append(listTodosArgs, true, "%milk%", int16(3), int32(7), int32(51)),
+		},
+		{
+			// Sorting by position keeps the todos grouped by list, and the id breaks the ties left
+			name:     "several sort keys",
+			sort:     []db.TodoSortKey{{Column: "priority", Desc: true}, {Column: "position"}, {Column: "due_at"}},
+			page:     db.TodoPageParams{After: &db.Todo{ID: 7, ListID: 3, Priority: 3, Position: position}, Limit: 51},
+			wantFile: "testdata/list_todos_filtered/several_sort_keys.sql.golden",
+			wantArgs: append(listTodosArgs, int16(3), int32(3), position, int32(7), int32(51)),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := &recordingDB{}
+			q := db.NewWrappedQuerier(db.New(recorder))
+
+			_, err := q.ListTodosFiltered(context.Background(), db.ListTodosParams{UserID: 1}, tt.filter, tt.sort, tt.page)
+
+			require.ErrorIs(t, err, errRecorded)
+			assert.Equal(t, string(testutils.LoadFile(t, tt.wantFile)), recorder.query)
+			assert.Equal(t, tt.wantArgs, recorder.args)
+		})
+	}
+}
+
+func TestListTodosFiltered_InvalidSortColumn(t *testing.T) {
+	recorder := &recordingDB{}
+	q := db.NewWrappedQuerier(db.New(recorder))
+
+	_, err := q.ListTodosFiltered(context.Background(), db.ListTodosParams{UserID: 1}, db.TodoFilter{}, []db.TodoSortKey{{Column: "user_id; DROP TABLE todos"}}, db.TodoPageParams{})
+
+	assert.Error(t, err)
+	assert.Empty(t, recorder.query)
+}
+
+func TestContainsPattern(t *testing.T) {
+	tests := []struct {
+		s    string
+		want string
+	}{
+		{s: "milk", want: "%milk%"},
+		{s: "50%", want: `%50\%%`},
+		{s: "snake_case", want: `%snake\_case%`},
+		{s: `C:\temp`, want: `%C:\\temp%`},
+		{s: "", want: "%%"},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, db.ContainsPattern(tt.s), tt.s)
+	}
+}
@@ -11,21 +11,54 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const countTodos = `-- name: CountTodos :one
+SELECT COUNT(*) AS total, COUNT(*) FILTER (WHERE completed) AS completed
+FROM todos WHERE user_id = $1
+`
+
+type CountTodosRow struct {
+	Total     int64
+	Completed int64
+}
+
+func (q *Queries) CountTodos(ctx context.Context, userID int32) (CountTodosRow, error) {
+	row := q.db.QueryRow(ctx, countTodos, userID)
+	var i CountTodosRow
+	err := row.Scan(&i.Total, &i.Completed)
+	return i, err
+}
+
 const createTodo = `-- name: CreateTodo :one
-INSERT INTO todos (user_id, description, position)
-VALUES ($1, $2, 
-    COALESCE((SELECT MAX(position) FROM todos WHERE user_id = $1) + 100, 100)  -- default gap of 100
+INSERT INTO todos (user_id, list_id, description, position, due_at, remind_at, recurrence, priority, language)
+VALUES ($1, $2, $3, 
+    COALESCE((SELECT MAX(position) FROM todos WHERE list_id = $2) + 100, 100),  -- default gap of 100
+    $4, $5, $6, $7, $8
 )
-RETURNING id, user_id, description, position, completed, created_at, updated_at
+RETURNING id, user_id, description, position, completed, created_at, updated_at, due_at, remind_at, recurrence, items_total, items_completed, list_id, tags, priority, language, search_vector
 `
 
 type CreateTodoParams struct {
 	UserID      int32
+	ListID      int32
 	Description string
+	DueAt       pgtype.Timestamptz
+	RemindAt    pgtype.Timestamptz
+	Recurrence  pgtype.Text
+	Priority    int16
+	Language    string
 }
 
 func (q *Queries) CreateTodo(ctx context.Context, arg CreateTodoParams) (Todo, error) {
-	row := q.db.QueryRow(ctx, createTodo, arg.UserID, arg.Description)
+	row := q.db.QueryRow(ctx, createTodo,
+		arg.UserID,
+		arg.ListID,
+		arg.Description,
+		arg.DueAt,
+		arg.RemindAt,
+		arg.Recurrence,
+		arg.Priority,
+		arg.Language,
+	)
 	var i Todo
 	err := row.Scan(
 		&i.ID,
@@ -35,13 +68,23 @@ func (q *Queries) CreateTodo(ctx context.Context, arg CreateTodoParams) (Todo, e
 		&i.Completed,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.DueAt,
+		&i.RemindAt,
+		&i.Recurrence,
+		&i.ItemsTotal,
+		&i.ItemsCompleted,
+		&i.ListID,
+		&i.Tags,
+		&i.Priority,
+		&i.Language,
+		&i.SearchVector,
 	)
 	return i, err
 }
 
 const deleteTodo = `-- name: DeleteTodo :one
 DELETE FROM todos WHERE id = $1 AND user_id = $2
-RETURNING id, user_id, description, position, completed, created_at, updated_at
+RETURNING id, user_id, description, position, completed, created_at, updated_at, due_at, remind_at, recurrence, items_total, items_completed, list_id, tags, priority, language, search_vector
 `
 
 type DeleteTodoParams struct {
@@ -60,16 +103,130 @@ func (q *Queries) DeleteTodo(ctx context.Context, arg DeleteTodoParams) (Todo, e
 		&i.Completed,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.DueAt,
+		&i.RemindAt,
+		&i.Recurrence,
+		&i.ItemsTotal,
+		&i.ItemsCompleted,
+		&i.ListID,
+		&i.Tags,
+		&i.Priority,
+		&i.Language,
+		&i.SearchVector,
+	)
+	return i, err
+}
+
+const getTodo = `-- name: GetTodo :one
+SELECT id, user_id, description, position, completed, created_at, updated_at, due_at, remind_at, recurrence, items_total, items_completed, list_id, tags, priority, language, search_vector FROM todos WHERE id = $1 AND user_id = $2
+`
+
+type GetTodoParams struct {
+	ID     int32
+	UserID int32
+}
+
+func (q *Queries) GetTodo(ctx context.Context, arg GetTodoParams) (Todo, error) {
+	row := q.db.QueryRow(ctx, getTodo, arg.ID, arg.UserID)
+	var i Todo
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Description,
+		&i.Position,
+		&i.Completed,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DueAt,
+		&i.RemindAt,
+		&i.Recurrence,
+		&i.ItemsTotal,
+		&i.ItemsCompleted,
+		&i.ListID,
+		&i.Tags,
+		&i.Priority,
+		&i.Language,
+		&i.SearchVector,
+	)
+	return i, err
+}
+
+const getTodoForUpdate = `-- name: GetTodoForUpdate :one
+SELECT id, user_id, description, position, completed, created_at, updated_at, due_at, remind_at, recurrence, items_total, items_completed, list_id, tags, priority, language, search_vector FROM todos WHERE id = $1 AND user_id = $2
+FOR UPDATE
+`
+
+type GetTodoForUpdateParams struct {
+	ID     int32
+	UserID int32
+}
+
+// Locks the todo until the end of the transaction, so that concurrent updates see each other's changes
+func (q *Queries) GetTodoForUpdate(ctx context.Context, arg GetTodoForUpdateParams) (Todo, error) {
+	row := q.db.QueryRow(ctx, getTodoForUpdate, arg.ID, arg.UserID)
+	var i Todo
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Description,
+		&i.Position,
+		&i.Completed,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DueAt,
+		&i.RemindAt,
+		&i.Recurrence,
+		&i.ItemsTotal,
+		&i.ItemsCompleted,
+		&i.ListID,
+		&i.Tags,
+		&i.Priority,
+		&i.Language,
+		&i.SearchVector,
 	)
 	return i, err
 }
 
 const listTodos = `-- name: ListTodos :many
-SELECT id, user_id, description, position, completed, created_at, updated_at FROM todos WHERE user_id = $1 ORDER BY position
+SELECT id, user_id, description, position, completed, created_at, updated_at, due_at, remind_at, recurrence, items_total, items_completed, list_id, tags, priority, language, search_vector FROM todos
+WHERE user_id = $1
+  AND (CASE WHEN $2::INTEGER IS NULL
+         THEN list_id IN (SELECT id FROM lists WHERE lists.user_id = $1 AND archived_at IS NULL)
+         ELSE list_id = $2 END)
+  AND ($3::TIMESTAMPTZ IS NULL OR due_at < $3)
+  AND ($4::TIMESTAMPTZ IS NULL OR due_at >= $4)
+  AND (NOT $5::BOOLEAN OR (due_at < CURRENT_TIMESTAMP AND NOT completed))
+  AND ($6::TEXT[] IS NULL OR (
+    SELECT COUNT(*) FROM todo_tags
+    JOIN tags ON tags.id = todo_tags.tag_id
+    WHERE todo_tags.todo_id = todos.id AND tags.name = ANY($6::TEXT[])
+  ) >= CASE WHEN $7::BOOLEAN THEN cardinality($6::TEXT[]) ELSE 1 END)
+ORDER BY list_id, position
 `
 
-func (q *Queries) ListTodos(ctx context.Context, userID int32) ([]Todo, error) {
-	rows, err := q.db.Query(ctx, listTodos, userID)
+type ListTodosParams struct {
+	UserID       int32
+	ListID       pgtype.Int4
+	DueBefore    pgtype.Timestamptz
+	DueAfter     pgtype.Timestamptz
+	Overdue      bool
+	Tags         []string
+	MatchAllTags bool
+}
+
+// Without a list, the todos of every list not archived, list by list.
+// The due date and tag filters are skipped when null; overdue means due in the past and not completed yet.
+// Todos match either all of the tags or any of them
+func (q *Queries) ListTodos(ctx context.Context, arg ListTodosParams) ([]Todo, error) {
+	rows, err := q.db.Query(ctx, listTodos,
+		arg.UserID,
+		arg.ListID,
+		arg.DueBefore,
+		arg.DueAfter,
+		arg.Overdue,
+		arg.Tags,
+		arg.MatchAllTags,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -85,6 +242,16 @@ func (q *Queries) ListTodos(ctx context.Context, userID int32) ([]Todo, error) {
 			&i.Completed,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.DueAt,
+			&i.RemindAt,
+			&i.Recurrence,
+			&i.ItemsTotal,
+			&i.ItemsCompleted,
+			&i.ListID,
+			&i.Tags,
+			&i.Priority,
+			&i.Language,
+			&i.SearchVector,
 		); err != nil {
 			return nil, err
 		}
@@ -96,36 +263,159 @@ func (q *Queries) ListTodos(ctx context.Context, userID int32) ([]Todo, error) {
 	return items, nil
 }
 
+const moveTodo = `-- name: MoveTodo :one
+UPDATE todos
+SET list_id = $3,
+    position = CASE
+        WHEN $4::NUMERIC IS NULL AND $5::NUMERIC IS NULL
+            THEN COALESCE((SELECT MAX(t.position) FROM todos t WHERE t.list_id = $3 AND t.id <> $1) + 100, 100)
+        WHEN $5::NUMERIC IS NULL THEN $4::NUMERIC + 100
+        WHEN $4::NUMERIC IS NULL THEN $5::NUMERIC / 2
+        ELSE ($4::NUMERIC + $5::NUMERIC) / 2
+    END,
+    updated_at = NOW()
+WHERE todos.id = $1 AND todos.user_id = $2
+  AND EXISTS (SELECT 1 FROM lists WHERE lists.id = $3 AND lists.user_id = $2)
+RETURNING id, user_id, description, position, completed, created_at, updated_at, due_at, remind_at, recurrence, items_total, items_completed, list_id, tags, priority, language, search_vector
+`
+
+type MoveTodoParams struct {
+	ID      int32
+	UserID  int32
+	ListID  int32
+	Prevpos pgtype.Numeric
+	Nextpos pgtype.Numeric
+}
+
+// Moves the todo into another list of the user, between the given positions there.
+// Without the next position it goes after the previous one, and without either at the end of the list
+func (q *Queries) MoveTodo(ctx context.Context, arg MoveTodoParams) (Todo, error) {
+	row := q.db.QueryRow(ctx, moveTodo,
+		arg.ID,
+		arg.UserID,
+		arg.ListID,
+		arg.Prevpos,
+		arg.Nextpos,
+	)
+	var i Todo
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Description,
+		&i.Position,
+		&i.Completed,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DueAt,
+		&i.RemindAt,
+		&i.Recurrence,
+		&i.ItemsTotal,
+		&i.ItemsCompleted,
+		&i.ListID,
+		&i.Tags,
+		&i.Priority,
+		&i.Language,
+		&i.SearchVector,
+	)
+	return i, err
+}
+
 const searchTodos = `-- name: SearchTodos :many
-SELECT id, user_id, description, position, completed, created_at, updated_at
+WITH search AS (
+  SELECT websearch_to_tsquery('english', $2::TEXT) || CASE WHEN $3::TEXT = '' THEN ''::TSQUERY
+      ELSE websearch_to_tsquery('english', $4::TEXT) && to_tsquery('english', $3::TEXT || ':*') END AS english,
+    websearch_to_tsquery('japanese', $2::TEXT) || CASE WHEN $3::TEXT = '' THEN ''::TSQUERY
+      ELSE websearch_to_tsquery('japanese', $4::TEXT) && to_tsquery('japanese', $3::TEXT || ':*') END AS japanese
+)
+SELECT todos.id, todos.user_id, todos.description, todos.position, todos.completed, todos.created_at, todos.updated_at, todos.due_at, todos.remind_at, todos.recurrence, todos.items_total, todos.items_completed, todos.list_id, todos.tags, todos.priority, todos.language, todos.search_vector,
+  matched.rank,
+  CASE WHEN search_vector @@ matched.query
+    THEN ts_headline(language::REGCONFIG, description, matched.query, 'StartSel=' || chr(2) || ', StopSel=' || chr(3))
+    ELSE replace(description, $5::TEXT, chr(2) || $5::TEXT || chr(3)) END AS headline
 FROM todos
+CROSS JOIN search
+CROSS JOIN LATERAL (
+  SELECT query, (CASE WHEN todos.search_vector @@ query THEN 2 + ts_rank(todos.search_vector, query, 32)
+      WHEN todos.description ILIKE $6::TEXT THEN 1 + word_similarity($5::TEXT, todos.description)
+      ELSE word_similarity($5::TEXT, todos.description) END)::REAL AS rank
+  FROM (SELECT CASE todos.language WHEN 'japanese' THEN search.japanese ELSE search.english END AS query) AS todo_search
+) AS matched
 WHERE user_id = $1
-  AND description @@ to_tsquery('english', $2)
-ORDER BY position
+  AND ((language = 'english' AND search_vector @@ search.english)
+    OR (language = 'japanese' AND search_vector @@ search.japanese)
+    OR $5::TEXT <% description
+    OR description ILIKE $6::TEXT)
+  AND ($7::TEXT[] IS NULL OR (
+    SELECT COUNT(*) FROM todo_tags
+    JOIN tags ON tags.id = todo_tags.tag_id
+    WHERE todo_tags.todo_id = todos.id AND tags.name = ANY($7::TEXT[])
+  ) >= CASE WHEN $8::BOOLEAN THEN cardinality($7::TEXT[]) ELSE 1 END)
+ORDER BY rank DESC, id
 `
 
 type SearchTodosParams struct {
-	UserID    int32
-	ToTsquery string
+	UserID       int32
+	Keyword      string
+	Prefix       string
+	PrefixOf     string
+	Fuzzy        string
+	Pattern      string
+	Tags         []string
+	MatchAllTags bool
+}
+
+type SearchTodosRow struct {
+	Todo     Todo
+	Rank     float32
+	Headline string
 }
 
-func (q *Queries) SearchTodos(ctx context.Context, arg SearchTodosParams) ([]Todo, error) {
-	rows, err := q.db.Query(ctx, searchTodos, arg.UserID, arg.ToTsquery)
+// The keyword follows the web search syntax of websearch_to_tsquery, which takes any input, and is matched in the language
+// of each todo. For type-ahead, its last word also matches as a prefix: prefix is that word when it is being typed,
+// and prefix_of the keyword before it.
+// Trigrams find the todos text search misses: those containing fuzzy (the plain words of the keyword, which pattern
+// is an ILIKE pattern for) or a word similar to it. Text search matches rank first, then the todos containing fuzzy,
+// then those similar to it, each by their score.
+// Headlines mark the matched words, or else fuzzy where it appears as given, with \x02 and \x03.
+// The tag filter works the same as in ListTodos
+func (q *Queries) SearchTodos(ctx context.Context, arg SearchTodosParams) ([]SearchTodosRow, error) {
+	rows, err := q.db.Query(ctx, searchTodos,
+		arg.UserID,
+		arg.Keyword,
+		arg.Prefix,
+		arg.PrefixOf,
+		arg.Fuzzy,
+		arg.Pattern,
+		arg.Tags,
+		arg.MatchAllTags,
+	)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []Todo
+	var items []SearchTodosRow
 	for rows.Next() {
-		var i Todo
+		var i SearchTodosRow
 		if err := rows.Scan(
-			&i.ID,
-			&i.UserID,
-			&i.Description,
-			&i.Position,
-			&i.Completed,
-			&i.CreatedAt,
-			&i.UpdatedAt,
+			&i.Todo.ID,
+			&i.Todo.UserID,
+			&i.Todo.Description,
+			&i.Todo.Position,
+			&i.Todo.Completed,
+			&i.Todo.CreatedAt,
+			&i.Todo.UpdatedAt,
+			&i.Todo.DueAt,
+			&i.Todo.RemindAt,
+			&i.Todo.Recurrence,
+			&i.Todo.ItemsTotal,
+			&i.Todo.ItemsCompleted,
+			&i.Todo.ListID,
+			&i.Todo.Tags,
+			&i.Todo.Priority,
+			&i.Todo.Language,
+			&i.Todo.SearchVector,
+			&i.Rank,
+			&i.Headline,
 		); err != nil {
 			return nil, err
 		}
@@ -142,9 +432,14 @@ UPDATE todos
 SET description = $2, 
     completed = $3, 
     position = $4,
+    due_at = $6,
+    remind_at = $7,
+    recurrence = $8,
+    priority = $9,
+    language = $10,
     updated_at = NOW()
 WHERE id = $1 AND user_id = $5
-RETURNING id, user_id, description, position, completed, created_at, updated_at
+RETURNING id, user_id, description, position, completed, created_at, updated_at, due_at, remind_at, recurrence, items_total, items_completed, list_id, tags, priority, language, search_vector
 `
 
 type UpdateTodoParams struct {
@@ -153,6 +448,11 @@ type UpdateTodoParams struct {
 	Completed   pgtype.Bool
 	Position    pgtype.Numeric
 	UserID      int32
+	DueAt       pgtype.Timestamptz
+	RemindAt    pgtype.Timestamptz
+	Recurrence  pgtype.Text
+	Priority    int16
+	Language    string
 }
 
 func (q *Queries) UpdateTodo(ctx context.Context, arg UpdateTodoParams) (Todo, error) {
@@ -162,6 +462,11 @@ func (q *Queries) UpdateTodo(ctx context.Context, arg UpdateTodoParams) (Todo, e
 		arg.Completed,
 		arg.Position,
 		arg.UserID,
+		arg.DueAt,
+		arg.RemindAt,
+		arg.Recurrence,
+		arg.Priority,
+		arg.Language,
 	)
 	var i Todo
 	err := row.Scan(
@@ -172,6 +477,16 @@ func (q *Queries) UpdateTodo(ctx context.Context, arg UpdateTodoParams) (Todo, e
 		&i.Completed,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.DueAt,
+		&i.RemindAt,
+		&i.Recurrence,
+		&i.ItemsTotal,
+		&i.ItemsCompleted,
+		&i.ListID,
+		&i.Tags,
+		&i.Priority,
+		&i.Language,
+		&i.SearchVector,
 	)
 	return i, err
 }
@@ -181,7 +496,7 @@ UPDATE todos
 SET position = ($3::NUMERIC + $4::NUMERIC) / 2,
     updated_at = NOW()
 WHERE todos.id = $1 AND todos.user_id = $2
-RETURNING id, user_id, description, position, completed, created_at, updated_at
+RETURNING id, user_id, description, position, completed, created_at, updated_at, due_at, remind_at, recurrence, items_total, items_completed, list_id, tags, priority, language, search_vector
 `
 
 type UpdateTodoPositionParams struct {
@@ -207,6 +522,16 @@ func (q *Queries) UpdateTodoPosition(ctx context.Context, arg UpdateTodoPosition
 		&i.Completed,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.DueAt,
+		&i.RemindAt,
+		&i.Recurrence,
+		&i.ItemsTotal,
+		&i.ItemsCompleted,
+		&i.ListID,
+		&i.Tags,
+		&i.Priority,
+		&i.Language,
+		&i.SearchVector,
 	)
 	return i, err
 }
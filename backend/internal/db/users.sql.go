@@ -11,17 +11,25 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
-const createUser = `-- name: CreateUser :one
-INSERT INTO users (email, password_hash) VALUES ($1, $2) RETURNING id, user_id, username, email, password_hash, created_at, updated_at
+const countUsers = `-- name: CountUsers :one
+SELECT COUNT(*) FROM users
+WHERE email ILIKE $1::text OR username ILIKE $1::text
 `
 
-type CreateUserParams struct {
-	Email        string
-	PasswordHash []byte
+func (q *Queries) CountUsers(ctx context.Context, pattern string) (int64, error) {
+	row := q.db.QueryRow(ctx, countUsers, pattern)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
 }
 
-func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, error) {
-	row := q.db.QueryRow(ctx, createUser, arg.Email, arg.PasswordHash)
+const createOIDCUser = `-- name: CreateOIDCUser :one
+INSERT INTO users (email, password_hash, email_verified_at) VALUES ($1, '', CURRENT_TIMESTAMP) RETURNING id, user_id, username, email, password_hash, created_at, updated_at, email_verified_at, totp_secret, totp_enabled_at, totp_recovery_codes, totp_last_used_step, role, disabled_at, deletion_requested_at
+`
+
+// Users created through an OIDC provider have no password until they set one via the password reset flow
+func (q *Queries) CreateOIDCUser(ctx context.Context, email string) (User, error) {
+	row := q.db.QueryRow(ctx, createOIDCUser, email)
 	var i User
 	err := row.Scan(
 		&i.ID,
@@ -31,17 +39,29 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 		&i.PasswordHash,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.EmailVerifiedAt,
+		&i.TotpSecret,
+		&i.TotpEnabledAt,
+		&i.TotpRecoveryCodes,
+		&i.TotpLastUsedStep,
+		&i.Role,
+		&i.DisabledAt,
+		&i.DeletionRequestedAt,
 	)
 	return i, err
 }
 
-const deleteUser = `-- name: DeleteUser :one
-DELETE FROM users WHERE user_id = $1
-RETURNING id, user_id, username, email, password_hash, created_at, updated_at
+const createUser = `-- name: CreateUser :one
+INSERT INTO users (email, password_hash) VALUES ($1, $2) RETURNING id, user_id, username, email, password_hash, created_at, updated_at, email_verified_at, totp_secret, totp_enabled_at, totp_recovery_codes, totp_last_used_step, role, disabled_at, deletion_requested_at
 `
 
-func (q *Queries) DeleteUser(ctx context.Context, userID pgtype.UUID) (User, error) {
-	row := q.db.QueryRow(ctx, deleteUser, userID)
+type CreateUserParams struct {
+	Email        string
+	PasswordHash []byte
+}
+
+func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, error) {
+	row := q.db.QueryRow(ctx, createUser, arg.Email, arg.PasswordHash)
 	var i User
 	err := row.Scan(
 		&i.ID,
@@ -51,12 +71,76 @@ func (q *Queries) DeleteUser(ctx context.Context, userID pgtype.UUID) (User, err
 		&i.PasswordHash,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.EmailVerifiedAt,
+		&i.TotpSecret,
+		&i.TotpEnabledAt,
+		&i.TotpRecoveryCodes,
+		&i.TotpLastUsedStep,
+		&i.Role,
+		&i.DisabledAt,
+		&i.DeletionRequestedAt,
 	)
 	return i, err
 }
 
+const disableTOTP = `-- name: DisableTOTP :exec
+UPDATE users
+SET totp_secret = NULL, totp_enabled_at = NULL, totp_recovery_codes = '{}', totp_last_used_step = NULL
+WHERE user_id = $1
+`
+
+func (q *Queries) DisableTOTP(ctx context.Context, userID pgtype.UUID) error {
+	_, err := q.db.Exec(ctx, disableTOTP, userID)
+	return err
+}
+
+const disableUser = `-- name: DisableUser :execrows
+UPDATE users
+SET disabled_at = COALESCE(disabled_at, CURRENT_TIMESTAMP)
+WHERE user_id = $1
+`
+
+func (q *Queries) DisableUser(ctx context.Context, userID pgtype.UUID) (int64, error) {
+	result, err := q.db.Exec(ctx, disableUser, userID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const enableTOTP = `-- name: EnableTOTP :exec
+UPDATE users
+SET totp_enabled_at = CURRENT_TIMESTAMP, totp_recovery_codes = $1, totp_last_used_step = $2
+WHERE user_id = $3 AND totp_secret IS NOT NULL
+`
+
+type EnableTOTPParams struct {
+	TotpRecoveryCodes []string
+	TotpLastUsedStep  pgtype.Int8
+	UserID            pgtype.UUID
+}
+
+func (q *Queries) EnableTOTP(ctx context.Context, arg EnableTOTPParams) error {
+	_, err := q.db.Exec(ctx, enableTOTP, arg.TotpRecoveryCodes, arg.TotpLastUsedStep, arg.UserID)
+	return err
+}
+
+const enableUser = `-- name: EnableUser :execrows
+UPDATE users
+SET disabled_at = NULL
+WHERE user_id = $1
+`
+
+func (q *Queries) EnableUser(ctx context.Context, userID pgtype.UUID) (int64, error) {
+	result, err := q.db.Exec(ctx, enableUser, userID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const getUserByEmail = `-- name: GetUserByEmail :one
-SELECT id, user_id, username, email, password_hash, created_at, updated_at FROM users WHERE email = $1 LIMIT 1
+SELECT id, user_id, username, email, password_hash, created_at, updated_at, email_verified_at, totp_secret, totp_enabled_at, totp_recovery_codes, totp_last_used_step, role, disabled_at, deletion_requested_at FROM users WHERE email = $1 LIMIT 1
 `
 
 func (q *Queries) GetUserByEmail(ctx context.Context, email string) (User, error) {
@@ -70,12 +154,20 @@ func (q *Queries) GetUserByEmail(ctx context.Context, email string) (User, error
 		&i.PasswordHash,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.EmailVerifiedAt,
+		&i.TotpSecret,
+		&i.TotpEnabledAt,
+		&i.TotpRecoveryCodes,
+		&i.TotpLastUsedStep,
+		&i.Role,
+		&i.DisabledAt,
+		&i.DeletionRequestedAt,
 	)
 	return i, err
 }
 
 const getUserByID = `-- name: GetUserByID :one
-SELECT id, user_id, username, email, password_hash, created_at, updated_at FROM users WHERE id = $1
+SELECT id, user_id, username, email, password_hash, created_at, updated_at, email_verified_at, totp_secret, totp_enabled_at, totp_recovery_codes, totp_last_used_step, role, disabled_at, deletion_requested_at FROM users WHERE id = $1
 `
 
 func (q *Queries) GetUserByID(ctx context.Context, id int32) (User, error) {
@@ -89,12 +181,20 @@ func (q *Queries) GetUserByID(ctx context.Context, id int32) (User, error) {
 		&i.PasswordHash,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.EmailVerifiedAt,
+		&i.TotpSecret,
+		&i.TotpEnabledAt,
+		&i.TotpRecoveryCodes,
+		&i.TotpLastUsedStep,
+		&i.Role,
+		&i.DisabledAt,
+		&i.DeletionRequestedAt,
 	)
 	return i, err
 }
 
 const getUserByUserID = `-- name: GetUserByUserID :one
-SELECT id, user_id, username, email, password_hash, created_at, updated_at FROM users WHERE user_id = $1
+SELECT id, user_id, username, email, password_hash, created_at, updated_at, email_verified_at, totp_secret, totp_enabled_at, totp_recovery_codes, totp_last_used_step, role, disabled_at, deletion_requested_at FROM users WHERE user_id = $1
 `
 
 func (q *Queries) GetUserByUserID(ctx context.Context, userID pgtype.UUID) (User, error) {
@@ -108,10 +208,253 @@ func (q *Queries) GetUserByUserID(ctx context.Context, userID pgtype.UUID) (User
 		&i.PasswordHash,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.EmailVerifiedAt,
+		&i.TotpSecret,
+		&i.TotpEnabledAt,
+		&i.TotpRecoveryCodes,
+		&i.TotpLastUsedStep,
+		&i.Role,
+		&i.DisabledAt,
+		&i.DeletionRequestedAt,
 	)
 	return i, err
 }
 
+const listUsers = `-- name: ListUsers :many
+SELECT users.user_id, users.username, users.email, users.role, users.email_verified_at, users.totp_enabled_at, users.disabled_at, users.created_at,
+  COUNT(todos.id) AS todo_count,
+  COUNT(todos.id) FILTER (WHERE todos.completed) AS completed_todo_count
+FROM users
+LEFT JOIN todos ON todos.user_id = users.id
+WHERE users.email ILIKE $1::text OR users.username ILIKE $1::text
+GROUP BY users.id
+ORDER BY users.created_at, users.id
+LIMIT $2 OFFSET $3
+`
+
+type ListUsersParams struct {
+	Pattern   string
+	RowLimit  int32
+	RowOffset int32
+}
+
+type ListUsersRow struct {
+	UserID             pgtype.UUID
+	Username           string
+	Email              string
+	Role               string
+	EmailVerifiedAt    pgtype.Timestamptz
+	TotpEnabledAt      pgtype.Timestamptz
+	DisabledAt         pgtype.Timestamptz
+	CreatedAt          pgtype.Timestamptz
+	TodoCount          int64
+	CompletedTodoCount int64
+}
+
+// Users whose email or username matches the pattern, along with their todo counts
+func (q *Queries) ListUsers(ctx context.Context, arg ListUsersParams) ([]ListUsersRow, error) {
+	rows, err := q.db.Query(ctx, listUsers, arg.Pattern, arg.RowLimit, arg.RowOffset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListUsersRow
+	for rows.Next() {
+		var i ListUsersRow
+		if err := rows.Scan(
+			&i.UserID,
+			&i.Username,
+			&i.Email,
+			&i.Role,
+			&i.EmailVerifiedAt,
+			&i.TotpEnabledAt,
+			&i.DisabledAt,
+			&i.CreatedAt,
+			&i.TodoCount,
+			&i.CompletedTodoCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const lockUser = `-- name: LockUser :exec
+SELECT id FROM users WHERE id = $1 FOR UPDATE
+`
+
+// Makes the other transactions locking the user wait until this one ends
+func (q *Queries) LockUser(ctx context.Context, id int32) error {
+	_, err := q.db.Exec(ctx, lockUser, id)
+	return err
+}
+
+const markEmailVerified = `-- name: MarkEmailVerified :exec
+UPDATE users
+SET email_verified_at = COALESCE(email_verified_at, CURRENT_TIMESTAMP)
+WHERE user_id = $1 AND email = $2
+`
+
+type MarkEmailVerifiedParams struct {
+	UserID pgtype.UUID
+	Email  string
+}
+
+func (q *Queries) MarkEmailVerified(ctx context.Context, arg MarkEmailVerifiedParams) error {
+	_, err := q.db.Exec(ctx, markEmailVerified, arg.UserID, arg.Email)
+	return err
+}
+
+const purgeDeletedUsers = `-- name: PurgeDeletedUsers :many
+DELETE FROM users
+WHERE id IN (
+  SELECT id FROM users
+  WHERE deletion_requested_at < $1
+  ORDER BY deletion_requested_at
+  LIMIT $2
+  FOR UPDATE SKIP LOCKED
+)
+RETURNING user_id
+`
+
+type PurgeDeletedUsersParams struct {
+	Cutoff    pgtype.Timestamptz
+	BatchSize int32
+}
+
+// Deletes a batch of accounts whose deletion was requested before the cutoff; their data goes along by ON DELETE CASCADE.
+// Returns the user_id of each purged account so that the data kept outside the database can be removed too
+func (q *Queries) PurgeDeletedUsers(ctx context.Context, arg PurgeDeletedUsersParams) ([]pgtype.UUID, error) {
+	rows, err := q.db.Query(ctx, purgeDeletedUsers, arg.Cutoff, arg.BatchSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []pgtype.UUID
+	for rows.Next() {
+		var user_id pgtype.UUID
+		if err := rows.Scan(&user_id); err != nil {
+			return nil, err
+		}
+		items = append(items, user_id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const requestUserDeletion = `-- name: RequestUserDeletion :one
+UPDATE users
+SET deletion_requested_at = COALESCE(deletion_requested_at, CURRENT_TIMESTAMP)
+WHERE user_id = $1
+RETURNING deletion_requested_at
+`
+
+// Keeps the original request time when the account is deleted again, so that the grace period is not extended
+func (q *Queries) RequestUserDeletion(ctx context.Context, userID pgtype.UUID) (pgtype.Timestamptz, error) {
+	row := q.db.QueryRow(ctx, requestUserDeletion, userID)
+	var deletion_requested_at pgtype.Timestamptz
+	err := row.Scan(&deletion_requested_at)
+	return deletion_requested_at, err
+}
+
+const restoreUser = `-- name: RestoreUser :execrows
+UPDATE users
+SET deletion_requested_at = NULL
+WHERE user_id = $1 AND deletion_requested_at > $2
+`
+
+type RestoreUserParams struct {
+	UserID pgtype.UUID
+	Cutoff pgtype.Timestamptz
+}
+
+// Only accounts whose deletion was requested after the cutoff (i.e. still within the grace period) can be restored
+func (q *Queries) RestoreUser(ctx context.Context, arg RestoreUserParams) (int64, error) {
+	result, err := q.db.Exec(ctx, restoreUser, arg.UserID, arg.Cutoff)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const startTOTPEnrollment = `-- name: StartTOTPEnrollment :exec
+UPDATE users
+SET totp_secret = $1, totp_recovery_codes = '{}', totp_last_used_step = NULL
+WHERE user_id = $2 AND totp_enabled_at IS NULL
+`
+
+type StartTOTPEnrollmentParams struct {
+	TotpSecret pgtype.Text
+	UserID     pgtype.UUID
+}
+
+func (q *Queries) StartTOTPEnrollment(ctx context.Context, arg StartTOTPEnrollmentParams) error {
+	_, err := q.db.Exec(ctx, startTOTPEnrollment, arg.TotpSecret, arg.UserID)
+	return err
+}
+
+const updateEmail = `-- name: UpdateEmail :execrows
+UPDATE users
+SET email = $1, email_verified_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+WHERE user_id = $2 AND email = $3
+`
+
+type UpdateEmailParams struct {
+	NewEmail string
+	UserID   pgtype.UUID
+	Email    string
+}
+
+// Swaps the email only if it is still the one the change was requested from. The new email counts as verified, as the change was confirmed through it.
+func (q *Queries) UpdateEmail(ctx context.Context, arg UpdateEmailParams) (int64, error) {
+	result, err := q.db.Exec(ctx, updateEmail, arg.NewEmail, arg.UserID, arg.Email)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const updatePasswordHash = `-- name: UpdatePasswordHash :exec
+UPDATE users
+SET password_hash = $1, updated_at = CURRENT_TIMESTAMP
+WHERE user_id = $2
+`
+
+type UpdatePasswordHashParams struct {
+	PasswordHash []byte
+	UserID       pgtype.UUID
+}
+
+func (q *Queries) UpdatePasswordHash(ctx context.Context, arg UpdatePasswordHashParams) error {
+	_, err := q.db.Exec(ctx, updatePasswordHash, arg.PasswordHash, arg.UserID)
+	return err
+}
+
+const updateTOTPLastUsedStep = `-- name: UpdateTOTPLastUsedStep :execrows
+UPDATE users
+SET totp_last_used_step = $1
+WHERE user_id = $2 AND (totp_last_used_step IS NULL OR totp_last_used_step < $1)
+`
+
+type UpdateTOTPLastUsedStepParams struct {
+	TotpLastUsedStep pgtype.Int8
+	UserID           pgtype.UUID
+}
+
+func (q *Queries) UpdateTOTPLastUsedStep(ctx context.Context, arg UpdateTOTPLastUsedStepParams) (int64, error) {
+	result, err := q.db.Exec(ctx, updateTOTPLastUsedStep, arg.TotpLastUsedStep, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const updateUsername = `-- name: UpdateUsername :exec
 UPDATE users
 SET username = $1, updated_at = CURRENT_TIMESTAMP
@@ -127,3 +470,22 @@ func (q *Queries) UpdateUsername(ctx context.Context, arg UpdateUsernameParams)
 	_, err := q.db.Exec(ctx, updateUsername, arg.Username, arg.UserID)
 	return err
 }
+
+const useTOTPRecoveryCode = `-- name: UseTOTPRecoveryCode :execrows
+UPDATE users
+SET totp_recovery_codes = array_remove(totp_recovery_codes, $1::text)
+WHERE user_id = $2 AND $1::text = ANY(totp_recovery_codes)
+`
+
+type UseTOTPRecoveryCodeParams struct {
+	CodeHash string
+	UserID   pgtype.UUID
+}
+
+func (q *Queries) UseTOTPRecoveryCode(ctx context.Context, arg UseTOTPRecoveryCodeParams) (int64, error) {
+	result, err := q.db.Exec(ctx, useTOTPRecoveryCode, arg.CodeHash, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
@@ -1,6 +1,9 @@
 package db
 
 import (
+	"context"
+	"errors"
+
 	"github.com/jackc/pgx/v5"
 )
 
@@ -9,6 +12,10 @@ import (
 type WrappedQuerier interface {
 	Querier
 	WithTx(tx pgx.Tx) WrappedQuerier
+	ExecTx(ctx context.Context, fn func(q WrappedQuerier) error) error
+	StreamTodos(ctx context.Context, userID int32, fn func(Todo) error) error
+	ListTodosFiltered(ctx context.Context, arg ListTodosParams, filter TodoFilter, sort []TodoSortKey, page TodoPageParams) ([]Todo, error)
+	SearchTodosPage(ctx context.Context, arg SearchTodosParams, page TodoPageParams) ([]SearchTodosRow, error)
 }
 
 type WrappedQueries struct {
@@ -21,6 +28,35 @@ func (q *WrappedQueries) WithTx(tx pgx.Tx) WrappedQuerier {
 	}
 }
 
+// Runs fn with queries bound to a transaction (see WithTx), which is committed if fn succeeds and rolled back otherwise.
+// Inside another transaction it becomes a savepoint.
+func (q *WrappedQueries) ExecTx(ctx context.Context, fn func(q WrappedQuerier) error) error {
+	tx, err := q.begin(ctx)
+	if err != nil {
+		return err
+	}
+	// A no-op once the transaction is committed
+	defer tx.Rollback(ctx)
+
+	if err = fn(q.WithTx(tx)); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// Both *pgxpool.Pool and pgx.Tx (as a savepoint) can begin a transaction
+func (q *WrappedQueries) begin(ctx context.Context) (pgx.Tx, error) {
+	beginner, ok := q.db.(interface {
+		Begin(ctx context.Context) (pgx.Tx, error)
+	})
+	if !ok {
+		return nil, errors.New("connection cannot begin a transaction")
+	}
+
+	return beginner.Begin(ctx)
+}
+
 func NewWrappedQuerier(q *Queries) WrappedQuerier {
 	return &WrappedQueries{
 		Queries: q,
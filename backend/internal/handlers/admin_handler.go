@@ -0,0 +1,241 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"time"
+	"todo-app/internal/db"
+	"todo-app/internal/services"
+	"todo-app/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type AdminHandler struct {
+	AdminService services.IAdminService
+}
+
+type AdminUserResponse struct {
+	UserID             string     `json:"user_id"`
+	Username           string     `json:"username"`
+	Email              string     `json:"email"`
+	Role               string     `json:"role"`
+	EmailVerified      bool       `json:"email_verified"`
+	TwoFactorEnabled   bool       `json:"two_factor_enabled"`
+	Disabled           bool       `json:"disabled"`
+	DisabledAt         *time.Time `json:"disabled_at"`
+	CreatedAt          time.Time  `json:"created_at"`
+	TodoCount          int64      `json:"todo_count"`
+	CompletedTodoCount int64      `json:"completed_todo_count"`
+}
+
+type AdminUserListResponse struct {
+	Users   []AdminUserResponse `json:"users"`
+	Total   int64               `json:"total"`
+	Page    int                 `json:"page"`
+	PerPage int                 `json:"per_page"`
+}
+
+func NewAdminHandler(adminService services.IAdminService) *AdminHandler {
+	return &AdminHandler{AdminService: adminService}
+}
+
+// @Summary List users
+// @Description Users whose email or username contains q, oldest first. Requires the admin role.
+// @Tags Admin
+// @Produce json
+// @Param q query string false "part of the email or username"
+// @Param page query int false "page number (default 1)"
+// @Param per_page query int false "users per page (default 20, max 100)"
+// @Security BearerAuth
+// @Success 200 {object} AdminUserListResponse
+// @Failure 400 {object} gin.H "{"error": "Invalid request"}"
+// @Failure 403 {object} gin.H "{"error": "You do not have permission to access this resource"}"
+// @Failure 500 {object} gin.H "{"error": "Internal server error"}"
+// @Router /admin/users [get]
+func (h *AdminHandler) ListUsers(ctx *gin.Context) {
+	var req services.ListUsersRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": utils.MsgInvalidReq})
+		return
+	}
+
+	userList, err := h.AdminService.ListUsers(ctx, req)
+	if err != nil {
+		log.Println(err.Error())
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": utils.MsgInternalServerErr})
+		return
+	}
+
+	userResponses := make([]AdminUserResponse, len(userList.Users))
+	for i, user := range userList.Users {
+		userResponses[i] = toAdminUserResponse(db.User{
+			UserID:          user.UserID,
+			Username:        user.Username,
+			Email:           user.Email,
+			Role:            user.Role,
+			EmailVerifiedAt: user.EmailVerifiedAt,
+			TotpEnabledAt:   user.TotpEnabledAt,
+			DisabledAt:      user.DisabledAt,
+			CreatedAt:       user.CreatedAt,
+		}, db.CountTodosRow{Total: user.TodoCount, Completed: user.CompletedTodoCount})
+	}
+
+	ctx.JSON(http.StatusOK, AdminUserListResponse{Users: userResponses, Total: userList.Total, Page: req.Page, PerPage: req.PerPage})
+}
+
+// @Summary Get a user
+// @Description Requires the admin role.
+// @Tags Admin
+// @Produce json
+// @Param id path string true "User ID"
+// @Security BearerAuth
+// @Success 200 {object} AdminUserResponse
+// @Failure 400 {object} gin.H "{"error": "Invalid request"}"
+// @Failure 403 {object} gin.H "{"error": "You do not have permission to access this resource"}"
+// @Failure 404 {object} gin.H "{"error": "Resource not found"}"
+// @Failure 500 {object} gin.H "{"error": "Internal server error"}"
+// @Router /admin/users/{id} [get]
+func (h *AdminHandler) GetUser(ctx *gin.Context) {
+	userID, ok := bindUserIDParam(ctx)
+	if !ok {
+		return
+	}
+
+	userDetail, err := h.AdminService.GetUser(ctx, userID)
+	if err != nil {
+		respondAdminServiceErr(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, toAdminUserResponse(userDetail.User, userDetail.TodoCounts))
+}
+
+// @Summary Disable a user
+// @Description The user can no longer log in and is signed out of every session. Requires the admin role.
+// @Tags Admin
+// @Produce json
+// @Param id path string true "User ID"
+// @Security BearerAuth
+// @Success 200 {object} gin.H "{"message": "User disabled"}"
+// @Failure 400 {object} gin.H "{"error": "Invalid request"} or {"error": "Admins cannot disable their own account"}"
+// @Failure 403 {object} gin.H "{"error": "You do not have permission to access this resource"}"
+// @Failure 404 {object} gin.H "{"error": "Resource not found"}"
+// @Failure 500 {object} gin.H "{"error": "Internal server error"}"
+// @Router /admin/users/{id}/disable [post]
+func (h *AdminHandler) DisableUser(ctx *gin.Context) {
+	adminIDUuid, err := utils.GetUIDFromCtxAndCreateRespUponErr(ctx)
+	if err != nil {
+		return
+	}
+
+	userID, ok := bindUserIDParam(ctx)
+	if !ok {
+		return
+	}
+
+	if err := h.AdminService.DisableUser(ctx, adminIDUuid, userID); err != nil {
+		respondAdminServiceErr(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "User disabled"})
+}
+
+// @Summary Enable a disabled user
+// @Description Requires the admin role.
+// @Tags Admin
+// @Produce json
+// @Param id path string true "User ID"
+// @Security BearerAuth
+// @Success 200 {object} gin.H "{"message": "User enabled"}"
+// @Failure 400 {object} gin.H "{"error": "Invalid request"}"
+// @Failure 403 {object} gin.H "{"error": "You do not have permission to access this resource"}"
+// @Failure 404 {object} gin.H "{"error": "Resource not found"}"
+// @Failure 500 {object} gin.H "{"error": "Internal server error"}"
+// @Router /admin/users/{id}/enable [post]
+func (h *AdminHandler) EnableUser(ctx *gin.Context) {
+	userID, ok := bindUserIDParam(ctx)
+	if !ok {
+		return
+	}
+
+	if err := h.AdminService.EnableUser(ctx, userID); err != nil {
+		respondAdminServiceErr(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "User enabled"})
+}
+
+// @Summary Force a password reset
+// @Description Clears the password of the user, signs out every session and emails a reset link. Requires the admin role.
+// @Tags Admin
+// @Produce json
+// @Param id path string true "User ID"
+// @Security BearerAuth
+// @Success 200 {object} gin.H "{"message": "Password reset email sent"}"
+// @Failure 400 {object} gin.H "{"error": "Invalid request"}"
+// @Failure 403 {object} gin.H "{"error": "You do not have permission to access this resource"}"
+// @Failure 404 {object} gin.H "{"error": "Resource not found"}"
+// @Failure 500 {object} gin.H "{"error": "Internal server error"}"
+// @Router /admin/users/{id}/password-reset [post]
+func (h *AdminHandler) ForcePasswordReset(ctx *gin.Context) {
+	userID, ok := bindUserIDParam(ctx)
+	if !ok {
+		return
+	}
+
+	if err := h.AdminService.ForcePasswordReset(ctx, userID); err != nil {
+		respondAdminServiceErr(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "Password reset email sent"})
+}
+
+func bindUserIDParam(ctx *gin.Context) (pgtype.UUID, bool) {
+	userID, err := utils.StringToUUID(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": utils.MsgInvalidReq})
+		return pgtype.UUID{}, false
+	}
+
+	return userID, true
+}
+
+func respondAdminServiceErr(ctx *gin.Context, err error) {
+	log.Println(err.Error())
+
+	if err == utils.ErrNoRowsMatchedSQLC {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": utils.MsgResourceNotFound})
+		return
+	}
+
+	if err == utils.ErrCannotModifySelf {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": utils.MsgCannotModifySelf})
+		return
+	}
+
+	ctx.JSON(http.StatusInternalServerError, gin.H{"error": utils.MsgInternalServerErr})
+}
+
+func toAdminUserResponse(user db.User, todoCounts db.CountTodosRow) AdminUserResponse {
+	resp := AdminUserResponse{
+		UserID:             utils.UUIDToString(user.UserID),
+		Username:           user.Username,
+		Email:              user.Email,
+		Role:               user.Role,
+		EmailVerified:      user.EmailVerifiedAt.Valid,
+		TwoFactorEnabled:   user.TotpEnabledAt.Valid,
+		Disabled:           user.DisabledAt.Valid,
+		CreatedAt:          user.CreatedAt.Time,
+		TodoCount:          todoCounts.Total,
+		CompletedTodoCount: todoCounts.Completed,
+	}
+	if user.DisabledAt.Valid {
+		resp.DisabledAt = &user.DisabledAt.Time
+	}
+	return resp
+}
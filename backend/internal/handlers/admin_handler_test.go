@@ -0,0 +1,415 @@
+package handlers_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"todo-app/internal/db"
+	"todo-app/internal/handlers"
+	"todo-app/internal/services"
+	mock_services "todo-app/internal/services/_mock"
+	"todo-app/internal/utils"
+	"todo-app/internal/utils/testutils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+type adminTestSetup struct {
+	ctrl             *gomock.Controller
+	mockAdminService *mock_services.MockIAdminService
+	adminHandler     *handlers.AdminHandler
+	router           *gin.Engine
+	recorder         *httptest.ResponseRecorder
+	context          *gin.Context
+}
+
+// The admin acting on the users
+const adminIDStr = "0f0e0d0c-0b0a-0908-0706-050403020100"
+
+var mockAdminUser = db.User{
+	UserID:          uIDUuid,
+	Username:        "testuser",
+	Email:           "test@example.com",
+	Role:            services.RoleUser,
+	EmailVerifiedAt: pgtype.Timestamptz{Time: mockTime, Valid: true},
+	DisabledAt:      pgtype.Timestamptz{Time: mockTime, Valid: true},
+	CreatedAt:       pgtype.Timestamptz{Time: mockTime, Valid: true},
+}
+
+func setupAdminTest(t *testing.T, setUserIDInCtx bool) *adminTestSetup {
+	ctrl := gomock.NewController(t)
+	mockAdminService := mock_services.NewMockIAdminService(ctrl)
+	adminHandler := handlers.NewAdminHandler(mockAdminService)
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	ctx, r := gin.CreateTestContext(w)
+
+	if setUserIDInCtx {
+		ctx.Set("userID", adminIDStr)
+		r.Use(func(c *gin.Context) {
+			c.Set("userID", adminIDStr)
+			c.Next()
+		})
+	}
+
+	return &adminTestSetup{
+		ctrl:             ctrl,
+		mockAdminService: mockAdminService,
+		adminHandler:     adminHandler,
+		router:           r,
+		recorder:         w,
+		context:          ctx,
+	}
+}
+
+func TestAdminHandler_ListUsers(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  want
+	}{
+		{
+			name:  "successful list users",
+			query: "?q=test&page=2&per_page=1",
+			want: want{
+				status:   http.StatusOK,
+				respFile: "testdata/list_users/200_resp.json.golden",
+			},
+		},
+		{
+			name:  "invalid query",
+			query: "?per_page=101",
+			want: want{
+				status:   http.StatusBadRequest,
+				respFile: "testdata/list_users/400_resp.json.golden",
+			},
+		},
+		{
+			name:  "internal server error",
+			query: "",
+			want: want{
+				status:   http.StatusInternalServerError,
+				respFile: "testdata/list_users/500_resp.json.golden",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setup := setupAdminTest(t, true)
+			defer setup.ctrl.Finish()
+
+			// ListUsers service won't be called when the query is invalid
+			if tt.want.status != http.StatusBadRequest {
+				setup.mockAdminService.EXPECT().ListUsers(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, req services.ListUsersRequest) (*services.UserList, error) {
+					switch tt.want.status {
+					case http.StatusOK:
+						assert.Equal(t, services.ListUsersRequest{Query: "test", Page: 2, PerPage: 1}, req)
+						return &services.UserList{
+							Users: []db.ListUsersRow{
+								{
+									UserID:             mockAdminUser.UserID,
+									Username:           mockAdminUser.Username,
+									Email:              mockAdminUser.Email,
+									Role:               mockAdminUser.Role,
+									EmailVerifiedAt:    mockAdminUser.EmailVerifiedAt,
+									DisabledAt:         mockAdminUser.DisabledAt,
+									CreatedAt:          mockAdminUser.CreatedAt,
+									TodoCount:          3,
+									CompletedTodoCount: 1,
+								},
+							},
+							Total: 2,
+						}, nil
+					case http.StatusInternalServerError:
+						return nil, errors.New("unexpected error")
+					}
+					return nil, errors.New("error from mock")
+				})
+			}
+
+			setup.context.Request = httptest.NewRequest(http.MethodGet, "/admin/users"+tt.query, nil)
+			setup.router.GET("/admin/users", setup.adminHandler.ListUsers)
+			setup.router.ServeHTTP(setup.recorder, setup.context.Request)
+
+			testutils.AssertResponse(t, setup.recorder.Result(), tt.want.status, testutils.LoadFile(t, tt.want.respFile))
+		})
+	}
+}
+
+func TestAdminHandler_GetUser(t *testing.T) {
+	tests := []struct {
+		name   string
+		userID string
+		want   want
+	}{
+		{
+			name:   "successful get user",
+			userID: uIDStr,
+			want: want{
+				status:   http.StatusOK,
+				respFile: "testdata/get_user/200_resp.json.golden",
+			},
+		},
+		{
+			name:   "invalid user ID",
+			userID: "abc",
+			want: want{
+				status:   http.StatusBadRequest,
+				respFile: "testdata/get_user/400_resp.json.golden",
+			},
+		},
+		{
+			name:   "user not found",
+			userID: uIDStr,
+			want: want{
+				status:   http.StatusNotFound,
+				respFile: "testdata/get_user/404_resp.json.golden",
+			},
+		},
+		{
+			name:   "internal server error",
+			userID: uIDStr,
+			want: want{
+				status:   http.StatusInternalServerError,
+				respFile: "testdata/get_user/500_resp.json.golden",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setup := setupAdminTest(t, true)
+			defer setup.ctrl.Finish()
+
+			// GetUser service won't be called when the user ID is invalid
+			if tt.want.status != http.StatusBadRequest {
+				setup.mockAdminService.EXPECT().GetUser(gomock.Any(), uIDUuid).DoAndReturn(func(ctx context.Context, userID pgtype.UUID) (*services.UserDetail, error) {
+					switch tt.want.status {
+					case http.StatusOK:
+						return &services.UserDetail{User: mockAdminUser, TodoCounts: db.CountTodosRow{Total: 3, Completed: 1}}, nil
+					case http.StatusNotFound:
+						return nil, utils.ErrNoRowsMatchedSQLC
+					case http.StatusInternalServerError:
+						return nil, errors.New("unexpected error")
+					}
+					return nil, errors.New("error from mock")
+				})
+			}
+
+			setup.context.Request = httptest.NewRequest(http.MethodGet, "/admin/users/"+tt.userID, nil)
+			setup.router.GET("/admin/users/:id", setup.adminHandler.GetUser)
+			setup.router.ServeHTTP(setup.recorder, setup.context.Request)
+
+			testutils.AssertResponse(t, setup.recorder.Result(), tt.want.status, testutils.LoadFile(t, tt.want.respFile))
+		})
+	}
+}
+
+func TestAdminHandler_DisableUser(t *testing.T) {
+	tests := []struct {
+		name           string
+		userID         string
+		want           want
+		setUserIDInCtx bool
+	}{
+		{
+			name:   "successful disable user",
+			userID: uIDStr,
+			want: want{
+				status:   http.StatusOK,
+				respFile: "testdata/disable_user/200_resp.json.golden",
+			},
+			setUserIDInCtx: true,
+		},
+		{
+			name:   "invalid user ID",
+			userID: "abc",
+			want: want{
+				status:   http.StatusBadRequest,
+				respFile: "testdata/disable_user/400_resp.json.golden",
+			},
+			setUserIDInCtx: true,
+		},
+		{
+			name:   "disable own account",
+			userID: adminIDStr,
+			want: want{
+				status:   http.StatusBadRequest,
+				respFile: "testdata/disable_user/400_self_resp.json.golden",
+			},
+			setUserIDInCtx: true,
+		},
+		{
+			name:   "failed to get userID from context",
+			userID: uIDStr,
+			want: want{
+				status:   http.StatusUnauthorized,
+				respFile: "testdata/disable_user/401_resp.json.golden",
+			},
+			setUserIDInCtx: false,
+		},
+		{
+			name:   "user not found",
+			userID: uIDStr,
+			want: want{
+				status:   http.StatusNotFound,
+				respFile: "testdata/disable_user/404_resp.json.golden",
+			},
+			setUserIDInCtx: true,
+		},
+		{
+			name:   "internal server error",
+			userID: uIDStr,
+			want: want{
+				status:   http.StatusInternalServerError,
+				respFile: "testdata/disable_user/500_resp.json.golden",
+			},
+			setUserIDInCtx: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setup := setupAdminTest(t, tt.setUserIDInCtx)
+			defer setup.ctrl.Finish()
+
+			// DisableUser service won't be called when userID is not in context or the user ID is invalid
+			if tt.setUserIDInCtx && tt.name != "invalid user ID" {
+				setup.mockAdminService.EXPECT().DisableUser(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, adminID, userID pgtype.UUID) error {
+					switch tt.want.status {
+					case http.StatusOK:
+						return nil
+					case http.StatusBadRequest:
+						return utils.ErrCannotModifySelf
+					case http.StatusNotFound:
+						return utils.ErrNoRowsMatchedSQLC
+					case http.StatusInternalServerError:
+						return errors.New("unexpected error")
+					}
+					return errors.New("error from mock")
+				})
+			}
+
+			setup.context.Request = httptest.NewRequest(http.MethodPost, "/admin/users/"+tt.userID+"/disable", nil)
+			setup.router.POST("/admin/users/:id/disable", setup.adminHandler.DisableUser)
+			setup.router.ServeHTTP(setup.recorder, setup.context.Request)
+
+			testutils.AssertResponse(t, setup.recorder.Result(), tt.want.status, testutils.LoadFile(t, tt.want.respFile))
+		})
+	}
+}
+
+func TestAdminHandler_EnableUser(t *testing.T) {
+	tests := []struct {
+		name string
+		want want
+	}{
+		{
+			name: "successful enable user",
+			want: want{
+				status:   http.StatusOK,
+				respFile: "testdata/enable_user/200_resp.json.golden",
+			},
+		},
+		{
+			name: "user not found",
+			want: want{
+				status:   http.StatusNotFound,
+				respFile: "testdata/enable_user/404_resp.json.golden",
+			},
+		},
+		{
+			name: "internal server error",
+			want: want{
+				status:   http.StatusInternalServerError,
+				respFile: "testdata/enable_user/500_resp.json.golden",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setup := setupAdminTest(t, true)
+			defer setup.ctrl.Finish()
+
+			setup.mockAdminService.EXPECT().EnableUser(gomock.Any(), uIDUuid).DoAndReturn(func(ctx context.Context, userID pgtype.UUID) error {
+				switch tt.want.status {
+				case http.StatusOK:
+					return nil
+				case http.StatusNotFound:
+					return utils.ErrNoRowsMatchedSQLC
+				case http.StatusInternalServerError:
+					return errors.New("unexpected error")
+				}
+				return errors.New("error from mock")
+			})
+
+			setup.context.Request = httptest.NewRequest(http.MethodPost, "/admin/users/"+uIDStr+"/enable", nil)
+			setup.router.POST("/admin/users/:id/enable", setup.adminHandler.EnableUser)
+			setup.router.ServeHTTP(setup.recorder, setup.context.Request)
+
+			testutils.AssertResponse(t, setup.recorder.Result(), tt.want.status, testutils.LoadFile(t, tt.want.respFile))
+		})
+	}
+}
+
+func TestAdminHandler_ForcePasswordReset(t *testing.T) {
+	tests := []struct {
+		name string
+		want want
+	}{
+		{
+			name: "successful force password reset",
+			want: want{
+				status:   http.StatusOK,
+				respFile: "testdata/force_password_reset/200_resp.json.golden",
+			},
+		},
+		{
+			name: "user not found",
+			want: want{
+				status:   http.StatusNotFound,
+				respFile: "testdata/force_password_reset/404_resp.json.golden",
+			},
+		},
+		{
+			name: "internal server error",
+			want: want{
+				status:   http.StatusInternalServerError,
+				respFile: "testdata/force_password_reset/500_resp.json.golden",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setup := setupAdminTest(t, true)
+			defer setup.ctrl.Finish()
+
+			setup.mockAdminService.EXPECT().ForcePasswordReset(gomock.Any(), uIDUuid).DoAndReturn(func(ctx context.Context, userID pgtype.UUID) error {
+				switch tt.want.status {
+				case http.StatusOK:
+					return nil
+				case http.StatusNotFound:
+					return utils.ErrNoRowsMatchedSQLC
+				case http.StatusInternalServerError:
+					return errors.New("unexpected error")
+				}
+				return errors.New("error from mock")
+			})
+
+			setup.context.Request = httptest.NewRequest(http.MethodPost, "/admin/users/"+uIDStr+"/password-reset", nil)
+			setup.router.POST("/admin/users/:id/password-reset", setup.adminHandler.ForcePasswordReset)
+			setup.router.ServeHTTP(setup.recorder, setup.context.Request)
+
+			testutils.AssertResponse(t, setup.recorder.Result(), tt.want.status, testutils.LoadFile(t, tt.want.respFile))
+		})
+	}
+}
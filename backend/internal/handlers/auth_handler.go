@@ -1,8 +1,12 @@
 package handlers
 
 import (
+	"errors"
 	"log"
+	"math"
 	"net/http"
+	"strconv"
+	"time"
 	"todo-app/internal/services"
 	"todo-app/internal/utils"
 
@@ -15,8 +19,15 @@ type AuthHandler struct {
 }
 
 type LoginResponse struct {
-	UserID      string `json:"user_id"`
-	AccessToken string `json:"access_token"`
+	UserID       string `json:"user_id"`
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+// Returned by login instead of LoginResponse when the user has enabled two-factor authentication
+type TwoFactorChallengeResponse struct {
+	TwoFactorRequired bool   `json:"two_factor_required"`
+	ChallengeToken    string `json:"challenge_token"`
 }
 
 func NewAuthHandler(authService services.IAuthService) *AuthHandler {
@@ -29,7 +40,7 @@ func NewAuthHandler(authService services.IAuthService) *AuthHandler {
 // @Produce json
 // @Param credential body services.RegisterRequest true "user credential"
 // @Success 200 {object} gin.H "{"message": "User registered"}"
-// @Failure 400 {object} gin.H "{"error": "Invalid request"}"
+// @Failure 400 {object} gin.H "{"error": "Invalid request"} or {"error": "Password does not meet the requirements", "violations": [{"code": "too_short", "message": "..."}]}"
 // @Failure 409 {object} gin.H "{"error": "User already registered"}"
 // @Failure 500 {object} gin.H "{"error": "The server encountered unexpected error"}"
 // @Router /register [post]
@@ -44,6 +55,12 @@ func (h *AuthHandler) Register(ctx *gin.Context) {
 	if err != nil {
 		log.Println(err.Error())
 
+		var policyErr *services.PasswordPolicyError
+		if errors.As(err, &policyErr) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": utils.MsgWeakPassword, "violations": policyErr.Violations})
+			return
+		}
+
 		// TODO: Consider more manageable error handling
 		if pgErr, ok := utils.AssertPgErr(err); ok {
 			if pgErr.Code == "23505" {
@@ -64,9 +81,11 @@ func (h *AuthHandler) Register(ctx *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param credential body services.LoginRequest true "user credential"
-// @Success 200 {object} LoginResponse
+// @Success 200 {object} LoginResponse "or TwoFactorChallengeResponse when two-factor authentication is enabled"
 // @Failure 400 {object} gin.H "{"error": "Invalid request"}"
 // @Failure 401 {object} gin.H "{"error": "Invalid email or password"}"
+// @Failure 429 {object} gin.H "{"error": "Too many failed login attempts, try again later"}"
+// @Header 429 {integer} Retry-After "seconds to wait before retrying"
 // @Failure 500 {object} gin.H "{"error": "Internal server error"}"
 // @Router /login [post]
 func (h *AuthHandler) Login(ctx *gin.Context) {
@@ -77,9 +96,21 @@ func (h *AuthHandler) Login(ctx *gin.Context) {
 	}
 
 	session := sessions.Default(ctx)
+
+	// A new session gets its ID only once it is persisted, but the issued tokens have to be bound to it
+	if session.ID() == "" {
+		session.Set("createdAt", time.Now().Unix())
+		if err := session.Save(); err != nil {
+			log.Println(err.Error())
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": utils.MsgInternalServerErr})
+			return
+		}
+	}
 	sessionID := session.ID()
 
-	userID, accessToken, err := h.AuthService.Login(ctx, req, sessionID)
+	client := services.ClientInfo{UserAgent: ctx.Request.UserAgent(), IP: ctx.ClientIP()}
+
+	userID, tokens, err := h.AuthService.Login(ctx, req, sessionID, client)
 	if err != nil {
 		log.Println(err.Error())
 
@@ -88,28 +119,170 @@ func (h *AuthHandler) Login(ctx *gin.Context) {
 			return
 		}
 
+		if err == utils.ErrAccountDisabled {
+			ctx.JSON(http.StatusForbidden, gin.H{"error": utils.MsgAccountDisabled})
+			return
+		}
+
+		if err == utils.ErrAccountDeleted {
+			ctx.JSON(http.StatusForbidden, gin.H{"error": utils.MsgAccountDeleted})
+			return
+		}
+
+		var twoFactorErr *services.TwoFactorRequiredError
+		if errors.As(err, &twoFactorErr) {
+			ctx.JSON(http.StatusOK, TwoFactorChallengeResponse{TwoFactorRequired: true, ChallengeToken: twoFactorErr.ChallengeToken})
+			return
+		}
+
+		var lockedErr *services.LoginLockedError
+		if errors.As(err, &lockedErr) {
+			ctx.Header("Retry-After", strconv.Itoa(int(math.Ceil(lockedErr.RetryAfter.Seconds()))))
+			ctx.JSON(http.StatusTooManyRequests, gin.H{"error": utils.MsgTooManyLoginAttempts})
+			return
+		}
+
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": utils.MsgInternalServerErr})
+		return
+	}
+
+	if err = setSessionUser(session, userID); err != nil {
+		log.Println(err.Error())
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": utils.MsgInternalServerErr})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, LoginResponse{UserID: userID, AccessToken: tokens.AccessToken, RefreshToken: tokens.RefreshToken})
+}
+
+// @Summary Complete a login with a two-factor authentication code
+// @Description The code can be either a code from the authenticator app or one of the recovery codes.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param code body services.TwoFactorLoginRequest true "challenge token returned by login and the code"
+// @Success 200 {object} LoginResponse
+// @Failure 400 {object} gin.H "{"error": "Invalid request"}"
+// @Failure 401 {object} gin.H "{"error": "Invalid or expired two-factor challenge"} or {"error": "Invalid two-factor authentication code"}"
+// @Failure 403 {object} gin.H "{"error": "This account has been disabled"}"
+// @Failure 429 {object} gin.H "{"error": "Too many failed login attempts, try again later"}"
+// @Header 429 {integer} Retry-After "seconds to wait before retrying"
+// @Failure 500 {object} gin.H "{"error": "Internal server error"}"
+// @Router /login/2fa [post]
+func (h *AuthHandler) LoginTwoFactor(ctx *gin.Context) {
+	var req services.TwoFactorLoginRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": utils.MsgInvalidReq})
+		return
+	}
+
+	// The challenge token is bound to the session the login started in
+	session := sessions.Default(ctx)
+	client := services.ClientInfo{UserAgent: ctx.Request.UserAgent(), IP: ctx.ClientIP()}
+
+	userID, tokens, err := h.AuthService.LoginTwoFactor(ctx, req, session.ID(), client)
+	if err != nil {
+		log.Println(err.Error())
+
+		if err == utils.ErrInvalidChallengeToken {
+			ctx.JSON(http.StatusUnauthorized, gin.H{"error": utils.MsgInvalidChallengeToken})
+			return
+		}
+
+		if err == utils.ErrInvalidTwoFactorCode {
+			ctx.JSON(http.StatusUnauthorized, gin.H{"error": utils.MsgInvalidTwoFactorCode})
+			return
+		}
+
+		if err == utils.ErrAccountDisabled {
+			ctx.JSON(http.StatusForbidden, gin.H{"error": utils.MsgAccountDisabled})
+			return
+		}
+
+		if err == utils.ErrAccountDeleted {
+			ctx.JSON(http.StatusForbidden, gin.H{"error": utils.MsgAccountDeleted})
+			return
+		}
+
+		var lockedErr *services.LoginLockedError
+		if errors.As(err, &lockedErr) {
+			ctx.Header("Retry-After", strconv.Itoa(int(math.Ceil(lockedErr.RetryAfter.Seconds()))))
+			ctx.JSON(http.StatusTooManyRequests, gin.H{"error": utils.MsgTooManyLoginAttempts})
+			return
+		}
+
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": utils.MsgInternalServerErr})
 		return
 	}
 
+	if err = setSessionUser(session, userID); err != nil {
+		log.Println(err.Error())
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": utils.MsgInternalServerErr})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, LoginResponse{UserID: userID, AccessToken: tokens.AccessToken, RefreshToken: tokens.RefreshToken})
+}
+
+func setSessionUser(session sessions.Session, userID string) error {
 	if session.Get("userID") != nil {
 		session.Clear()
 		// session.Options(sessions.Options{MaxAge: -1})
-		if err = session.Save(); err != nil {
-			log.Println(err.Error())
-			ctx.JSON(http.StatusInternalServerError, gin.H{"error": utils.MsgInternalServerErr})
-			return
+		if err := session.Save(); err != nil {
+			return err
 		}
 	}
 
 	session.Set("userID", userID)
-	if err = session.Save(); err != nil {
+	return session.Save()
+}
+
+// @Summary Refresh the access token
+// @Description The refresh token is rotated on every use. Reusing an old refresh token revokes the whole session.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param token body services.RefreshTokenRequest true "refresh token"
+// @Success 200 {object} LoginResponse
+// @Failure 400 {object} gin.H "{"error": "Invalid request"}"
+// @Failure 401 {object} gin.H "{"error": "Invalid or expired refresh token"}"
+// @Failure 500 {object} gin.H "{"error": "Internal server error"}"
+// @Router /token/refresh [post]
+func (h *AuthHandler) RefreshToken(ctx *gin.Context) {
+	var req services.RefreshTokenRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": utils.MsgInvalidReq})
+		return
+	}
+
+	session := sessions.Default(ctx)
+
+	userID, tokens, err := h.AuthService.RefreshToken(ctx, req, session.ID())
+	if err != nil {
 		log.Println(err.Error())
+
+		if err == utils.ErrRefreshTokenReused {
+			// The session has already been revoked on the server side; let the client drop its cookie as well
+			session.Clear()
+			session.Options(sessions.Options{MaxAge: -1})
+			if err = session.Save(); err != nil {
+				log.Println(err.Error())
+			}
+
+			ctx.JSON(http.StatusUnauthorized, gin.H{"error": utils.MsgRefreshTokenReused})
+			return
+		}
+
+		if err == utils.ErrInvalidRefreshToken {
+			ctx.JSON(http.StatusUnauthorized, gin.H{"error": utils.MsgInvalidRefreshToken})
+			return
+		}
+
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": utils.MsgInternalServerErr})
 		return
 	}
 
-	ctx.JSON(http.StatusOK, LoginResponse{UserID: userID, AccessToken: accessToken})
+	ctx.JSON(http.StatusOK, LoginResponse{UserID: userID, AccessToken: tokens.AccessToken, RefreshToken: tokens.RefreshToken})
 }
 
 // @Summary Logout a user
@@ -122,6 +295,12 @@ func (h *AuthHandler) Login(ctx *gin.Context) {
 func (h *AuthHandler) Logout(ctx *gin.Context) {
 	session := sessions.Default(ctx)
 
+	if err := h.AuthService.Logout(ctx, ctx.GetString("userID"), session.ID()); err != nil {
+		log.Println(err.Error())
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": utils.MsgInternalServerErr})
+		return
+	}
+
 	session.Clear()
 	session.Options(sessions.Options{MaxAge: -1})
 	if err := session.Save(); err != nil {
@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 	"todo-app/internal/db"
 	"todo-app/internal/handlers"
 	middlewares_mock "todo-app/internal/middlewares/_mock"
@@ -19,6 +20,7 @@ import (
 	"github.com/gin-contrib/sessions/memstore"
 	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
 )
 
@@ -74,6 +76,8 @@ func checkSessionHandler(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"userID": userID})
 }
 
+var mockTokenPair = services.TokenPair{AccessToken: "access-token-123", RefreshToken: "refresh-token-123"}
+
 type want struct {
 	status   int
 	respFile string
@@ -118,6 +122,15 @@ func TestAuthHandler_Register(t *testing.T) {
 			},
 			useMockSession: false,
 		},
+		{
+			name:    "weak password",
+			reqFile: "testdata/register/400_weak_password_req.json.golden",
+			want: want{
+				status:   http.StatusBadRequest,
+				respFile: "testdata/register/400_weak_password_resp.json.golden",
+			},
+			useMockSession: false,
+		},
 		{
 			name:    "user already registered",
 			reqFile: "testdata/register/409_req.json.golden",
@@ -149,6 +162,11 @@ func TestAuthHandler_Register(t *testing.T) {
 					switch tt.want.status {
 					case http.StatusCreated:
 						return &db.User{}, nil
+					case http.StatusBadRequest:
+						return nil, &services.PasswordPolicyError{Violations: []services.PasswordViolation{
+							{Code: services.PasswordViolationTooShort, Message: "Password must be at least 8 characters long"},
+							{Code: services.PasswordViolationCharClass, Message: "Password must contain at least one digit character"},
+						}}
 					case http.StatusConflict:
 						return nil, &pgconn.PgError{Code: "23505"}
 					case http.StatusInternalServerError:
@@ -186,6 +204,16 @@ func TestAuthHandler_Login(t *testing.T) {
 			checkSessionWant: checkSessionWants.exist,
 			useMockSession:   false,
 		},
+		{
+			name:    "two-factor authentication required",
+			reqFile: "testdata/login/200_req.json.golden",
+			want: want{
+				status:   http.StatusOK,
+				respFile: "testdata/login/200_two_factor_resp.json.golden",
+			},
+			checkSessionWant: checkSessionWants.notExist,
+			useMockSession:   false,
+		},
 		{
 			name:    "invalid request body",
 			reqFile: "testdata/login/400_req.json.golden",
@@ -206,6 +234,36 @@ func TestAuthHandler_Login(t *testing.T) {
 			checkSessionWant: checkSessionWants.notExist,
 			useMockSession:   false,
 		},
+		{
+			name:    "account disabled",
+			reqFile: "testdata/login/200_req.json.golden",
+			want: want{
+				status:   http.StatusForbidden,
+				respFile: "testdata/login/403_resp.json.golden",
+			},
+			checkSessionWant: checkSessionWants.notExist,
+			useMockSession:   false,
+		},
+		{
+			name:    "account deleted",
+			reqFile: "testdata/login/200_req.json.golden",
+			want: want{
+				status:   http.StatusForbidden,
+				respFile: "testdata/login/403_deleted_resp.json.golden",
+			},
+			checkSessionWant: checkSessionWants.notExist,
+			useMockSession:   false,
+		},
+		{
+			name:    "too many failed login attempts",
+			reqFile: "testdata/login/429_req.json.golden",
+			want: want{
+				status:   http.StatusTooManyRequests,
+				respFile: "testdata/login/429_resp.json.golden",
+			},
+			checkSessionWant: checkSessionWants.notExist,
+			useMockSession:   false,
+		},
 		{
 			name:    "internal server error",
 			reqFile: "testdata/login/500_req.json.golden",
@@ -235,19 +293,29 @@ func TestAuthHandler_Login(t *testing.T) {
 
 			// Login service won't be called when request body is invalid
 			if tt.name != "invalid request body" {
-				setup.mockAuthService.EXPECT().Login(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, req services.LoginRequest, sessionID string) (string, string, error) {
+				setup.mockAuthService.EXPECT().Login(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, req services.LoginRequest, sessionID string, client services.ClientInfo) (string, *services.TokenPair, error) {
 					switch tt.want.status {
 					case http.StatusOK:
-						return "user-id-123", "access-token-123", nil
+						if tt.name == "two-factor authentication required" {
+							return "", nil, &services.TwoFactorRequiredError{ChallengeToken: "challenge-token-123"}
+						}
+						return "user-id-123", &mockTokenPair, nil
 					case http.StatusUnauthorized:
-						return "", "", utils.ErrInvalidEmailOrPswd
+						return "", nil, utils.ErrInvalidEmailOrPswd
+					case http.StatusForbidden:
+						if tt.name == "account deleted" {
+							return "", nil, utils.ErrAccountDeleted
+						}
+						return "", nil, utils.ErrAccountDisabled
+					case http.StatusTooManyRequests:
+						return "", nil, &services.LoginLockedError{RetryAfter: 29500 * time.Millisecond}
 					case http.StatusInternalServerError:
 						if tt.name == "failed to save session" {
-							return "user-id-123", "access-token-123", nil
+							return "user-id-123", &mockTokenPair, nil
 						}
-						return "", "", errors.New("unexpected error")
+						return "", nil, errors.New("unexpected error")
 					}
-					return "", "", errors.New("error from mock")
+					return "", nil, errors.New("error from mock")
 				})
 			}
 
@@ -258,6 +326,10 @@ func TestAuthHandler_Login(t *testing.T) {
 
 			testutils.AssertResponse(t, setup.recorder.Result(), tt.want.status, testutils.LoadFile(t, tt.want.respFile))
 
+			if tt.want.status == http.StatusTooManyRequests {
+				assert.Equal(t, "30", setup.recorder.Header().Get("Retry-After"))
+			}
+
 			// Verify session
 			// Simulate the check-session request with the session cookie
 			cookies := setup.recorder.Result().Cookies()
@@ -275,6 +347,129 @@ func TestAuthHandler_Login(t *testing.T) {
 	}
 }
 
+func TestAuthHandler_LoginTwoFactor(t *testing.T) {
+	tests := []struct {
+		name             string
+		reqFile          string
+		want             want
+		checkSessionWant want
+	}{
+		{
+			name:    "successful login",
+			reqFile: "testdata/login_2fa/200_req.json.golden",
+			want: want{
+				status:   http.StatusOK,
+				respFile: "testdata/login_2fa/200_resp.json.golden",
+			},
+			checkSessionWant: checkSessionWants.exist,
+		},
+		{
+			name:    "invalid request body",
+			reqFile: "testdata/login_2fa/400_req.json.golden",
+			want: want{
+				status:   http.StatusBadRequest,
+				respFile: "testdata/login_2fa/400_resp.json.golden",
+			},
+			checkSessionWant: checkSessionWants.notExist,
+		},
+		{
+			name:    "invalid challenge token",
+			reqFile: "testdata/login_2fa/200_req.json.golden",
+			want: want{
+				status:   http.StatusUnauthorized,
+				respFile: "testdata/login_2fa/401_invalid_challenge_resp.json.golden",
+			},
+			checkSessionWant: checkSessionWants.notExist,
+		},
+		{
+			name:    "invalid code",
+			reqFile: "testdata/login_2fa/200_req.json.golden",
+			want: want{
+				status:   http.StatusUnauthorized,
+				respFile: "testdata/login_2fa/401_invalid_code_resp.json.golden",
+			},
+			checkSessionWant: checkSessionWants.notExist,
+		},
+		{
+			name:    "account disabled",
+			reqFile: "testdata/login_2fa/200_req.json.golden",
+			want: want{
+				status:   http.StatusForbidden,
+				respFile: "testdata/login_2fa/403_resp.json.golden",
+			},
+			checkSessionWant: checkSessionWants.notExist,
+		},
+		{
+			name:    "too many failed login attempts",
+			reqFile: "testdata/login_2fa/200_req.json.golden",
+			want: want{
+				status:   http.StatusTooManyRequests,
+				respFile: "testdata/login_2fa/429_resp.json.golden",
+			},
+			checkSessionWant: checkSessionWants.notExist,
+		},
+		{
+			name:    "internal server error",
+			reqFile: "testdata/login_2fa/200_req.json.golden",
+			want: want{
+				status:   http.StatusInternalServerError,
+				respFile: "testdata/login_2fa/500_resp.json.golden",
+			},
+			checkSessionWant: checkSessionWants.notExist,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setup := setupAuthTest(t, false)
+			defer setup.ctrl.Finish()
+
+			// LoginTwoFactor service won't be called when request body is invalid
+			if tt.name != "invalid request body" {
+				setup.mockAuthService.EXPECT().LoginTwoFactor(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, req services.TwoFactorLoginRequest, sessionID string, client services.ClientInfo) (string, *services.TokenPair, error) {
+					switch tt.name {
+					case "successful login":
+						return "user-id-123", &mockTokenPair, nil
+					case "invalid challenge token":
+						return "", nil, utils.ErrInvalidChallengeToken
+					case "invalid code":
+						return "", nil, utils.ErrInvalidTwoFactorCode
+					case "account disabled":
+						return "", nil, utils.ErrAccountDisabled
+					case "too many failed login attempts":
+						return "", nil, &services.LoginLockedError{RetryAfter: 29500 * time.Millisecond}
+					}
+					return "", nil, errors.New("unexpected error")
+				})
+			}
+
+			setup.context.Request = httptest.NewRequest(http.MethodPost, "/login/2fa", bytes.NewReader(testutils.LoadFile(t, tt.reqFile)))
+			setup.context.Request.Header.Set("Content-Type", "application/json")
+			setup.router.POST("/login/2fa", setup.authHandler.LoginTwoFactor)
+			setup.router.ServeHTTP(setup.recorder, setup.context.Request)
+
+			testutils.AssertResponse(t, setup.recorder.Result(), tt.want.status, testutils.LoadFile(t, tt.want.respFile))
+
+			if tt.want.status == http.StatusTooManyRequests {
+				assert.Equal(t, "30", setup.recorder.Header().Get("Retry-After"))
+			}
+
+			// Verify session
+			cookies := setup.recorder.Result().Cookies()
+			setup.recorder = httptest.NewRecorder()
+			setup.context.Request = httptest.NewRequest(http.MethodGet, "/check-session", nil)
+			for _, cookie := range cookies {
+				setup.context.Request.AddCookie(cookie)
+			}
+
+			setup.router.GET("/check-session", checkSessionHandler)
+			setup.router.ServeHTTP(setup.recorder, setup.context.Request)
+
+			testutils.AssertResponse(t, setup.recorder.Result(), tt.checkSessionWant.status, []byte(tt.checkSessionWant.respFile))
+		})
+	}
+}
+
 func TestAuthHandler_Logout(t *testing.T) {
 	tests := []struct {
 		name             string
@@ -307,6 +502,8 @@ func TestAuthHandler_Logout(t *testing.T) {
 			setup := setupAuthTest(t, tt.useMockSession)
 			defer setup.ctrl.Finish()
 
+			setup.mockAuthService.EXPECT().Logout(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+
 			setup.context.Request = httptest.NewRequest(http.MethodPost, "/logout", nil)
 			setup.context.Request.Header.Set("Content-Type", "application/json")
 			setup.router.POST("/logout", setup.authHandler.Logout)
@@ -329,3 +526,90 @@ func TestAuthHandler_Logout(t *testing.T) {
 		})
 	}
 }
+
+func TestAuthHandler_RefreshToken(t *testing.T) {
+	tests := []struct {
+		name           string
+		reqFile        string
+		want           want
+		useMockSession bool
+	}{
+		{
+			name:    "successful refresh",
+			reqFile: "testdata/refresh_token/200_req.json.golden",
+			want: want{
+				status:   http.StatusOK,
+				respFile: "testdata/refresh_token/200_resp.json.golden",
+			},
+			useMockSession: false,
+		},
+		{
+			name:    "invalid request body",
+			reqFile: "testdata/refresh_token/400_req.json.golden",
+			want: want{
+				status:   http.StatusBadRequest,
+				respFile: "testdata/refresh_token/400_resp.json.golden",
+			},
+			useMockSession: false,
+		},
+		{
+			name:    "invalid refresh token",
+			reqFile: "testdata/refresh_token/401_req.json.golden",
+			want: want{
+				status:   http.StatusUnauthorized,
+				respFile: "testdata/refresh_token/401_resp.json.golden",
+			},
+			useMockSession: false,
+		},
+		{
+			name:    "reused refresh token",
+			reqFile: "testdata/refresh_token/401_req.json.golden",
+			want: want{
+				status:   http.StatusUnauthorized,
+				respFile: "testdata/refresh_token/401_resp_reused.json.golden",
+			},
+			useMockSession: false,
+		},
+		{
+			name:    "internal server error",
+			reqFile: "testdata/refresh_token/500_req.json.golden",
+			want: want{
+				status:   http.StatusInternalServerError,
+				respFile: "testdata/refresh_token/500_resp.json.golden",
+			},
+			useMockSession: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setup := setupAuthTest(t, tt.useMockSession)
+			defer setup.ctrl.Finish()
+
+			// RefreshToken service won't be called when request body is invalid
+			if tt.name != "invalid request body" {
+				setup.mockAuthService.EXPECT().RefreshToken(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, req services.RefreshTokenRequest, sessionID string) (string, *services.TokenPair, error) {
+					switch tt.want.status {
+					case http.StatusOK:
+						return "user-id-123", &services.TokenPair{AccessToken: "access-token-456", RefreshToken: "refresh-token-456"}, nil
+					case http.StatusUnauthorized:
+						if tt.name == "reused refresh token" {
+							return "", nil, utils.ErrRefreshTokenReused
+						}
+						return "", nil, utils.ErrInvalidRefreshToken
+					case http.StatusInternalServerError:
+						return "", nil, errors.New("unexpected error")
+					}
+					return "", nil, errors.New("error from mock")
+				})
+			}
+
+			setup.context.Request = httptest.NewRequest(http.MethodPost, "/token/refresh", bytes.NewReader(testutils.LoadFile(t, tt.reqFile)))
+			setup.context.Request.Header.Set("Content-Type", "application/json")
+			setup.router.POST("/token/refresh", setup.authHandler.RefreshToken)
+			setup.router.ServeHTTP(setup.recorder, setup.context.Request)
+
+			testutils.AssertResponse(t, setup.recorder.Result(), tt.want.status, testutils.LoadFile(t, tt.want.respFile))
+		})
+	}
+}
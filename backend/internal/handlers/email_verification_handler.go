@@ -0,0 +1,165 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"todo-app/internal/services"
+	"todo-app/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type EmailVerificationHandler struct {
+	EmailVerificationService services.IEmailVerificationService
+}
+
+func NewEmailVerificationHandler(emailVerificationService services.IEmailVerificationService) *EmailVerificationHandler {
+	return &EmailVerificationHandler{EmailVerificationService: emailVerificationService}
+}
+
+// @Summary Verify an email address with the token from the verification email
+// @Tags Auth
+// @Produce json
+// @Param token query string true "verification token"
+// @Success 200 {object} gin.H "{"message": "Email verified"}"
+// @Failure 400 {object} gin.H "{"error": "Invalid or expired verification token"}"
+// @Failure 500 {object} gin.H "{"error": "Internal server error"}"
+// @Router /verify-email [get]
+func (h *EmailVerificationHandler) VerifyEmail(ctx *gin.Context) {
+	token := ctx.Query("token")
+	if token == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": utils.MsgInvalidVerificationToken})
+		return
+	}
+
+	if err := h.EmailVerificationService.VerifyEmail(ctx, token); err != nil {
+		log.Println(err.Error())
+
+		if err == utils.ErrInvalidVerificationToken {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": utils.MsgInvalidVerificationToken})
+			return
+		}
+
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": utils.MsgInternalServerErr})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "Email verified"})
+}
+
+// @Summary Send the verification email again
+// @Tags User
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} gin.H "{"message": "Verification email sent"}"
+// @Failure 409 {object} gin.H "{"error": "Email address is already verified"}"
+// @Failure 500 {object} gin.H "{"error": "Internal server error"}"
+// @Router /me/verify-email/resend [post]
+func (h *EmailVerificationHandler) ResendMyVerification(ctx *gin.Context) {
+	userIDUuid, err := utils.GetUIDFromCtxAndCreateRespUponErr(ctx)
+	if err != nil {
+		return
+	}
+
+	if err := h.EmailVerificationService.ResendVerification(ctx, userIDUuid); err != nil {
+		log.Println(err.Error())
+
+		if err == utils.ErrEmailAlreadyVerified {
+			ctx.JSON(http.StatusConflict, gin.H{"error": utils.MsgEmailAlreadyVerified})
+			return
+		}
+
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": utils.MsgInternalServerErr})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "Verification email sent"})
+}
+
+// @Summary Change the email address
+// @Description Sends a confirmation link to the new email address. The email address is changed once the link is opened.
+// @Tags User
+// @Accept json
+// @Produce json
+// @Param request body services.ChangeEmailRequest true "new email address and current password"
+// @Security BearerAuth
+// @Success 202 {object} gin.H "{"message": "Confirmation email sent"}"
+// @Failure 400 {object} gin.H "{"error": "Invalid request"} or {"error": "Current password is incorrect"} or {"error": "New email address is the same as the current one"}"
+// @Failure 409 {object} gin.H "{"error": "Email address is already in use"}"
+// @Failure 500 {object} gin.H "{"error": "Internal server error"}"
+// @Router /me/email [put]
+func (h *EmailVerificationHandler) ChangeMyEmail(ctx *gin.Context) {
+	userIDUuid, err := utils.GetUIDFromCtxAndCreateRespUponErr(ctx)
+	if err != nil {
+		return
+	}
+
+	var req services.ChangeEmailRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": utils.MsgInvalidReq})
+		return
+	}
+
+	if err := h.EmailVerificationService.RequestEmailChange(ctx, userIDUuid, req); err != nil {
+		log.Println(err.Error())
+
+		if err == utils.ErrInvalidCurrentPassword {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": utils.MsgInvalidCurrentPassword})
+			return
+		}
+
+		if err == utils.ErrSameEmail {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": utils.MsgSameEmail})
+			return
+		}
+
+		if err == utils.ErrEmailAlreadyInUse {
+			ctx.JSON(http.StatusConflict, gin.H{"error": utils.MsgEmailAlreadyInUse})
+			return
+		}
+
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": utils.MsgInternalServerErr})
+		return
+	}
+
+	ctx.JSON(http.StatusAccepted, gin.H{"message": "Confirmation email sent"})
+}
+
+// @Summary Confirm an email change with the token from the confirmation email
+// @Tags User
+// @Produce json
+// @Param token query string true "email change token"
+// @Success 200 {object} gin.H "{"message": "Email changed"}"
+// @Failure 400 {object} gin.H "{"error": "Invalid or expired email change token"}"
+// @Failure 409 {object} gin.H "{"error": "Email address is already in use"}"
+// @Failure 500 {object} gin.H "{"error": "Internal server error"}"
+// @Router /email-change/confirm [get]
+func (h *EmailVerificationHandler) ConfirmEmailChange(ctx *gin.Context) {
+	token := ctx.Query("token")
+	if token == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": utils.MsgInvalidEmailChangeToken})
+		return
+	}
+
+	if err := h.EmailVerificationService.ConfirmEmailChange(ctx, token); err != nil {
+		log.Println(err.Error())
+
+		if err == utils.ErrInvalidEmailChangeToken {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": utils.MsgInvalidEmailChangeToken})
+			return
+		}
+
+		// The new email may have been taken by someone else since the change was requested
+		if pgErr, ok := utils.AssertPgErr(err); ok {
+			if pgErr.Code == "23505" {
+				ctx.JSON(http.StatusConflict, gin.H{"error": utils.MsgEmailAlreadyInUse})
+				return
+			}
+		}
+
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": utils.MsgInternalServerErr})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "Email changed"})
+}
@@ -0,0 +1,370 @@
+package handlers_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"todo-app/internal/handlers"
+	"todo-app/internal/services"
+	mock_services "todo-app/internal/services/_mock"
+	"todo-app/internal/utils"
+	"todo-app/internal/utils/testutils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/mock/gomock"
+)
+
+type emailVerificationTestSetup struct {
+	ctrl                         *gomock.Controller
+	mockEmailVerificationService *mock_services.MockIEmailVerificationService
+	emailVerificationHandler     *handlers.EmailVerificationHandler
+	router                       *gin.Engine
+	recorder                     *httptest.ResponseRecorder
+	context                      *gin.Context
+}
+
+func setupEmailVerificationTest(t *testing.T, setUserIDInCtx bool) *emailVerificationTestSetup {
+	ctrl := gomock.NewController(t)
+	mockEmailVerificationService := mock_services.NewMockIEmailVerificationService(ctrl)
+	emailVerificationHandler := handlers.NewEmailVerificationHandler(mockEmailVerificationService)
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	ctx, r := gin.CreateTestContext(w)
+
+	if setUserIDInCtx {
+		ctx.Set("userID", uIDStr)
+		r.Use(func(c *gin.Context) {
+			c.Set("userID", uIDStr)
+			c.Next()
+		})
+	}
+
+	return &emailVerificationTestSetup{
+		ctrl:                         ctrl,
+		mockEmailVerificationService: mockEmailVerificationService,
+		emailVerificationHandler:     emailVerificationHandler,
+		router:                       r,
+		recorder:                     w,
+		context:                      ctx,
+	}
+}
+
+func TestEmailVerificationHandler_VerifyEmail(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  want
+	}{
+		{
+			name:  "successful verification",
+			query: "?token=verification-token-123",
+			want: want{
+				status:   http.StatusOK,
+				respFile: "testdata/verify_email/200_resp.json.golden",
+			},
+		},
+		{
+			name:  "missing token",
+			query: "",
+			want: want{
+				status:   http.StatusBadRequest,
+				respFile: "testdata/verify_email/400_resp.json.golden",
+			},
+		},
+		{
+			name:  "invalid token",
+			query: "?token=invalid-token",
+			want: want{
+				status:   http.StatusBadRequest,
+				respFile: "testdata/verify_email/400_resp.json.golden",
+			},
+		},
+		{
+			name:  "internal server error",
+			query: "?token=verification-token-123",
+			want: want{
+				status:   http.StatusInternalServerError,
+				respFile: "testdata/verify_email/500_resp.json.golden",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setup := setupEmailVerificationTest(t, false)
+			defer setup.ctrl.Finish()
+
+			// VerifyEmail service won't be called when token is missing
+			if tt.query != "" {
+				setup.mockEmailVerificationService.EXPECT().VerifyEmail(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, token string) error {
+					switch tt.want.status {
+					case http.StatusOK:
+						return nil
+					case http.StatusBadRequest:
+						return utils.ErrInvalidVerificationToken
+					}
+					return errors.New("unexpected error")
+				})
+			}
+
+			setup.context.Request = httptest.NewRequest(http.MethodGet, "/verify-email"+tt.query, nil)
+			setup.router.GET("/verify-email", setup.emailVerificationHandler.VerifyEmail)
+			setup.router.ServeHTTP(setup.recorder, setup.context.Request)
+
+			testutils.AssertResponse(t, setup.recorder.Result(), tt.want.status, testutils.LoadFile(t, tt.want.respFile))
+		})
+	}
+}
+
+func TestEmailVerificationHandler_ResendMyVerification(t *testing.T) {
+	tests := []struct {
+		name           string
+		want           want
+		setUserIDInCtx bool
+	}{
+		{
+			name: "successful resend",
+			want: want{
+				status:   http.StatusOK,
+				respFile: "testdata/resend_my_verification/200_resp.json.golden",
+			},
+			setUserIDInCtx: true,
+		},
+		{
+			name: "failed to get userID from context",
+			want: want{
+				status:   http.StatusUnauthorized,
+				respFile: "testdata/resend_my_verification/401_resp.json.golden",
+			},
+			setUserIDInCtx: false,
+		},
+		{
+			name: "already verified",
+			want: want{
+				status:   http.StatusConflict,
+				respFile: "testdata/resend_my_verification/409_resp.json.golden",
+			},
+			setUserIDInCtx: true,
+		},
+		{
+			name: "internal server error",
+			want: want{
+				status:   http.StatusInternalServerError,
+				respFile: "testdata/resend_my_verification/500_resp.json.golden",
+			},
+			setUserIDInCtx: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setup := setupEmailVerificationTest(t, tt.setUserIDInCtx)
+			defer setup.ctrl.Finish()
+
+			if tt.setUserIDInCtx {
+				setup.mockEmailVerificationService.EXPECT().ResendVerification(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, userID pgtype.UUID) error {
+					switch tt.want.status {
+					case http.StatusOK:
+						return nil
+					case http.StatusConflict:
+						return utils.ErrEmailAlreadyVerified
+					}
+					return errors.New("unexpected error")
+				})
+			}
+
+			setup.context.Request = httptest.NewRequest(http.MethodPost, "/me/verify-email/resend", nil)
+			setup.router.POST("/me/verify-email/resend", setup.emailVerificationHandler.ResendMyVerification)
+			setup.router.ServeHTTP(setup.recorder, setup.context.Request)
+
+			testutils.AssertResponse(t, setup.recorder.Result(), tt.want.status, testutils.LoadFile(t, tt.want.respFile))
+		})
+	}
+}
+
+func TestEmailVerificationHandler_ChangeMyEmail(t *testing.T) {
+	tests := []struct {
+		name           string
+		reqFile        string
+		want           want
+		setUserIDInCtx bool
+	}{
+		{
+			name:    "successful change email request",
+			reqFile: "testdata/change_my_email/202_req.json.golden",
+			want: want{
+				status:   http.StatusAccepted,
+				respFile: "testdata/change_my_email/202_resp.json.golden",
+			},
+			setUserIDInCtx: true,
+		},
+		{
+			name:    "invalid request body",
+			reqFile: "testdata/change_my_email/400_req.json.golden",
+			want: want{
+				status:   http.StatusBadRequest,
+				respFile: "testdata/change_my_email/400_resp.json.golden",
+			},
+			setUserIDInCtx: true,
+		},
+		{
+			name:    "wrong current password",
+			reqFile: "testdata/change_my_email/202_req.json.golden",
+			want: want{
+				status:   http.StatusBadRequest,
+				respFile: "testdata/change_my_email/400_wrong_current_password_resp.json.golden",
+			},
+			setUserIDInCtx: true,
+		},
+		{
+			name:    "same email",
+			reqFile: "testdata/change_my_email/202_req.json.golden",
+			want: want{
+				status:   http.StatusBadRequest,
+				respFile: "testdata/change_my_email/400_same_email_resp.json.golden",
+			},
+			setUserIDInCtx: true,
+		},
+		{
+			name:    "failed to get userID from context",
+			reqFile: "testdata/change_my_email/202_req.json.golden",
+			want: want{
+				status:   http.StatusUnauthorized,
+				respFile: "testdata/change_my_email/401_resp.json.golden",
+			},
+			setUserIDInCtx: false,
+		},
+		{
+			name:    "email already in use",
+			reqFile: "testdata/change_my_email/202_req.json.golden",
+			want: want{
+				status:   http.StatusConflict,
+				respFile: "testdata/change_my_email/409_resp.json.golden",
+			},
+			setUserIDInCtx: true,
+		},
+		{
+			name:    "internal server error",
+			reqFile: "testdata/change_my_email/202_req.json.golden",
+			want: want{
+				status:   http.StatusInternalServerError,
+				respFile: "testdata/change_my_email/500_resp.json.golden",
+			},
+			setUserIDInCtx: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setup := setupEmailVerificationTest(t, tt.setUserIDInCtx)
+			defer setup.ctrl.Finish()
+
+			// RequestEmailChange service won't be called when request body is invalid or userID is missing
+			if tt.name != "invalid request body" && tt.setUserIDInCtx {
+				setup.mockEmailVerificationService.EXPECT().RequestEmailChange(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, userID pgtype.UUID, req services.ChangeEmailRequest) error {
+					switch tt.name {
+					case "successful change email request":
+						return nil
+					case "wrong current password":
+						return utils.ErrInvalidCurrentPassword
+					case "same email":
+						return utils.ErrSameEmail
+					case "email already in use":
+						return utils.ErrEmailAlreadyInUse
+					}
+					return errors.New("unexpected error")
+				})
+			}
+
+			setup.context.Request = httptest.NewRequest(http.MethodPut, "/me/email", bytes.NewReader(testutils.LoadFile(t, tt.reqFile)))
+			setup.context.Request.Header.Set("Content-Type", "application/json")
+			setup.router.PUT("/me/email", setup.emailVerificationHandler.ChangeMyEmail)
+			setup.router.ServeHTTP(setup.recorder, setup.context.Request)
+
+			testutils.AssertResponse(t, setup.recorder.Result(), tt.want.status, testutils.LoadFile(t, tt.want.respFile))
+		})
+	}
+}
+
+func TestEmailVerificationHandler_ConfirmEmailChange(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  want
+	}{
+		{
+			name:  "successful confirmation",
+			query: "?token=email-change-token-123",
+			want: want{
+				status:   http.StatusOK,
+				respFile: "testdata/confirm_email_change/200_resp.json.golden",
+			},
+		},
+		{
+			name:  "missing token",
+			query: "",
+			want: want{
+				status:   http.StatusBadRequest,
+				respFile: "testdata/confirm_email_change/400_resp.json.golden",
+			},
+		},
+		{
+			name:  "invalid token",
+			query: "?token=invalid-token",
+			want: want{
+				status:   http.StatusBadRequest,
+				respFile: "testdata/confirm_email_change/400_resp.json.golden",
+			},
+		},
+		{
+			name:  "email taken in the meantime",
+			query: "?token=email-change-token-123",
+			want: want{
+				status:   http.StatusConflict,
+				respFile: "testdata/confirm_email_change/409_resp.json.golden",
+			},
+		},
+		{
+			name:  "internal server error",
+			query: "?token=email-change-token-123",
+			want: want{
+				status:   http.StatusInternalServerError,
+				respFile: "testdata/confirm_email_change/500_resp.json.golden",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setup := setupEmailVerificationTest(t, false)
+			defer setup.ctrl.Finish()
+
+			// ConfirmEmailChange service won't be called when token is missing
+			if tt.query != "" {
+				setup.mockEmailVerificationService.EXPECT().ConfirmEmailChange(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, token string) error {
+					switch tt.want.status {
+					case http.StatusOK:
+						return nil
+					case http.StatusBadRequest:
+						return utils.ErrInvalidEmailChangeToken
+					case http.StatusConflict:
+						return &pgconn.PgError{Code: "23505"}
+					}
+					return errors.New("unexpected error")
+				})
+			}
+
+			setup.context.Request = httptest.NewRequest(http.MethodGet, "/email-change/confirm"+tt.query, nil)
+			setup.router.GET("/email-change/confirm", setup.emailVerificationHandler.ConfirmEmailChange)
+			setup.router.ServeHTTP(setup.recorder, setup.context.Request)
+
+			testutils.AssertResponse(t, setup.recorder.Result(), tt.want.status, testutils.LoadFile(t, tt.want.respFile))
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+	"todo-app/internal/services"
+	"todo-app/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ExportHandler struct {
+	ExportService services.IExportService
+}
+
+func NewExportHandler(exportService services.IExportService) *ExportHandler {
+	return &ExportHandler{ExportService: exportService}
+}
+
+// @Summary Export current user's data
+// @Description Streams a ZIP with the profile and preferences (profile.json), the lists (lists.csv), all todos (todos.json and todos.csv), their checklist items (todo_items.csv) and the avatar, if any.
+// @Tags User
+// @Produce application/zip
+// @Security BearerAuth
+// @Success 200 {file} file "ZIP archive"
+// @Failure 404 {object} gin.H "{"error": "Resource not found"}"
+// @Failure 500 {object} gin.H "{"error": "Internal server error"}"
+// @Router /me/export [get]
+func (h *ExportHandler) ExportMyData(ctx *gin.Context) {
+	userIDUuid, err := utils.GetUIDFromCtxAndCreateRespUponErr(ctx)
+	if err != nil {
+		return
+	}
+
+	ctx.Header("Content-Type", "application/zip")
+	ctx.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="todo-app-export-%s.zip"`, time.Now().UTC().Format("20060102")))
+	ctx.Header("Cache-Control", "no-store")
+
+	if err := h.ExportService.WriteExport(ctx, userIDUuid, ctx.Writer); err != nil {
+		log.Println(err.Error())
+
+		// Once part of the ZIP has been sent, the status cannot be changed anymore and the truncated ZIP is all the client gets
+		if ctx.Writer.Written() {
+			ctx.Abort()
+			return
+		}
+
+		ctx.Writer.Header().Del("Content-Type")
+		ctx.Writer.Header().Del("Content-Disposition")
+
+		if err == utils.ErrNoRowsMatchedSQLC {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": utils.MsgResourceNotFound})
+			return
+		}
+
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": utils.MsgInternalServerErr})
+	}
+}
@@ -0,0 +1,123 @@
+package handlers_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"todo-app/internal/handlers"
+	mock_services "todo-app/internal/services/_mock"
+	"todo-app/internal/utils"
+	"todo-app/internal/utils/testutils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestExportHandler_ExportMyData(t *testing.T) {
+	tests := []struct {
+		name           string
+		want           want
+		setUserIDInCtx bool
+	}{
+		{
+			name: "successful export",
+			want: want{
+				status: http.StatusOK,
+			},
+			setUserIDInCtx: true,
+		},
+		{
+			name: "failed to get userID from context",
+			want: want{
+				status:   http.StatusUnauthorized,
+				respFile: "testdata/export_my_data/401_resp.json.golden",
+			},
+			setUserIDInCtx: false,
+		},
+		{
+			name: "specified user not found",
+			want: want{
+				status:   http.StatusNotFound,
+				respFile: "testdata/export_my_data/404_resp.json.golden",
+			},
+			setUserIDInCtx: true,
+		},
+		{
+			name: "internal server error",
+			want: want{
+				status:   http.StatusInternalServerError,
+				respFile: "testdata/export_my_data/500_resp.json.golden",
+			},
+			setUserIDInCtx: true,
+		},
+		{
+			name: "error after the export started",
+			want: want{
+				status: http.StatusOK,
+			},
+			setUserIDInCtx: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockExportService := mock_services.NewMockIExportService(ctrl)
+			exportHandler := handlers.NewExportHandler(mockExportService)
+			gin.SetMode(gin.TestMode)
+
+			w := httptest.NewRecorder()
+			_, r := gin.CreateTestContext(w)
+			if tt.setUserIDInCtx {
+				r.Use(func(c *gin.Context) {
+					c.Set("userID", uIDStr)
+					c.Next()
+				})
+			}
+
+			// WriteExport service won't be called when userID is not in context
+			if tt.setUserIDInCtx {
+				mockExportService.EXPECT().WriteExport(gomock.Any(), uIDUuid, gomock.Any()).DoAndReturn(func(ctx context.Context, userID pgtype.UUID, w io.Writer) error {
+					switch tt.name {
+					case "successful export":
+						_, err := io.WriteString(w, "zip-content")
+						return err
+					case "specified user not found":
+						return utils.ErrNoRowsMatchedSQLC
+					case "error after the export started":
+						if _, err := io.WriteString(w, "zip-"); err != nil {
+							return err
+						}
+						return errors.New("fetch failed")
+					}
+					return errors.New("unexpected error")
+				})
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/me/export", nil)
+			r.GET("/me/export", exportHandler.ExportMyData)
+			r.ServeHTTP(w, req)
+
+			switch tt.name {
+			case "successful export":
+				assert.Equal(t, tt.want.status, w.Code)
+				assert.Equal(t, "application/zip", w.Header().Get("Content-Type"))
+				assert.Regexp(t, `^attachment; filename="todo-app-export-\d{8}\.zip"$`, w.Header().Get("Content-Disposition"))
+				assert.Equal(t, "zip-content", w.Body.String())
+			case "error after the export started":
+				// The status has already been sent, so the response is only cut off
+				assert.Equal(t, tt.want.status, w.Code)
+				assert.Equal(t, "zip-", w.Body.String())
+			default:
+				assert.Empty(t, w.Header().Get("Content-Disposition"))
+				testutils.AssertResponse(t, w.Result(), tt.want.status, testutils.LoadFile(t, tt.want.respFile))
+			}
+		})
+	}
+}
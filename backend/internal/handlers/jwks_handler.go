@@ -0,0 +1,23 @@
+package handlers
+
+import (
+	"net/http"
+	"todo-app/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type JWKSHandler struct {
+	TokenGenerator services.ITokenGenerator
+}
+
+func NewJWKSHandler(jwter services.ITokenGenerator) *JWKSHandler {
+	return &JWKSHandler{TokenGenerator: jwter}
+}
+
+// Served at /.well-known/jwks.json (outside of /api/v1) so that other services can verify access tokens issued by this API
+func (h *JWKSHandler) GetJWKS(ctx *gin.Context) {
+	// Verifiers may cache the keyset for a while; rotated keys stay in the set until tokens signed by them expire
+	ctx.Header("Cache-Control", "public, max-age=300")
+	ctx.JSON(http.StatusOK, h.TokenGenerator.JWKS())
+}
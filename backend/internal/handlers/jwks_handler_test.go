@@ -0,0 +1,37 @@
+package handlers_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"todo-app/internal/handlers"
+	"todo-app/internal/services"
+	mock_services "todo-app/internal/services/_mock"
+	"todo-app/internal/utils/testutils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestJWKSHandler_GetJWKS(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTokenGen := mock_services.NewMockITokenGenerator(ctrl)
+	jwksHandler := handlers.NewJWKSHandler(mockTokenGen)
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	_, r := gin.CreateTestContext(w)
+
+	mockTokenGen.EXPECT().JWKS().Return(&services.JWKS{Keys: []services.JWK{
+		{Kty: "OKP", Kid: "ed-2024", Use: "sig", Alg: "EdDSA", Crv: "Ed25519", X: "11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo"},
+	}})
+
+	r.GET("/.well-known/jwks.json", jwksHandler.GetJWKS)
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/.well-known/jwks.json", nil))
+
+	assert.Equal(t, "public, max-age=300", w.Result().Header.Get("Cache-Control"))
+	testutils.AssertResponse(t, w.Result(), http.StatusOK, testutils.LoadFile(t, "testdata/jwks/200_resp.json.golden"))
+}
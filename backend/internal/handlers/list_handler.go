@@ -0,0 +1,288 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"todo-app/internal/db"
+	"todo-app/internal/services"
+	"todo-app/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ListHandler struct {
+	ListService services.IListService
+}
+
+type ListResponse struct {
+	ID         int32      `json:"id"`
+	Name       string     `json:"name"`
+	Inbox      bool       `json:"inbox"`
+	ArchivedAt *time.Time `json:"archived_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+func NewListHandler(listService services.IListService) *ListHandler {
+	return &ListHandler{ListService: listService}
+}
+
+// @Summary Create a list
+// @Tags List
+// @Accept json
+// @Produce json
+// @Param list body services.ListRequest true "Name of the list"
+// @Security BearerAuth
+// @Success 201 {object} ListResponse
+// @Failure 400 {object} gin.H "{"error": "Invalid request"}"
+// @Failure 500 {object} gin.H "{"error": "Internal server error"}"
+// @Router /lists [post]
+func (h *ListHandler) CreateList(ctx *gin.Context) {
+	userIDUuid, err := utils.GetUIDFromCtxAndCreateRespUponErr(ctx)
+	if err != nil {
+		return
+	}
+
+	var req services.ListRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil || strings.TrimSpace(req.Name) == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": utils.MsgInvalidReq})
+		return
+	}
+
+	list, err := h.ListService.CreateList(ctx, userIDUuid, req)
+	if err != nil {
+		log.Println(err.Error())
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": utils.MsgInternalServerErr})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, toListResponse(*list))
+}
+
+// @Summary List current user's lists
+// @Description The inbox comes first, then the other lists in the order they were created.
+// @Tags List
+// @Produce json
+// @Param archived query bool false "include archived lists"
+// @Security BearerAuth
+// @Success 200 {array} ListResponse
+// @Failure 400 {object} gin.H "{"error": "Invalid request"}"
+// @Failure 500 {object} gin.H "{"error": "Internal server error"}"
+// @Router /lists [get]
+func (h *ListHandler) ListLists(ctx *gin.Context) {
+	userIDUuid, err := utils.GetUIDFromCtxAndCreateRespUponErr(ctx)
+	if err != nil {
+		return
+	}
+
+	var req services.ListListsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": utils.MsgInvalidReq})
+		return
+	}
+
+	lists, err := h.ListService.ListLists(ctx, userIDUuid, req)
+	if err != nil {
+		log.Println(err.Error())
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": utils.MsgInternalServerErr})
+		return
+	}
+
+	listResponses := make([]ListResponse, len(lists))
+	for i, list := range lists {
+		listResponses[i] = toListResponse(list)
+	}
+
+	ctx.JSON(http.StatusOK, listResponses)
+}
+
+// @Summary Get a list
+// @Tags List
+// @Produce json
+// @Param id path int true "List ID"
+// @Security BearerAuth
+// @Success 200 {object} ListResponse
+// @Failure 400 {object} gin.H "{"error": "Invalid request"}"
+// @Failure 404 {object} gin.H "{"error": "Resource not found"}"
+// @Failure 500 {object} gin.H "{"error": "Internal server error"}"
+// @Router /lists/{id} [get]
+func (h *ListHandler) GetList(ctx *gin.Context) {
+	userIDUuid, err := utils.GetUIDFromCtxAndCreateRespUponErr(ctx)
+	if err != nil {
+		return
+	}
+
+	listID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": utils.MsgInvalidReq})
+		return
+	}
+
+	list, err := h.ListService.GetList(ctx, userIDUuid, int32(listID))
+	h.respondList(ctx, list, err)
+}
+
+// @Summary Rename a list
+// @Tags List
+// @Accept json
+// @Produce json
+// @Param id path int true "List ID"
+// @Param list body services.ListRequest true "New name of the list"
+// @Security BearerAuth
+// @Success 200 {object} ListResponse
+// @Failure 400 {object} gin.H "{"error": "Invalid request"}"
+// @Failure 404 {object} gin.H "{"error": "Resource not found"}"
+// @Failure 500 {object} gin.H "{"error": "Internal server error"}"
+// @Router /lists/{id} [put]
+func (h *ListHandler) UpdateList(ctx *gin.Context) {
+	userIDUuid, err := utils.GetUIDFromCtxAndCreateRespUponErr(ctx)
+	if err != nil {
+		return
+	}
+
+	listID, err := strconv.Atoi(ctx.Param("id"))
+	var req services.ListRequest
+	if reqErr := ctx.ShouldBindJSON(&req); reqErr != nil || err != nil || strings.TrimSpace(req.Name) == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": utils.MsgInvalidReq})
+		return
+	}
+
+	list, err := h.ListService.UpdateList(ctx, userIDUuid, int32(listID), req)
+	h.respondList(ctx, list, err)
+}
+
+// @Summary Archive a list
+// @Description The todos of an archived list are left out of GET /todos unless the list is asked for by list_id.
+// @Tags List
+// @Produce json
+// @Param id path int true "List ID"
+// @Security BearerAuth
+// @Success 200 {object} ListResponse
+// @Failure 400 {object} gin.H "{"error": "Invalid request"} or {"error": "The inbox cannot be archived or deleted"}"
+// @Failure 404 {object} gin.H "{"error": "Resource not found"}"
+// @Failure 500 {object} gin.H "{"error": "Internal server error"}"
+// @Router /lists/{id}/archive [post]
+func (h *ListHandler) ArchiveList(ctx *gin.Context) {
+	userIDUuid, err := utils.GetUIDFromCtxAndCreateRespUponErr(ctx)
+	if err != nil {
+		return
+	}
+
+	listID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": utils.MsgInvalidReq})
+		return
+	}
+
+	list, err := h.ListService.ArchiveList(ctx, userIDUuid, int32(listID))
+	h.respondList(ctx, list, err)
+}
+
+// @Summary Unarchive a list
+// @Tags List
+// @Produce json
+// @Param id path int true "List ID"
+// @Security BearerAuth
+// @Success 200 {object} ListResponse
+// @Failure 400 {object} gin.H "{"error": "Invalid request"} or {"error": "The inbox cannot be archived or deleted"}"
+// @Failure 404 {object} gin.H "{"error": "Resource not found"}"
+// @Failure 500 {object} gin.H "{"error": "Internal server error"}"
+// @Router /lists/{id}/unarchive [post]
+func (h *ListHandler) UnarchiveList(ctx *gin.Context) {
+	userIDUuid, err := utils.GetUIDFromCtxAndCreateRespUponErr(ctx)
+	if err != nil {
+		return
+	}
+
+	listID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": utils.MsgInvalidReq})
+		return
+	}
+
+	list, err := h.ListService.UnarchiveList(ctx, userIDUuid, int32(listID))
+	h.respondList(ctx, list, err)
+}
+
+// @Summary Delete a list
+// @Description The todos of the list are deleted along with it.
+// @Tags List
+// @Produce json
+// @Param id path int true "List ID"
+// @Security BearerAuth
+// @Success 200 {object} gin.H "{"message": "List deleted"}"
+// @Failure 400 {object} gin.H "{"error": "Invalid request"} or {"error": "The inbox cannot be archived or deleted"}"
+// @Failure 404 {object} gin.H "{"error": "Resource not found"}"
+// @Failure 500 {object} gin.H "{"error": "Internal server error"}"
+// @Router /lists/{id} [delete]
+func (h *ListHandler) DeleteList(ctx *gin.Context) {
+	userIDUuid, err := utils.GetUIDFromCtxAndCreateRespUponErr(ctx)
+	if err != nil {
+		return
+	}
+
+	listID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": utils.MsgInvalidReq})
+		return
+	}
+
+	err = h.ListService.DeleteList(ctx, userIDUuid, int32(listID))
+	if err != nil {
+		log.Println(err.Error())
+
+		if err == utils.ErrInboxList {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": utils.MsgInboxList})
+			return
+		}
+
+		if err == utils.ErrNoRowsMatchedSQLC {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": utils.MsgResourceNotFound})
+			return
+		}
+
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": utils.MsgInternalServerErr})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "List deleted"})
+}
+
+// Shared by the endpoints returning a single list
+func (h *ListHandler) respondList(ctx *gin.Context, list *db.List, err error) {
+	if err != nil {
+		log.Println(err.Error())
+
+		if err == utils.ErrInboxList {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": utils.MsgInboxList})
+			return
+		}
+
+		if err == utils.ErrNoRowsMatchedSQLC {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": utils.MsgResourceNotFound})
+			return
+		}
+
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": utils.MsgInternalServerErr})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, toListResponse(*list))
+}
+
+func toListResponse(list db.List) ListResponse {
+	resp := ListResponse{
+		ID:        list.ID,
+		Name:      list.Name,
+		Inbox:     list.IsInbox,
+		CreatedAt: list.CreatedAt.Time,
+		UpdatedAt: list.UpdatedAt.Time,
+	}
+	if list.ArchivedAt.Valid {
+		resp.ArchivedAt = &list.ArchivedAt.Time
+	}
+	return resp
+}
@@ -0,0 +1,263 @@
+package handlers_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"todo-app/internal/db"
+	"todo-app/internal/handlers"
+	"todo-app/internal/services"
+	mock_services "todo-app/internal/services/_mock"
+	"todo-app/internal/utils"
+	"todo-app/internal/utils/testutils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+type listTestSetup struct {
+	ctrl            *gomock.Controller
+	mockListService *mock_services.MockIListService
+	listHandler     *handlers.ListHandler
+	router          *gin.Engine
+	recorder        *httptest.ResponseRecorder
+	context         *gin.Context
+}
+
+func setupListTest(t *testing.T, setUserIDInCtx bool) *listTestSetup {
+	ctrl := gomock.NewController(t)
+	mockListService := mock_services.NewMockIListService(ctrl)
+	listHandler := handlers.NewListHandler(mockListService)
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	ctx, r := gin.CreateTestContext(w)
+
+	if setUserIDInCtx {
+		ctx.Set("userID", uIDStr)
+		r.Use(func(c *gin.Context) {
+			c.Set("userID", uIDStr)
+			c.Next()
+		})
+	}
+
+	return &listTestSetup{
+		ctrl:            ctrl,
+		mockListService: mockListService,
+		listHandler:     listHandler,
+		router:          r,
+		recorder:        w,
+		context:         ctx,
+	}
+}
+
+func mockList(id int32, name string, archived bool) db.List {
+	list := db.List{
+		ID:        id,
+		UserID:    1,
+		Name:      name,
+		IsInbox:   name == "Inbox",
+		CreatedAt: pgtype.Timestamptz{Time: mockTime, Valid: true},
+		UpdatedAt: pgtype.Timestamptz{Time: mockTime, Valid: true},
+	}
+	if archived {
+		list.ArchivedAt = pgtype.Timestamptz{Time: mockTime, Valid: true}
+	}
+	return list
+}
+
+func TestListHandler_CreateList(t *testing.T) {
+	tests := []struct {
+		name           string
+		reqFile        string
+		want           want
+		setUserIDInCtx bool
+	}{
+		{
+			name:    "successful create list",
+			reqFile: "testdata/create_list/201_req.json.golden",
+			want: want{
+				status:   http.StatusCreated,
+				respFile: "testdata/create_list/201_resp.json.golden",
+			},
+			setUserIDInCtx: true,
+		},
+		{
+			name:    "failed to get userID from context",
+			reqFile: "testdata/create_list/201_req.json.golden",
+			want: want{
+				status:   http.StatusUnauthorized,
+				respFile: "testdata/create_list/401_resp.json.golden",
+			},
+			setUserIDInCtx: false,
+		},
+		{
+			name:    "blank name",
+			reqFile: "testdata/create_list/400_req.json.golden",
+			want: want{
+				status:   http.StatusBadRequest,
+				respFile: "testdata/create_list/400_resp.json.golden",
+			},
+			setUserIDInCtx: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setup := setupListTest(t, tt.setUserIDInCtx)
+			defer setup.ctrl.Finish()
+
+			if tt.want.status == http.StatusCreated {
+				setup.mockListService.EXPECT().CreateList(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, userID pgtype.UUID, req services.ListRequest) (*db.List, error) {
+					list := mockList(20, req.Name, false)
+					return &list, nil
+				})
+			}
+
+			setup.context.Request = httptest.NewRequest(http.MethodPost, "/lists", bytes.NewReader(testutils.LoadFile(t, tt.reqFile)))
+			setup.context.Request.Header.Set("Content-Type", "application/json")
+			setup.router.POST("/lists", setup.listHandler.CreateList)
+			setup.router.ServeHTTP(setup.recorder, setup.context.Request)
+
+			testutils.AssertResponse(t, setup.recorder.Result(), tt.want.status, testutils.LoadFile(t, tt.want.respFile))
+		})
+	}
+}
+
+func TestListHandler_ListLists(t *testing.T) {
+	setup := setupListTest(t, true)
+	defer setup.ctrl.Finish()
+
+	setup.mockListService.EXPECT().ListLists(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, userID pgtype.UUID, req services.ListListsRequest) ([]db.List, error) {
+		assert.True(t, req.Archived)
+		return []db.List{mockList(10, "Inbox", false), mockList(20, "Groceries", true)}, nil
+	})
+
+	setup.context.Request = httptest.NewRequest(http.MethodGet, "/lists?archived=true", nil)
+	setup.router.GET("/lists", setup.listHandler.ListLists)
+	setup.router.ServeHTTP(setup.recorder, setup.context.Request)
+
+	testutils.AssertResponse(t, setup.recorder.Result(), http.StatusOK, testutils.LoadFile(t, "testdata/list_lists/200_resp.json.golden"))
+}
+
+func TestListHandler_ArchiveList(t *testing.T) {
+	tests := []struct {
+		name   string
+		listID string
+		want   want
+	}{
+		{
+			name:   "successful archive list",
+			listID: "20",
+			want: want{
+				status:   http.StatusOK,
+				respFile: "testdata/archive_list/200_resp.json.golden",
+			},
+		},
+		{
+			name:   "inbox",
+			listID: "10",
+			want: want{
+				status:   http.StatusBadRequest,
+				respFile: "testdata/archive_list/400_resp_inbox.json.golden",
+			},
+		},
+		{
+			name:   "specified list not found",
+			listID: "1000",
+			want: want{
+				status:   http.StatusNotFound,
+				respFile: "testdata/archive_list/404_resp.json.golden",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setup := setupListTest(t, true)
+			defer setup.ctrl.Finish()
+
+			setup.mockListService.EXPECT().ArchiveList(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, userID pgtype.UUID, listID int32) (*db.List, error) {
+				switch tt.want.status {
+				case http.StatusOK:
+					list := mockList(listID, "Groceries", true)
+					return &list, nil
+				case http.StatusBadRequest:
+					return nil, utils.ErrInboxList
+				case http.StatusNotFound:
+					return nil, utils.ErrNoRowsMatchedSQLC
+				}
+				return nil, errors.New("error from mock")
+			})
+
+			setup.context.Request = httptest.NewRequest(http.MethodPost, "/lists/"+tt.listID+"/archive", nil)
+			setup.router.POST("/lists/:id/archive", setup.listHandler.ArchiveList)
+			setup.router.ServeHTTP(setup.recorder, setup.context.Request)
+
+			testutils.AssertResponse(t, setup.recorder.Result(), tt.want.status, testutils.LoadFile(t, tt.want.respFile))
+		})
+	}
+}
+
+func TestListHandler_DeleteList(t *testing.T) {
+	tests := []struct {
+		name   string
+		listID string
+		want   want
+	}{
+		{
+			name:   "successful delete list",
+			listID: "20",
+			want: want{
+				status:   http.StatusOK,
+				respFile: "testdata/delete_list/200_resp.json.golden",
+			},
+		},
+		{
+			name:   "inbox",
+			listID: "10",
+			want: want{
+				status:   http.StatusBadRequest,
+				respFile: "testdata/delete_list/400_resp_inbox.json.golden",
+			},
+		},
+		{
+			name:   "specified list not found",
+			listID: "1000",
+			want: want{
+				status:   http.StatusNotFound,
+				respFile: "testdata/delete_list/404_resp.json.golden",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setup := setupListTest(t, true)
+			defer setup.ctrl.Finish()
+
+			setup.mockListService.EXPECT().DeleteList(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, userID pgtype.UUID, listID int32) error {
+				switch tt.want.status {
+				case http.StatusOK:
+					return nil
+				case http.StatusBadRequest:
+					return utils.ErrInboxList
+				case http.StatusNotFound:
+					return utils.ErrNoRowsMatchedSQLC
+				}
+				return errors.New("error from mock")
+			})
+
+			setup.context.Request = httptest.NewRequest(http.MethodDelete, "/lists/"+tt.listID, nil)
+			setup.router.DELETE("/lists/:id", setup.listHandler.DeleteList)
+			setup.router.ServeHTTP(setup.recorder, setup.context.Request)
+
+			testutils.AssertResponse(t, setup.recorder.Result(), tt.want.status, testutils.LoadFile(t, tt.want.respFile))
+		})
+	}
+}
@@ -0,0 +1,177 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"time"
+	"todo-app/internal/services"
+	"todo-app/internal/utils"
+
+	"github.com/gin-contrib/sessions"
+	"github.com/gin-gonic/gin"
+)
+
+// Session keys holding the values generated when an OIDC login starts
+const (
+	oidcProviderSessionKey     = "oidcProvider"
+	oidcStateSessionKey        = "oidcState"
+	oidcNonceSessionKey        = "oidcNonce"
+	oidcCodeVerifierSessionKey = "oidcCodeVerifier"
+)
+
+type OIDCHandler struct {
+	OIDCService services.IOIDCService
+}
+
+func NewOIDCHandler(oidcService services.IOIDCService) *OIDCHandler {
+	return &OIDCHandler{OIDCService: oidcService}
+}
+
+// @Summary Start a login with an OIDC provider
+// @Description Redirects to the provider, which redirects back to the callback endpoint once the user has signed in.
+// @Tags Auth
+// @Param provider path string true "provider name as configured in OIDC_PROVIDERS"
+// @Success 302 "Redirect to the authorization endpoint of the provider"
+// @Failure 404 {object} gin.H "{"error": "Unknown login provider"}"
+// @Failure 500 {object} gin.H "{"error": "Internal server error"}"
+// @Router /oidc/{provider}/login [get]
+func (h *OIDCHandler) StartOIDCLogin(ctx *gin.Context) {
+	authReq, err := h.OIDCService.StartLogin(ctx, ctx.Param("provider"))
+	if err != nil {
+		log.Println(err.Error())
+
+		if err == utils.ErrUnknownOIDCProvider {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": utils.MsgUnknownOIDCProvider})
+			return
+		}
+
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": utils.MsgInternalServerErr})
+		return
+	}
+
+	// Saving also gives a new session its ID, which the tokens issued by the callback are bound to
+	session := sessions.Default(ctx)
+	if session.Get("createdAt") == nil {
+		session.Set("createdAt", time.Now().Unix())
+	}
+	session.Set(oidcProviderSessionKey, authReq.AuthState.Provider)
+	session.Set(oidcStateSessionKey, authReq.AuthState.State)
+	session.Set(oidcNonceSessionKey, authReq.AuthState.Nonce)
+	session.Set(oidcCodeVerifierSessionKey, authReq.AuthState.CodeVerifier)
+	if err := session.Save(); err != nil {
+		log.Println(err.Error())
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": utils.MsgInternalServerErr})
+		return
+	}
+
+	ctx.Redirect(http.StatusFound, authReq.URL)
+}
+
+// @Summary Complete a login with an OIDC provider
+// @Description The provider redirects here with the authorization code. The account is linked by a verified email or created if none exists.
+// @Tags Auth
+// @Produce json
+// @Param provider path string true "provider name as configured in OIDC_PROVIDERS"
+// @Param code query string true "authorization code"
+// @Param state query string true "state passed to the provider"
+// @Success 200 {object} LoginResponse "or TwoFactorChallengeResponse when two-factor authentication is enabled"
+// @Failure 400 {object} gin.H "{"error": "Invalid request"}"
+// @Failure 401 {object} gin.H "{"error": "Login with the provider failed"}"
+// @Failure 403 {object} gin.H "{"error": "The provider did not report a verified email address"} or {"error": "This account has been disabled"}"
+// @Failure 404 {object} gin.H "{"error": "Unknown login provider"}"
+// @Failure 409 {object} gin.H "{"error": "An account with this email address already exists but its email is not verified"}"
+// @Failure 500 {object} gin.H "{"error": "Internal server error"}"
+// @Router /oidc/{provider}/callback [get]
+func (h *OIDCHandler) OIDCCallback(ctx *gin.Context) {
+	session := sessions.Default(ctx)
+	authState := services.OIDCAuthState{
+		Provider:     sessionString(session, oidcProviderSessionKey),
+		State:        sessionString(session, oidcStateSessionKey),
+		Nonce:        sessionString(session, oidcNonceSessionKey),
+		CodeVerifier: sessionString(session, oidcCodeVerifierSessionKey),
+	}
+
+	// The values can be used only once, whatever the outcome
+	session.Delete(oidcProviderSessionKey)
+	session.Delete(oidcStateSessionKey)
+	session.Delete(oidcNonceSessionKey)
+	session.Delete(oidcCodeVerifierSessionKey)
+	if err := session.Save(); err != nil {
+		log.Println(err.Error())
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": utils.MsgInternalServerErr})
+		return
+	}
+
+	// e.g. the user denied the consent at the provider
+	if providerErr := ctx.Query("error"); providerErr != "" {
+		log.Println("oidc provider returned error: " + providerErr)
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": utils.MsgOIDCLoginFailed})
+		return
+	}
+
+	var req services.OIDCCallbackRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": utils.MsgInvalidReq})
+		return
+	}
+
+	client := services.ClientInfo{UserAgent: ctx.Request.UserAgent(), IP: ctx.ClientIP()}
+
+	userID, tokens, err := h.OIDCService.Login(ctx, ctx.Param("provider"), req, authState, session.ID(), client)
+	if err != nil {
+		log.Println(err.Error())
+
+		if err == utils.ErrUnknownOIDCProvider {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": utils.MsgUnknownOIDCProvider})
+			return
+		}
+
+		if err == utils.ErrInvalidOIDCState || errors.Is(err, utils.ErrOIDCLoginFailed) {
+			ctx.JSON(http.StatusUnauthorized, gin.H{"error": utils.MsgOIDCLoginFailed})
+			return
+		}
+
+		if err == utils.ErrOIDCEmailNotVerified {
+			ctx.JSON(http.StatusForbidden, gin.H{"error": utils.MsgOIDCEmailNotVerified})
+			return
+		}
+
+		if err == utils.ErrAccountDisabled {
+			ctx.JSON(http.StatusForbidden, gin.H{"error": utils.MsgAccountDisabled})
+			return
+		}
+
+		if err == utils.ErrAccountDeleted {
+			ctx.JSON(http.StatusForbidden, gin.H{"error": utils.MsgAccountDeleted})
+			return
+		}
+
+		if err == utils.ErrOIDCAccountNotLinkable {
+			ctx.JSON(http.StatusConflict, gin.H{"error": utils.MsgOIDCAccountNotLinkable})
+			return
+		}
+
+		var twoFactorErr *services.TwoFactorRequiredError
+		if errors.As(err, &twoFactorErr) {
+			ctx.JSON(http.StatusOK, TwoFactorChallengeResponse{TwoFactorRequired: true, ChallengeToken: twoFactorErr.ChallengeToken})
+			return
+		}
+
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": utils.MsgInternalServerErr})
+		return
+	}
+
+	if err = setSessionUser(session, userID); err != nil {
+		log.Println(err.Error())
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": utils.MsgInternalServerErr})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, LoginResponse{UserID: userID, AccessToken: tokens.AccessToken, RefreshToken: tokens.RefreshToken})
+}
+
+func sessionString(session sessions.Session, key string) string {
+	v, _ := session.Get(key).(string)
+	return v
+}
@@ -0,0 +1,284 @@
+package handlers_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"todo-app/internal/handlers"
+	"todo-app/internal/services"
+	mock_services "todo-app/internal/services/_mock"
+	"todo-app/internal/utils"
+	"todo-app/internal/utils/testutils"
+
+	"github.com/gin-contrib/sessions"
+	"github.com/gin-contrib/sessions/memstore"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+var mockOIDCAuthState = services.OIDCAuthState{Provider: "google", State: "state-123", Nonce: "nonce-123", CodeVerifier: "verifier-123"}
+
+func setupOIDCTest(t *testing.T) (*gomock.Controller, *mock_services.MockIOIDCService, *gin.Engine) {
+	ctrl := gomock.NewController(t)
+	mockOIDCService := mock_services.NewMockIOIDCService(ctrl)
+	oidcHandler := handlers.NewOIDCHandler(mockOIDCService)
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	store := memstore.NewStore([]byte("secret"))
+	r.Use(sessions.Sessions("mysession", store))
+	r.GET("/oidc/:provider/login", oidcHandler.StartOIDCLogin)
+	r.GET("/oidc/:provider/callback", oidcHandler.OIDCCallback)
+	r.GET("/check-session", checkSessionHandler)
+
+	return ctrl, mockOIDCService, r
+}
+
+func serveWithCookies(r *gin.Engine, target string, cookies []*http.Cookie) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	for _, cookie := range cookies {
+		req.AddCookie(cookie)
+	}
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestOIDCHandler_StartOIDCLogin(t *testing.T) {
+	tests := []struct {
+		name     string
+		provider string
+		err      error
+		want     want
+	}{
+		{
+			name:     "successful redirect",
+			provider: "google",
+			want: want{
+				status: http.StatusFound,
+			},
+		},
+		{
+			name:     "unknown provider",
+			provider: "unknown",
+			err:      utils.ErrUnknownOIDCProvider,
+			want: want{
+				status:   http.StatusNotFound,
+				respFile: "testdata/start_oidc_login/404_resp.json.golden",
+			},
+		},
+		{
+			name:     "internal server error",
+			provider: "google",
+			err:      errors.New("discovery failed"),
+			want: want{
+				status:   http.StatusInternalServerError,
+				respFile: "testdata/start_oidc_login/500_resp.json.golden",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl, mockOIDCService, r := setupOIDCTest(t)
+			defer ctrl.Finish()
+
+			mockOIDCService.EXPECT().StartLogin(gomock.Any(), tt.provider).DoAndReturn(func(ctx context.Context, providerName string) (*services.OIDCAuthRequest, error) {
+				if tt.err != nil {
+					return nil, tt.err
+				}
+				return &services.OIDCAuthRequest{URL: "https://accounts.example.com/authorize?state=state-123", AuthState: mockOIDCAuthState}, nil
+			})
+
+			w := serveWithCookies(r, "/oidc/"+tt.provider+"/login", nil)
+
+			if tt.want.status == http.StatusFound {
+				assert.Equal(t, http.StatusFound, w.Code)
+				assert.Equal(t, "https://accounts.example.com/authorize?state=state-123", w.Header().Get("Location"))
+				assert.NotEmpty(t, w.Result().Cookies())
+				return
+			}
+
+			testutils.AssertResponse(t, w.Result(), tt.want.status, testutils.LoadFile(t, tt.want.respFile))
+		})
+	}
+}
+
+func TestOIDCHandler_OIDCCallback(t *testing.T) {
+	tests := []struct {
+		name             string
+		query            string
+		err              error
+		want             want
+		checkSessionWant want
+	}{
+		{
+			name:  "successful login",
+			query: "?code=code-123&state=state-123",
+			want: want{
+				status:   http.StatusOK,
+				respFile: "testdata/oidc_callback/200_resp.json.golden",
+			},
+			checkSessionWant: checkSessionWants.exist,
+		},
+		{
+			name:  "two-factor authentication required",
+			query: "?code=code-123&state=state-123",
+			err:   &services.TwoFactorRequiredError{ChallengeToken: "challenge-token-123"},
+			want: want{
+				status:   http.StatusOK,
+				respFile: "testdata/oidc_callback/200_two_factor_resp.json.golden",
+			},
+			checkSessionWant: checkSessionWants.notExist,
+		},
+		{
+			name:  "missing code",
+			query: "?state=state-123",
+			want: want{
+				status:   http.StatusBadRequest,
+				respFile: "testdata/oidc_callback/400_resp.json.golden",
+			},
+			checkSessionWant: checkSessionWants.notExist,
+		},
+		{
+			name:  "error from provider",
+			query: "?error=access_denied&state=state-123",
+			want: want{
+				status:   http.StatusUnauthorized,
+				respFile: "testdata/oidc_callback/401_resp.json.golden",
+			},
+			checkSessionWant: checkSessionWants.notExist,
+		},
+		{
+			name:  "state mismatch",
+			query: "?code=code-123&state=forged-state",
+			err:   utils.ErrInvalidOIDCState,
+			want: want{
+				status:   http.StatusUnauthorized,
+				respFile: "testdata/oidc_callback/401_resp.json.golden",
+			},
+			checkSessionWant: checkSessionWants.notExist,
+		},
+		{
+			name:  "code exchange failed",
+			query: "?code=code-123&state=state-123",
+			err:   fmt.Errorf("%w: invalid_grant", utils.ErrOIDCLoginFailed),
+			want: want{
+				status:   http.StatusUnauthorized,
+				respFile: "testdata/oidc_callback/401_resp.json.golden",
+			},
+			checkSessionWant: checkSessionWants.notExist,
+		},
+		{
+			name:  "email not verified",
+			query: "?code=code-123&state=state-123",
+			err:   utils.ErrOIDCEmailNotVerified,
+			want: want{
+				status:   http.StatusForbidden,
+				respFile: "testdata/oidc_callback/403_resp.json.golden",
+			},
+			checkSessionWant: checkSessionWants.notExist,
+		},
+		{
+			name:  "account disabled",
+			query: "?code=code-123&state=state-123",
+			err:   utils.ErrAccountDisabled,
+			want: want{
+				status:   http.StatusForbidden,
+				respFile: "testdata/oidc_callback/403_disabled_resp.json.golden",
+			},
+			checkSessionWant: checkSessionWants.notExist,
+		},
+		{
+			name:  "unknown provider",
+			query: "?code=code-123&state=state-123",
+			err:   utils.ErrUnknownOIDCProvider,
+			want: want{
+				status:   http.StatusNotFound,
+				respFile: "testdata/oidc_callback/404_resp.json.golden",
+			},
+			checkSessionWant: checkSessionWants.notExist,
+		},
+		{
+			name:  "account not linkable",
+			query: "?code=code-123&state=state-123",
+			err:   utils.ErrOIDCAccountNotLinkable,
+			want: want{
+				status:   http.StatusConflict,
+				respFile: "testdata/oidc_callback/409_resp.json.golden",
+			},
+			checkSessionWant: checkSessionWants.notExist,
+		},
+		{
+			name:  "internal server error",
+			query: "?code=code-123&state=state-123",
+			err:   errors.New("unexpected error"),
+			want: want{
+				status:   http.StatusInternalServerError,
+				respFile: "testdata/oidc_callback/500_resp.json.golden",
+			},
+			checkSessionWant: checkSessionWants.notExist,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl, mockOIDCService, r := setupOIDCTest(t)
+			defer ctrl.Finish()
+
+			// Start the login first so that the session holds the pending state
+			mockOIDCService.EXPECT().StartLogin(gomock.Any(), "google").Return(&services.OIDCAuthRequest{URL: "https://accounts.example.com/authorize", AuthState: mockOIDCAuthState}, nil)
+			cookies := serveWithCookies(r, "/oidc/google/login", nil).Result().Cookies()
+
+			// Login service won't be called when the provider returned an error or the query is invalid
+			if tt.name != "error from provider" && tt.name != "missing code" {
+				mockOIDCService.EXPECT().Login(gomock.Any(), "google", gomock.Any(), mockOIDCAuthState, gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, providerName string, req services.OIDCCallbackRequest, authState services.OIDCAuthState, sessionID string, client services.ClientInfo) (string, *services.TokenPair, error) {
+					assert.Equal(t, "code-123", req.Code)
+					assert.NotEmpty(t, sessionID)
+					if tt.err != nil {
+						return "", nil, tt.err
+					}
+					return "user-id-123", &mockTokenPair, nil
+				})
+			}
+
+			w := serveWithCookies(r, "/oidc/google/callback"+tt.query, cookies)
+
+			testutils.AssertResponse(t, w.Result(), tt.want.status, testutils.LoadFile(t, tt.want.respFile))
+
+			// Verify session
+			if newCookies := w.Result().Cookies(); len(newCookies) > 0 {
+				cookies = newCookies
+			}
+			w = serveWithCookies(r, "/check-session", cookies)
+
+			testutils.AssertResponse(t, w.Result(), tt.checkSessionWant.status, []byte(tt.checkSessionWant.respFile))
+		})
+	}
+
+	t.Run("pending state is used only once", func(t *testing.T) {
+		ctrl, mockOIDCService, r := setupOIDCTest(t)
+		defer ctrl.Finish()
+
+		mockOIDCService.EXPECT().StartLogin(gomock.Any(), "google").Return(&services.OIDCAuthRequest{URL: "https://accounts.example.com/authorize", AuthState: mockOIDCAuthState}, nil)
+		cookies := serveWithCookies(r, "/oidc/google/login", nil).Result().Cookies()
+
+		gomock.InOrder(
+			mockOIDCService.EXPECT().Login(gomock.Any(), "google", gomock.Any(), mockOIDCAuthState, gomock.Any(), gomock.Any()).Return("", nil, utils.ErrOIDCEmailNotVerified),
+			mockOIDCService.EXPECT().Login(gomock.Any(), "google", gomock.Any(), services.OIDCAuthState{}, gomock.Any(), gomock.Any()).Return("", nil, utils.ErrInvalidOIDCState),
+		)
+
+		w := serveWithCookies(r, "/oidc/google/callback?code=code-123&state=state-123", cookies)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		if newCookies := w.Result().Cookies(); len(newCookies) > 0 {
+			cookies = newCookies
+		}
+
+		w = serveWithCookies(r, "/oidc/google/callback?code=code-123&state=state-123", cookies)
+		testutils.AssertResponse(t, w.Result(), http.StatusUnauthorized, testutils.LoadFile(t, "testdata/oidc_callback/401_resp.json.golden"))
+	})
+}
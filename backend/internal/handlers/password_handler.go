@@ -0,0 +1,142 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"todo-app/internal/services"
+	"todo-app/internal/utils"
+
+	"github.com/gin-contrib/sessions"
+	"github.com/gin-gonic/gin"
+)
+
+type PasswordHandler struct {
+	PasswordService services.IPasswordService
+}
+
+func NewPasswordHandler(passwordService services.IPasswordService) *PasswordHandler {
+	return &PasswordHandler{PasswordService: passwordService}
+}
+
+// @Summary Change current user's password
+// @Description Every other session of the user is signed out.
+// @Tags User
+// @Accept json
+// @Produce json
+// @Param password body services.ChangePasswordRequest true "Current and new password"
+// @Security BearerAuth
+// @Success 200 {object} gin.H "{"message": "Password changed"}"
+// @Failure 400 {object} gin.H "{"error": "Invalid request"} or {"error": "Current password is incorrect"} or {"error": "Password does not meet the requirements", "violations": [...]}"
+// @Failure 500 {object} gin.H "{"error": "Internal server error"}"
+// @Router /me/password [put]
+func (h *PasswordHandler) ChangeMyPassword(ctx *gin.Context) {
+	userIDUuid, err := utils.GetUIDFromCtxAndCreateRespUponErr(ctx)
+	if err != nil {
+		return
+	}
+
+	var req services.ChangePasswordRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": utils.MsgInvalidReq})
+		return
+	}
+
+	err = h.PasswordService.ChangePassword(ctx, userIDUuid, sessions.Default(ctx).ID(), req)
+	if err != nil {
+		log.Println(err.Error())
+
+		if err == utils.ErrInvalidCurrentPassword {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": utils.MsgInvalidCurrentPassword})
+			return
+		}
+
+		var policyErr *services.PasswordPolicyError
+		if errors.As(err, &policyErr) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": utils.MsgWeakPassword, "violations": policyErr.Violations})
+			return
+		}
+
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": utils.MsgInternalServerErr})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "Password changed"})
+}
+
+// @Summary Request a password reset email
+// @Description The response is the same whether or not the email is registered.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param email body services.ForgotPasswordRequest true "Email of the account"
+// @Success 200 {object} gin.H "{"message": "If the email is registered, a password reset link has been sent"}"
+// @Failure 400 {object} gin.H "{"error": "Invalid request"}"
+// @Failure 429 {object} gin.H "{"error": "Too many password reset requests, try again later"}"
+// @Header 429 {integer} Retry-After "seconds to wait before retrying"
+// @Failure 500 {object} gin.H "{"error": "Internal server error"}"
+// @Router /password/forgot [post]
+func (h *PasswordHandler) ForgotPassword(ctx *gin.Context) {
+	var req services.ForgotPasswordRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": utils.MsgInvalidReq})
+		return
+	}
+
+	client := services.ClientInfo{UserAgent: ctx.Request.UserAgent(), IP: ctx.ClientIP()}
+	if err := h.PasswordService.ForgotPassword(ctx, req, client); err != nil {
+		log.Println(err.Error())
+
+		var lockedErr *services.LoginLockedError
+		if errors.As(err, &lockedErr) {
+			ctx.Header("Retry-After", strconv.Itoa(int(math.Ceil(lockedErr.RetryAfter.Seconds()))))
+			ctx.JSON(http.StatusTooManyRequests, gin.H{"error": utils.MsgTooManyPasswordResets})
+			return
+		}
+
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": utils.MsgInternalServerErr})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "If the email is registered, a password reset link has been sent"})
+}
+
+// @Summary Reset the password with a token from the reset email
+// @Description Every session of the user is signed out.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param reset body services.ResetPasswordRequest true "Reset token and new password"
+// @Success 200 {object} gin.H "{"message": "Password reset"}"
+// @Failure 400 {object} gin.H "{"error": "Invalid request"} or {"error": "Invalid or expired password reset token"} or {"error": "Password does not meet the requirements", "violations": [...]}"
+// @Failure 500 {object} gin.H "{"error": "Internal server error"}"
+// @Router /password/reset [post]
+func (h *PasswordHandler) ResetPassword(ctx *gin.Context) {
+	var req services.ResetPasswordRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": utils.MsgInvalidReq})
+		return
+	}
+
+	if err := h.PasswordService.ResetPassword(ctx, req); err != nil {
+		log.Println(err.Error())
+
+		if err == utils.ErrInvalidResetToken {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": utils.MsgInvalidResetToken})
+			return
+		}
+
+		var policyErr *services.PasswordPolicyError
+		if errors.As(err, &policyErr) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": utils.MsgWeakPassword, "violations": policyErr.Violations})
+			return
+		}
+
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": utils.MsgInternalServerErr})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "Password reset"})
+}
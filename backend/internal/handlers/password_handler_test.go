@@ -0,0 +1,309 @@
+package handlers_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"todo-app/internal/handlers"
+	"todo-app/internal/services"
+	mock_services "todo-app/internal/services/_mock"
+	"todo-app/internal/utils"
+	"todo-app/internal/utils/testutils"
+
+	"github.com/gin-contrib/sessions"
+	"github.com/gin-contrib/sessions/memstore"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+type passwordTestSetup struct {
+	ctrl                *gomock.Controller
+	mockPasswordService *mock_services.MockIPasswordService
+	passwordHandler     *handlers.PasswordHandler
+	router              *gin.Engine
+	recorder            *httptest.ResponseRecorder
+	context             *gin.Context
+}
+
+func setupPasswordTest(t *testing.T, setUserIDInCtx bool) *passwordTestSetup {
+	ctrl := gomock.NewController(t)
+	mockPasswordService := mock_services.NewMockIPasswordService(ctrl)
+	passwordHandler := handlers.NewPasswordHandler(mockPasswordService)
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	ctx, r := gin.CreateTestContext(w)
+
+	store := memstore.NewStore([]byte("secret"))
+	r.Use(sessions.Sessions("mysession", store))
+
+	if setUserIDInCtx {
+		ctx.Set("userID", uIDStr)
+		r.Use(func(c *gin.Context) {
+			c.Set("userID", uIDStr)
+			c.Next()
+		})
+	}
+
+	return &passwordTestSetup{
+		ctrl:                ctrl,
+		mockPasswordService: mockPasswordService,
+		passwordHandler:     passwordHandler,
+		router:              r,
+		recorder:            w,
+		context:             ctx,
+	}
+}
+
+var mockPolicyErr = &services.PasswordPolicyError{Violations: []services.PasswordViolation{
+	{Code: services.PasswordViolationTooShort, Message: "Password must be at least 8 characters long"},
+}}
+
+func TestPasswordHandler_ChangeMyPassword(t *testing.T) {
+	tests := []struct {
+		name           string
+		reqFile        string
+		want           want
+		setUserIDInCtx bool
+	}{
+		{
+			name:    "successful change password",
+			reqFile: "testdata/change_my_password/200_req.json.golden",
+			want: want{
+				status:   http.StatusOK,
+				respFile: "testdata/change_my_password/200_resp.json.golden",
+			},
+			setUserIDInCtx: true,
+		},
+		{
+			name:    "invalid request body",
+			reqFile: "testdata/change_my_password/400_req.json.golden",
+			want: want{
+				status:   http.StatusBadRequest,
+				respFile: "testdata/change_my_password/400_resp.json.golden",
+			},
+			setUserIDInCtx: true,
+		},
+		{
+			name:    "wrong current password",
+			reqFile: "testdata/change_my_password/200_req.json.golden",
+			want: want{
+				status:   http.StatusBadRequest,
+				respFile: "testdata/change_my_password/400_wrong_current_password_resp.json.golden",
+			},
+			setUserIDInCtx: true,
+		},
+		{
+			name:    "weak password",
+			reqFile: "testdata/change_my_password/400_weak_password_req.json.golden",
+			want: want{
+				status:   http.StatusBadRequest,
+				respFile: "testdata/change_my_password/400_weak_password_resp.json.golden",
+			},
+			setUserIDInCtx: true,
+		},
+		{
+			name:    "failed to get userID from context",
+			reqFile: "testdata/change_my_password/200_req.json.golden",
+			want: want{
+				status:   http.StatusUnauthorized,
+				respFile: "testdata/change_my_password/401_resp.json.golden",
+			},
+			setUserIDInCtx: false,
+		},
+		{
+			name:    "internal server error",
+			reqFile: "testdata/change_my_password/200_req.json.golden",
+			want: want{
+				status:   http.StatusInternalServerError,
+				respFile: "testdata/change_my_password/500_resp.json.golden",
+			},
+			setUserIDInCtx: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setup := setupPasswordTest(t, tt.setUserIDInCtx)
+			defer setup.ctrl.Finish()
+
+			// ChangePassword service won't be called when request body is invalid or userID is missing
+			if tt.name != "invalid request body" && tt.setUserIDInCtx {
+				setup.mockPasswordService.EXPECT().ChangePassword(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, userID pgtype.UUID, sessionID string, req services.ChangePasswordRequest) error {
+					switch tt.name {
+					case "successful change password":
+						return nil
+					case "wrong current password":
+						return utils.ErrInvalidCurrentPassword
+					case "weak password":
+						return mockPolicyErr
+					}
+					return errors.New("unexpected error")
+				})
+			}
+
+			setup.context.Request = httptest.NewRequest(http.MethodPut, "/me/password", bytes.NewReader(testutils.LoadFile(t, tt.reqFile)))
+			setup.context.Request.Header.Set("Content-Type", "application/json")
+			setup.router.PUT("/me/password", setup.passwordHandler.ChangeMyPassword)
+			setup.router.ServeHTTP(setup.recorder, setup.context.Request)
+
+			testutils.AssertResponse(t, setup.recorder.Result(), tt.want.status, testutils.LoadFile(t, tt.want.respFile))
+		})
+	}
+}
+
+func TestPasswordHandler_ForgotPassword(t *testing.T) {
+	tests := []struct {
+		name    string
+		reqFile string
+		want    want
+	}{
+		{
+			name:    "successful forgot password",
+			reqFile: "testdata/forgot_password/200_req.json.golden",
+			want: want{
+				status:   http.StatusOK,
+				respFile: "testdata/forgot_password/200_resp.json.golden",
+			},
+		},
+		{
+			name:    "invalid request body",
+			reqFile: "testdata/forgot_password/400_req.json.golden",
+			want: want{
+				status:   http.StatusBadRequest,
+				respFile: "testdata/forgot_password/400_resp.json.golden",
+			},
+		},
+		{
+			name:    "too many requests",
+			reqFile: "testdata/forgot_password/200_req.json.golden",
+			want: want{
+				status:   http.StatusTooManyRequests,
+				respFile: "testdata/forgot_password/429_resp.json.golden",
+			},
+		},
+		{
+			name:    "internal server error",
+			reqFile: "testdata/forgot_password/200_req.json.golden",
+			want: want{
+				status:   http.StatusInternalServerError,
+				respFile: "testdata/forgot_password/500_resp.json.golden",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setup := setupPasswordTest(t, false)
+			defer setup.ctrl.Finish()
+
+			// ForgotPassword service won't be called when request body is invalid
+			if tt.name != "invalid request body" {
+				setup.mockPasswordService.EXPECT().ForgotPassword(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, req services.ForgotPasswordRequest, client services.ClientInfo) error {
+					switch tt.want.status {
+					case http.StatusOK:
+						return nil
+					case http.StatusTooManyRequests:
+						return &services.LoginLockedError{RetryAfter: 29500 * time.Millisecond}
+					}
+					return errors.New("unexpected error")
+				})
+			}
+
+			setup.context.Request = httptest.NewRequest(http.MethodPost, "/password/forgot", bytes.NewReader(testutils.LoadFile(t, tt.reqFile)))
+			setup.context.Request.Header.Set("Content-Type", "application/json")
+			setup.router.POST("/password/forgot", setup.passwordHandler.ForgotPassword)
+			setup.router.ServeHTTP(setup.recorder, setup.context.Request)
+
+			testutils.AssertResponse(t, setup.recorder.Result(), tt.want.status, testutils.LoadFile(t, tt.want.respFile))
+
+			if tt.want.status == http.StatusTooManyRequests {
+				assert.Equal(t, "30", setup.recorder.Header().Get("Retry-After"))
+			}
+		})
+	}
+}
+
+func TestPasswordHandler_ResetPassword(t *testing.T) {
+	tests := []struct {
+		name    string
+		reqFile string
+		want    want
+	}{
+		{
+			name:    "successful reset password",
+			reqFile: "testdata/reset_password/200_req.json.golden",
+			want: want{
+				status:   http.StatusOK,
+				respFile: "testdata/reset_password/200_resp.json.golden",
+			},
+		},
+		{
+			name:    "invalid request body",
+			reqFile: "testdata/reset_password/400_req.json.golden",
+			want: want{
+				status:   http.StatusBadRequest,
+				respFile: "testdata/reset_password/400_resp.json.golden",
+			},
+		},
+		{
+			name:    "invalid reset token",
+			reqFile: "testdata/reset_password/200_req.json.golden",
+			want: want{
+				status:   http.StatusBadRequest,
+				respFile: "testdata/reset_password/400_invalid_token_resp.json.golden",
+			},
+		},
+		{
+			name:    "weak password",
+			reqFile: "testdata/reset_password/400_weak_password_req.json.golden",
+			want: want{
+				status:   http.StatusBadRequest,
+				respFile: "testdata/reset_password/400_weak_password_resp.json.golden",
+			},
+		},
+		{
+			name:    "internal server error",
+			reqFile: "testdata/reset_password/200_req.json.golden",
+			want: want{
+				status:   http.StatusInternalServerError,
+				respFile: "testdata/reset_password/500_resp.json.golden",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setup := setupPasswordTest(t, false)
+			defer setup.ctrl.Finish()
+
+			// ResetPassword service won't be called when request body is invalid
+			if tt.name != "invalid request body" {
+				setup.mockPasswordService.EXPECT().ResetPassword(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, req services.ResetPasswordRequest) error {
+					switch tt.name {
+					case "successful reset password":
+						return nil
+					case "invalid reset token":
+						return utils.ErrInvalidResetToken
+					case "weak password":
+						return mockPolicyErr
+					}
+					return errors.New("unexpected error")
+				})
+			}
+
+			setup.context.Request = httptest.NewRequest(http.MethodPost, "/password/reset", bytes.NewReader(testutils.LoadFile(t, tt.reqFile)))
+			setup.context.Request.Header.Set("Content-Type", "application/json")
+			setup.router.POST("/password/reset", setup.passwordHandler.ResetPassword)
+			setup.router.ServeHTTP(setup.recorder, setup.context.Request)
+
+			testutils.AssertResponse(t, setup.recorder.Result(), tt.want.status, testutils.LoadFile(t, tt.want.respFile))
+		})
+	}
+}
@@ -0,0 +1,152 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"todo-app/internal/db"
+	"todo-app/internal/services"
+	"todo-app/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PersonalAccessTokenHandler struct {
+	PersonalAccessTokenService services.IPersonalAccessTokenService
+}
+
+// The token hash is never returned
+type PersonalAccessTokenResponse struct {
+	ID         int32      `json:"id"`
+	Name       string     `json:"name"`
+	Scopes     []string   `json:"scopes"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+type CreatePersonalAccessTokenResponse struct {
+	PersonalAccessTokenResponse
+	Token string `json:"token"`
+}
+
+func NewPersonalAccessTokenHandler(personalAccessTokenService services.IPersonalAccessTokenService) *PersonalAccessTokenHandler {
+	return &PersonalAccessTokenHandler{PersonalAccessTokenService: personalAccessTokenService}
+}
+
+// @Summary Create a personal access token
+// @Description The token is shown only once. Send it as `Authorization: Bearer pat_...`; it is limited to the given scopes (todos:read, todos:write).
+// @Tags Token
+// @Accept json
+// @Produce json
+// @Param token body services.CreatePersonalAccessTokenRequest true "name, scopes and lifetime of the token"
+// @Security BearerAuth
+// @Success 201 {object} CreatePersonalAccessTokenResponse
+// @Failure 400 {object} gin.H "{"error": "Invalid request"}"
+// @Failure 500 {object} gin.H "{"error": "Internal server error"}"
+// @Router /me/tokens [post]
+func (h *PersonalAccessTokenHandler) CreateMyToken(ctx *gin.Context) {
+	userIDUuid, err := utils.GetUIDFromCtxAndCreateRespUponErr(ctx)
+	if err != nil {
+		return
+	}
+
+	var req services.CreatePersonalAccessTokenRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil || strings.TrimSpace(req.Name) == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": utils.MsgInvalidReq})
+		return
+	}
+
+	token, plainToken, err := h.PersonalAccessTokenService.CreateToken(ctx, userIDUuid, req)
+	if err != nil {
+		log.Println(err.Error())
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": utils.MsgInternalServerErr})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, CreatePersonalAccessTokenResponse{
+		PersonalAccessTokenResponse: toPersonalAccessTokenResponse(*token),
+		Token:                       plainToken,
+	})
+}
+
+// @Summary List current user's personal access tokens
+// @Tags Token
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} PersonalAccessTokenResponse
+// @Failure 500 {object} gin.H "{"error": "Internal server error"}"
+// @Router /me/tokens [get]
+func (h *PersonalAccessTokenHandler) ListMyTokens(ctx *gin.Context) {
+	userIDUuid, err := utils.GetUIDFromCtxAndCreateRespUponErr(ctx)
+	if err != nil {
+		return
+	}
+
+	tokens, err := h.PersonalAccessTokenService.ListTokens(ctx, userIDUuid)
+	if err != nil {
+		log.Println(err.Error())
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": utils.MsgInternalServerErr})
+		return
+	}
+
+	tokenResponses := make([]PersonalAccessTokenResponse, len(tokens))
+	for i, token := range tokens {
+		tokenResponses[i] = toPersonalAccessTokenResponse(token)
+	}
+
+	ctx.JSON(http.StatusOK, tokenResponses)
+}
+
+// @Summary Delete one of current user's personal access tokens
+// @Tags Token
+// @Produce json
+// @Param id path int true "Token ID"
+// @Security BearerAuth
+// @Success 200 {object} gin.H "{"message": "Token deleted"}"
+// @Failure 400 {object} gin.H "{"error": "Invalid request"}"
+// @Failure 404 {object} gin.H "{"error": "Resource not found"}"
+// @Failure 500 {object} gin.H "{"error": "Internal server error"}"
+// @Router /me/tokens/{id} [delete]
+func (h *PersonalAccessTokenHandler) DeleteMyToken(ctx *gin.Context) {
+	userIDUuid, err := utils.GetUIDFromCtxAndCreateRespUponErr(ctx)
+	if err != nil {
+		return
+	}
+
+	tokenID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": utils.MsgInvalidReq})
+		return
+	}
+
+	if err := h.PersonalAccessTokenService.DeleteToken(ctx, userIDUuid, int32(tokenID)); err != nil {
+		log.Println(err.Error())
+
+		if err == utils.ErrNoRowsMatchedSQLC {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": utils.MsgResourceNotFound})
+			return
+		}
+
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": utils.MsgInternalServerErr})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "Token deleted"})
+}
+
+func toPersonalAccessTokenResponse(token db.PersonalAccessToken) PersonalAccessTokenResponse {
+	resp := PersonalAccessTokenResponse{
+		ID:        token.ID,
+		Name:      token.Name,
+		Scopes:    token.Scopes,
+		ExpiresAt: token.ExpiresAt.Time,
+		CreatedAt: token.CreatedAt.Time,
+	}
+	if token.LastUsedAt.Valid {
+		resp.LastUsedAt = &token.LastUsedAt.Time
+	}
+	return resp
+}
@@ -0,0 +1,288 @@
+package handlers_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"todo-app/internal/db"
+	"todo-app/internal/handlers"
+	"todo-app/internal/services"
+	mock_services "todo-app/internal/services/_mock"
+	"todo-app/internal/utils"
+	"todo-app/internal/utils/testutils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/mock/gomock"
+)
+
+type personalAccessTokenTestSetup struct {
+	ctrl                           *gomock.Controller
+	mockPersonalAccessTokenService *mock_services.MockIPersonalAccessTokenService
+	personalAccessTokenHandler     *handlers.PersonalAccessTokenHandler
+	router                         *gin.Engine
+	recorder                       *httptest.ResponseRecorder
+	context                        *gin.Context
+}
+
+var mockPersonalAccessToken = db.PersonalAccessToken{
+	ID:        1,
+	Name:      "ci",
+	Scopes:    []string{services.ScopeTodosRead, services.ScopeTodosWrite},
+	ExpiresAt: pgtype.Timestamptz{Time: mockTime.AddDate(0, 0, 30), Valid: true},
+	CreatedAt: pgtype.Timestamptz{Time: mockTime, Valid: true},
+}
+
+func setupPersonalAccessTokenTest(t *testing.T, setUserIDInCtx bool) *personalAccessTokenTestSetup {
+	ctrl := gomock.NewController(t)
+	mockPersonalAccessTokenService := mock_services.NewMockIPersonalAccessTokenService(ctrl)
+	personalAccessTokenHandler := handlers.NewPersonalAccessTokenHandler(mockPersonalAccessTokenService)
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	ctx, r := gin.CreateTestContext(w)
+
+	if setUserIDInCtx {
+		ctx.Set("userID", uIDStr)
+		r.Use(func(c *gin.Context) {
+			c.Set("userID", uIDStr)
+			c.Next()
+		})
+	}
+
+	return &personalAccessTokenTestSetup{
+		ctrl:                           ctrl,
+		mockPersonalAccessTokenService: mockPersonalAccessTokenService,
+		personalAccessTokenHandler:     personalAccessTokenHandler,
+		router:                         r,
+		recorder:                       w,
+		context:                        ctx,
+	}
+}
+
+func TestPersonalAccessTokenHandler_CreateMyToken(t *testing.T) {
+	tests := []struct {
+		name           string
+		reqFile        string
+		want           want
+		setUserIDInCtx bool
+	}{
+		{
+			name:    "successful create token",
+			reqFile: "testdata/create_my_token/201_req.json.golden",
+			want: want{
+				status:   http.StatusCreated,
+				respFile: "testdata/create_my_token/201_resp.json.golden",
+			},
+			setUserIDInCtx: true,
+		},
+		{
+			name:    "invalid request body",
+			reqFile: "testdata/create_my_token/400_req.json.golden",
+			want: want{
+				status:   http.StatusBadRequest,
+				respFile: "testdata/create_my_token/400_resp.json.golden",
+			},
+			setUserIDInCtx: true,
+		},
+		{
+			name:    "failed to get userID from context",
+			reqFile: "testdata/create_my_token/201_req.json.golden",
+			want: want{
+				status:   http.StatusUnauthorized,
+				respFile: "testdata/create_my_token/401_resp.json.golden",
+			},
+			setUserIDInCtx: false,
+		},
+		{
+			name:    "internal server error",
+			reqFile: "testdata/create_my_token/201_req.json.golden",
+			want: want{
+				status:   http.StatusInternalServerError,
+				respFile: "testdata/create_my_token/500_resp.json.golden",
+			},
+			setUserIDInCtx: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setup := setupPersonalAccessTokenTest(t, tt.setUserIDInCtx)
+			defer setup.ctrl.Finish()
+
+			// CreateToken service won't be called when userID is not in context or request body is invalid
+			if tt.setUserIDInCtx && tt.want.status != http.StatusBadRequest {
+				setup.mockPersonalAccessTokenService.EXPECT().CreateToken(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, userID pgtype.UUID, req services.CreatePersonalAccessTokenRequest) (*db.PersonalAccessToken, string, error) {
+					switch tt.want.status {
+					case http.StatusCreated:
+						return &mockPersonalAccessToken, "pat_token-123", nil
+					case http.StatusInternalServerError:
+						return nil, "", errors.New("unexpected error")
+					}
+					return nil, "", errors.New("error from mock")
+				})
+			}
+
+			setup.context.Request = httptest.NewRequest(http.MethodPost, "/me/tokens", bytes.NewReader(testutils.LoadFile(t, tt.reqFile)))
+			setup.context.Request.Header.Set("Content-Type", "application/json")
+			setup.router.POST("/me/tokens", setup.personalAccessTokenHandler.CreateMyToken)
+			setup.router.ServeHTTP(setup.recorder, setup.context.Request)
+
+			testutils.AssertResponse(t, setup.recorder.Result(), tt.want.status, testutils.LoadFile(t, tt.want.respFile))
+		})
+	}
+}
+
+func TestPersonalAccessTokenHandler_ListMyTokens(t *testing.T) {
+	tests := []struct {
+		name           string
+		want           want
+		setUserIDInCtx bool
+	}{
+		{
+			name: "successful list tokens",
+			want: want{
+				status:   http.StatusOK,
+				respFile: "testdata/list_my_tokens/200_resp.json.golden",
+			},
+			setUserIDInCtx: true,
+		},
+		{
+			name: "failed to get userID from context",
+			want: want{
+				status:   http.StatusUnauthorized,
+				respFile: "testdata/list_my_tokens/401_resp.json.golden",
+			},
+			setUserIDInCtx: false,
+		},
+		{
+			name: "internal server error",
+			want: want{
+				status:   http.StatusInternalServerError,
+				respFile: "testdata/list_my_tokens/500_resp.json.golden",
+			},
+			setUserIDInCtx: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setup := setupPersonalAccessTokenTest(t, tt.setUserIDInCtx)
+			defer setup.ctrl.Finish()
+
+			if tt.setUserIDInCtx {
+				setup.mockPersonalAccessTokenService.EXPECT().ListTokens(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, userID pgtype.UUID) ([]db.PersonalAccessToken, error) {
+					switch tt.want.status {
+					case http.StatusOK:
+						return []db.PersonalAccessToken{
+							{
+								ID:         2,
+								Name:       "deploy",
+								Scopes:     []string{services.ScopeTodosRead},
+								ExpiresAt:  pgtype.Timestamptz{Time: mockTime.AddDate(0, 0, 30), Valid: true},
+								LastUsedAt: pgtype.Timestamptz{Time: mockTime, Valid: true},
+								CreatedAt:  pgtype.Timestamptz{Time: mockTime, Valid: true},
+							},
+							mockPersonalAccessToken,
+						}, nil
+					case http.StatusInternalServerError:
+						return nil, errors.New("unexpected error")
+					}
+					return nil, errors.New("error from mock")
+				})
+			}
+
+			setup.context.Request = httptest.NewRequest(http.MethodGet, "/me/tokens", nil)
+			setup.router.GET("/me/tokens", setup.personalAccessTokenHandler.ListMyTokens)
+			setup.router.ServeHTTP(setup.recorder, setup.context.Request)
+
+			testutils.AssertResponse(t, setup.recorder.Result(), tt.want.status, testutils.LoadFile(t, tt.want.respFile))
+		})
+	}
+}
+
+func TestPersonalAccessTokenHandler_DeleteMyToken(t *testing.T) {
+	tests := []struct {
+		name           string
+		tokenID        string
+		want           want
+		setUserIDInCtx bool
+	}{
+		{
+			name:    "successful delete token",
+			tokenID: "1",
+			want: want{
+				status:   http.StatusOK,
+				respFile: "testdata/delete_my_token/200_resp.json.golden",
+			},
+			setUserIDInCtx: true,
+		},
+		{
+			name:    "invalid token ID",
+			tokenID: "abc",
+			want: want{
+				status:   http.StatusBadRequest,
+				respFile: "testdata/delete_my_token/400_resp.json.golden",
+			},
+			setUserIDInCtx: true,
+		},
+		{
+			name:    "failed to get userID from context",
+			tokenID: "1",
+			want: want{
+				status:   http.StatusUnauthorized,
+				respFile: "testdata/delete_my_token/401_resp.json.golden",
+			},
+			setUserIDInCtx: false,
+		},
+		{
+			name:    "token not found",
+			tokenID: "99",
+			want: want{
+				status:   http.StatusNotFound,
+				respFile: "testdata/delete_my_token/404_resp.json.golden",
+			},
+			setUserIDInCtx: true,
+		},
+		{
+			name:    "internal server error",
+			tokenID: "1",
+			want: want{
+				status:   http.StatusInternalServerError,
+				respFile: "testdata/delete_my_token/500_resp.json.golden",
+			},
+			setUserIDInCtx: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setup := setupPersonalAccessTokenTest(t, tt.setUserIDInCtx)
+			defer setup.ctrl.Finish()
+
+			// DeleteToken service won't be called when userID is not in context or the token ID is invalid
+			if tt.setUserIDInCtx && tt.want.status != http.StatusBadRequest {
+				setup.mockPersonalAccessTokenService.EXPECT().DeleteToken(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, userID pgtype.UUID, tokenID int32) error {
+					switch tt.want.status {
+					case http.StatusOK:
+						return nil
+					case http.StatusNotFound:
+						return utils.ErrNoRowsMatchedSQLC
+					case http.StatusInternalServerError:
+						return errors.New("unexpected error")
+					}
+					return errors.New("error from mock")
+				})
+			}
+
+			setup.context.Request = httptest.NewRequest(http.MethodDelete, "/me/tokens/"+tt.tokenID, nil)
+			setup.router.DELETE("/me/tokens/:id", setup.personalAccessTokenHandler.DeleteMyToken)
+			setup.router.ServeHTTP(setup.recorder, setup.context.Request)
+
+			testutils.AssertResponse(t, setup.recorder.Result(), tt.want.status, testutils.LoadFile(t, tt.want.respFile))
+		})
+	}
+}
@@ -0,0 +1,227 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"time"
+	"todo-app/internal/db"
+	"todo-app/internal/services"
+	"todo-app/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PreferencesHandler struct {
+	PreferencesService services.IPreferencesService
+}
+
+type PreferencesResponse struct {
+	DisplayName     *string `json:"display_name"`
+	TimeZone        string  `json:"time_zone"`
+	Locale          string  `json:"locale"`
+	DefaultTodoSort string  `json:"default_todo_sort"`
+	WeekStart       string  `json:"week_start"`
+	// Set when the user has uploaded an avatar
+	AvatarURL *string    `json:"avatar_url"`
+	UpdatedAt *time.Time `json:"updated_at"`
+}
+
+const avatarURL = "/api/v1/me/preferences/avatar"
+
+func NewPreferencesHandler(preferencesService services.IPreferencesService) *PreferencesHandler {
+	return &PreferencesHandler{PreferencesService: preferencesService}
+}
+
+// @Summary Get current user's preferences
+// @Description Preferences the user has not set have their defaults.
+// @Tags User
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} PreferencesResponse
+// @Failure 500 {object} gin.H "{"error": "Internal server error"}"
+// @Router /me/preferences [get]
+func (h *PreferencesHandler) GetMyPreferences(ctx *gin.Context) {
+	userIDUuid, err := utils.GetUIDFromCtxAndCreateRespUponErr(ctx)
+	if err != nil {
+		return
+	}
+
+	prefs, err := h.PreferencesService.GetPreferences(ctx, userIDUuid)
+	if err != nil {
+		log.Println(err.Error())
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": utils.MsgInternalServerErr})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, toPreferencesResponse(prefs))
+}
+
+// @Summary Update current user's preferences
+// @Description The body is a JSON Merge Patch: only the given preferences are changed and null resets a preference to its default.
+// @Tags User
+// @Accept json
+// @Produce json
+// @Param preferences body object true "e.g. {"time_zone": "Europe/Berlin", "display_name": null}"
+// @Security BearerAuth
+// @Success 200 {object} PreferencesResponse
+// @Failure 400 {object} gin.H "{"error": "Invalid request"} or {"error": "Preferences are invalid", "violations": [...]}"
+// @Failure 500 {object} gin.H "{"error": "Internal server error"}"
+// @Router /me/preferences [patch]
+func (h *PreferencesHandler) UpdateMyPreferences(ctx *gin.Context) {
+	userIDUuid, err := utils.GetUIDFromCtxAndCreateRespUponErr(ctx)
+	if err != nil {
+		return
+	}
+
+	// A merge patch has to be an object; anything else would replace the preferences as a whole
+	var patch map[string]json.RawMessage
+	if err := json.NewDecoder(ctx.Request.Body).Decode(&patch); err != nil || patch == nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": utils.MsgInvalidReq})
+		return
+	}
+
+	prefs, err := h.PreferencesService.UpdatePreferences(ctx, userIDUuid, patch)
+	if err != nil {
+		log.Println(err.Error())
+
+		var validationErr *services.PreferencesValidationError
+		if errors.As(err, &validationErr) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": utils.MsgInvalidPreferences, "violations": validationErr.Violations})
+			return
+		}
+
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": utils.MsgInternalServerErr})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, toPreferencesResponse(prefs))
+}
+
+// @Summary Upload an avatar
+// @Description The body is the image itself. Replaces the current avatar.
+// @Tags User
+// @Accept png,jpeg,gif
+// @Produce json
+// @Param avatar body []byte true "PNG, JPEG or GIF image"
+// @Security BearerAuth
+// @Success 200 {object} PreferencesResponse
+// @Failure 400 {object} gin.H "{"error": "Avatar must be a PNG, JPEG or GIF image"}"
+// @Failure 413 {object} gin.H "{"error": "Avatar image is too large"}"
+// @Failure 500 {object} gin.H "{"error": "Internal server error"}"
+// @Router /me/preferences/avatar [put]
+func (h *PreferencesHandler) PutMyAvatar(ctx *gin.Context) {
+	userIDUuid, err := utils.GetUIDFromCtxAndCreateRespUponErr(ctx)
+	if err != nil {
+		return
+	}
+
+	prefs, err := h.PreferencesService.PutAvatar(ctx, userIDUuid, ctx.Request.Body)
+	if err != nil {
+		log.Println(err.Error())
+
+		if err == utils.ErrInvalidAvatar {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": utils.MsgInvalidAvatar})
+			return
+		}
+
+		if err == utils.ErrAvatarTooLarge {
+			ctx.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": utils.MsgAvatarTooLarge})
+			return
+		}
+
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": utils.MsgInternalServerErr})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, toPreferencesResponse(prefs))
+}
+
+// @Summary Get current user's avatar
+// @Tags User
+// @Produce png,jpeg,gif
+// @Security BearerAuth
+// @Success 200 {file} file "avatar image"
+// @Failure 404 {object} gin.H "{"error": "Resource not found"}"
+// @Failure 500 {object} gin.H "{"error": "Internal server error"}"
+// @Router /me/preferences/avatar [get]
+func (h *PreferencesHandler) GetMyAvatar(ctx *gin.Context) {
+	userIDUuid, err := utils.GetUIDFromCtxAndCreateRespUponErr(ctx)
+	if err != nil {
+		return
+	}
+
+	avatar, contentType, err := h.PreferencesService.GetAvatar(ctx, userIDUuid)
+	if err != nil {
+		log.Println(err.Error())
+
+		if err == utils.ErrNoRowsMatchedSQLC {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": utils.MsgResourceNotFound})
+			return
+		}
+
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": utils.MsgInternalServerErr})
+		return
+	}
+	defer avatar.Close()
+
+	ctx.Header("Content-Type", contentType)
+	ctx.Header("X-Content-Type-Options", "nosniff")
+	// The avatar can be replaced at any time under the same URL
+	ctx.Header("Cache-Control", "private, no-cache")
+	ctx.Status(http.StatusOK)
+	if _, err := io.Copy(ctx.Writer, avatar); err != nil {
+		log.Println(err.Error())
+	}
+}
+
+// @Summary Delete current user's avatar
+// @Tags User
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} gin.H "{"message": "Avatar deleted"}"
+// @Failure 404 {object} gin.H "{"error": "Resource not found"}"
+// @Failure 500 {object} gin.H "{"error": "Internal server error"}"
+// @Router /me/preferences/avatar [delete]
+func (h *PreferencesHandler) DeleteMyAvatar(ctx *gin.Context) {
+	userIDUuid, err := utils.GetUIDFromCtxAndCreateRespUponErr(ctx)
+	if err != nil {
+		return
+	}
+
+	if err := h.PreferencesService.DeleteAvatar(ctx, userIDUuid); err != nil {
+		log.Println(err.Error())
+
+		if err == utils.ErrNoRowsMatchedSQLC {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": utils.MsgResourceNotFound})
+			return
+		}
+
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": utils.MsgInternalServerErr})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "Avatar deleted"})
+}
+
+func toPreferencesResponse(prefs *db.UserPreference) PreferencesResponse {
+	resp := PreferencesResponse{
+		TimeZone:        prefs.TimeZone,
+		Locale:          prefs.Locale,
+		DefaultTodoSort: prefs.DefaultTodoSort,
+		WeekStart:       prefs.WeekStart,
+	}
+	if prefs.DisplayName.Valid {
+		resp.DisplayName = &prefs.DisplayName.String
+	}
+	if prefs.AvatarKey.Valid {
+		url := avatarURL
+		resp.AvatarURL = &url
+	}
+	if prefs.UpdatedAt.Valid {
+		resp.UpdatedAt = &prefs.UpdatedAt.Time
+	}
+	return resp
+}
@@ -0,0 +1,443 @@
+package handlers_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"todo-app/internal/db"
+	"todo-app/internal/handlers"
+	"todo-app/internal/services"
+	mock_services "todo-app/internal/services/_mock"
+	"todo-app/internal/utils"
+	"todo-app/internal/utils/testutils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+type preferencesTestSetup struct {
+	ctrl                   *gomock.Controller
+	mockPreferencesService *mock_services.MockIPreferencesService
+	preferencesHandler     *handlers.PreferencesHandler
+	router                 *gin.Engine
+	recorder               *httptest.ResponseRecorder
+	context                *gin.Context
+}
+
+var mockPreferences = db.UserPreference{
+	UserID:          1,
+	DisplayName:     pgtype.Text{String: "Taro", Valid: true},
+	TimeZone:        "Asia/Tokyo",
+	Locale:          "ja",
+	DefaultTodoSort: "position",
+	WeekStart:       services.WeekStartSunday,
+	AvatarKey:       pgtype.Text{String: "avatars/" + uIDStr, Valid: true},
+	UpdatedAt:       pgtype.Timestamptz{Time: mockTime, Valid: true},
+}
+
+func setupPreferencesTest(t *testing.T, setUserIDInCtx bool) *preferencesTestSetup {
+	ctrl := gomock.NewController(t)
+	mockPreferencesService := mock_services.NewMockIPreferencesService(ctrl)
+	preferencesHandler := handlers.NewPreferencesHandler(mockPreferencesService)
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	ctx, r := gin.CreateTestContext(w)
+
+	if setUserIDInCtx {
+		ctx.Set("userID", uIDStr)
+		r.Use(func(c *gin.Context) {
+			c.Set("userID", uIDStr)
+			c.Next()
+		})
+	}
+
+	return &preferencesTestSetup{
+		ctrl:                   ctrl,
+		mockPreferencesService: mockPreferencesService,
+		preferencesHandler:     preferencesHandler,
+		router:                 r,
+		recorder:               w,
+		context:                ctx,
+	}
+}
+
+func TestPreferencesHandler_GetMyPreferences(t *testing.T) {
+	tests := []struct {
+		name           string
+		want           want
+		setUserIDInCtx bool
+	}{
+		{
+			name: "successful get preferences",
+			want: want{
+				status:   http.StatusOK,
+				respFile: "testdata/get_my_preferences/200_resp.json.golden",
+			},
+			setUserIDInCtx: true,
+		},
+		{
+			name: "defaults",
+			want: want{
+				status:   http.StatusOK,
+				respFile: "testdata/get_my_preferences/200_defaults_resp.json.golden",
+			},
+			setUserIDInCtx: true,
+		},
+		{
+			name: "failed to get userID from context",
+			want: want{
+				status:   http.StatusUnauthorized,
+				respFile: "testdata/get_my_preferences/401_resp.json.golden",
+			},
+			setUserIDInCtx: false,
+		},
+		{
+			name: "internal server error",
+			want: want{
+				status:   http.StatusInternalServerError,
+				respFile: "testdata/get_my_preferences/500_resp.json.golden",
+			},
+			setUserIDInCtx: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setup := setupPreferencesTest(t, tt.setUserIDInCtx)
+			defer setup.ctrl.Finish()
+
+			// GetPreferences service won't be called when userID is not in context
+			if tt.setUserIDInCtx {
+				setup.mockPreferencesService.EXPECT().GetPreferences(gomock.Any(), uIDUuid).DoAndReturn(func(ctx context.Context, userID pgtype.UUID) (*db.UserPreference, error) {
+					switch tt.name {
+					case "successful get preferences":
+						return &mockPreferences, nil
+					case "defaults":
+						return &db.UserPreference{UserID: 1, TimeZone: "UTC", Locale: "en", DefaultTodoSort: "position", WeekStart: services.WeekStartMonday}, nil
+					}
+					return nil, errors.New("unexpected error")
+				})
+			}
+
+			setup.context.Request = httptest.NewRequest(http.MethodGet, "/me/preferences", nil)
+			setup.router.GET("/me/preferences", setup.preferencesHandler.GetMyPreferences)
+			setup.router.ServeHTTP(setup.recorder, setup.context.Request)
+
+			testutils.AssertResponse(t, setup.recorder.Result(), tt.want.status, testutils.LoadFile(t, tt.want.respFile))
+		})
+	}
+}
+
+func TestPreferencesHandler_UpdateMyPreferences(t *testing.T) {
+	tests := []struct {
+		name           string
+		reqFile        string
+		want           want
+		setUserIDInCtx bool
+	}{
+		{
+			name:    "successful update preferences",
+			reqFile: "testdata/update_my_preferences/200_req.json.golden",
+			want: want{
+				status:   http.StatusOK,
+				respFile: "testdata/update_my_preferences/200_resp.json.golden",
+			},
+			setUserIDInCtx: true,
+		},
+		{
+			name:    "patch is not an object",
+			reqFile: "testdata/update_my_preferences/400_req.json.golden",
+			want: want{
+				status:   http.StatusBadRequest,
+				respFile: "testdata/update_my_preferences/400_resp.json.golden",
+			},
+			setUserIDInCtx: true,
+		},
+		{
+			name:    "invalid preferences",
+			reqFile: "testdata/update_my_preferences/400_invalid_preferences_req.json.golden",
+			want: want{
+				status:   http.StatusBadRequest,
+				respFile: "testdata/update_my_preferences/400_invalid_preferences_resp.json.golden",
+			},
+			setUserIDInCtx: true,
+		},
+		{
+			name:    "failed to get userID from context",
+			reqFile: "testdata/update_my_preferences/200_req.json.golden",
+			want: want{
+				status:   http.StatusUnauthorized,
+				respFile: "testdata/update_my_preferences/401_resp.json.golden",
+			},
+			setUserIDInCtx: false,
+		},
+		{
+			name:    "internal server error",
+			reqFile: "testdata/update_my_preferences/200_req.json.golden",
+			want: want{
+				status:   http.StatusInternalServerError,
+				respFile: "testdata/update_my_preferences/500_resp.json.golden",
+			},
+			setUserIDInCtx: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setup := setupPreferencesTest(t, tt.setUserIDInCtx)
+			defer setup.ctrl.Finish()
+
+			// UpdatePreferences service won't be called when the patch is not an object or userID is missing
+			if tt.name != "patch is not an object" && tt.setUserIDInCtx {
+				setup.mockPreferencesService.EXPECT().UpdatePreferences(gomock.Any(), uIDUuid, gomock.Any()).DoAndReturn(func(ctx context.Context, userID pgtype.UUID, patch map[string]json.RawMessage) (*db.UserPreference, error) {
+					switch tt.name {
+					case "successful update preferences":
+						// null must reach the service, as it resets the preference
+						assert.JSONEq(t, `null`, string(patch["locale"]))
+						return &mockPreferences, nil
+					case "invalid preferences":
+						return nil, &services.PreferencesValidationError{Violations: []services.PreferenceViolation{
+							{Field: "time_zone", Message: "Must be an IANA time zone name such as Europe/Berlin"},
+						}}
+					}
+					return nil, errors.New("unexpected error")
+				})
+			}
+
+			setup.context.Request = httptest.NewRequest(http.MethodPatch, "/me/preferences", bytes.NewReader(testutils.LoadFile(t, tt.reqFile)))
+			setup.context.Request.Header.Set("Content-Type", "application/merge-patch+json")
+			setup.router.PATCH("/me/preferences", setup.preferencesHandler.UpdateMyPreferences)
+			setup.router.ServeHTTP(setup.recorder, setup.context.Request)
+
+			testutils.AssertResponse(t, setup.recorder.Result(), tt.want.status, testutils.LoadFile(t, tt.want.respFile))
+		})
+	}
+}
+
+func TestPreferencesHandler_PutMyAvatar(t *testing.T) {
+	tests := []struct {
+		name           string
+		want           want
+		setUserIDInCtx bool
+	}{
+		{
+			name: "successful upload",
+			want: want{
+				status:   http.StatusOK,
+				respFile: "testdata/put_my_avatar/200_resp.json.golden",
+			},
+			setUserIDInCtx: true,
+		},
+		{
+			name: "not an image",
+			want: want{
+				status:   http.StatusBadRequest,
+				respFile: "testdata/put_my_avatar/400_resp.json.golden",
+			},
+			setUserIDInCtx: true,
+		},
+		{
+			name: "failed to get userID from context",
+			want: want{
+				status:   http.StatusUnauthorized,
+				respFile: "testdata/put_my_avatar/401_resp.json.golden",
+			},
+			setUserIDInCtx: false,
+		},
+		{
+			name: "too large",
+			want: want{
+				status:   http.StatusRequestEntityTooLarge,
+				respFile: "testdata/put_my_avatar/413_resp.json.golden",
+			},
+			setUserIDInCtx: true,
+		},
+		{
+			name: "internal server error",
+			want: want{
+				status:   http.StatusInternalServerError,
+				respFile: "testdata/put_my_avatar/500_resp.json.golden",
+			},
+			setUserIDInCtx: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setup := setupPreferencesTest(t, tt.setUserIDInCtx)
+			defer setup.ctrl.Finish()
+
+			// PutAvatar service won't be called when userID is not in context
+			if tt.setUserIDInCtx {
+				setup.mockPreferencesService.EXPECT().PutAvatar(gomock.Any(), uIDUuid, gomock.Any()).DoAndReturn(func(ctx context.Context, userID pgtype.UUID, r io.Reader) (*db.UserPreference, error) {
+					switch tt.name {
+					case "successful upload":
+						return &mockPreferences, nil
+					case "not an image":
+						return nil, utils.ErrInvalidAvatar
+					case "too large":
+						return nil, utils.ErrAvatarTooLarge
+					}
+					return nil, errors.New("unexpected error")
+				})
+			}
+
+			setup.context.Request = httptest.NewRequest(http.MethodPut, "/me/preferences/avatar", strings.NewReader("image"))
+			setup.context.Request.Header.Set("Content-Type", "image/png")
+			setup.router.PUT("/me/preferences/avatar", setup.preferencesHandler.PutMyAvatar)
+			setup.router.ServeHTTP(setup.recorder, setup.context.Request)
+
+			testutils.AssertResponse(t, setup.recorder.Result(), tt.want.status, testutils.LoadFile(t, tt.want.respFile))
+		})
+	}
+}
+
+func TestPreferencesHandler_GetMyAvatar(t *testing.T) {
+	tests := []struct {
+		name           string
+		want           want
+		setUserIDInCtx bool
+	}{
+		{
+			name: "successful get avatar",
+			want: want{
+				status: http.StatusOK,
+			},
+			setUserIDInCtx: true,
+		},
+		{
+			name: "failed to get userID from context",
+			want: want{
+				status:   http.StatusUnauthorized,
+				respFile: "testdata/get_my_avatar/401_resp.json.golden",
+			},
+			setUserIDInCtx: false,
+		},
+		{
+			name: "no avatar",
+			want: want{
+				status:   http.StatusNotFound,
+				respFile: "testdata/get_my_avatar/404_resp.json.golden",
+			},
+			setUserIDInCtx: true,
+		},
+		{
+			name: "internal server error",
+			want: want{
+				status:   http.StatusInternalServerError,
+				respFile: "testdata/get_my_avatar/500_resp.json.golden",
+			},
+			setUserIDInCtx: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setup := setupPreferencesTest(t, tt.setUserIDInCtx)
+			defer setup.ctrl.Finish()
+
+			// GetAvatar service won't be called when userID is not in context
+			if tt.setUserIDInCtx {
+				setup.mockPreferencesService.EXPECT().GetAvatar(gomock.Any(), uIDUuid).DoAndReturn(func(ctx context.Context, userID pgtype.UUID) (io.ReadCloser, string, error) {
+					switch tt.name {
+					case "successful get avatar":
+						return io.NopCloser(strings.NewReader("gif-content")), "image/gif", nil
+					case "no avatar":
+						return nil, "", utils.ErrNoRowsMatchedSQLC
+					}
+					return nil, "", errors.New("unexpected error")
+				})
+			}
+
+			setup.context.Request = httptest.NewRequest(http.MethodGet, "/me/preferences/avatar", nil)
+			setup.router.GET("/me/preferences/avatar", setup.preferencesHandler.GetMyAvatar)
+			setup.router.ServeHTTP(setup.recorder, setup.context.Request)
+
+			if tt.name == "successful get avatar" {
+				assert.Equal(t, tt.want.status, setup.recorder.Code)
+				assert.Equal(t, "image/gif", setup.recorder.Header().Get("Content-Type"))
+				assert.Equal(t, "nosniff", setup.recorder.Header().Get("X-Content-Type-Options"))
+				assert.Equal(t, "gif-content", setup.recorder.Body.String())
+				return
+			}
+
+			testutils.AssertResponse(t, setup.recorder.Result(), tt.want.status, testutils.LoadFile(t, tt.want.respFile))
+		})
+	}
+}
+
+func TestPreferencesHandler_DeleteMyAvatar(t *testing.T) {
+	tests := []struct {
+		name           string
+		want           want
+		setUserIDInCtx bool
+	}{
+		{
+			name: "successful delete avatar",
+			want: want{
+				status:   http.StatusOK,
+				respFile: "testdata/delete_my_avatar/200_resp.json.golden",
+			},
+			setUserIDInCtx: true,
+		},
+		{
+			name: "failed to get userID from context",
+			want: want{
+				status:   http.StatusUnauthorized,
+				respFile: "testdata/delete_my_avatar/401_resp.json.golden",
+			},
+			setUserIDInCtx: false,
+		},
+		{
+			name: "no avatar",
+			want: want{
+				status:   http.StatusNotFound,
+				respFile: "testdata/delete_my_avatar/404_resp.json.golden",
+			},
+			setUserIDInCtx: true,
+		},
+		{
+			name: "internal server error",
+			want: want{
+				status:   http.StatusInternalServerError,
+				respFile: "testdata/delete_my_avatar/500_resp.json.golden",
+			},
+			setUserIDInCtx: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setup := setupPreferencesTest(t, tt.setUserIDInCtx)
+			defer setup.ctrl.Finish()
+
+			// DeleteAvatar service won't be called when userID is not in context
+			if tt.setUserIDInCtx {
+				setup.mockPreferencesService.EXPECT().DeleteAvatar(gomock.Any(), uIDUuid).DoAndReturn(func(ctx context.Context, userID pgtype.UUID) error {
+					switch tt.name {
+					case "successful delete avatar":
+						return nil
+					case "no avatar":
+						return utils.ErrNoRowsMatchedSQLC
+					}
+					return errors.New("unexpected error")
+				})
+			}
+
+			setup.context.Request = httptest.NewRequest(http.MethodDelete, "/me/preferences/avatar", nil)
+			setup.router.DELETE("/me/preferences/avatar", setup.preferencesHandler.DeleteMyAvatar)
+			setup.router.ServeHTTP(setup.recorder, setup.context.Request)
+
+			testutils.AssertResponse(t, setup.recorder.Result(), tt.want.status, testutils.LoadFile(t, tt.want.respFile))
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"time"
+	"todo-app/internal/db"
+	"todo-app/internal/services"
+	"todo-app/internal/utils"
+
+	"github.com/gin-contrib/sessions"
+	"github.com/gin-gonic/gin"
+)
+
+type SessionHandler struct {
+	SessionService services.ISessionService
+}
+
+type SessionResponse struct {
+	ID         string    `json:"id"`
+	UserAgent  string    `json:"user_agent"`
+	IP         string    `json:"ip"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+	Current    bool      `json:"current"`
+}
+
+func NewSessionHandler(sessionService services.ISessionService) *SessionHandler {
+	return &SessionHandler{SessionService: sessionService}
+}
+
+// @Summary List current user's active sessions
+// @Tags Session
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} SessionResponse
+// @Failure 500 {object} gin.H "{"error": "Internal server error"}"
+// @Router /me/sessions [get]
+func (h *SessionHandler) ListMySessions(ctx *gin.Context) {
+	userIDUuid, err := utils.GetUIDFromCtxAndCreateRespUponErr(ctx)
+	if err != nil {
+		return
+	}
+
+	activeSessions, err := h.SessionService.ListSessions(ctx, userIDUuid)
+	if err != nil {
+		log.Println(err.Error())
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": utils.MsgInternalServerErr})
+		return
+	}
+
+	currentPublicID := db.SessionPublicID(sessions.Default(ctx).ID())
+
+	sessionResponses := make([]SessionResponse, len(activeSessions))
+	for i, session := range activeSessions {
+		sessionResponses[i] = SessionResponse{
+			ID:         session.PublicID,
+			UserAgent:  session.UserAgent,
+			IP:         session.IP,
+			CreatedAt:  session.CreatedAt,
+			LastSeenAt: session.LastSeenAt,
+			Current:    session.PublicID == currentPublicID,
+		}
+	}
+
+	ctx.JSON(http.StatusOK, sessionResponses)
+}
+
+// @Summary Revoke one of current user's sessions
+// @Tags Session
+// @Produce json
+// @Param id path string true "Session ID"
+// @Security BearerAuth
+// @Success 200 {object} gin.H "{"message": "Session revoked"}"
+// @Failure 404 {object} gin.H "{"error": "Resource not found"}"
+// @Failure 500 {object} gin.H "{"error": "Internal server error"}"
+// @Router /me/sessions/{id} [delete]
+func (h *SessionHandler) RevokeMySession(ctx *gin.Context) {
+	userIDUuid, err := utils.GetUIDFromCtxAndCreateRespUponErr(ctx)
+	if err != nil {
+		return
+	}
+
+	err = h.SessionService.RevokeSession(ctx, userIDUuid, ctx.Param("id"))
+	if err != nil {
+		log.Println(err.Error())
+
+		if err == utils.ErrNoRowsMatchedSQLC {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": utils.MsgResourceNotFound})
+			return
+		}
+
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": utils.MsgInternalServerErr})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "Session revoked"})
+}
+
+// @Summary Revoke all of current user's sessions (log out everywhere)
+// @Tags Session
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} gin.H "{"message": "All sessions revoked"}"
+// @Failure 500 {object} gin.H "{"error": "Internal server error"}"
+// @Router /me/sessions [delete]
+func (h *SessionHandler) RevokeAllMySessions(ctx *gin.Context) {
+	userIDUuid, err := utils.GetUIDFromCtxAndCreateRespUponErr(ctx)
+	if err != nil {
+		return
+	}
+
+	err = h.SessionService.RevokeAllSessions(ctx, userIDUuid)
+	if err != nil {
+		log.Println(err.Error())
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": utils.MsgInternalServerErr})
+		return
+	}
+
+	// The current session is gone as well, so let the client drop its cookie
+	session := sessions.Default(ctx)
+	session.Clear()
+	session.Options(sessions.Options{MaxAge: -1})
+	if err := session.Save(); err != nil {
+		log.Println(err.Error())
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "All sessions revoked"})
+}
@@ -0,0 +1,251 @@
+package handlers_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"todo-app/internal/db"
+	"todo-app/internal/handlers"
+	mock_services "todo-app/internal/services/_mock"
+	"todo-app/internal/utils"
+	"todo-app/internal/utils/testutils"
+
+	"github.com/gin-contrib/sessions"
+	"github.com/gin-contrib/sessions/memstore"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/mock/gomock"
+)
+
+type sessionTestSetup struct {
+	ctrl               *gomock.Controller
+	mockSessionService *mock_services.MockISessionService
+	sessionHandler     *handlers.SessionHandler
+	router             *gin.Engine
+	recorder           *httptest.ResponseRecorder
+	context            *gin.Context
+}
+
+func setupSessionTest(t *testing.T, setUserIDInCtx bool) *sessionTestSetup {
+	ctrl := gomock.NewController(t)
+	mockSessionService := mock_services.NewMockISessionService(ctrl)
+	sessionHandler := handlers.NewSessionHandler(mockSessionService)
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	ctx, r := gin.CreateTestContext(w)
+
+	store := memstore.NewStore([]byte("secret"))
+	r.Use(sessions.Sessions("mysession", store))
+
+	if setUserIDInCtx {
+		ctx.Set("userID", uIDStr)
+		r.Use(func(c *gin.Context) {
+			c.Set("userID", uIDStr)
+			c.Next()
+		})
+	}
+
+	return &sessionTestSetup{
+		ctrl:               ctrl,
+		mockSessionService: mockSessionService,
+		sessionHandler:     sessionHandler,
+		router:             r,
+		recorder:           w,
+		context:            ctx,
+	}
+}
+
+func TestSessionHandler_ListMySessions(t *testing.T) {
+	tests := []struct {
+		name           string
+		want           want
+		setUserIDInCtx bool
+	}{
+		{
+			name: "successful list sessions",
+			want: want{
+				status:   http.StatusOK,
+				respFile: "testdata/list_my_sessions/200_resp.json.golden",
+			},
+			setUserIDInCtx: true,
+		},
+		{
+			name: "failed to get userID from context",
+			want: want{
+				status:   http.StatusUnauthorized,
+				respFile: "testdata/list_my_sessions/401_resp.json.golden",
+			},
+			setUserIDInCtx: false,
+		},
+		{
+			name: "internal server error",
+			want: want{
+				status:   http.StatusInternalServerError,
+				respFile: "testdata/list_my_sessions/500_resp.json.golden",
+			},
+			setUserIDInCtx: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setup := setupSessionTest(t, tt.setUserIDInCtx)
+			defer setup.ctrl.Finish()
+
+			if tt.setUserIDInCtx {
+				setup.mockSessionService.EXPECT().ListSessions(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, userID pgtype.UUID) ([]db.Session, error) {
+					switch tt.want.status {
+					case http.StatusOK:
+						return []db.Session{{
+							ID:         "session-id-123",
+							PublicID:   "0123456789abcdef01234567",
+							UserID:     uIDStr,
+							UserAgent:  "Mozilla/5.0",
+							IP:         "192.0.2.1",
+							CreatedAt:  mockTime,
+							LastSeenAt: mockTime,
+						}}, nil
+					case http.StatusInternalServerError:
+						return nil, errors.New("unexpected error")
+					}
+					return nil, errors.New("error from mock")
+				})
+			}
+
+			setup.context.Request = httptest.NewRequest(http.MethodGet, "/me/sessions", nil)
+			setup.router.GET("/me/sessions", setup.sessionHandler.ListMySessions)
+			setup.router.ServeHTTP(setup.recorder, setup.context.Request)
+
+			testutils.AssertResponse(t, setup.recorder.Result(), tt.want.status, testutils.LoadFile(t, tt.want.respFile))
+		})
+	}
+}
+
+func TestSessionHandler_RevokeMySession(t *testing.T) {
+	tests := []struct {
+		name           string
+		want           want
+		setUserIDInCtx bool
+	}{
+		{
+			name: "successful revoke session",
+			want: want{
+				status:   http.StatusOK,
+				respFile: "testdata/revoke_my_session/200_resp.json.golden",
+			},
+			setUserIDInCtx: true,
+		},
+		{
+			name: "failed to get userID from context",
+			want: want{
+				status:   http.StatusUnauthorized,
+				respFile: "testdata/revoke_my_session/401_resp.json.golden",
+			},
+			setUserIDInCtx: false,
+		},
+		{
+			name: "session not found",
+			want: want{
+				status:   http.StatusNotFound,
+				respFile: "testdata/revoke_my_session/404_resp.json.golden",
+			},
+			setUserIDInCtx: true,
+		},
+		{
+			name: "internal server error",
+			want: want{
+				status:   http.StatusInternalServerError,
+				respFile: "testdata/revoke_my_session/500_resp.json.golden",
+			},
+			setUserIDInCtx: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setup := setupSessionTest(t, tt.setUserIDInCtx)
+			defer setup.ctrl.Finish()
+
+			if tt.setUserIDInCtx {
+				setup.mockSessionService.EXPECT().RevokeSession(gomock.Any(), gomock.Any(), "0123456789abcdef01234567").DoAndReturn(func(ctx context.Context, userID pgtype.UUID, publicID string) error {
+					switch tt.want.status {
+					case http.StatusOK:
+						return nil
+					case http.StatusNotFound:
+						return utils.ErrNoRowsMatchedSQLC
+					case http.StatusInternalServerError:
+						return errors.New("unexpected error")
+					}
+					return errors.New("error from mock")
+				})
+			}
+
+			setup.context.Request = httptest.NewRequest(http.MethodDelete, "/me/sessions/0123456789abcdef01234567", nil)
+			setup.router.DELETE("/me/sessions/:id", setup.sessionHandler.RevokeMySession)
+			setup.router.ServeHTTP(setup.recorder, setup.context.Request)
+
+			testutils.AssertResponse(t, setup.recorder.Result(), tt.want.status, testutils.LoadFile(t, tt.want.respFile))
+		})
+	}
+}
+
+func TestSessionHandler_RevokeAllMySessions(t *testing.T) {
+	tests := []struct {
+		name           string
+		want           want
+		setUserIDInCtx bool
+	}{
+		{
+			name: "successful revoke all sessions",
+			want: want{
+				status:   http.StatusOK,
+				respFile: "testdata/revoke_all_my_sessions/200_resp.json.golden",
+			},
+			setUserIDInCtx: true,
+		},
+		{
+			name: "failed to get userID from context",
+			want: want{
+				status:   http.StatusUnauthorized,
+				respFile: "testdata/revoke_all_my_sessions/401_resp.json.golden",
+			},
+			setUserIDInCtx: false,
+		},
+		{
+			name: "internal server error",
+			want: want{
+				status:   http.StatusInternalServerError,
+				respFile: "testdata/revoke_all_my_sessions/500_resp.json.golden",
+			},
+			setUserIDInCtx: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setup := setupSessionTest(t, tt.setUserIDInCtx)
+			defer setup.ctrl.Finish()
+
+			if tt.setUserIDInCtx {
+				setup.mockSessionService.EXPECT().RevokeAllSessions(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, userID pgtype.UUID) error {
+					switch tt.want.status {
+					case http.StatusOK:
+						return nil
+					case http.StatusInternalServerError:
+						return errors.New("unexpected error")
+					}
+					return errors.New("error from mock")
+				})
+			}
+
+			setup.context.Request = httptest.NewRequest(http.MethodDelete, "/me/sessions", nil)
+			setup.router.DELETE("/me/sessions", setup.sessionHandler.RevokeAllMySessions)
+			setup.router.ServeHTTP(setup.recorder, setup.context.Request)
+
+			testutils.AssertResponse(t, setup.recorder.Result(), tt.want.status, testutils.LoadFile(t, tt.want.respFile))
+		})
+	}
+}
@@ -0,0 +1,281 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"todo-app/internal/db"
+	"todo-app/internal/services"
+	"todo-app/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TagHandler struct {
+	TagService services.ITagService
+}
+
+// Also the shape of the tags in TodoResponse
+type TagResponse struct {
+	ID    int32  `json:"id"`
+	Name  string `json:"name"`
+	Color string `json:"color"`
+}
+
+func NewTagHandler(tagService services.ITagService) *TagHandler {
+	return &TagHandler{TagService: tagService}
+}
+
+// @Summary Create a tag
+// @Description The color is given as #rrggbb and defaults to grey.
+// @Tags Tag
+// @Accept json
+// @Produce json
+// @Param tag body services.TagRequest true "Name and color of the tag"
+// @Security BearerAuth
+// @Success 201 {object} TagResponse
+// @Failure 400 {object} gin.H "{"error": "Invalid request"}"
+// @Failure 409 {object} gin.H "{"error": "A tag with this name already exists"}"
+// @Failure 500 {object} gin.H "{"error": "Internal server error"}"
+// @Router /tags [post]
+func (h *TagHandler) CreateTag(ctx *gin.Context) {
+	userIDUuid, err := utils.GetUIDFromCtxAndCreateRespUponErr(ctx)
+	if err != nil {
+		return
+	}
+
+	var req services.TagRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil || strings.TrimSpace(req.Name) == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": utils.MsgInvalidReq})
+		return
+	}
+
+	tag, err := h.TagService.CreateTag(ctx, userIDUuid, req)
+	if err != nil {
+		log.Println(err.Error())
+
+		if pgErr, ok := utils.AssertPgErr(err); ok {
+			if pgErr.Code == "23505" {
+				ctx.JSON(http.StatusConflict, gin.H{"error": utils.MsgTagAlreadyExists})
+				return
+			}
+		}
+
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": utils.MsgInternalServerErr})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, toTagResponse(*tag))
+}
+
+// @Summary List current user's tags
+// @Description Ordered by name.
+// @Tags Tag
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} TagResponse
+// @Failure 500 {object} gin.H "{"error": "Internal server error"}"
+// @Router /tags [get]
+func (h *TagHandler) ListTags(ctx *gin.Context) {
+	userIDUuid, err := utils.GetUIDFromCtxAndCreateRespUponErr(ctx)
+	if err != nil {
+		return
+	}
+
+	tags, err := h.TagService.ListTags(ctx, userIDUuid)
+	if err != nil {
+		log.Println(err.Error())
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": utils.MsgInternalServerErr})
+		return
+	}
+
+	tagResponses := make([]TagResponse, len(tags))
+	for i, tag := range tags {
+		tagResponses[i] = toTagResponse(tag)
+	}
+
+	ctx.JSON(http.StatusOK, tagResponses)
+}
+
+// @Summary Rename or recolor a tag
+// @Description The todos with the tag reflect the change.
+// @Tags Tag
+// @Accept json
+// @Produce json
+// @Param id path int true "Tag ID"
+// @Param tag body services.TagRequest true "New name and color of the tag"
+// @Security BearerAuth
+// @Success 200 {object} TagResponse
+// @Failure 400 {object} gin.H "{"error": "Invalid request"}"
+// @Failure 404 {object} gin.H "{"error": "Resource not found"}"
+// @Failure 409 {object} gin.H "{"error": "A tag with this name already exists"}"
+// @Failure 500 {object} gin.H "{"error": "Internal server error"}"
+// @Router /tags/{id} [put]
+func (h *TagHandler) UpdateTag(ctx *gin.Context) {
+	userIDUuid, err := utils.GetUIDFromCtxAndCreateRespUponErr(ctx)
+	if err != nil {
+		return
+	}
+
+	tagID, err := strconv.Atoi(ctx.Param("id"))
+	var req services.TagRequest
+	if reqErr := ctx.ShouldBindJSON(&req); reqErr != nil || err != nil || strings.TrimSpace(req.Name) == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": utils.MsgInvalidReq})
+		return
+	}
+
+	tag, err := h.TagService.UpdateTag(ctx, userIDUuid, int32(tagID), req)
+	if err != nil {
+		log.Println(err.Error())
+
+		if err == utils.ErrNoRowsMatchedSQLC {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": utils.MsgResourceNotFound})
+			return
+		}
+
+		if pgErr, ok := utils.AssertPgErr(err); ok {
+			if pgErr.Code == "23505" {
+				ctx.JSON(http.StatusConflict, gin.H{"error": utils.MsgTagAlreadyExists})
+				return
+			}
+		}
+
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": utils.MsgInternalServerErr})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, toTagResponse(*tag))
+}
+
+// @Summary Delete a tag
+// @Description The tag is removed from every todo that has it.
+// @Tags Tag
+// @Produce json
+// @Param id path int true "Tag ID"
+// @Security BearerAuth
+// @Success 200 {object} gin.H "{"message": "Tag deleted"}"
+// @Failure 400 {object} gin.H "{"error": "Invalid request"}"
+// @Failure 404 {object} gin.H "{"error": "Resource not found"}"
+// @Failure 500 {object} gin.H "{"error": "Internal server error"}"
+// @Router /tags/{id} [delete]
+func (h *TagHandler) DeleteTag(ctx *gin.Context) {
+	userIDUuid, err := utils.GetUIDFromCtxAndCreateRespUponErr(ctx)
+	if err != nil {
+		return
+	}
+
+	tagID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": utils.MsgInvalidReq})
+		return
+	}
+
+	err = h.TagService.DeleteTag(ctx, userIDUuid, int32(tagID))
+	if err != nil {
+		log.Println(err.Error())
+
+		if err == utils.ErrNoRowsMatchedSQLC {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": utils.MsgResourceNotFound})
+			return
+		}
+
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": utils.MsgInternalServerErr})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "Tag deleted"})
+}
+
+// @Summary Tag a todo
+// @Description Tagging a todo that already has the tag changes nothing.
+// @Tags Tag
+// @Produce json
+// @Param id path int true "Todo ID"
+// @Param tagId path int true "Tag ID"
+// @Security BearerAuth
+// @Success 200 {object} TodoResponse
+// @Failure 400 {object} gin.H "{"error": "Invalid request"}"
+// @Failure 404 {object} gin.H "{"error": "Resource not found"}"
+// @Failure 500 {object} gin.H "{"error": "Internal server error"}"
+// @Router /todos/{id}/tags/{tagId} [put]
+func (h *TagHandler) AttachTag(ctx *gin.Context) {
+	userIDUuid, err := utils.GetUIDFromCtxAndCreateRespUponErr(ctx)
+	if err != nil {
+		return
+	}
+
+	todoID, tagID, ok := todoAndTagIDs(ctx)
+	if !ok {
+		return
+	}
+
+	todo, err := h.TagService.AttachTag(ctx, userIDUuid, todoID, tagID)
+	respondTaggedTodo(ctx, todo, err)
+}
+
+// @Summary Untag a todo
+// @Tags Tag
+// @Produce json
+// @Param id path int true "Todo ID"
+// @Param tagId path int true "Tag ID"
+// @Security BearerAuth
+// @Success 200 {object} TodoResponse
+// @Failure 400 {object} gin.H "{"error": "Invalid request"}"
+// @Failure 404 {object} gin.H "{"error": "Resource not found"}"
+// @Failure 500 {object} gin.H "{"error": "Internal server error"}"
+// @Router /todos/{id}/tags/{tagId} [delete]
+func (h *TagHandler) DetachTag(ctx *gin.Context) {
+	userIDUuid, err := utils.GetUIDFromCtxAndCreateRespUponErr(ctx)
+	if err != nil {
+		return
+	}
+
+	todoID, tagID, ok := todoAndTagIDs(ctx)
+	if !ok {
+		return
+	}
+
+	todo, err := h.TagService.DetachTag(ctx, userIDUuid, todoID, tagID)
+	respondTaggedTodo(ctx, todo, err)
+}
+
+func todoAndTagIDs(ctx *gin.Context) (int32, int32, bool) {
+	todoID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": utils.MsgInvalidReq})
+		return 0, 0, false
+	}
+
+	tagID, err := strconv.Atoi(ctx.Param("tagId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": utils.MsgInvalidReq})
+		return 0, 0, false
+	}
+
+	return int32(todoID), int32(tagID), true
+}
+
+func respondTaggedTodo(ctx *gin.Context, todo *db.Todo, err error) {
+	if err != nil {
+		log.Println(err.Error())
+
+		if err == utils.ErrNoRowsMatchedSQLC {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": utils.MsgResourceNotFound})
+			return
+		}
+
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": utils.MsgInternalServerErr})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, toTodoResponse(*todo))
+}
+
+func toTagResponse(tag db.Tag) TagResponse {
+	return TagResponse{
+		ID:    tag.ID,
+		Name:  tag.Name,
+		Color: tag.Color,
+	}
+}
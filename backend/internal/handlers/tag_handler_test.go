@@ -0,0 +1,194 @@
+package handlers_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"todo-app/internal/db"
+	"todo-app/internal/handlers"
+	"todo-app/internal/services"
+	mock_services "todo-app/internal/services/_mock"
+	"todo-app/internal/utils"
+	"todo-app/internal/utils/testutils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+type tagTestSetup struct {
+	ctrl           *gomock.Controller
+	mockTagService *mock_services.MockITagService
+	tagHandler     *handlers.TagHandler
+	router         *gin.Engine
+	recorder       *httptest.ResponseRecorder
+	context        *gin.Context
+}
+
+func setupTagTest(t *testing.T, setUserIDInCtx bool) *tagTestSetup {
+	ctrl := gomock.NewController(t)
+	mockTagService := mock_services.NewMockITagService(ctrl)
+	tagHandler := handlers.NewTagHandler(mockTagService)
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	ctx, r := gin.CreateTestContext(w)
+
+	if setUserIDInCtx {
+		ctx.Set("userID", uIDStr)
+		r.Use(func(c *gin.Context) {
+			c.Set("userID", uIDStr)
+			c.Next()
+		})
+	}
+
+	return &tagTestSetup{
+		ctrl:           ctrl,
+		mockTagService: mockTagService,
+		tagHandler:     tagHandler,
+		router:         r,
+		recorder:       w,
+		context:        ctx,
+	}
+}
+
+func TestTagHandler_CreateTag(t *testing.T) {
+	tests := []struct {
+		name           string
+		reqFile        string
+		want           want
+		setUserIDInCtx bool
+	}{
+		{
+			name:    "successful create tag",
+			reqFile: "testdata/create_tag/201_req.json.golden",
+			want: want{
+				status:   http.StatusCreated,
+				respFile: "testdata/create_tag/201_resp.json.golden",
+			},
+			setUserIDInCtx: true,
+		},
+		{
+			name:    "invalid color",
+			reqFile: "testdata/create_tag/400_req.json.golden",
+			want: want{
+				status:   http.StatusBadRequest,
+				respFile: "testdata/create_tag/400_resp.json.golden",
+			},
+			setUserIDInCtx: true,
+		},
+		{
+			name:    "name already taken",
+			reqFile: "testdata/create_tag/201_req.json.golden",
+			want: want{
+				status:   http.StatusConflict,
+				respFile: "testdata/create_tag/409_resp.json.golden",
+			},
+			setUserIDInCtx: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setup := setupTagTest(t, tt.setUserIDInCtx)
+			defer setup.ctrl.Finish()
+
+			if tt.want.status != http.StatusBadRequest {
+				setup.mockTagService.EXPECT().CreateTag(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, userID pgtype.UUID, req services.TagRequest) (*db.Tag, error) {
+					switch tt.want.status {
+					case http.StatusCreated:
+						assert.Equal(t, services.TagRequest{Name: "work", Color: "#FF0000"}, req)
+						return &db.Tag{ID: 1, UserID: 1, Name: "work", Color: "#ff0000"}, nil
+					case http.StatusConflict:
+						return nil, &pgconn.PgError{Code: "23505"}
+					}
+					return nil, errors.New("error from mock")
+				})
+			}
+
+			setup.context.Request = httptest.NewRequest(http.MethodPost, "/tags", bytes.NewReader(testutils.LoadFile(t, tt.reqFile)))
+			setup.context.Request.Header.Set("Content-Type", "application/json")
+			setup.router.POST("/tags", setup.tagHandler.CreateTag)
+			setup.router.ServeHTTP(setup.recorder, setup.context.Request)
+
+			testutils.AssertResponse(t, setup.recorder.Result(), tt.want.status, testutils.LoadFile(t, tt.want.respFile))
+		})
+	}
+}
+
+func TestTagHandler_ListTags(t *testing.T) {
+	setup := setupTagTest(t, true)
+	defer setup.ctrl.Finish()
+
+	setup.mockTagService.EXPECT().ListTags(gomock.Any(), gomock.Any()).Return([]db.Tag{
+		{ID: 2, UserID: 1, Name: "home", Color: "#00ff00"},
+		{ID: 1, UserID: 1, Name: "work", Color: "#ff0000"},
+	}, nil)
+
+	setup.context.Request = httptest.NewRequest(http.MethodGet, "/tags", nil)
+	setup.router.GET("/tags", setup.tagHandler.ListTags)
+	setup.router.ServeHTTP(setup.recorder, setup.context.Request)
+
+	testutils.AssertResponse(t, setup.recorder.Result(), http.StatusOK, testutils.LoadFile(t, "testdata/list_tags/200_resp.json.golden"))
+}
+
+func TestTagHandler_AttachTag(t *testing.T) {
+	tests := []struct {
+		name  string
+		tagID string
+		want  want
+	}{
+		{
+			name:  "successful attach tag",
+			tagID: "1",
+			want: want{
+				status:   http.StatusOK,
+				respFile: "testdata/attach_tag/200_resp.json.golden",
+			},
+		},
+		{
+			name:  "specified tag not found",
+			tagID: "1000",
+			want: want{
+				status:   http.StatusNotFound,
+				respFile: "testdata/attach_tag/404_resp.json.golden",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setup := setupTagTest(t, true)
+			defer setup.ctrl.Finish()
+
+			setup.mockTagService.EXPECT().AttachTag(gomock.Any(), gomock.Any(), int32(1), gomock.Any()).DoAndReturn(func(ctx context.Context, userID pgtype.UUID, todoID, tagID int32) (*db.Todo, error) {
+				if tt.want.status == http.StatusNotFound {
+					return nil, utils.ErrNoRowsMatchedSQLC
+				}
+				return &db.Todo{
+					ID:          todoID,
+					ListID:      10,
+					Description: "Test todo",
+					Language:    "english",
+					Position:    pgtype.Numeric{Int: big.NewInt(100), Valid: true},
+					Completed:   pgtype.Bool{Bool: false, Valid: true},
+					CreatedAt:   pgtype.Timestamptz{Time: mockTime, Valid: true},
+					UpdatedAt:   pgtype.Timestamptz{Time: mockTime, Valid: true},
+					Tags:        []byte(`[{"id": 1, "name": "work", "color": "#ff0000"}]`),
+				}, nil
+			})
+
+			setup.context.Request = httptest.NewRequest(http.MethodPut, "/todos/1/tags/"+tt.tagID, nil)
+			setup.router.PUT("/todos/:id/tags/:tagId", setup.tagHandler.AttachTag)
+			setup.router.ServeHTTP(setup.recorder, setup.context.Request)
+
+			testutils.AssertResponse(t, setup.recorder.Result(), tt.want.status, testutils.LoadFile(t, tt.want.respFile))
+		})
+	}
+}
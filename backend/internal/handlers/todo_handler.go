@@ -1,10 +1,15 @@
 package handlers
 
 import (
+	"encoding/json"
+	"fmt"
+	"html"
 	"log"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
+	"todo-app/internal/db"
 	"todo-app/internal/services"
 	"todo-app/internal/utils"
 
@@ -17,12 +22,36 @@ type TodoHandler struct {
 
 // Hide private userId (users.id)
 type TodoResponse struct {
-	ID          int32     `json:"id"`
-	Description string    `json:"description"`
-	Position    int64     `json:"position"`
-	Completed   bool      `json:"completed"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID          int32      `json:"id"`
+	ListID      int32      `json:"list_id"`
+	Description string     `json:"description"`
+	Position    int64      `json:"position"`
+	Completed   bool       `json:"completed"`
+	DueAt       *time.Time `json:"due_at"`
+	RemindAt    *time.Time `json:"remind_at"`
+	Recurrence  *string    `json:"recurrence"`
+	// none, low, medium, high or urgent
+	Priority string `json:"priority"`
+	// english or japanese, which the todo is searched in
+	Language string `json:"language"`
+	// Completed checklist items out of all of them, e.g. "3/5"; null without a checklist
+	Progress  *string       `json:"progress"`
+	Tags      []TagResponse `json:"tags"`
+	CreatedAt time.Time     `json:"created_at"`
+	UpdatedAt time.Time     `json:"updated_at"`
+	// Search results only: the description, or the part of it around the matches, as HTML with the matched words in <mark>
+	Headline string `json:"headline,omitempty"`
+}
+
+// Returned instead of the bare array when a page is asked for
+type TodoPageResponse struct {
+	Todos      []TodoResponse `json:"todos"`
+	NextCursor *string        `json:"next_cursor"`
+	HasMore    bool           `json:"has_more"`
+}
+
+type OccurrencesResponse struct {
+	Occurrences []time.Time `json:"occurrences"`
 }
 
 func NewTodoHandler(todoService services.ITodoService) *TodoHandler {
@@ -30,13 +59,15 @@ func NewTodoHandler(todoService services.ITodoService) *TodoHandler {
 }
 
 // @Summary Create a new todo
+// @Description The todo is added at the end of the list given by list_id, or of the inbox without one.
 // @Tags Todo
 // @Accept json
 // @Produce json
 // @Param todo body services.CreateTodoRequest true "Todo details"
 // @Security BearerAuth
 // @Success 201 {object} TodoResponse
-// @Failure 400 {object} gin.H "{"error": "Invalid request"}"
+// @Failure 400 {object} gin.H "{"error": "Invalid request"}, {"error": "Description must not contain control characters other than line breaks and tabs"}, {"error": "Dates must be RFC 3339 timestamps or dates in YYYY-MM-DD format"}, {"error": "Recurrence must be an RRULE repeating at most daily, such as FREQ=WEEKLY;BYDAY=MO"} or {"error": "Recurring todos need a due date"}"
+// @Failure 404 {object} gin.H "{"error": "Resource not found"}"
 // @Failure 500 {object} gin.H "{"error": "Internal server error"}"
 // @Router /todos [post]
 func (h *TodoHandler) CreateTodo(ctx *gin.Context) {
@@ -54,27 +85,64 @@ func (h *TodoHandler) CreateTodo(ctx *gin.Context) {
 	todo, err := h.TodoService.CreateTodo(ctx, userIDUuid, req)
 	if err != nil {
 		log.Println(err.Error())
+
+		if err == utils.ErrInvalidDescription {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": utils.MsgInvalidDescription})
+			return
+		}
+
+		if err == utils.ErrInvalidDate {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": utils.MsgInvalidDate})
+			return
+		}
+
+		if err == utils.ErrInvalidRecurrence {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": utils.MsgInvalidRecurrence})
+			return
+		}
+
+		if err == utils.ErrRecurrenceWithoutDueDate {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": utils.MsgRecurrenceWithoutDueDate})
+			return
+		}
+
+		if err == utils.ErrNoRowsMatchedSQLC {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": utils.MsgResourceNotFound})
+			return
+		}
+
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": utils.MsgInternalServerErr})
 		return
 	}
 
-	todoResponse := TodoResponse{
-		ID:          todo.ID,
-		Description: todo.Description,
-		Position:    todo.Position.Int.Int64(),
-		Completed:   todo.Completed.Bool,
-		CreatedAt:   todo.CreatedAt.Time,
-		UpdatedAt:   todo.UpdatedAt.Time,
-	}
+	todoResponse := toTodoResponse(*todo)
 
 	ctx.JSON(http.StatusCreated, todoResponse)
 }
 
 // @Summary List all todos
+// @Description Todos of the given list, or of every list not archived ordered list by list. Dates in the filters are RFC 3339 timestamps or dates (YYYY-MM-DD) in the user's time zone, where a date means the start of that day.
 // @Tags Todo
 // @Produce json
+// @Param list_id query int false "only todos of this list"
+// @Param due_before query string false "only todos due before this time"
+// @Param due_after query string false "only todos due at or after this time"
+// @Param overdue query bool false "only todos past their due date and not completed"
+// @Param completed query bool false "only completed (true) or not completed (false) todos"
+// @Param created_after query string false "only todos created at or after this time"
+// @Param created_before query string false "only todos created before this time"
+// @Param updated_after query string false "only todos updated at or after this time"
+// @Param updated_before query string false "only todos updated before this time"
+// @Param q query string false "only todos whose description contains this text, ignoring case"
+// @Param tag query []string false "only todos with these tags (by name)" collectionFormat(multi)
+// @Param tag_mode query string false "all (default) or any of the tags" Enums(all, any)
+// @Param sort query string false "comma separated columns (position, priority, due_at, created_at, updated_at, description), descending with a leading -; the user's default sort otherwise"
+// @Param limit query int false "page size (1-200, default 50); asks for a page"
+// @Param cursor query string false "next_cursor of the previous page; asks for a page"
 // @Security BearerAuth
-// @Success 200 {array} TodoResponse
+// @Success 200 {array} TodoResponse "without limit and cursor"
+// @Success 200 {object} TodoPageResponse "with limit or cursor"
+// @Failure 400 {object} gin.H "{"error": "Invalid request"} or {"error": "Dates must be RFC 3339 timestamps or dates in YYYY-MM-DD format"} or {"error": "Sort must be a comma separated list of ..."} or {"error": "Invalid or expired cursor"}"
 // @Failure 500 {object} gin.H "{"error": "Internal server error"}"
 // @Router /todos [get]
 func (h *TodoHandler) ListTodos(ctx *gin.Context) {
@@ -83,35 +151,50 @@ func (h *TodoHandler) ListTodos(ctx *gin.Context) {
 		return
 	}
 
-	todos, err := h.TodoService.ListTodos(ctx, userIDUuid)
-	if err != nil {
-		log.Println(err.Error())
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": utils.MsgInternalServerErr})
+	var req services.ListTodosRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": utils.MsgInvalidReq})
 		return
 	}
 
-	todoResponses := make([]TodoResponse, len(*todos))
-	for i, todo := range *todos {
-		todoResponses[i] = TodoResponse{
-			ID:          todo.ID,
-			Description: todo.Description,
-			Position:    todo.Position.Int.Int64(),
-			Completed:   todo.Completed.Bool,
-			CreatedAt:   todo.CreatedAt.Time,
-			UpdatedAt:   todo.UpdatedAt.Time,
+	todos, err := h.TodoService.ListTodos(ctx, userIDUuid, req)
+	if err != nil {
+		log.Println(err.Error())
+
+		if err == utils.ErrInvalidDate {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": utils.MsgInvalidDate})
+			return
+		}
+
+		if err == utils.ErrInvalidSort {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": utils.MsgInvalidSort})
+			return
+		}
+
+		if err == utils.ErrInvalidCursor {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": utils.MsgInvalidCursor})
+			return
 		}
+
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": utils.MsgInternalServerErr})
+		return
 	}
 
-	ctx.JSON(http.StatusOK, todoResponses)
+	respondTodoPage(ctx, todos, req.PageRequest)
 }
 
 // @Summary Search todos by keyword
 // @Tags Todo
 // @Produce json
-// @Param keyword query string true "Search keyword"
+// @Param keyword query string true "words, quoted phrases, -excluded words and or (web search syntax); the last word also matches as a prefix"
+// @Param tag query []string false "only todos with these tags (by name)" collectionFormat(multi)
+// @Param tag_mode query string false "all (default) or any of the tags" Enums(all, any)
+// @Param limit query int false "page size (1-200, default 50); asks for a page"
+// @Param cursor query string false "next_cursor of the previous page; asks for a page"
 // @Security BearerAuth
-// @Success 200 {array} TodoResponse
-// @Failure 400 {object} gin.H "{"error": "Invalid request"}"
+// @Success 200 {array} TodoResponse "without limit and cursor"
+// @Success 200 {object} TodoPageResponse "with limit or cursor"
+// @Failure 400 {object} gin.H "{"error": "Invalid request"} or {"error": "Invalid or expired cursor"}"
 // @Failure 500 {object} gin.H "{"error": "Internal server error"}"
 // @Router /todos/search [get]
 func (h *TodoHandler) SearchTodos(ctx *gin.Context) {
@@ -120,35 +203,30 @@ func (h *TodoHandler) SearchTodos(ctx *gin.Context) {
 		return
 	}
 
-	keyword := ctx.Query("keyword")
-	if keyword == "" {
+	var req services.SearchTodosRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": utils.MsgInvalidReq})
 		return
 	}
 
-	todos, err := h.TodoService.SearchTodos(ctx, userIDUuid, keyword)
+	todos, err := h.TodoService.SearchTodos(ctx, userIDUuid, req)
 	if err != nil {
 		log.Println(err.Error())
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": utils.MsgInternalServerErr})
-		return
-	}
 
-	todoResponses := make([]TodoResponse, len(*todos))
-	for i, todo := range *todos {
-		todoResponses[i] = TodoResponse{
-			ID:          todo.ID,
-			Description: todo.Description,
-			Position:    todo.Position.Int.Int64(),
-			Completed:   todo.Completed.Bool,
-			CreatedAt:   todo.CreatedAt.Time,
-			UpdatedAt:   todo.UpdatedAt.Time,
+		if err == utils.ErrInvalidCursor {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": utils.MsgInvalidCursor})
+			return
 		}
+
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": utils.MsgInternalServerErr})
+		return
 	}
 
-	ctx.JSON(http.StatusOK, todoResponses)
+	respondTodoPage(ctx, todos, req.PageRequest)
 }
 
 // @Summary Update a todo
+// @Description Due dates, reminders, recurrence and priorities left out are kept as they are, while null or an empty string clears the first three. Completing a recurring todo creates its next occurrence, which takes over the recurrence. With complete_items, completing the todo also completes its checklist.
 // @Tags Todo
 // @Accept json
 // @Produce json
@@ -156,7 +234,7 @@ func (h *TodoHandler) SearchTodos(ctx *gin.Context) {
 // @Param todo body services.UpdateTodoRequest true "Updated todo details"
 // @Security BearerAuth
 // @Success 200 {object} TodoResponse
-// @Failure 400 {object} gin.H "{"error": "Invalid request"}"
+// @Failure 400 {object} gin.H "{"error": "Invalid request"}, {"error": "Description must not contain control characters other than line breaks and tabs"}, {"error": "Dates must be RFC 3339 timestamps or dates in YYYY-MM-DD format"}, {"error": "Recurrence must be an RRULE repeating at most daily, such as FREQ=WEEKLY;BYDAY=MO"} or {"error": "Recurring todos need a due date"}"
 // @Failure 404 {object} gin.H "{"error": "Resource not found"}"
 // @Failure 500 {object} gin.H "{"error": "Internal server error"}"
 // @Router /todos/{id} [put]
@@ -177,6 +255,26 @@ func (h *TodoHandler) UpdateTodo(ctx *gin.Context) {
 	if err != nil {
 		log.Println(err.Error())
 
+		if err == utils.ErrInvalidDescription {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": utils.MsgInvalidDescription})
+			return
+		}
+
+		if err == utils.ErrInvalidDate {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": utils.MsgInvalidDate})
+			return
+		}
+
+		if err == utils.ErrInvalidRecurrence {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": utils.MsgInvalidRecurrence})
+			return
+		}
+
+		if err == utils.ErrRecurrenceWithoutDueDate {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": utils.MsgRecurrenceWithoutDueDate})
+			return
+		}
+
 		if err == utils.ErrNoRowsMatchedSQLC {
 			ctx.JSON(http.StatusNotFound, gin.H{"error": utils.MsgResourceNotFound})
 			return
@@ -186,18 +284,52 @@ func (h *TodoHandler) UpdateTodo(ctx *gin.Context) {
 		return
 	}
 
-	todoResponse := TodoResponse{
-		ID:          todo.ID,
-		Description: todo.Description,
-		Position:    todo.Position.Int.Int64(),
-		Completed:   todo.Completed.Bool,
-		CreatedAt:   todo.CreatedAt.Time,
-		UpdatedAt:   todo.UpdatedAt.Time,
-	}
+	todoResponse := toTodoResponse(*todo)
 
 	ctx.JSON(http.StatusOK, todoResponse)
 }
 
+// @Summary Preview the next occurrences of a recurring todo
+// @Description Occurrences following the due date, in the user's time zone. Empty if the todo does not recur.
+// @Tags Todo
+// @Produce json
+// @Param id path int true "Todo ID"
+// @Param count query int false "number of occurrences (1-50, default 5)"
+// @Security BearerAuth
+// @Success 200 {object} OccurrencesResponse
+// @Failure 400 {object} gin.H "{"error": "Invalid request"}"
+// @Failure 404 {object} gin.H "{"error": "Resource not found"}"
+// @Failure 500 {object} gin.H "{"error": "Internal server error"}"
+// @Router /todos/{id}/occurrences [get]
+func (h *TodoHandler) ListTodoOccurrences(ctx *gin.Context) {
+	userIDUuid, err := utils.GetUIDFromCtxAndCreateRespUponErr(ctx)
+	if err != nil {
+		return
+	}
+
+	todoID, err := strconv.Atoi(ctx.Param("id"))
+	var req services.ListOccurrencesRequest
+	if reqErr := ctx.ShouldBindQuery(&req); reqErr != nil || err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": utils.MsgInvalidReq})
+		return
+	}
+
+	occurrences, err := h.TodoService.ListOccurrences(ctx, userIDUuid, int32(todoID), req)
+	if err != nil {
+		log.Println(err.Error())
+
+		if err == utils.ErrNoRowsMatchedSQLC {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": utils.MsgResourceNotFound})
+			return
+		}
+
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": utils.MsgInternalServerErr})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, OccurrencesResponse{Occurrences: occurrences})
+}
+
 // @Summary Update a todo's position
 // @Tags Todo
 // @Accept json
@@ -236,18 +368,53 @@ func (h *TodoHandler) UpdateTodoPosition(ctx *gin.Context) {
 		return
 	}
 
-	todoResponse := TodoResponse{
-		ID:          todo.ID,
-		Description: todo.Description,
-		Position:    todo.Position.Int.Int64(),
-		Completed:   todo.Completed.Bool,
-		CreatedAt:   todo.CreatedAt.Time,
-		UpdatedAt:   todo.UpdatedAt.Time,
-	}
+	todoResponse := toTodoResponse(*todo)
 
 	ctx.JSON(http.StatusOK, todoResponse)
 }
 
+// @Summary Move a todo to a list
+// @Description Places the todo between prev_pos and next_pos in the list, after prev_pos if next_pos is omitted, or at the end of the list if both are.
+// @Tags Todo
+// @Accept json
+// @Produce json
+// @Param id path int true "Todo ID"
+// @Param move body services.MoveTodoRequest true "Target list and position"
+// @Security BearerAuth
+// @Success 200 {object} TodoResponse
+// @Failure 400 {object} gin.H "{"error": "Invalid request"}"
+// @Failure 404 {object} gin.H "{"error": "Resource not found"}"
+// @Failure 500 {object} gin.H "{"error": "Internal server error"}"
+// @Router /todos/{id}/list [patch]
+func (h *TodoHandler) MoveTodo(ctx *gin.Context) {
+	userIDUuid, err := utils.GetUIDFromCtxAndCreateRespUponErr(ctx)
+	if err != nil {
+		return
+	}
+
+	todoID, err := strconv.Atoi(ctx.Param("id"))
+	var req services.MoveTodoRequest
+	if reqErr := ctx.ShouldBindJSON(&req); reqErr != nil || err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": utils.MsgInvalidReq})
+		return
+	}
+
+	todo, err := h.TodoService.MoveTodo(ctx, userIDUuid, int32(todoID), req)
+	if err != nil {
+		log.Println(err.Error())
+
+		if err == utils.ErrNoRowsMatchedSQLC {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": utils.MsgResourceNotFound})
+			return
+		}
+
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": utils.MsgInternalServerErr})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, toTodoResponse(*todo))
+}
+
 // @Summary Delete a todo
 // @Tags Todo
 // @Produce json
@@ -285,3 +452,67 @@ func (h *TodoHandler) DeleteTodo(ctx *gin.Context) {
 
 	ctx.JSON(http.StatusOK, gin.H{"message": "Todo deleted"})
 }
+
+// The bare array unless the request asked for a page, so that clients not paging yet keep working
+func respondTodoPage(ctx *gin.Context, page *services.TodoPage, req services.PageRequest) {
+	todoResponses := make([]TodoResponse, len(page.Todos))
+	for i, todo := range page.Todos {
+		todoResponses[i] = toTodoResponse(todo)
+		if headline, ok := page.Headlines[todo.ID]; ok {
+			todoResponses[i].Headline = highlight(headline)
+		}
+	}
+
+	if !req.Paginated() {
+		ctx.JSON(http.StatusOK, todoResponses)
+		return
+	}
+
+	resp := TodoPageResponse{Todos: todoResponses, HasMore: page.HasMore}
+	if page.HasMore {
+		resp.NextCursor = &page.NextCursor
+	}
+	ctx.JSON(http.StatusOK, resp)
+}
+
+// The matched words come between \x02 and \x03, which are the only markup kept; the description itself is escaped
+var headlineReplacer = strings.NewReplacer("\x02", "<mark>", "\x03", "</mark>")
+
+func highlight(headline string) string {
+	return headlineReplacer.Replace(html.EscapeString(headline))
+}
+
+func toTodoResponse(todo db.Todo) TodoResponse {
+	resp := TodoResponse{
+		ID:          todo.ID,
+		ListID:      todo.ListID,
+		Description: todo.Description,
+		Position:    todo.Position.Int.Int64(),
+		Completed:   todo.Completed.Bool,
+		Priority:    services.TodoPriorities[todo.Priority],
+		Language:    todo.Language,
+		CreatedAt:   todo.CreatedAt.Time,
+		UpdatedAt:   todo.UpdatedAt.Time,
+	}
+	if todo.DueAt.Valid {
+		resp.DueAt = &todo.DueAt.Time
+	}
+	if todo.RemindAt.Valid {
+		resp.RemindAt = &todo.RemindAt.Time
+	}
+	if todo.Recurrence.Valid {
+		resp.Recurrence = &todo.Recurrence.String
+	}
+	if todo.ItemsTotal > 0 {
+		progress := fmt.Sprintf("%d/%d", todo.ItemsCompleted, todo.ItemsTotal)
+		resp.Progress = &progress
+	}
+	resp.Tags = []TagResponse{}
+	if len(todo.Tags) > 0 {
+		// Kept by the database as [{"id", "name", "color"}], the same shape as TagResponse
+		if err := json.Unmarshal(todo.Tags, &resp.Tags); err != nil {
+			log.Println(err.Error())
+		}
+	}
+	return resp
+}
@@ -18,6 +18,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
 )
 
@@ -75,6 +76,15 @@ func TestTodoHandler_CreateTodo(t *testing.T) {
 			},
 			setUserIDInCtx: true,
 		},
+		{
+			name:    "successful create todo - recurring",
+			reqFile: "testdata/create_todo/201_req_recurring.json.golden",
+			want: want{
+				status:   http.StatusCreated,
+				respFile: "testdata/create_todo/201_resp_recurring.json.golden",
+			},
+			setUserIDInCtx: true,
+		},
 		{
 			name:    "failed to get userID from context",
 			reqFile: "testdata/create_todo/401_req.json.golden",
@@ -93,6 +103,33 @@ func TestTodoHandler_CreateTodo(t *testing.T) {
 			},
 			setUserIDInCtx: true,
 		},
+		{
+			name:    "invalid description",
+			reqFile: "testdata/create_todo/400_req_invalid_description.json.golden",
+			want: want{
+				status:   http.StatusBadRequest,
+				respFile: "testdata/create_todo/400_resp_invalid_description.json.golden",
+			},
+			setUserIDInCtx: true,
+		},
+		{
+			name:    "invalid recurrence",
+			reqFile: "testdata/create_todo/400_req_invalid_recurrence.json.golden",
+			want: want{
+				status:   http.StatusBadRequest,
+				respFile: "testdata/create_todo/400_resp_invalid_recurrence.json.golden",
+			},
+			setUserIDInCtx: true,
+		},
+		{
+			name:    "recurrence without due date",
+			reqFile: "testdata/create_todo/400_req_recurrence_without_due_date.json.golden",
+			want: want{
+				status:   http.StatusBadRequest,
+				respFile: "testdata/create_todo/400_resp_recurrence_without_due_date.json.golden",
+			},
+			setUserIDInCtx: true,
+		},
 		{
 			name:    "internal server error",
 			reqFile: "testdata/create_todo/500_req.json.golden",
@@ -114,14 +151,29 @@ func TestTodoHandler_CreateTodo(t *testing.T) {
 				setup.mockTodoService.EXPECT().CreateTodo(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, userID pgtype.UUID, req services.CreateTodoRequest) (*db.Todo, error) {
 					switch tt.want.status {
 					case http.StatusCreated:
-						return &db.Todo{
+						todo := &db.Todo{
 							ID:          1,
+							ListID:      10,
 							Description: req.Description,
+							Language:    "english",
 							Position:    pgtype.Numeric{Int: big.NewInt(100), Valid: true},
 							Completed:   pgtype.Bool{Bool: false, Valid: true},
 							CreatedAt:   pgtype.Timestamptz{Time: mockTime, Valid: true},
 							UpdatedAt:   pgtype.Timestamptz{Time: mockTime, Valid: true},
-						}, nil
+						}
+						if req.Recurrence != nil {
+							todo.DueAt = pgtype.Timestamptz{Time: mockTime.Add(24*time.Hour - time.Second), Valid: true}
+							todo.Recurrence = pgtype.Text{String: *req.Recurrence, Valid: true}
+						}
+						return todo, nil
+					case http.StatusBadRequest:
+						switch tt.name {
+						case "invalid description":
+							return nil, utils.ErrInvalidDescription
+						case "invalid recurrence":
+							return nil, utils.ErrInvalidRecurrence
+						}
+						return nil, utils.ErrRecurrenceWithoutDueDate
 					case http.StatusInternalServerError:
 						return nil, errors.New("unexpected error")
 					}
@@ -142,6 +194,7 @@ func TestTodoHandler_CreateTodo(t *testing.T) {
 func TestTodoHandler_ListTodos(t *testing.T) {
 	tests := []struct {
 		name           string
+		queryParam     string
 		want           want
 		setUserIDInCtx bool
 	}{
@@ -161,6 +214,42 @@ func TestTodoHandler_ListTodos(t *testing.T) {
 			},
 			setUserIDInCtx: true,
 		},
+		{
+			name:       "successful list todos - due date filters",
+			queryParam: "due_after=2024-01-01&due_before=2024-01-02T00:00:00Z&overdue=true",
+			want: want{
+				status:   http.StatusOK,
+				respFile: "testdata/list_todos/200_resp_due.json.golden",
+			},
+			setUserIDInCtx: true,
+		},
+		{
+			name:       "successful list todos - tag filters and sort",
+			queryParam: "tag=work&tag=home&tag_mode=any&sort=-priority,due_at",
+			want: want{
+				status:   http.StatusOK,
+				respFile: "testdata/list_todos/200_resp_tags.json.golden",
+			},
+			setUserIDInCtx: true,
+		},
+		{
+			name:       "successful list todos - filters",
+			queryParam: "completed=false&created_after=2024-01-01&updated_before=2024-02-01T00:00:00Z&q=Test",
+			want: want{
+				status:   http.StatusOK,
+				respFile: "testdata/list_todos/200_resp.json.golden",
+			},
+			setUserIDInCtx: true,
+		},
+		{
+			name:       "successful list todos - page",
+			queryParam: "limit=1&cursor=prev-cursor",
+			want: want{
+				status:   http.StatusOK,
+				respFile: "testdata/list_todos/200_resp_page.json.golden",
+			},
+			setUserIDInCtx: true,
+		},
 		{
 			name: "failed to get userID from context",
 			want: want{
@@ -169,6 +258,51 @@ func TestTodoHandler_ListTodos(t *testing.T) {
 			},
 			setUserIDInCtx: false,
 		},
+		{
+			name:       "invalid request",
+			queryParam: "overdue=maybe",
+			want: want{
+				status:   http.StatusBadRequest,
+				respFile: "testdata/list_todos/400_resp.json.golden",
+			},
+			setUserIDInCtx: true,
+		},
+		{
+			name:       "invalid completed",
+			queryParam: "completed=sometimes",
+			want: want{
+				status:   http.StatusBadRequest,
+				respFile: "testdata/list_todos/400_resp.json.golden",
+			},
+			setUserIDInCtx: true,
+		},
+		{
+			name:       "invalid date",
+			queryParam: "due_before=tomorrow",
+			want: want{
+				status:   http.StatusBadRequest,
+				respFile: "testdata/list_todos/400_resp_invalid_date.json.golden",
+			},
+			setUserIDInCtx: true,
+		},
+		{
+			name:       "invalid sort",
+			queryParam: "sort=id",
+			want: want{
+				status:   http.StatusBadRequest,
+				respFile: "testdata/list_todos/400_resp_invalid_sort.json.golden",
+			},
+			setUserIDInCtx: true,
+		},
+		{
+			name:       "invalid cursor",
+			queryParam: "cursor=tampered",
+			want: want{
+				status:   http.StatusBadRequest,
+				respFile: "testdata/list_todos/400_resp_invalid_cursor.json.golden",
+			},
+			setUserIDInCtx: true,
+		},
 		{
 			name: "internal server error",
 			want: want{
@@ -184,22 +318,93 @@ func TestTodoHandler_ListTodos(t *testing.T) {
 			setup := setupTodoTest(t, tt.setUserIDInCtx)
 			defer setup.ctrl.Finish()
 
-			if tt.setUserIDInCtx {
-				setup.mockTodoService.EXPECT().ListTodos(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, userID pgtype.UUID) (*[]db.Todo, error) {
+			// ListTodos service won't be called when userID is not in context or query is invalid
+			if tt.setUserIDInCtx && tt.name != "invalid request" && tt.name != "invalid completed" {
+				setup.mockTodoService.EXPECT().ListTodos(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, userID pgtype.UUID, req services.ListTodosRequest) (*services.TodoPage, error) {
 					switch tt.want.status {
 					case http.StatusOK:
-						if tt.name != "successful list todos - empty list" {
-							return &[]db.Todo{{
+						switch tt.name {
+						case "successful list todos - empty list":
+							return &services.TodoPage{Todos: []db.Todo{}}, nil
+						case "successful list todos - due date filters":
+							assert.Equal(t, services.ListTodosRequest{DueBefore: "2024-01-02T00:00:00Z", DueAfter: "2024-01-01", Overdue: true}, req)
+							return &services.TodoPage{Todos: []db.Todo{{
+								ID:             1,
+								ListID:         10,
+								Description:    "Test todo",
+								Language:       "english",
+								Position:       pgtype.Numeric{Int: big.NewInt(100), Valid: true},
+								Completed:      pgtype.Bool{Bool: false, Valid: true},
+								CreatedAt:      pgtype.Timestamptz{Time: mockTime, Valid: true},
+								UpdatedAt:      pgtype.Timestamptz{Time: mockTime, Valid: true},
+								DueAt:          pgtype.Timestamptz{Time: mockTime.Add(12 * time.Hour), Valid: true},
+								RemindAt:       pgtype.Timestamptz{Time: mockTime.Add(9 * time.Hour), Valid: true},
+								ItemsTotal:     5,
+								ItemsCompleted: 3,
+							}}}, nil
+						case "successful list todos - tag filters and sort":
+							assert.Equal(t, services.ListTodosRequest{Sort: "-priority,due_at", TagFilter: services.TagFilter{Tags: []string{"work", "home"}, TagMode: "any"}}, req)
+							return &services.TodoPage{Todos: []db.Todo{{
 								ID:          1,
+								ListID:      10,
 								Description: "Test todo",
+								Language:    "english",
 								Position:    pgtype.Numeric{Int: big.NewInt(100), Valid: true},
 								Completed:   pgtype.Bool{Bool: false, Valid: true},
 								CreatedAt:   pgtype.Timestamptz{Time: mockTime, Valid: true},
 								UpdatedAt:   pgtype.Timestamptz{Time: mockTime, Valid: true},
-							}}, nil
-						} else {
-							return &[]db.Todo{}, nil
+								Tags:        []byte(`[{"id": 2, "name": "home", "color": "#00ff00"}, {"id": 1, "name": "work", "color": "#ff0000"}]`),
+								Priority:    3,
+							}}}, nil
+						case "successful list todos - filters":
+							completed := false
+							assert.Equal(t, services.ListTodosRequest{Completed: &completed, CreatedAfter: "2024-01-01", UpdatedBefore: "2024-02-01T00:00:00Z", Q: "Test"}, req)
+							return &services.TodoPage{Todos: []db.Todo{{
+								ID:          1,
+								ListID:      10,
+								Description: "Test todo",
+								Language:    "english",
+								Position:    pgtype.Numeric{Int: big.NewInt(100), Valid: true},
+								Completed:   pgtype.Bool{Bool: false, Valid: true},
+								CreatedAt:   pgtype.Timestamptz{Time: mockTime, Valid: true},
+								UpdatedAt:   pgtype.Timestamptz{Time: mockTime, Valid: true},
+							}}}, nil
+						case "successful list todos - page":
+							assert.Equal(t, services.PageRequest{Limit: 1, Cursor: "prev-cursor"}, req.PageRequest)
+							return &services.TodoPage{
+								Todos: []db.Todo{{
+									ID:          1,
+									ListID:      10,
+									Description: "Test todo",
+									Language:    "english",
+									Position:    pgtype.Numeric{Int: big.NewInt(100), Valid: true},
+									Completed:   pgtype.Bool{Bool: false, Valid: true},
+									CreatedAt:   pgtype.Timestamptz{Time: mockTime, Valid: true},
+									UpdatedAt:   pgtype.Timestamptz{Time: mockTime, Valid: true},
+								}},
+								NextCursor: "next-cursor",
+								HasMore:    true,
+							}, nil
+						default:
+							return &services.TodoPage{Todos: []db.Todo{{
+								ID:          1,
+								ListID:      10,
+								Description: "Test todo",
+								Language:    "english",
+								Position:    pgtype.Numeric{Int: big.NewInt(100), Valid: true},
+								Completed:   pgtype.Bool{Bool: false, Valid: true},
+								CreatedAt:   pgtype.Timestamptz{Time: mockTime, Valid: true},
+								UpdatedAt:   pgtype.Timestamptz{Time: mockTime, Valid: true},
+							}}}, nil
+						}
+					case http.StatusBadRequest:
+						if tt.name == "invalid sort" {
+							return nil, utils.ErrInvalidSort
 						}
+						if tt.name == "invalid cursor" {
+							return nil, utils.ErrInvalidCursor
+						}
+						return nil, utils.ErrInvalidDate
 					case http.StatusInternalServerError:
 						return nil, errors.New("unexpected error")
 					}
@@ -207,7 +412,7 @@ func TestTodoHandler_ListTodos(t *testing.T) {
 				})
 			}
 
-			setup.context.Request = httptest.NewRequest(http.MethodGet, "/todos", nil)
+			setup.context.Request = httptest.NewRequest(http.MethodGet, "/todos?"+tt.queryParam, nil)
 			setup.router.GET("/todos", setup.todoHandler.ListTodos)
 			setup.router.ServeHTTP(setup.recorder, setup.context.Request)
 
@@ -234,7 +439,7 @@ func TestTodoHandler_SearchTodos(t *testing.T) {
 		},
 		{
 			name:       "successful search todos - empty list",
-			queryParam: "keyword=Void",
+			queryParam: "keyword=a%26",
 			want: want{
 				status:   http.StatusOK,
 				respFile: "testdata/search_todos/200_resp_empty.json.golden",
@@ -277,20 +482,25 @@ func TestTodoHandler_SearchTodos(t *testing.T) {
 
 			// SearchTodos service won't be called when userID is not in context or request body is invalid
 			if tt.setUserIDInCtx && tt.name != "invalid request" {
-				setup.mockTodoService.EXPECT().SearchTodos(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, userID pgtype.UUID, keyword string) (*[]db.Todo, error) {
+				setup.mockTodoService.EXPECT().SearchTodos(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, userID pgtype.UUID, req services.SearchTodosRequest) (*services.TodoPage, error) {
 					switch tt.want.status {
 					case http.StatusOK:
 						if tt.name != "successful search todos - empty list" {
-							return &[]db.Todo{{
-								ID:          1,
-								Description: "Test todo",
-								Position:    pgtype.Numeric{Int: big.NewInt(100), Valid: true},
-								Completed:   pgtype.Bool{Bool: false, Valid: true},
-								CreatedAt:   pgtype.Timestamptz{Time: mockTime, Valid: true},
-								UpdatedAt:   pgtype.Timestamptz{Time: mockTime, Valid: true},
-							}}, nil
+							return &services.TodoPage{
+								Todos: []db.Todo{{
+									ID:          1,
+									ListID:      10,
+									Description: "Test <todo>",
+									Language:    "english",
+									Position:    pgtype.Numeric{Int: big.NewInt(100), Valid: true},
+									Completed:   pgtype.Bool{Bool: false, Valid: true},
+									CreatedAt:   pgtype.Timestamptz{Time: mockTime, Valid: true},
+									UpdatedAt:   pgtype.Timestamptz{Time: mockTime, Valid: true},
+								}},
+								Headlines: map[int32]string{1: "\x02Test\x03 <todo>"},
+							}, nil
 						} else {
-							return &[]db.Todo{}, nil
+							return &services.TodoPage{Todos: []db.Todo{}}, nil
 						}
 					case http.StatusInternalServerError:
 						return nil, errors.New("unexpected error")
@@ -380,7 +590,9 @@ func TestTodoHandler_UpdateTodo(t *testing.T) {
 					case http.StatusOK:
 						return &db.Todo{
 							ID:          todoID,
+							ListID:      10,
 							Description: req.Description,
+							Language:    "english",
 							Position:    pgtype.Numeric{Int: big.NewInt(req.Position), Valid: true},
 							Completed:   pgtype.Bool{Bool: req.Completed, Valid: true},
 							CreatedAt:   pgtype.Timestamptz{Time: mockTime, Valid: true},
@@ -406,6 +618,93 @@ func TestTodoHandler_UpdateTodo(t *testing.T) {
 }
 
 // Let's say a todo C[pos=300] has been moved inbetween A[pos=100] and B[pos=200]
+func TestTodoHandler_ListTodoOccurrences(t *testing.T) {
+	tests := []struct {
+		name           string
+		todoID         string
+		queryParam     string
+		want           want
+		setUserIDInCtx bool
+	}{
+		{
+			name:       "successful list occurrences",
+			todoID:     "1",
+			queryParam: "count=2",
+			want: want{
+				status:   http.StatusOK,
+				respFile: "testdata/list_todo_occurrences/200_resp.json.golden",
+			},
+			setUserIDInCtx: true,
+		},
+		{
+			name:   "failed to get userID from context",
+			todoID: "1",
+			want: want{
+				status:   http.StatusUnauthorized,
+				respFile: "testdata/list_todo_occurrences/401_resp.json.golden",
+			},
+			setUserIDInCtx: false,
+		},
+		{
+			name:       "invalid request",
+			todoID:     "1",
+			queryParam: "count=51",
+			want: want{
+				status:   http.StatusBadRequest,
+				respFile: "testdata/list_todo_occurrences/400_resp.json.golden",
+			},
+			setUserIDInCtx: true,
+		},
+		{
+			name:   "specified todo not found",
+			todoID: "1000",
+			want: want{
+				status:   http.StatusNotFound,
+				respFile: "testdata/list_todo_occurrences/404_resp.json.golden",
+			},
+			setUserIDInCtx: true,
+		},
+		{
+			name:   "internal server error",
+			todoID: "1",
+			want: want{
+				status:   http.StatusInternalServerError,
+				respFile: "testdata/list_todo_occurrences/500_resp.json.golden",
+			},
+			setUserIDInCtx: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setup := setupTodoTest(t, tt.setUserIDInCtx)
+			defer setup.ctrl.Finish()
+
+			// ListOccurrences service won't be called when userID is not in context or query is invalid
+			if tt.setUserIDInCtx && tt.name != "invalid request" {
+				setup.mockTodoService.EXPECT().ListOccurrences(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, userID pgtype.UUID, todoID int32, req services.ListOccurrencesRequest) ([]time.Time, error) {
+					switch tt.want.status {
+					case http.StatusOK:
+						assert.Equal(t, 2, req.Count)
+						return []time.Time{mockTime.AddDate(0, 0, 7), mockTime.AddDate(0, 0, 14)}, nil
+					case http.StatusNotFound:
+						return nil, utils.ErrNoRowsMatchedSQLC
+					case http.StatusInternalServerError:
+						return nil, errors.New("unexpected error")
+					}
+					return nil, errors.New("error from mock")
+				})
+			}
+
+			setup.context.Request = httptest.NewRequest(http.MethodGet, "/todos/"+tt.todoID+"/occurrences?"+tt.queryParam, nil)
+			setup.router.GET("/todos/:id/occurrences", setup.todoHandler.ListTodoOccurrences)
+			setup.router.ServeHTTP(setup.recorder, setup.context.Request)
+
+			testutils.AssertResponse(t, setup.recorder.Result(), tt.want.status, testutils.LoadFile(t, tt.want.respFile))
+		})
+	}
+}
+
 func TestTodoHandler_UpdateTodoPosition(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -478,7 +777,9 @@ func TestTodoHandler_UpdateTodoPosition(t *testing.T) {
 					case http.StatusOK:
 						return &db.Todo{
 							ID:          todoID,
+							ListID:      10,
 							Description: "Updated todo",
+							Language:    "english",
 							Position:    pgtype.Numeric{Int: big.NewInt(150), Valid: true},
 							Completed:   pgtype.Bool{Bool: false, Valid: true},
 							CreatedAt:   pgtype.Timestamptz{Time: mockTime, Valid: true},
@@ -503,6 +804,83 @@ func TestTodoHandler_UpdateTodoPosition(t *testing.T) {
 	}
 }
 
+func TestTodoHandler_MoveTodo(t *testing.T) {
+	tests := []struct {
+		name           string
+		todoID         string
+		reqFile        string
+		want           want
+		setUserIDInCtx bool
+	}{
+		{
+			name:    "successful move todo",
+			todoID:  "3",
+			reqFile: "testdata/move_todo/200_req.json.golden",
+			want: want{
+				status:   http.StatusOK,
+				respFile: "testdata/move_todo/200_resp.json.golden",
+			},
+			setUserIDInCtx: true,
+		},
+		{
+			name:    "invalid request",
+			todoID:  "3",
+			reqFile: "testdata/move_todo/400_req.json.golden",
+			want: want{
+				status:   http.StatusBadRequest,
+				respFile: "testdata/move_todo/400_resp.json.golden",
+			},
+			setUserIDInCtx: true,
+		},
+		{
+			name:    "specified todo or list not found",
+			todoID:  "1000",
+			reqFile: "testdata/move_todo/200_req.json.golden",
+			want: want{
+				status:   http.StatusNotFound,
+				respFile: "testdata/move_todo/404_resp.json.golden",
+			},
+			setUserIDInCtx: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setup := setupTodoTest(t, tt.setUserIDInCtx)
+			defer setup.ctrl.Finish()
+
+			// MoveTodo service won't be called when the list is missing from the request body
+			if tt.name != "invalid request" {
+				setup.mockTodoService.EXPECT().MoveTodo(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, userID pgtype.UUID, todoID int32, req services.MoveTodoRequest) (*db.Todo, error) {
+					switch tt.want.status {
+					case http.StatusOK:
+						return &db.Todo{
+							ID:          todoID,
+							ListID:      req.ListID,
+							Description: "Updated todo",
+							Language:    "english",
+							Position:    pgtype.Numeric{Int: big.NewInt(*req.Prevpos + 100), Valid: true},
+							Completed:   pgtype.Bool{Bool: false, Valid: true},
+							CreatedAt:   pgtype.Timestamptz{Time: mockTime, Valid: true},
+							UpdatedAt:   pgtype.Timestamptz{Time: mockTime, Valid: true},
+						}, nil
+					case http.StatusNotFound:
+						return nil, utils.ErrNoRowsMatchedSQLC
+					}
+					return nil, errors.New("error from mock")
+				})
+			}
+
+			setup.context.Request = httptest.NewRequest(http.MethodPatch, "/todos/"+tt.todoID+"/list", bytes.NewReader(testutils.LoadFile(t, tt.reqFile)))
+			setup.context.Request.Header.Set("Content-Type", "application/json")
+			setup.router.PATCH("/todos/:id/list", setup.todoHandler.MoveTodo)
+			setup.router.ServeHTTP(setup.recorder, setup.context.Request)
+
+			testutils.AssertResponse(t, setup.recorder.Result(), tt.want.status, testutils.LoadFile(t, tt.want.respFile))
+		})
+	}
+}
+
 func TestTodoHandler_DeleteTodo(t *testing.T) {
 	tests := []struct {
 		name           string
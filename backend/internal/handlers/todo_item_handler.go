@@ -0,0 +1,262 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+	"todo-app/internal/db"
+	"todo-app/internal/services"
+	"todo-app/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TodoItemHandler struct {
+	TodoItemService services.ITodoItemService
+}
+
+type TodoItemResponse struct {
+	ID          int32     `json:"id"`
+	Description string    `json:"description"`
+	Position    int64     `json:"position"`
+	Completed   bool      `json:"completed"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+func NewTodoItemHandler(todoItemService services.ITodoItemService) *TodoItemHandler {
+	return &TodoItemHandler{TodoItemService: todoItemService}
+}
+
+// @Summary Add an item to the checklist of a todo
+// @Tags Todo
+// @Accept json
+// @Produce json
+// @Param id path int true "Todo ID"
+// @Param item body services.CreateTodoItemRequest true "Item details"
+// @Security BearerAuth
+// @Success 201 {object} TodoItemResponse
+// @Failure 400 {object} gin.H "{"error": "Invalid request"}"
+// @Failure 404 {object} gin.H "{"error": "Resource not found"}"
+// @Failure 500 {object} gin.H "{"error": "Internal server error"}"
+// @Router /todos/{id}/items [post]
+func (h *TodoItemHandler) CreateTodoItem(ctx *gin.Context) {
+	userIDUuid, err := utils.GetUIDFromCtxAndCreateRespUponErr(ctx)
+	if err != nil {
+		return
+	}
+
+	todoID, err := strconv.Atoi(ctx.Param("id"))
+	var req services.CreateTodoItemRequest
+	if reqErr := ctx.ShouldBindJSON(&req); reqErr != nil || err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": utils.MsgInvalidReq})
+		return
+	}
+
+	item, err := h.TodoItemService.CreateItem(ctx, userIDUuid, int32(todoID), req)
+	if err != nil {
+		log.Println(err.Error())
+
+		if err == utils.ErrNoRowsMatchedSQLC {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": utils.MsgResourceNotFound})
+			return
+		}
+
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": utils.MsgInternalServerErr})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, toTodoItemResponse(*item))
+}
+
+// @Summary List the checklist of a todo
+// @Tags Todo
+// @Produce json
+// @Param id path int true "Todo ID"
+// @Security BearerAuth
+// @Success 200 {array} TodoItemResponse
+// @Failure 400 {object} gin.H "{"error": "Invalid request"}"
+// @Failure 404 {object} gin.H "{"error": "Resource not found"}"
+// @Failure 500 {object} gin.H "{"error": "Internal server error"}"
+// @Router /todos/{id}/items [get]
+func (h *TodoItemHandler) ListTodoItems(ctx *gin.Context) {
+	userIDUuid, err := utils.GetUIDFromCtxAndCreateRespUponErr(ctx)
+	if err != nil {
+		return
+	}
+
+	todoID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": utils.MsgInvalidReq})
+		return
+	}
+
+	items, err := h.TodoItemService.ListItems(ctx, userIDUuid, int32(todoID))
+	if err != nil {
+		log.Println(err.Error())
+
+		if err == utils.ErrNoRowsMatchedSQLC {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": utils.MsgResourceNotFound})
+			return
+		}
+
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": utils.MsgInternalServerErr})
+		return
+	}
+
+	itemResponses := make([]TodoItemResponse, len(*items))
+	for i, item := range *items {
+		itemResponses[i] = toTodoItemResponse(item)
+	}
+
+	ctx.JSON(http.StatusOK, itemResponses)
+}
+
+// @Summary Update or complete a checklist item
+// @Tags Todo
+// @Accept json
+// @Produce json
+// @Param id path int true "Todo ID"
+// @Param itemId path int true "Item ID"
+// @Param item body services.UpdateTodoItemRequest true "Updated item details"
+// @Security BearerAuth
+// @Success 200 {object} TodoItemResponse
+// @Failure 400 {object} gin.H "{"error": "Invalid request"}"
+// @Failure 404 {object} gin.H "{"error": "Resource not found"}"
+// @Failure 500 {object} gin.H "{"error": "Internal server error"}"
+// @Router /todos/{id}/items/{itemId} [put]
+func (h *TodoItemHandler) UpdateTodoItem(ctx *gin.Context) {
+	userIDUuid, err := utils.GetUIDFromCtxAndCreateRespUponErr(ctx)
+	if err != nil {
+		return
+	}
+
+	todoID, itemID, err := itemPathParams(ctx)
+	var req services.UpdateTodoItemRequest
+	if reqErr := ctx.ShouldBindJSON(&req); reqErr != nil || err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": utils.MsgInvalidReq})
+		return
+	}
+
+	item, err := h.TodoItemService.UpdateItem(ctx, userIDUuid, todoID, itemID, req)
+	if err != nil {
+		log.Println(err.Error())
+
+		if err == utils.ErrNoRowsMatchedSQLC {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": utils.MsgResourceNotFound})
+			return
+		}
+
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": utils.MsgInternalServerErr})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, toTodoItemResponse(*item))
+}
+
+// @Summary Update a checklist item's position
+// @Tags Todo
+// @Accept json
+// @Produce json
+// @Param id path int true "Todo ID"
+// @Param itemId path int true "Item ID"
+// @Param position body services.UpdateTodoPositionRequest true "Positions of the items before and after the new place"
+// @Security BearerAuth
+// @Success 200 {object} TodoItemResponse
+// @Failure 400 {object} gin.H "{"error": "Invalid request"}"
+// @Failure 404 {object} gin.H "{"error": "Resource not found"}"
+// @Failure 500 {object} gin.H "{"error": "Internal server error"}"
+// @Router /todos/{id}/items/{itemId}/position [patch]
+func (h *TodoItemHandler) UpdateTodoItemPosition(ctx *gin.Context) {
+	userIDUuid, err := utils.GetUIDFromCtxAndCreateRespUponErr(ctx)
+	if err != nil {
+		return
+	}
+
+	todoID, itemID, err := itemPathParams(ctx)
+	var req services.UpdateTodoPositionRequest
+	if reqErr := ctx.ShouldBindJSON(&req); reqErr != nil || err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": utils.MsgInvalidReq})
+		return
+	}
+
+	item, err := h.TodoItemService.UpdateItemPosition(ctx, userIDUuid, todoID, itemID, req)
+	if err != nil {
+		log.Println(err.Error())
+
+		if err == utils.ErrNoRowsMatchedSQLC {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": utils.MsgResourceNotFound})
+			return
+		}
+
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": utils.MsgInternalServerErr})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, toTodoItemResponse(*item))
+}
+
+// @Summary Delete a checklist item
+// @Tags Todo
+// @Produce json
+// @Param id path int true "Todo ID"
+// @Param itemId path int true "Item ID"
+// @Security BearerAuth
+// @Success 200 {object} gin.H "{"message": "Item deleted"}"
+// @Failure 400 {object} gin.H "{"error": "Invalid request"}"
+// @Failure 404 {object} gin.H "{"error": "Resource not found"}"
+// @Failure 500 {object} gin.H "{"error": "Internal server error"}"
+// @Router /todos/{id}/items/{itemId} [delete]
+func (h *TodoItemHandler) DeleteTodoItem(ctx *gin.Context) {
+	userIDUuid, err := utils.GetUIDFromCtxAndCreateRespUponErr(ctx)
+	if err != nil {
+		return
+	}
+
+	todoID, itemID, err := itemPathParams(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": utils.MsgInvalidReq})
+		return
+	}
+
+	err = h.TodoItemService.DeleteItem(ctx, userIDUuid, todoID, itemID)
+	if err != nil {
+		log.Println(err.Error())
+
+		if err == utils.ErrNoRowsMatchedSQLC {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": utils.MsgResourceNotFound})
+			return
+		}
+
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": utils.MsgInternalServerErr})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "Item deleted"})
+}
+
+func itemPathParams(ctx *gin.Context) (int32, int32, error) {
+	todoID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		return 0, 0, err
+	}
+
+	itemID, err := strconv.Atoi(ctx.Param("itemId"))
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return int32(todoID), int32(itemID), nil
+}
+
+func toTodoItemResponse(item db.TodoItem) TodoItemResponse {
+	return TodoItemResponse{
+		ID:          item.ID,
+		Description: item.Description,
+		Position:    item.Position.Int.Int64(),
+		Completed:   item.Completed,
+		CreatedAt:   item.CreatedAt.Time,
+		UpdatedAt:   item.UpdatedAt.Time,
+	}
+}
@@ -0,0 +1,398 @@
+package handlers_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"todo-app/internal/db"
+	"todo-app/internal/handlers"
+	"todo-app/internal/services"
+	mock_services "todo-app/internal/services/_mock"
+	"todo-app/internal/utils"
+	"todo-app/internal/utils/testutils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/mock/gomock"
+)
+
+type todoItemTestSetup struct {
+	ctrl                *gomock.Controller
+	mockTodoItemService *mock_services.MockITodoItemService
+	todoItemHandler     *handlers.TodoItemHandler
+	router              *gin.Engine
+	recorder            *httptest.ResponseRecorder
+	context             *gin.Context
+}
+
+func setupTodoItemTest(t *testing.T, setUserIDInCtx bool) *todoItemTestSetup {
+	ctrl := gomock.NewController(t)
+	mockTodoItemService := mock_services.NewMockITodoItemService(ctrl)
+	todoItemHandler := handlers.NewTodoItemHandler(mockTodoItemService)
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	ctx, r := gin.CreateTestContext(w)
+
+	if setUserIDInCtx {
+		ctx.Set("userID", uIDStr)
+		r.Use(func(c *gin.Context) {
+			c.Set("userID", uIDStr)
+			c.Next()
+		})
+	}
+
+	return &todoItemTestSetup{
+		ctrl:                ctrl,
+		mockTodoItemService: mockTodoItemService,
+		todoItemHandler:     todoItemHandler,
+		router:              r,
+		recorder:            w,
+		context:             ctx,
+	}
+}
+
+func mockTodoItem(completed bool, position int64) *db.TodoItem {
+	return &db.TodoItem{
+		ID:          2,
+		TodoID:      1,
+		Description: "Passport",
+		Position:    pgtype.Numeric{Int: big.NewInt(position), Valid: true},
+		Completed:   completed,
+		CreatedAt:   pgtype.Timestamptz{Time: mockTime, Valid: true},
+		UpdatedAt:   pgtype.Timestamptz{Time: mockTime, Valid: true},
+	}
+}
+
+func TestTodoItemHandler_CreateTodoItem(t *testing.T) {
+	tests := []struct {
+		name           string
+		todoID         string
+		reqFile        string
+		want           want
+		setUserIDInCtx bool
+	}{
+		{
+			name:    "successful create item",
+			todoID:  "1",
+			reqFile: "testdata/create_todo_item/201_req.json.golden",
+			want: want{
+				status:   http.StatusCreated,
+				respFile: "testdata/create_todo_item/201_resp.json.golden",
+			},
+			setUserIDInCtx: true,
+		},
+		{
+			name:    "failed to get userID from context",
+			todoID:  "1",
+			reqFile: "testdata/create_todo_item/201_req.json.golden",
+			want: want{
+				status:   http.StatusUnauthorized,
+				respFile: "testdata/create_todo_item/401_resp.json.golden",
+			},
+			setUserIDInCtx: false,
+		},
+		{
+			name:    "invalid request",
+			todoID:  "1",
+			reqFile: "testdata/create_todo_item/400_req.json.golden",
+			want: want{
+				status:   http.StatusBadRequest,
+				respFile: "testdata/create_todo_item/400_resp.json.golden",
+			},
+			setUserIDInCtx: true,
+		},
+		{
+			name:    "specified todo not found",
+			todoID:  "1000",
+			reqFile: "testdata/create_todo_item/201_req.json.golden",
+			want: want{
+				status:   http.StatusNotFound,
+				respFile: "testdata/create_todo_item/404_resp.json.golden",
+			},
+			setUserIDInCtx: true,
+		},
+		{
+			name:    "internal server error",
+			todoID:  "1",
+			reqFile: "testdata/create_todo_item/201_req.json.golden",
+			want: want{
+				status:   http.StatusInternalServerError,
+				respFile: "testdata/create_todo_item/500_resp.json.golden",
+			},
+			setUserIDInCtx: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setup := setupTodoItemTest(t, tt.setUserIDInCtx)
+			defer setup.ctrl.Finish()
+
+			// CreateItem service won't be called when userID is not in context or request body is invalid
+			if tt.setUserIDInCtx && tt.name != "invalid request" {
+				setup.mockTodoItemService.EXPECT().CreateItem(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, userID pgtype.UUID, todoID int32, req services.CreateTodoItemRequest) (*db.TodoItem, error) {
+					switch tt.want.status {
+					case http.StatusCreated:
+						return mockTodoItem(false, 100), nil
+					case http.StatusNotFound:
+						return nil, utils.ErrNoRowsMatchedSQLC
+					case http.StatusInternalServerError:
+						return nil, errors.New("unexpected error")
+					}
+					return nil, errors.New("error from mock")
+				})
+			}
+
+			setup.context.Request = httptest.NewRequest(http.MethodPost, "/todos/"+tt.todoID+"/items", bytes.NewReader(testutils.LoadFile(t, tt.reqFile)))
+			setup.context.Request.Header.Set("Content-Type", "application/json")
+			setup.router.POST("/todos/:id/items", setup.todoItemHandler.CreateTodoItem)
+			setup.router.ServeHTTP(setup.recorder, setup.context.Request)
+
+			testutils.AssertResponse(t, setup.recorder.Result(), tt.want.status, testutils.LoadFile(t, tt.want.respFile))
+		})
+	}
+}
+
+func TestTodoItemHandler_ListTodoItems(t *testing.T) {
+	tests := []struct {
+		name           string
+		todoID         string
+		want           want
+		setUserIDInCtx bool
+	}{
+		{
+			name:   "successful list items",
+			todoID: "1",
+			want: want{
+				status:   http.StatusOK,
+				respFile: "testdata/list_todo_items/200_resp.json.golden",
+			},
+			setUserIDInCtx: true,
+		},
+		{
+			name:   "invalid request",
+			todoID: "invalid",
+			want: want{
+				status:   http.StatusBadRequest,
+				respFile: "testdata/list_todo_items/400_resp.json.golden",
+			},
+			setUserIDInCtx: true,
+		},
+		{
+			name:   "specified todo not found",
+			todoID: "1000",
+			want: want{
+				status:   http.StatusNotFound,
+				respFile: "testdata/list_todo_items/404_resp.json.golden",
+			},
+			setUserIDInCtx: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setup := setupTodoItemTest(t, tt.setUserIDInCtx)
+			defer setup.ctrl.Finish()
+
+			if tt.setUserIDInCtx && tt.name != "invalid request" {
+				setup.mockTodoItemService.EXPECT().ListItems(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, userID pgtype.UUID, todoID int32) (*[]db.TodoItem, error) {
+					switch tt.want.status {
+					case http.StatusOK:
+						return &[]db.TodoItem{*mockTodoItem(true, 100)}, nil
+					case http.StatusNotFound:
+						return nil, utils.ErrNoRowsMatchedSQLC
+					}
+					return nil, errors.New("error from mock")
+				})
+			}
+
+			setup.context.Request = httptest.NewRequest(http.MethodGet, "/todos/"+tt.todoID+"/items", nil)
+			setup.router.GET("/todos/:id/items", setup.todoItemHandler.ListTodoItems)
+			setup.router.ServeHTTP(setup.recorder, setup.context.Request)
+
+			testutils.AssertResponse(t, setup.recorder.Result(), tt.want.status, testutils.LoadFile(t, tt.want.respFile))
+		})
+	}
+}
+
+func TestTodoItemHandler_UpdateTodoItem(t *testing.T) {
+	tests := []struct {
+		name           string
+		path           string
+		want           want
+		setUserIDInCtx bool
+	}{
+		{
+			name: "successful update item",
+			path: "/todos/1/items/2",
+			want: want{
+				status:   http.StatusOK,
+				respFile: "testdata/update_todo_item/200_resp.json.golden",
+			},
+			setUserIDInCtx: true,
+		},
+		{
+			name: "invalid request",
+			path: "/todos/1/items/invalid",
+			want: want{
+				status:   http.StatusBadRequest,
+				respFile: "testdata/update_todo_item/400_resp.json.golden",
+			},
+			setUserIDInCtx: true,
+		},
+		{
+			name: "specified item not found",
+			path: "/todos/1/items/1000",
+			want: want{
+				status:   http.StatusNotFound,
+				respFile: "testdata/update_todo_item/404_resp.json.golden",
+			},
+			setUserIDInCtx: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setup := setupTodoItemTest(t, tt.setUserIDInCtx)
+			defer setup.ctrl.Finish()
+
+			if tt.setUserIDInCtx && tt.name != "invalid request" {
+				setup.mockTodoItemService.EXPECT().UpdateItem(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, userID pgtype.UUID, todoID, itemID int32, req services.UpdateTodoItemRequest) (*db.TodoItem, error) {
+					switch tt.want.status {
+					case http.StatusOK:
+						return mockTodoItem(req.Completed, 100), nil
+					case http.StatusNotFound:
+						return nil, utils.ErrNoRowsMatchedSQLC
+					}
+					return nil, errors.New("error from mock")
+				})
+			}
+
+			setup.context.Request = httptest.NewRequest(http.MethodPut, tt.path, bytes.NewReader(testutils.LoadFile(t, "testdata/update_todo_item/200_req.json.golden")))
+			setup.context.Request.Header.Set("Content-Type", "application/json")
+			setup.router.PUT("/todos/:id/items/:itemId", setup.todoItemHandler.UpdateTodoItem)
+			setup.router.ServeHTTP(setup.recorder, setup.context.Request)
+
+			testutils.AssertResponse(t, setup.recorder.Result(), tt.want.status, testutils.LoadFile(t, tt.want.respFile))
+		})
+	}
+}
+
+func TestTodoItemHandler_UpdateTodoItemPosition(t *testing.T) {
+	tests := []struct {
+		name           string
+		path           string
+		want           want
+		setUserIDInCtx bool
+	}{
+		{
+			name: "successful update item position",
+			path: "/todos/1/items/2/position",
+			want: want{
+				status:   http.StatusOK,
+				respFile: "testdata/update_todo_item_position/200_resp.json.golden",
+			},
+			setUserIDInCtx: true,
+		},
+		{
+			name: "specified item not found",
+			path: "/todos/1/items/1000/position",
+			want: want{
+				status:   http.StatusNotFound,
+				respFile: "testdata/update_todo_item_position/404_resp.json.golden",
+			},
+			setUserIDInCtx: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setup := setupTodoItemTest(t, tt.setUserIDInCtx)
+			defer setup.ctrl.Finish()
+
+			setup.mockTodoItemService.EXPECT().UpdateItemPosition(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, userID pgtype.UUID, todoID, itemID int32, req services.UpdateTodoPositionRequest) (*db.TodoItem, error) {
+				switch tt.want.status {
+				case http.StatusOK:
+					return mockTodoItem(false, (req.Prevpos+req.Nextpos)/2), nil
+				case http.StatusNotFound:
+					return nil, utils.ErrNoRowsMatchedSQLC
+				}
+				return nil, errors.New("error from mock")
+			})
+
+			setup.context.Request = httptest.NewRequest(http.MethodPatch, tt.path, bytes.NewReader(testutils.LoadFile(t, "testdata/update_todo_item_position/200_req.json.golden")))
+			setup.context.Request.Header.Set("Content-Type", "application/json")
+			setup.router.PATCH("/todos/:id/items/:itemId/position", setup.todoItemHandler.UpdateTodoItemPosition)
+			setup.router.ServeHTTP(setup.recorder, setup.context.Request)
+
+			testutils.AssertResponse(t, setup.recorder.Result(), tt.want.status, testutils.LoadFile(t, tt.want.respFile))
+		})
+	}
+}
+
+func TestTodoItemHandler_DeleteTodoItem(t *testing.T) {
+	tests := []struct {
+		name           string
+		path           string
+		want           want
+		setUserIDInCtx bool
+	}{
+		{
+			name: "successful delete item",
+			path: "/todos/1/items/2",
+			want: want{
+				status:   http.StatusOK,
+				respFile: "testdata/delete_todo_item/200_resp.json.golden",
+			},
+			setUserIDInCtx: true,
+		},
+		{
+			name: "invalid request",
+			path: "/todos/invalid/items/2",
+			want: want{
+				status:   http.StatusBadRequest,
+				respFile: "testdata/delete_todo_item/400_resp.json.golden",
+			},
+			setUserIDInCtx: true,
+		},
+		{
+			name: "specified item not found",
+			path: "/todos/1/items/1000",
+			want: want{
+				status:   http.StatusNotFound,
+				respFile: "testdata/delete_todo_item/404_resp.json.golden",
+			},
+			setUserIDInCtx: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setup := setupTodoItemTest(t, tt.setUserIDInCtx)
+			defer setup.ctrl.Finish()
+
+			if tt.setUserIDInCtx && tt.name != "invalid request" {
+				setup.mockTodoItemService.EXPECT().DeleteItem(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, userID pgtype.UUID, todoID, itemID int32) error {
+					switch tt.want.status {
+					case http.StatusOK:
+						return nil
+					case http.StatusNotFound:
+						return utils.ErrNoRowsMatchedSQLC
+					}
+					return errors.New("error from mock")
+				})
+			}
+
+			setup.context.Request = httptest.NewRequest(http.MethodDelete, tt.path, nil)
+			setup.router.DELETE("/todos/:id/items/:itemId", setup.todoItemHandler.DeleteTodoItem)
+			setup.router.ServeHTTP(setup.recorder, setup.context.Request)
+
+			testutils.AssertResponse(t, setup.recorder.Result(), tt.want.status, testutils.LoadFile(t, tt.want.respFile))
+		})
+	}
+}
@@ -0,0 +1,150 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"todo-app/internal/services"
+	"todo-app/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TwoFactorHandler struct {
+	TwoFactorService services.ITwoFactorService
+}
+
+type StartTOTPEnrollmentResponse struct {
+	Secret     string `json:"secret"`
+	OtpauthURI string `json:"otpauth_uri"`
+}
+
+type ConfirmTOTPEnrollmentResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+func NewTwoFactorHandler(twoFactorService services.ITwoFactorService) *TwoFactorHandler {
+	return &TwoFactorHandler{TwoFactorService: twoFactorService}
+}
+
+// @Summary Start enrolling a TOTP authenticator
+// @Description Register the secret (or the otpauth URI as a QR code) in an authenticator app and confirm it with a code.
+// @Tags User
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} StartTOTPEnrollmentResponse
+// @Failure 409 {object} gin.H "{"error": "Two-factor authentication is already enabled"}"
+// @Failure 500 {object} gin.H "{"error": "Internal server error"}"
+// @Router /me/2fa/totp [post]
+func (h *TwoFactorHandler) StartMyTOTPEnrollment(ctx *gin.Context) {
+	userIDUuid, err := utils.GetUIDFromCtxAndCreateRespUponErr(ctx)
+	if err != nil {
+		return
+	}
+
+	enrollment, err := h.TwoFactorService.StartTOTPEnrollment(ctx, userIDUuid)
+	if err != nil {
+		log.Println(err.Error())
+
+		if err == utils.ErrTwoFactorAlreadyEnabled {
+			ctx.JSON(http.StatusConflict, gin.H{"error": utils.MsgTwoFactorAlreadyEnabled})
+			return
+		}
+
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": utils.MsgInternalServerErr})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, StartTOTPEnrollmentResponse{Secret: enrollment.Secret, OtpauthURI: enrollment.OtpauthURI})
+}
+
+// @Summary Confirm the TOTP enrolment with a first code and enable two-factor authentication
+// @Description The recovery codes are shown only once. Each of them can be used once instead of a code.
+// @Tags User
+// @Accept json
+// @Produce json
+// @Param code body services.ConfirmTOTPRequest true "code from the authenticator app"
+// @Security BearerAuth
+// @Success 200 {object} ConfirmTOTPEnrollmentResponse
+// @Failure 400 {object} gin.H "{"error": "Invalid request"} or {"error": "Invalid two-factor authentication code"} or {"error": "Two-factor authentication enrolment has not been started"}"
+// @Failure 409 {object} gin.H "{"error": "Two-factor authentication is already enabled"}"
+// @Failure 500 {object} gin.H "{"error": "Internal server error"}"
+// @Router /me/2fa/totp/confirm [post]
+func (h *TwoFactorHandler) ConfirmMyTOTPEnrollment(ctx *gin.Context) {
+	userIDUuid, err := utils.GetUIDFromCtxAndCreateRespUponErr(ctx)
+	if err != nil {
+		return
+	}
+
+	var req services.ConfirmTOTPRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": utils.MsgInvalidReq})
+		return
+	}
+
+	recoveryCodes, err := h.TwoFactorService.ConfirmTOTPEnrollment(ctx, userIDUuid, req)
+	if err != nil {
+		log.Println(err.Error())
+
+		if err == utils.ErrInvalidTwoFactorCode {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": utils.MsgInvalidTwoFactorCode})
+			return
+		}
+
+		if err == utils.ErrTwoFactorNotEnrolled {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": utils.MsgTwoFactorNotEnrolled})
+			return
+		}
+
+		if err == utils.ErrTwoFactorAlreadyEnabled {
+			ctx.JSON(http.StatusConflict, gin.H{"error": utils.MsgTwoFactorAlreadyEnabled})
+			return
+		}
+
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": utils.MsgInternalServerErr})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, ConfirmTOTPEnrollmentResponse{RecoveryCodes: recoveryCodes})
+}
+
+// @Summary Disable two-factor authentication
+// @Tags User
+// @Accept json
+// @Produce json
+// @Param password body services.DisableTOTPRequest true "current password"
+// @Security BearerAuth
+// @Success 200 {object} gin.H "{"message": "Two-factor authentication disabled"}"
+// @Failure 400 {object} gin.H "{"error": "Invalid request"} or {"error": "Current password is incorrect"} or {"error": "Two-factor authentication is not enabled"}"
+// @Failure 500 {object} gin.H "{"error": "Internal server error"}"
+// @Router /me/2fa/totp [delete]
+func (h *TwoFactorHandler) DisableMyTOTP(ctx *gin.Context) {
+	userIDUuid, err := utils.GetUIDFromCtxAndCreateRespUponErr(ctx)
+	if err != nil {
+		return
+	}
+
+	var req services.DisableTOTPRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": utils.MsgInvalidReq})
+		return
+	}
+
+	if err := h.TwoFactorService.DisableTOTP(ctx, userIDUuid, req); err != nil {
+		log.Println(err.Error())
+
+		if err == utils.ErrInvalidCurrentPassword {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": utils.MsgInvalidCurrentPassword})
+			return
+		}
+
+		if err == utils.ErrTwoFactorNotEnabled {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": utils.MsgTwoFactorNotEnabled})
+			return
+		}
+
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": utils.MsgInternalServerErr})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "Two-factor authentication disabled"})
+}
@@ -0,0 +1,321 @@
+package handlers_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"todo-app/internal/handlers"
+	"todo-app/internal/services"
+	mock_services "todo-app/internal/services/_mock"
+	"todo-app/internal/utils"
+	"todo-app/internal/utils/testutils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/mock/gomock"
+)
+
+type twoFactorTestSetup struct {
+	ctrl                 *gomock.Controller
+	mockTwoFactorService *mock_services.MockITwoFactorService
+	twoFactorHandler     *handlers.TwoFactorHandler
+	router               *gin.Engine
+	recorder             *httptest.ResponseRecorder
+	context              *gin.Context
+}
+
+func setupTwoFactorTest(t *testing.T, setUserIDInCtx bool) *twoFactorTestSetup {
+	ctrl := gomock.NewController(t)
+	mockTwoFactorService := mock_services.NewMockITwoFactorService(ctrl)
+	twoFactorHandler := handlers.NewTwoFactorHandler(mockTwoFactorService)
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	ctx, r := gin.CreateTestContext(w)
+
+	if setUserIDInCtx {
+		ctx.Set("userID", uIDStr)
+		r.Use(func(c *gin.Context) {
+			c.Set("userID", uIDStr)
+			c.Next()
+		})
+	}
+
+	return &twoFactorTestSetup{
+		ctrl:                 ctrl,
+		mockTwoFactorService: mockTwoFactorService,
+		twoFactorHandler:     twoFactorHandler,
+		router:               r,
+		recorder:             w,
+		context:              ctx,
+	}
+}
+
+func TestTwoFactorHandler_StartMyTOTPEnrollment(t *testing.T) {
+	tests := []struct {
+		name           string
+		want           want
+		setUserIDInCtx bool
+	}{
+		{
+			name: "successful start enrollment",
+			want: want{
+				status:   http.StatusOK,
+				respFile: "testdata/start_my_totp_enrollment/200_resp.json.golden",
+			},
+			setUserIDInCtx: true,
+		},
+		{
+			name: "failed to get userID from context",
+			want: want{
+				status:   http.StatusUnauthorized,
+				respFile: "testdata/start_my_totp_enrollment/401_resp.json.golden",
+			},
+			setUserIDInCtx: false,
+		},
+		{
+			name: "already enabled",
+			want: want{
+				status:   http.StatusConflict,
+				respFile: "testdata/start_my_totp_enrollment/409_resp.json.golden",
+			},
+			setUserIDInCtx: true,
+		},
+		{
+			name: "internal server error",
+			want: want{
+				status:   http.StatusInternalServerError,
+				respFile: "testdata/start_my_totp_enrollment/500_resp.json.golden",
+			},
+			setUserIDInCtx: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setup := setupTwoFactorTest(t, tt.setUserIDInCtx)
+			defer setup.ctrl.Finish()
+
+			if tt.setUserIDInCtx {
+				setup.mockTwoFactorService.EXPECT().StartTOTPEnrollment(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, userID pgtype.UUID) (*services.TOTPEnrollment, error) {
+					switch tt.want.status {
+					case http.StatusOK:
+						return &services.TOTPEnrollment{
+							Secret:     "JBSWY3DPEHPK3PXP",
+							OtpauthURI: "otpauth://totp/Todo%20app:test@example.com?algorithm=SHA1&digits=6&issuer=Todo+app&period=30&secret=JBSWY3DPEHPK3PXP",
+						}, nil
+					case http.StatusConflict:
+						return nil, utils.ErrTwoFactorAlreadyEnabled
+					}
+					return nil, errors.New("unexpected error")
+				})
+			}
+
+			setup.context.Request = httptest.NewRequest(http.MethodPost, "/me/2fa/totp", nil)
+			setup.router.POST("/me/2fa/totp", setup.twoFactorHandler.StartMyTOTPEnrollment)
+			setup.router.ServeHTTP(setup.recorder, setup.context.Request)
+
+			testutils.AssertResponse(t, setup.recorder.Result(), tt.want.status, testutils.LoadFile(t, tt.want.respFile))
+		})
+	}
+}
+
+func TestTwoFactorHandler_ConfirmMyTOTPEnrollment(t *testing.T) {
+	tests := []struct {
+		name           string
+		reqFile        string
+		want           want
+		setUserIDInCtx bool
+	}{
+		{
+			name:    "successful confirm enrollment",
+			reqFile: "testdata/confirm_my_totp_enrollment/200_req.json.golden",
+			want: want{
+				status:   http.StatusOK,
+				respFile: "testdata/confirm_my_totp_enrollment/200_resp.json.golden",
+			},
+			setUserIDInCtx: true,
+		},
+		{
+			name:    "invalid request body",
+			reqFile: "testdata/confirm_my_totp_enrollment/400_req.json.golden",
+			want: want{
+				status:   http.StatusBadRequest,
+				respFile: "testdata/confirm_my_totp_enrollment/400_resp.json.golden",
+			},
+			setUserIDInCtx: true,
+		},
+		{
+			name:    "invalid code",
+			reqFile: "testdata/confirm_my_totp_enrollment/200_req.json.golden",
+			want: want{
+				status:   http.StatusBadRequest,
+				respFile: "testdata/confirm_my_totp_enrollment/400_invalid_code_resp.json.golden",
+			},
+			setUserIDInCtx: true,
+		},
+		{
+			name:    "enrollment not started",
+			reqFile: "testdata/confirm_my_totp_enrollment/200_req.json.golden",
+			want: want{
+				status:   http.StatusBadRequest,
+				respFile: "testdata/confirm_my_totp_enrollment/400_not_enrolled_resp.json.golden",
+			},
+			setUserIDInCtx: true,
+		},
+		{
+			name:    "failed to get userID from context",
+			reqFile: "testdata/confirm_my_totp_enrollment/200_req.json.golden",
+			want: want{
+				status:   http.StatusUnauthorized,
+				respFile: "testdata/confirm_my_totp_enrollment/401_resp.json.golden",
+			},
+			setUserIDInCtx: false,
+		},
+		{
+			name:    "already enabled",
+			reqFile: "testdata/confirm_my_totp_enrollment/200_req.json.golden",
+			want: want{
+				status:   http.StatusConflict,
+				respFile: "testdata/confirm_my_totp_enrollment/409_resp.json.golden",
+			},
+			setUserIDInCtx: true,
+		},
+		{
+			name:    "internal server error",
+			reqFile: "testdata/confirm_my_totp_enrollment/200_req.json.golden",
+			want: want{
+				status:   http.StatusInternalServerError,
+				respFile: "testdata/confirm_my_totp_enrollment/500_resp.json.golden",
+			},
+			setUserIDInCtx: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setup := setupTwoFactorTest(t, tt.setUserIDInCtx)
+			defer setup.ctrl.Finish()
+
+			// ConfirmTOTPEnrollment service won't be called when request body is invalid or userID is missing
+			if tt.name != "invalid request body" && tt.setUserIDInCtx {
+				setup.mockTwoFactorService.EXPECT().ConfirmTOTPEnrollment(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, userID pgtype.UUID, req services.ConfirmTOTPRequest) ([]string, error) {
+					switch tt.name {
+					case "successful confirm enrollment":
+						return []string{"abcde-fghij", "klmno-pqrst"}, nil
+					case "invalid code":
+						return nil, utils.ErrInvalidTwoFactorCode
+					case "enrollment not started":
+						return nil, utils.ErrTwoFactorNotEnrolled
+					case "already enabled":
+						return nil, utils.ErrTwoFactorAlreadyEnabled
+					}
+					return nil, errors.New("unexpected error")
+				})
+			}
+
+			setup.context.Request = httptest.NewRequest(http.MethodPost, "/me/2fa/totp/confirm", bytes.NewReader(testutils.LoadFile(t, tt.reqFile)))
+			setup.context.Request.Header.Set("Content-Type", "application/json")
+			setup.router.POST("/me/2fa/totp/confirm", setup.twoFactorHandler.ConfirmMyTOTPEnrollment)
+			setup.router.ServeHTTP(setup.recorder, setup.context.Request)
+
+			testutils.AssertResponse(t, setup.recorder.Result(), tt.want.status, testutils.LoadFile(t, tt.want.respFile))
+		})
+	}
+}
+
+func TestTwoFactorHandler_DisableMyTOTP(t *testing.T) {
+	tests := []struct {
+		name           string
+		reqFile        string
+		want           want
+		setUserIDInCtx bool
+	}{
+		{
+			name:    "successful disable",
+			reqFile: "testdata/disable_my_totp/200_req.json.golden",
+			want: want{
+				status:   http.StatusOK,
+				respFile: "testdata/disable_my_totp/200_resp.json.golden",
+			},
+			setUserIDInCtx: true,
+		},
+		{
+			name:    "invalid request body",
+			reqFile: "testdata/disable_my_totp/400_req.json.golden",
+			want: want{
+				status:   http.StatusBadRequest,
+				respFile: "testdata/disable_my_totp/400_resp.json.golden",
+			},
+			setUserIDInCtx: true,
+		},
+		{
+			name:    "wrong password",
+			reqFile: "testdata/disable_my_totp/200_req.json.golden",
+			want: want{
+				status:   http.StatusBadRequest,
+				respFile: "testdata/disable_my_totp/400_wrong_password_resp.json.golden",
+			},
+			setUserIDInCtx: true,
+		},
+		{
+			name:    "not enabled",
+			reqFile: "testdata/disable_my_totp/200_req.json.golden",
+			want: want{
+				status:   http.StatusBadRequest,
+				respFile: "testdata/disable_my_totp/400_not_enabled_resp.json.golden",
+			},
+			setUserIDInCtx: true,
+		},
+		{
+			name:    "failed to get userID from context",
+			reqFile: "testdata/disable_my_totp/200_req.json.golden",
+			want: want{
+				status:   http.StatusUnauthorized,
+				respFile: "testdata/disable_my_totp/401_resp.json.golden",
+			},
+			setUserIDInCtx: false,
+		},
+		{
+			name:    "internal server error",
+			reqFile: "testdata/disable_my_totp/200_req.json.golden",
+			want: want{
+				status:   http.StatusInternalServerError,
+				respFile: "testdata/disable_my_totp/500_resp.json.golden",
+			},
+			setUserIDInCtx: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setup := setupTwoFactorTest(t, tt.setUserIDInCtx)
+			defer setup.ctrl.Finish()
+
+			// DisableTOTP service won't be called when request body is invalid or userID is missing
+			if tt.name != "invalid request body" && tt.setUserIDInCtx {
+				setup.mockTwoFactorService.EXPECT().DisableTOTP(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, userID pgtype.UUID, req services.DisableTOTPRequest) error {
+					switch tt.name {
+					case "successful disable":
+						return nil
+					case "wrong password":
+						return utils.ErrInvalidCurrentPassword
+					case "not enabled":
+						return utils.ErrTwoFactorNotEnabled
+					}
+					return errors.New("unexpected error")
+				})
+			}
+
+			setup.context.Request = httptest.NewRequest(http.MethodDelete, "/me/2fa/totp", bytes.NewReader(testutils.LoadFile(t, tt.reqFile)))
+			setup.context.Request.Header.Set("Content-Type", "application/json")
+			setup.router.DELETE("/me/2fa/totp", setup.twoFactorHandler.DisableMyTOTP)
+			setup.router.ServeHTTP(setup.recorder, setup.context.Request)
+
+			testutils.AssertResponse(t, setup.recorder.Result(), tt.want.status, testutils.LoadFile(t, tt.want.respFile))
+		})
+	}
+}
@@ -3,6 +3,7 @@ package handlers
 import (
 	"log"
 	"net/http"
+	"time"
 	"todo-app/internal/services"
 	"todo-app/internal/utils"
 
@@ -14,9 +15,15 @@ type UserHandler struct {
 }
 
 type GetMeResponse struct {
-	UserID   string `json:"user_id"`
-	Username string `json:"username"`
-	Email    string `json:"email"`
+	UserID           string     `json:"user_id"`
+	Username         string     `json:"username"`
+	Email            string     `json:"email"`
+	Role             string     `json:"role"`
+	EmailVerified    bool       `json:"email_verified"`
+	EmailVerifiedAt  *time.Time `json:"email_verified_at"`
+	TwoFactorEnabled bool       `json:"two_factor_enabled"`
+	// Set while the account is pending deletion
+	DeletionRequestedAt *time.Time `json:"deletion_requested_at"`
 }
 
 func NewUserHandler(userService services.IUserService) *UserHandler {
@@ -50,7 +57,15 @@ func (h *UserHandler) GetMe(ctx *gin.Context) {
 		return
 	}
 
-	ctx.JSON(http.StatusOK, GetMeResponse{UserID: utils.UUIDToString(user.UserID), Username: user.Username, Email: user.Email})
+	resp := GetMeResponse{UserID: utils.UUIDToString(user.UserID), Username: user.Username, Email: user.Email, Role: user.Role, EmailVerified: user.EmailVerifiedAt.Valid, TwoFactorEnabled: user.TotpEnabledAt.Valid}
+	if user.EmailVerifiedAt.Valid {
+		resp.EmailVerifiedAt = &user.EmailVerifiedAt.Time
+	}
+	if user.DeletionRequestedAt.Valid {
+		resp.DeletionRequestedAt = &user.DeletionRequestedAt.Time
+	}
+
+	ctx.JSON(http.StatusOK, resp)
 }
 
 // @Summary Update current user's username
@@ -93,10 +108,11 @@ func (h *UserHandler) UpdateMyUsername(ctx *gin.Context) {
 }
 
 // @Summary Delete current user
+// @Description Signs out every session and schedules the account to be purged. It can be restored by logging in again before purge_at.
 // @Tags User
 // @Produce json
 // @Security BearerAuth
-// @Success 200 {object} gin.H "{"message": "User deleted"}"
+// @Success 200 {object} gin.H "{"message": "User scheduled for deletion", "purge_at": "2024-01-31T00:00:00Z"}"
 // @Failure 404 {object} gin.H "{"error": "Resource not found"}"
 // @Failure 500 {object} gin.H "{"error": "Internal server error"}"
 // @Router /me [delete]
@@ -106,7 +122,7 @@ func (h *UserHandler) DeleteMe(ctx *gin.Context) {
 		return
 	}
 
-	err = h.UserService.DeleteUser(ctx, userIDUuid)
+	purgeAt, err := h.UserService.DeleteUser(ctx, userIDUuid)
 	if err != nil {
 		log.Println(err.Error())
 
@@ -119,5 +135,34 @@ func (h *UserHandler) DeleteMe(ctx *gin.Context) {
 		return
 	}
 
-	ctx.JSON(http.StatusOK, gin.H{"message": "User deleted"})
+	ctx.JSON(http.StatusOK, gin.H{"message": "User scheduled for deletion", "purge_at": purgeAt})
+}
+
+// @Summary Restore current user pending deletion
+// @Tags User
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} gin.H "{"message": "User restored"}"
+// @Failure 409 {object} gin.H "{"error": "This account is not pending deletion or its grace period has passed"}"
+// @Failure 500 {object} gin.H "{"error": "Internal server error"}"
+// @Router /me/restore [post]
+func (h *UserHandler) RestoreMe(ctx *gin.Context) {
+	userIDUuid, err := utils.GetUIDFromCtxAndCreateRespUponErr(ctx)
+	if err != nil {
+		return
+	}
+
+	if err := h.UserService.RestoreUser(ctx, userIDUuid); err != nil {
+		log.Println(err.Error())
+
+		if err == utils.ErrAccountNotPendingDeletion {
+			ctx.JSON(http.StatusConflict, gin.H{"error": utils.MsgAccountNotPendingDeletion})
+			return
+		}
+
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": utils.MsgInternalServerErr})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "User restored"})
 }
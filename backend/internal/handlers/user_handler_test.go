@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 	"todo-app/internal/db"
 	"todo-app/internal/handlers"
 	"todo-app/internal/services"
@@ -73,6 +74,14 @@ func TestUserHandler_GetMe(t *testing.T) {
 			},
 			setUserIDInCtx: true,
 		},
+		{
+			name: "successful get verified user",
+			want: want{
+				status:   http.StatusOK,
+				respFile: "testdata/get_me/200_verified_resp.json.golden",
+			},
+			setUserIDInCtx: true,
+		},
 		{
 			name: "failed to get userID from context",
 			want: want{
@@ -109,7 +118,10 @@ func TestUserHandler_GetMe(t *testing.T) {
 				setup.mockUserService.EXPECT().GetMe(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, userID pgtype.UUID) (*db.User, error) {
 					switch tt.want.status {
 					case http.StatusOK:
-						return &db.User{UserID: uIDUuid, Username: "testuser", Email: "test@example.com"}, nil
+						if tt.name == "successful get verified user" {
+							return &db.User{UserID: uIDUuid, Username: "testuser", Email: "test@example.com", Role: services.RoleAdmin, EmailVerifiedAt: pgtype.Timestamptz{Time: mockTime, Valid: true}, TotpEnabledAt: pgtype.Timestamptz{Time: mockTime, Valid: true}, DeletionRequestedAt: pgtype.Timestamptz{Time: mockTime, Valid: true}}, nil
+						}
+						return &db.User{UserID: uIDUuid, Username: "testuser", Email: "test@example.com", Role: services.RoleUser}, nil
 					case http.StatusNotFound:
 						return nil, utils.ErrNoRowsMatchedSQLC
 					case http.StatusInternalServerError:
@@ -259,16 +271,16 @@ func TestUserHandler_DeleteMe(t *testing.T) {
 
 			// DeleteUser service won't be called when userID is not in context
 			if tt.setUserIDInCtx {
-				setup.mockUserService.EXPECT().DeleteUser(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, userID pgtype.UUID) error {
+				setup.mockUserService.EXPECT().DeleteUser(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, userID pgtype.UUID) (time.Time, error) {
 					switch tt.want.status {
 					case http.StatusOK:
-						return nil
+						return mockTime.AddDate(0, 0, 30), nil
 					case http.StatusNotFound:
-						return utils.ErrNoRowsMatchedSQLC
+						return time.Time{}, utils.ErrNoRowsMatchedSQLC
 					case http.StatusInternalServerError:
-						return errors.New("unexpected error")
+						return time.Time{}, errors.New("unexpected error")
 					}
-					return errors.New("error from mock")
+					return time.Time{}, errors.New("error from mock")
 				})
 			}
 
@@ -280,3 +292,72 @@ func TestUserHandler_DeleteMe(t *testing.T) {
 		})
 	}
 }
+
+func TestUserHandler_RestoreMe(t *testing.T) {
+	tests := []struct {
+		name           string
+		want           want
+		setUserIDInCtx bool
+	}{
+		{
+			name: "successful restore user",
+			want: want{
+				status:   http.StatusOK,
+				respFile: "testdata/restore_me/200_resp.json.golden",
+			},
+			setUserIDInCtx: true,
+		},
+		{
+			name: "failed to get userID from context",
+			want: want{
+				status:   http.StatusUnauthorized,
+				respFile: "testdata/restore_me/401_resp.json.golden",
+			},
+			setUserIDInCtx: false,
+		},
+		{
+			name: "not pending deletion",
+			want: want{
+				status:   http.StatusConflict,
+				respFile: "testdata/restore_me/409_resp.json.golden",
+			},
+			setUserIDInCtx: true,
+		},
+		{
+			name: "internal server error",
+			want: want{
+				status:   http.StatusInternalServerError,
+				respFile: "testdata/restore_me/500_resp.json.golden",
+			},
+			setUserIDInCtx: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setup := setupUserTest(t, tt.setUserIDInCtx)
+			defer setup.ctrl.Finish()
+
+			// RestoreUser service won't be called when userID is not in context
+			if tt.setUserIDInCtx {
+				setup.mockUserService.EXPECT().RestoreUser(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, userID pgtype.UUID) error {
+					switch tt.want.status {
+					case http.StatusOK:
+						return nil
+					case http.StatusConflict:
+						return utils.ErrAccountNotPendingDeletion
+					case http.StatusInternalServerError:
+						return errors.New("unexpected error")
+					}
+					return errors.New("error from mock")
+				})
+			}
+
+			setup.context.Request = httptest.NewRequest(http.MethodPost, "/me/restore", nil)
+			setup.router.POST("/me/restore", setup.userHandler.RestoreMe)
+			setup.router.ServeHTTP(setup.recorder, setup.context.Request)
+
+			testutils.AssertResponse(t, setup.recorder.Result(), tt.want.status, testutils.LoadFile(t, tt.want.respFile))
+		})
+	}
+}
@@ -0,0 +1,8 @@
+package mailer
+
+// Plain text email; the implementations behind services.IMailer are SMTPMailer and LogMailer
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
@@ -1,8 +1,12 @@
 package middlewares
 
 import (
+	"log"
 	"net/http"
+	"strings"
+	"time"
 	"todo-app/internal/services"
+	"todo-app/internal/utils"
 
 	"github.com/gin-contrib/sessions"
 	"github.com/gin-gonic/gin"
@@ -10,7 +14,10 @@ import (
 
 const BEARER_SCHEMA = "Bearer "
 
-func AuthMiddleware(jwter services.ITokenGenerator) gin.HandlerFunc {
+// Set in the context for requests authenticated by a personal access token, which are limited to these scopes
+const TokenScopesKey = "tokenScopes"
+
+func AuthMiddleware(jwter services.ITokenGenerator, sessionStore services.ISessionStore, patService services.IPersonalAccessTokenService) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		// Bearer token will be shown like `Authorization: Bearer <token>` in http header
 
@@ -22,8 +29,28 @@ func AuthMiddleware(jwter services.ITokenGenerator) gin.HandlerFunc {
 		}
 
 		token := authHeader[len(BEARER_SCHEMA):]
+
+		// Personal access tokens are not bound to a session, so they are checked against the database instead
+		if strings.HasPrefix(token, services.PersonalAccessTokenPrefix) {
+			userID, scopes, err := patService.Authenticate(ctx, token)
+			if err != nil {
+				if err != utils.ErrInvalidPersonalAccessToken {
+					log.Println(err.Error())
+				}
+				ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired token"})
+				ctx.Abort()
+				return
+			}
+
+			ctx.Set("userID", userID)
+			ctx.Set(TokenScopesKey, scopes)
+			ctx.Next()
+			return
+		}
+
 		claims, err := jwter.ValidateToken(token)
-		if err != nil {
+		// Tokens other than access tokens must not grant access to resources even if they are validly signed
+		if err != nil || claims.TokenType != services.TokenTypeAccess {
 			ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired token"})
 			ctx.Abort()
 			return
@@ -40,6 +67,21 @@ func AuthMiddleware(jwter services.ITokenGenerator) gin.HandlerFunc {
 			return
 		}
 
+		// A session revoked from another device is gone from the index even before its cookie expires.
+		// If the index cannot be reached the session may have been revoked, so the request is turned away.
+		active, err := sessionStore.Touch(ctx, sessionID, time.Now())
+		if err != nil {
+			log.Println(err.Error())
+			ctx.JSON(http.StatusServiceUnavailable, gin.H{"error": "Unable to check the session, try again later"})
+			ctx.Abort()
+			return
+		}
+		if !active {
+			ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired session"})
+			ctx.Abort()
+			return
+		}
+
 		ctx.Set("userID", userID)
 		ctx.Next()
 	}
@@ -9,6 +9,7 @@ import (
 	middlewares_mock "todo-app/internal/middlewares/_mock"
 	"todo-app/internal/services"
 	mock_services "todo-app/internal/services/_mock"
+	"todo-app/internal/utils"
 
 	"github.com/gin-contrib/sessions"
 	"github.com/gin-gonic/gin"
@@ -16,10 +17,12 @@ import (
 )
 
 type testSetup struct {
-	ctrl         *gomock.Controller
-	mockTokenGen *mock_services.MockITokenGenerator
-	router       *gin.Engine
-	recorder     *httptest.ResponseRecorder
+	ctrl             *gomock.Controller
+	mockTokenGen     *mock_services.MockITokenGenerator
+	mockSessionStore *mock_services.MockISessionStore
+	mockPATService   *mock_services.MockIPersonalAccessTokenService
+	router           *gin.Engine
+	recorder         *httptest.ResponseRecorder
 }
 
 var validUID = "user-id-123"
@@ -28,6 +31,8 @@ var validSID = "mock-session-id"
 func setupMiddlewareTest(t *testing.T) *testSetup {
 	ctrl := gomock.NewController(t)
 	mockTokenGen := mock_services.NewMockITokenGenerator(ctrl)
+	mockSessionStore := mock_services.NewMockISessionStore(ctrl)
+	mockPATService := mock_services.NewMockIPersonalAccessTokenService(ctrl)
 	gin.SetMode(gin.TestMode)
 
 	w := httptest.NewRecorder()
@@ -45,10 +50,12 @@ func setupMiddlewareTest(t *testing.T) *testSetup {
 	})
 
 	return &testSetup{
-		ctrl:         ctrl,
-		mockTokenGen: mockTokenGen,
-		router:       r,
-		recorder:     w,
+		ctrl:             ctrl,
+		mockTokenGen:     mockTokenGen,
+		mockSessionStore: mockSessionStore,
+		mockPATService:   mockPATService,
+		router:           r,
+		recorder:         w,
 	}
 }
 
@@ -58,14 +65,31 @@ func TestAuthMiddleware(t *testing.T) {
 		authHeader     string
 		mockTokenResp  *services.JWTCustomClaims
 		mockTokenErr   error
+		sessionActive  bool
+		sessionErr     error
 		expectedStatus int
 	}{
 		{
 			name:           "valid token and session",
 			authHeader:     "Bearer valid-token",
-			mockTokenResp:  &services.JWTCustomClaims{UserID: validUID, SessionID: validSID},
+			mockTokenResp:  &services.JWTCustomClaims{UserID: validUID, SessionID: validSID, TokenType: services.TokenTypeAccess},
+			sessionActive:  true,
 			expectedStatus: http.StatusOK,
 		},
+		{
+			name:           "valid token but revoked session",
+			authHeader:     "Bearer valid-token",
+			mockTokenResp:  &services.JWTCustomClaims{UserID: validUID, SessionID: validSID, TokenType: services.TokenTypeAccess},
+			sessionActive:  false,
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "valid token but the session store fails",
+			authHeader:     "Bearer valid-token",
+			mockTokenResp:  &services.JWTCustomClaims{UserID: validUID, SessionID: validSID, TokenType: services.TokenTypeAccess},
+			sessionErr:     errors.New("connection refused"),
+			expectedStatus: http.StatusServiceUnavailable,
+		},
 		{
 			name:           "missing auth header",
 			authHeader:     "",
@@ -82,16 +106,38 @@ func TestAuthMiddleware(t *testing.T) {
 			mockTokenErr:   errors.New("invalid or expired token"),
 			expectedStatus: http.StatusUnauthorized,
 		},
+		{
+			name:           "valid token but not an access token",
+			authHeader:     "Bearer valid-token",
+			mockTokenResp:  &services.JWTCustomClaims{UserID: validUID, SessionID: validSID, TokenType: "unknown"},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "valid token but a todo cursor",
+			authHeader:     "Bearer valid-token",
+			mockTokenResp:  &services.JWTCustomClaims{UserID: validUID, TokenType: services.TokenTypeTodoCursor},
+			expectedStatus: http.StatusUnauthorized,
+		},
 		{
 			name:           "valid token but invalid session",
 			authHeader:     "Bearer valid-token",
-			mockTokenResp:  &services.JWTCustomClaims{UserID: validUID, SessionID: "invalid-session-id"},
+			mockTokenResp:  &services.JWTCustomClaims{UserID: validUID, SessionID: "invalid-session-id", TokenType: services.TokenTypeAccess},
 			expectedStatus: http.StatusUnauthorized,
 		},
 		{
 			name:           "valid token with invalid user ID",
 			authHeader:     "Bearer valid-token",
-			mockTokenResp:  &services.JWTCustomClaims{UserID: "invalid-user-id", SessionID: validSID},
+			mockTokenResp:  &services.JWTCustomClaims{UserID: "invalid-user-id", SessionID: validSID, TokenType: services.TokenTypeAccess},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "valid personal access token",
+			authHeader:     "Bearer pat_valid-token",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "invalid or expired personal access token",
+			authHeader:     "Bearer pat_invalid-token",
 			expectedStatus: http.StatusUnauthorized,
 		},
 	}
@@ -101,7 +147,12 @@ func TestAuthMiddleware(t *testing.T) {
 			setup := setupMiddlewareTest(t)
 			defer setup.ctrl.Finish()
 
-			if tt.name != "missing auth header" && tt.name != "malformed token" {
+			// Personal access tokens are never parsed as JWTs and need no session
+			if tt.name == "valid personal access token" {
+				setup.mockPATService.EXPECT().Authenticate(gomock.Any(), "pat_valid-token").Return(validUID, []string{services.ScopeTodosRead}, nil)
+			} else if tt.name == "invalid or expired personal access token" {
+				setup.mockPATService.EXPECT().Authenticate(gomock.Any(), "pat_invalid-token").Return("", nil, utils.ErrInvalidPersonalAccessToken)
+			} else if tt.name != "missing auth header" && tt.name != "malformed token" {
 				if tt.name == "invalid or expired token" {
 					setup.mockTokenGen.EXPECT().ValidateToken("invalid-token").Return(nil, tt.mockTokenErr)
 				} else {
@@ -109,7 +160,12 @@ func TestAuthMiddleware(t *testing.T) {
 				}
 			}
 
-			setup.router.Use(middlewares.AuthMiddleware(setup.mockTokenGen))
+			// The session index is only consulted once the token and the session match
+			if tt.name == "valid token and session" || tt.name == "valid token but revoked session" || tt.name == "valid token but the session store fails" {
+				setup.mockSessionStore.EXPECT().Touch(gomock.Any(), validSID, gomock.Any()).Return(tt.sessionActive, tt.sessionErr)
+			}
+
+			setup.router.Use(middlewares.AuthMiddleware(setup.mockTokenGen, setup.mockSessionStore, setup.mockPATService))
 			setup.router.GET("/protected", func(c *gin.Context) {
 				c.JSON(http.StatusOK, gin.H{"message": "success"})
 			})
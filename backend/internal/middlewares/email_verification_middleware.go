@@ -0,0 +1,52 @@
+package middlewares
+
+import (
+	"log"
+	"net/http"
+	"todo-app/internal/services"
+	"todo-app/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Restricts users whose email is not verified yet according to the policy; must run after AuthMiddleware
+func EmailVerificationMiddleware(userService services.IUserService, policy services.UnverifiedUserPolicy) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if policy == services.UnverifiedUserPolicyFull {
+			ctx.Next()
+			return
+		}
+
+		// Reading is always allowed under the read-only policy, so the user does not even have to be looked up
+		if policy == services.UnverifiedUserPolicyReadOnly && isReadOnlyMethod(ctx.Request.Method) {
+			ctx.Next()
+			return
+		}
+
+		userIDUuid, err := utils.GetUIDFromCtxAndCreateRespUponErr(ctx)
+		if err != nil {
+			ctx.Abort()
+			return
+		}
+
+		user, err := userService.GetMe(ctx, userIDUuid)
+		if err != nil {
+			log.Println(err.Error())
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": utils.MsgInternalServerErr})
+			ctx.Abort()
+			return
+		}
+
+		if !user.EmailVerifiedAt.Valid {
+			ctx.JSON(http.StatusForbidden, gin.H{"error": utils.MsgEmailNotVerified})
+			ctx.Abort()
+			return
+		}
+
+		ctx.Next()
+	}
+}
+
+func isReadOnlyMethod(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+}
@@ -0,0 +1,120 @@
+package middlewares_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"todo-app/internal/db"
+	"todo-app/internal/middlewares"
+	"todo-app/internal/services"
+	mock_services "todo-app/internal/services/_mock"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestEmailVerificationMiddleware(t *testing.T) {
+	uID := "00010203-0405-0607-0809-0a0b0c0d0e0f"
+	verifiedAt := pgtype.Timestamptz{Time: time.Now(), Valid: true}
+
+	tests := []struct {
+		name           string
+		policy         services.UnverifiedUserPolicy
+		method         string
+		lookupUser     bool
+		emailVerified  pgtype.Timestamptz
+		lookupErr      error
+		expectedStatus int
+	}{
+		{
+			name:           "full access for unverified user",
+			policy:         services.UnverifiedUserPolicyFull,
+			method:         http.MethodPost,
+			lookupUser:     false,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "read-only allows reading without lookup",
+			policy:         services.UnverifiedUserPolicyReadOnly,
+			method:         http.MethodGet,
+			lookupUser:     false,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "read-only rejects writing by unverified user",
+			policy:         services.UnverifiedUserPolicyReadOnly,
+			method:         http.MethodPost,
+			lookupUser:     true,
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "read-only allows writing by verified user",
+			policy:         services.UnverifiedUserPolicyReadOnly,
+			method:         http.MethodPost,
+			lookupUser:     true,
+			emailVerified:  verifiedAt,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "blocked rejects reading by unverified user",
+			policy:         services.UnverifiedUserPolicyBlocked,
+			method:         http.MethodGet,
+			lookupUser:     true,
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "blocked allows verified user",
+			policy:         services.UnverifiedUserPolicyBlocked,
+			method:         http.MethodGet,
+			lookupUser:     true,
+			emailVerified:  verifiedAt,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "failed to look up user",
+			policy:         services.UnverifiedUserPolicyBlocked,
+			method:         http.MethodGet,
+			lookupUser:     true,
+			lookupErr:      errors.New("unexpected error"),
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockUserService := mock_services.NewMockIUserService(ctrl)
+			gin.SetMode(gin.TestMode)
+
+			if tt.lookupUser {
+				mockUserService.EXPECT().GetMe(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, userID pgtype.UUID) (*db.User, error) {
+					if tt.lookupErr != nil {
+						return nil, tt.lookupErr
+					}
+					return &db.User{UserID: userID, EmailVerifiedAt: tt.emailVerified}, nil
+				})
+			}
+
+			w := httptest.NewRecorder()
+			r := gin.New()
+			r.Use(func(c *gin.Context) {
+				c.Set("userID", uID)
+				c.Next()
+			})
+			r.Handle(tt.method, "/todos", middlewares.EmailVerificationMiddleware(mockUserService, tt.policy), func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(tt.method, "/todos", nil)
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package middlewares
+
+import (
+	"log"
+	"net/http"
+	"todo-app/internal/services"
+	"todo-app/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Allows only users with the given role; must run after AuthMiddleware
+func RequireRole(userService services.IUserService, role string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		userIDUuid, err := utils.GetUIDFromCtxAndCreateRespUponErr(ctx)
+		if err != nil {
+			ctx.Abort()
+			return
+		}
+
+		// Looked up on every request so that a demoted admin loses access right away
+		user, err := userService.GetMe(ctx, userIDUuid)
+		if err != nil {
+			log.Println(err.Error())
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": utils.MsgInternalServerErr})
+			ctx.Abort()
+			return
+		}
+
+		if user.Role != role {
+			ctx.JSON(http.StatusForbidden, gin.H{"error": utils.MsgForbidden})
+			ctx.Abort()
+			return
+		}
+
+		ctx.Next()
+	}
+}
@@ -0,0 +1,94 @@
+package middlewares_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"todo-app/internal/db"
+	"todo-app/internal/middlewares"
+	"todo-app/internal/services"
+	mock_services "todo-app/internal/services/_mock"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestRequireRole(t *testing.T) {
+	uID := "00010203-0405-0607-0809-0a0b0c0d0e0f"
+
+	tests := []struct {
+		name           string
+		setUserID      bool
+		lookupUser     bool
+		role           string
+		lookupErr      error
+		expectedStatus int
+	}{
+		{
+			name:           "admin is allowed",
+			setUserID:      true,
+			lookupUser:     true,
+			role:           services.RoleAdmin,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "user is rejected",
+			setUserID:      true,
+			lookupUser:     true,
+			role:           services.RoleUser,
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "failed to look up user",
+			setUserID:      true,
+			lookupUser:     true,
+			lookupErr:      errors.New("unexpected error"),
+			expectedStatus: http.StatusInternalServerError,
+		},
+		{
+			name:           "no userID in context",
+			setUserID:      false,
+			lookupUser:     false,
+			expectedStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockUserService := mock_services.NewMockIUserService(ctrl)
+			gin.SetMode(gin.TestMode)
+
+			if tt.lookupUser {
+				mockUserService.EXPECT().GetMe(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, userID pgtype.UUID) (*db.User, error) {
+					if tt.lookupErr != nil {
+						return nil, tt.lookupErr
+					}
+					return &db.User{UserID: userID, Role: tt.role}, nil
+				})
+			}
+
+			w := httptest.NewRecorder()
+			r := gin.New()
+			if tt.setUserID {
+				r.Use(func(c *gin.Context) {
+					c.Set("userID", uID)
+					c.Next()
+				})
+			}
+			r.GET("/admin/users", middlewares.RequireRole(mockUserService, services.RoleAdmin), func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/admin/users", nil)
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
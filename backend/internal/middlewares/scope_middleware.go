@@ -0,0 +1,42 @@
+package middlewares
+
+import (
+	"net/http"
+	"slices"
+	"todo-app/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Requires the scope from requests authenticated by a personal access token; must run after AuthMiddleware
+// Requests authenticated by a login session are not limited by scopes
+func RequireScope(scope string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		scopes, ok := ctx.Get(TokenScopesKey)
+		if !ok {
+			ctx.Next()
+			return
+		}
+
+		if granted, _ := scopes.([]string); !slices.Contains(granted, scope) {
+			ctx.JSON(http.StatusForbidden, gin.H{"error": utils.MsgInsufficientScope})
+			ctx.Abort()
+			return
+		}
+
+		ctx.Next()
+	}
+}
+
+// Rejects requests authenticated by a personal access token, e.g. for managing the account itself; must run after AuthMiddleware
+func RequireSession() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if _, ok := ctx.Get(TokenScopesKey); ok {
+			ctx.JSON(http.StatusForbidden, gin.H{"error": utils.MsgSessionRequired})
+			ctx.Abort()
+			return
+		}
+
+		ctx.Next()
+	}
+}
@@ -0,0 +1,74 @@
+package middlewares_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"todo-app/internal/middlewares"
+	"todo-app/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestScopeMiddleware(t *testing.T) {
+	tests := []struct {
+		name           string
+		middleware     gin.HandlerFunc
+		tokenScopes    []string
+		expectedStatus int
+	}{
+		{
+			name:           "session login is not limited by scopes",
+			middleware:     middlewares.RequireScope(services.ScopeTodosWrite),
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "token with the required scope",
+			middleware:     middlewares.RequireScope(services.ScopeTodosRead),
+			tokenScopes:    []string{services.ScopeTodosRead},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "token without the required scope",
+			middleware:     middlewares.RequireScope(services.ScopeTodosWrite),
+			tokenScopes:    []string{services.ScopeTodosRead},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "session required with session login",
+			middleware:     middlewares.RequireSession(),
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "session required with token",
+			middleware:     middlewares.RequireSession(),
+			tokenScopes:    []string{services.ScopeTodosRead, services.ScopeTodosWrite},
+			expectedStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			r := gin.New()
+
+			// Stands in for AuthMiddleware
+			r.Use(func(c *gin.Context) {
+				if tt.tokenScopes != nil {
+					c.Set(middlewares.TokenScopesKey, tt.tokenScopes)
+				}
+				c.Next()
+			})
+			r.GET("/protected", tt.middleware, func(c *gin.Context) {
+				c.JSON(http.StatusOK, gin.H{"message": "success"})
+			})
+
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/protected", nil))
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+		})
+	}
+}
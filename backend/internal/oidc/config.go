@@ -0,0 +1,45 @@
+package oidc
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+var defaultScopes = []string{"openid", "email", "profile"}
+
+/*
+Providers are listed in OIDC_PROVIDERS (comma separated) and each one is configured by
+OIDC_<NAME>_ISSUER, OIDC_<NAME>_CLIENT_ID, OIDC_<NAME>_CLIENT_SECRET, OIDC_<NAME>_REDIRECT_URL and OIDC_<NAME>_SCOPES (optional)
+e.g. OIDC_PROVIDERS=google with OIDC_GOOGLE_ISSUER=https://accounts.google.com
+*/
+func ProvidersFromEnv() (map[string]*Provider, error) {
+	providers := make(map[string]*Provider)
+	for _, name := range strings.Split(os.Getenv("OIDC_PROVIDERS"), ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+
+		prefix := "OIDC_" + strings.ToUpper(name) + "_"
+		config := Config{
+			Name:         name,
+			Issuer:       os.Getenv(prefix + "ISSUER"),
+			ClientID:     os.Getenv(prefix + "CLIENT_ID"),
+			ClientSecret: os.Getenv(prefix + "CLIENT_SECRET"),
+			RedirectURL:  os.Getenv(prefix + "REDIRECT_URL"),
+			Scopes:       defaultScopes,
+		}
+		if scopes := strings.Fields(strings.ReplaceAll(os.Getenv(prefix+"SCOPES"), ",", " ")); len(scopes) > 0 {
+			config.Scopes = scopes
+		}
+
+		if config.Issuer == "" || config.ClientID == "" || config.RedirectURL == "" {
+			return nil, fmt.Errorf("oidc provider %q requires %sISSUER, %sCLIENT_ID and %sREDIRECT_URL", name, prefix, prefix, prefix)
+		}
+
+		providers[name] = NewProvider(config)
+	}
+
+	return providers, nil
+}
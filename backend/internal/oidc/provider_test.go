@@ -0,0 +1,187 @@
+package oidc_test
+
+import (
+	"context"
+	"testing"
+	"time"
+	"todo-app/internal/oidc"
+	"todo-app/internal/utils/testutils"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProvider(t *testing.T) {
+	stub := testutils.NewOIDCProvider(t, "client-id", "client-secret")
+
+	provider := oidc.NewProvider(oidc.Config{
+		Name:         "stub",
+		Issuer:       stub.Issuer,
+		ClientID:     "client-id",
+		ClientSecret: "client-secret",
+		RedirectURL:  "http://localhost:8080/api/v1/oidc/stub/callback",
+		Scopes:       []string{"openid", "email"},
+	})
+
+	t.Run("AuthCodeURL_And_Exchange", func(t *testing.T) {
+		ctx := context.Background()
+
+		authURL, err := provider.AuthCodeURL(ctx, "state", "nonce", oidc.CodeChallengeS256("verifier"))
+		require.NoError(t, err)
+		assert.Contains(t, authURL, stub.Issuer+"/authorize?")
+		assert.Contains(t, authURL, "code_challenge_method=S256")
+
+		code, state := stub.Authorize(t, authURL)
+		assert.Equal(t, "state", state)
+
+		claims, err := provider.Exchange(ctx, code, "verifier", "nonce")
+
+		require.NoError(t, err)
+		assert.Equal(t, "stub-subject", claims.Subject)
+		assert.Equal(t, "oidc@example.com", claims.Email)
+		assert.True(t, bool(claims.EmailVerified))
+	})
+
+	t.Run("Exchange_CodeVerifierMismatch", func(t *testing.T) {
+		ctx := context.Background()
+
+		authURL, err := provider.AuthCodeURL(ctx, "state", "nonce", oidc.CodeChallengeS256("verifier"))
+		require.NoError(t, err)
+		code, _ := stub.Authorize(t, authURL)
+
+		claims, err := provider.Exchange(ctx, code, "other-verifier", "nonce")
+
+		assert.Error(t, err)
+		assert.Nil(t, claims)
+	})
+
+	t.Run("Exchange_CodeReused", func(t *testing.T) {
+		ctx := context.Background()
+
+		authURL, err := provider.AuthCodeURL(ctx, "state", "nonce", oidc.CodeChallengeS256("verifier"))
+		require.NoError(t, err)
+		code, _ := stub.Authorize(t, authURL)
+
+		_, err = provider.Exchange(ctx, code, "verifier", "nonce")
+		require.NoError(t, err)
+
+		claims, err := provider.Exchange(ctx, code, "verifier", "nonce")
+
+		assert.Error(t, err)
+		assert.Nil(t, claims)
+	})
+
+	t.Run("Exchange_NonceMismatch", func(t *testing.T) {
+		ctx := context.Background()
+
+		authURL, err := provider.AuthCodeURL(ctx, "state", "nonce", oidc.CodeChallengeS256("verifier"))
+		require.NoError(t, err)
+		code, _ := stub.Authorize(t, authURL)
+
+		claims, err := provider.Exchange(ctx, code, "verifier", "other-nonce")
+
+		assert.ErrorIs(t, err, oidc.ErrNonceMismatch)
+		assert.Nil(t, claims)
+	})
+
+	t.Run("VerifyIDToken_InvalidClaims", func(t *testing.T) {
+		ctx := context.Background()
+		validClaims := func() jwt.MapClaims {
+			return jwt.MapClaims{
+				"iss":   stub.Issuer,
+				"aud":   "client-id",
+				"sub":   "stub-subject",
+				"nonce": "nonce",
+				"exp":   time.Now().Add(time.Minute).Unix(),
+			}
+		}
+
+		tests := []struct {
+			name   string
+			modify func(claims jwt.MapClaims)
+		}{
+			{"WrongAudience", func(claims jwt.MapClaims) { claims["aud"] = "other-client" }},
+			{"WrongIssuer", func(claims jwt.MapClaims) { claims["iss"] = "https://evil.example.com" }},
+			{"Expired", func(claims jwt.MapClaims) { claims["exp"] = time.Now().Add(-time.Minute).Unix() }},
+			{"NoExpiry", func(claims jwt.MapClaims) { delete(claims, "exp") }},
+			{"NoSubject", func(claims jwt.MapClaims) { delete(claims, "sub") }},
+			{"OtherAuthorizedParty", func(claims jwt.MapClaims) {
+				claims["aud"] = []string{"client-id", "other-client"}
+				claims["azp"] = "other-client"
+			}},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				claims := validClaims()
+				tt.modify(claims)
+
+				verified, err := provider.VerifyIDToken(ctx, stub.SignIDToken(t, claims), "nonce")
+
+				assert.ErrorIs(t, err, oidc.ErrInvalidIDToken)
+				assert.Nil(t, verified)
+			})
+		}
+	})
+
+	t.Run("VerifyIDToken_EmailVerifiedAsString", func(t *testing.T) {
+		ctx := context.Background()
+
+		claims, err := provider.VerifyIDToken(ctx, stub.SignIDToken(t, jwt.MapClaims{
+			"iss":            stub.Issuer,
+			"aud":            "client-id",
+			"sub":            "stub-subject",
+			"email":          "oidc@example.com",
+			"email_verified": "true",
+			"nonce":          "nonce",
+			"exp":            time.Now().Add(time.Minute).Unix(),
+		}), "nonce")
+
+		require.NoError(t, err)
+		assert.True(t, bool(claims.EmailVerified))
+	})
+}
+
+func TestProvidersFromEnv(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		t.Setenv("OIDC_PROVIDERS", "Google, github")
+		t.Setenv("OIDC_GOOGLE_ISSUER", "https://accounts.google.com")
+		t.Setenv("OIDC_GOOGLE_CLIENT_ID", "google-client")
+		t.Setenv("OIDC_GOOGLE_CLIENT_SECRET", "google-secret")
+		t.Setenv("OIDC_GOOGLE_REDIRECT_URL", "http://localhost:8080/api/v1/oidc/google/callback")
+		t.Setenv("OIDC_GITHUB_ISSUER", "https://token.example.com")
+		t.Setenv("OIDC_GITHUB_CLIENT_ID", "github-client")
+		t.Setenv("OIDC_GITHUB_REDIRECT_URL", "http://localhost:8080/api/v1/oidc/github/callback")
+		t.Setenv("OIDC_GITHUB_SCOPES", "openid,email")
+
+		providers, err := oidc.ProvidersFromEnv()
+
+		require.NoError(t, err)
+		require.Len(t, providers, 2)
+		assert.Equal(t, "google-client", providers["google"].Config.ClientID)
+		assert.Equal(t, []string{"openid", "email", "profile"}, providers["google"].Config.Scopes)
+		assert.Equal(t, []string{"openid", "email"}, providers["github"].Config.Scopes)
+	})
+
+	t.Run("None", func(t *testing.T) {
+		t.Setenv("OIDC_PROVIDERS", "")
+
+		providers, err := oidc.ProvidersFromEnv()
+
+		require.NoError(t, err)
+		assert.Empty(t, providers)
+	})
+
+	t.Run("MissingConfig", func(t *testing.T) {
+		t.Setenv("OIDC_PROVIDERS", "google")
+		t.Setenv("OIDC_GOOGLE_ISSUER", "https://accounts.google.com")
+		t.Setenv("OIDC_GOOGLE_CLIENT_ID", "")
+		t.Setenv("OIDC_GOOGLE_REDIRECT_URL", "http://localhost:8080/api/v1/oidc/google/callback")
+
+		providers, err := oidc.ProvidersFromEnv()
+
+		assert.Error(t, err)
+		assert.Nil(t, providers)
+	})
+}
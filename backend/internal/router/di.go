@@ -9,24 +9,110 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-func InitAuthHandler(sqlClient *db.Queries, passHasher services.IPasswordHasher, jwter services.ITokenGenerator) *handlers.AuthHandler {
+func InitAuthHandler(sqlClient *db.Queries, passHasher services.IPasswordHasher, jwter services.ITokenGenerator, refreshTokenStore services.IRefreshTokenStore, sessionStore services.ISessionStore, loginAttemptStore services.ILoginAttemptStore, passwordPolicy *services.PasswordPolicy, mailer services.IMailer) *handlers.AuthHandler {
 	wrappedSqlClient := db.NewWrappedQuerier(sqlClient)
-	s := services.NewAuthService(wrappedSqlClient, passHasher, jwter)
+	s := services.NewAuthService(wrappedSqlClient, passHasher, jwter, refreshTokenStore, sessionStore, services.NewLoginThrottle(loginAttemptStore), passwordPolicy, mailer)
 	return handlers.NewAuthHandler(s)
 }
 
-func InitUserHandler(sqlClient *db.Queries) *handlers.UserHandler {
+func InitUserHandler(sqlClient *db.Queries, refreshTokenStore services.IRefreshTokenStore, sessionStore services.ISessionStore) *handlers.UserHandler {
 	wrappedSqlClient := db.NewWrappedQuerier(sqlClient)
-	s := services.NewUserService(wrappedSqlClient)
+	s := services.NewUserService(wrappedSqlClient, refreshTokenStore, sessionStore)
 	return handlers.NewUserHandler(s)
 }
 
-func InitTodoHandler(sqlClient *db.Queries) *handlers.TodoHandler {
+func InitExportHandler(sqlClient *db.Queries, blobStore services.IBlobStore) *handlers.ExportHandler {
 	wrappedSqlClient := db.NewWrappedQuerier(sqlClient)
-	s := services.NewTodoService(wrappedSqlClient)
+	s := services.NewExportService(wrappedSqlClient, blobStore)
+	return handlers.NewExportHandler(s)
+}
+
+func InitPasswordHandler(sqlClient *db.Queries, passHasher services.IPasswordHasher, passwordPolicy *services.PasswordPolicy, refreshTokenStore services.IRefreshTokenStore, sessionStore services.ISessionStore, loginAttemptStore services.ILoginAttemptStore, mailer services.IMailer) *handlers.PasswordHandler {
+	wrappedSqlClient := db.NewWrappedQuerier(sqlClient)
+	s := services.NewPasswordService(wrappedSqlClient, passHasher, passwordPolicy, refreshTokenStore, sessionStore, mailer, services.NewPasswordResetThrottle(loginAttemptStore))
+	return handlers.NewPasswordHandler(s)
+}
+
+func InitPreferencesHandler(sqlClient *db.Queries, blobStore services.IBlobStore) *handlers.PreferencesHandler {
+	wrappedSqlClient := db.NewWrappedQuerier(sqlClient)
+	s := services.NewPreferencesService(wrappedSqlClient, blobStore)
+	return handlers.NewPreferencesHandler(s)
+}
+
+func InitEmailVerificationHandler(sqlClient *db.Queries, jwter services.ITokenGenerator, passHasher services.IPasswordHasher, mailer services.IMailer) *handlers.EmailVerificationHandler {
+	wrappedSqlClient := db.NewWrappedQuerier(sqlClient)
+	s := services.NewEmailVerificationService(wrappedSqlClient, jwter, passHasher, mailer)
+	return handlers.NewEmailVerificationHandler(s)
+}
+
+func InitTwoFactorHandler(sqlClient *db.Queries, passHasher services.IPasswordHasher) *handlers.TwoFactorHandler {
+	wrappedSqlClient := db.NewWrappedQuerier(sqlClient)
+	s := services.NewTwoFactorService(wrappedSqlClient, passHasher)
+	return handlers.NewTwoFactorHandler(s)
+}
+
+func InitOIDCHandler(sqlClient *db.Queries, jwter services.ITokenGenerator, refreshTokenStore services.IRefreshTokenStore, sessionStore services.ISessionStore, providers map[string]services.IOIDCProvider) *handlers.OIDCHandler {
+	wrappedSqlClient := db.NewWrappedQuerier(sqlClient)
+	s := services.NewOIDCService(wrappedSqlClient, jwter, refreshTokenStore, sessionStore, providers)
+	return handlers.NewOIDCHandler(s)
+}
+
+func InitSessionHandler(refreshTokenStore services.IRefreshTokenStore, sessionStore services.ISessionStore) *handlers.SessionHandler {
+	s := services.NewSessionService(refreshTokenStore, sessionStore)
+	return handlers.NewSessionHandler(s)
+}
+
+func InitTodoHandler(sqlClient *db.Queries, jwter services.ITokenGenerator) *handlers.TodoHandler {
+	wrappedSqlClient := db.NewWrappedQuerier(sqlClient)
+	s := services.NewTodoService(wrappedSqlClient, jwter)
 	return handlers.NewTodoHandler(s)
 }
 
-func InitAuthMiddleware(jwter services.ITokenGenerator) gin.HandlerFunc {
-	return middlewares.AuthMiddleware(jwter)
+func InitTodoItemHandler(sqlClient *db.Queries) *handlers.TodoItemHandler {
+	wrappedSqlClient := db.NewWrappedQuerier(sqlClient)
+	s := services.NewTodoItemService(wrappedSqlClient)
+	return handlers.NewTodoItemHandler(s)
+}
+
+func InitListHandler(sqlClient *db.Queries) *handlers.ListHandler {
+	wrappedSqlClient := db.NewWrappedQuerier(sqlClient)
+	s := services.NewListService(wrappedSqlClient)
+	return handlers.NewListHandler(s)
+}
+
+func InitTagHandler(sqlClient *db.Queries) *handlers.TagHandler {
+	wrappedSqlClient := db.NewWrappedQuerier(sqlClient)
+	s := services.NewTagService(wrappedSqlClient)
+	return handlers.NewTagHandler(s)
+}
+
+func InitJWKSHandler(jwter services.ITokenGenerator) *handlers.JWKSHandler {
+	return handlers.NewJWKSHandler(jwter)
+}
+
+func InitPersonalAccessTokenHandler(sqlClient *db.Queries) *handlers.PersonalAccessTokenHandler {
+	wrappedSqlClient := db.NewWrappedQuerier(sqlClient)
+	s := services.NewPersonalAccessTokenService(wrappedSqlClient)
+	return handlers.NewPersonalAccessTokenHandler(s)
+}
+
+func InitAdminHandler(sqlClient *db.Queries, refreshTokenStore services.IRefreshTokenStore, sessionStore services.ISessionStore, mailer services.IMailer) *handlers.AdminHandler {
+	wrappedSqlClient := db.NewWrappedQuerier(sqlClient)
+	s := services.NewAdminService(wrappedSqlClient, refreshTokenStore, sessionStore, mailer)
+	return handlers.NewAdminHandler(s)
+}
+
+func InitAuthMiddleware(sqlClient *db.Queries, jwter services.ITokenGenerator, sessionStore services.ISessionStore) gin.HandlerFunc {
+	wrappedSqlClient := db.NewWrappedQuerier(sqlClient)
+	return middlewares.AuthMiddleware(jwter, sessionStore, services.NewPersonalAccessTokenService(wrappedSqlClient))
+}
+
+func InitEmailVerificationMiddleware(sqlClient *db.Queries, refreshTokenStore services.IRefreshTokenStore, sessionStore services.ISessionStore, policy services.UnverifiedUserPolicy) gin.HandlerFunc {
+	wrappedSqlClient := db.NewWrappedQuerier(sqlClient)
+	return middlewares.EmailVerificationMiddleware(services.NewUserService(wrappedSqlClient, refreshTokenStore, sessionStore), policy)
+}
+
+func InitRoleMiddleware(sqlClient *db.Queries, refreshTokenStore services.IRefreshTokenStore, sessionStore services.ISessionStore, role string) gin.HandlerFunc {
+	wrappedSqlClient := db.NewWrappedQuerier(sqlClient)
+	return middlewares.RequireRole(services.NewUserService(wrappedSqlClient, refreshTokenStore, sessionStore), role)
 }
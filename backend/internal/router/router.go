@@ -1,8 +1,15 @@
 package router
 
 import (
+	"fmt"
 	"os"
+	"strconv"
+	"strings"
+	"todo-app/internal/blobstore"
 	"todo-app/internal/db"
+	"todo-app/internal/mailer"
+	"todo-app/internal/middlewares"
+	"todo-app/internal/oidc"
 	"todo-app/internal/services"
 
 	_ "todo-app/docs"
@@ -22,15 +29,101 @@ import (
 // @securitydefinitions.bearerauth BearerAuth
 // @in header
 // @name Authorization
-func SetupRouter(sqlClient *db.Queries, redisStore redis.Store) *gin.Engine {
+func SetupRouter(sqlClient *db.Queries, redisStore redis.Store) (*gin.Engine, error) {
 	r := gin.Default()
 
-	passHasher := services.NewDefaultPasswordHasher()
-	jwter := services.NewJWTer()
-	authHandler := InitAuthHandler(sqlClient, passHasher, jwter)
-	userHandler := InitUserHandler(sqlClient)
-	authMiddleware := InitAuthMiddleware(jwter)
-	todoHandler := InitTodoHandler(sqlClient)
+	// X-Forwarded-For and X-Real-IP are only believed from these proxies, comma separated IPs or CIDRs, so that clients cannot
+	// pick the IP the login throttle counts them under. None by default, in which case the IP is the peer address.
+	var trustedProxies []string
+	if proxies := strings.Fields(strings.ReplaceAll(os.Getenv("TRUSTED_PROXIES"), ",", " ")); len(proxies) > 0 {
+		trustedProxies = proxies
+	}
+	if err := r.SetTrustedProxies(trustedProxies); err != nil {
+		return nil, fmt.Errorf("failed to set trusted proxies: %w", err)
+	}
+
+	redisPool, err := db.GetRedisPool(redisStore)
+	if err != nil {
+		return nil, err
+	}
+
+	keySet, err := services.LoadKeySet(os.Getenv("JWT_KEYSET_DIR"), os.Getenv("JWT_SIGNING_KID"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load jwt keyset: %w", err)
+	}
+
+	todoCursorKey, err := services.TodoCursorKeyFromEnv()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load todo cursor key: %w", err)
+	}
+
+	twoFactorChallengeKey, err := services.TwoFactorChallengeKeyFromEnv()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load two-factor challenge key: %w", err)
+	}
+
+	passwordPolicy, err := services.NewPasswordPolicyFromEnv()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load password policy: %w", err)
+	}
+
+	unverifiedUserPolicy, err := services.UnverifiedUserPolicyFromEnv()
+	if err != nil {
+		return nil, err
+	}
+
+	oidcProviders, err := oidc.ProvidersFromEnv()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load oidc providers: %w", err)
+	}
+	providers := make(map[string]services.IOIDCProvider, len(oidcProviders))
+	for name, provider := range oidcProviders {
+		providers[name] = provider
+	}
+
+	passHasher := services.NewArgon2idPasswordHasher()
+	jwter := services.NewJWTer(keySet, todoCursorKey, twoFactorChallengeKey)
+	refreshTokenStore := db.NewRedisRefreshTokenStore(redisPool)
+	sessionStore := db.NewRedisSessionStore(redisPool)
+	// The in-memory store only works with a single API instance
+	var loginAttemptStore services.ILoginAttemptStore = db.NewRedisLoginAttemptStore(redisPool)
+	if os.Getenv("LOGIN_ATTEMPT_STORE") == "memory" {
+		loginAttemptStore = db.NewMemoryLoginAttemptStore()
+	}
+	// The log mailer writes emails to MAIL_LOG_FILE (or the log) instead of sending them, for local development
+	var m services.IMailer = mailer.NewLogMailer(os.Getenv("MAIL_LOG_FILE"))
+	if os.Getenv("MAILER") == "smtp" {
+		smtpPort, err := strconv.Atoi(os.Getenv("SMTP_PORT"))
+		if err != nil {
+			return nil, fmt.Errorf("failed to obtain required parameter for smtp mailer: %w", err)
+		}
+		m = mailer.NewSMTPMailer(os.Getenv("SMTP_HOST"), smtpPort, os.Getenv("SMTP_USERNAME"), os.Getenv("SMTP_PASSWORD"), os.Getenv("MAIL_FROM"))
+	}
+
+	blobStore, err := SetupBlobStore()
+	if err != nil {
+		return nil, err
+	}
+
+	authHandler := InitAuthHandler(sqlClient, passHasher, jwter, refreshTokenStore, sessionStore, loginAttemptStore, passwordPolicy, m)
+	userHandler := InitUserHandler(sqlClient, refreshTokenStore, sessionStore)
+	exportHandler := InitExportHandler(sqlClient, blobStore)
+	preferencesHandler := InitPreferencesHandler(sqlClient, blobStore)
+	emailVerificationHandler := InitEmailVerificationHandler(sqlClient, jwter, passHasher, m)
+	passwordHandler := InitPasswordHandler(sqlClient, passHasher, passwordPolicy, refreshTokenStore, sessionStore, loginAttemptStore, m)
+	twoFactorHandler := InitTwoFactorHandler(sqlClient, passHasher)
+	oidcHandler := InitOIDCHandler(sqlClient, jwter, refreshTokenStore, sessionStore, providers)
+	sessionHandler := InitSessionHandler(refreshTokenStore, sessionStore)
+	personalAccessTokenHandler := InitPersonalAccessTokenHandler(sqlClient)
+	adminHandler := InitAdminHandler(sqlClient, refreshTokenStore, sessionStore, m)
+	authMiddleware := InitAuthMiddleware(sqlClient, jwter, sessionStore)
+	emailVerificationMiddleware := InitEmailVerificationMiddleware(sqlClient, refreshTokenStore, sessionStore, unverifiedUserPolicy)
+	adminMiddleware := InitRoleMiddleware(sqlClient, refreshTokenStore, sessionStore, services.RoleAdmin)
+	jwksHandler := InitJWKSHandler(jwter)
+	todoHandler := InitTodoHandler(sqlClient, jwter)
+	todoItemHandler := InitTodoItemHandler(sqlClient)
+	listHandler := InitListHandler(sqlClient)
+	tagHandler := InitTagHandler(sqlClient)
 
 	r.Use(sessions.Sessions("mysession", redisStore))
 
@@ -39,33 +132,117 @@ func SetupRouter(sqlClient *db.Queries, redisStore redis.Store) *gin.Engine {
 		AllowHeaders: []string{"Origin", "Content-Type", "Authorization"},
 	}))
 
+	r.GET("/.well-known/jwks.json", jwksHandler.GetJWKS)
+
 	api := r.Group("/api")
 	v1 := api.Group("/v1")
 	{
 		v1.POST("/register", authHandler.Register)
 		v1.POST("/login", authHandler.Login)
-		v1.POST("/logout", authMiddleware, authHandler.Logout)
+		v1.POST("/login/2fa", authHandler.LoginTwoFactor)
+		v1.GET("/oidc/:provider/login", oidcHandler.StartOIDCLogin)
+		v1.GET("/oidc/:provider/callback", oidcHandler.OIDCCallback) // /oidc/{provider}/callback?code={code}&state={state}
+		v1.POST("/logout", authMiddleware, middlewares.RequireSession(), authHandler.Logout)
+		v1.POST("/token/refresh", authHandler.RefreshToken)
+		v1.POST("/password/forgot", passwordHandler.ForgotPassword)
+		v1.POST("/password/reset", passwordHandler.ResetPassword)
+		v1.GET("/verify-email", emailVerificationHandler.VerifyEmail)                // /verify-email?token={token}
+		v1.GET("/email-change/confirm", emailVerificationHandler.ConfirmEmailChange) // /email-change/confirm?token={token}
 
-		users := v1.Group("/me", authMiddleware)
+		// Personal access tokens only grant access to todos, not to the account itself
+		users := v1.Group("/me", authMiddleware, middlewares.RequireSession())
 		{
 			users.GET("/", userHandler.GetMe)
 			users.PATCH("/username", userHandler.UpdateMyUsername)
 			users.DELETE("/", userHandler.DeleteMe)
+			users.POST("/restore", userHandler.RestoreMe)
+			users.GET("/export", exportHandler.ExportMyData)
+			users.GET("/preferences", preferencesHandler.GetMyPreferences)
+			users.PATCH("/preferences", preferencesHandler.UpdateMyPreferences)
+			users.PUT("/preferences/avatar", preferencesHandler.PutMyAvatar)
+			users.GET("/preferences/avatar", preferencesHandler.GetMyAvatar)
+			users.DELETE("/preferences/avatar", preferencesHandler.DeleteMyAvatar)
+			users.PUT("/password", passwordHandler.ChangeMyPassword)
+			users.PUT("/email", emailVerificationHandler.ChangeMyEmail)
+			users.POST("/verify-email/resend", emailVerificationHandler.ResendMyVerification)
+			users.POST("/2fa/totp", twoFactorHandler.StartMyTOTPEnrollment)
+			users.POST("/2fa/totp/confirm", twoFactorHandler.ConfirmMyTOTPEnrollment)
+			users.DELETE("/2fa/totp", twoFactorHandler.DisableMyTOTP)
+			users.GET("/sessions", sessionHandler.ListMySessions)
+			users.DELETE("/sessions", sessionHandler.RevokeAllMySessions)
+			users.DELETE("/sessions/:id", sessionHandler.RevokeMySession)
+			users.POST("/tokens", personalAccessTokenHandler.CreateMyToken)
+			users.GET("/tokens", personalAccessTokenHandler.ListMyTokens)
+			users.DELETE("/tokens/:id", personalAccessTokenHandler.DeleteMyToken)
 		}
 
-		todos := v1.Group("/todos", authMiddleware)
+		readTodos := middlewares.RequireScope(services.ScopeTodosRead)
+		writeTodos := middlewares.RequireScope(services.ScopeTodosWrite)
+
+		todos := v1.Group("/todos", authMiddleware, emailVerificationMiddleware)
 		{
-			todos.POST("/", todoHandler.CreateTodo)
-			todos.GET("/", todoHandler.ListTodos)
-			todos.GET("/search", todoHandler.SearchTodos) // /search?keyword={keyword}
-			todos.PUT("/:id", todoHandler.UpdateTodo)
-			todos.PATCH("/:id/position", todoHandler.UpdateTodoPosition)
-			todos.DELETE("/:id", todoHandler.DeleteTodo)
+			todos.POST("/", writeTodos, todoHandler.CreateTodo)
+			todos.GET("/", readTodos, todoHandler.ListTodos)         // /?list_id={id}&tag={name}&tag_mode={all|any}
+			todos.GET("/search", readTodos, todoHandler.SearchTodos) // /search?keyword={keyword}&tag={name}&tag_mode={all|any}
+			todos.PUT("/:id", writeTodos, todoHandler.UpdateTodo)
+			todos.GET("/:id/occurrences", readTodos, todoHandler.ListTodoOccurrences) // /:id/occurrences?count={count}
+			todos.PATCH("/:id/position", writeTodos, todoHandler.UpdateTodoPosition)
+			todos.PATCH("/:id/list", writeTodos, todoHandler.MoveTodo)
+			todos.DELETE("/:id", writeTodos, todoHandler.DeleteTodo)
+			todos.POST("/:id/items", writeTodos, todoItemHandler.CreateTodoItem)
+			todos.GET("/:id/items", readTodos, todoItemHandler.ListTodoItems)
+			todos.PUT("/:id/items/:itemId", writeTodos, todoItemHandler.UpdateTodoItem)
+			todos.PATCH("/:id/items/:itemId/position", writeTodos, todoItemHandler.UpdateTodoItemPosition)
+			todos.DELETE("/:id/items/:itemId", writeTodos, todoItemHandler.DeleteTodoItem)
+			todos.PUT("/:id/tags/:tagId", writeTodos, tagHandler.AttachTag)
+			todos.DELETE("/:id/tags/:tagId", writeTodos, tagHandler.DetachTag)
+		}
+
+		lists := v1.Group("/lists", authMiddleware, emailVerificationMiddleware)
+		{
+			lists.POST("/", writeTodos, listHandler.CreateList)
+			lists.GET("/", readTodos, listHandler.ListLists) // /?archived={true|false}
+			lists.GET("/:id", readTodos, listHandler.GetList)
+			lists.PUT("/:id", writeTodos, listHandler.UpdateList)
+			lists.POST("/:id/archive", writeTodos, listHandler.ArchiveList)
+			lists.POST("/:id/unarchive", writeTodos, listHandler.UnarchiveList)
+			lists.DELETE("/:id", writeTodos, listHandler.DeleteList)
+		}
+
+		tags := v1.Group("/tags", authMiddleware, emailVerificationMiddleware)
+		{
+			tags.POST("/", writeTodos, tagHandler.CreateTag)
+			tags.GET("/", readTodos, tagHandler.ListTags)
+			tags.PUT("/:id", writeTodos, tagHandler.UpdateTag)
+			tags.DELETE("/:id", writeTodos, tagHandler.DeleteTag)
+		}
+
+		admin := v1.Group("/admin", authMiddleware, middlewares.RequireSession(), adminMiddleware)
+		{
+			admin.GET("/users", adminHandler.ListUsers) // /users?q={query}&page={page}&per_page={per_page}
+			admin.GET("/users/:id", adminHandler.GetUser)
+			admin.POST("/users/:id/disable", adminHandler.DisableUser)
+			admin.POST("/users/:id/enable", adminHandler.EnableUser)
+			admin.POST("/users/:id/password-reset", adminHandler.ForcePasswordReset)
 		}
 	}
 
 	// http://localhost:8080/swagger/index.html
 	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
-	return r
+	return r, nil
+}
+
+// Only the local filesystem store exists for now; other stores can be added behind services.IBlobStore
+func SetupBlobStore() (services.IBlobStore, error) {
+	switch os.Getenv("BLOB_STORE") {
+	case "", "local":
+		blobDir := os.Getenv("BLOB_STORE_DIR")
+		if blobDir == "" {
+			blobDir = "data/blobs"
+		}
+		return blobstore.NewLocalStore(blobDir), nil
+	default:
+		return nil, fmt.Errorf("unsupported blob store: %s", os.Getenv("BLOB_STORE"))
+	}
 }
@@ -11,8 +11,13 @@ package mock_services
 
 import (
 	context "context"
+	json "encoding/json"
+	io "io"
 	reflect "reflect"
+	time "time"
 	db "todo-app/internal/db"
+	mailer "todo-app/internal/mailer"
+	oidc "todo-app/internal/oidc"
 	services "todo-app/internal/services"
 
 	pgtype "github.com/jackc/pgx/v5/pgtype"
@@ -44,19 +49,65 @@ func (m *MockIAuthService) EXPECT() *MockIAuthServiceMockRecorder {
 }
 
 // Login mocks base method.
-func (m *MockIAuthService) Login(ctx context.Context, req services.LoginRequest, sessionID string) (string, string, error) {
+func (m *MockIAuthService) Login(ctx context.Context, req services.LoginRequest, sessionID string, client services.ClientInfo) (string, *services.TokenPair, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Login", ctx, req, sessionID)
+	ret := m.ctrl.Call(m, "Login", ctx, req, sessionID, client)
 	ret0, _ := ret[0].(string)
-	ret1, _ := ret[1].(string)
+	ret1, _ := ret[1].(*services.TokenPair)
 	ret2, _ := ret[2].(error)
 	return ret0, ret1, ret2
 }
 
 // Login indicates an expected call of Login.
-func (mr *MockIAuthServiceMockRecorder) Login(ctx, req, sessionID any) *gomock.Call {
+func (mr *MockIAuthServiceMockRecorder) Login(ctx, req, sessionID, client any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Login", reflect.TypeOf((*MockIAuthService)(nil).Login), ctx, req, sessionID, client)
+}
+
+// LoginTwoFactor mocks base method.
+func (m *MockIAuthService) LoginTwoFactor(ctx context.Context, req services.TwoFactorLoginRequest, sessionID string, client services.ClientInfo) (string, *services.TokenPair, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoginTwoFactor", ctx, req, sessionID, client)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(*services.TokenPair)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// LoginTwoFactor indicates an expected call of LoginTwoFactor.
+func (mr *MockIAuthServiceMockRecorder) LoginTwoFactor(ctx, req, sessionID, client any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoginTwoFactor", reflect.TypeOf((*MockIAuthService)(nil).LoginTwoFactor), ctx, req, sessionID, client)
+}
+
+// Logout mocks base method.
+func (m *MockIAuthService) Logout(ctx context.Context, userID, sessionID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Logout", ctx, userID, sessionID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Logout indicates an expected call of Logout.
+func (mr *MockIAuthServiceMockRecorder) Logout(ctx, userID, sessionID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Logout", reflect.TypeOf((*MockIAuthService)(nil).Logout), ctx, userID, sessionID)
+}
+
+// RefreshToken mocks base method.
+func (m *MockIAuthService) RefreshToken(ctx context.Context, req services.RefreshTokenRequest, sessionID string) (string, *services.TokenPair, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RefreshToken", ctx, req, sessionID)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(*services.TokenPair)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// RefreshToken indicates an expected call of RefreshToken.
+func (mr *MockIAuthServiceMockRecorder) RefreshToken(ctx, req, sessionID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Login", reflect.TypeOf((*MockIAuthService)(nil).Login), ctx, req, sessionID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefreshToken", reflect.TypeOf((*MockIAuthService)(nil).RefreshToken), ctx, req, sessionID)
 }
 
 // Register mocks base method.
@@ -74,6 +125,61 @@ func (mr *MockIAuthServiceMockRecorder) Register(ctx, req any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Register", reflect.TypeOf((*MockIAuthService)(nil).Register), ctx, req)
 }
 
+// MockIOIDCService is a mock of IOIDCService interface.
+type MockIOIDCService struct {
+	ctrl     *gomock.Controller
+	recorder *MockIOIDCServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockIOIDCServiceMockRecorder is the mock recorder for MockIOIDCService.
+type MockIOIDCServiceMockRecorder struct {
+	mock *MockIOIDCService
+}
+
+// NewMockIOIDCService creates a new mock instance.
+func NewMockIOIDCService(ctrl *gomock.Controller) *MockIOIDCService {
+	mock := &MockIOIDCService{ctrl: ctrl}
+	mock.recorder = &MockIOIDCServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIOIDCService) EXPECT() *MockIOIDCServiceMockRecorder {
+	return m.recorder
+}
+
+// Login mocks base method.
+func (m *MockIOIDCService) Login(ctx context.Context, providerName string, req services.OIDCCallbackRequest, authState services.OIDCAuthState, sessionID string, client services.ClientInfo) (string, *services.TokenPair, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Login", ctx, providerName, req, authState, sessionID, client)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(*services.TokenPair)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Login indicates an expected call of Login.
+func (mr *MockIOIDCServiceMockRecorder) Login(ctx, providerName, req, authState, sessionID, client any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Login", reflect.TypeOf((*MockIOIDCService)(nil).Login), ctx, providerName, req, authState, sessionID, client)
+}
+
+// StartLogin mocks base method.
+func (m *MockIOIDCService) StartLogin(ctx context.Context, providerName string) (*services.OIDCAuthRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StartLogin", ctx, providerName)
+	ret0, _ := ret[0].(*services.OIDCAuthRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StartLogin indicates an expected call of StartLogin.
+func (mr *MockIOIDCServiceMockRecorder) StartLogin(ctx, providerName any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartLogin", reflect.TypeOf((*MockIOIDCService)(nil).StartLogin), ctx, providerName)
+}
+
 // MockIUserService is a mock of IUserService interface.
 type MockIUserService struct {
 	ctrl     *gomock.Controller
@@ -99,11 +205,12 @@ func (m *MockIUserService) EXPECT() *MockIUserServiceMockRecorder {
 }
 
 // DeleteUser mocks base method.
-func (m *MockIUserService) DeleteUser(ctx context.Context, userID pgtype.UUID) error {
+func (m *MockIUserService) DeleteUser(ctx context.Context, userID pgtype.UUID) (time.Time, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "DeleteUser", ctx, userID)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret0, _ := ret[0].(time.Time)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // DeleteUser indicates an expected call of DeleteUser.
@@ -127,6 +234,20 @@ func (mr *MockIUserServiceMockRecorder) GetMe(ctx, userID any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMe", reflect.TypeOf((*MockIUserService)(nil).GetMe), ctx, userID)
 }
 
+// RestoreUser mocks base method.
+func (m *MockIUserService) RestoreUser(ctx context.Context, userID pgtype.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreUser", ctx, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RestoreUser indicates an expected call of RestoreUser.
+func (mr *MockIUserServiceMockRecorder) RestoreUser(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreUser", reflect.TypeOf((*MockIUserService)(nil).RestoreUser), ctx, userID)
+}
+
 // UpdateUsername mocks base method.
 func (m *MockIUserService) UpdateUsername(ctx context.Context, userID pgtype.UUID, req services.UpdateUsernameRequest) error {
 	m.ctrl.T.Helper()
@@ -141,222 +262,1759 @@ func (mr *MockIUserServiceMockRecorder) UpdateUsername(ctx, userID, req any) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUsername", reflect.TypeOf((*MockIUserService)(nil).UpdateUsername), ctx, userID, req)
 }
 
-// MockIPasswordHasher is a mock of IPasswordHasher interface.
-type MockIPasswordHasher struct {
+// MockIExportService is a mock of IExportService interface.
+type MockIExportService struct {
 	ctrl     *gomock.Controller
-	recorder *MockIPasswordHasherMockRecorder
+	recorder *MockIExportServiceMockRecorder
 	isgomock struct{}
 }
 
-// MockIPasswordHasherMockRecorder is the mock recorder for MockIPasswordHasher.
-type MockIPasswordHasherMockRecorder struct {
-	mock *MockIPasswordHasher
+// MockIExportServiceMockRecorder is the mock recorder for MockIExportService.
+type MockIExportServiceMockRecorder struct {
+	mock *MockIExportService
 }
 
-// NewMockIPasswordHasher creates a new mock instance.
-func NewMockIPasswordHasher(ctrl *gomock.Controller) *MockIPasswordHasher {
-	mock := &MockIPasswordHasher{ctrl: ctrl}
-	mock.recorder = &MockIPasswordHasherMockRecorder{mock}
+// NewMockIExportService creates a new mock instance.
+func NewMockIExportService(ctrl *gomock.Controller) *MockIExportService {
+	mock := &MockIExportService{ctrl: ctrl}
+	mock.recorder = &MockIExportServiceMockRecorder{mock}
 	return mock
 }
 
 // EXPECT returns an object that allows the caller to indicate expected use.
-func (m *MockIPasswordHasher) EXPECT() *MockIPasswordHasherMockRecorder {
+func (m *MockIExportService) EXPECT() *MockIExportServiceMockRecorder {
 	return m.recorder
 }
 
-// CompareHashAndPassword mocks base method.
-func (m *MockIPasswordHasher) CompareHashAndPassword(hashedPassword, password []byte) error {
+// WriteExport mocks base method.
+func (m *MockIExportService) WriteExport(ctx context.Context, userID pgtype.UUID, w io.Writer) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CompareHashAndPassword", hashedPassword, password)
+	ret := m.ctrl.Call(m, "WriteExport", ctx, userID, w)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// CompareHashAndPassword indicates an expected call of CompareHashAndPassword.
-func (mr *MockIPasswordHasherMockRecorder) CompareHashAndPassword(hashedPassword, password any) *gomock.Call {
+// WriteExport indicates an expected call of WriteExport.
+func (mr *MockIExportServiceMockRecorder) WriteExport(ctx, userID, w any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompareHashAndPassword", reflect.TypeOf((*MockIPasswordHasher)(nil).CompareHashAndPassword), hashedPassword, password)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WriteExport", reflect.TypeOf((*MockIExportService)(nil).WriteExport), ctx, userID, w)
 }
 
-// GenerateFromPassword mocks base method.
-func (m *MockIPasswordHasher) GenerateFromPassword(password []byte, cost int) ([]byte, error) {
+// MockIPreferencesService is a mock of IPreferencesService interface.
+type MockIPreferencesService struct {
+	ctrl     *gomock.Controller
+	recorder *MockIPreferencesServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockIPreferencesServiceMockRecorder is the mock recorder for MockIPreferencesService.
+type MockIPreferencesServiceMockRecorder struct {
+	mock *MockIPreferencesService
+}
+
+// NewMockIPreferencesService creates a new mock instance.
+func NewMockIPreferencesService(ctrl *gomock.Controller) *MockIPreferencesService {
+	mock := &MockIPreferencesService{ctrl: ctrl}
+	mock.recorder = &MockIPreferencesServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIPreferencesService) EXPECT() *MockIPreferencesServiceMockRecorder {
+	return m.recorder
+}
+
+// DeleteAvatar mocks base method.
+func (m *MockIPreferencesService) DeleteAvatar(ctx context.Context, userID pgtype.UUID) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GenerateFromPassword", password, cost)
-	ret0, _ := ret[0].([]byte)
+	ret := m.ctrl.Call(m, "DeleteAvatar", ctx, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteAvatar indicates an expected call of DeleteAvatar.
+func (mr *MockIPreferencesServiceMockRecorder) DeleteAvatar(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAvatar", reflect.TypeOf((*MockIPreferencesService)(nil).DeleteAvatar), ctx, userID)
+}
+
+// GetAvatar mocks base method.
+func (m *MockIPreferencesService) GetAvatar(ctx context.Context, userID pgtype.UUID) (io.ReadCloser, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAvatar", ctx, userID)
+	ret0, _ := ret[0].(io.ReadCloser)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetAvatar indicates an expected call of GetAvatar.
+func (mr *MockIPreferencesServiceMockRecorder) GetAvatar(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAvatar", reflect.TypeOf((*MockIPreferencesService)(nil).GetAvatar), ctx, userID)
+}
+
+// GetPreferences mocks base method.
+func (m *MockIPreferencesService) GetPreferences(ctx context.Context, userID pgtype.UUID) (*db.UserPreference, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPreferences", ctx, userID)
+	ret0, _ := ret[0].(*db.UserPreference)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GenerateFromPassword indicates an expected call of GenerateFromPassword.
-func (mr *MockIPasswordHasherMockRecorder) GenerateFromPassword(password, cost any) *gomock.Call {
+// GetPreferences indicates an expected call of GetPreferences.
+func (mr *MockIPreferencesServiceMockRecorder) GetPreferences(ctx, userID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerateFromPassword", reflect.TypeOf((*MockIPasswordHasher)(nil).GenerateFromPassword), password, cost)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPreferences", reflect.TypeOf((*MockIPreferencesService)(nil).GetPreferences), ctx, userID)
 }
 
-// MockITokenGenerator is a mock of ITokenGenerator interface.
-type MockITokenGenerator struct {
+// PutAvatar mocks base method.
+func (m *MockIPreferencesService) PutAvatar(ctx context.Context, userID pgtype.UUID, r io.Reader) (*db.UserPreference, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PutAvatar", ctx, userID, r)
+	ret0, _ := ret[0].(*db.UserPreference)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PutAvatar indicates an expected call of PutAvatar.
+func (mr *MockIPreferencesServiceMockRecorder) PutAvatar(ctx, userID, r any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutAvatar", reflect.TypeOf((*MockIPreferencesService)(nil).PutAvatar), ctx, userID, r)
+}
+
+// UpdatePreferences mocks base method.
+func (m *MockIPreferencesService) UpdatePreferences(ctx context.Context, userID pgtype.UUID, patch map[string]json.RawMessage) (*db.UserPreference, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePreferences", ctx, userID, patch)
+	ret0, _ := ret[0].(*db.UserPreference)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdatePreferences indicates an expected call of UpdatePreferences.
+func (mr *MockIPreferencesServiceMockRecorder) UpdatePreferences(ctx, userID, patch any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePreferences", reflect.TypeOf((*MockIPreferencesService)(nil).UpdatePreferences), ctx, userID, patch)
+}
+
+// MockIPasswordService is a mock of IPasswordService interface.
+type MockIPasswordService struct {
 	ctrl     *gomock.Controller
-	recorder *MockITokenGeneratorMockRecorder
+	recorder *MockIPasswordServiceMockRecorder
 	isgomock struct{}
 }
 
-// MockITokenGeneratorMockRecorder is the mock recorder for MockITokenGenerator.
-type MockITokenGeneratorMockRecorder struct {
-	mock *MockITokenGenerator
+// MockIPasswordServiceMockRecorder is the mock recorder for MockIPasswordService.
+type MockIPasswordServiceMockRecorder struct {
+	mock *MockIPasswordService
 }
 
-// NewMockITokenGenerator creates a new mock instance.
-func NewMockITokenGenerator(ctrl *gomock.Controller) *MockITokenGenerator {
-	mock := &MockITokenGenerator{ctrl: ctrl}
-	mock.recorder = &MockITokenGeneratorMockRecorder{mock}
+// NewMockIPasswordService creates a new mock instance.
+func NewMockIPasswordService(ctrl *gomock.Controller) *MockIPasswordService {
+	mock := &MockIPasswordService{ctrl: ctrl}
+	mock.recorder = &MockIPasswordServiceMockRecorder{mock}
 	return mock
 }
 
 // EXPECT returns an object that allows the caller to indicate expected use.
-func (m *MockITokenGenerator) EXPECT() *MockITokenGeneratorMockRecorder {
+func (m *MockIPasswordService) EXPECT() *MockIPasswordServiceMockRecorder {
 	return m.recorder
 }
 
-// GenerateToken mocks base method.
-func (m *MockITokenGenerator) GenerateToken(userID, sessionID string) (string, error) {
+// ChangePassword mocks base method.
+func (m *MockIPasswordService) ChangePassword(ctx context.Context, userID pgtype.UUID, sessionID string, req services.ChangePasswordRequest) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GenerateToken", userID, sessionID)
-	ret0, _ := ret[0].(string)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret := m.ctrl.Call(m, "ChangePassword", ctx, userID, sessionID, req)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
-// GenerateToken indicates an expected call of GenerateToken.
-func (mr *MockITokenGeneratorMockRecorder) GenerateToken(userID, sessionID any) *gomock.Call {
+// ChangePassword indicates an expected call of ChangePassword.
+func (mr *MockIPasswordServiceMockRecorder) ChangePassword(ctx, userID, sessionID, req any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerateToken", reflect.TypeOf((*MockITokenGenerator)(nil).GenerateToken), userID, sessionID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangePassword", reflect.TypeOf((*MockIPasswordService)(nil).ChangePassword), ctx, userID, sessionID, req)
 }
 
-// ValidateToken mocks base method.
-func (m *MockITokenGenerator) ValidateToken(tokenString string) (*services.JWTCustomClaims, error) {
+// ForgotPassword mocks base method.
+func (m *MockIPasswordService) ForgotPassword(ctx context.Context, req services.ForgotPasswordRequest, client services.ClientInfo) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ValidateToken", tokenString)
-	ret0, _ := ret[0].(*services.JWTCustomClaims)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret := m.ctrl.Call(m, "ForgotPassword", ctx, req, client)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
-// ValidateToken indicates an expected call of ValidateToken.
-func (mr *MockITokenGeneratorMockRecorder) ValidateToken(tokenString any) *gomock.Call {
+// ForgotPassword indicates an expected call of ForgotPassword.
+func (mr *MockIPasswordServiceMockRecorder) ForgotPassword(ctx, req, client any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidateToken", reflect.TypeOf((*MockITokenGenerator)(nil).ValidateToken), tokenString)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ForgotPassword", reflect.TypeOf((*MockIPasswordService)(nil).ForgotPassword), ctx, req, client)
 }
 
-// MockITodoService is a mock of ITodoService interface.
-type MockITodoService struct {
+// ResetPassword mocks base method.
+func (m *MockIPasswordService) ResetPassword(ctx context.Context, req services.ResetPasswordRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResetPassword", ctx, req)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResetPassword indicates an expected call of ResetPassword.
+func (mr *MockIPasswordServiceMockRecorder) ResetPassword(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetPassword", reflect.TypeOf((*MockIPasswordService)(nil).ResetPassword), ctx, req)
+}
+
+// MockIEmailVerificationService is a mock of IEmailVerificationService interface.
+type MockIEmailVerificationService struct {
 	ctrl     *gomock.Controller
-	recorder *MockITodoServiceMockRecorder
+	recorder *MockIEmailVerificationServiceMockRecorder
 	isgomock struct{}
 }
 
-// MockITodoServiceMockRecorder is the mock recorder for MockITodoService.
-type MockITodoServiceMockRecorder struct {
-	mock *MockITodoService
+// MockIEmailVerificationServiceMockRecorder is the mock recorder for MockIEmailVerificationService.
+type MockIEmailVerificationServiceMockRecorder struct {
+	mock *MockIEmailVerificationService
 }
 
-// NewMockITodoService creates a new mock instance.
-func NewMockITodoService(ctrl *gomock.Controller) *MockITodoService {
-	mock := &MockITodoService{ctrl: ctrl}
-	mock.recorder = &MockITodoServiceMockRecorder{mock}
+// NewMockIEmailVerificationService creates a new mock instance.
+func NewMockIEmailVerificationService(ctrl *gomock.Controller) *MockIEmailVerificationService {
+	mock := &MockIEmailVerificationService{ctrl: ctrl}
+	mock.recorder = &MockIEmailVerificationServiceMockRecorder{mock}
 	return mock
 }
 
 // EXPECT returns an object that allows the caller to indicate expected use.
-func (m *MockITodoService) EXPECT() *MockITodoServiceMockRecorder {
+func (m *MockIEmailVerificationService) EXPECT() *MockIEmailVerificationServiceMockRecorder {
 	return m.recorder
 }
 
-// CreateTodo mocks base method.
-func (m *MockITodoService) CreateTodo(ctx context.Context, userID pgtype.UUID, req services.CreateTodoRequest) (*db.Todo, error) {
+// ConfirmEmailChange mocks base method.
+func (m *MockIEmailVerificationService) ConfirmEmailChange(ctx context.Context, token string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateTodo", ctx, userID, req)
-	ret0, _ := ret[0].(*db.Todo)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret := m.ctrl.Call(m, "ConfirmEmailChange", ctx, token)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
-// CreateTodo indicates an expected call of CreateTodo.
-func (mr *MockITodoServiceMockRecorder) CreateTodo(ctx, userID, req any) *gomock.Call {
+// ConfirmEmailChange indicates an expected call of ConfirmEmailChange.
+func (mr *MockIEmailVerificationServiceMockRecorder) ConfirmEmailChange(ctx, token any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTodo", reflect.TypeOf((*MockITodoService)(nil).CreateTodo), ctx, userID, req)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConfirmEmailChange", reflect.TypeOf((*MockIEmailVerificationService)(nil).ConfirmEmailChange), ctx, token)
 }
 
-// DeleteTodo mocks base method.
-func (m *MockITodoService) DeleteTodo(ctx context.Context, userID pgtype.UUID, todoID int32) error {
+// RequestEmailChange mocks base method.
+func (m *MockIEmailVerificationService) RequestEmailChange(ctx context.Context, userID pgtype.UUID, req services.ChangeEmailRequest) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteTodo", ctx, userID, todoID)
+	ret := m.ctrl.Call(m, "RequestEmailChange", ctx, userID, req)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// DeleteTodo indicates an expected call of DeleteTodo.
-func (mr *MockITodoServiceMockRecorder) DeleteTodo(ctx, userID, todoID any) *gomock.Call {
+// RequestEmailChange indicates an expected call of RequestEmailChange.
+func (mr *MockIEmailVerificationServiceMockRecorder) RequestEmailChange(ctx, userID, req any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTodo", reflect.TypeOf((*MockITodoService)(nil).DeleteTodo), ctx, userID, todoID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequestEmailChange", reflect.TypeOf((*MockIEmailVerificationService)(nil).RequestEmailChange), ctx, userID, req)
 }
 
-// ListTodos mocks base method.
-func (m *MockITodoService) ListTodos(ctx context.Context, userID pgtype.UUID) (*[]db.Todo, error) {
+// ResendVerification mocks base method.
+func (m *MockIEmailVerificationService) ResendVerification(ctx context.Context, userID pgtype.UUID) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListTodos", ctx, userID)
-	ret0, _ := ret[0].(*[]db.Todo)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret := m.ctrl.Call(m, "ResendVerification", ctx, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
-// ListTodos indicates an expected call of ListTodos.
-func (mr *MockITodoServiceMockRecorder) ListTodos(ctx, userID any) *gomock.Call {
+// ResendVerification indicates an expected call of ResendVerification.
+func (mr *MockIEmailVerificationServiceMockRecorder) ResendVerification(ctx, userID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTodos", reflect.TypeOf((*MockITodoService)(nil).ListTodos), ctx, userID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResendVerification", reflect.TypeOf((*MockIEmailVerificationService)(nil).ResendVerification), ctx, userID)
 }
 
-// SearchTodos mocks base method.
-func (m *MockITodoService) SearchTodos(ctx context.Context, userID pgtype.UUID, keyword string) (*[]db.Todo, error) {
+// VerifyEmail mocks base method.
+func (m *MockIEmailVerificationService) VerifyEmail(ctx context.Context, token string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SearchTodos", ctx, userID, keyword)
-	ret0, _ := ret[0].(*[]db.Todo)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret := m.ctrl.Call(m, "VerifyEmail", ctx, token)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
-// SearchTodos indicates an expected call of SearchTodos.
-func (mr *MockITodoServiceMockRecorder) SearchTodos(ctx, userID, keyword any) *gomock.Call {
+// VerifyEmail indicates an expected call of VerifyEmail.
+func (mr *MockIEmailVerificationServiceMockRecorder) VerifyEmail(ctx, token any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchTodos", reflect.TypeOf((*MockITodoService)(nil).SearchTodos), ctx, userID, keyword)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyEmail", reflect.TypeOf((*MockIEmailVerificationService)(nil).VerifyEmail), ctx, token)
 }
 
-// UpdateTodo mocks base method.
-func (m *MockITodoService) UpdateTodo(ctx context.Context, userID pgtype.UUID, todoID int32, req services.UpdateTodoRequest) (*db.Todo, error) {
+// MockITwoFactorService is a mock of ITwoFactorService interface.
+type MockITwoFactorService struct {
+	ctrl     *gomock.Controller
+	recorder *MockITwoFactorServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockITwoFactorServiceMockRecorder is the mock recorder for MockITwoFactorService.
+type MockITwoFactorServiceMockRecorder struct {
+	mock *MockITwoFactorService
+}
+
+// NewMockITwoFactorService creates a new mock instance.
+func NewMockITwoFactorService(ctrl *gomock.Controller) *MockITwoFactorService {
+	mock := &MockITwoFactorService{ctrl: ctrl}
+	mock.recorder = &MockITwoFactorServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockITwoFactorService) EXPECT() *MockITwoFactorServiceMockRecorder {
+	return m.recorder
+}
+
+// ConfirmTOTPEnrollment mocks base method.
+func (m *MockITwoFactorService) ConfirmTOTPEnrollment(ctx context.Context, userID pgtype.UUID, req services.ConfirmTOTPRequest) ([]string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateTodo", ctx, userID, todoID, req)
-	ret0, _ := ret[0].(*db.Todo)
+	ret := m.ctrl.Call(m, "ConfirmTOTPEnrollment", ctx, userID, req)
+	ret0, _ := ret[0].([]string)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// UpdateTodo indicates an expected call of UpdateTodo.
-func (mr *MockITodoServiceMockRecorder) UpdateTodo(ctx, userID, todoID, req any) *gomock.Call {
+// ConfirmTOTPEnrollment indicates an expected call of ConfirmTOTPEnrollment.
+func (mr *MockITwoFactorServiceMockRecorder) ConfirmTOTPEnrollment(ctx, userID, req any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTodo", reflect.TypeOf((*MockITodoService)(nil).UpdateTodo), ctx, userID, todoID, req)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConfirmTOTPEnrollment", reflect.TypeOf((*MockITwoFactorService)(nil).ConfirmTOTPEnrollment), ctx, userID, req)
 }
 
-// UpdateTodoPosition mocks base method.
-func (m *MockITodoService) UpdateTodoPosition(ctx context.Context, userID pgtype.UUID, todoID int32, req services.UpdateTodoPositionRequest) (*db.Todo, error) {
+// DisableTOTP mocks base method.
+func (m *MockITwoFactorService) DisableTOTP(ctx context.Context, userID pgtype.UUID, req services.DisableTOTPRequest) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateTodoPosition", ctx, userID, todoID, req)
-	ret0, _ := ret[0].(*db.Todo)
+	ret := m.ctrl.Call(m, "DisableTOTP", ctx, userID, req)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DisableTOTP indicates an expected call of DisableTOTP.
+func (mr *MockITwoFactorServiceMockRecorder) DisableTOTP(ctx, userID, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisableTOTP", reflect.TypeOf((*MockITwoFactorService)(nil).DisableTOTP), ctx, userID, req)
+}
+
+// StartTOTPEnrollment mocks base method.
+func (m *MockITwoFactorService) StartTOTPEnrollment(ctx context.Context, userID pgtype.UUID) (*services.TOTPEnrollment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StartTOTPEnrollment", ctx, userID)
+	ret0, _ := ret[0].(*services.TOTPEnrollment)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// UpdateTodoPosition indicates an expected call of UpdateTodoPosition.
-func (mr *MockITodoServiceMockRecorder) UpdateTodoPosition(ctx, userID, todoID, req any) *gomock.Call {
+// StartTOTPEnrollment indicates an expected call of StartTOTPEnrollment.
+func (mr *MockITwoFactorServiceMockRecorder) StartTOTPEnrollment(ctx, userID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTodoPosition", reflect.TypeOf((*MockITodoService)(nil).UpdateTodoPosition), ctx, userID, todoID, req)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartTOTPEnrollment", reflect.TypeOf((*MockITwoFactorService)(nil).StartTOTPEnrollment), ctx, userID)
+}
+
+// MockISessionService is a mock of ISessionService interface.
+type MockISessionService struct {
+	ctrl     *gomock.Controller
+	recorder *MockISessionServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockISessionServiceMockRecorder is the mock recorder for MockISessionService.
+type MockISessionServiceMockRecorder struct {
+	mock *MockISessionService
+}
+
+// NewMockISessionService creates a new mock instance.
+func NewMockISessionService(ctrl *gomock.Controller) *MockISessionService {
+	mock := &MockISessionService{ctrl: ctrl}
+	mock.recorder = &MockISessionServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockISessionService) EXPECT() *MockISessionServiceMockRecorder {
+	return m.recorder
+}
+
+// ListSessions mocks base method.
+func (m *MockISessionService) ListSessions(ctx context.Context, userID pgtype.UUID) ([]db.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSessions", ctx, userID)
+	ret0, _ := ret[0].([]db.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSessions indicates an expected call of ListSessions.
+func (mr *MockISessionServiceMockRecorder) ListSessions(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSessions", reflect.TypeOf((*MockISessionService)(nil).ListSessions), ctx, userID)
+}
+
+// RevokeAllSessions mocks base method.
+func (m *MockISessionService) RevokeAllSessions(ctx context.Context, userID pgtype.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeAllSessions", ctx, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeAllSessions indicates an expected call of RevokeAllSessions.
+func (mr *MockISessionServiceMockRecorder) RevokeAllSessions(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeAllSessions", reflect.TypeOf((*MockISessionService)(nil).RevokeAllSessions), ctx, userID)
+}
+
+// RevokeSession mocks base method.
+func (m *MockISessionService) RevokeSession(ctx context.Context, userID pgtype.UUID, publicID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeSession", ctx, userID, publicID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeSession indicates an expected call of RevokeSession.
+func (mr *MockISessionServiceMockRecorder) RevokeSession(ctx, userID, publicID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeSession", reflect.TypeOf((*MockISessionService)(nil).RevokeSession), ctx, userID, publicID)
+}
+
+// MockIPersonalAccessTokenService is a mock of IPersonalAccessTokenService interface.
+type MockIPersonalAccessTokenService struct {
+	ctrl     *gomock.Controller
+	recorder *MockIPersonalAccessTokenServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockIPersonalAccessTokenServiceMockRecorder is the mock recorder for MockIPersonalAccessTokenService.
+type MockIPersonalAccessTokenServiceMockRecorder struct {
+	mock *MockIPersonalAccessTokenService
+}
+
+// NewMockIPersonalAccessTokenService creates a new mock instance.
+func NewMockIPersonalAccessTokenService(ctrl *gomock.Controller) *MockIPersonalAccessTokenService {
+	mock := &MockIPersonalAccessTokenService{ctrl: ctrl}
+	mock.recorder = &MockIPersonalAccessTokenServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIPersonalAccessTokenService) EXPECT() *MockIPersonalAccessTokenServiceMockRecorder {
+	return m.recorder
+}
+
+// Authenticate mocks base method.
+func (m *MockIPersonalAccessTokenService) Authenticate(ctx context.Context, plainToken string) (string, []string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Authenticate", ctx, plainToken)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].([]string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Authenticate indicates an expected call of Authenticate.
+func (mr *MockIPersonalAccessTokenServiceMockRecorder) Authenticate(ctx, plainToken any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Authenticate", reflect.TypeOf((*MockIPersonalAccessTokenService)(nil).Authenticate), ctx, plainToken)
+}
+
+// CreateToken mocks base method.
+func (m *MockIPersonalAccessTokenService) CreateToken(ctx context.Context, userID pgtype.UUID, req services.CreatePersonalAccessTokenRequest) (*db.PersonalAccessToken, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateToken", ctx, userID, req)
+	ret0, _ := ret[0].(*db.PersonalAccessToken)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// CreateToken indicates an expected call of CreateToken.
+func (mr *MockIPersonalAccessTokenServiceMockRecorder) CreateToken(ctx, userID, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateToken", reflect.TypeOf((*MockIPersonalAccessTokenService)(nil).CreateToken), ctx, userID, req)
+}
+
+// DeleteToken mocks base method.
+func (m *MockIPersonalAccessTokenService) DeleteToken(ctx context.Context, userID pgtype.UUID, tokenID int32) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteToken", ctx, userID, tokenID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteToken indicates an expected call of DeleteToken.
+func (mr *MockIPersonalAccessTokenServiceMockRecorder) DeleteToken(ctx, userID, tokenID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteToken", reflect.TypeOf((*MockIPersonalAccessTokenService)(nil).DeleteToken), ctx, userID, tokenID)
+}
+
+// ListTokens mocks base method.
+func (m *MockIPersonalAccessTokenService) ListTokens(ctx context.Context, userID pgtype.UUID) ([]db.PersonalAccessToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTokens", ctx, userID)
+	ret0, _ := ret[0].([]db.PersonalAccessToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTokens indicates an expected call of ListTokens.
+func (mr *MockIPersonalAccessTokenServiceMockRecorder) ListTokens(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTokens", reflect.TypeOf((*MockIPersonalAccessTokenService)(nil).ListTokens), ctx, userID)
+}
+
+// MockIAdminService is a mock of IAdminService interface.
+type MockIAdminService struct {
+	ctrl     *gomock.Controller
+	recorder *MockIAdminServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockIAdminServiceMockRecorder is the mock recorder for MockIAdminService.
+type MockIAdminServiceMockRecorder struct {
+	mock *MockIAdminService
+}
+
+// NewMockIAdminService creates a new mock instance.
+func NewMockIAdminService(ctrl *gomock.Controller) *MockIAdminService {
+	mock := &MockIAdminService{ctrl: ctrl}
+	mock.recorder = &MockIAdminServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIAdminService) EXPECT() *MockIAdminServiceMockRecorder {
+	return m.recorder
+}
+
+// DisableUser mocks base method.
+func (m *MockIAdminService) DisableUser(ctx context.Context, adminID, userID pgtype.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DisableUser", ctx, adminID, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DisableUser indicates an expected call of DisableUser.
+func (mr *MockIAdminServiceMockRecorder) DisableUser(ctx, adminID, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisableUser", reflect.TypeOf((*MockIAdminService)(nil).DisableUser), ctx, adminID, userID)
+}
+
+// EnableUser mocks base method.
+func (m *MockIAdminService) EnableUser(ctx context.Context, userID pgtype.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnableUser", ctx, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EnableUser indicates an expected call of EnableUser.
+func (mr *MockIAdminServiceMockRecorder) EnableUser(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnableUser", reflect.TypeOf((*MockIAdminService)(nil).EnableUser), ctx, userID)
+}
+
+// ForcePasswordReset mocks base method.
+func (m *MockIAdminService) ForcePasswordReset(ctx context.Context, userID pgtype.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ForcePasswordReset", ctx, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ForcePasswordReset indicates an expected call of ForcePasswordReset.
+func (mr *MockIAdminServiceMockRecorder) ForcePasswordReset(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ForcePasswordReset", reflect.TypeOf((*MockIAdminService)(nil).ForcePasswordReset), ctx, userID)
+}
+
+// GetUser mocks base method.
+func (m *MockIAdminService) GetUser(ctx context.Context, userID pgtype.UUID) (*services.UserDetail, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUser", ctx, userID)
+	ret0, _ := ret[0].(*services.UserDetail)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUser indicates an expected call of GetUser.
+func (mr *MockIAdminServiceMockRecorder) GetUser(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUser", reflect.TypeOf((*MockIAdminService)(nil).GetUser), ctx, userID)
+}
+
+// ListUsers mocks base method.
+func (m *MockIAdminService) ListUsers(ctx context.Context, req services.ListUsersRequest) (*services.UserList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUsers", ctx, req)
+	ret0, _ := ret[0].(*services.UserList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUsers indicates an expected call of ListUsers.
+func (mr *MockIAdminServiceMockRecorder) ListUsers(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUsers", reflect.TypeOf((*MockIAdminService)(nil).ListUsers), ctx, req)
+}
+
+// MockIPasswordHasher is a mock of IPasswordHasher interface.
+type MockIPasswordHasher struct {
+	ctrl     *gomock.Controller
+	recorder *MockIPasswordHasherMockRecorder
+	isgomock struct{}
+}
+
+// MockIPasswordHasherMockRecorder is the mock recorder for MockIPasswordHasher.
+type MockIPasswordHasherMockRecorder struct {
+	mock *MockIPasswordHasher
+}
+
+// NewMockIPasswordHasher creates a new mock instance.
+func NewMockIPasswordHasher(ctrl *gomock.Controller) *MockIPasswordHasher {
+	mock := &MockIPasswordHasher{ctrl: ctrl}
+	mock.recorder = &MockIPasswordHasherMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIPasswordHasher) EXPECT() *MockIPasswordHasherMockRecorder {
+	return m.recorder
+}
+
+// CompareHashAndPassword mocks base method.
+func (m *MockIPasswordHasher) CompareHashAndPassword(hashedPassword, password []byte) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompareHashAndPassword", hashedPassword, password)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CompareHashAndPassword indicates an expected call of CompareHashAndPassword.
+func (mr *MockIPasswordHasherMockRecorder) CompareHashAndPassword(hashedPassword, password any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompareHashAndPassword", reflect.TypeOf((*MockIPasswordHasher)(nil).CompareHashAndPassword), hashedPassword, password)
+}
+
+// GenerateFromPassword mocks base method.
+func (m *MockIPasswordHasher) GenerateFromPassword(password []byte) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GenerateFromPassword", password)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GenerateFromPassword indicates an expected call of GenerateFromPassword.
+func (mr *MockIPasswordHasherMockRecorder) GenerateFromPassword(password any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerateFromPassword", reflect.TypeOf((*MockIPasswordHasher)(nil).GenerateFromPassword), password)
+}
+
+// NeedsRehash mocks base method.
+func (m *MockIPasswordHasher) NeedsRehash(hashedPassword []byte) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NeedsRehash", hashedPassword)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// NeedsRehash indicates an expected call of NeedsRehash.
+func (mr *MockIPasswordHasherMockRecorder) NeedsRehash(hashedPassword any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NeedsRehash", reflect.TypeOf((*MockIPasswordHasher)(nil).NeedsRehash), hashedPassword)
+}
+
+// MockITokenGenerator is a mock of ITokenGenerator interface.
+type MockITokenGenerator struct {
+	ctrl     *gomock.Controller
+	recorder *MockITokenGeneratorMockRecorder
+	isgomock struct{}
+}
+
+// MockITokenGeneratorMockRecorder is the mock recorder for MockITokenGenerator.
+type MockITokenGeneratorMockRecorder struct {
+	mock *MockITokenGenerator
+}
+
+// NewMockITokenGenerator creates a new mock instance.
+func NewMockITokenGenerator(ctrl *gomock.Controller) *MockITokenGenerator {
+	mock := &MockITokenGenerator{ctrl: ctrl}
+	mock.recorder = &MockITokenGeneratorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockITokenGenerator) EXPECT() *MockITokenGeneratorMockRecorder {
+	return m.recorder
+}
+
+// GenerateEmailChangeToken mocks base method.
+func (m *MockITokenGenerator) GenerateEmailChangeToken(userID, email, newEmail string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GenerateEmailChangeToken", userID, email, newEmail)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GenerateEmailChangeToken indicates an expected call of GenerateEmailChangeToken.
+func (mr *MockITokenGeneratorMockRecorder) GenerateEmailChangeToken(userID, email, newEmail any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerateEmailChangeToken", reflect.TypeOf((*MockITokenGenerator)(nil).GenerateEmailChangeToken), userID, email, newEmail)
+}
+
+// GenerateEmailVerificationToken mocks base method.
+func (m *MockITokenGenerator) GenerateEmailVerificationToken(userID, email string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GenerateEmailVerificationToken", userID, email)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GenerateEmailVerificationToken indicates an expected call of GenerateEmailVerificationToken.
+func (mr *MockITokenGeneratorMockRecorder) GenerateEmailVerificationToken(userID, email any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerateEmailVerificationToken", reflect.TypeOf((*MockITokenGenerator)(nil).GenerateEmailVerificationToken), userID, email)
+}
+
+// GenerateRefreshToken mocks base method.
+func (m *MockITokenGenerator) GenerateRefreshToken() (string, time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GenerateRefreshToken")
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(time.Time)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GenerateRefreshToken indicates an expected call of GenerateRefreshToken.
+func (mr *MockITokenGeneratorMockRecorder) GenerateRefreshToken() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerateRefreshToken", reflect.TypeOf((*MockITokenGenerator)(nil).GenerateRefreshToken))
+}
+
+// GenerateTodoCursor mocks base method.
+func (m *MockITokenGenerator) GenerateTodoCursor(userID string, cursor json.RawMessage) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GenerateTodoCursor", userID, cursor)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GenerateTodoCursor indicates an expected call of GenerateTodoCursor.
+func (mr *MockITokenGeneratorMockRecorder) GenerateTodoCursor(userID, cursor any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerateTodoCursor", reflect.TypeOf((*MockITokenGenerator)(nil).GenerateTodoCursor), userID, cursor)
+}
+
+// GenerateToken mocks base method.
+func (m *MockITokenGenerator) GenerateToken(userID, sessionID string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GenerateToken", userID, sessionID)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GenerateToken indicates an expected call of GenerateToken.
+func (mr *MockITokenGeneratorMockRecorder) GenerateToken(userID, sessionID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerateToken", reflect.TypeOf((*MockITokenGenerator)(nil).GenerateToken), userID, sessionID)
+}
+
+// GenerateTwoFactorChallengeToken mocks base method.
+func (m *MockITokenGenerator) GenerateTwoFactorChallengeToken(userID, sessionID string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GenerateTwoFactorChallengeToken", userID, sessionID)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GenerateTwoFactorChallengeToken indicates an expected call of GenerateTwoFactorChallengeToken.
+func (mr *MockITokenGeneratorMockRecorder) GenerateTwoFactorChallengeToken(userID, sessionID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerateTwoFactorChallengeToken", reflect.TypeOf((*MockITokenGenerator)(nil).GenerateTwoFactorChallengeToken), userID, sessionID)
+}
+
+// JWKS mocks base method.
+func (m *MockITokenGenerator) JWKS() *services.JWKS {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "JWKS")
+	ret0, _ := ret[0].(*services.JWKS)
+	return ret0
+}
+
+// JWKS indicates an expected call of JWKS.
+func (mr *MockITokenGeneratorMockRecorder) JWKS() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "JWKS", reflect.TypeOf((*MockITokenGenerator)(nil).JWKS))
+}
+
+// ValidateEmailToken mocks base method.
+func (m *MockITokenGenerator) ValidateEmailToken(tokenString, tokenType string) (*services.JWTCustomClaims, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ValidateEmailToken", tokenString, tokenType)
+	ret0, _ := ret[0].(*services.JWTCustomClaims)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ValidateEmailToken indicates an expected call of ValidateEmailToken.
+func (mr *MockITokenGeneratorMockRecorder) ValidateEmailToken(tokenString, tokenType any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidateEmailToken", reflect.TypeOf((*MockITokenGenerator)(nil).ValidateEmailToken), tokenString, tokenType)
+}
+
+// ValidateTodoCursor mocks base method.
+func (m *MockITokenGenerator) ValidateTodoCursor(tokenString string) (*services.JWTCustomClaims, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ValidateTodoCursor", tokenString)
+	ret0, _ := ret[0].(*services.JWTCustomClaims)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ValidateTodoCursor indicates an expected call of ValidateTodoCursor.
+func (mr *MockITokenGeneratorMockRecorder) ValidateTodoCursor(tokenString any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidateTodoCursor", reflect.TypeOf((*MockITokenGenerator)(nil).ValidateTodoCursor), tokenString)
+}
+
+// ValidateToken mocks base method.
+func (m *MockITokenGenerator) ValidateToken(tokenString string) (*services.JWTCustomClaims, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ValidateToken", tokenString)
+	ret0, _ := ret[0].(*services.JWTCustomClaims)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ValidateToken indicates an expected call of ValidateToken.
+func (mr *MockITokenGeneratorMockRecorder) ValidateToken(tokenString any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidateToken", reflect.TypeOf((*MockITokenGenerator)(nil).ValidateToken), tokenString)
+}
+
+// ValidateTwoFactorChallengeToken mocks base method.
+func (m *MockITokenGenerator) ValidateTwoFactorChallengeToken(tokenString string) (*services.JWTCustomClaims, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ValidateTwoFactorChallengeToken", tokenString)
+	ret0, _ := ret[0].(*services.JWTCustomClaims)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ValidateTwoFactorChallengeToken indicates an expected call of ValidateTwoFactorChallengeToken.
+func (mr *MockITokenGeneratorMockRecorder) ValidateTwoFactorChallengeToken(tokenString any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidateTwoFactorChallengeToken", reflect.TypeOf((*MockITokenGenerator)(nil).ValidateTwoFactorChallengeToken), tokenString)
+}
+
+// MockIRefreshTokenStore is a mock of IRefreshTokenStore interface.
+type MockIRefreshTokenStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockIRefreshTokenStoreMockRecorder
+	isgomock struct{}
+}
+
+// MockIRefreshTokenStoreMockRecorder is the mock recorder for MockIRefreshTokenStore.
+type MockIRefreshTokenStoreMockRecorder struct {
+	mock *MockIRefreshTokenStore
+}
+
+// NewMockIRefreshTokenStore creates a new mock instance.
+func NewMockIRefreshTokenStore(ctrl *gomock.Controller) *MockIRefreshTokenStore {
+	mock := &MockIRefreshTokenStore{ctrl: ctrl}
+	mock.recorder = &MockIRefreshTokenStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIRefreshTokenStore) EXPECT() *MockIRefreshTokenStoreMockRecorder {
+	return m.recorder
+}
+
+// Get mocks base method.
+func (m *MockIRefreshTokenStore) Get(ctx context.Context, tokenHash string) (*db.RefreshToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, tokenHash)
+	ret0, _ := ret[0].(*db.RefreshToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockIRefreshTokenStoreMockRecorder) Get(ctx, tokenHash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockIRefreshTokenStore)(nil).Get), ctx, tokenHash)
+}
+
+// MarkUsed mocks base method.
+func (m *MockIRefreshTokenStore) MarkUsed(ctx context.Context, tokenHash string, expiresAt time.Time) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkUsed", ctx, tokenHash, expiresAt)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkUsed indicates an expected call of MarkUsed.
+func (mr *MockIRefreshTokenStoreMockRecorder) MarkUsed(ctx, tokenHash, expiresAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkUsed", reflect.TypeOf((*MockIRefreshTokenStore)(nil).MarkUsed), ctx, tokenHash, expiresAt)
+}
+
+// RevokeFamily mocks base method.
+func (m *MockIRefreshTokenStore) RevokeFamily(ctx context.Context, sessionID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeFamily", ctx, sessionID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeFamily indicates an expected call of RevokeFamily.
+func (mr *MockIRefreshTokenStoreMockRecorder) RevokeFamily(ctx, sessionID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeFamily", reflect.TypeOf((*MockIRefreshTokenStore)(nil).RevokeFamily), ctx, sessionID)
+}
+
+// Save mocks base method.
+func (m *MockIRefreshTokenStore) Save(ctx context.Context, tokenHash string, token db.RefreshToken) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", ctx, tokenHash, token)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockIRefreshTokenStoreMockRecorder) Save(ctx, tokenHash, token any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockIRefreshTokenStore)(nil).Save), ctx, tokenHash, token)
+}
+
+// MockISessionStore is a mock of ISessionStore interface.
+type MockISessionStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockISessionStoreMockRecorder
+	isgomock struct{}
+}
+
+// MockISessionStoreMockRecorder is the mock recorder for MockISessionStore.
+type MockISessionStoreMockRecorder struct {
+	mock *MockISessionStore
+}
+
+// NewMockISessionStore creates a new mock instance.
+func NewMockISessionStore(ctrl *gomock.Controller) *MockISessionStore {
+	mock := &MockISessionStore{ctrl: ctrl}
+	mock.recorder = &MockISessionStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockISessionStore) EXPECT() *MockISessionStoreMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockISessionStore) Create(ctx context.Context, session db.Session, expiresAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, session, expiresAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockISessionStoreMockRecorder) Create(ctx, session, expiresAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockISessionStore)(nil).Create), ctx, session, expiresAt)
+}
+
+// Delete mocks base method.
+func (m *MockISessionStore) Delete(ctx context.Context, userID, sessionID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, userID, sessionID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockISessionStoreMockRecorder) Delete(ctx, userID, sessionID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockISessionStore)(nil).Delete), ctx, userID, sessionID)
+}
+
+// FindByPublicID mocks base method.
+func (m *MockISessionStore) FindByPublicID(ctx context.Context, userID, publicID string) (*db.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByPublicID", ctx, userID, publicID)
+	ret0, _ := ret[0].(*db.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByPublicID indicates an expected call of FindByPublicID.
+func (mr *MockISessionStoreMockRecorder) FindByPublicID(ctx, userID, publicID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByPublicID", reflect.TypeOf((*MockISessionStore)(nil).FindByPublicID), ctx, userID, publicID)
+}
+
+// List mocks base method.
+func (m *MockISessionStore) List(ctx context.Context, userID string) ([]db.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, userID)
+	ret0, _ := ret[0].([]db.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockISessionStoreMockRecorder) List(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockISessionStore)(nil).List), ctx, userID)
+}
+
+// Touch mocks base method.
+func (m *MockISessionStore) Touch(ctx context.Context, sessionID string, lastSeenAt time.Time) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Touch", ctx, sessionID, lastSeenAt)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Touch indicates an expected call of Touch.
+func (mr *MockISessionStoreMockRecorder) Touch(ctx, sessionID, lastSeenAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Touch", reflect.TypeOf((*MockISessionStore)(nil).Touch), ctx, sessionID, lastSeenAt)
+}
+
+// MockILoginAttemptStore is a mock of ILoginAttemptStore interface.
+type MockILoginAttemptStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockILoginAttemptStoreMockRecorder
+	isgomock struct{}
+}
+
+// MockILoginAttemptStoreMockRecorder is the mock recorder for MockILoginAttemptStore.
+type MockILoginAttemptStoreMockRecorder struct {
+	mock *MockILoginAttemptStore
+}
+
+// NewMockILoginAttemptStore creates a new mock instance.
+func NewMockILoginAttemptStore(ctrl *gomock.Controller) *MockILoginAttemptStore {
+	mock := &MockILoginAttemptStore{ctrl: ctrl}
+	mock.recorder = &MockILoginAttemptStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockILoginAttemptStore) EXPECT() *MockILoginAttemptStoreMockRecorder {
+	return m.recorder
+}
+
+// Lock mocks base method.
+func (m *MockILoginAttemptStore) Lock(ctx context.Context, key string, until time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Lock", ctx, key, until)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Lock indicates an expected call of Lock.
+func (mr *MockILoginAttemptStoreMockRecorder) Lock(ctx, key, until any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Lock", reflect.TypeOf((*MockILoginAttemptStore)(nil).Lock), ctx, key, until)
+}
+
+// LockedUntil mocks base method.
+func (m *MockILoginAttemptStore) LockedUntil(ctx context.Context, key string) (time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockedUntil", ctx, key)
+	ret0, _ := ret[0].(time.Time)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LockedUntil indicates an expected call of LockedUntil.
+func (mr *MockILoginAttemptStoreMockRecorder) LockedUntil(ctx, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockedUntil", reflect.TypeOf((*MockILoginAttemptStore)(nil).LockedUntil), ctx, key)
+}
+
+// RecordFailure mocks base method.
+func (m *MockILoginAttemptStore) RecordFailure(ctx context.Context, key string, window time.Duration) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordFailure", ctx, key, window)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RecordFailure indicates an expected call of RecordFailure.
+func (mr *MockILoginAttemptStoreMockRecorder) RecordFailure(ctx, key, window any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordFailure", reflect.TypeOf((*MockILoginAttemptStore)(nil).RecordFailure), ctx, key, window)
+}
+
+// Reset mocks base method.
+func (m *MockILoginAttemptStore) Reset(ctx context.Context, key string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reset", ctx, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Reset indicates an expected call of Reset.
+func (mr *MockILoginAttemptStoreMockRecorder) Reset(ctx, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reset", reflect.TypeOf((*MockILoginAttemptStore)(nil).Reset), ctx, key)
+}
+
+// MockIOIDCProvider is a mock of IOIDCProvider interface.
+type MockIOIDCProvider struct {
+	ctrl     *gomock.Controller
+	recorder *MockIOIDCProviderMockRecorder
+	isgomock struct{}
+}
+
+// MockIOIDCProviderMockRecorder is the mock recorder for MockIOIDCProvider.
+type MockIOIDCProviderMockRecorder struct {
+	mock *MockIOIDCProvider
+}
+
+// NewMockIOIDCProvider creates a new mock instance.
+func NewMockIOIDCProvider(ctrl *gomock.Controller) *MockIOIDCProvider {
+	mock := &MockIOIDCProvider{ctrl: ctrl}
+	mock.recorder = &MockIOIDCProviderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIOIDCProvider) EXPECT() *MockIOIDCProviderMockRecorder {
+	return m.recorder
+}
+
+// AuthCodeURL mocks base method.
+func (m *MockIOIDCProvider) AuthCodeURL(ctx context.Context, state, nonce, codeChallenge string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AuthCodeURL", ctx, state, nonce, codeChallenge)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AuthCodeURL indicates an expected call of AuthCodeURL.
+func (mr *MockIOIDCProviderMockRecorder) AuthCodeURL(ctx, state, nonce, codeChallenge any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AuthCodeURL", reflect.TypeOf((*MockIOIDCProvider)(nil).AuthCodeURL), ctx, state, nonce, codeChallenge)
+}
+
+// Exchange mocks base method.
+func (m *MockIOIDCProvider) Exchange(ctx context.Context, code, codeVerifier, nonce string) (*oidc.Claims, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Exchange", ctx, code, codeVerifier, nonce)
+	ret0, _ := ret[0].(*oidc.Claims)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Exchange indicates an expected call of Exchange.
+func (mr *MockIOIDCProviderMockRecorder) Exchange(ctx, code, codeVerifier, nonce any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Exchange", reflect.TypeOf((*MockIOIDCProvider)(nil).Exchange), ctx, code, codeVerifier, nonce)
+}
+
+// MockIBlobStore is a mock of IBlobStore interface.
+type MockIBlobStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockIBlobStoreMockRecorder
+	isgomock struct{}
+}
+
+// MockIBlobStoreMockRecorder is the mock recorder for MockIBlobStore.
+type MockIBlobStoreMockRecorder struct {
+	mock *MockIBlobStore
+}
+
+// NewMockIBlobStore creates a new mock instance.
+func NewMockIBlobStore(ctrl *gomock.Controller) *MockIBlobStore {
+	mock := &MockIBlobStore{ctrl: ctrl}
+	mock.recorder = &MockIBlobStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIBlobStore) EXPECT() *MockIBlobStoreMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockIBlobStore) Delete(ctx context.Context, key string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockIBlobStoreMockRecorder) Delete(ctx, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockIBlobStore)(nil).Delete), ctx, key)
+}
+
+// Get mocks base method.
+func (m *MockIBlobStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, key)
+	ret0, _ := ret[0].(io.ReadCloser)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockIBlobStoreMockRecorder) Get(ctx, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockIBlobStore)(nil).Get), ctx, key)
+}
+
+// Put mocks base method.
+func (m *MockIBlobStore) Put(ctx context.Context, key string, r io.Reader) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Put", ctx, key, r)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Put indicates an expected call of Put.
+func (mr *MockIBlobStoreMockRecorder) Put(ctx, key, r any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Put", reflect.TypeOf((*MockIBlobStore)(nil).Put), ctx, key, r)
+}
+
+// MockIMailer is a mock of IMailer interface.
+type MockIMailer struct {
+	ctrl     *gomock.Controller
+	recorder *MockIMailerMockRecorder
+	isgomock struct{}
+}
+
+// MockIMailerMockRecorder is the mock recorder for MockIMailer.
+type MockIMailerMockRecorder struct {
+	mock *MockIMailer
+}
+
+// NewMockIMailer creates a new mock instance.
+func NewMockIMailer(ctrl *gomock.Controller) *MockIMailer {
+	mock := &MockIMailer{ctrl: ctrl}
+	mock.recorder = &MockIMailerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIMailer) EXPECT() *MockIMailerMockRecorder {
+	return m.recorder
+}
+
+// Send mocks base method.
+func (m *MockIMailer) Send(ctx context.Context, msg mailer.Message) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Send", ctx, msg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Send indicates an expected call of Send.
+func (mr *MockIMailerMockRecorder) Send(ctx, msg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Send", reflect.TypeOf((*MockIMailer)(nil).Send), ctx, msg)
+}
+
+// MockITodoService is a mock of ITodoService interface.
+type MockITodoService struct {
+	ctrl     *gomock.Controller
+	recorder *MockITodoServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockITodoServiceMockRecorder is the mock recorder for MockITodoService.
+type MockITodoServiceMockRecorder struct {
+	mock *MockITodoService
+}
+
+// NewMockITodoService creates a new mock instance.
+func NewMockITodoService(ctrl *gomock.Controller) *MockITodoService {
+	mock := &MockITodoService{ctrl: ctrl}
+	mock.recorder = &MockITodoServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockITodoService) EXPECT() *MockITodoServiceMockRecorder {
+	return m.recorder
+}
+
+// CreateTodo mocks base method.
+func (m *MockITodoService) CreateTodo(ctx context.Context, userID pgtype.UUID, req services.CreateTodoRequest) (*db.Todo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTodo", ctx, userID, req)
+	ret0, _ := ret[0].(*db.Todo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTodo indicates an expected call of CreateTodo.
+func (mr *MockITodoServiceMockRecorder) CreateTodo(ctx, userID, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTodo", reflect.TypeOf((*MockITodoService)(nil).CreateTodo), ctx, userID, req)
+}
+
+// DeleteTodo mocks base method.
+func (m *MockITodoService) DeleteTodo(ctx context.Context, userID pgtype.UUID, todoID int32) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTodo", ctx, userID, todoID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteTodo indicates an expected call of DeleteTodo.
+func (mr *MockITodoServiceMockRecorder) DeleteTodo(ctx, userID, todoID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTodo", reflect.TypeOf((*MockITodoService)(nil).DeleteTodo), ctx, userID, todoID)
+}
+
+// ListOccurrences mocks base method.
+func (m *MockITodoService) ListOccurrences(ctx context.Context, userID pgtype.UUID, todoID int32, req services.ListOccurrencesRequest) ([]time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListOccurrences", ctx, userID, todoID, req)
+	ret0, _ := ret[0].([]time.Time)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListOccurrences indicates an expected call of ListOccurrences.
+func (mr *MockITodoServiceMockRecorder) ListOccurrences(ctx, userID, todoID, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOccurrences", reflect.TypeOf((*MockITodoService)(nil).ListOccurrences), ctx, userID, todoID, req)
+}
+
+// ListTodos mocks base method.
+func (m *MockITodoService) ListTodos(ctx context.Context, userID pgtype.UUID, req services.ListTodosRequest) (*services.TodoPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTodos", ctx, userID, req)
+	ret0, _ := ret[0].(*services.TodoPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTodos indicates an expected call of ListTodos.
+func (mr *MockITodoServiceMockRecorder) ListTodos(ctx, userID, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTodos", reflect.TypeOf((*MockITodoService)(nil).ListTodos), ctx, userID, req)
+}
+
+// MoveTodo mocks base method.
+func (m *MockITodoService) MoveTodo(ctx context.Context, userID pgtype.UUID, todoID int32, req services.MoveTodoRequest) (*db.Todo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MoveTodo", ctx, userID, todoID, req)
+	ret0, _ := ret[0].(*db.Todo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MoveTodo indicates an expected call of MoveTodo.
+func (mr *MockITodoServiceMockRecorder) MoveTodo(ctx, userID, todoID, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MoveTodo", reflect.TypeOf((*MockITodoService)(nil).MoveTodo), ctx, userID, todoID, req)
+}
+
+// SearchTodos mocks base method.
+func (m *MockITodoService) SearchTodos(ctx context.Context, userID pgtype.UUID, req services.SearchTodosRequest) (*services.TodoPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchTodos", ctx, userID, req)
+	ret0, _ := ret[0].(*services.TodoPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchTodos indicates an expected call of SearchTodos.
+func (mr *MockITodoServiceMockRecorder) SearchTodos(ctx, userID, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchTodos", reflect.TypeOf((*MockITodoService)(nil).SearchTodos), ctx, userID, req)
+}
+
+// UpdateTodo mocks base method.
+func (m *MockITodoService) UpdateTodo(ctx context.Context, userID pgtype.UUID, todoID int32, req services.UpdateTodoRequest) (*db.Todo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTodo", ctx, userID, todoID, req)
+	ret0, _ := ret[0].(*db.Todo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateTodo indicates an expected call of UpdateTodo.
+func (mr *MockITodoServiceMockRecorder) UpdateTodo(ctx, userID, todoID, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTodo", reflect.TypeOf((*MockITodoService)(nil).UpdateTodo), ctx, userID, todoID, req)
+}
+
+// UpdateTodoPosition mocks base method.
+func (m *MockITodoService) UpdateTodoPosition(ctx context.Context, userID pgtype.UUID, todoID int32, req services.UpdateTodoPositionRequest) (*db.Todo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTodoPosition", ctx, userID, todoID, req)
+	ret0, _ := ret[0].(*db.Todo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateTodoPosition indicates an expected call of UpdateTodoPosition.
+func (mr *MockITodoServiceMockRecorder) UpdateTodoPosition(ctx, userID, todoID, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTodoPosition", reflect.TypeOf((*MockITodoService)(nil).UpdateTodoPosition), ctx, userID, todoID, req)
+}
+
+// MockIListService is a mock of IListService interface.
+type MockIListService struct {
+	ctrl     *gomock.Controller
+	recorder *MockIListServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockIListServiceMockRecorder is the mock recorder for MockIListService.
+type MockIListServiceMockRecorder struct {
+	mock *MockIListService
+}
+
+// NewMockIListService creates a new mock instance.
+func NewMockIListService(ctrl *gomock.Controller) *MockIListService {
+	mock := &MockIListService{ctrl: ctrl}
+	mock.recorder = &MockIListServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIListService) EXPECT() *MockIListServiceMockRecorder {
+	return m.recorder
+}
+
+// ArchiveList mocks base method.
+func (m *MockIListService) ArchiveList(ctx context.Context, userID pgtype.UUID, listID int32) (*db.List, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ArchiveList", ctx, userID, listID)
+	ret0, _ := ret[0].(*db.List)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ArchiveList indicates an expected call of ArchiveList.
+func (mr *MockIListServiceMockRecorder) ArchiveList(ctx, userID, listID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ArchiveList", reflect.TypeOf((*MockIListService)(nil).ArchiveList), ctx, userID, listID)
+}
+
+// CreateList mocks base method.
+func (m *MockIListService) CreateList(ctx context.Context, userID pgtype.UUID, req services.ListRequest) (*db.List, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateList", ctx, userID, req)
+	ret0, _ := ret[0].(*db.List)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateList indicates an expected call of CreateList.
+func (mr *MockIListServiceMockRecorder) CreateList(ctx, userID, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateList", reflect.TypeOf((*MockIListService)(nil).CreateList), ctx, userID, req)
+}
+
+// DeleteList mocks base method.
+func (m *MockIListService) DeleteList(ctx context.Context, userID pgtype.UUID, listID int32) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteList", ctx, userID, listID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteList indicates an expected call of DeleteList.
+func (mr *MockIListServiceMockRecorder) DeleteList(ctx, userID, listID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteList", reflect.TypeOf((*MockIListService)(nil).DeleteList), ctx, userID, listID)
+}
+
+// GetList mocks base method.
+func (m *MockIListService) GetList(ctx context.Context, userID pgtype.UUID, listID int32) (*db.List, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetList", ctx, userID, listID)
+	ret0, _ := ret[0].(*db.List)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetList indicates an expected call of GetList.
+func (mr *MockIListServiceMockRecorder) GetList(ctx, userID, listID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetList", reflect.TypeOf((*MockIListService)(nil).GetList), ctx, userID, listID)
+}
+
+// ListLists mocks base method.
+func (m *MockIListService) ListLists(ctx context.Context, userID pgtype.UUID, req services.ListListsRequest) ([]db.List, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListLists", ctx, userID, req)
+	ret0, _ := ret[0].([]db.List)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListLists indicates an expected call of ListLists.
+func (mr *MockIListServiceMockRecorder) ListLists(ctx, userID, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListLists", reflect.TypeOf((*MockIListService)(nil).ListLists), ctx, userID, req)
+}
+
+// UnarchiveList mocks base method.
+func (m *MockIListService) UnarchiveList(ctx context.Context, userID pgtype.UUID, listID int32) (*db.List, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnarchiveList", ctx, userID, listID)
+	ret0, _ := ret[0].(*db.List)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UnarchiveList indicates an expected call of UnarchiveList.
+func (mr *MockIListServiceMockRecorder) UnarchiveList(ctx, userID, listID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnarchiveList", reflect.TypeOf((*MockIListService)(nil).UnarchiveList), ctx, userID, listID)
+}
+
+// UpdateList mocks base method.
+func (m *MockIListService) UpdateList(ctx context.Context, userID pgtype.UUID, listID int32, req services.ListRequest) (*db.List, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateList", ctx, userID, listID, req)
+	ret0, _ := ret[0].(*db.List)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateList indicates an expected call of UpdateList.
+func (mr *MockIListServiceMockRecorder) UpdateList(ctx, userID, listID, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateList", reflect.TypeOf((*MockIListService)(nil).UpdateList), ctx, userID, listID, req)
+}
+
+// MockITagService is a mock of ITagService interface.
+type MockITagService struct {
+	ctrl     *gomock.Controller
+	recorder *MockITagServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockITagServiceMockRecorder is the mock recorder for MockITagService.
+type MockITagServiceMockRecorder struct {
+	mock *MockITagService
+}
+
+// NewMockITagService creates a new mock instance.
+func NewMockITagService(ctrl *gomock.Controller) *MockITagService {
+	mock := &MockITagService{ctrl: ctrl}
+	mock.recorder = &MockITagServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockITagService) EXPECT() *MockITagServiceMockRecorder {
+	return m.recorder
+}
+
+// AttachTag mocks base method.
+func (m *MockITagService) AttachTag(ctx context.Context, userID pgtype.UUID, todoID, tagID int32) (*db.Todo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AttachTag", ctx, userID, todoID, tagID)
+	ret0, _ := ret[0].(*db.Todo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AttachTag indicates an expected call of AttachTag.
+func (mr *MockITagServiceMockRecorder) AttachTag(ctx, userID, todoID, tagID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AttachTag", reflect.TypeOf((*MockITagService)(nil).AttachTag), ctx, userID, todoID, tagID)
+}
+
+// CreateTag mocks base method.
+func (m *MockITagService) CreateTag(ctx context.Context, userID pgtype.UUID, req services.TagRequest) (*db.Tag, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTag", ctx, userID, req)
+	ret0, _ := ret[0].(*db.Tag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTag indicates an expected call of CreateTag.
+func (mr *MockITagServiceMockRecorder) CreateTag(ctx, userID, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTag", reflect.TypeOf((*MockITagService)(nil).CreateTag), ctx, userID, req)
+}
+
+// DeleteTag mocks base method.
+func (m *MockITagService) DeleteTag(ctx context.Context, userID pgtype.UUID, tagID int32) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTag", ctx, userID, tagID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteTag indicates an expected call of DeleteTag.
+func (mr *MockITagServiceMockRecorder) DeleteTag(ctx, userID, tagID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTag", reflect.TypeOf((*MockITagService)(nil).DeleteTag), ctx, userID, tagID)
+}
+
+// DetachTag mocks base method.
+func (m *MockITagService) DetachTag(ctx context.Context, userID pgtype.UUID, todoID, tagID int32) (*db.Todo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DetachTag", ctx, userID, todoID, tagID)
+	ret0, _ := ret[0].(*db.Todo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DetachTag indicates an expected call of DetachTag.
+func (mr *MockITagServiceMockRecorder) DetachTag(ctx, userID, todoID, tagID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DetachTag", reflect.TypeOf((*MockITagService)(nil).DetachTag), ctx, userID, todoID, tagID)
+}
+
+// ListTags mocks base method.
+func (m *MockITagService) ListTags(ctx context.Context, userID pgtype.UUID) ([]db.Tag, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTags", ctx, userID)
+	ret0, _ := ret[0].([]db.Tag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTags indicates an expected call of ListTags.
+func (mr *MockITagServiceMockRecorder) ListTags(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTags", reflect.TypeOf((*MockITagService)(nil).ListTags), ctx, userID)
+}
+
+// UpdateTag mocks base method.
+func (m *MockITagService) UpdateTag(ctx context.Context, userID pgtype.UUID, tagID int32, req services.TagRequest) (*db.Tag, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTag", ctx, userID, tagID, req)
+	ret0, _ := ret[0].(*db.Tag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateTag indicates an expected call of UpdateTag.
+func (mr *MockITagServiceMockRecorder) UpdateTag(ctx, userID, tagID, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTag", reflect.TypeOf((*MockITagService)(nil).UpdateTag), ctx, userID, tagID, req)
+}
+
+// MockITodoItemService is a mock of ITodoItemService interface.
+type MockITodoItemService struct {
+	ctrl     *gomock.Controller
+	recorder *MockITodoItemServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockITodoItemServiceMockRecorder is the mock recorder for MockITodoItemService.
+type MockITodoItemServiceMockRecorder struct {
+	mock *MockITodoItemService
+}
+
+// NewMockITodoItemService creates a new mock instance.
+func NewMockITodoItemService(ctrl *gomock.Controller) *MockITodoItemService {
+	mock := &MockITodoItemService{ctrl: ctrl}
+	mock.recorder = &MockITodoItemServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockITodoItemService) EXPECT() *MockITodoItemServiceMockRecorder {
+	return m.recorder
+}
+
+// CreateItem mocks base method.
+func (m *MockITodoItemService) CreateItem(ctx context.Context, userID pgtype.UUID, todoID int32, req services.CreateTodoItemRequest) (*db.TodoItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateItem", ctx, userID, todoID, req)
+	ret0, _ := ret[0].(*db.TodoItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateItem indicates an expected call of CreateItem.
+func (mr *MockITodoItemServiceMockRecorder) CreateItem(ctx, userID, todoID, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateItem", reflect.TypeOf((*MockITodoItemService)(nil).CreateItem), ctx, userID, todoID, req)
+}
+
+// DeleteItem mocks base method.
+func (m *MockITodoItemService) DeleteItem(ctx context.Context, userID pgtype.UUID, todoID, itemID int32) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteItem", ctx, userID, todoID, itemID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteItem indicates an expected call of DeleteItem.
+func (mr *MockITodoItemServiceMockRecorder) DeleteItem(ctx, userID, todoID, itemID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteItem", reflect.TypeOf((*MockITodoItemService)(nil).DeleteItem), ctx, userID, todoID, itemID)
+}
+
+// ListItems mocks base method.
+func (m *MockITodoItemService) ListItems(ctx context.Context, userID pgtype.UUID, todoID int32) (*[]db.TodoItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListItems", ctx, userID, todoID)
+	ret0, _ := ret[0].(*[]db.TodoItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListItems indicates an expected call of ListItems.
+func (mr *MockITodoItemServiceMockRecorder) ListItems(ctx, userID, todoID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListItems", reflect.TypeOf((*MockITodoItemService)(nil).ListItems), ctx, userID, todoID)
+}
+
+// UpdateItem mocks base method.
+func (m *MockITodoItemService) UpdateItem(ctx context.Context, userID pgtype.UUID, todoID, itemID int32, req services.UpdateTodoItemRequest) (*db.TodoItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateItem", ctx, userID, todoID, itemID, req)
+	ret0, _ := ret[0].(*db.TodoItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateItem indicates an expected call of UpdateItem.
+func (mr *MockITodoItemServiceMockRecorder) UpdateItem(ctx, userID, todoID, itemID, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateItem", reflect.TypeOf((*MockITodoItemService)(nil).UpdateItem), ctx, userID, todoID, itemID, req)
+}
+
+// UpdateItemPosition mocks base method.
+func (m *MockITodoItemService) UpdateItemPosition(ctx context.Context, userID pgtype.UUID, todoID, itemID int32, req services.UpdateTodoPositionRequest) (*db.TodoItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateItemPosition", ctx, userID, todoID, itemID, req)
+	ret0, _ := ret[0].(*db.TodoItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateItemPosition indicates an expected call of UpdateItemPosition.
+func (mr *MockITodoItemServiceMockRecorder) UpdateItemPosition(ctx, userID, todoID, itemID, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateItemPosition", reflect.TypeOf((*MockITodoItemService)(nil).UpdateItemPosition), ctx, userID, todoID, itemID, req)
 }
@@ -0,0 +1,69 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"log"
+	"time"
+	"todo-app/internal/db"
+	"todo-app/internal/utils"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Hard-deletes the accounts whose deletion grace period has passed
+type AccountPurger struct {
+	SqlClient   db.WrappedQuerier
+	BlobStore   IBlobStore
+	GracePeriod time.Duration
+	BatchSize   int32
+	Interval    time.Duration
+}
+
+type PurgeSummary struct {
+	Purged  int64
+	Batches int
+}
+
+func NewAccountPurger(sqlClient db.WrappedQuerier, blobStore IBlobStore) *AccountPurger {
+	return &AccountPurger{
+		SqlClient:   sqlClient,
+		BlobStore:   blobStore,
+		GracePeriod: AccountDeletionGracePeriodFromEnv(),
+		BatchSize:   int32(envInt("ACCOUNT_PURGE_BATCH_SIZE", 100)),
+		Interval:    time.Duration(envInt("ACCOUNT_PURGE_INTERVAL_MINUTE", 60)) * time.Minute,
+	}
+}
+
+// Deletes in batches until none is left, so that a large backlog is not purged in one long transaction.
+// The summary covers the batches done so far even when an error is returned.
+func (p *AccountPurger) Purge(ctx context.Context) (*PurgeSummary, error) {
+	cutoff := pgtype.Timestamptz{Time: time.Now().Add(-p.GracePeriod), Valid: true}
+	summary := &PurgeSummary{}
+
+	for {
+		userIDs, err := p.SqlClient.PurgeDeletedUsers(ctx, db.PurgeDeletedUsersParams{Cutoff: cutoff, BatchSize: p.BatchSize})
+		if err != nil {
+			return summary, err
+		}
+
+		summary.Batches++
+		summary.Purged += int64(len(userIDs))
+
+		for _, userID := range userIDs {
+			p.deleteAvatar(ctx, userID)
+		}
+
+		if len(userIDs) < int(p.BatchSize) {
+			return summary, nil
+		}
+	}
+}
+
+// The account is already gone and cannot be purged again, so a failure only gets logged instead of stopping the purge
+func (p *AccountPurger) deleteAvatar(ctx context.Context, userID pgtype.UUID) {
+	if err := p.BlobStore.Delete(ctx, avatarKey(userID)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		log.Printf("failed to delete avatar of purged user %s: %v", utils.UUIDToString(userID), err)
+	}
+}
@@ -0,0 +1,132 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"testing"
+	"time"
+	"todo-app/internal/db"
+	mock_db "todo-app/internal/db/_mock"
+	"todo-app/internal/services"
+	mock_services "todo-app/internal/services/_mock"
+	"todo-app/internal/utils"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestAccountPurger(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockQueries := mock_db.NewMockWrappedQuerier(ctrl)
+	mockBlobStore := mock_services.NewMockIBlobStore(ctrl)
+
+	purger := services.NewAccountPurger(mockQueries, mockBlobStore)
+	purger.GracePeriod = 30 * 24 * time.Hour
+	purger.BatchSize = 2
+
+	var userIDs []pgtype.UUID
+	for _, id := range []string{
+		"11111111-1111-1111-1111-111111111111",
+		"22222222-2222-2222-2222-222222222222",
+		"33333333-3333-3333-3333-333333333333",
+	} {
+		userID, _ := utils.StringToUUID(id)
+		userIDs = append(userIDs, userID)
+	}
+
+	t.Run("Purge", func(t *testing.T) {
+		ctx := context.Background()
+		batches := [][]pgtype.UUID{userIDs[:2], userIDs[2:]}
+		var cutoffs []time.Time
+
+		// Stops after the first batch that is not full
+		mockQueries.EXPECT().
+			PurgeDeletedUsers(ctx, gomock.Any()).
+			DoAndReturn(func(ctx context.Context, arg db.PurgeDeletedUsersParams) ([]pgtype.UUID, error) {
+				assert.Equal(t, int32(2), arg.BatchSize)
+				assert.WithinDuration(t, time.Now().AddDate(0, 0, -30), arg.Cutoff.Time, time.Minute)
+				cutoffs = append(cutoffs, arg.Cutoff.Time)
+				return batches[len(cutoffs)-1], nil
+			}).
+			Times(2)
+
+		// The avatar of every purged user goes too
+		for _, userID := range userIDs {
+			mockBlobStore.EXPECT().
+				Delete(ctx, "avatars/"+utils.UUIDToString(userID)).
+				Return(nil)
+		}
+
+		summary, err := purger.Purge(ctx)
+
+		require.NoError(t, err)
+		assert.Equal(t, &services.PurgeSummary{Purged: 3, Batches: 2}, summary)
+		// Every batch uses the same cutoff
+		assert.Equal(t, cutoffs[0], cutoffs[1])
+	})
+
+	t.Run("Purge_NothingToPurge", func(t *testing.T) {
+		ctx := context.Background()
+
+		mockQueries.EXPECT().
+			PurgeDeletedUsers(ctx, gomock.Any()).
+			Return(nil, nil)
+
+		summary, err := purger.Purge(ctx)
+
+		require.NoError(t, err)
+		assert.Equal(t, &services.PurgeSummary{Purged: 0, Batches: 1}, summary)
+	})
+
+	t.Run("Purge_AvatarDeleteFails", func(t *testing.T) {
+		ctx := context.Background()
+
+		mockQueries.EXPECT().
+			PurgeDeletedUsers(ctx, gomock.Any()).
+			Return(userIDs[:2], nil)
+
+		// Users without an avatar and a failing store do not stop the purge
+		mockBlobStore.EXPECT().
+			Delete(ctx, "avatars/"+utils.UUIDToString(userIDs[0])).
+			Return(fs.ErrNotExist)
+		mockBlobStore.EXPECT().
+			Delete(ctx, "avatars/"+utils.UUIDToString(userIDs[1])).
+			Return(errors.New("store unavailable"))
+
+		mockQueries.EXPECT().
+			PurgeDeletedUsers(ctx, gomock.Any()).
+			Return(nil, nil)
+
+		summary, err := purger.Purge(ctx)
+
+		require.NoError(t, err)
+		assert.Equal(t, &services.PurgeSummary{Purged: 2, Batches: 2}, summary)
+	})
+
+	t.Run("Purge_DBError", func(t *testing.T) {
+		ctx := context.Background()
+
+		gomock.InOrder(
+			mockQueries.EXPECT().
+				PurgeDeletedUsers(ctx, gomock.Any()).
+				Return(userIDs[:2], nil),
+			mockQueries.EXPECT().
+				PurgeDeletedUsers(ctx, gomock.Any()).
+				Return(nil, errors.New("delete failed")),
+		)
+		mockBlobStore.EXPECT().
+			Delete(ctx, gomock.Any()).
+			Return(nil).
+			Times(2)
+
+		summary, err := purger.Purge(ctx)
+
+		assert.Error(t, err)
+		assert.Equal(t, int64(2), summary.Purged)
+	})
+}
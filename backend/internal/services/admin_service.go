@@ -0,0 +1,144 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"todo-app/internal/db"
+	"todo-app/internal/utils"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const (
+	RoleUser  = "user"
+	RoleAdmin = "admin"
+)
+
+type AdminService struct {
+	SqlClient         db.WrappedQuerier
+	RefreshTokenStore IRefreshTokenStore
+	SessionStore      ISessionStore
+	Mailer            IMailer
+}
+
+type ListUsersRequest struct {
+	Query   string `form:"q"`
+	Page    int    `form:"page,default=1" binding:"min=1,max=1000000"`
+	PerPage int    `form:"per_page,default=20" binding:"min=1,max=100"`
+}
+
+type UserList struct {
+	Users []db.ListUsersRow
+	Total int64
+}
+
+type UserDetail struct {
+	User       db.User
+	TodoCounts db.CountTodosRow
+}
+
+func NewAdminService(sqlClient db.WrappedQuerier, refreshTokenStore IRefreshTokenStore, sessionStore ISessionStore, mailer IMailer) *AdminService {
+	return &AdminService{SqlClient: sqlClient, RefreshTokenStore: refreshTokenStore, SessionStore: sessionStore, Mailer: mailer}
+}
+
+// Lists the users whose email or username contains the query (all users if empty), oldest first
+func (s *AdminService) ListUsers(ctx context.Context, req ListUsersRequest) (*UserList, error) {
+	pattern := db.ContainsPattern(req.Query)
+
+	total, err := s.SqlClient.CountUsers(ctx, pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	users, err := s.SqlClient.ListUsers(ctx, db.ListUsersParams{
+		Pattern:   pattern,
+		RowLimit:  int32(req.PerPage),
+		RowOffset: int32((req.Page - 1) * req.PerPage),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &UserList{Users: users, Total: total}, nil
+}
+
+func (s *AdminService) GetUser(ctx context.Context, userID pgtype.UUID) (*UserDetail, error) {
+	user, err := s.getUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	todoCounts, err := s.SqlClient.CountTodos(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &UserDetail{User: *user, TodoCounts: todoCounts}, nil
+}
+
+// Disabled users cannot log in, and their sessions are revoked so that they are signed out right away
+func (s *AdminService) DisableUser(ctx context.Context, adminID, userID pgtype.UUID) error {
+	// Otherwise the last admin could lock everyone out of the admin API
+	if adminID == userID {
+		return utils.ErrCannotModifySelf
+	}
+
+	rows, err := s.SqlClient.DisableUser(ctx, userID)
+	if err != nil {
+		return err
+	} else if rows == 0 {
+		return utils.ErrNoRowsMatchedSQLC
+	}
+
+	return revokeSessions(ctx, s.RefreshTokenStore, s.SessionStore, utils.UUIDToString(userID), "")
+}
+
+func (s *AdminService) EnableUser(ctx context.Context, userID pgtype.UUID) error {
+	rows, err := s.SqlClient.EnableUser(ctx, userID)
+	if err != nil {
+		return err
+	} else if rows == 0 {
+		return utils.ErrNoRowsMatchedSQLC
+	}
+
+	return nil
+}
+
+// Clears the password, signs out every session and emails a reset link, so that the user has to choose a new password
+func (s *AdminService) ForcePasswordReset(ctx context.Context, userID pgtype.UUID) error {
+	user, err := s.getUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	// An empty hash never matches any password
+	err = s.SqlClient.UpdatePasswordHash(ctx, db.UpdatePasswordHashParams{
+		PasswordHash: []byte{},
+		UserID:       user.UserID,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err = revokeSessions(ctx, s.RefreshTokenStore, s.SessionStore, utils.UUIDToString(user.UserID), ""); err != nil {
+		return err
+	}
+
+	return sendPasswordReset(ctx, s.SqlClient, s.Mailer, *user,
+		"An administrator has reset the password of your account.",
+		"You cannot log in with your previous password anymore.",
+	)
+}
+
+func (s *AdminService) getUser(ctx context.Context, userID pgtype.UUID) (*db.User, error) {
+	user, err := s.SqlClient.GetUserByUserID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, utils.ErrNoRowsMatchedSQLC
+		}
+		return nil, err
+	}
+
+	return &user, nil
+}
@@ -0,0 +1,190 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"todo-app/internal/db"
+	mock_db "todo-app/internal/db/_mock"
+	"todo-app/internal/mailer"
+	"todo-app/internal/services"
+	mock_services "todo-app/internal/services/_mock"
+	"todo-app/internal/utils"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestAdminService(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockQueries := mock_db.NewMockWrappedQuerier(ctrl)
+	mockRefreshTokenStore := mock_services.NewMockIRefreshTokenStore(ctrl)
+	mockSessionStore := mock_services.NewMockISessionStore(ctrl)
+	mockMailer := mock_services.NewMockIMailer(ctrl)
+
+	adminService := services.NewAdminService(mockQueries, mockRefreshTokenStore, mockSessionStore, mockMailer)
+
+	adminIDUuid, _ := utils.StringToUUID("0f0e0d0c-0b0a-0908-0706-050403020100")
+	uIDStr := "00010203-0405-0607-0809-0a0b0c0d0e0f"
+	uIDUuid, _ := utils.StringToUUID(uIDStr)
+	user := db.User{ID: 1, UserID: uIDUuid, Email: "test@example.com", PasswordHash: []byte("hashedpassword"), Role: services.RoleUser}
+	sessionID := "session-id-123"
+
+	t.Setenv("PASSWORD_RESET_TOKEN_EXP_MINUTE", "30")
+	t.Setenv("FRONTEND_URL", "http://localhost:3000")
+
+	t.Run("ListUsers", func(t *testing.T) {
+		ctx := context.Background()
+
+		mockQueries.EXPECT().
+			CountUsers(ctx, `%50\%\_off%`).
+			Return(int64(45), nil)
+
+		mockQueries.EXPECT().
+			ListUsers(ctx, db.ListUsersParams{Pattern: `%50\%\_off%`, RowLimit: 20, RowOffset: 40}).
+			Return([]db.ListUsersRow{{UserID: uIDUuid, Email: user.Email, TodoCount: 3, CompletedTodoCount: 1}}, nil)
+
+		userList, err := adminService.ListUsers(ctx, services.ListUsersRequest{Query: "50%_off", Page: 3, PerPage: 20})
+
+		require.NoError(t, err)
+		assert.Equal(t, int64(45), userList.Total)
+		assert.Len(t, userList.Users, 1)
+	})
+
+	t.Run("GetUser", func(t *testing.T) {
+		ctx := context.Background()
+
+		mockQueries.EXPECT().
+			GetUserByUserID(ctx, uIDUuid).
+			Return(user, nil)
+
+		mockQueries.EXPECT().
+			CountTodos(ctx, user.ID).
+			Return(db.CountTodosRow{Total: 3, Completed: 1}, nil)
+
+		userDetail, err := adminService.GetUser(ctx, uIDUuid)
+
+		require.NoError(t, err)
+		assert.Equal(t, user.Email, userDetail.User.Email)
+		assert.Equal(t, db.CountTodosRow{Total: 3, Completed: 1}, userDetail.TodoCounts)
+	})
+
+	t.Run("GetUser_NotFound", func(t *testing.T) {
+		ctx := context.Background()
+
+		mockQueries.EXPECT().
+			GetUserByUserID(ctx, uIDUuid).
+			Return(db.User{}, pgx.ErrNoRows)
+
+		userDetail, err := adminService.GetUser(ctx, uIDUuid)
+
+		assert.Equal(t, utils.ErrNoRowsMatchedSQLC, err)
+		assert.Nil(t, userDetail)
+	})
+
+	t.Run("DisableUser", func(t *testing.T) {
+		ctx := context.Background()
+
+		mockQueries.EXPECT().
+			DisableUser(ctx, uIDUuid).
+			Return(int64(1), nil)
+
+		mockSessionStore.EXPECT().
+			List(ctx, uIDStr).
+			Return([]db.Session{{ID: sessionID, UserID: uIDStr}}, nil)
+
+		mockRefreshTokenStore.EXPECT().
+			RevokeFamily(ctx, sessionID).
+			Return(nil)
+
+		mockSessionStore.EXPECT().
+			Delete(ctx, uIDStr, sessionID).
+			Return(nil)
+
+		err := adminService.DisableUser(ctx, adminIDUuid, uIDUuid)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("DisableUser_Self", func(t *testing.T) {
+		ctx := context.Background()
+
+		err := adminService.DisableUser(ctx, adminIDUuid, adminIDUuid)
+
+		assert.Equal(t, utils.ErrCannotModifySelf, err)
+	})
+
+	t.Run("DisableUser_NotFound", func(t *testing.T) {
+		ctx := context.Background()
+
+		mockQueries.EXPECT().
+			DisableUser(ctx, uIDUuid).
+			Return(int64(0), nil)
+
+		err := adminService.DisableUser(ctx, adminIDUuid, uIDUuid)
+
+		assert.Equal(t, utils.ErrNoRowsMatchedSQLC, err)
+	})
+
+	t.Run("EnableUser", func(t *testing.T) {
+		ctx := context.Background()
+
+		mockQueries.EXPECT().
+			EnableUser(ctx, uIDUuid).
+			Return(int64(1), nil)
+
+		err := adminService.EnableUser(ctx, uIDUuid)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("ForcePasswordReset", func(t *testing.T) {
+		ctx := context.Background()
+
+		mockQueries.EXPECT().
+			GetUserByUserID(ctx, uIDUuid).
+			Return(user, nil)
+
+		mockQueries.EXPECT().
+			UpdatePasswordHash(ctx, db.UpdatePasswordHashParams{PasswordHash: []byte{}, UserID: uIDUuid}).
+			Return(nil)
+
+		mockSessionStore.EXPECT().
+			List(ctx, uIDStr).
+			Return([]db.Session{}, nil)
+
+		mockQueries.EXPECT().
+			ExecTx(ctx, gomock.Any()).
+			DoAndReturn(func(ctx context.Context, fn func(q db.WrappedQuerier) error) error {
+				return fn(mockQueries)
+			})
+
+		mockQueries.EXPECT().
+			LockUser(ctx, user.ID).
+			Return(nil)
+
+		mockQueries.EXPECT().
+			InvalidatePasswordResetTokens(ctx, user.ID).
+			Return(nil)
+
+		mockQueries.EXPECT().
+			CreatePasswordResetToken(ctx, gomock.Any()).
+			Return(db.PasswordResetToken{}, nil)
+
+		mockMailer.EXPECT().
+			Send(ctx, gomock.Any()).
+			DoAndReturn(func(ctx context.Context, msg mailer.Message) error {
+				assert.Equal(t, user.Email, msg.To)
+				assert.Contains(t, msg.Body, "An administrator has reset the password of your account.")
+				assert.Contains(t, msg.Body, "http://localhost:3000/reset-password?token=")
+				return nil
+			})
+
+		err := adminService.ForcePasswordReset(ctx, uIDUuid)
+
+		assert.NoError(t, err)
+	})
+}
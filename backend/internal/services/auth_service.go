@@ -3,16 +3,25 @@ package services
 import (
 	"context"
 	"errors"
+	"log"
+	"time"
 	"todo-app/internal/db"
 	"todo-app/internal/utils"
 
-	"golang.org/x/crypto/bcrypt"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 type AuthService struct {
-	SqlClient      db.WrappedQuerier
-	PasswordHasher IPasswordHasher
-	TokenGenerator ITokenGenerator
+	SqlClient         db.WrappedQuerier
+	PasswordHasher    IPasswordHasher
+	TokenGenerator    ITokenGenerator
+	RefreshTokenStore IRefreshTokenStore
+	SessionStore      ISessionStore
+	LoginThrottle     *LoginThrottle
+	PasswordPolicy    *PasswordPolicy
+	Mailer            IMailer
+	// How long logging in still restores an account whose deletion was requested
+	DeletionGracePeriod time.Duration
 }
 
 type RegisterRequest struct {
@@ -25,12 +34,45 @@ type LoginRequest struct {
 	Password string `json:"password" binding:"required"`
 }
 
-func NewAuthService(sqlClient db.WrappedQuerier, passHasher IPasswordHasher, jwter ITokenGenerator) *AuthService {
-	return &AuthService{SqlClient: sqlClient, PasswordHasher: passHasher, TokenGenerator: jwter}
+type TwoFactorLoginRequest struct {
+	ChallengeToken string `json:"challenge_token" binding:"required"`
+	// Either a code from the authenticator app or one of the recovery codes
+	Code string `json:"code" binding:"required"`
+}
+
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+type TokenPair struct {
+	AccessToken  string
+	RefreshToken string
+}
+
+// Returned by Login instead of the tokens when the user has enabled two-factor authentication
+// The login is completed by LoginTwoFactor with the challenge token and a code
+type TwoFactorRequiredError struct {
+	ChallengeToken string
+}
+
+func (e *TwoFactorRequiredError) Error() string {
+	return utils.ErrTwoFactorRequired.Error()
+}
+
+func (e *TwoFactorRequiredError) Unwrap() error {
+	return utils.ErrTwoFactorRequired
+}
+
+func NewAuthService(sqlClient db.WrappedQuerier, passHasher IPasswordHasher, jwter ITokenGenerator, refreshTokenStore IRefreshTokenStore, sessionStore ISessionStore, loginThrottle *LoginThrottle, passwordPolicy *PasswordPolicy, mailer IMailer) *AuthService {
+	return &AuthService{SqlClient: sqlClient, PasswordHasher: passHasher, TokenGenerator: jwter, RefreshTokenStore: refreshTokenStore, SessionStore: sessionStore, LoginThrottle: loginThrottle, PasswordPolicy: passwordPolicy, Mailer: mailer, DeletionGracePeriod: AccountDeletionGracePeriodFromEnv()}
 }
 
 func (s *AuthService) Register(ctx context.Context, req RegisterRequest) (*db.User, error) {
-	hashedPassword, err := s.PasswordHasher.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+	if err := s.PasswordPolicy.Validate(req.Password); err != nil {
+		return nil, err
+	}
+
+	hashedPassword, err := s.PasswordHasher.GenerateFromPassword([]byte(req.Password))
 	if err != nil {
 		return nil, err
 	}
@@ -43,28 +85,254 @@ func (s *AuthService) Register(ctx context.Context, req RegisterRequest) (*db.Us
 		return nil, err
 	}
 
+	// The account already exists at this point, and the email can be sent again from the resend endpoint
+	if err = sendVerificationEmail(ctx, s.TokenGenerator, s.Mailer, user); err != nil {
+		log.Println(err.Error())
+	}
+
 	return &user, nil
 }
 
-func (s *AuthService) Login(ctx context.Context, req LoginRequest, sessionID string) (string, string, error) {
+func (s *AuthService) Login(ctx context.Context, req LoginRequest, sessionID string, client ClientInfo) (string, *TokenPair, error) {
+	if err := s.LoginThrottle.Check(ctx, req.Email, client.IP); err != nil {
+		return "", nil, err
+	}
+
 	user, err := s.SqlClient.GetUserByEmail(ctx, req.Email)
 	if err != nil {
-		return "", "", utils.ErrInvalidEmailOrPswd
+		return "", nil, s.loginFailed(ctx, req.Email, client.IP)
 	}
 
 	if err = s.PasswordHasher.CompareHashAndPassword([]byte(user.PasswordHash), []byte(req.Password)); err != nil {
-		return "", "", utils.ErrInvalidEmailOrPswd
+		return "", nil, s.loginFailed(ctx, req.Email, client.IP)
+	}
+
+	// Only reported once the password is right, so that it does not reveal which accounts exist
+	if user.DisabledAt.Valid {
+		return "", nil, utils.ErrAccountDisabled
+	}
+
+	if s.PasswordHasher.NeedsRehash(user.PasswordHash) {
+		s.rehashPassword(ctx, user, req.Password)
 	}
 
 	userIDStr := utils.UUIDToString(user.UserID)
 	if userIDStr == "" {
-		return "", "", errors.New("failed to convert uuid to string")
+		return "", nil, errors.New("failed to convert uuid to string")
+	}
+
+	// The failure counters are kept until the second factor succeeds as well, so that codes cannot be guessed endlessly
+	if user.TotpEnabledAt.Valid {
+		challengeToken, err := s.TokenGenerator.GenerateTwoFactorChallengeToken(userIDStr, sessionID)
+		if err != nil {
+			return "", nil, err
+		}
+		return "", nil, &TwoFactorRequiredError{ChallengeToken: challengeToken}
+	}
+
+	if err = restoreOnLogin(ctx, s.SqlClient, user, s.DeletionGracePeriod); err != nil {
+		return "", nil, err
+	}
+
+	if err = s.LoginThrottle.Reset(ctx, req.Email); err != nil {
+		return "", nil, err
+	}
+
+	return issueTokens(ctx, s.TokenGenerator, s.RefreshTokenStore, s.SessionStore, userIDStr, sessionID, client)
+}
+
+// Completes a login of a user with two-factor authentication enabled
+// Wrong codes count as failed logins of the user, so the same lockout applies as for wrong passwords
+func (s *AuthService) LoginTwoFactor(ctx context.Context, req TwoFactorLoginRequest, sessionID string, client ClientInfo) (string, *TokenPair, error) {
+	claims, err := s.TokenGenerator.ValidateTwoFactorChallengeToken(req.ChallengeToken)
+	if err != nil || claims.TokenType != TokenTypeTwoFactorChallenge || claims.SessionID != sessionID {
+		return "", nil, utils.ErrInvalidChallengeToken
+	}
+
+	userID, err := utils.StringToUUID(claims.UserID)
+	if err != nil {
+		return "", nil, utils.ErrInvalidChallengeToken
+	}
+
+	user, err := s.SqlClient.GetUserByUserID(ctx, userID)
+	if err != nil || !user.TotpEnabledAt.Valid {
+		return "", nil, utils.ErrInvalidChallengeToken
+	}
+
+	if user.DisabledAt.Valid {
+		return "", nil, utils.ErrAccountDisabled
+	}
+
+	if err = s.LoginThrottle.Check(ctx, user.Email, client.IP); err != nil {
+		return "", nil, err
+	}
+
+	ok, err := s.verifyTwoFactorCode(ctx, user, req.Code)
+	if err != nil {
+		return "", nil, err
+	}
+	if !ok {
+		if err = s.LoginThrottle.RecordFailure(ctx, user.Email, client.IP); err != nil {
+			return "", nil, err
+		}
+		return "", nil, utils.ErrInvalidTwoFactorCode
+	}
+
+	if err = restoreOnLogin(ctx, s.SqlClient, user, s.DeletionGracePeriod); err != nil {
+		return "", nil, err
+	}
+
+	if err = s.LoginThrottle.Reset(ctx, user.Email); err != nil {
+		return "", nil, err
+	}
+
+	return issueTokens(ctx, s.TokenGenerator, s.RefreshTokenStore, s.SessionStore, claims.UserID, sessionID, client)
+}
+
+// A TOTP code is accepted only once, and a recovery code is removed once it has been used
+func (s *AuthService) verifyTwoFactorCode(ctx context.Context, user db.User, code string) (bool, error) {
+	if step, ok := ValidateTOTPCode(user.TotpSecret.String, code, time.Now()); ok {
+		rows, err := s.SqlClient.UpdateTOTPLastUsedStep(ctx, db.UpdateTOTPLastUsedStepParams{
+			TotpLastUsedStep: pgtype.Int8{Int64: step, Valid: true},
+			UserID:           user.UserID,
+		})
+		if err != nil {
+			return false, err
+		}
+		return rows == 1, nil
 	}
 
-	accessToken, err := s.TokenGenerator.GenerateToken(userIDStr, sessionID)
+	rows, err := s.SqlClient.UseTOTPRecoveryCode(ctx, db.UseTOTPRecoveryCodeParams{
+		CodeHash: utils.HashToken(NormalizeRecoveryCode(code)),
+		UserID:   user.UserID,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return rows == 1, nil
+}
+
+// Shared by every way of logging in, so that they all end up with the same session and tokens
+func issueTokens(ctx context.Context, tokenGenerator ITokenGenerator, refreshTokenStore IRefreshTokenStore, sessionStore ISessionStore, userIDStr, sessionID string, client ClientInfo) (string, *TokenPair, error) {
+	accessToken, err := tokenGenerator.GenerateToken(userIDStr, sessionID)
+	if err != nil {
+		return "", nil, err
+	}
+
+	// A login starts a new refresh token family bound to the session
+	refreshToken, expiresAt, err := tokenGenerator.GenerateRefreshToken()
+	if err != nil {
+		return "", nil, err
+	}
+
+	err = refreshTokenStore.Save(ctx, utils.HashToken(refreshToken), db.RefreshToken{
+		UserID:    userIDStr,
+		SessionID: sessionID,
+		ExpiresAt: expiresAt,
+	})
+	if err != nil {
+		return "", nil, err
+	}
+
+	now := time.Now()
+	err = sessionStore.Create(ctx, db.Session{
+		ID:         sessionID,
+		UserID:     userIDStr,
+		UserAgent:  client.UserAgent,
+		IP:         client.IP,
+		CreatedAt:  now,
+		LastSeenAt: now,
+	}, expiresAt)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return userIDStr, &TokenPair{AccessToken: accessToken, RefreshToken: refreshToken}, nil
+}
+
+// Upgrades a hash created by an older algorithm or with older parameters while the plain password is at hand
+// A failure only postpones the upgrade to the next login, so it does not fail the login itself
+func (s *AuthService) rehashPassword(ctx context.Context, user db.User, password string) {
+	hashedPassword, err := s.PasswordHasher.GenerateFromPassword([]byte(password))
+	if err != nil {
+		log.Println(err.Error())
+		return
+	}
+
+	err = s.SqlClient.UpdatePasswordHash(ctx, db.UpdatePasswordHashParams{
+		PasswordHash: hashedPassword,
+		UserID:       user.UserID,
+	})
+	if err != nil {
+		log.Println(err.Error())
+	}
+}
+
+// Unknown emails count as failures as well so that the lockout does not reveal which emails are registered
+func (s *AuthService) loginFailed(ctx context.Context, email, ip string) error {
+	if err := s.LoginThrottle.RecordFailure(ctx, email, ip); err != nil {
+		return err
+	}
+
+	return utils.ErrInvalidEmailOrPswd
+}
+
+// Exchanges a refresh token for a new access token and rotates the refresh token itself
+// Presenting an already rotated refresh token is treated as token theft and revokes the whole session
+func (s *AuthService) RefreshToken(ctx context.Context, req RefreshTokenRequest, sessionID string) (string, *TokenPair, error) {
+	tokenHash := utils.HashToken(req.RefreshToken)
+
+	stored, err := s.RefreshTokenStore.Get(ctx, tokenHash)
 	if err != nil {
-		return "", "", err
+		if err == db.ErrRefreshTokenNotFound {
+			return "", nil, utils.ErrInvalidRefreshToken
+		}
+		return "", nil, err
+	}
+
+	if stored.SessionID != sessionID {
+		return "", nil, utils.ErrInvalidRefreshToken
 	}
 
-	return userIDStr, accessToken, nil
+	firstUse, err := s.RefreshTokenStore.MarkUsed(ctx, tokenHash, stored.ExpiresAt)
+	if err != nil {
+		if err == db.ErrRefreshTokenNotFound {
+			return "", nil, utils.ErrInvalidRefreshToken
+		}
+		return "", nil, err
+	}
+
+	if !firstUse {
+		if err := revokeSession(ctx, s.RefreshTokenStore, s.SessionStore, stored.UserID, stored.SessionID); err != nil {
+			return "", nil, err
+		}
+		return "", nil, utils.ErrRefreshTokenReused
+	}
+
+	accessToken, err := s.TokenGenerator.GenerateToken(stored.UserID, stored.SessionID)
+	if err != nil {
+		return "", nil, err
+	}
+
+	// The rotated token inherits the expiry so that the family cannot outlive the session
+	refreshToken, _, err := s.TokenGenerator.GenerateRefreshToken()
+	if err != nil {
+		return "", nil, err
+	}
+
+	err = s.RefreshTokenStore.Save(ctx, utils.HashToken(refreshToken), db.RefreshToken{
+		UserID:    stored.UserID,
+		SessionID: stored.SessionID,
+		ExpiresAt: stored.ExpiresAt,
+	})
+	if err != nil {
+		return "", nil, err
+	}
+
+	return stored.UserID, &TokenPair{AccessToken: accessToken, RefreshToken: refreshToken}, nil
+}
+
+func (s *AuthService) Logout(ctx context.Context, userID, sessionID string) error {
+	return revokeSession(ctx, s.RefreshTokenStore, s.SessionStore, userID, sessionID)
 }
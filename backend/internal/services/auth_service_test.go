@@ -3,17 +3,20 @@ package services_test
 import (
 	"context"
 	"errors"
+	"fmt"
 	"testing"
+	"time"
 	"todo-app/internal/db"
 	mock_db "todo-app/internal/db/_mock"
+	"todo-app/internal/mailer"
 	"todo-app/internal/services"
 	mock_services "todo-app/internal/services/_mock"
 	"todo-app/internal/utils"
 
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	gomock "go.uber.org/mock/gomock"
-	"golang.org/x/crypto/bcrypt"
 )
 
 func TestAuthService(t *testing.T) {
@@ -23,15 +26,21 @@ func TestAuthService(t *testing.T) {
 	mockQueries := mock_db.NewMockWrappedQuerier(ctrl)
 	mockPassHasher := mock_services.NewMockIPasswordHasher(ctrl)
 	mockTokenGen := mock_services.NewMockITokenGenerator(ctrl)
+	mockRefreshTokenStore := mock_services.NewMockIRefreshTokenStore(ctrl)
+	mockSessionStore := mock_services.NewMockISessionStore(ctrl)
+	mockMailer := mock_services.NewMockIMailer(ctrl)
+	loginThrottle := services.NewLoginThrottle(db.NewMemoryLoginAttemptStore())
+	passwordPolicy := services.NewPasswordPolicy(8, 128, []string{services.PasswordCharClassLower, services.PasswordCharClassDigit}, 1000)
 
-	authService := services.NewAuthService(mockQueries, mockPassHasher, mockTokenGen)
+	authService := services.NewAuthService(mockQueries, mockPassHasher, mockTokenGen, mockRefreshTokenStore, mockSessionStore, loginThrottle, passwordPolicy, mockMailer)
 
 	uIDStr := "00010203-0405-0607-0809-0a0b0c0d0e0f"
 	uIDUuid, _ := utils.StringToUUID(uIDStr)
 	sessionID := "session-id-123"
+	client := services.ClientInfo{UserAgent: "test-agent", IP: "192.0.2.1"}
 	passwordCases := map[string]map[string]string{
 		"correct": {
-			"plain":  "password123",
+			"plain":  "correct-horse-42",
 			"hashed": "hashedpassword123",
 		},
 		"wrong": {
@@ -40,6 +49,9 @@ func TestAuthService(t *testing.T) {
 		},
 	}
 	token := "access-token-123"
+	refreshToken := "refresh-token-123"
+	newRefreshToken := "refresh-token-456"
+	refreshTokenExp := time.Now().Add(time.Hour)
 
 	t.Run("Register", func(t *testing.T) {
 		ctx := context.Background()
@@ -51,7 +63,7 @@ func TestAuthService(t *testing.T) {
 		}
 
 		mockPassHasher.EXPECT().
-			GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost).
+			GenerateFromPassword([]byte(req.Password)).
 			Return([]byte(hashedPassword), nil)
 
 		mockQueries.EXPECT().
@@ -61,6 +73,18 @@ func TestAuthService(t *testing.T) {
 			}).
 			Return(db.User{UserID: uIDUuid, Email: req.Email, PasswordHash: []byte(hashedPassword)}, nil)
 
+		mockTokenGen.EXPECT().
+			GenerateEmailVerificationToken(uIDStr, req.Email).
+			Return("verification-token-123", nil)
+
+		mockMailer.EXPECT().
+			Send(ctx, gomock.Any()).
+			DoAndReturn(func(ctx context.Context, msg mailer.Message) error {
+				assert.Equal(t, req.Email, msg.To)
+				assert.Contains(t, msg.Body, "/api/v1/verify-email?token=verification-token-123")
+				return nil
+			})
+
 		user, err := authService.Register(ctx, req)
 
 		require.NoError(t, err)
@@ -68,6 +92,22 @@ func TestAuthService(t *testing.T) {
 		assert.Equal(t, uIDUuid, user.UserID)
 	})
 
+	t.Run("Register_WeakPassword", func(t *testing.T) {
+		ctx := context.Background()
+		req := services.RegisterRequest{
+			Email:    "test@example.com",
+			Password: "password",
+		}
+
+		user, err := authService.Register(ctx, req)
+
+		var policyErr *services.PasswordPolicyError
+		require.ErrorAs(t, err, &policyErr)
+		assert.ErrorIs(t, err, utils.ErrWeakPassword)
+		assert.Equal(t, []string{services.PasswordViolationCharClass, services.PasswordViolationCommon}, violationCodes(policyErr))
+		assert.Nil(t, user)
+	})
+
 	t.Run("Login", func(t *testing.T) {
 		ctx := context.Background()
 		plainPassword := passwordCases["correct"]["plain"]
@@ -85,15 +125,98 @@ func TestAuthService(t *testing.T) {
 			CompareHashAndPassword([]byte(hashedPassword), []byte(req.Password)).
 			Return(nil)
 
+		mockPassHasher.EXPECT().
+			NeedsRehash([]byte(hashedPassword)).
+			Return(false)
+
+		mockTokenGen.EXPECT().
+			GenerateToken(uIDStr, sessionID).
+			Return(token, nil)
+
+		mockTokenGen.EXPECT().
+			GenerateRefreshToken().
+			Return(refreshToken, refreshTokenExp, nil)
+
+		mockRefreshTokenStore.EXPECT().
+			Save(ctx, utils.HashToken(refreshToken), db.RefreshToken{
+				UserID:    uIDStr,
+				SessionID: sessionID,
+				ExpiresAt: refreshTokenExp,
+			}).
+			Return(nil)
+
+		mockSessionStore.EXPECT().
+			Create(ctx, gomock.Any(), refreshTokenExp).
+			DoAndReturn(func(ctx context.Context, session db.Session, expiresAt time.Time) error {
+				assert.Equal(t, sessionID, session.ID)
+				assert.Equal(t, uIDStr, session.UserID)
+				assert.Equal(t, client.UserAgent, session.UserAgent)
+				assert.Equal(t, client.IP, session.IP)
+				return nil
+			})
+
+		userID, tokens, err := authService.Login(ctx, req, sessionID, client)
+
+		require.NoError(t, err)
+		assert.Equal(t, uIDStr, userID)
+		assert.Equal(t, token, tokens.AccessToken)
+		assert.Equal(t, refreshToken, tokens.RefreshToken)
+	})
+
+	t.Run("Login_RehashesPassword", func(t *testing.T) {
+		ctx := context.Background()
+		plainPassword := passwordCases["correct"]["plain"]
+		legacyHashedPassword := "$2a$10$legacybcrypthash"
+		upgradedHashedPassword := "$argon2id$v=19$m=19456,t=2,p=1$salt$hash"
+		req := services.LoginRequest{
+			Email:    "test@example.com",
+			Password: plainPassword,
+		}
+
+		mockQueries.EXPECT().
+			GetUserByEmail(ctx, req.Email).
+			Return(db.User{UserID: uIDUuid, PasswordHash: []byte(legacyHashedPassword)}, nil)
+
+		mockPassHasher.EXPECT().
+			CompareHashAndPassword([]byte(legacyHashedPassword), []byte(req.Password)).
+			Return(nil)
+
+		mockPassHasher.EXPECT().
+			NeedsRehash([]byte(legacyHashedPassword)).
+			Return(true)
+
+		mockPassHasher.EXPECT().
+			GenerateFromPassword([]byte(req.Password)).
+			Return([]byte(upgradedHashedPassword), nil)
+
+		mockQueries.EXPECT().
+			UpdatePasswordHash(ctx, db.UpdatePasswordHashParams{
+				PasswordHash: []byte(upgradedHashedPassword),
+				UserID:       uIDUuid,
+			}).
+			Return(nil)
+
 		mockTokenGen.EXPECT().
 			GenerateToken(uIDStr, sessionID).
 			Return(token, nil)
 
-		userID, accessToken, err := authService.Login(ctx, req, sessionID)
+		mockTokenGen.EXPECT().
+			GenerateRefreshToken().
+			Return(refreshToken, refreshTokenExp, nil)
+
+		mockRefreshTokenStore.EXPECT().
+			Save(ctx, utils.HashToken(refreshToken), gomock.Any()).
+			Return(nil)
+
+		mockSessionStore.EXPECT().
+			Create(ctx, gomock.Any(), refreshTokenExp).
+			Return(nil)
+
+		userID, tokens, err := authService.Login(ctx, req, sessionID, client)
 
 		require.NoError(t, err)
 		assert.Equal(t, uIDStr, userID)
-		assert.Equal(t, token, accessToken)
+		assert.NotNil(t, tokens)
 	})
 
 	t.Run("Login_InvalidEmail", func(t *testing.T) {
@@ -108,11 +231,11 @@ func TestAuthService(t *testing.T) {
 			GetUserByEmail(ctx, req.Email).
 			Return(db.User{}, errors.New("user not found"))
 
-		userID, accessToken, err := authService.Login(ctx, req, sessionID)
+		userID, tokens, err := authService.Login(ctx, req, sessionID, client)
 
 		assert.Error(t, err)
 		assert.Equal(t, "", userID)
-		assert.Equal(t, "", accessToken)
+		assert.Nil(t, tokens)
 	})
 
 	t.Run("Login_PasswordMismatch", func(t *testing.T) {
@@ -132,10 +255,575 @@ func TestAuthService(t *testing.T) {
 			CompareHashAndPassword([]byte(correctHashedPassword), []byte(req.Password)).
 			Return(errors.New("invalid password"))
 
-		userID, accessToken, err := authService.Login(ctx, req, sessionID)
+		userID, tokens, err := authService.Login(ctx, req, sessionID, client)
 
 		assert.Error(t, err)
 		assert.Equal(t, "", userID)
-		assert.Equal(t, "", accessToken)
+		assert.Nil(t, tokens)
+	})
+
+	t.Run("Login_AccountDisabled", func(t *testing.T) {
+		ctx := context.Background()
+		hashedPassword := passwordCases["correct"]["hashed"]
+		req := services.LoginRequest{
+			Email:    "disabled@example.com",
+			Password: passwordCases["correct"]["plain"],
+		}
+
+		mockQueries.EXPECT().
+			GetUserByEmail(ctx, req.Email).
+			Return(db.User{UserID: uIDUuid, PasswordHash: []byte(hashedPassword), DisabledAt: pgtype.Timestamptz{Time: time.Now(), Valid: true}}, nil)
+
+		mockPassHasher.EXPECT().
+			CompareHashAndPassword([]byte(hashedPassword), []byte(req.Password)).
+			Return(nil)
+
+		userID, tokens, err := authService.Login(ctx, req, sessionID, client)
+
+		assert.Equal(t, utils.ErrAccountDisabled, err)
+		assert.Empty(t, userID)
+		assert.Nil(t, tokens)
+	})
+
+	t.Run("Login_RestoresPendingDeletion", func(t *testing.T) {
+		ctx := context.Background()
+		hashedPassword := passwordCases["correct"]["hashed"]
+		req := services.LoginRequest{
+			Email:    "leaving@example.com",
+			Password: passwordCases["correct"]["plain"],
+		}
+		requestedAt := pgtype.Timestamptz{Time: time.Now().Add(-24 * time.Hour), Valid: true}
+
+		mockQueries.EXPECT().
+			GetUserByEmail(ctx, req.Email).
+			Return(db.User{UserID: uIDUuid, PasswordHash: []byte(hashedPassword), DeletionRequestedAt: requestedAt}, nil)
+
+		mockPassHasher.EXPECT().
+			CompareHashAndPassword([]byte(hashedPassword), []byte(req.Password)).
+			Return(nil)
+
+		mockPassHasher.EXPECT().
+			NeedsRehash([]byte(hashedPassword)).
+			Return(false)
+
+		// Only within the grace period
+		mockQueries.EXPECT().
+			RestoreUser(ctx, gomock.Any()).
+			DoAndReturn(func(ctx context.Context, arg db.RestoreUserParams) (int64, error) {
+				assert.Equal(t, uIDUuid, arg.UserID)
+				assert.WithinDuration(t, time.Now().Add(-30*24*time.Hour), arg.Cutoff.Time, time.Minute)
+				return 1, nil
+			})
+
+		mockTokenGen.EXPECT().
+			GenerateToken(uIDStr, sessionID).
+			Return(token, nil)
+
+		mockTokenGen.EXPECT().
+			GenerateRefreshToken().
+			Return(refreshToken, refreshTokenExp, nil)
+
+		mockRefreshTokenStore.EXPECT().
+			Save(ctx, utils.HashToken(refreshToken), gomock.Any()).
+			Return(nil)
+
+		mockSessionStore.EXPECT().
+			Create(ctx, gomock.Any(), refreshTokenExp).
+			Return(nil)
+
+		userID, tokens, err := authService.Login(ctx, req, sessionID, client)
+
+		require.NoError(t, err)
+		assert.Equal(t, uIDStr, userID)
+		assert.Equal(t, token, tokens.AccessToken)
+	})
+
+	t.Run("Login_DeletedAfterGracePeriod", func(t *testing.T) {
+		ctx := context.Background()
+		hashedPassword := passwordCases["correct"]["hashed"]
+		req := services.LoginRequest{
+			Email:    "left@example.com",
+			Password: passwordCases["correct"]["plain"],
+		}
+		requestedAt := pgtype.Timestamptz{Time: time.Now().Add(-31 * 24 * time.Hour), Valid: true}
+
+		mockQueries.EXPECT().
+			GetUserByEmail(ctx, req.Email).
+			Return(db.User{UserID: uIDUuid, PasswordHash: []byte(hashedPassword), DeletionRequestedAt: requestedAt}, nil)
+
+		mockPassHasher.EXPECT().
+			CompareHashAndPassword([]byte(hashedPassword), []byte(req.Password)).
+			Return(nil)
+
+		mockPassHasher.EXPECT().
+			NeedsRehash([]byte(hashedPassword)).
+			Return(false)
+
+		// Waiting for the purger, so no session is issued
+		mockQueries.EXPECT().
+			RestoreUser(ctx, gomock.Any()).
+			Return(int64(0), nil)
+
+		userID, tokens, err := authService.Login(ctx, req, sessionID, client)
+
+		assert.Equal(t, utils.ErrAccountDeleted, err)
+		assert.Empty(t, userID)
+		assert.Nil(t, tokens)
+	})
+
+	t.Run("Login_LockedOut", func(t *testing.T) {
+		ctx := context.Background()
+		correctHashedPassword := passwordCases["correct"]["hashed"]
+		req := services.LoginRequest{
+			Email:    "locked@example.com",
+			Password: passwordCases["wrong"]["plain"],
+		}
+
+		mockQueries.EXPECT().
+			GetUserByEmail(ctx, req.Email).
+			Return(db.User{PasswordHash: []byte(correctHashedPassword)}, nil).
+			Times(loginThrottle.MaxFailuresPerEmail)
+
+		mockPassHasher.EXPECT().
+			CompareHashAndPassword([]byte(correctHashedPassword), []byte(req.Password)).
+			Return(errors.New("invalid password")).
+			Times(loginThrottle.MaxFailuresPerEmail)
+
+		for i := 0; i < loginThrottle.MaxFailuresPerEmail; i++ {
+			_, _, err := authService.Login(ctx, req, sessionID, client)
+			require.Equal(t, utils.ErrInvalidEmailOrPswd, err)
+		}
+
+		// Locked out without even looking up the user
+		userID, tokens, err := authService.Login(ctx, req, sessionID, client)
+
+		var lockedErr *services.LoginLockedError
+		require.ErrorAs(t, err, &lockedErr)
+		assert.ErrorIs(t, err, utils.ErrTooManyLoginAttempts)
+		assert.InDelta(t, loginThrottle.BaseLockout.Seconds(), lockedErr.RetryAfter.Seconds(), 1)
+		assert.Equal(t, "", userID)
+		assert.Nil(t, tokens)
+	})
+
+	t.Run("Login_ResetsFailures", func(t *testing.T) {
+		ctx := context.Background()
+		hashedPassword := passwordCases["correct"]["hashed"]
+		req := services.LoginRequest{
+			Email:    "reset@example.com",
+			Password: passwordCases["correct"]["plain"],
+		}
+
+		for i := 0; i < loginThrottle.MaxFailuresPerEmail-1; i++ {
+			require.NoError(t, loginThrottle.RecordFailure(ctx, req.Email, client.IP))
+		}
+
+		mockQueries.EXPECT().
+			GetUserByEmail(ctx, req.Email).
+			Return(db.User{UserID: uIDUuid, PasswordHash: []byte(hashedPassword)}, nil)
+
+		mockPassHasher.EXPECT().
+			CompareHashAndPassword([]byte(hashedPassword), []byte(req.Password)).
+			Return(nil)
+
+		mockPassHasher.EXPECT().
+			NeedsRehash([]byte(hashedPassword)).
+			Return(false)
+
+		mockTokenGen.EXPECT().
+			GenerateToken(uIDStr, sessionID).
+			Return(token, nil)
+
+		mockTokenGen.EXPECT().
+			GenerateRefreshToken().
+			Return(refreshToken, refreshTokenExp, nil)
+
+		mockRefreshTokenStore.EXPECT().
+			Save(ctx, utils.HashToken(refreshToken), gomock.Any()).
+			Return(nil)
+
+		mockSessionStore.EXPECT().
+			Create(ctx, gomock.Any(), refreshTokenExp).
+			Return(nil)
+
+		_, _, err := authService.Login(ctx, req, sessionID, client)
+
+		require.NoError(t, err)
+
+		// The counter starts over, so a single failure does not lock the email
+		require.NoError(t, loginThrottle.RecordFailure(ctx, req.Email, client.IP))
+		assert.NoError(t, loginThrottle.Check(ctx, req.Email, client.IP))
+	})
+
+	t.Run("Login_KeepsIPFailures", func(t *testing.T) {
+		ctx := context.Background()
+		hashedPassword := passwordCases["correct"]["hashed"]
+		req := services.LoginRequest{
+			Email:    "sprayer@example.com",
+			Password: passwordCases["correct"]["plain"],
+		}
+		sprayer := services.ClientInfo{UserAgent: "test-agent", IP: "192.0.2.2"}
+
+		for i := 0; i < loginThrottle.MaxFailuresPerIP-1; i++ {
+			require.NoError(t, loginThrottle.RecordFailure(ctx, fmt.Sprintf("victim%d@example.com", i), sprayer.IP))
+		}
+
+		mockQueries.EXPECT().
+			GetUserByEmail(ctx, req.Email).
+			Return(db.User{UserID: uIDUuid, PasswordHash: []byte(hashedPassword)}, nil)
+
+		mockPassHasher.EXPECT().
+			CompareHashAndPassword([]byte(hashedPassword), []byte(req.Password)).
+			Return(nil)
+
+		mockPassHasher.EXPECT().
+			NeedsRehash([]byte(hashedPassword)).
+			Return(false)
+
+		mockTokenGen.EXPECT().
+			GenerateToken(uIDStr, sessionID).
+			Return(token, nil)
+
+		mockTokenGen.EXPECT().
+			GenerateRefreshToken().
+			Return(refreshToken, refreshTokenExp, nil)
+
+		mockRefreshTokenStore.EXPECT().
+			Save(ctx, utils.HashToken(refreshToken), gomock.Any()).
+			Return(nil)
+
+		mockSessionStore.EXPECT().
+			Create(ctx, gomock.Any(), refreshTokenExp).
+			Return(nil)
+
+		_, _, err := authService.Login(ctx, req, sessionID, sprayer)
+
+		require.NoError(t, err)
+
+		// Logging into their own account does not give the IP more guesses at others
+		require.NoError(t, loginThrottle.RecordFailure(ctx, "victim@example.com", sprayer.IP))
+		var lockedErr *services.LoginLockedError
+		assert.ErrorAs(t, loginThrottle.Check(ctx, "another@example.com", sprayer.IP), &lockedErr)
+	})
+
+	t.Run("Login_TwoFactorRequired", func(t *testing.T) {
+		ctx := context.Background()
+		hashedPassword := passwordCases["correct"]["hashed"]
+		req := services.LoginRequest{
+			Email:    "2fa@example.com",
+			Password: passwordCases["correct"]["plain"],
+		}
+
+		mockQueries.EXPECT().
+			GetUserByEmail(ctx, req.Email).
+			Return(db.User{UserID: uIDUuid, PasswordHash: []byte(hashedPassword), TotpEnabledAt: pgtype.Timestamptz{Time: time.Now(), Valid: true}}, nil)
+
+		mockPassHasher.EXPECT().
+			CompareHashAndPassword([]byte(hashedPassword), []byte(req.Password)).
+			Return(nil)
+
+		mockPassHasher.EXPECT().
+			NeedsRehash([]byte(hashedPassword)).
+			Return(false)
+
+		mockTokenGen.EXPECT().
+			GenerateTwoFactorChallengeToken(uIDStr, sessionID).
+			Return("challenge-token-123", nil)
+
+		userID, tokens, err := authService.Login(ctx, req, sessionID, client)
+
+		var twoFactorErr *services.TwoFactorRequiredError
+		require.ErrorAs(t, err, &twoFactorErr)
+		assert.ErrorIs(t, err, utils.ErrTwoFactorRequired)
+		assert.Equal(t, "challenge-token-123", twoFactorErr.ChallengeToken)
+		assert.Equal(t, "", userID)
+		assert.Nil(t, tokens)
+	})
+
+	totpSecret := "JBSWY3DPEHPK3PXP"
+	totpUser := db.User{
+		UserID:        uIDUuid,
+		Email:         "2fa@example.com",
+		TotpSecret:    pgtype.Text{String: totpSecret, Valid: true},
+		TotpEnabledAt: pgtype.Timestamptz{Time: time.Now(), Valid: true},
+	}
+	challengeClaims := &services.JWTCustomClaims{UserID: uIDStr, SessionID: sessionID, TokenType: services.TokenTypeTwoFactorChallenge}
+
+	t.Run("LoginTwoFactor", func(t *testing.T) {
+		ctx := context.Background()
+		code, err := services.TOTPCode(totpSecret, services.TOTPStep(time.Now()))
+		require.NoError(t, err)
+		req := services.TwoFactorLoginRequest{ChallengeToken: "challenge-token-123", Code: code}
+
+		mockTokenGen.EXPECT().
+			ValidateTwoFactorChallengeToken(req.ChallengeToken).
+			Return(challengeClaims, nil)
+
+		mockQueries.EXPECT().
+			GetUserByUserID(ctx, uIDUuid).
+			Return(totpUser, nil)
+
+		mockQueries.EXPECT().
+			UpdateTOTPLastUsedStep(ctx, gomock.Any()).
+			DoAndReturn(func(ctx context.Context, arg db.UpdateTOTPLastUsedStepParams) (int64, error) {
+				assert.InDelta(t, services.TOTPStep(time.Now()), arg.TotpLastUsedStep.Int64, 1)
+				assert.Equal(t, uIDUuid, arg.UserID)
+				return 1, nil
+			})
+
+		mockTokenGen.EXPECT().
+			GenerateToken(uIDStr, sessionID).
+			Return(token, nil)
+
+		mockTokenGen.EXPECT().
+			GenerateRefreshToken().
+			Return(refreshToken, refreshTokenExp, nil)
+
+		mockRefreshTokenStore.EXPECT().
+			Save(ctx, utils.HashToken(refreshToken), gomock.Any()).
+			Return(nil)
+
+		mockSessionStore.EXPECT().
+			Create(ctx, gomock.Any(), refreshTokenExp).
+			Return(nil)
+
+		userID, tokens, err := authService.LoginTwoFactor(ctx, req, sessionID, client)
+
+		require.NoError(t, err)
+		assert.Equal(t, uIDStr, userID)
+		assert.Equal(t, token, tokens.AccessToken)
+		assert.Equal(t, refreshToken, tokens.RefreshToken)
+	})
+
+	t.Run("LoginTwoFactor_RecoveryCode", func(t *testing.T) {
+		ctx := context.Background()
+		req := services.TwoFactorLoginRequest{ChallengeToken: "challenge-token-123", Code: "ABCDE FGHIJ"}
+
+		mockTokenGen.EXPECT().
+			ValidateTwoFactorChallengeToken(req.ChallengeToken).
+			Return(challengeClaims, nil)
+
+		mockQueries.EXPECT().
+			GetUserByUserID(ctx, uIDUuid).
+			Return(totpUser, nil)
+
+		mockQueries.EXPECT().
+			UseTOTPRecoveryCode(ctx, db.UseTOTPRecoveryCodeParams{
+				CodeHash: utils.HashToken("abcde-fghij"),
+				UserID:   uIDUuid,
+			}).
+			Return(int64(1), nil)
+
+		mockTokenGen.EXPECT().
+			GenerateToken(uIDStr, sessionID).
+			Return(token, nil)
+
+		mockTokenGen.EXPECT().
+			GenerateRefreshToken().
+			Return(refreshToken, refreshTokenExp, nil)
+
+		mockRefreshTokenStore.EXPECT().
+			Save(ctx, utils.HashToken(refreshToken), gomock.Any()).
+			Return(nil)
+
+		mockSessionStore.EXPECT().
+			Create(ctx, gomock.Any(), refreshTokenExp).
+			Return(nil)
+
+		userID, tokens, err := authService.LoginTwoFactor(ctx, req, sessionID, client)
+
+		require.NoError(t, err)
+		assert.Equal(t, uIDStr, userID)
+		assert.NotNil(t, tokens)
+	})
+
+	t.Run("LoginTwoFactor_ReplayedCode", func(t *testing.T) {
+		ctx := context.Background()
+		code, err := services.TOTPCode(totpSecret, services.TOTPStep(time.Now()))
+		require.NoError(t, err)
+		req := services.TwoFactorLoginRequest{ChallengeToken: "challenge-token-123", Code: code}
+
+		mockTokenGen.EXPECT().
+			ValidateTwoFactorChallengeToken(req.ChallengeToken).
+			Return(challengeClaims, nil)
+
+		mockQueries.EXPECT().
+			GetUserByUserID(ctx, uIDUuid).
+			Return(totpUser, nil)
+
+		// The step of the code has already been used
+		mockQueries.EXPECT().
+			UpdateTOTPLastUsedStep(ctx, gomock.Any()).
+			Return(int64(0), nil)
+
+		userID, tokens, err := authService.LoginTwoFactor(ctx, req, sessionID, client)
+
+		assert.Equal(t, utils.ErrInvalidTwoFactorCode, err)
+		assert.Equal(t, "", userID)
+		assert.Nil(t, tokens)
+	})
+
+	t.Run("LoginTwoFactor_InvalidCode", func(t *testing.T) {
+		ctx := context.Background()
+		req := services.TwoFactorLoginRequest{ChallengeToken: "challenge-token-123", Code: "wrong-code"}
+
+		mockTokenGen.EXPECT().
+			ValidateTwoFactorChallengeToken(req.ChallengeToken).
+			Return(challengeClaims, nil)
+
+		mockQueries.EXPECT().
+			GetUserByUserID(ctx, uIDUuid).
+			Return(totpUser, nil)
+
+		mockQueries.EXPECT().
+			UseTOTPRecoveryCode(ctx, gomock.Any()).
+			Return(int64(0), nil)
+
+		userID, tokens, err := authService.LoginTwoFactor(ctx, req, sessionID, client)
+
+		assert.Equal(t, utils.ErrInvalidTwoFactorCode, err)
+		assert.Equal(t, "", userID)
+		assert.Nil(t, tokens)
+	})
+
+	t.Run("LoginTwoFactor_InvalidChallenge", func(t *testing.T) {
+		ctx := context.Background()
+		req := services.TwoFactorLoginRequest{ChallengeToken: "access-token-123", Code: "123456"}
+
+		// An access token cannot be used as a challenge token
+		mockTokenGen.EXPECT().
+			ValidateTwoFactorChallengeToken(req.ChallengeToken).
+			Return(&services.JWTCustomClaims{UserID: uIDStr, SessionID: sessionID, TokenType: services.TokenTypeAccess}, nil)
+
+		userID, tokens, err := authService.LoginTwoFactor(ctx, req, sessionID, client)
+
+		assert.Equal(t, utils.ErrInvalidChallengeToken, err)
+		assert.Equal(t, "", userID)
+		assert.Nil(t, tokens)
+	})
+
+	t.Run("LoginTwoFactor_SessionMismatch", func(t *testing.T) {
+		ctx := context.Background()
+		req := services.TwoFactorLoginRequest{ChallengeToken: "challenge-token-123", Code: "123456"}
+
+		mockTokenGen.EXPECT().
+			ValidateTwoFactorChallengeToken(req.ChallengeToken).
+			Return(challengeClaims, nil)
+
+		userID, tokens, err := authService.LoginTwoFactor(ctx, req, "another-session-id", client)
+
+		assert.Equal(t, utils.ErrInvalidChallengeToken, err)
+		assert.Equal(t, "", userID)
+		assert.Nil(t, tokens)
+	})
+
+	t.Run("RefreshToken", func(t *testing.T) {
+		ctx := context.Background()
+		req := services.RefreshTokenRequest{RefreshToken: refreshToken}
+		tokenHash := utils.HashToken(refreshToken)
+
+		mockRefreshTokenStore.EXPECT().
+			Get(ctx, tokenHash).
+			Return(&db.RefreshToken{UserID: uIDStr, SessionID: sessionID, ExpiresAt: refreshTokenExp}, nil)
+
+		mockRefreshTokenStore.EXPECT().
+			MarkUsed(ctx, tokenHash, refreshTokenExp).
+			Return(true, nil)
+
+		mockTokenGen.EXPECT().
+			GenerateToken(uIDStr, sessionID).
+			Return(token, nil)
+
+		mockTokenGen.EXPECT().
+			GenerateRefreshToken().
+			Return(newRefreshToken, time.Now().Add(2*time.Hour), nil)
+
+		mockRefreshTokenStore.EXPECT().
+			Save(ctx, utils.HashToken(newRefreshToken), db.RefreshToken{
+				UserID:    uIDStr,
+				SessionID: sessionID,
+				ExpiresAt: refreshTokenExp,
+			}).
+			Return(nil)
+
+		userID, tokens, err := authService.RefreshToken(ctx, req, sessionID)
+
+		require.NoError(t, err)
+		assert.Equal(t, uIDStr, userID)
+		assert.Equal(t, token, tokens.AccessToken)
+		assert.Equal(t, newRefreshToken, tokens.RefreshToken)
+	})
+
+	t.Run("RefreshToken_NotFound", func(t *testing.T) {
+		ctx := context.Background()
+		req := services.RefreshTokenRequest{RefreshToken: "unknown-token"}
+
+		mockRefreshTokenStore.EXPECT().
+			Get(ctx, utils.HashToken(req.RefreshToken)).
+			Return(nil, db.ErrRefreshTokenNotFound)
+
+		userID, tokens, err := authService.RefreshToken(ctx, req, sessionID)
+
+		assert.Equal(t, utils.ErrInvalidRefreshToken, err)
+		assert.Equal(t, "", userID)
+		assert.Nil(t, tokens)
+	})
+
+	t.Run("RefreshToken_SessionMismatch", func(t *testing.T) {
+		ctx := context.Background()
+		req := services.RefreshTokenRequest{RefreshToken: refreshToken}
+
+		mockRefreshTokenStore.EXPECT().
+			Get(ctx, utils.HashToken(refreshToken)).
+			Return(&db.RefreshToken{UserID: uIDStr, SessionID: "another-session-id", ExpiresAt: refreshTokenExp}, nil)
+
+		userID, tokens, err := authService.RefreshToken(ctx, req, sessionID)
+
+		assert.Equal(t, utils.ErrInvalidRefreshToken, err)
+		assert.Equal(t, "", userID)
+		assert.Nil(t, tokens)
+	})
+
+	t.Run("RefreshToken_Reused", func(t *testing.T) {
+		ctx := context.Background()
+		req := services.RefreshTokenRequest{RefreshToken: refreshToken}
+		tokenHash := utils.HashToken(refreshToken)
+
+		mockRefreshTokenStore.EXPECT().
+			Get(ctx, tokenHash).
+			Return(&db.RefreshToken{UserID: uIDStr, SessionID: sessionID, ExpiresAt: refreshTokenExp}, nil)
+
+		mockRefreshTokenStore.EXPECT().
+			MarkUsed(ctx, tokenHash, refreshTokenExp).
+			Return(false, nil)
+
+		mockRefreshTokenStore.EXPECT().
+			RevokeFamily(ctx, sessionID).
+			Return(nil)
+
+		mockSessionStore.EXPECT().
+			Delete(ctx, uIDStr, sessionID).
+			Return(nil)
+
+		userID, tokens, err := authService.RefreshToken(ctx, req, sessionID)
+
+		assert.Equal(t, utils.ErrRefreshTokenReused, err)
+		assert.Equal(t, "", userID)
+		assert.Nil(t, tokens)
+	})
+
+	t.Run("Logout", func(t *testing.T) {
+		ctx := context.Background()
+
+		mockRefreshTokenStore.EXPECT().
+			RevokeFamily(ctx, sessionID).
+			Return(nil)
+
+		mockSessionStore.EXPECT().
+			Delete(ctx, uIDStr, sessionID).
+			Return(nil)
+
+		err := authService.Logout(ctx, uIDStr, sessionID)
+
+		require.NoError(t, err)
 	})
 }
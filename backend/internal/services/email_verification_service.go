@@ -0,0 +1,176 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"todo-app/internal/db"
+	"todo-app/internal/mailer"
+	"todo-app/internal/utils"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// What users who have not verified their email yet are allowed to do with their todos
+type UnverifiedUserPolicy string
+
+const (
+	UnverifiedUserPolicyFull     UnverifiedUserPolicy = "full"
+	UnverifiedUserPolicyReadOnly UnverifiedUserPolicy = "read_only"
+	UnverifiedUserPolicyBlocked  UnverifiedUserPolicy = "blocked"
+)
+
+type EmailVerificationService struct {
+	SqlClient      db.WrappedQuerier
+	TokenGenerator ITokenGenerator
+	PasswordHasher IPasswordHasher
+	Mailer         IMailer
+}
+
+type ChangeEmailRequest struct {
+	NewEmail string `json:"new_email" binding:"required,email"`
+	Password string `json:"password" binding:"required"`
+}
+
+func NewEmailVerificationService(sqlClient db.WrappedQuerier, jwter ITokenGenerator, passHasher IPasswordHasher, mailer IMailer) *EmailVerificationService {
+	return &EmailVerificationService{SqlClient: sqlClient, TokenGenerator: jwter, PasswordHasher: passHasher, Mailer: mailer}
+}
+
+// Configured through UNVERIFIED_USER_POLICY (full, read_only or blocked; default read_only)
+func UnverifiedUserPolicyFromEnv() (UnverifiedUserPolicy, error) {
+	policy := UnverifiedUserPolicy(os.Getenv("UNVERIFIED_USER_POLICY"))
+	switch policy {
+	case "":
+		return UnverifiedUserPolicyReadOnly, nil
+	case UnverifiedUserPolicyFull, UnverifiedUserPolicyReadOnly, UnverifiedUserPolicyBlocked:
+		return policy, nil
+	}
+
+	return "", fmt.Errorf("unknown unverified user policy %q", policy)
+}
+
+func (s *EmailVerificationService) VerifyEmail(ctx context.Context, token string) error {
+	claims, err := s.TokenGenerator.ValidateEmailToken(token, TokenTypeEmailVerification)
+	if err != nil || claims.TokenType != TokenTypeEmailVerification {
+		return utils.ErrInvalidVerificationToken
+	}
+
+	userID, err := utils.StringToUUID(claims.UserID)
+	if err != nil {
+		return utils.ErrInvalidVerificationToken
+	}
+
+	user, err := s.SqlClient.GetUserByUserID(ctx, userID)
+	if err != nil || user.Email != claims.Email {
+		return utils.ErrInvalidVerificationToken
+	}
+
+	return s.SqlClient.MarkEmailVerified(ctx, db.MarkEmailVerifiedParams{
+		UserID: user.UserID,
+		Email:  user.Email,
+	})
+}
+
+func (s *EmailVerificationService) ResendVerification(ctx context.Context, userID pgtype.UUID) error {
+	user, err := s.SqlClient.GetUserByUserID(ctx, userID)
+	if err != nil {
+		return utils.ErrInvalidUID
+	}
+
+	if user.EmailVerifiedAt.Valid {
+		return utils.ErrEmailAlreadyVerified
+	}
+
+	return sendVerificationEmail(ctx, s.TokenGenerator, s.Mailer, user)
+}
+
+// Sends a confirmation link to the new email; the email is changed only once the link is opened
+func (s *EmailVerificationService) RequestEmailChange(ctx context.Context, userID pgtype.UUID, req ChangeEmailRequest) error {
+	user, err := s.SqlClient.GetUserByUserID(ctx, userID)
+	if err != nil {
+		return utils.ErrInvalidUID
+	}
+
+	if err = s.PasswordHasher.CompareHashAndPassword(user.PasswordHash, []byte(req.Password)); err != nil {
+		return utils.ErrInvalidCurrentPassword
+	}
+
+	if req.NewEmail == user.Email {
+		return utils.ErrSameEmail
+	}
+
+	// Checked here as well so that no link is sent for an email that cannot be used; the unique index still decides on confirmation
+	_, err = s.SqlClient.GetUserByEmail(ctx, req.NewEmail)
+	if err == nil {
+		return utils.ErrEmailAlreadyInUse
+	} else if !errors.Is(err, pgx.ErrNoRows) {
+		return err
+	}
+
+	token, err := s.TokenGenerator.GenerateEmailChangeToken(utils.UUIDToString(user.UserID), user.Email, req.NewEmail)
+	if err != nil {
+		return err
+	}
+
+	return s.Mailer.Send(ctx, mailer.Message{
+		To:      req.NewEmail,
+		Subject: "Confirm your new email address",
+		Body: fmt.Sprintf(
+			"Open the link below to use this email address for your account:\n%s/api/v1/email-change/confirm?token=%s\n\nIf you did not request this, you can ignore this email.",
+			os.Getenv("API_URL"), token,
+		),
+	})
+}
+
+// Swaps the email and lets the previous one know, so that the owner notices if the change was not theirs
+func (s *EmailVerificationService) ConfirmEmailChange(ctx context.Context, token string) error {
+	claims, err := s.TokenGenerator.ValidateEmailToken(token, TokenTypeEmailChange)
+	if err != nil || claims.TokenType != TokenTypeEmailChange {
+		return utils.ErrInvalidEmailChangeToken
+	}
+
+	userID, err := utils.StringToUUID(claims.UserID)
+	if err != nil {
+		return utils.ErrInvalidEmailChangeToken
+	}
+
+	// Fails once the email has changed since the token was issued, which also makes the token single use
+	rows, err := s.SqlClient.UpdateEmail(ctx, db.UpdateEmailParams{
+		NewEmail: claims.NewEmail,
+		UserID:   userID,
+		Email:    claims.Email,
+	})
+	if err != nil {
+		return err
+	} else if rows == 0 {
+		return utils.ErrInvalidEmailChangeToken
+	}
+
+	return s.Mailer.Send(ctx, mailer.Message{
+		To:      claims.Email,
+		Subject: "Your email address has been changed",
+		Body: fmt.Sprintf(
+			"The email address of your account has been changed to %s.\n\nIf you did not make this change, please contact us immediately.",
+			claims.NewEmail,
+		),
+	})
+}
+
+// The token is a signed JWT, so nothing has to be stored until the link is opened
+func sendVerificationEmail(ctx context.Context, tokenGenerator ITokenGenerator, m IMailer, user db.User) error {
+	token, err := tokenGenerator.GenerateEmailVerificationToken(utils.UUIDToString(user.UserID), user.Email)
+	if err != nil {
+		return err
+	}
+
+	return m.Send(ctx, mailer.Message{
+		To:      user.Email,
+		Subject: "Verify your email address",
+		Body: fmt.Sprintf(
+			"Open the link below to verify your email address:\n%s/api/v1/verify-email?token=%s\n\nIf you did not create an account, you can ignore this email.",
+			os.Getenv("API_URL"), token,
+		),
+	})
+}
@@ -0,0 +1,273 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"todo-app/internal/db"
+	mock_db "todo-app/internal/db/_mock"
+	"todo-app/internal/mailer"
+	"todo-app/internal/services"
+	mock_services "todo-app/internal/services/_mock"
+	"todo-app/internal/utils"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestEmailVerificationService(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockQueries := mock_db.NewMockWrappedQuerier(ctrl)
+	mockTokenGen := mock_services.NewMockITokenGenerator(ctrl)
+	mockPassHasher := mock_services.NewMockIPasswordHasher(ctrl)
+	mockMailer := mock_services.NewMockIMailer(ctrl)
+
+	emailVerificationService := services.NewEmailVerificationService(mockQueries, mockTokenGen, mockPassHasher, mockMailer)
+
+	uIDStr := "00010203-0405-0607-0809-0a0b0c0d0e0f"
+	uIDUuid, _ := utils.StringToUUID(uIDStr)
+	email := "test@example.com"
+	newEmail := "new@example.com"
+	token := "verification-token-123"
+	user := db.User{ID: 1, UserID: uIDUuid, Email: email, PasswordHash: []byte("hashedpassword")}
+	changeEmailReq := services.ChangeEmailRequest{NewEmail: newEmail, Password: "password"}
+
+	t.Run("VerifyEmail", func(t *testing.T) {
+		ctx := context.Background()
+
+		mockTokenGen.EXPECT().
+			ValidateEmailToken(token, services.TokenTypeEmailVerification).
+			Return(&services.JWTCustomClaims{UserID: uIDStr, TokenType: services.TokenTypeEmailVerification, Email: email}, nil)
+
+		mockQueries.EXPECT().
+			GetUserByUserID(ctx, uIDUuid).
+			Return(db.User{UserID: uIDUuid, Email: email}, nil)
+
+		mockQueries.EXPECT().
+			MarkEmailVerified(ctx, db.MarkEmailVerifiedParams{UserID: uIDUuid, Email: email}).
+			Return(nil)
+
+		err := emailVerificationService.VerifyEmail(ctx, token)
+
+		require.NoError(t, err)
+	})
+
+	t.Run("VerifyEmail_InvalidToken", func(t *testing.T) {
+		ctx := context.Background()
+
+		mockTokenGen.EXPECT().
+			ValidateEmailToken("invalid-token", services.TokenTypeEmailVerification).
+			Return(nil, errors.New("token is expired"))
+
+		err := emailVerificationService.VerifyEmail(ctx, "invalid-token")
+
+		assert.Equal(t, utils.ErrInvalidVerificationToken, err)
+	})
+
+	t.Run("VerifyEmail_AccessToken", func(t *testing.T) {
+		ctx := context.Background()
+
+		mockTokenGen.EXPECT().
+			ValidateEmailToken(token, services.TokenTypeEmailVerification).
+			Return(&services.JWTCustomClaims{UserID: uIDStr, TokenType: services.TokenTypeAccess}, nil)
+
+		err := emailVerificationService.VerifyEmail(ctx, token)
+
+		assert.Equal(t, utils.ErrInvalidVerificationToken, err)
+	})
+
+	t.Run("VerifyEmail_EmailChanged", func(t *testing.T) {
+		ctx := context.Background()
+
+		mockTokenGen.EXPECT().
+			ValidateEmailToken(token, services.TokenTypeEmailVerification).
+			Return(&services.JWTCustomClaims{UserID: uIDStr, TokenType: services.TokenTypeEmailVerification, Email: email}, nil)
+
+		mockQueries.EXPECT().
+			GetUserByUserID(ctx, uIDUuid).
+			Return(db.User{UserID: uIDUuid, Email: "new@example.com"}, nil)
+
+		err := emailVerificationService.VerifyEmail(ctx, token)
+
+		assert.Equal(t, utils.ErrInvalidVerificationToken, err)
+	})
+
+	t.Run("ResendVerification", func(t *testing.T) {
+		ctx := context.Background()
+
+		mockQueries.EXPECT().
+			GetUserByUserID(ctx, uIDUuid).
+			Return(db.User{UserID: uIDUuid, Email: email}, nil)
+
+		mockTokenGen.EXPECT().
+			GenerateEmailVerificationToken(uIDStr, email).
+			Return(token, nil)
+
+		mockMailer.EXPECT().
+			Send(ctx, gomock.Any()).
+			DoAndReturn(func(ctx context.Context, msg mailer.Message) error {
+				assert.Equal(t, email, msg.To)
+				assert.Contains(t, msg.Body, "verify-email?token="+token)
+				return nil
+			})
+
+		err := emailVerificationService.ResendVerification(ctx, uIDUuid)
+
+		require.NoError(t, err)
+	})
+
+	t.Run("ResendVerification_AlreadyVerified", func(t *testing.T) {
+		ctx := context.Background()
+
+		mockQueries.EXPECT().
+			GetUserByUserID(ctx, uIDUuid).
+			Return(db.User{UserID: uIDUuid, Email: email, EmailVerifiedAt: pgtype.Timestamptz{Time: time.Now(), Valid: true}}, nil)
+
+		err := emailVerificationService.ResendVerification(ctx, uIDUuid)
+
+		assert.Equal(t, utils.ErrEmailAlreadyVerified, err)
+	})
+
+	t.Run("RequestEmailChange", func(t *testing.T) {
+		ctx := context.Background()
+		t.Setenv("API_URL", "http://localhost:8080")
+
+		mockQueries.EXPECT().
+			GetUserByUserID(ctx, uIDUuid).
+			Return(user, nil)
+
+		mockPassHasher.EXPECT().
+			CompareHashAndPassword(user.PasswordHash, []byte("password")).
+			Return(nil)
+
+		mockQueries.EXPECT().
+			GetUserByEmail(ctx, newEmail).
+			Return(db.User{}, pgx.ErrNoRows)
+
+		mockTokenGen.EXPECT().
+			GenerateEmailChangeToken(uIDStr, email, newEmail).
+			Return(token, nil)
+
+		mockMailer.EXPECT().
+			Send(ctx, gomock.Any()).
+			DoAndReturn(func(ctx context.Context, msg mailer.Message) error {
+				assert.Equal(t, newEmail, msg.To)
+				assert.Contains(t, msg.Body, "http://localhost:8080/api/v1/email-change/confirm?token="+token)
+				return nil
+			})
+
+		err := emailVerificationService.RequestEmailChange(ctx, uIDUuid, changeEmailReq)
+
+		require.NoError(t, err)
+	})
+
+	t.Run("RequestEmailChange_WrongPassword", func(t *testing.T) {
+		ctx := context.Background()
+
+		mockQueries.EXPECT().
+			GetUserByUserID(ctx, uIDUuid).
+			Return(user, nil)
+
+		mockPassHasher.EXPECT().
+			CompareHashAndPassword(user.PasswordHash, []byte("password")).
+			Return(errors.New("mismatched hash and password"))
+
+		err := emailVerificationService.RequestEmailChange(ctx, uIDUuid, changeEmailReq)
+
+		assert.Equal(t, utils.ErrInvalidCurrentPassword, err)
+	})
+
+	t.Run("RequestEmailChange_SameEmail", func(t *testing.T) {
+		ctx := context.Background()
+
+		mockQueries.EXPECT().
+			GetUserByUserID(ctx, uIDUuid).
+			Return(user, nil)
+
+		mockPassHasher.EXPECT().
+			CompareHashAndPassword(user.PasswordHash, []byte("password")).
+			Return(nil)
+
+		err := emailVerificationService.RequestEmailChange(ctx, uIDUuid, services.ChangeEmailRequest{NewEmail: email, Password: "password"})
+
+		assert.Equal(t, utils.ErrSameEmail, err)
+	})
+
+	t.Run("RequestEmailChange_EmailInUse", func(t *testing.T) {
+		ctx := context.Background()
+
+		mockQueries.EXPECT().
+			GetUserByUserID(ctx, uIDUuid).
+			Return(user, nil)
+
+		mockPassHasher.EXPECT().
+			CompareHashAndPassword(user.PasswordHash, []byte("password")).
+			Return(nil)
+
+		mockQueries.EXPECT().
+			GetUserByEmail(ctx, newEmail).
+			Return(db.User{ID: 2, Email: newEmail}, nil)
+
+		err := emailVerificationService.RequestEmailChange(ctx, uIDUuid, changeEmailReq)
+
+		assert.Equal(t, utils.ErrEmailAlreadyInUse, err)
+	})
+
+	t.Run("ConfirmEmailChange", func(t *testing.T) {
+		ctx := context.Background()
+
+		mockTokenGen.EXPECT().
+			ValidateEmailToken(token, services.TokenTypeEmailChange).
+			Return(&services.JWTCustomClaims{UserID: uIDStr, TokenType: services.TokenTypeEmailChange, Email: email, NewEmail: newEmail}, nil)
+
+		mockQueries.EXPECT().
+			UpdateEmail(ctx, db.UpdateEmailParams{NewEmail: newEmail, UserID: uIDUuid, Email: email}).
+			Return(int64(1), nil)
+
+		mockMailer.EXPECT().
+			Send(ctx, gomock.Any()).
+			DoAndReturn(func(ctx context.Context, msg mailer.Message) error {
+				assert.Equal(t, email, msg.To)
+				assert.Contains(t, msg.Body, newEmail)
+				return nil
+			})
+
+		err := emailVerificationService.ConfirmEmailChange(ctx, token)
+
+		require.NoError(t, err)
+	})
+
+	t.Run("ConfirmEmailChange_VerificationToken", func(t *testing.T) {
+		ctx := context.Background()
+
+		mockTokenGen.EXPECT().
+			ValidateEmailToken(token, services.TokenTypeEmailChange).
+			Return(&services.JWTCustomClaims{UserID: uIDStr, TokenType: services.TokenTypeEmailVerification, Email: email}, nil)
+
+		err := emailVerificationService.ConfirmEmailChange(ctx, token)
+
+		assert.Equal(t, utils.ErrInvalidEmailChangeToken, err)
+	})
+
+	t.Run("ConfirmEmailChange_AlreadyUsed", func(t *testing.T) {
+		ctx := context.Background()
+
+		mockTokenGen.EXPECT().
+			ValidateEmailToken(token, services.TokenTypeEmailChange).
+			Return(&services.JWTCustomClaims{UserID: uIDStr, TokenType: services.TokenTypeEmailChange, Email: email, NewEmail: newEmail}, nil)
+
+		mockQueries.EXPECT().
+			UpdateEmail(ctx, db.UpdateEmailParams{NewEmail: newEmail, UserID: uIDUuid, Email: email}).
+			Return(int64(0), nil)
+
+		err := emailVerificationService.ConfirmEmailChange(ctx, token)
+
+		assert.Equal(t, utils.ErrInvalidEmailChangeToken, err)
+	})
+}
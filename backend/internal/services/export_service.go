@@ -0,0 +1,402 @@
+package services
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/fs"
+	"strconv"
+	"strings"
+	"time"
+	"todo-app/internal/db"
+	"todo-app/internal/utils"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type ExportService struct {
+	SqlClient db.WrappedQuerier
+	BlobStore IBlobStore
+}
+
+// Everything GetMe reports, plus the timestamps and the preferences; secrets such as hashes are left out
+type ExportProfile struct {
+	UserID              string            `json:"user_id"`
+	Username            string            `json:"username"`
+	Email               string            `json:"email"`
+	Role                string            `json:"role"`
+	EmailVerified       bool              `json:"email_verified"`
+	EmailVerifiedAt     *time.Time        `json:"email_verified_at"`
+	TwoFactorEnabled    bool              `json:"two_factor_enabled"`
+	TwoFactorEnabledAt  *time.Time        `json:"two_factor_enabled_at"`
+	DeletionRequestedAt *time.Time        `json:"deletion_requested_at"`
+	CreatedAt           time.Time         `json:"created_at"`
+	UpdatedAt           time.Time         `json:"updated_at"`
+	Preferences         ExportPreferences `json:"preferences"`
+}
+
+type ExportPreferences struct {
+	DisplayName     *string `json:"display_name"`
+	TimeZone        string  `json:"time_zone"`
+	Locale          string  `json:"locale"`
+	DefaultTodoSort string  `json:"default_todo_sort"`
+	WeekStart       string  `json:"week_start"`
+	// Name of the avatar image in the ZIP; null when the user has none
+	Avatar    *string    `json:"avatar"`
+	UpdatedAt *time.Time `json:"updated_at"`
+}
+
+type ExportTodo struct {
+	ID          int32       `json:"id"`
+	ListID      int32       `json:"list_id"`
+	Description string      `json:"description"`
+	Position    json.Number `json:"position"`
+	Completed   bool        `json:"completed"`
+	DueAt       *time.Time  `json:"due_at"`
+	RemindAt    *time.Time  `json:"remind_at"`
+	Recurrence  *string     `json:"recurrence"`
+	Priority    string      `json:"priority"`
+	Language    string      `json:"language"`
+	Tags        []string    `json:"tags"`
+	CreatedAt   time.Time   `json:"created_at"`
+	UpdatedAt   time.Time   `json:"updated_at"`
+}
+
+var exportListCSVHeader = []string{"id", "name", "inbox", "archived_at", "created_at", "updated_at"}
+
+var exportTodoItemCSVHeader = []string{"todo_id", "id", "description", "position", "completed", "created_at", "updated_at"}
+
+var exportTodoCSVHeader = []string{"id", "list_id", "description", "position", "completed", "due_at", "remind_at", "recurrence", "priority", "language", "tags", "created_at", "updated_at"}
+
+var exportAvatarExtensions = map[string]string{"image/png": ".png", "image/jpeg": ".jpg", "image/gif": ".gif"}
+
+func NewExportService(sqlClient db.WrappedQuerier, blobStore IBlobStore) *ExportService {
+	return &ExportService{SqlClient: sqlClient, BlobStore: blobStore}
+}
+
+// Writes a ZIP with profile.json, lists.csv, todos.json, todos.csv, todo_items.csv and the avatar, if any, to w.
+// The todos are streamed from the database into the ZIP, so that a large export is never held in memory.
+func (s *ExportService) WriteExport(ctx context.Context, userID pgtype.UUID, w io.Writer) error {
+	user, err := s.SqlClient.GetUserByUserID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return utils.ErrNoRowsMatchedSQLC
+		}
+		return err
+	}
+
+	prefs, err := getUserPreferences(ctx, s.SqlClient, user.ID)
+	if err != nil {
+		return err
+	}
+
+	// Opened before the profile is written, so that the profile only refers to an avatar that is in the ZIP
+	avatar, avatarName, err := s.openExportAvatar(ctx, prefs)
+	if err != nil {
+		return err
+	}
+	if avatar != nil {
+		defer avatar.Close()
+	}
+
+	now := time.Now()
+	zw := zip.NewWriter(w)
+
+	if err = writeExportProfile(zw, now, user, prefs, avatarName); err != nil {
+		return err
+	}
+
+	if err = s.writeExportListsCSV(ctx, zw, now, user.ID); err != nil {
+		return err
+	}
+
+	if err = s.writeExportTodosJSON(ctx, zw, now, user.ID); err != nil {
+		return err
+	}
+
+	if err = s.writeExportTodosCSV(ctx, zw, now, user.ID); err != nil {
+		return err
+	}
+
+	if err = s.writeExportTodoItemsCSV(ctx, zw, now, user.ID); err != nil {
+		return err
+	}
+
+	if avatar != nil {
+		f, err := createExportFile(zw, *avatarName, now)
+		if err != nil {
+			return err
+		}
+		if _, err = io.Copy(f, avatar); err != nil {
+			return err
+		}
+	}
+
+	return zw.Close()
+}
+
+// An avatar whose blob has gone missing is left out rather than failing the export
+func (s *ExportService) openExportAvatar(ctx context.Context, prefs db.UserPreference) (io.ReadCloser, *string, error) {
+	if !prefs.AvatarKey.Valid {
+		return nil, nil, nil
+	}
+
+	avatar, err := s.BlobStore.Get(ctx, prefs.AvatarKey.String)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil, nil
+		}
+		return nil, nil, err
+	}
+
+	name := "avatar" + exportAvatarExtensions[prefs.AvatarContentType.String]
+	return avatar, &name, nil
+}
+
+func writeExportProfile(zw *zip.Writer, now time.Time, user db.User, prefs db.UserPreference, avatarName *string) error {
+	f, err := createExportFile(zw, "profile.json", now)
+	if err != nil {
+		return err
+	}
+
+	profile := ExportProfile{
+		UserID:              utils.UUIDToString(user.UserID),
+		Username:            user.Username,
+		Email:               user.Email,
+		Role:                user.Role,
+		EmailVerified:       user.EmailVerifiedAt.Valid,
+		EmailVerifiedAt:     timestamptzPtr(user.EmailVerifiedAt),
+		TwoFactorEnabled:    user.TotpEnabledAt.Valid,
+		TwoFactorEnabledAt:  timestamptzPtr(user.TotpEnabledAt),
+		DeletionRequestedAt: timestamptzPtr(user.DeletionRequestedAt),
+		CreatedAt:           user.CreatedAt.Time,
+		UpdatedAt:           user.UpdatedAt.Time,
+		Preferences: ExportPreferences{
+			DisplayName:     textPtr(prefs.DisplayName),
+			TimeZone:        prefs.TimeZone,
+			Locale:          prefs.Locale,
+			DefaultTodoSort: prefs.DefaultTodoSort,
+			WeekStart:       prefs.WeekStart,
+			Avatar:          avatarName,
+			UpdatedAt:       timestamptzPtr(prefs.UpdatedAt),
+		},
+	}
+
+	enc := json.NewEncoder(f)
+	enc.SetIndent("", "  ")
+	return enc.Encode(profile)
+}
+
+// Archived lists included
+func (s *ExportService) writeExportListsCSV(ctx context.Context, zw *zip.Writer, now time.Time, userID int32) error {
+	f, err := createExportFile(zw, "lists.csv", now)
+	if err != nil {
+		return err
+	}
+
+	lists, err := s.SqlClient.ListLists(ctx, db.ListListsParams{UserID: userID, IncludeArchived: true})
+	if err != nil {
+		return err
+	}
+
+	cw := csv.NewWriter(f)
+	if err = cw.Write(exportListCSVHeader); err != nil {
+		return err
+	}
+
+	for _, list := range lists {
+		err = cw.Write([]string{
+			strconv.Itoa(int(list.ID)),
+			list.Name,
+			strconv.FormatBool(list.IsInbox),
+			formatOptionalTime(timestamptzPtr(list.ArchivedAt)),
+			list.CreatedAt.Time.Format(time.RFC3339),
+			list.UpdatedAt.Time.Format(time.RFC3339),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// The array is written element by element as the todos come from the cursor
+func (s *ExportService) writeExportTodosJSON(ctx context.Context, zw *zip.Writer, now time.Time, userID int32) error {
+	f, err := createExportFile(zw, "todos.json", now)
+	if err != nil {
+		return err
+	}
+
+	if _, err = io.WriteString(f, "["); err != nil {
+		return err
+	}
+
+	separator := "\n  "
+	err = s.SqlClient.StreamTodos(ctx, userID, func(todo db.Todo) error {
+		b, err := json.MarshalIndent(toExportTodo(todo), "  ", "  ")
+		if err != nil {
+			return err
+		}
+
+		if _, err = io.WriteString(f, separator); err != nil {
+			return err
+		}
+		separator = ",\n  "
+
+		_, err = f.Write(b)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(f, "\n]\n")
+	return err
+}
+
+func (s *ExportService) writeExportTodosCSV(ctx context.Context, zw *zip.Writer, now time.Time, userID int32) error {
+	f, err := createExportFile(zw, "todos.csv", now)
+	if err != nil {
+		return err
+	}
+
+	cw := csv.NewWriter(f)
+	if err = cw.Write(exportTodoCSVHeader); err != nil {
+		return err
+	}
+
+	err = s.SqlClient.StreamTodos(ctx, userID, func(todo db.Todo) error {
+		t := toExportTodo(todo)
+		return cw.Write([]string{
+			strconv.Itoa(int(t.ID)),
+			strconv.Itoa(int(t.ListID)),
+			t.Description,
+			t.Position.String(),
+			strconv.FormatBool(t.Completed),
+			formatOptionalTime(t.DueAt),
+			formatOptionalTime(t.RemindAt),
+			todo.Recurrence.String,
+			t.Priority,
+			t.Language,
+			strings.Join(t.Tags, ";"),
+			t.CreatedAt.Format(time.RFC3339),
+			t.UpdatedAt.Format(time.RFC3339),
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// Checklists are short, so unlike the todos the items are loaded at once
+func (s *ExportService) writeExportTodoItemsCSV(ctx context.Context, zw *zip.Writer, now time.Time, userID int32) error {
+	f, err := createExportFile(zw, "todo_items.csv", now)
+	if err != nil {
+		return err
+	}
+
+	items, err := s.SqlClient.ListTodoItemsByUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	cw := csv.NewWriter(f)
+	if err = cw.Write(exportTodoItemCSVHeader); err != nil {
+		return err
+	}
+
+	for _, item := range items {
+		err = cw.Write([]string{
+			strconv.Itoa(int(item.TodoID)),
+			strconv.Itoa(int(item.ID)),
+			item.Description,
+			numericToJSONNumber(item.Position).String(),
+			strconv.FormatBool(item.Completed),
+			item.CreatedAt.Time.Format(time.RFC3339),
+			item.UpdatedAt.Time.Format(time.RFC3339),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+func createExportFile(zw *zip.Writer, name string, now time.Time) (io.Writer, error) {
+	return zw.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: now})
+}
+
+func toExportTodo(todo db.Todo) ExportTodo {
+	return ExportTodo{
+		ID:          todo.ID,
+		ListID:      todo.ListID,
+		Description: todo.Description,
+		Position:    numericToJSONNumber(todo.Position),
+		Completed:   todo.Completed.Bool,
+		DueAt:       timestamptzPtr(todo.DueAt),
+		RemindAt:    timestamptzPtr(todo.RemindAt),
+		Recurrence:  textPtr(todo.Recurrence),
+		Priority:    TodoPriorities[todo.Priority],
+		Language:    todo.Language,
+		Tags:        tagNames(todo.Tags),
+		CreatedAt:   todo.CreatedAt.Time,
+		UpdatedAt:   todo.UpdatedAt.Time,
+	}
+}
+
+// Only the names are exported, as in the tag filters
+func tagNames(tags []byte) []string {
+	var parsed []struct {
+		Name string `json:"name"`
+	}
+	names := []string{}
+	if err := json.Unmarshal(tags, &parsed); err != nil {
+		return names
+	}
+	for _, tag := range parsed {
+		names = append(names, tag.Name)
+	}
+	return names
+}
+
+// Positions can be fractional after reordering, so the exact decimal is kept
+func numericToJSONNumber(n pgtype.Numeric) json.Number {
+	v, err := n.Value()
+	if s, ok := v.(string); err == nil && ok {
+		return json.Number(s)
+	}
+	return json.Number("0")
+}
+
+func timestamptzPtr(t pgtype.Timestamptz) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
+
+func textPtr(t pgtype.Text) *string {
+	if !t.Valid {
+		return nil
+	}
+	return &t.String
+}
+
+// Empty in the CSV when not set
+func formatOptionalTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
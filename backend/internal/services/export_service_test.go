@@ -0,0 +1,337 @@
+package services_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/fs"
+	"math/big"
+	"testing"
+	"time"
+	"todo-app/internal/db"
+	mock_db "todo-app/internal/db/_mock"
+	"todo-app/internal/services"
+	mock_services "todo-app/internal/services/_mock"
+	"todo-app/internal/utils"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestExportService(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockQueries := mock_db.NewMockWrappedQuerier(ctrl)
+	mockBlobStore := mock_services.NewMockIBlobStore(ctrl)
+
+	exportService := services.NewExportService(mockQueries, mockBlobStore)
+
+	uIDStr := "00010203-0405-0607-0809-0a0b0c0d0e0f"
+	uIDUuid, _ := utils.StringToUUID(uIDStr)
+	createdAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	user := db.User{
+		ID:              1,
+		UserID:          uIDUuid,
+		Username:        "testuser",
+		Email:           "test@example.com",
+		PasswordHash:    []byte("hashedpassword"),
+		Role:            services.RoleUser,
+		EmailVerifiedAt: pgtype.Timestamptz{Time: createdAt, Valid: true},
+		TotpSecret:      pgtype.Text{String: "secret", Valid: true},
+		CreatedAt:       pgtype.Timestamptz{Time: createdAt, Valid: true},
+		UpdatedAt:       pgtype.Timestamptz{Time: createdAt, Valid: true},
+	}
+	prefs := db.UserPreference{
+		UserID:            1,
+		DisplayName:       pgtype.Text{String: "Test User", Valid: true},
+		TimeZone:          "Asia/Tokyo",
+		Locale:            "ja",
+		DefaultTodoSort:   "due_at",
+		WeekStart:         services.WeekStartSunday,
+		AvatarKey:         pgtype.Text{String: "avatars/" + uIDStr, Valid: true},
+		AvatarContentType: pgtype.Text{String: "image/png", Valid: true},
+		UpdatedAt:         pgtype.Timestamptz{Time: createdAt, Valid: true},
+	}
+	todos := []db.Todo{
+		{
+			ID:          1,
+			UserID:      1,
+			ListID:      1,
+			Description: "buy milk",
+			Language:    "english",
+			Position:    pgtype.Numeric{Int: big.NewInt(100), Valid: true},
+			Completed:   pgtype.Bool{Bool: true, Valid: true},
+			Tags:        []byte(`[{"id": 2, "name": "home", "color": "#00ff00"}, {"id": 1, "name": "shopping", "color": "#808080"}]`),
+			CreatedAt:   pgtype.Timestamptz{Time: createdAt, Valid: true},
+			UpdatedAt:   pgtype.Timestamptz{Time: createdAt, Valid: true},
+		},
+		{
+			ID:          2,
+			UserID:      1,
+			ListID:      2,
+			Description: "call \"Bob\", then Alice",
+			Priority:    3,
+			Language:    "english",
+			Position:    pgtype.Numeric{Int: big.NewInt(1505), Exp: -1, Valid: true},
+			Completed:   pgtype.Bool{Bool: false, Valid: true},
+			CreatedAt:   pgtype.Timestamptz{Time: createdAt, Valid: true},
+			UpdatedAt:   pgtype.Timestamptz{Time: createdAt, Valid: true},
+		},
+	}
+	streamTodos := func(ctx context.Context, userID int32, fn func(db.Todo) error) error {
+		for _, todo := range todos {
+			if err := fn(todo); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	t.Run("WriteExport", func(t *testing.T) {
+		ctx := context.Background()
+		var buf bytes.Buffer
+
+		mockQueries.EXPECT().
+			GetUserByUserID(ctx, uIDUuid).
+			Return(user, nil)
+
+		mockQueries.EXPECT().
+			GetUserPreferences(ctx, int32(1)).
+			Return(prefs, nil)
+
+		mockBlobStore.EXPECT().
+			Get(ctx, "avatars/"+uIDStr).
+			Return(io.NopCloser(bytes.NewReader([]byte("avatar image"))), nil)
+
+		mockQueries.EXPECT().
+			ListLists(ctx, db.ListListsParams{UserID: 1, IncludeArchived: true}).
+			Return([]db.List{
+				{ID: 1, UserID: 1, Name: "Inbox", IsInbox: true, CreatedAt: pgtype.Timestamptz{Time: createdAt, Valid: true}, UpdatedAt: pgtype.Timestamptz{Time: createdAt, Valid: true}},
+				{ID: 2, UserID: 1, Name: "Errands", ArchivedAt: pgtype.Timestamptz{Time: createdAt, Valid: true}, CreatedAt: pgtype.Timestamptz{Time: createdAt, Valid: true}, UpdatedAt: pgtype.Timestamptz{Time: createdAt, Valid: true}},
+			}, nil)
+
+		// Once for the JSON and once for the CSV
+		mockQueries.EXPECT().
+			StreamTodos(ctx, int32(1), gomock.Any()).
+			DoAndReturn(streamTodos).
+			Times(2)
+
+		mockQueries.EXPECT().
+			ListTodoItemsByUser(ctx, int32(1)).
+			Return([]db.TodoItem{{
+				ID:          1,
+				TodoID:      1,
+				Description: "oat milk",
+				Position:    pgtype.Numeric{Int: big.NewInt(100), Valid: true},
+				Completed:   true,
+				CreatedAt:   pgtype.Timestamptz{Time: createdAt, Valid: true},
+				UpdatedAt:   pgtype.Timestamptz{Time: createdAt, Valid: true},
+			}}, nil)
+
+		err := exportService.WriteExport(ctx, uIDUuid, &buf)
+		require.NoError(t, err)
+
+		files := readZip(t, buf.Bytes())
+		require.Len(t, files, 6)
+		assert.Equal(t, []byte("avatar image"), files["avatar.png"])
+
+		var profile map[string]any
+		require.NoError(t, json.Unmarshal(files["profile.json"], &profile))
+		assert.Equal(t, uIDStr, profile["user_id"])
+		assert.Equal(t, "test@example.com", profile["email"])
+		assert.Equal(t, true, profile["email_verified"])
+		assert.Equal(t, "2024-01-01T00:00:00Z", profile["created_at"])
+		assert.NotContains(t, profile, "password_hash")
+		assert.NotContains(t, string(files["profile.json"]), "secret")
+		assert.Equal(t, map[string]any{
+			"display_name":      "Test User",
+			"time_zone":         "Asia/Tokyo",
+			"locale":            "ja",
+			"default_todo_sort": "due_at",
+			"week_start":        "sunday",
+			"avatar":            "avatar.png",
+			"updated_at":        "2024-01-01T00:00:00Z",
+		}, profile["preferences"])
+
+		var exportedTodos []services.ExportTodo
+		require.NoError(t, json.Unmarshal(files["todos.json"], &exportedTodos))
+		require.Len(t, exportedTodos, 2)
+		assert.Equal(t, "buy milk", exportedTodos[0].Description)
+		assert.Equal(t, "100", exportedTodos[0].Position.String())
+		assert.Equal(t, "150.5", exportedTodos[1].Position.String())
+		assert.Equal(t, []string{"home", "shopping"}, exportedTodos[0].Tags)
+		assert.Empty(t, exportedTodos[1].Tags)
+
+		records, err := csv.NewReader(bytes.NewReader(files["lists.csv"])).ReadAll()
+		require.NoError(t, err)
+		assert.Equal(t, [][]string{
+			{"id", "name", "inbox", "archived_at", "created_at", "updated_at"},
+			{"1", "Inbox", "true", "", "2024-01-01T00:00:00Z", "2024-01-01T00:00:00Z"},
+			{"2", "Errands", "false", "2024-01-01T00:00:00Z", "2024-01-01T00:00:00Z", "2024-01-01T00:00:00Z"},
+		}, records)
+
+		records, err = csv.NewReader(bytes.NewReader(files["todos.csv"])).ReadAll()
+		require.NoError(t, err)
+		assert.Equal(t, [][]string{
+			{"id", "list_id", "description", "position", "completed", "due_at", "remind_at", "recurrence", "priority", "language", "tags", "created_at", "updated_at"},
+			{"1", "1", "buy milk", "100", "true", "", "", "", "none", "english", "home;shopping", "2024-01-01T00:00:00Z", "2024-01-01T00:00:00Z"},
+			{"2", "2", "call \"Bob\", then Alice", "150.5", "false", "", "", "", "high", "english", "", "2024-01-01T00:00:00Z", "2024-01-01T00:00:00Z"},
+		}, records)
+
+		records, err = csv.NewReader(bytes.NewReader(files["todo_items.csv"])).ReadAll()
+		require.NoError(t, err)
+		assert.Equal(t, [][]string{
+			{"todo_id", "id", "description", "position", "completed", "created_at", "updated_at"},
+			{"1", "1", "oat milk", "100", "true", "2024-01-01T00:00:00Z", "2024-01-01T00:00:00Z"},
+		}, records)
+	})
+
+	t.Run("WriteExport_NoTodos", func(t *testing.T) {
+		ctx := context.Background()
+		var buf bytes.Buffer
+
+		mockQueries.EXPECT().
+			GetUserByUserID(ctx, uIDUuid).
+			Return(user, nil)
+
+		// No preferences saved yet
+		mockQueries.EXPECT().
+			GetUserPreferences(ctx, int32(1)).
+			Return(db.UserPreference{}, pgx.ErrNoRows)
+
+		mockQueries.EXPECT().
+			ListLists(ctx, gomock.Any()).
+			Return(nil, nil)
+
+		mockQueries.EXPECT().
+			StreamTodos(ctx, int32(1), gomock.Any()).
+			Return(nil).
+			Times(2)
+
+		mockQueries.EXPECT().
+			ListTodoItemsByUser(ctx, int32(1)).
+			Return(nil, nil)
+
+		err := exportService.WriteExport(ctx, uIDUuid, &buf)
+		require.NoError(t, err)
+
+		files := readZip(t, buf.Bytes())
+		var exportedTodos []services.ExportTodo
+		require.NoError(t, json.Unmarshal(files["todos.json"], &exportedTodos))
+		assert.Empty(t, exportedTodos)
+
+		var profile services.ExportProfile
+		require.NoError(t, json.Unmarshal(files["profile.json"], &profile))
+		assert.Equal(t, services.ExportPreferences{
+			TimeZone:        "UTC",
+			Locale:          "en",
+			DefaultTodoSort: "position",
+			WeekStart:       services.WeekStartMonday,
+		}, profile.Preferences)
+	})
+
+	t.Run("WriteExport_AvatarMissing", func(t *testing.T) {
+		ctx := context.Background()
+		var buf bytes.Buffer
+
+		mockQueries.EXPECT().
+			GetUserByUserID(ctx, uIDUuid).
+			Return(user, nil)
+
+		mockQueries.EXPECT().
+			GetUserPreferences(ctx, int32(1)).
+			Return(prefs, nil)
+
+		// Left out of the ZIP and the profile instead of failing the export
+		mockBlobStore.EXPECT().
+			Get(ctx, "avatars/"+uIDStr).
+			Return(nil, fs.ErrNotExist)
+
+		mockQueries.EXPECT().
+			ListLists(ctx, gomock.Any()).
+			Return(nil, nil)
+
+		mockQueries.EXPECT().
+			StreamTodos(ctx, int32(1), gomock.Any()).
+			Return(nil).
+			Times(2)
+
+		mockQueries.EXPECT().
+			ListTodoItemsByUser(ctx, int32(1)).
+			Return(nil, nil)
+
+		err := exportService.WriteExport(ctx, uIDUuid, &buf)
+		require.NoError(t, err)
+
+		files := readZip(t, buf.Bytes())
+		assert.Len(t, files, 5)
+
+		var profile services.ExportProfile
+		require.NoError(t, json.Unmarshal(files["profile.json"], &profile))
+		assert.Nil(t, profile.Preferences.Avatar)
+		assert.Equal(t, "Asia/Tokyo", profile.Preferences.TimeZone)
+	})
+
+	t.Run("WriteExport_UserNotFound", func(t *testing.T) {
+		ctx := context.Background()
+		var buf bytes.Buffer
+
+		mockQueries.EXPECT().
+			GetUserByUserID(ctx, uIDUuid).
+			Return(db.User{}, pgx.ErrNoRows)
+
+		err := exportService.WriteExport(ctx, uIDUuid, &buf)
+
+		assert.Equal(t, utils.ErrNoRowsMatchedSQLC, err)
+		assert.Zero(t, buf.Len())
+	})
+
+	t.Run("WriteExport_StreamError", func(t *testing.T) {
+		ctx := context.Background()
+		var buf bytes.Buffer
+
+		mockQueries.EXPECT().
+			GetUserByUserID(ctx, uIDUuid).
+			Return(user, nil)
+
+		mockQueries.EXPECT().
+			GetUserPreferences(ctx, int32(1)).
+			Return(db.UserPreference{}, pgx.ErrNoRows)
+
+		mockQueries.EXPECT().
+			ListLists(ctx, gomock.Any()).
+			Return(nil, nil)
+
+		mockQueries.EXPECT().
+			StreamTodos(ctx, int32(1), gomock.Any()).
+			Return(errors.New("fetch failed"))
+
+		err := exportService.WriteExport(ctx, uIDUuid, &buf)
+
+		assert.Error(t, err)
+	})
+}
+
+func readZip(t *testing.T, b []byte) map[string][]byte {
+	zr, err := zip.NewReader(bytes.NewReader(b), int64(len(b)))
+	require.NoError(t, err)
+
+	files := make(map[string][]byte, len(zr.File))
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		require.NoError(t, err)
+		content, err := io.ReadAll(rc)
+		require.NoError(t, err)
+		rc.Close()
+		files[f.Name] = content
+	}
+	return files
+}
@@ -1,9 +1,14 @@
 package services
 
 import (
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"os"
 	"strconv"
 	"time"
+	"todo-app/internal/utils"
 
 	"github.com/golang-jwt/jwt/v5"
 )
@@ -15,47 +20,322 @@ https://zenn.dev/ritou/articles/4a5d6597a5f250#%E3%80%8C%E3%82%BB%E3%83%83%E3%82
 
 const issuer = "example_issuer"
 
+// Only access tokens can be used to access protected resources
+const TokenTypeAccess = "access"
+const TokenTypeEmailVerification = "email_verification"
+const TokenTypeEmailChange = "email_change"
+const TokenTypeTwoFactorChallenge = "2fa_challenge"
+const TokenTypeTodoCursor = "todo_cursor"
+
+// Access tokens name the API as their audience and are the only ones that do, so that services checking tokens
+// against the JWKS can tell identity tokens from the other tokens signed with the same keyset
+const accessTokenAudience = "todo_api"
+
+// Challenge tokens prove only the password step of a login, so like cursors they are signed with a key of their own
+// that is never published, and name this audience
+const twoFactorChallengeAudience = TokenTypeTwoFactorChallenge
+
+// Emailed tokens name their type as the audience, so that nothing checking tokens against the JWKS and the audience
+// takes one for an identity token
+const emailVerificationAudience = TokenTypeEmailVerification
+const emailChangeAudience = TokenTypeEmailChange
+
+// Cursors are meant for paging through todos in one sitting
+const todoCursorLifeSpan = 24 * time.Hour
+
+// Cursors are signed with their own HMAC key rather than the published keyset and name this audience,
+// so that nothing checking tokens against the JWKS takes a cursor for an identity token
+const todoCursorAudience = "todo_cursor"
+
+const serverKeyBytes = 32
+
+const refreshTokenBytes = 32
+
 type JWTCustomClaims struct {
 	UserID    string `json:"user_id"`
 	SessionID string `json:"session_id"`
+	TokenType string `json:"token_type"`
+	Email     string `json:"email,omitempty"`
+	NewEmail  string `json:"new_email,omitempty"`
+	// Where the next page of todos starts
+	Cursor json.RawMessage `json:"cursor,omitempty"`
 	jwt.RegisteredClaims
 }
 
-type JWTer struct{}
+type JWTer struct {
+	KeySet *KeySet
+	// Only ever known to the server
+	TodoCursorKey         []byte
+	TwoFactorChallengeKey []byte
+}
+
+func NewJWTer(keySet *KeySet, todoCursorKey, twoFactorChallengeKey []byte) *JWTer {
+	return &JWTer{KeySet: keySet, TodoCursorKey: todoCursorKey, TwoFactorChallengeKey: twoFactorChallengeKey}
+}
 
-func NewJWTer() *JWTer {
-	return &JWTer{}
+// TODO_CURSOR_KEY, at least 32 bytes, shared by every API instance.
+// Without it a random key is used, so cursors stop working on restart and only work on the instance that issued them.
+func TodoCursorKeyFromEnv() ([]byte, error) {
+	return serverKeyFromEnv("TODO_CURSOR_KEY")
+}
+
+// TOTP_CHALLENGE_KEY, at least 32 bytes, shared by every API instance.
+// Without it a random key is used, so a login has to finish its second step on the instance that checked the password.
+func TwoFactorChallengeKeyFromEnv() ([]byte, error) {
+	return serverKeyFromEnv("TOTP_CHALLENGE_KEY")
+}
+
+func serverKeyFromEnv(name string) ([]byte, error) {
+	key := os.Getenv(name)
+	if key == "" {
+		key := make([]byte, serverKeyBytes)
+		if _, err := rand.Read(key); err != nil {
+			return nil, err
+		}
+		return key, nil
+	}
+	if len(key) < serverKeyBytes {
+		return nil, fmt.Errorf("%s must be at least %d bytes", name, serverKeyBytes)
+	}
+
+	return []byte(key), nil
+}
+
+// JWT_ACCESS_TOKEN_EXP_MINUTE, or else JWT_ACCESS_TOKEN_EXP_HOUR which it replaced, so that deployments still setting the old one keep their lifetime
+func accessTokenLifeSpan() (time.Duration, error) {
+	if hour := os.Getenv("JWT_ACCESS_TOKEN_EXP_HOUR"); os.Getenv("JWT_ACCESS_TOKEN_EXP_MINUTE") == "" && hour != "" {
+		tokenLifeSpanHour, err := strconv.Atoi(hour)
+		if err != nil {
+			return 0, err
+		}
+		return time.Hour * time.Duration(tokenLifeSpanHour), nil
+	}
+
+	tokenLifeSpanMinute, err := strconv.Atoi(os.Getenv("JWT_ACCESS_TOKEN_EXP_MINUTE"))
+	if err != nil {
+		return 0, err
+	}
+
+	return time.Minute * time.Duration(tokenLifeSpanMinute), nil
 }
 
 func (j *JWTer) GenerateToken(userID, sessionID string) (string, error) {
-	tokenLifeSpanHour, err := strconv.Atoi(os.Getenv("JWT_ACCESS_TOKEN_EXP_HOUR"))
+	tokenLifeSpan, err := accessTokenLifeSpan()
 	if err != nil {
 		return "", err
 	}
 
 	claims := &JWTCustomClaims{
-		userID,
-		sessionID,
-		jwt.RegisteredClaims{
+		UserID:    userID,
+		SessionID: sessionID,
+		TokenType: TokenTypeAccess,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(tokenLifeSpan)),
+			Issuer:    issuer,
+			Audience:  jwt.ClaimStrings{accessTokenAudience},
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+		},
+	}
+
+	return j.sign(claims)
+}
+
+// The email is included so that the token no longer verifies anything once the email has been changed
+func (j *JWTer) GenerateEmailVerificationToken(userID, email string) (string, error) {
+	tokenLifeSpanHour, err := strconv.Atoi(os.Getenv("EMAIL_VERIFICATION_TOKEN_EXP_HOUR"))
+	if err != nil {
+		return "", err
+	}
+
+	claims := &JWTCustomClaims{
+		UserID:    userID,
+		TokenType: TokenTypeEmailVerification,
+		Email:     email,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour * time.Duration(tokenLifeSpanHour))),
+			Issuer:    issuer,
+			Audience:  jwt.ClaimStrings{emailVerificationAudience},
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+		},
+	}
+
+	return j.sign(claims)
+}
+
+// Confirms that the user owns the new email; the current email is included so that the token is useless once the email has changed.
+// It shares the lifetime of the email verification token.
+func (j *JWTer) GenerateEmailChangeToken(userID, email, newEmail string) (string, error) {
+	tokenLifeSpanHour, err := strconv.Atoi(os.Getenv("EMAIL_VERIFICATION_TOKEN_EXP_HOUR"))
+	if err != nil {
+		return "", err
+	}
+
+	claims := &JWTCustomClaims{
+		UserID:    userID,
+		TokenType: TokenTypeEmailChange,
+		Email:     email,
+		NewEmail:  newEmail,
+		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour * time.Duration(tokenLifeSpanHour))),
 			Issuer:    issuer,
+			Audience:  jwt.ClaimStrings{emailChangeAudience},
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+		},
+	}
+
+	return j.sign(claims)
+}
+
+// Proves that the password step of a login succeeded; it is bound to the session the login started in
+func (j *JWTer) GenerateTwoFactorChallengeToken(userID, sessionID string) (string, error) {
+	tokenLifeSpanMinute, err := strconv.Atoi(os.Getenv("TOTP_CHALLENGE_TOKEN_EXP_MINUTE"))
+	if err != nil {
+		return "", err
+	}
+
+	claims := &JWTCustomClaims{
+		UserID:    userID,
+		SessionID: sessionID,
+		TokenType: TokenTypeTwoFactorChallenge,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute * time.Duration(tokenLifeSpanMinute))),
+			Issuer:    issuer,
+			Audience:  jwt.ClaimStrings{twoFactorChallengeAudience},
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(j.TwoFactorChallengeKey)
+}
 
-	return token.SignedString([]byte(os.Getenv("JWT_SECRET")))
+// Signed so that clients cannot craft the keyset values; it is bound to the user it was issued to
+func (j *JWTer) GenerateTodoCursor(userID string, cursor json.RawMessage) (string, error) {
+	claims := &JWTCustomClaims{
+		UserID:    userID,
+		TokenType: TokenTypeTodoCursor,
+		Cursor:    cursor,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(todoCursorLifeSpan)),
+			Issuer:    issuer,
+			Audience:  jwt.ClaimStrings{todoCursorAudience},
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+		},
+	}
+
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(j.TodoCursorKey)
 }
 
+func (j *JWTer) sign(claims *JWTCustomClaims) (string, error) {
+	signingKey := j.KeySet.SigningKey()
+	token := jwt.NewWithClaims(signingKey.Method, claims)
+	token.Header["kid"] = signingKey.Kid
+	return token.SignedString(signingKey.PrivateKey)
+}
+
+// Refresh tokens are opaque rather than JWT so that they can only be checked against the server-side store
+func (j *JWTer) GenerateRefreshToken() (string, time.Time, error) {
+	tokenLifeSpanHour, err := strconv.Atoi(os.Getenv("JWT_REFRESH_TOKEN_EXP_HOUR"))
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	token, err := utils.GenerateRandomToken(refreshTokenBytes)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	return token, time.Now().Add(time.Hour * time.Duration(tokenLifeSpanHour)), nil
+}
+
+// Only accepts access tokens, which are the only ones naming the API as their audience
 func (j *JWTer) ValidateToken(tokenString string) (*JWTCustomClaims, error) {
+	claims, err := j.parse(tokenString, jwt.WithAudience(accessTokenAudience))
+	if err != nil {
+		return nil, err
+	}
+	if claims.TokenType != TokenTypeAccess {
+		return nil, errors.New("invalid token")
+	}
+
+	return claims, nil
+}
+
+// Only accepts an email verification or email change token, whose audience is its type
+func (j *JWTer) ValidateEmailToken(tokenString, tokenType string) (*JWTCustomClaims, error) {
+	if tokenType != TokenTypeEmailVerification && tokenType != TokenTypeEmailChange {
+		return nil, fmt.Errorf("%q is not an email token type", tokenType)
+	}
+
+	claims, err := j.parse(tokenString, jwt.WithAudience(tokenType))
+	if err != nil {
+		return nil, err
+	}
+	if claims.TokenType != tokenType {
+		return nil, errors.New("invalid token")
+	}
+
+	return claims, nil
+}
+
+// Checks a token signed with the keyset, along with any further options such as the audience
+func (j *JWTer) parse(tokenString string, opts ...jwt.ParserOption) (*JWTCustomClaims, error) {
+	opts = append(opts, jwt.WithValidMethods([]string{jwt.SigningMethodRS256.Alg(), jwt.SigningMethodEdDSA.Alg()}), jwt.WithIssuer(issuer))
+
+	// Any key in the keyset is accepted as long as the algorithm matches the one of the key
 	token, err := jwt.ParseWithClaims(tokenString, &JWTCustomClaims{}, func(token *jwt.Token) (interface{}, error) {
-		return []byte(os.Getenv("JWT_SECRET")), nil
-	})
+		kid, ok := token.Header["kid"].(string)
+		if !ok {
+			return nil, ErrUnknownKeyID
+		}
+
+		key, err := j.KeySet.Key(kid)
+		if err != nil {
+			return nil, err
+		}
+
+		if token.Method.Alg() != key.Method.Alg() {
+			return nil, fmt.Errorf("unexpected signing method %q for key %q", token.Method.Alg(), kid)
+		}
+
+		return key.PublicKey, nil
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
 
 	if claims, ok := token.Claims.(*JWTCustomClaims); ok && token.Valid {
 		return claims, nil
 	}
 
-	return nil, err
+	return nil, errors.New("invalid token")
+}
+
+// Only accepts cursors, which ValidateToken never does as they are not signed with the keyset
+func (j *JWTer) ValidateTodoCursor(tokenString string) (*JWTCustomClaims, error) {
+	return parseServerToken(tokenString, j.TodoCursorKey, todoCursorAudience, TokenTypeTodoCursor)
+}
+
+// Only accepts challenge tokens, which ValidateToken never does as they are not signed with the keyset
+func (j *JWTer) ValidateTwoFactorChallengeToken(tokenString string) (*JWTCustomClaims, error) {
+	return parseServerToken(tokenString, j.TwoFactorChallengeKey, twoFactorChallengeAudience, TokenTypeTwoFactorChallenge)
+}
+
+// Checks a token signed with one of the server-only keys, which has to name the audience and type given
+func parseServerToken(tokenString string, key []byte, audience, tokenType string) (*JWTCustomClaims, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &JWTCustomClaims{}, func(token *jwt.Token) (interface{}, error) {
+		return key, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithIssuer(issuer), jwt.WithAudience(audience))
+	if err != nil {
+		return nil, err
+	}
+
+	if claims, ok := token.Claims.(*JWTCustomClaims); ok && token.Valid && claims.TokenType == tokenType {
+		return claims, nil
+	}
+
+	return nil, fmt.Errorf("invalid %s token", tokenType)
+}
+
+func (j *JWTer) JWKS() *JWKS {
+	return j.KeySet.JWKS()
 }
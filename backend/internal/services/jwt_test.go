@@ -0,0 +1,402 @@
+package services_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+	"todo-app/internal/services"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writePrivateKey(t *testing.T, dir, kid string, key any) {
+	t.Helper()
+
+	b, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, kid+".pem"), pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: b}), 0o600))
+}
+
+func writePublicKey(t *testing.T, dir, kid string, key any) {
+	t.Helper()
+
+	b, err := x509.MarshalPKIXPublicKey(key)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, kid+".pem"), pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: b}), 0o600))
+}
+
+func TestJWTer(t *testing.T) {
+	t.Setenv("JWT_ACCESS_TOKEN_EXP_MINUTE", "15")
+	t.Setenv("EMAIL_VERIFICATION_TOKEN_EXP_HOUR", "24")
+	t.Setenv("TOTP_CHALLENGE_TOKEN_EXP_MINUTE", "5")
+
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	_, retiredKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	writePrivateKey(t, dir, "ed-2024", edKey)
+	writePrivateKey(t, dir, "rsa-2024", rsaKey)
+	writePublicKey(t, dir, "retired", retiredKey.Public())
+
+	uID := "00010203-0405-0607-0809-0a0b0c0d0e0f"
+	sID := "session-id-123"
+	cursorKey := []byte("todo-cursor-key-of-at-least-32-bytes")
+	challengeKey := []byte("2fa-challenge-key-of-at-least-32-bytes")
+
+	t.Run("GenerateToken_EdDSA", func(t *testing.T) {
+		keySet, err := services.LoadKeySet(dir, "ed-2024")
+		require.NoError(t, err)
+		jwter := services.NewJWTer(keySet, cursorKey, challengeKey)
+
+		token, err := jwter.GenerateToken(uID, sID)
+		require.NoError(t, err)
+
+		parsed, _, err := jwt.NewParser().ParseUnverified(token, &services.JWTCustomClaims{})
+		require.NoError(t, err)
+		assert.Equal(t, "ed-2024", parsed.Header["kid"])
+		assert.Equal(t, "EdDSA", parsed.Header["alg"])
+
+		claims, err := jwter.ValidateToken(token)
+		require.NoError(t, err)
+		assert.Equal(t, uID, claims.UserID)
+		assert.Equal(t, sID, claims.SessionID)
+		assert.Equal(t, services.TokenTypeAccess, claims.TokenType)
+		assert.Equal(t, jwt.ClaimStrings{"todo_api"}, claims.Audience)
+	})
+
+	t.Run("GenerateToken_LegacyHourLifetime", func(t *testing.T) {
+		keySet, err := services.LoadKeySet(dir, "ed-2024")
+		require.NoError(t, err)
+		jwter := services.NewJWTer(keySet, cursorKey, challengeKey)
+
+		// Deployments still setting only the hour variable keep their lifetime
+		t.Setenv("JWT_ACCESS_TOKEN_EXP_MINUTE", "")
+		t.Setenv("JWT_ACCESS_TOKEN_EXP_HOUR", "2")
+		token, err := jwter.GenerateToken(uID, sID)
+		require.NoError(t, err)
+		claims, err := jwter.ValidateToken(token)
+		require.NoError(t, err)
+		assert.WithinDuration(t, time.Now().Add(2*time.Hour), claims.ExpiresAt.Time, time.Minute)
+
+		// The minute variable wins when both are set
+		t.Setenv("JWT_ACCESS_TOKEN_EXP_MINUTE", "15")
+		token, err = jwter.GenerateToken(uID, sID)
+		require.NoError(t, err)
+		claims, err = jwter.ValidateToken(token)
+		require.NoError(t, err)
+		assert.WithinDuration(t, time.Now().Add(15*time.Minute), claims.ExpiresAt.Time, time.Minute)
+	})
+
+	t.Run("GenerateEmailVerificationToken", func(t *testing.T) {
+		keySet, err := services.LoadKeySet(dir, "ed-2024")
+		require.NoError(t, err)
+		jwter := services.NewJWTer(keySet, cursorKey, challengeKey)
+
+		token, err := jwter.GenerateEmailVerificationToken(uID, "test@example.com")
+		require.NoError(t, err)
+
+		claims, err := jwter.ValidateEmailToken(token, services.TokenTypeEmailVerification)
+		require.NoError(t, err)
+		assert.Equal(t, uID, claims.UserID)
+		assert.Equal(t, "test@example.com", claims.Email)
+		assert.Equal(t, services.TokenTypeEmailVerification, claims.TokenType)
+		assert.Equal(t, jwt.ClaimStrings{services.TokenTypeEmailVerification}, claims.Audience)
+		assert.Empty(t, claims.SessionID)
+
+		_, err = jwter.ValidateEmailToken(token, services.TokenTypeEmailChange)
+		assert.Error(t, err)
+	})
+
+	t.Run("GenerateEmailChangeToken", func(t *testing.T) {
+		keySet, err := services.LoadKeySet(dir, "ed-2024")
+		require.NoError(t, err)
+		jwter := services.NewJWTer(keySet, cursorKey, challengeKey)
+
+		token, err := jwter.GenerateEmailChangeToken(uID, "test@example.com", "new@example.com")
+		require.NoError(t, err)
+
+		claims, err := jwter.ValidateEmailToken(token, services.TokenTypeEmailChange)
+		require.NoError(t, err)
+		assert.Equal(t, uID, claims.UserID)
+		assert.Equal(t, "test@example.com", claims.Email)
+		assert.Equal(t, "new@example.com", claims.NewEmail)
+		assert.Equal(t, services.TokenTypeEmailChange, claims.TokenType)
+		assert.Equal(t, jwt.ClaimStrings{services.TokenTypeEmailChange}, claims.Audience)
+
+		_, err = jwter.ValidateEmailToken(token, services.TokenTypeEmailVerification)
+		assert.Error(t, err)
+	})
+
+	t.Run("ValidateEmailToken_Invalid", func(t *testing.T) {
+		keySet, err := services.LoadKeySet(dir, "ed-2024")
+		require.NoError(t, err)
+		jwter := services.NewJWTer(keySet, cursorKey, challengeKey)
+
+		accessToken, err := jwter.GenerateToken(uID, sID)
+		require.NoError(t, err)
+		// Signed with the keyset and of the right type, but naming no audience
+		withoutAudience := jwt.NewWithClaims(jwt.SigningMethodEdDSA, &services.JWTCustomClaims{
+			UserID:           uID,
+			TokenType:        services.TokenTypeEmailVerification,
+			Email:            "test@example.com",
+			RegisteredClaims: jwt.RegisteredClaims{Issuer: "example_issuer"},
+		})
+		withoutAudience.Header["kid"] = "ed-2024"
+		signed, err := withoutAudience.SignedString(edKey)
+		require.NoError(t, err)
+
+		for _, token := range []string{accessToken, signed} {
+			claims, err := jwter.ValidateEmailToken(token, services.TokenTypeEmailVerification)
+
+			assert.Error(t, err)
+			assert.Nil(t, claims)
+		}
+	})
+
+	t.Run("GenerateTodoCursor", func(t *testing.T) {
+		keySet, err := services.LoadKeySet(dir, "ed-2024")
+		require.NoError(t, err)
+		jwter := services.NewJWTer(keySet, cursorKey, challengeKey)
+
+		token, err := jwter.GenerateTodoCursor(uID, []byte(`{"sort":"position","id":7}`))
+		require.NoError(t, err)
+
+		claims, err := jwter.ValidateTodoCursor(token)
+		require.NoError(t, err)
+		assert.Equal(t, uID, claims.UserID)
+		assert.JSONEq(t, `{"sort":"position","id":7}`, string(claims.Cursor))
+		assert.Equal(t, services.TokenTypeTodoCursor, claims.TokenType)
+		assert.Equal(t, jwt.ClaimStrings{"todo_cursor"}, claims.Audience)
+		assert.Empty(t, claims.SessionID)
+
+		// Not signed with the keyset, so it is no identity token
+		parsed, _, err := jwt.NewParser().ParseUnverified(token, &services.JWTCustomClaims{})
+		require.NoError(t, err)
+		assert.Equal(t, "HS256", parsed.Header["alg"])
+		assert.Nil(t, parsed.Header["kid"])
+		_, err = jwter.ValidateToken(token)
+		assert.Error(t, err)
+	})
+
+	t.Run("ValidateTodoCursor_Invalid", func(t *testing.T) {
+		keySet, err := services.LoadKeySet(dir, "ed-2024")
+		require.NoError(t, err)
+		jwter := services.NewJWTer(keySet, cursorKey, challengeKey)
+
+		accessToken, err := jwter.GenerateToken(uID, sID)
+		require.NoError(t, err)
+		otherCursor, err := services.NewJWTer(keySet, []byte("another-todo-cursor-key-of-32-bytes"), challengeKey).GenerateTodoCursor(uID, []byte(`{"sort":"position","id":7}`))
+		require.NoError(t, err)
+		withoutAudience, err := jwt.NewWithClaims(jwt.SigningMethodHS256, &services.JWTCustomClaims{
+			UserID:           uID,
+			TokenType:        services.TokenTypeTodoCursor,
+			RegisteredClaims: jwt.RegisteredClaims{Issuer: "example_issuer"},
+		}).SignedString(cursorKey)
+		require.NoError(t, err)
+
+		for _, token := range []string{accessToken, otherCursor, withoutAudience} {
+			claims, err := jwter.ValidateTodoCursor(token)
+
+			assert.Error(t, err)
+			assert.Nil(t, claims)
+		}
+	})
+
+	t.Run("TodoCursorKeyFromEnv", func(t *testing.T) {
+		t.Setenv("TODO_CURSOR_KEY", string(cursorKey))
+		key, err := services.TodoCursorKeyFromEnv()
+		require.NoError(t, err)
+		assert.Equal(t, cursorKey, key)
+
+		t.Setenv("TODO_CURSOR_KEY", "too-short")
+		_, err = services.TodoCursorKeyFromEnv()
+		assert.Error(t, err)
+
+		// A random key for a single instance
+		t.Setenv("TODO_CURSOR_KEY", "")
+		key, err = services.TodoCursorKeyFromEnv()
+		require.NoError(t, err)
+		assert.Len(t, key, 32)
+	})
+
+	t.Run("GenerateTwoFactorChallengeToken", func(t *testing.T) {
+		keySet, err := services.LoadKeySet(dir, "ed-2024")
+		require.NoError(t, err)
+		jwter := services.NewJWTer(keySet, cursorKey, challengeKey)
+
+		token, err := jwter.GenerateTwoFactorChallengeToken(uID, sID)
+		require.NoError(t, err)
+
+		claims, err := jwter.ValidateTwoFactorChallengeToken(token)
+		require.NoError(t, err)
+		assert.Equal(t, uID, claims.UserID)
+		assert.Equal(t, sID, claims.SessionID)
+		assert.Equal(t, services.TokenTypeTwoFactorChallenge, claims.TokenType)
+		assert.Equal(t, jwt.ClaimStrings{services.TokenTypeTwoFactorChallenge}, claims.Audience)
+
+		// Only the password step is behind it, so it is neither published nor an access token
+		parsed, _, err := jwt.NewParser().ParseUnverified(token, &services.JWTCustomClaims{})
+		require.NoError(t, err)
+		assert.Equal(t, "HS256", parsed.Header["alg"])
+		assert.Nil(t, parsed.Header["kid"])
+		_, err = jwter.ValidateToken(token)
+		assert.Error(t, err)
+	})
+
+	t.Run("ValidateTwoFactorChallengeToken_Invalid", func(t *testing.T) {
+		keySet, err := services.LoadKeySet(dir, "ed-2024")
+		require.NoError(t, err)
+		jwter := services.NewJWTer(keySet, cursorKey, challengeKey)
+
+		accessToken, err := jwter.GenerateToken(uID, sID)
+		require.NoError(t, err)
+		cursor, err := jwter.GenerateTodoCursor(uID, []byte(`{"sort":"position","id":7}`))
+		require.NoError(t, err)
+		otherChallenge, err := services.NewJWTer(keySet, cursorKey, []byte("another-2fa-challenge-key-of-32-bytes")).GenerateTwoFactorChallengeToken(uID, sID)
+		require.NoError(t, err)
+
+		for _, token := range []string{accessToken, cursor, otherChallenge} {
+			claims, err := jwter.ValidateTwoFactorChallengeToken(token)
+
+			assert.Error(t, err)
+			assert.Nil(t, claims)
+		}
+	})
+
+	t.Run("ValidateToken_OnlyAccessTokens", func(t *testing.T) {
+		keySet, err := services.LoadKeySet(dir, "ed-2024")
+		require.NoError(t, err)
+		jwter := services.NewJWTer(keySet, cursorKey, challengeKey)
+
+		verificationToken, err := jwter.GenerateEmailVerificationToken(uID, "test@example.com")
+		require.NoError(t, err)
+		emailChangeToken, err := jwter.GenerateEmailChangeToken(uID, "test@example.com", "new@example.com")
+		require.NoError(t, err)
+		challengeToken, err := jwter.GenerateTwoFactorChallengeToken(uID, sID)
+		require.NoError(t, err)
+		// Claims to be an access token and is signed with the keyset, but names no audience
+		withoutAudience := jwt.NewWithClaims(jwt.SigningMethodEdDSA, &services.JWTCustomClaims{
+			UserID:           uID,
+			SessionID:        sID,
+			TokenType:        services.TokenTypeAccess,
+			RegisteredClaims: jwt.RegisteredClaims{Issuer: "example_issuer"},
+		})
+		withoutAudience.Header["kid"] = "ed-2024"
+		signed, err := withoutAudience.SignedString(edKey)
+		require.NoError(t, err)
+
+		for _, token := range []string{verificationToken, emailChangeToken, challengeToken, signed} {
+			claims, err := jwter.ValidateToken(token)
+
+			assert.Error(t, err)
+			assert.Nil(t, claims)
+		}
+	})
+
+	t.Run("TwoFactorChallengeKeyFromEnv", func(t *testing.T) {
+		t.Setenv("TOTP_CHALLENGE_KEY", string(challengeKey))
+		key, err := services.TwoFactorChallengeKeyFromEnv()
+		require.NoError(t, err)
+		assert.Equal(t, challengeKey, key)
+
+		t.Setenv("TOTP_CHALLENGE_KEY", "too-short")
+		_, err = services.TwoFactorChallengeKeyFromEnv()
+		assert.Error(t, err)
+	})
+
+	t.Run("GenerateToken_RS256", func(t *testing.T) {
+		keySet, err := services.LoadKeySet(dir, "rsa-2024")
+		require.NoError(t, err)
+		jwter := services.NewJWTer(keySet, cursorKey, challengeKey)
+
+		token, err := jwter.GenerateToken(uID, sID)
+		require.NoError(t, err)
+
+		parsed, _, err := jwt.NewParser().ParseUnverified(token, &services.JWTCustomClaims{})
+		require.NoError(t, err)
+		assert.Equal(t, "rsa-2024", parsed.Header["kid"])
+		assert.Equal(t, "RS256", parsed.Header["alg"])
+
+		_, err = jwter.ValidateToken(token)
+		require.NoError(t, err)
+	})
+
+	t.Run("ValidateToken_AfterRotation", func(t *testing.T) {
+		oldKeySet, err := services.LoadKeySet(dir, "ed-2024")
+		require.NoError(t, err)
+		newKeySet, err := services.LoadKeySet(dir, "rsa-2024")
+		require.NoError(t, err)
+
+		token, err := services.NewJWTer(oldKeySet, cursorKey, challengeKey).GenerateToken(uID, sID)
+		require.NoError(t, err)
+
+		_, err = services.NewJWTer(newKeySet, cursorKey, challengeKey).ValidateToken(token)
+		require.NoError(t, err)
+	})
+
+	t.Run("ValidateToken_UnknownKey", func(t *testing.T) {
+		otherDir := t.TempDir()
+		_, otherKey, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+		writePrivateKey(t, otherDir, "other", otherKey)
+
+		otherKeySet, err := services.LoadKeySet(otherDir, "other")
+		require.NoError(t, err)
+		token, err := services.NewJWTer(otherKeySet, cursorKey, challengeKey).GenerateToken(uID, sID)
+		require.NoError(t, err)
+
+		keySet, err := services.LoadKeySet(dir, "ed-2024")
+		require.NoError(t, err)
+		claims, err := services.NewJWTer(keySet, cursorKey, challengeKey).ValidateToken(token)
+		assert.Error(t, err)
+		assert.Nil(t, claims)
+	})
+
+	t.Run("ValidateToken_AlgorithmMismatch", func(t *testing.T) {
+		keySet, err := services.LoadKeySet(dir, "ed-2024")
+		require.NoError(t, err)
+
+		// A token claiming the RSA key but signed with HS256 must never be accepted
+		token := jwt.NewWithClaims(jwt.SigningMethodHS256, &services.JWTCustomClaims{UserID: uID, SessionID: sID, TokenType: services.TokenTypeAccess})
+		token.Header["kid"] = "rsa-2024"
+		signed, err := token.SignedString([]byte("secret"))
+		require.NoError(t, err)
+
+		claims, err := services.NewJWTer(keySet, cursorKey, challengeKey).ValidateToken(signed)
+		assert.Error(t, err)
+		assert.Nil(t, claims)
+	})
+
+	t.Run("LoadKeySet_PublicOnlySigningKey", func(t *testing.T) {
+		keySet, err := services.LoadKeySet(dir, "retired")
+		assert.Error(t, err)
+		assert.Nil(t, keySet)
+	})
+
+	t.Run("JWKS", func(t *testing.T) {
+		keySet, err := services.LoadKeySet(dir, "ed-2024")
+		require.NoError(t, err)
+
+		jwks := services.NewJWTer(keySet, cursorKey, challengeKey).JWKS()
+		require.Len(t, jwks.Keys, 3)
+		assert.Equal(t, "ed-2024", jwks.Keys[0].Kid)
+		assert.Equal(t, "OKP", jwks.Keys[0].Kty)
+		assert.Equal(t, "Ed25519", jwks.Keys[0].Crv)
+		assert.Equal(t, "retired", jwks.Keys[1].Kid)
+		assert.Equal(t, "rsa-2024", jwks.Keys[2].Kid)
+		assert.Equal(t, "RSA", jwks.Keys[2].Kty)
+		assert.Equal(t, "AQAB", jwks.Keys[2].E)
+	})
+}
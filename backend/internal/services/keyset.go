@@ -0,0 +1,176 @@
+package services
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+/*
+The keyset is a local directory of PEM files named after their key ID (e.g. 2024-10.pem)
+Private keys (PKCS#8) can sign and verify, public keys (PKIX) can only verify
+To rotate, add a new key, point JWT_SIGNING_KID at it and keep the old one until tokens signed by it have expired
+(the old key can be replaced by its public half so that it can no longer sign)
+*/
+
+var ErrUnknownKeyID = errors.New("unknown key id")
+
+type SigningKey struct {
+	Kid        string
+	Method     jwt.SigningMethod
+	PrivateKey crypto.Signer
+	PublicKey  crypto.PublicKey
+}
+
+type KeySet struct {
+	keys       map[string]*SigningKey
+	signingKey *SigningKey
+}
+
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+func NewKeySet(keys []*SigningKey, signingKid string) (*KeySet, error) {
+	ks := &KeySet{keys: make(map[string]*SigningKey)}
+	for _, key := range keys {
+		if _, ok := ks.keys[key.Kid]; ok {
+			return nil, fmt.Errorf("duplicated key id %q", key.Kid)
+		}
+		ks.keys[key.Kid] = key
+	}
+
+	signingKey, ok := ks.keys[signingKid]
+	if !ok {
+		return nil, fmt.Errorf("signing key %q not found in the keyset", signingKid)
+	}
+	if signingKey.PrivateKey == nil {
+		return nil, fmt.Errorf("signing key %q has no private key", signingKid)
+	}
+	ks.signingKey = signingKey
+
+	return ks, nil
+}
+
+func LoadKeySet(dir, signingKid string) (*KeySet, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.pem"))
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]*SigningKey, 0, len(paths))
+	for _, path := range paths {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("reading key file: %w", err)
+		}
+
+		key, err := ParseSigningKey(strings.TrimSuffix(filepath.Base(path), ".pem"), b)
+		if err != nil {
+			return nil, fmt.Errorf("parsing key file %s: %w", path, err)
+		}
+		keys = append(keys, key)
+	}
+
+	return NewKeySet(keys, signingKid)
+}
+
+func ParseSigningKey(kid string, pemBytes []byte) (*SigningKey, error) {
+	block, _ := pem.Decode(pemBytes)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+
+	var parsed any
+	var err error
+	switch block.Type {
+	case "PRIVATE KEY":
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "PUBLIC KEY":
+		parsed, err = x509.ParsePKIXPublicKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("unsupported PEM block type %q", block.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	switch k := parsed.(type) {
+	case *rsa.PrivateKey:
+		return &SigningKey{Kid: kid, Method: jwt.SigningMethodRS256, PrivateKey: k, PublicKey: &k.PublicKey}, nil
+	case *rsa.PublicKey:
+		return &SigningKey{Kid: kid, Method: jwt.SigningMethodRS256, PublicKey: k}, nil
+	case ed25519.PrivateKey:
+		return &SigningKey{Kid: kid, Method: jwt.SigningMethodEdDSA, PrivateKey: k, PublicKey: k.Public()}, nil
+	case ed25519.PublicKey:
+		return &SigningKey{Kid: kid, Method: jwt.SigningMethodEdDSA, PublicKey: k}, nil
+	}
+
+	return nil, fmt.Errorf("unsupported key type %T", parsed)
+}
+
+func (ks *KeySet) SigningKey() *SigningKey {
+	return ks.signingKey
+}
+
+func (ks *KeySet) Key(kid string) (*SigningKey, error) {
+	key, ok := ks.keys[kid]
+	if !ok {
+		return nil, ErrUnknownKeyID
+	}
+
+	return key, nil
+}
+
+// Public halves of every key in the keyset, so that other services can verify tokens signed by any of them
+func (ks *KeySet) JWKS() *JWKS {
+	kids := make([]string, 0, len(ks.keys))
+	for kid := range ks.keys {
+		kids = append(kids, kid)
+	}
+	sort.Strings(kids)
+
+	jwks := &JWKS{Keys: make([]JWK, 0, len(kids))}
+	for _, kid := range kids {
+		key := ks.keys[kid]
+		jwk := JWK{Kid: kid, Use: "sig", Alg: key.Method.Alg()}
+
+		switch pub := key.PublicKey.(type) {
+		case *rsa.PublicKey:
+			jwk.Kty = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+		case ed25519.PublicKey:
+			jwk.Kty = "OKP"
+			jwk.Crv = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(pub)
+		}
+
+		jwks.Keys = append(jwks.Keys, jwk)
+	}
+
+	return jwks
+}
@@ -0,0 +1,165 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"todo-app/internal/db"
+	"todo-app/internal/utils"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type ListService struct {
+	SqlClient db.WrappedQuerier
+}
+
+type ListRequest struct {
+	Name string `json:"name" binding:"required,max=100"`
+}
+
+type ListListsRequest struct {
+	Archived bool `form:"archived"`
+}
+
+func NewListService(sqlClient db.WrappedQuerier) *ListService {
+	return &ListService{SqlClient: sqlClient}
+}
+
+func (s *ListService) CreateList(ctx context.Context, userID pgtype.UUID, req ListRequest) (*db.List, error) {
+	user, err := s.SqlClient.GetUserByUserID(ctx, userID)
+	if err != nil {
+		return nil, utils.ErrInvalidUID
+	}
+
+	list, err := s.SqlClient.CreateList(ctx, db.CreateListParams{
+		UserID: user.ID,
+		Name:   strings.TrimSpace(req.Name),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &list, nil
+}
+
+// Archived lists are left out unless asked for
+func (s *ListService) ListLists(ctx context.Context, userID pgtype.UUID, req ListListsRequest) ([]db.List, error) {
+	user, err := s.SqlClient.GetUserByUserID(ctx, userID)
+	if err != nil {
+		return nil, utils.ErrInvalidUID
+	}
+
+	lists, err := s.SqlClient.ListLists(ctx, db.ListListsParams{
+		UserID:          user.ID,
+		IncludeArchived: req.Archived,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return lists, nil
+}
+
+func (s *ListService) GetList(ctx context.Context, userID pgtype.UUID, listID int32) (*db.List, error) {
+	user, err := s.SqlClient.GetUserByUserID(ctx, userID)
+	if err != nil {
+		return nil, utils.ErrInvalidUID
+	}
+
+	list, err := s.SqlClient.GetList(ctx, db.GetListParams{ID: listID, UserID: user.ID})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, utils.ErrNoRowsMatchedSQLC
+		}
+		return nil, err
+	}
+
+	return &list, nil
+}
+
+func (s *ListService) UpdateList(ctx context.Context, userID pgtype.UUID, listID int32, req ListRequest) (*db.List, error) {
+	user, err := s.SqlClient.GetUserByUserID(ctx, userID)
+	if err != nil {
+		return nil, utils.ErrInvalidUID
+	}
+
+	list, err := s.SqlClient.UpdateList(ctx, db.UpdateListParams{
+		ID:     listID,
+		UserID: user.ID,
+		Name:   strings.TrimSpace(req.Name),
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, utils.ErrNoRowsMatchedSQLC
+		}
+		return nil, err
+	}
+
+	return &list, nil
+}
+
+// The todos of an archived list are hidden from ListTodos unless the list is asked for
+func (s *ListService) ArchiveList(ctx context.Context, userID pgtype.UUID, listID int32) (*db.List, error) {
+	return s.setArchived(ctx, userID, listID, true)
+}
+
+func (s *ListService) UnarchiveList(ctx context.Context, userID pgtype.UUID, listID int32) (*db.List, error) {
+	return s.setArchived(ctx, userID, listID, false)
+}
+
+func (s *ListService) setArchived(ctx context.Context, userID pgtype.UUID, listID int32, archived bool) (*db.List, error) {
+	user, err := s.SqlClient.GetUserByUserID(ctx, userID)
+	if err != nil {
+		return nil, utils.ErrInvalidUID
+	}
+
+	list, err := s.SqlClient.SetListArchived(ctx, db.SetListArchivedParams{
+		ID:       listID,
+		UserID:   user.ID,
+		Archived: archived,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, s.inboxOrNotFound(ctx, listID, user.ID)
+		}
+		return nil, err
+	}
+
+	return &list, nil
+}
+
+// Deletes the list along with its todos
+func (s *ListService) DeleteList(ctx context.Context, userID pgtype.UUID, listID int32) error {
+	user, err := s.SqlClient.GetUserByUserID(ctx, userID)
+	if err != nil {
+		return utils.ErrInvalidUID
+	}
+
+	rows, err := s.SqlClient.DeleteList(ctx, db.DeleteListParams{ID: listID, UserID: user.ID})
+	if err != nil {
+		return err
+	} else if rows == 0 {
+		return s.inboxOrNotFound(ctx, listID, user.ID)
+	}
+
+	return nil
+}
+
+// The queries leave the inbox alone, so a list they did not match is either the inbox or not there at all
+func (s *ListService) inboxOrNotFound(ctx context.Context, listID, userID int32) error {
+	list, err := s.SqlClient.GetList(ctx, db.GetListParams{ID: listID, UserID: userID})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return utils.ErrNoRowsMatchedSQLC
+		}
+		return err
+	}
+
+	if list.IsInbox {
+		return utils.ErrInboxList
+	}
+
+	return utils.ErrNoRowsMatchedSQLC
+}
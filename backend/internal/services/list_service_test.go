@@ -0,0 +1,209 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"todo-app/internal/db"
+	mock_db "todo-app/internal/db/_mock"
+	"todo-app/internal/services"
+	"todo-app/internal/utils"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestListService(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockQueries := mock_db.NewMockWrappedQuerier(ctrl)
+	listService := services.NewListService(mockQueries)
+
+	uIDStr := "00010203-0405-0607-0809-0a0b0c0d0e0f"
+	uIDUuid, _ := utils.StringToUUID(uIDStr)
+	var listID int32 = 20
+
+	expectUser := func(ctx context.Context) {
+		mockQueries.EXPECT().
+			GetUserByUserID(ctx, uIDUuid).
+			Return(db.User{ID: 1}, nil)
+	}
+
+	t.Run("CreateList", func(t *testing.T) {
+		ctx := context.Background()
+
+		expectUser(ctx)
+
+		mockQueries.EXPECT().
+			CreateList(ctx, db.CreateListParams{UserID: 1, Name: "Groceries"}).
+			Return(db.List{ID: listID, UserID: 1, Name: "Groceries"}, nil)
+
+		list, err := listService.CreateList(ctx, uIDUuid, services.ListRequest{Name: "  Groceries "})
+
+		require.NoError(t, err)
+		assert.Equal(t, "Groceries", list.Name)
+	})
+
+	t.Run("CreateList_UserNotFound", func(t *testing.T) {
+		ctx := context.Background()
+
+		mockQueries.EXPECT().
+			GetUserByUserID(ctx, uIDUuid).
+			Return(db.User{}, errors.New("user not found"))
+
+		list, err := listService.CreateList(ctx, uIDUuid, services.ListRequest{Name: "Groceries"})
+
+		assert.Equal(t, utils.ErrInvalidUID, err)
+		assert.Nil(t, list)
+	})
+
+	t.Run("ListLists", func(t *testing.T) {
+		ctx := context.Background()
+
+		expectUser(ctx)
+
+		mockQueries.EXPECT().
+			ListLists(ctx, db.ListListsParams{UserID: 1, IncludeArchived: true}).
+			Return([]db.List{{ID: 10, IsInbox: true}, {ID: listID}}, nil)
+
+		lists, err := listService.ListLists(ctx, uIDUuid, services.ListListsRequest{Archived: true})
+
+		require.NoError(t, err)
+		assert.Len(t, lists, 2)
+	})
+
+	t.Run("GetList_NotFound", func(t *testing.T) {
+		ctx := context.Background()
+
+		expectUser(ctx)
+
+		mockQueries.EXPECT().
+			GetList(ctx, db.GetListParams{ID: listID, UserID: 1}).
+			Return(db.List{}, pgx.ErrNoRows)
+
+		list, err := listService.GetList(ctx, uIDUuid, listID)
+
+		assert.Equal(t, utils.ErrNoRowsMatchedSQLC, err)
+		assert.Nil(t, list)
+	})
+
+	t.Run("UpdateList", func(t *testing.T) {
+		ctx := context.Background()
+
+		expectUser(ctx)
+
+		mockQueries.EXPECT().
+			UpdateList(ctx, db.UpdateListParams{ID: listID, UserID: 1, Name: "Shopping"}).
+			Return(db.List{ID: listID, UserID: 1, Name: "Shopping"}, nil)
+
+		list, err := listService.UpdateList(ctx, uIDUuid, listID, services.ListRequest{Name: "Shopping"})
+
+		require.NoError(t, err)
+		assert.Equal(t, "Shopping", list.Name)
+	})
+
+	t.Run("ArchiveList", func(t *testing.T) {
+		ctx := context.Background()
+		archivedAt := pgtype.Timestamptz{Time: time.Now(), Valid: true}
+
+		expectUser(ctx)
+
+		mockQueries.EXPECT().
+			SetListArchived(ctx, db.SetListArchivedParams{ID: listID, UserID: 1, Archived: true}).
+			Return(db.List{ID: listID, ArchivedAt: archivedAt}, nil)
+
+		list, err := listService.ArchiveList(ctx, uIDUuid, listID)
+
+		require.NoError(t, err)
+		assert.True(t, list.ArchivedAt.Valid)
+	})
+
+	t.Run("ArchiveList_Inbox", func(t *testing.T) {
+		ctx := context.Background()
+
+		expectUser(ctx)
+
+		mockQueries.EXPECT().
+			SetListArchived(ctx, db.SetListArchivedParams{ID: 10, UserID: 1, Archived: true}).
+			Return(db.List{}, pgx.ErrNoRows)
+
+		mockQueries.EXPECT().
+			GetList(ctx, db.GetListParams{ID: 10, UserID: 1}).
+			Return(db.List{ID: 10, IsInbox: true}, nil)
+
+		list, err := listService.ArchiveList(ctx, uIDUuid, 10)
+
+		assert.Equal(t, utils.ErrInboxList, err)
+		assert.Nil(t, list)
+	})
+
+	t.Run("UnarchiveList", func(t *testing.T) {
+		ctx := context.Background()
+
+		expectUser(ctx)
+
+		mockQueries.EXPECT().
+			SetListArchived(ctx, db.SetListArchivedParams{ID: listID, UserID: 1, Archived: false}).
+			Return(db.List{ID: listID}, nil)
+
+		list, err := listService.UnarchiveList(ctx, uIDUuid, listID)
+
+		require.NoError(t, err)
+		assert.False(t, list.ArchivedAt.Valid)
+	})
+
+	t.Run("DeleteList", func(t *testing.T) {
+		ctx := context.Background()
+
+		expectUser(ctx)
+
+		mockQueries.EXPECT().
+			DeleteList(ctx, db.DeleteListParams{ID: listID, UserID: 1}).
+			Return(int64(1), nil)
+
+		err := listService.DeleteList(ctx, uIDUuid, listID)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("DeleteList_Inbox", func(t *testing.T) {
+		ctx := context.Background()
+
+		expectUser(ctx)
+
+		mockQueries.EXPECT().
+			DeleteList(ctx, db.DeleteListParams{ID: 10, UserID: 1}).
+			Return(int64(0), nil)
+
+		mockQueries.EXPECT().
+			GetList(ctx, db.GetListParams{ID: 10, UserID: 1}).
+			Return(db.List{ID: 10, IsInbox: true}, nil)
+
+		err := listService.DeleteList(ctx, uIDUuid, 10)
+
+		assert.Equal(t, utils.ErrInboxList, err)
+	})
+
+	t.Run("DeleteList_NotFound", func(t *testing.T) {
+		ctx := context.Background()
+
+		expectUser(ctx)
+
+		mockQueries.EXPECT().
+			DeleteList(ctx, db.DeleteListParams{ID: 1000, UserID: 1}).
+			Return(int64(0), nil)
+
+		mockQueries.EXPECT().
+			GetList(ctx, db.GetListParams{ID: 1000, UserID: 1}).
+			Return(db.List{}, pgx.ErrNoRows)
+
+		err := listService.DeleteList(ctx, uIDUuid, 1000)
+
+		assert.Equal(t, utils.ErrNoRowsMatchedSQLC, err)
+	})
+}
@@ -0,0 +1,140 @@
+package services
+
+import (
+	"context"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+	"todo-app/internal/utils"
+)
+
+/*
+Failed logins are counted both per email and per client IP
+Once either reaches its limit, the key is locked and the lockout doubles on every further failure (exponential backoff)
+A successful login only resets the email counter, so that one valid account cannot clear the IP counter between guesses at others
+The same throttle counts password reset requests, so that the reset email cannot be used to flood a mailbox
+*/
+
+type LoginThrottle struct {
+	Store ILoginAttemptStore
+	// Keeps the counters of different throttles apart in the same store
+	KeyPrefix           string
+	MaxFailuresPerEmail int
+	MaxFailuresPerIP    int
+	FailureWindow       time.Duration
+	BaseLockout         time.Duration
+	MaxLockout          time.Duration
+}
+
+// Returned while a login is locked out, telling how long the client should wait
+type LoginLockedError struct {
+	RetryAfter time.Duration
+}
+
+func (e *LoginLockedError) Error() string {
+	return utils.ErrTooManyLoginAttempts.Error()
+}
+
+func (e *LoginLockedError) Unwrap() error {
+	return utils.ErrTooManyLoginAttempts
+}
+
+func NewLoginThrottle(store ILoginAttemptStore) *LoginThrottle {
+	return &LoginThrottle{
+		Store:               store,
+		MaxFailuresPerEmail: envInt("LOGIN_MAX_FAILURES_PER_EMAIL", 5),
+		MaxFailuresPerIP:    envInt("LOGIN_MAX_FAILURES_PER_IP", 20),
+		FailureWindow:       time.Duration(envInt("LOGIN_FAILURE_WINDOW_MINUTE", 15)) * time.Minute,
+		BaseLockout:         time.Duration(envInt("LOGIN_LOCKOUT_BASE_SECOND", 30)) * time.Second,
+		MaxLockout:          time.Duration(envInt("LOGIN_LOCKOUT_MAX_MINUTE", 60)) * time.Minute,
+	}
+}
+
+// Password reset requests all count, as each one sends an email. Reaching a limit locks out further requests for a window and longer.
+func NewPasswordResetThrottle(store ILoginAttemptStore) *LoginThrottle {
+	window := time.Duration(envInt("PASSWORD_RESET_REQUEST_WINDOW_MINUTE", 60)) * time.Minute
+	return &LoginThrottle{
+		Store:               store,
+		KeyPrefix:           "password_reset:",
+		MaxFailuresPerEmail: envInt("PASSWORD_RESET_MAX_REQUESTS_PER_EMAIL", 3),
+		MaxFailuresPerIP:    envInt("PASSWORD_RESET_MAX_REQUESTS_PER_IP", 10),
+		FailureWindow:       window,
+		BaseLockout:         window,
+		MaxLockout:          24 * time.Hour,
+	}
+}
+
+// Returns *LoginLockedError if either the email or the IP is locked out
+func (t *LoginThrottle) Check(ctx context.Context, email, ip string) error {
+	var lockedUntil time.Time
+	for _, key := range t.keys(email, ip) {
+		until, err := t.Store.LockedUntil(ctx, key)
+		if err != nil {
+			return err
+		}
+		if until.After(lockedUntil) {
+			lockedUntil = until
+		}
+	}
+
+	if retryAfter := time.Until(lockedUntil); retryAfter > 0 {
+		return &LoginLockedError{RetryAfter: retryAfter}
+	}
+
+	return nil
+}
+
+func (t *LoginThrottle) RecordFailure(ctx context.Context, email, ip string) error {
+	limits := []int{t.MaxFailuresPerEmail, t.MaxFailuresPerIP}
+	for i, key := range t.keys(email, ip) {
+		failures, err := t.Store.RecordFailure(ctx, key, t.FailureWindow)
+		if err != nil {
+			return err
+		}
+
+		if failures < limits[i] {
+			continue
+		}
+
+		if err := t.Store.Lock(ctx, key, time.Now().Add(t.lockout(failures-limits[i]))); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (t *LoginThrottle) Reset(ctx context.Context, email string) error {
+	return t.Store.Reset(ctx, t.keys(email, "")[0])
+}
+
+// BaseLockout, 2*BaseLockout, 4*BaseLockout, ... up to MaxLockout
+func (t *LoginThrottle) lockout(excess int) time.Duration {
+	d := float64(t.BaseLockout) * math.Pow(2, float64(excess))
+	if d > float64(t.MaxLockout) {
+		return t.MaxLockout
+	}
+
+	return time.Duration(d)
+}
+
+// The email key always comes first, followed by the IP key if the IP is known
+func (t *LoginThrottle) keys(email, ip string) []string {
+	keys := []string{t.KeyPrefix + "email:" + strings.ToLower(email)}
+	if ip != "" {
+		keys = append(keys, t.KeyPrefix+"ip:"+ip)
+	}
+
+	return keys
+}
+
+func envInt(key string, fallback int) int {
+	v, err := strconv.Atoi(os.Getenv(key))
+	if err != nil || v <= 0 {
+		return fallback
+	}
+
+	return v
+}
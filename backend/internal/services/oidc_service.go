@@ -0,0 +1,174 @@
+package services
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"time"
+	"todo-app/internal/db"
+	"todo-app/internal/oidc"
+	"todo-app/internal/utils"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type OIDCService struct {
+	SqlClient         db.WrappedQuerier
+	TokenGenerator    ITokenGenerator
+	RefreshTokenStore IRefreshTokenStore
+	SessionStore      ISessionStore
+	Providers         map[string]IOIDCProvider
+	// How long logging in still restores an account whose deletion was requested
+	DeletionGracePeriod time.Duration
+}
+
+// Values generated when the login starts, which have to be kept (in the session) until the provider redirects back
+type OIDCAuthState struct {
+	Provider     string
+	State        string
+	Nonce        string
+	CodeVerifier string
+}
+
+type OIDCAuthRequest struct {
+	URL       string
+	AuthState OIDCAuthState
+}
+
+type OIDCCallbackRequest struct {
+	Code  string `form:"code" binding:"required"`
+	State string `form:"state" binding:"required"`
+}
+
+func NewOIDCService(sqlClient db.WrappedQuerier, jwter ITokenGenerator, refreshTokenStore IRefreshTokenStore, sessionStore ISessionStore, providers map[string]IOIDCProvider) *OIDCService {
+	return &OIDCService{SqlClient: sqlClient, TokenGenerator: jwter, RefreshTokenStore: refreshTokenStore, SessionStore: sessionStore, Providers: providers, DeletionGracePeriod: AccountDeletionGracePeriodFromEnv()}
+}
+
+func (s *OIDCService) StartLogin(ctx context.Context, providerName string) (*OIDCAuthRequest, error) {
+	provider, ok := s.Providers[providerName]
+	if !ok {
+		return nil, utils.ErrUnknownOIDCProvider
+	}
+
+	authState := OIDCAuthState{Provider: providerName}
+	for _, v := range []*string{&authState.State, &authState.Nonce, &authState.CodeVerifier} {
+		token, err := utils.GenerateRandomToken(32)
+		if err != nil {
+			return nil, err
+		}
+		*v = token
+	}
+
+	url, err := provider.AuthCodeURL(ctx, authState.State, authState.Nonce, oidc.CodeChallengeS256(authState.CodeVerifier))
+	if err != nil {
+		return nil, err
+	}
+
+	return &OIDCAuthRequest{URL: url, AuthState: authState}, nil
+}
+
+// Completes the login once the provider has redirected back with the authorization code
+// The user is looked up by the identity, then linked by a verified email, or created otherwise
+func (s *OIDCService) Login(ctx context.Context, providerName string, req OIDCCallbackRequest, authState OIDCAuthState, sessionID string, client ClientInfo) (string, *TokenPair, error) {
+	provider, ok := s.Providers[providerName]
+	if !ok {
+		return "", nil, utils.ErrUnknownOIDCProvider
+	}
+
+	// The state ties the callback to the login started in this session (CSRF protection)
+	if authState.State == "" || authState.Provider != providerName || subtle.ConstantTimeCompare([]byte(authState.State), []byte(req.State)) != 1 {
+		return "", nil, utils.ErrInvalidOIDCState
+	}
+
+	claims, err := provider.Exchange(ctx, req.Code, authState.CodeVerifier, authState.Nonce)
+	if err != nil {
+		return "", nil, fmt.Errorf("%w: %v", utils.ErrOIDCLoginFailed, err)
+	}
+
+	user, err := s.findOrCreateUser(ctx, providerName, claims)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if user.DisabledAt.Valid {
+		return "", nil, utils.ErrAccountDisabled
+	}
+
+	userIDStr := utils.UUIDToString(user.UserID)
+	if userIDStr == "" {
+		return "", nil, errors.New("failed to convert uuid to string")
+	}
+
+	// The provider only replaces the password, so the second factor is still required
+	if user.TotpEnabledAt.Valid {
+		challengeToken, err := s.TokenGenerator.GenerateTwoFactorChallengeToken(userIDStr, sessionID)
+		if err != nil {
+			return "", nil, err
+		}
+		return "", nil, &TwoFactorRequiredError{ChallengeToken: challengeToken}
+	}
+
+	if err = restoreOnLogin(ctx, s.SqlClient, *user, s.DeletionGracePeriod); err != nil {
+		return "", nil, err
+	}
+
+	return issueTokens(ctx, s.TokenGenerator, s.RefreshTokenStore, s.SessionStore, userIDStr, sessionID, client)
+}
+
+func (s *OIDCService) findOrCreateUser(ctx context.Context, providerName string, claims *oidc.Claims) (*db.User, error) {
+	identity, err := s.SqlClient.GetUserIdentity(ctx, db.GetUserIdentityParams{
+		Provider: providerName,
+		Subject:  claims.Subject,
+	})
+	if err == nil {
+		user, err := s.SqlClient.GetUserByID(ctx, identity.UserID)
+		if err != nil {
+			return nil, err
+		}
+		return &user, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return nil, err
+	}
+
+	// Linking by an unverified email would let anyone sign in to an account by claiming its email at the provider
+	if claims.Email == "" || !claims.EmailVerified {
+		return nil, utils.ErrOIDCEmailNotVerified
+	}
+
+	user, err := s.SqlClient.GetUserByEmail(ctx, claims.Email)
+	exists := err == nil
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return nil, err
+	}
+	if exists && !user.EmailVerifiedAt.Valid {
+		// Whoever registered the unverified account may not own the email, and would keep access through its password
+		return nil, utils.ErrOIDCAccountNotLinkable
+	}
+
+	// A new user is created along with its identity, so that a failed link does not leave an account
+	// that the next login with the provider runs into by email
+	err = s.SqlClient.ExecTx(ctx, func(q db.WrappedQuerier) error {
+		if !exists {
+			var err error
+			user, err = q.CreateOIDCUser(ctx, claims.Email)
+			if err != nil {
+				return err
+			}
+		}
+
+		_, err := q.CreateUserIdentity(ctx, db.CreateUserIdentityParams{
+			UserID:   user.ID,
+			Provider: providerName,
+			Subject:  claims.Subject,
+			Email:    claims.Email,
+		})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
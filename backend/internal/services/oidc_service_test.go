@@ -0,0 +1,342 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"todo-app/internal/db"
+	mock_db "todo-app/internal/db/_mock"
+	"todo-app/internal/oidc"
+	"todo-app/internal/services"
+	mock_services "todo-app/internal/services/_mock"
+	"todo-app/internal/utils"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestOIDCService(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockQueries := mock_db.NewMockWrappedQuerier(ctrl)
+	mockTokenGen := mock_services.NewMockITokenGenerator(ctrl)
+	mockRefreshTokenStore := mock_services.NewMockIRefreshTokenStore(ctrl)
+	mockSessionStore := mock_services.NewMockISessionStore(ctrl)
+	mockProvider := mock_services.NewMockIOIDCProvider(ctrl)
+
+	oidcService := services.NewOIDCService(mockQueries, mockTokenGen, mockRefreshTokenStore, mockSessionStore, map[string]services.IOIDCProvider{"google": mockProvider})
+
+	uIDStr := "00010203-0405-0607-0809-0a0b0c0d0e0f"
+	uIDUuid, _ := utils.StringToUUID(uIDStr)
+	sessionID := "session-id-123"
+	client := services.ClientInfo{UserAgent: "test-agent", IP: "192.0.2.1"}
+	token := "access-token-123"
+	refreshToken := "refresh-token-123"
+	refreshTokenExp := time.Now().Add(time.Hour)
+	authState := services.OIDCAuthState{Provider: "google", State: "state-123", Nonce: "nonce-123", CodeVerifier: "verifier-123"}
+	req := services.OIDCCallbackRequest{Code: "code-123", State: "state-123"}
+	claims := &oidc.Claims{
+		Email:            "test@example.com",
+		EmailVerified:    true,
+		RegisteredClaims: jwt.RegisteredClaims{Subject: "subject-123"},
+	}
+	verifiedUser := db.User{ID: 1, UserID: uIDUuid, Email: "test@example.com", EmailVerifiedAt: pgtype.Timestamptz{Time: time.Now(), Valid: true}}
+	identityParams := db.GetUserIdentityParams{Provider: "google", Subject: "subject-123"}
+
+	expectTx := func(ctx context.Context) {
+		mockQueries.EXPECT().
+			ExecTx(ctx, gomock.Any()).
+			DoAndReturn(func(ctx context.Context, fn func(q db.WrappedQuerier) error) error {
+				return fn(mockQueries)
+			})
+	}
+
+	expectIssueTokens := func(ctx context.Context) {
+		mockTokenGen.EXPECT().
+			GenerateToken(uIDStr, sessionID).
+			Return(token, nil)
+
+		mockTokenGen.EXPECT().
+			GenerateRefreshToken().
+			Return(refreshToken, refreshTokenExp, nil)
+
+		mockRefreshTokenStore.EXPECT().
+			Save(ctx, utils.HashToken(refreshToken), db.RefreshToken{
+				UserID:    uIDStr,
+				SessionID: sessionID,
+				ExpiresAt: refreshTokenExp,
+			}).
+			Return(nil)
+
+		mockSessionStore.EXPECT().
+			Create(ctx, gomock.Any(), refreshTokenExp).
+			Return(nil)
+	}
+
+	t.Run("StartLogin", func(t *testing.T) {
+		ctx := context.Background()
+
+		mockProvider.EXPECT().
+			AuthCodeURL(ctx, gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, state, nonce, codeChallenge string) (string, error) {
+				assert.NotEmpty(t, state)
+				assert.NotEmpty(t, nonce)
+				assert.NotEqual(t, state, nonce)
+				return "https://accounts.example.com/authorize?state=" + state, nil
+			})
+
+		authReq, err := oidcService.StartLogin(ctx, "google")
+
+		require.NoError(t, err)
+		assert.Equal(t, "google", authReq.AuthState.Provider)
+		assert.Equal(t, "https://accounts.example.com/authorize?state="+authReq.AuthState.State, authReq.URL)
+		assert.NotEmpty(t, authReq.AuthState.CodeVerifier)
+	})
+
+	t.Run("StartLogin_UnknownProvider", func(t *testing.T) {
+		ctx := context.Background()
+
+		authReq, err := oidcService.StartLogin(ctx, "unknown")
+
+		assert.Equal(t, utils.ErrUnknownOIDCProvider, err)
+		assert.Nil(t, authReq)
+	})
+
+	t.Run("Login_ExistingIdentity", func(t *testing.T) {
+		ctx := context.Background()
+
+		mockProvider.EXPECT().
+			Exchange(ctx, req.Code, authState.CodeVerifier, authState.Nonce).
+			Return(claims, nil)
+
+		mockQueries.EXPECT().
+			GetUserIdentity(ctx, identityParams).
+			Return(db.UserIdentity{UserID: 1, Provider: "google", Subject: "subject-123"}, nil)
+
+		mockQueries.EXPECT().
+			GetUserByID(ctx, int32(1)).
+			Return(verifiedUser, nil)
+
+		expectIssueTokens(ctx)
+
+		userID, tokens, err := oidcService.Login(ctx, "google", req, authState, sessionID, client)
+
+		require.NoError(t, err)
+		assert.Equal(t, uIDStr, userID)
+		assert.Equal(t, token, tokens.AccessToken)
+		assert.Equal(t, refreshToken, tokens.RefreshToken)
+	})
+
+	t.Run("Login_LinksVerifiedUser", func(t *testing.T) {
+		ctx := context.Background()
+
+		mockProvider.EXPECT().
+			Exchange(ctx, req.Code, authState.CodeVerifier, authState.Nonce).
+			Return(claims, nil)
+
+		mockQueries.EXPECT().
+			GetUserIdentity(ctx, identityParams).
+			Return(db.UserIdentity{}, pgx.ErrNoRows)
+
+		mockQueries.EXPECT().
+			GetUserByEmail(ctx, claims.Email).
+			Return(verifiedUser, nil)
+
+		expectTx(ctx)
+
+		mockQueries.EXPECT().
+			CreateUserIdentity(ctx, db.CreateUserIdentityParams{UserID: 1, Provider: "google", Subject: "subject-123", Email: claims.Email}).
+			Return(db.UserIdentity{}, nil)
+
+		expectIssueTokens(ctx)
+
+		userID, _, err := oidcService.Login(ctx, "google", req, authState, sessionID, client)
+
+		require.NoError(t, err)
+		assert.Equal(t, uIDStr, userID)
+	})
+
+	t.Run("Login_CreatesUser", func(t *testing.T) {
+		ctx := context.Background()
+
+		mockProvider.EXPECT().
+			Exchange(ctx, req.Code, authState.CodeVerifier, authState.Nonce).
+			Return(claims, nil)
+
+		mockQueries.EXPECT().
+			GetUserIdentity(ctx, identityParams).
+			Return(db.UserIdentity{}, pgx.ErrNoRows)
+
+		mockQueries.EXPECT().
+			GetUserByEmail(ctx, claims.Email).
+			Return(db.User{}, pgx.ErrNoRows)
+
+		expectTx(ctx)
+
+		mockQueries.EXPECT().
+			CreateOIDCUser(ctx, claims.Email).
+			Return(verifiedUser, nil)
+
+		mockQueries.EXPECT().
+			CreateUserIdentity(ctx, db.CreateUserIdentityParams{UserID: 1, Provider: "google", Subject: "subject-123", Email: claims.Email}).
+			Return(db.UserIdentity{}, nil)
+
+		expectIssueTokens(ctx)
+
+		userID, _, err := oidcService.Login(ctx, "google", req, authState, sessionID, client)
+
+		require.NoError(t, err)
+		assert.Equal(t, uIDStr, userID)
+	})
+
+	t.Run("Login_CreatesUser_LinkFails", func(t *testing.T) {
+		ctx := context.Background()
+		linkErr := errors.New("link failed")
+
+		mockProvider.EXPECT().
+			Exchange(ctx, req.Code, authState.CodeVerifier, authState.Nonce).
+			Return(claims, nil)
+
+		mockQueries.EXPECT().
+			GetUserIdentity(ctx, identityParams).
+			Return(db.UserIdentity{}, pgx.ErrNoRows)
+
+		mockQueries.EXPECT().
+			GetUserByEmail(ctx, claims.Email).
+			Return(db.User{}, pgx.ErrNoRows)
+
+		// Returning the error rolls back the transaction, and the new user with it
+		mockQueries.EXPECT().
+			ExecTx(ctx, gomock.Any()).
+			DoAndReturn(func(ctx context.Context, fn func(q db.WrappedQuerier) error) error {
+				err := fn(mockQueries)
+				assert.ErrorIs(t, err, linkErr)
+				return err
+			})
+
+		mockQueries.EXPECT().
+			CreateOIDCUser(ctx, claims.Email).
+			Return(verifiedUser, nil)
+
+		mockQueries.EXPECT().
+			CreateUserIdentity(ctx, db.CreateUserIdentityParams{UserID: 1, Provider: "google", Subject: "subject-123", Email: claims.Email}).
+			Return(db.UserIdentity{}, linkErr)
+
+		_, _, err := oidcService.Login(ctx, "google", req, authState, sessionID, client)
+
+		assert.ErrorIs(t, err, linkErr)
+	})
+
+	t.Run("Login_EmailNotVerified", func(t *testing.T) {
+		ctx := context.Background()
+		unverifiedClaims := *claims
+		unverifiedClaims.EmailVerified = false
+
+		mockProvider.EXPECT().
+			Exchange(ctx, req.Code, authState.CodeVerifier, authState.Nonce).
+			Return(&unverifiedClaims, nil)
+
+		mockQueries.EXPECT().
+			GetUserIdentity(ctx, identityParams).
+			Return(db.UserIdentity{}, pgx.ErrNoRows)
+
+		userID, tokens, err := oidcService.Login(ctx, "google", req, authState, sessionID, client)
+
+		assert.Equal(t, utils.ErrOIDCEmailNotVerified, err)
+		assert.Empty(t, userID)
+		assert.Nil(t, tokens)
+	})
+
+	t.Run("Login_AccountNotLinkable", func(t *testing.T) {
+		ctx := context.Background()
+
+		mockProvider.EXPECT().
+			Exchange(ctx, req.Code, authState.CodeVerifier, authState.Nonce).
+			Return(claims, nil)
+
+		mockQueries.EXPECT().
+			GetUserIdentity(ctx, identityParams).
+			Return(db.UserIdentity{}, pgx.ErrNoRows)
+
+		mockQueries.EXPECT().
+			GetUserByEmail(ctx, claims.Email).
+			Return(db.User{ID: 1, UserID: uIDUuid, Email: claims.Email}, nil)
+
+		userID, tokens, err := oidcService.Login(ctx, "google", req, authState, sessionID, client)
+
+		assert.Equal(t, utils.ErrOIDCAccountNotLinkable, err)
+		assert.Empty(t, userID)
+		assert.Nil(t, tokens)
+	})
+
+	t.Run("Login_StateMismatch", func(t *testing.T) {
+		ctx := context.Background()
+		forgedReq := services.OIDCCallbackRequest{Code: "code-123", State: "forged-state"}
+
+		userID, tokens, err := oidcService.Login(ctx, "google", forgedReq, authState, sessionID, client)
+
+		assert.Equal(t, utils.ErrInvalidOIDCState, err)
+		assert.Empty(t, userID)
+		assert.Nil(t, tokens)
+	})
+
+	t.Run("Login_NoPendingLogin", func(t *testing.T) {
+		ctx := context.Background()
+
+		userID, tokens, err := oidcService.Login(ctx, "google", req, services.OIDCAuthState{}, sessionID, client)
+
+		assert.Equal(t, utils.ErrInvalidOIDCState, err)
+		assert.Empty(t, userID)
+		assert.Nil(t, tokens)
+	})
+
+	t.Run("Login_ExchangeFailed", func(t *testing.T) {
+		ctx := context.Background()
+
+		mockProvider.EXPECT().
+			Exchange(ctx, req.Code, authState.CodeVerifier, authState.Nonce).
+			Return(nil, oidc.ErrNonceMismatch)
+
+		userID, tokens, err := oidcService.Login(ctx, "google", req, authState, sessionID, client)
+
+		assert.ErrorIs(t, err, utils.ErrOIDCLoginFailed)
+		assert.Empty(t, userID)
+		assert.Nil(t, tokens)
+	})
+
+	t.Run("Login_TwoFactorRequired", func(t *testing.T) {
+		ctx := context.Background()
+		totpUser := verifiedUser
+		totpUser.TotpEnabledAt = pgtype.Timestamptz{Time: time.Now(), Valid: true}
+
+		mockProvider.EXPECT().
+			Exchange(ctx, req.Code, authState.CodeVerifier, authState.Nonce).
+			Return(claims, nil)
+
+		mockQueries.EXPECT().
+			GetUserIdentity(ctx, identityParams).
+			Return(db.UserIdentity{UserID: 1, Provider: "google", Subject: "subject-123"}, nil)
+
+		mockQueries.EXPECT().
+			GetUserByID(ctx, int32(1)).
+			Return(totpUser, nil)
+
+		mockTokenGen.EXPECT().
+			GenerateTwoFactorChallengeToken(uIDStr, sessionID).
+			Return("challenge-token-123", nil)
+
+		userID, tokens, err := oidcService.Login(ctx, "google", req, authState, sessionID, client)
+
+		var twoFactorErr *services.TwoFactorRequiredError
+		require.True(t, errors.As(err, &twoFactorErr))
+		assert.Equal(t, "challenge-token-123", twoFactorErr.ChallengeToken)
+		assert.Empty(t, userID)
+		assert.Nil(t, tokens)
+	})
+}
@@ -1,17 +1,160 @@
 package services
 
-import "golang.org/x/crypto/bcrypt"
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
 
-type DefaultPasswordHasher struct{}
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var ErrMismatchedHashAndPassword = errors.New("hashed password does not match the password")
+var ErrUnsupportedPasswordHash = errors.New("unsupported password hash format")
+
+// Hashes with bcrypt; kept for deployments which have not moved to Argon2id yet
+type DefaultPasswordHasher struct {
+	Cost int
+}
 
 func NewDefaultPasswordHasher() *DefaultPasswordHasher {
-	return &DefaultPasswordHasher{}
+	return &DefaultPasswordHasher{Cost: bcrypt.DefaultCost}
 }
 
-func (h *DefaultPasswordHasher) GenerateFromPassword(password []byte, cost int) ([]byte, error) {
-	return bcrypt.GenerateFromPassword(password, cost)
+func (h *DefaultPasswordHasher) GenerateFromPassword(password []byte) ([]byte, error) {
+	return bcrypt.GenerateFromPassword(password, h.Cost)
 }
 
 func (h *DefaultPasswordHasher) CompareHashAndPassword(hashedPassword []byte, password []byte) error {
 	return bcrypt.CompareHashAndPassword(hashedPassword, password)
 }
+
+func (h *DefaultPasswordHasher) NeedsRehash(hashedPassword []byte) bool {
+	cost, err := bcrypt.Cost(hashedPassword)
+	return err != nil || cost != h.Cost
+}
+
+/*
+Hashes with Argon2id and encodes the result in the PHC string format
+($argon2id$v=19$m=<memory KiB>,t=<iterations>,p=<parallelism>$<salt>$<hash>)
+bcrypt hashes created before the switch can still be verified, and NeedsRehash reports them so that they are upgraded on login
+*/
+type Argon2idPasswordHasher struct {
+	Memory      uint32
+	Iterations  uint32
+	Parallelism uint8
+	SaltLength  uint32
+	KeyLength   uint32
+}
+
+type argon2idHash struct {
+	memory      uint32
+	iterations  uint32
+	parallelism uint8
+	salt        []byte
+	key         []byte
+}
+
+// Defaults follow the OWASP recommendation (19 MiB, 2 iterations, 1 degree of parallelism)
+func NewArgon2idPasswordHasher() *Argon2idPasswordHasher {
+	return &Argon2idPasswordHasher{
+		Memory:      19 * 1024,
+		Iterations:  2,
+		Parallelism: 1,
+		SaltLength:  16,
+		KeyLength:   32,
+	}
+}
+
+func (h *Argon2idPasswordHasher) GenerateFromPassword(password []byte) ([]byte, error) {
+	salt := make([]byte, h.SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+
+	key := argon2.IDKey(password, salt, h.Iterations, h.Memory, h.Parallelism, h.KeyLength)
+
+	encoded := fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version, h.Memory, h.Iterations, h.Parallelism,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	)
+
+	return []byte(encoded), nil
+}
+
+func (h *Argon2idPasswordHasher) CompareHashAndPassword(hashedPassword []byte, password []byte) error {
+	if isBcryptHash(hashedPassword) {
+		if err := bcrypt.CompareHashAndPassword(hashedPassword, password); err != nil {
+			if err == bcrypt.ErrMismatchedHashAndPassword {
+				return ErrMismatchedHashAndPassword
+			}
+			return err
+		}
+		return nil
+	}
+
+	parsed, err := parseArgon2idHash(hashedPassword)
+	if err != nil {
+		return err
+	}
+
+	key := argon2.IDKey(password, parsed.salt, parsed.iterations, parsed.memory, parsed.parallelism, uint32(len(parsed.key)))
+	if subtle.ConstantTimeCompare(key, parsed.key) != 1 {
+		return ErrMismatchedHashAndPassword
+	}
+
+	return nil
+}
+
+// Reports bcrypt hashes as well as Argon2id hashes created with other parameters
+func (h *Argon2idPasswordHasher) NeedsRehash(hashedPassword []byte) bool {
+	parsed, err := parseArgon2idHash(hashedPassword)
+	if err != nil {
+		return true
+	}
+
+	return parsed.memory != h.Memory ||
+		parsed.iterations != h.Iterations ||
+		parsed.parallelism != h.Parallelism ||
+		uint32(len(parsed.salt)) != h.SaltLength ||
+		uint32(len(parsed.key)) != h.KeyLength
+}
+
+func isBcryptHash(hashedPassword []byte) bool {
+	return bytes.HasPrefix(hashedPassword, []byte("$2a$")) ||
+		bytes.HasPrefix(hashedPassword, []byte("$2b$")) ||
+		bytes.HasPrefix(hashedPassword, []byte("$2y$"))
+}
+
+func parseArgon2idHash(hashedPassword []byte) (*argon2idHash, error) {
+	// "", "argon2id", "v=19", "m=...,t=...,p=...", salt, key
+	parts := strings.Split(string(hashedPassword), "$")
+	if len(parts) != 6 || parts[1] != "argon2id" {
+		return nil, ErrUnsupportedPasswordHash
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return nil, ErrUnsupportedPasswordHash
+	}
+
+	var parsed argon2idHash
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &parsed.memory, &parsed.iterations, &parsed.parallelism); err != nil {
+		return nil, ErrUnsupportedPasswordHash
+	}
+
+	var err error
+	if parsed.salt, err = base64.RawStdEncoding.DecodeString(parts[4]); err != nil {
+		return nil, ErrUnsupportedPasswordHash
+	}
+	if parsed.key, err = base64.RawStdEncoding.DecodeString(parts[5]); err != nil || len(parsed.key) == 0 {
+		return nil, ErrUnsupportedPasswordHash
+	}
+
+	return &parsed, nil
+}
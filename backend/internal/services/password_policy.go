@@ -0,0 +1,179 @@
+package services
+
+import (
+	"bufio"
+	"bytes"
+	_ "embed"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"todo-app/internal/utils"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Most common passwords first (from the public SecLists collection); only the top PASSWORD_COMMON_LIST_SIZE entries are checked
+//
+//go:embed data/common_passwords.txt
+var commonPasswordsFile []byte
+
+// One entry per line, so this is both the default and the most PASSWORD_COMMON_LIST_SIZE can check
+var commonPasswordListLen = bytes.Count(commonPasswordsFile, []byte("\n"))
+
+const (
+	PasswordViolationTooShort  = "too_short"
+	PasswordViolationTooLong   = "too_long"
+	PasswordViolationCharClass = "missing_char_class"
+	PasswordViolationCommon    = "common_password"
+)
+
+const (
+	PasswordCharClassUpper  = "upper"
+	PasswordCharClassLower  = "lower"
+	PasswordCharClassDigit  = "digit"
+	PasswordCharClassSymbol = "symbol"
+)
+
+type PasswordPolicy struct {
+	MinLength       int
+	MaxLength       int
+	RequiredClasses []string
+	commonPasswords map[string]struct{}
+}
+
+type PasswordViolation struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// Returned when a password does not satisfy the policy, listing every rule it breaks
+type PasswordPolicyError struct {
+	Violations []PasswordViolation
+}
+
+func (e *PasswordPolicyError) Error() string {
+	return utils.ErrWeakPassword.Error()
+}
+
+func (e *PasswordPolicyError) Unwrap() error {
+	return utils.ErrWeakPassword
+}
+
+func NewPasswordPolicy(minLength, maxLength int, requiredClasses []string, commonListSize int) *PasswordPolicy {
+	return &PasswordPolicy{
+		MinLength:       minLength,
+		MaxLength:       maxLength,
+		RequiredClasses: requiredClasses,
+		commonPasswords: loadCommonPasswords(commonListSize),
+	}
+}
+
+/*
+Configured through PASSWORD_MIN_LENGTH (default 8), PASSWORD_MAX_LENGTH (default 128),
+PASSWORD_REQUIRED_CLASSES (comma separated upper, lower, digit and symbol; default lower,digit)
+and PASSWORD_COMMON_LIST_SIZE (default and at most the length of the bundled list)
+*/
+func NewPasswordPolicyFromEnv() (*PasswordPolicy, error) {
+	classes := os.Getenv("PASSWORD_REQUIRED_CLASSES")
+	if classes == "" {
+		classes = PasswordCharClassLower + "," + PasswordCharClassDigit
+	}
+
+	var requiredClasses []string
+	for _, class := range strings.Split(classes, ",") {
+		class = strings.TrimSpace(class)
+		switch class {
+		case PasswordCharClassUpper, PasswordCharClassLower, PasswordCharClassDigit, PasswordCharClassSymbol:
+			requiredClasses = append(requiredClasses, class)
+		default:
+			return nil, fmt.Errorf("unknown password character class %q", class)
+		}
+	}
+
+	commonListSize := envInt("PASSWORD_COMMON_LIST_SIZE", commonPasswordListLen)
+	if commonListSize > commonPasswordListLen {
+		log.Printf("PASSWORD_COMMON_LIST_SIZE is %d, but the bundled list only has %d entries", commonListSize, commonPasswordListLen)
+		commonListSize = commonPasswordListLen
+	}
+
+	return NewPasswordPolicy(
+		envInt("PASSWORD_MIN_LENGTH", 8),
+		envInt("PASSWORD_MAX_LENGTH", 128),
+		requiredClasses,
+		commonListSize,
+	), nil
+}
+
+// Returns *PasswordPolicyError if the password breaks any rule
+func (p *PasswordPolicy) Validate(password string) error {
+	var violations []PasswordViolation
+
+	length := utf8.RuneCountInString(password)
+	if length < p.MinLength {
+		violations = append(violations, PasswordViolation{
+			Code:    PasswordViolationTooShort,
+			Message: fmt.Sprintf("Password must be at least %d characters long", p.MinLength),
+		})
+	}
+	if length > p.MaxLength {
+		violations = append(violations, PasswordViolation{
+			Code:    PasswordViolationTooLong,
+			Message: fmt.Sprintf("Password must be at most %d characters long", p.MaxLength),
+		})
+	}
+
+	present := passwordCharClasses(password)
+	for _, class := range p.RequiredClasses {
+		if !present[class] {
+			violations = append(violations, PasswordViolation{
+				Code:    PasswordViolationCharClass,
+				Message: fmt.Sprintf("Password must contain at least one %s character", class),
+			})
+		}
+	}
+
+	if _, ok := p.commonPasswords[strings.ToLower(password)]; ok {
+		violations = append(violations, PasswordViolation{
+			Code:    PasswordViolationCommon,
+			Message: "Password is too common",
+		})
+	}
+
+	if len(violations) > 0 {
+		return &PasswordPolicyError{Violations: violations}
+	}
+
+	return nil
+}
+
+func passwordCharClasses(password string) map[string]bool {
+	present := make(map[string]bool)
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			present[PasswordCharClassUpper] = true
+		case unicode.IsLower(r):
+			present[PasswordCharClassLower] = true
+		case unicode.IsDigit(r):
+			present[PasswordCharClassDigit] = true
+		default:
+			present[PasswordCharClassSymbol] = true
+		}
+	}
+
+	return present
+}
+
+func loadCommonPasswords(size int) map[string]struct{} {
+	passwords := make(map[string]struct{}, size)
+
+	scanner := bufio.NewScanner(bytes.NewReader(commonPasswordsFile))
+	for i := 0; i < size && scanner.Scan(); i++ {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			passwords[strings.ToLower(line)] = struct{}{}
+		}
+	}
+
+	return passwords
+}
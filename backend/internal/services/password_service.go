@@ -0,0 +1,184 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"time"
+	"todo-app/internal/db"
+	"todo-app/internal/mailer"
+	"todo-app/internal/utils"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type PasswordService struct {
+	SqlClient         db.WrappedQuerier
+	PasswordHasher    IPasswordHasher
+	PasswordPolicy    *PasswordPolicy
+	RefreshTokenStore IRefreshTokenStore
+	SessionStore      ISessionStore
+	Mailer            IMailer
+	ResetThrottle     *LoginThrottle
+}
+
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" binding:"required"`
+	NewPassword     string `json:"new_password" binding:"required"`
+}
+
+type ForgotPasswordRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+type ResetPasswordRequest struct {
+	Token       string `json:"token" binding:"required"`
+	NewPassword string `json:"new_password" binding:"required"`
+}
+
+func NewPasswordService(sqlClient db.WrappedQuerier, passHasher IPasswordHasher, passwordPolicy *PasswordPolicy, refreshTokenStore IRefreshTokenStore, sessionStore ISessionStore, mailer IMailer, resetThrottle *LoginThrottle) *PasswordService {
+	return &PasswordService{SqlClient: sqlClient, PasswordHasher: passHasher, PasswordPolicy: passwordPolicy, RefreshTokenStore: refreshTokenStore, SessionStore: sessionStore, Mailer: mailer, ResetThrottle: resetThrottle}
+}
+
+// Requires the current password and signs out every other session of the user
+func (s *PasswordService) ChangePassword(ctx context.Context, userID pgtype.UUID, sessionID string, req ChangePasswordRequest) error {
+	user, err := s.SqlClient.GetUserByUserID(ctx, userID)
+	if err != nil {
+		return utils.ErrInvalidUID
+	}
+
+	if err = s.PasswordHasher.CompareHashAndPassword(user.PasswordHash, []byte(req.CurrentPassword)); err != nil {
+		return utils.ErrInvalidCurrentPassword
+	}
+
+	if err = s.setPassword(ctx, s.SqlClient, user, req.NewPassword); err != nil {
+		return err
+	}
+
+	return revokeSessions(ctx, s.RefreshTokenStore, s.SessionStore, utils.UUIDToString(user.UserID), sessionID)
+}
+
+// Always succeeds for an unknown email so that the response does not reveal which emails are registered.
+// For the same reason a registered email whose reset link cannot be sent only gets the failure logged.
+// Returns *LoginLockedError once the email or the client has asked too often, whether or not the email is registered.
+func (s *PasswordService) ForgotPassword(ctx context.Context, req ForgotPasswordRequest, client ClientInfo) error {
+	if err := s.ResetThrottle.Check(ctx, req.Email, client.IP); err != nil {
+		return err
+	}
+	if err := s.ResetThrottle.RecordFailure(ctx, req.Email, client.IP); err != nil {
+		return err
+	}
+
+	user, err := s.SqlClient.GetUserByEmail(ctx, req.Email)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil
+		}
+		return err
+	}
+
+	err = sendPasswordReset(ctx, s.SqlClient, s.Mailer, user,
+		"Someone requested a password reset for your account.",
+		"If you did not request this, you can ignore this email.",
+	)
+	if err != nil {
+		log.Printf("failed to send password reset to user %d: %v", user.ID, err)
+	}
+
+	return nil
+}
+
+// Consumes the reset token and signs out every session of the user
+// The token is only used up along with the password change, so that it can be tried again if the change fails
+func (s *PasswordService) ResetPassword(ctx context.Context, req ResetPasswordRequest) error {
+	// Check the policy first so that a rejected password does not use up the token
+	if err := s.PasswordPolicy.Validate(req.NewPassword); err != nil {
+		return err
+	}
+
+	var user db.User
+	err := s.SqlClient.ExecTx(ctx, func(q db.WrappedQuerier) error {
+		resetToken, err := q.ConsumePasswordResetToken(ctx, utils.HashToken(req.Token))
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return utils.ErrInvalidResetToken
+			}
+			return err
+		}
+
+		user, err = q.GetUserByID(ctx, resetToken.UserID)
+		if err != nil {
+			return err
+		}
+
+		return s.setPassword(ctx, q, user, req.NewPassword)
+	})
+	if err != nil {
+		return err
+	}
+
+	return revokeSessions(ctx, s.RefreshTokenStore, s.SessionStore, utils.UUIDToString(user.UserID), "")
+}
+
+func (s *PasswordService) setPassword(ctx context.Context, q db.WrappedQuerier, user db.User, password string) error {
+	if err := s.PasswordPolicy.Validate(password); err != nil {
+		return err
+	}
+
+	hashedPassword, err := s.PasswordHasher.GenerateFromPassword([]byte(password))
+	if err != nil {
+		return err
+	}
+
+	return q.UpdatePasswordHash(ctx, db.UpdatePasswordHashParams{
+		PasswordHash: hashedPassword,
+		UserID:       user.UserID,
+	})
+}
+
+// Emails a single-use reset link to the user, invalidating the links sent before
+// Shared by the forgot password flow and the reset forced by an admin, which differ only in the text around the link
+func sendPasswordReset(ctx context.Context, sqlClient db.WrappedQuerier, m IMailer, user db.User, intro, outro string) error {
+	tokenLifeSpanMinute, err := strconv.Atoi(os.Getenv("PASSWORD_RESET_TOKEN_EXP_MINUTE"))
+	if err != nil {
+		return fmt.Errorf("failed to obtain required parameter for password reset token: %w", err)
+	}
+
+	token, err := utils.GenerateRandomToken(32)
+	if err != nil {
+		return err
+	}
+
+	// Only the latest token is valid. Concurrent requests take turns on the user, so that each one sees the token of the one before.
+	err = sqlClient.ExecTx(ctx, func(q db.WrappedQuerier) error {
+		if err := q.LockUser(ctx, user.ID); err != nil {
+			return err
+		}
+		if err := q.InvalidatePasswordResetTokens(ctx, user.ID); err != nil {
+			return err
+		}
+
+		_, err := q.CreatePasswordResetToken(ctx, db.CreatePasswordResetTokenParams{
+			UserID:    user.ID,
+			TokenHash: utils.HashToken(token),
+			ExpiresAt: pgtype.Timestamptz{Time: time.Now().Add(time.Duration(tokenLifeSpanMinute) * time.Minute), Valid: true},
+		})
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	return m.Send(ctx, mailer.Message{
+		To:      user.Email,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf(
+			"%s\n\nOpen the link below within %d minutes to choose a new password:\n%s/reset-password?token=%s\n\n%s",
+			intro, tokenLifeSpanMinute, os.Getenv("FRONTEND_URL"), token, outro,
+		),
+	})
+}
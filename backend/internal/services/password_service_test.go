@@ -0,0 +1,349 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+	"todo-app/internal/db"
+	mock_db "todo-app/internal/db/_mock"
+	"todo-app/internal/mailer"
+	"todo-app/internal/services"
+	mock_services "todo-app/internal/services/_mock"
+	"todo-app/internal/utils"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestPasswordService(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockQueries := mock_db.NewMockWrappedQuerier(ctrl)
+	mockPassHasher := mock_services.NewMockIPasswordHasher(ctrl)
+	mockRefreshTokenStore := mock_services.NewMockIRefreshTokenStore(ctrl)
+	mockSessionStore := mock_services.NewMockISessionStore(ctrl)
+	mockMailer := mock_services.NewMockIMailer(ctrl)
+	passwordPolicy := services.NewPasswordPolicy(8, 128, []string{services.PasswordCharClassLower, services.PasswordCharClassDigit}, 1000)
+
+	resetThrottle := services.NewPasswordResetThrottle(db.NewMemoryLoginAttemptStore())
+
+	passwordService := services.NewPasswordService(mockQueries, mockPassHasher, passwordPolicy, mockRefreshTokenStore, mockSessionStore, mockMailer, resetThrottle)
+
+	uIDStr := "00010203-0405-0607-0809-0a0b0c0d0e0f"
+	uIDUuid, _ := utils.StringToUUID(uIDStr)
+	user := db.User{ID: 1, UserID: uIDUuid, Email: "test@example.com", PasswordHash: []byte("hashedpassword")}
+	currentSessionID := "session-id-123"
+	otherSessionID := "session-id-456"
+	newPassword := "new-password-42"
+	newHashedPassword := []byte("hashednewpassword")
+	client := services.ClientInfo{UserAgent: "test-agent", IP: "192.0.2.1"}
+
+	t.Setenv("PASSWORD_RESET_TOKEN_EXP_MINUTE", "30")
+	t.Setenv("FRONTEND_URL", "http://localhost:3000")
+
+	t.Run("ChangePassword", func(t *testing.T) {
+		ctx := context.Background()
+		req := services.ChangePasswordRequest{CurrentPassword: "current-password-1", NewPassword: newPassword}
+
+		mockQueries.EXPECT().
+			GetUserByUserID(ctx, uIDUuid).
+			Return(user, nil)
+
+		mockPassHasher.EXPECT().
+			CompareHashAndPassword(user.PasswordHash, []byte(req.CurrentPassword)).
+			Return(nil)
+
+		mockPassHasher.EXPECT().
+			GenerateFromPassword([]byte(newPassword)).
+			Return(newHashedPassword, nil)
+
+		mockQueries.EXPECT().
+			UpdatePasswordHash(ctx, db.UpdatePasswordHashParams{PasswordHash: newHashedPassword, UserID: uIDUuid}).
+			Return(nil)
+
+		mockSessionStore.EXPECT().
+			List(ctx, uIDStr).
+			Return([]db.Session{{ID: currentSessionID}, {ID: otherSessionID}}, nil)
+
+		// Only the other session is signed out
+		mockRefreshTokenStore.EXPECT().
+			RevokeFamily(ctx, otherSessionID).
+			Return(nil)
+
+		mockSessionStore.EXPECT().
+			Delete(ctx, uIDStr, otherSessionID).
+			Return(nil)
+
+		err := passwordService.ChangePassword(ctx, uIDUuid, currentSessionID, req)
+
+		require.NoError(t, err)
+	})
+
+	t.Run("ChangePassword_WrongCurrentPassword", func(t *testing.T) {
+		ctx := context.Background()
+		req := services.ChangePasswordRequest{CurrentPassword: "wrong-password-1", NewPassword: newPassword}
+
+		mockQueries.EXPECT().
+			GetUserByUserID(ctx, uIDUuid).
+			Return(user, nil)
+
+		mockPassHasher.EXPECT().
+			CompareHashAndPassword(user.PasswordHash, []byte(req.CurrentPassword)).
+			Return(services.ErrMismatchedHashAndPassword)
+
+		err := passwordService.ChangePassword(ctx, uIDUuid, currentSessionID, req)
+
+		assert.Equal(t, utils.ErrInvalidCurrentPassword, err)
+	})
+
+	t.Run("ChangePassword_WeakPassword", func(t *testing.T) {
+		ctx := context.Background()
+		req := services.ChangePasswordRequest{CurrentPassword: "current-password-1", NewPassword: "short"}
+
+		mockQueries.EXPECT().
+			GetUserByUserID(ctx, uIDUuid).
+			Return(user, nil)
+
+		mockPassHasher.EXPECT().
+			CompareHashAndPassword(user.PasswordHash, []byte(req.CurrentPassword)).
+			Return(nil)
+
+		err := passwordService.ChangePassword(ctx, uIDUuid, currentSessionID, req)
+
+		assert.ErrorIs(t, err, utils.ErrWeakPassword)
+	})
+
+	t.Run("ForgotPassword", func(t *testing.T) {
+		ctx := context.Background()
+		req := services.ForgotPasswordRequest{Email: user.Email}
+		var tokenHash string
+
+		mockQueries.EXPECT().
+			GetUserByEmail(ctx, req.Email).
+			Return(user, nil)
+
+		mockQueries.EXPECT().
+			ExecTx(ctx, gomock.Any()).
+			DoAndReturn(func(ctx context.Context, fn func(q db.WrappedQuerier) error) error {
+				return fn(mockQueries)
+			})
+
+		mockQueries.EXPECT().
+			LockUser(ctx, user.ID).
+			Return(nil)
+
+		mockQueries.EXPECT().
+			InvalidatePasswordResetTokens(ctx, user.ID).
+			Return(nil)
+
+		mockQueries.EXPECT().
+			CreatePasswordResetToken(ctx, gomock.Any()).
+			DoAndReturn(func(ctx context.Context, arg db.CreatePasswordResetTokenParams) (db.PasswordResetToken, error) {
+				assert.Equal(t, user.ID, arg.UserID)
+				assert.True(t, arg.ExpiresAt.Valid)
+				tokenHash = arg.TokenHash
+				return db.PasswordResetToken{}, nil
+			})
+
+		mockMailer.EXPECT().
+			Send(ctx, gomock.Any()).
+			DoAndReturn(func(ctx context.Context, msg mailer.Message) error {
+				assert.Equal(t, user.Email, msg.To)
+
+				// Only the hash is stored, while the email carries the token itself
+				_, token, found := strings.Cut(msg.Body, "http://localhost:3000/reset-password?token=")
+				require.True(t, found)
+				token, _, _ = strings.Cut(token, "\n")
+				assert.Equal(t, tokenHash, utils.HashToken(token))
+				return nil
+			})
+
+		err := passwordService.ForgotPassword(ctx, req, client)
+
+		require.NoError(t, err)
+	})
+
+	t.Run("ForgotPassword_MailerError", func(t *testing.T) {
+		ctx := context.Background()
+		req := services.ForgotPasswordRequest{Email: user.Email}
+		otherClient := services.ClientInfo{UserAgent: "test-agent", IP: "192.0.2.3"}
+
+		mockQueries.EXPECT().
+			GetUserByEmail(ctx, req.Email).
+			Return(user, nil)
+
+		mockQueries.EXPECT().
+			ExecTx(ctx, gomock.Any()).
+			DoAndReturn(func(ctx context.Context, fn func(q db.WrappedQuerier) error) error {
+				return fn(mockQueries)
+			})
+
+		mockQueries.EXPECT().
+			LockUser(ctx, user.ID).
+			Return(nil)
+
+		mockQueries.EXPECT().
+			InvalidatePasswordResetTokens(ctx, user.ID).
+			Return(nil)
+
+		mockQueries.EXPECT().
+			CreatePasswordResetToken(ctx, gomock.Any()).
+			Return(db.PasswordResetToken{}, nil)
+
+		mockMailer.EXPECT().
+			Send(ctx, gomock.Any()).
+			Return(errors.New("smtp unavailable"))
+
+		// Answered like an unknown email, so that the failure does not reveal that the email is registered
+		err := passwordService.ForgotPassword(ctx, req, otherClient)
+
+		require.NoError(t, err)
+	})
+
+	t.Run("ForgotPassword_UnknownEmail", func(t *testing.T) {
+		ctx := context.Background()
+		req := services.ForgotPasswordRequest{Email: "unknown@example.com"}
+
+		mockQueries.EXPECT().
+			GetUserByEmail(ctx, req.Email).
+			Return(db.User{}, pgx.ErrNoRows)
+
+		err := passwordService.ForgotPassword(ctx, req, client)
+
+		require.NoError(t, err)
+	})
+
+	t.Run("ForgotPassword_Throttled", func(t *testing.T) {
+		ctx := context.Background()
+		req := services.ForgotPasswordRequest{Email: "flooded@example.com"}
+		otherClient := services.ClientInfo{UserAgent: "test-agent", IP: "192.0.2.2"}
+
+		// Unknown emails count as well, so that the response does not tell them apart
+		mockQueries.EXPECT().
+			GetUserByEmail(ctx, req.Email).
+			Return(db.User{}, pgx.ErrNoRows).
+			Times(3)
+
+		for range 3 {
+			require.NoError(t, passwordService.ForgotPassword(ctx, req, otherClient))
+		}
+
+		// Locked out without even looking up the user
+		err := passwordService.ForgotPassword(ctx, services.ForgotPasswordRequest{Email: "Flooded@example.com"}, client)
+
+		var lockedErr *services.LoginLockedError
+		require.ErrorAs(t, err, &lockedErr)
+		assert.Greater(t, lockedErr.RetryAfter, time.Duration(0))
+	})
+
+	t.Run("ResetPassword", func(t *testing.T) {
+		ctx := context.Background()
+		req := services.ResetPasswordRequest{Token: "reset-token-123", NewPassword: newPassword}
+
+		mockQueries.EXPECT().
+			ExecTx(ctx, gomock.Any()).
+			DoAndReturn(func(ctx context.Context, fn func(q db.WrappedQuerier) error) error {
+				return fn(mockQueries)
+			})
+
+		mockQueries.EXPECT().
+			ConsumePasswordResetToken(ctx, utils.HashToken(req.Token)).
+			Return(db.PasswordResetToken{UserID: user.ID}, nil)
+
+		mockQueries.EXPECT().
+			GetUserByID(ctx, user.ID).
+			Return(user, nil)
+
+		mockPassHasher.EXPECT().
+			GenerateFromPassword([]byte(newPassword)).
+			Return(newHashedPassword, nil)
+
+		mockQueries.EXPECT().
+			UpdatePasswordHash(ctx, db.UpdatePasswordHashParams{PasswordHash: newHashedPassword, UserID: uIDUuid}).
+			Return(nil)
+
+		mockSessionStore.EXPECT().
+			List(ctx, uIDStr).
+			Return([]db.Session{{ID: currentSessionID}}, nil)
+
+		mockRefreshTokenStore.EXPECT().
+			RevokeFamily(ctx, currentSessionID).
+			Return(nil)
+
+		mockSessionStore.EXPECT().
+			Delete(ctx, uIDStr, currentSessionID).
+			Return(nil)
+
+		err := passwordService.ResetPassword(ctx, req)
+
+		require.NoError(t, err)
+	})
+
+	t.Run("ResetPassword_InvalidToken", func(t *testing.T) {
+		ctx := context.Background()
+		req := services.ResetPasswordRequest{Token: "used-token", NewPassword: newPassword}
+
+		mockQueries.EXPECT().
+			ExecTx(ctx, gomock.Any()).
+			DoAndReturn(func(ctx context.Context, fn func(q db.WrappedQuerier) error) error {
+				return fn(mockQueries)
+			})
+
+		mockQueries.EXPECT().
+			ConsumePasswordResetToken(ctx, utils.HashToken(req.Token)).
+			Return(db.PasswordResetToken{}, pgx.ErrNoRows)
+
+		err := passwordService.ResetPassword(ctx, req)
+
+		assert.Equal(t, utils.ErrInvalidResetToken, err)
+	})
+
+	t.Run("ResetPassword_UpdateFailsKeepsToken", func(t *testing.T) {
+		ctx := context.Background()
+		req := services.ResetPasswordRequest{Token: "reset-token-123", NewPassword: newPassword}
+		updateErr := errors.New("update failed")
+
+		// Returning the error rolls back the transaction, and the token with it
+		mockQueries.EXPECT().
+			ExecTx(ctx, gomock.Any()).
+			DoAndReturn(func(ctx context.Context, fn func(q db.WrappedQuerier) error) error {
+				err := fn(mockQueries)
+				assert.ErrorIs(t, err, updateErr)
+				return err
+			})
+
+		mockQueries.EXPECT().
+			ConsumePasswordResetToken(ctx, utils.HashToken(req.Token)).
+			Return(db.PasswordResetToken{UserID: user.ID}, nil)
+
+		mockQueries.EXPECT().
+			GetUserByID(ctx, user.ID).
+			Return(user, nil)
+
+		mockPassHasher.EXPECT().
+			GenerateFromPassword([]byte(newPassword)).
+			Return(newHashedPassword, nil)
+
+		mockQueries.EXPECT().
+			UpdatePasswordHash(ctx, db.UpdatePasswordHashParams{PasswordHash: newHashedPassword, UserID: uIDUuid}).
+			Return(updateErr)
+
+		err := passwordService.ResetPassword(ctx, req)
+
+		assert.ErrorIs(t, err, updateErr)
+	})
+
+	t.Run("ResetPassword_WeakPasswordKeepsToken", func(t *testing.T) {
+		ctx := context.Background()
+		req := services.ResetPasswordRequest{Token: "reset-token-123", NewPassword: "short"}
+
+		err := passwordService.ResetPassword(ctx, req)
+
+		assert.ErrorIs(t, err, utils.ErrWeakPassword)
+	})
+}
@@ -0,0 +1,129 @@
+package services_test
+
+import (
+	"strings"
+	"testing"
+	"todo-app/internal/services"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestArgon2idPasswordHasher(t *testing.T) {
+	hasher := services.NewArgon2idPasswordHasher()
+	password := []byte("correct-horse-42")
+
+	t.Run("GenerateAndCompare", func(t *testing.T) {
+		hashed, err := hasher.GenerateFromPassword(password)
+
+		require.NoError(t, err)
+		assert.True(t, strings.HasPrefix(string(hashed), "$argon2id$v=19$m=19456,t=2,p=1$"))
+		assert.NoError(t, hasher.CompareHashAndPassword(hashed, password))
+		assert.Equal(t, services.ErrMismatchedHashAndPassword, hasher.CompareHashAndPassword(hashed, []byte("wrong-password")))
+		assert.False(t, hasher.NeedsRehash(hashed))
+	})
+
+	t.Run("SaltedHashesDiffer", func(t *testing.T) {
+		first, err := hasher.GenerateFromPassword(password)
+		require.NoError(t, err)
+		second, err := hasher.GenerateFromPassword(password)
+		require.NoError(t, err)
+
+		assert.NotEqual(t, first, second)
+	})
+
+	t.Run("LegacyBcryptHash", func(t *testing.T) {
+		hashed, err := bcrypt.GenerateFromPassword(password, bcrypt.MinCost)
+		require.NoError(t, err)
+
+		assert.NoError(t, hasher.CompareHashAndPassword(hashed, password))
+		assert.Equal(t, services.ErrMismatchedHashAndPassword, hasher.CompareHashAndPassword(hashed, []byte("wrong-password")))
+		assert.True(t, hasher.NeedsRehash(hashed))
+	})
+
+	t.Run("OutdatedParameters", func(t *testing.T) {
+		weaker := services.NewArgon2idPasswordHasher()
+		weaker.Iterations = 1
+
+		hashed, err := weaker.GenerateFromPassword(password)
+		require.NoError(t, err)
+
+		assert.NoError(t, hasher.CompareHashAndPassword(hashed, password))
+		assert.True(t, hasher.NeedsRehash(hashed))
+	})
+
+	t.Run("MalformedHash", func(t *testing.T) {
+		err := hasher.CompareHashAndPassword([]byte("$argon2id$v=19$broken"), password)
+
+		assert.Equal(t, services.ErrUnsupportedPasswordHash, err)
+		assert.True(t, hasher.NeedsRehash([]byte("$argon2id$v=19$broken")))
+	})
+}
+
+func TestPasswordPolicy(t *testing.T) {
+	policy := services.NewPasswordPolicy(8, 16, []string{services.PasswordCharClassUpper, services.PasswordCharClassDigit}, 1000)
+
+	t.Run("Valid", func(t *testing.T) {
+		assert.NoError(t, policy.Validate("Tr0ubadour"))
+	})
+
+	t.Run("TooShortAndMissingClasses", func(t *testing.T) {
+		err := policy.Validate("short")
+
+		var policyErr *services.PasswordPolicyError
+		require.ErrorAs(t, err, &policyErr)
+		assert.Equal(t, []string{
+			services.PasswordViolationTooShort,
+			services.PasswordViolationCharClass,
+			services.PasswordViolationCharClass,
+		}, violationCodes(policyErr))
+	})
+
+	t.Run("TooLong", func(t *testing.T) {
+		err := policy.Validate("Abcdefghijklmnop1")
+
+		var policyErr *services.PasswordPolicyError
+		require.ErrorAs(t, err, &policyErr)
+		assert.Equal(t, []string{services.PasswordViolationTooLong}, violationCodes(policyErr))
+	})
+
+	t.Run("CommonPasswordIgnoringCase", func(t *testing.T) {
+		err := policy.Validate("Password1")
+
+		var policyErr *services.PasswordPolicyError
+		require.ErrorAs(t, err, &policyErr)
+		assert.Equal(t, []string{services.PasswordViolationCommon}, violationCodes(policyErr))
+	})
+
+	t.Run("CommonListSize", func(t *testing.T) {
+		// "password1" is not among the 10 most common passwords
+		small := services.NewPasswordPolicy(8, 16, nil, 10)
+
+		assert.NoError(t, small.Validate("password1"))
+		assert.Error(t, small.Validate("12345678"))
+	})
+
+	t.Run("CommonListSizeFromEnv", func(t *testing.T) {
+		// "freepass" is the last entry of the bundled list, so it is checked by default and never left out by a size too large
+		t.Setenv("PASSWORD_REQUIRED_CLASSES", services.PasswordCharClassLower)
+		for _, size := range []string{"", "100000"} {
+			t.Setenv("PASSWORD_COMMON_LIST_SIZE", size)
+			policy, err := services.NewPasswordPolicyFromEnv()
+			require.NoError(t, err)
+
+			var policyErr *services.PasswordPolicyError
+			require.ErrorAs(t, policy.Validate("freepass"), &policyErr)
+			assert.Equal(t, []string{services.PasswordViolationCommon}, violationCodes(policyErr), size)
+		}
+	})
+}
+
+func violationCodes(err *services.PasswordPolicyError) []string {
+	codes := make([]string, 0, len(err.Violations))
+	for _, v := range err.Violations {
+		codes = append(codes, v.Code)
+	}
+
+	return codes
+}
@@ -0,0 +1,124 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+	"todo-app/internal/db"
+	"todo-app/internal/utils"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Personal access tokens are sent as `Authorization: Bearer pat_...` and are told apart from JWTs by the prefix
+const PersonalAccessTokenPrefix = "pat_"
+
+const (
+	ScopeTodosRead  = "todos:read"
+	ScopeTodosWrite = "todos:write"
+)
+
+type PersonalAccessTokenService struct {
+	SqlClient db.WrappedQuerier
+}
+
+type CreatePersonalAccessTokenRequest struct {
+	Name          string   `json:"name" binding:"required,max=100"`
+	Scopes        []string `json:"scopes" binding:"required,min=1,dive,oneof=todos:read todos:write"`
+	ExpiresInDays int      `json:"expires_in_days" binding:"required,min=1,max=365"`
+}
+
+func NewPersonalAccessTokenService(sqlClient db.WrappedQuerier) *PersonalAccessTokenService {
+	return &PersonalAccessTokenService{SqlClient: sqlClient}
+}
+
+// Returns the created token along with its plain text, which is not stored and cannot be shown again
+func (s *PersonalAccessTokenService) CreateToken(ctx context.Context, userID pgtype.UUID, req CreatePersonalAccessTokenRequest) (*db.PersonalAccessToken, string, error) {
+	user, err := s.SqlClient.GetUserByUserID(ctx, userID)
+	if err != nil {
+		return nil, "", utils.ErrInvalidUID
+	}
+
+	randomToken, err := utils.GenerateRandomToken(32)
+	if err != nil {
+		return nil, "", err
+	}
+	plainToken := PersonalAccessTokenPrefix + randomToken
+
+	token, err := s.SqlClient.CreatePersonalAccessToken(ctx, db.CreatePersonalAccessTokenParams{
+		UserID:    user.ID,
+		Name:      strings.TrimSpace(req.Name),
+		TokenHash: utils.HashToken(plainToken),
+		Scopes:    uniqueScopes(req.Scopes),
+		ExpiresAt: pgtype.Timestamptz{Time: time.Now().AddDate(0, 0, req.ExpiresInDays), Valid: true},
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	return &token, plainToken, nil
+}
+
+func (s *PersonalAccessTokenService) ListTokens(ctx context.Context, userID pgtype.UUID) ([]db.PersonalAccessToken, error) {
+	user, err := s.SqlClient.GetUserByUserID(ctx, userID)
+	if err != nil {
+		return nil, utils.ErrInvalidUID
+	}
+
+	return s.SqlClient.ListPersonalAccessTokens(ctx, user.ID)
+}
+
+func (s *PersonalAccessTokenService) DeleteToken(ctx context.Context, userID pgtype.UUID, tokenID int32) error {
+	user, err := s.SqlClient.GetUserByUserID(ctx, userID)
+	if err != nil {
+		return utils.ErrInvalidUID
+	}
+
+	rows, err := s.SqlClient.DeletePersonalAccessToken(ctx, db.DeletePersonalAccessTokenParams{
+		ID:     tokenID,
+		UserID: user.ID,
+	})
+	if err != nil {
+		return err
+	} else if rows == 0 {
+		return utils.ErrNoRowsMatchedSQLC
+	}
+
+	return nil
+}
+
+// Resolves the owner (as the public user ID) and the scopes of an unexpired token
+func (s *PersonalAccessTokenService) Authenticate(ctx context.Context, plainToken string) (string, []string, error) {
+	if !strings.HasPrefix(plainToken, PersonalAccessTokenPrefix) {
+		return "", nil, utils.ErrInvalidPersonalAccessToken
+	}
+
+	token, err := s.SqlClient.UsePersonalAccessToken(ctx, utils.HashToken(plainToken))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", nil, utils.ErrInvalidPersonalAccessToken
+		}
+		return "", nil, err
+	}
+
+	userIDStr := utils.UUIDToString(token.UserID)
+	if userIDStr == "" {
+		return "", nil, errors.New("failed to convert uuid to string")
+	}
+
+	return userIDStr, token.Scopes, nil
+}
+
+func uniqueScopes(scopes []string) []string {
+	seen := make(map[string]bool, len(scopes))
+	unique := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		if !seen[scope] {
+			seen[scope] = true
+			unique = append(unique, scope)
+		}
+	}
+	return unique
+}
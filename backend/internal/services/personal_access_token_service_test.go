@@ -0,0 +1,177 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+	"todo-app/internal/db"
+	mock_db "todo-app/internal/db/_mock"
+	"todo-app/internal/services"
+	"todo-app/internal/utils"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestPersonalAccessTokenService(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockQueries := mock_db.NewMockWrappedQuerier(ctrl)
+
+	personalAccessTokenService := services.NewPersonalAccessTokenService(mockQueries)
+
+	uIDStr := "00010203-0405-0607-0809-0a0b0c0d0e0f"
+	uIDUuid, _ := utils.StringToUUID(uIDStr)
+	user := db.User{ID: 1, UserID: uIDUuid}
+
+	t.Run("CreateToken", func(t *testing.T) {
+		ctx := context.Background()
+		req := services.CreatePersonalAccessTokenRequest{
+			Name:          " ci ",
+			Scopes:        []string{services.ScopeTodosRead, services.ScopeTodosWrite, services.ScopeTodosRead},
+			ExpiresInDays: 30,
+		}
+
+		mockQueries.EXPECT().
+			GetUserByUserID(ctx, uIDUuid).
+			Return(user, nil)
+
+		var storedHash string
+		mockQueries.EXPECT().
+			CreatePersonalAccessToken(ctx, gomock.Any()).
+			DoAndReturn(func(ctx context.Context, arg db.CreatePersonalAccessTokenParams) (db.PersonalAccessToken, error) {
+				assert.Equal(t, int32(1), arg.UserID)
+				assert.Equal(t, "ci", arg.Name)
+				assert.Equal(t, []string{services.ScopeTodosRead, services.ScopeTodosWrite}, arg.Scopes)
+				assert.WithinDuration(t, time.Now().AddDate(0, 0, 30), arg.ExpiresAt.Time, time.Minute)
+				storedHash = arg.TokenHash
+				return db.PersonalAccessToken{ID: 1, UserID: arg.UserID, Name: arg.Name, TokenHash: arg.TokenHash, Scopes: arg.Scopes, ExpiresAt: arg.ExpiresAt}, nil
+			})
+
+		token, plainToken, err := personalAccessTokenService.CreateToken(ctx, uIDUuid, req)
+
+		require.NoError(t, err)
+		assert.True(t, strings.HasPrefix(plainToken, services.PersonalAccessTokenPrefix))
+		assert.Equal(t, utils.HashToken(plainToken), storedHash)
+		assert.Equal(t, int32(1), token.ID)
+	})
+
+	t.Run("CreateToken_InvalidUserID", func(t *testing.T) {
+		ctx := context.Background()
+
+		mockQueries.EXPECT().
+			GetUserByUserID(ctx, uIDUuid).
+			Return(db.User{}, pgx.ErrNoRows)
+
+		token, plainToken, err := personalAccessTokenService.CreateToken(ctx, uIDUuid, services.CreatePersonalAccessTokenRequest{Name: "ci", Scopes: []string{services.ScopeTodosRead}, ExpiresInDays: 1})
+
+		assert.Equal(t, utils.ErrInvalidUID, err)
+		assert.Nil(t, token)
+		assert.Empty(t, plainToken)
+	})
+
+	t.Run("ListTokens", func(t *testing.T) {
+		ctx := context.Background()
+
+		mockQueries.EXPECT().
+			GetUserByUserID(ctx, uIDUuid).
+			Return(user, nil)
+
+		mockQueries.EXPECT().
+			ListPersonalAccessTokens(ctx, int32(1)).
+			Return([]db.PersonalAccessToken{{ID: 2, Name: "deploy"}, {ID: 1, Name: "ci"}}, nil)
+
+		tokens, err := personalAccessTokenService.ListTokens(ctx, uIDUuid)
+
+		require.NoError(t, err)
+		assert.Len(t, tokens, 2)
+	})
+
+	t.Run("DeleteToken", func(t *testing.T) {
+		ctx := context.Background()
+
+		mockQueries.EXPECT().
+			GetUserByUserID(ctx, uIDUuid).
+			Return(user, nil)
+
+		mockQueries.EXPECT().
+			DeletePersonalAccessToken(ctx, db.DeletePersonalAccessTokenParams{ID: 1, UserID: 1}).
+			Return(int64(1), nil)
+
+		err := personalAccessTokenService.DeleteToken(ctx, uIDUuid, 1)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("DeleteToken_NotFound", func(t *testing.T) {
+		ctx := context.Background()
+
+		mockQueries.EXPECT().
+			GetUserByUserID(ctx, uIDUuid).
+			Return(user, nil)
+
+		mockQueries.EXPECT().
+			DeletePersonalAccessToken(ctx, db.DeletePersonalAccessTokenParams{ID: 99, UserID: 1}).
+			Return(int64(0), nil)
+
+		err := personalAccessTokenService.DeleteToken(ctx, uIDUuid, 99)
+
+		assert.Equal(t, utils.ErrNoRowsMatchedSQLC, err)
+	})
+
+	t.Run("Authenticate", func(t *testing.T) {
+		ctx := context.Background()
+
+		mockQueries.EXPECT().
+			UsePersonalAccessToken(ctx, utils.HashToken("pat_token-123")).
+			Return(db.UsePersonalAccessTokenRow{UserID: uIDUuid, Scopes: []string{services.ScopeTodosRead}}, nil)
+
+		userID, scopes, err := personalAccessTokenService.Authenticate(ctx, "pat_token-123")
+
+		require.NoError(t, err)
+		assert.Equal(t, uIDStr, userID)
+		assert.Equal(t, []string{services.ScopeTodosRead}, scopes)
+	})
+
+	t.Run("Authenticate_InvalidOrExpired", func(t *testing.T) {
+		ctx := context.Background()
+
+		mockQueries.EXPECT().
+			UsePersonalAccessToken(ctx, utils.HashToken("pat_expired-token")).
+			Return(db.UsePersonalAccessTokenRow{}, pgx.ErrNoRows)
+
+		userID, scopes, err := personalAccessTokenService.Authenticate(ctx, "pat_expired-token")
+
+		assert.Equal(t, utils.ErrInvalidPersonalAccessToken, err)
+		assert.Empty(t, userID)
+		assert.Nil(t, scopes)
+	})
+
+	t.Run("Authenticate_NotAPersonalAccessToken", func(t *testing.T) {
+		ctx := context.Background()
+
+		userID, scopes, err := personalAccessTokenService.Authenticate(ctx, "token-123")
+
+		assert.Equal(t, utils.ErrInvalidPersonalAccessToken, err)
+		assert.Empty(t, userID)
+		assert.Nil(t, scopes)
+	})
+
+	t.Run("Authenticate_DBError", func(t *testing.T) {
+		ctx := context.Background()
+
+		mockQueries.EXPECT().
+			UsePersonalAccessToken(ctx, utils.HashToken("pat_token-123")).
+			Return(db.UsePersonalAccessTokenRow{}, errors.New("db error"))
+
+		_, _, err := personalAccessTokenService.Authenticate(ctx, "pat_token-123")
+
+		assert.Error(t, err)
+		assert.NotEqual(t, utils.ErrInvalidPersonalAccessToken, err)
+	})
+}
@@ -0,0 +1,364 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"io/fs"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+	_ "time/tzdata" // time zones are validated with time.LoadLocation, which should not depend on the zoneinfo of the host
+	"todo-app/internal/db"
+	"todo-app/internal/utils"
+	"unicode/utf8"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"golang.org/x/text/language"
+)
+
+const (
+	WeekStartSaturday = "saturday"
+	WeekStartSunday   = "sunday"
+	WeekStartMonday   = "monday"
+)
+
+// Same as the column defaults of user_preferences
+const (
+	defaultTimeZone  = "UTC"
+	defaultLocale    = "en"
+	defaultTodoSort  = "position"
+	defaultWeekStart = WeekStartMonday
+)
+
+const maxDisplayNameLength = 50
+
+var weekStarts = []string{WeekStartSaturday, WeekStartSunday, WeekStartMonday}
+
+var avatarContentTypes = []string{"image/png", "image/jpeg", "image/gif"}
+
+type PreferencesService struct {
+	SqlClient          db.WrappedQuerier
+	BlobStore          IBlobStore
+	MaxAvatarSize      int64
+	MaxAvatarDimension int
+}
+
+type PreferenceViolation struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// Returned when a patch contains unknown or invalid preferences, listing every offending field
+type PreferencesValidationError struct {
+	Violations []PreferenceViolation
+}
+
+func (e *PreferencesValidationError) Error() string {
+	return utils.ErrInvalidPreferences.Error()
+}
+
+func (e *PreferencesValidationError) Unwrap() error {
+	return utils.ErrInvalidPreferences
+}
+
+// Each setter validates the value and returns a violation message if it is invalid; a nil value resets the preference to its default
+var preferenceSetters = map[string]func(prefs *db.UserPreference, value *string) string{
+	"display_name":      setDisplayName,
+	"time_zone":         setTimeZone,
+	"locale":            setLocale,
+	"default_todo_sort": setDefaultTodoSort,
+	"week_start":        setWeekStart,
+}
+
+func NewPreferencesService(sqlClient db.WrappedQuerier, blobStore IBlobStore) *PreferencesService {
+	return &PreferencesService{
+		SqlClient:          sqlClient,
+		BlobStore:          blobStore,
+		MaxAvatarSize:      int64(envInt("AVATAR_MAX_SIZE_KB", 1024)) * 1024,
+		MaxAvatarDimension: envInt("AVATAR_MAX_DIMENSION_PX", 2048),
+	}
+}
+
+func (s *PreferencesService) GetPreferences(ctx context.Context, userID pgtype.UUID) (*db.UserPreference, error) {
+	user, err := s.SqlClient.GetUserByUserID(ctx, userID)
+	if err != nil {
+		return nil, utils.ErrInvalidUID
+	}
+
+	prefs, err := getUserPreferences(ctx, s.SqlClient, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &prefs, nil
+}
+
+// Applies a JSON Merge Patch (RFC 7396): members set to null are reset to their default and absent members are left as they are
+func (s *PreferencesService) UpdatePreferences(ctx context.Context, userID pgtype.UUID, patch map[string]json.RawMessage) (*db.UserPreference, error) {
+	user, err := s.SqlClient.GetUserByUserID(ctx, userID)
+	if err != nil {
+		return nil, utils.ErrInvalidUID
+	}
+
+	prefs, err := getUserPreferences(ctx, s.SqlClient, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = applyPreferencesPatch(&prefs, patch); err != nil {
+		return nil, err
+	}
+
+	updated, err := s.SqlClient.UpsertUserPreferences(ctx, db.UpsertUserPreferencesParams{
+		UserID:          user.ID,
+		DisplayName:     prefs.DisplayName,
+		TimeZone:        prefs.TimeZone,
+		Locale:          prefs.Locale,
+		DefaultTodoSort: prefs.DefaultTodoSort,
+		WeekStart:       prefs.WeekStart,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &updated, nil
+}
+
+// The image type is detected from the content rather than trusted from the client, and the image is decoded far enough to know its dimensions
+func (s *PreferencesService) PutAvatar(ctx context.Context, userID pgtype.UUID, r io.Reader) (*db.UserPreference, error) {
+	user, err := s.SqlClient.GetUserByUserID(ctx, userID)
+	if err != nil {
+		return nil, utils.ErrInvalidUID
+	}
+
+	data, err := io.ReadAll(io.LimitReader(r, s.MaxAvatarSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > s.MaxAvatarSize {
+		return nil, utils.ErrAvatarTooLarge
+	}
+
+	contentType := http.DetectContentType(data)
+	if !slices.Contains(avatarContentTypes, contentType) {
+		return nil, utils.ErrInvalidAvatar
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, utils.ErrInvalidAvatar
+	}
+	if config.Width > s.MaxAvatarDimension || config.Height > s.MaxAvatarDimension {
+		return nil, utils.ErrAvatarTooLarge
+	}
+
+	key := avatarKey(user.UserID)
+	if err = s.BlobStore.Put(ctx, key, bytes.NewReader(data)); err != nil {
+		return nil, err
+	}
+
+	prefs, err := s.SqlClient.UpdateUserAvatar(ctx, db.UpdateUserAvatarParams{
+		UserID:            user.ID,
+		AvatarKey:         pgtype.Text{String: key, Valid: true},
+		AvatarContentType: pgtype.Text{String: contentType, Valid: true},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &prefs, nil
+}
+
+// Returns the avatar image and its content type; the caller has to close the reader
+func (s *PreferencesService) GetAvatar(ctx context.Context, userID pgtype.UUID) (io.ReadCloser, string, error) {
+	user, err := s.SqlClient.GetUserByUserID(ctx, userID)
+	if err != nil {
+		return nil, "", utils.ErrInvalidUID
+	}
+
+	prefs, err := getUserPreferences(ctx, s.SqlClient, user.ID)
+	if err != nil {
+		return nil, "", err
+	} else if !prefs.AvatarKey.Valid {
+		return nil, "", utils.ErrNoRowsMatchedSQLC
+	}
+
+	avatar, err := s.BlobStore.Get(ctx, prefs.AvatarKey.String)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, "", utils.ErrNoRowsMatchedSQLC
+		}
+		return nil, "", err
+	}
+
+	return avatar, prefs.AvatarContentType.String, nil
+}
+
+func (s *PreferencesService) DeleteAvatar(ctx context.Context, userID pgtype.UUID) error {
+	user, err := s.SqlClient.GetUserByUserID(ctx, userID)
+	if err != nil {
+		return utils.ErrInvalidUID
+	}
+
+	rows, err := s.SqlClient.ClearUserAvatar(ctx, user.ID)
+	if err != nil {
+		return err
+	} else if rows == 0 {
+		return utils.ErrNoRowsMatchedSQLC
+	}
+
+	// The avatar is already gone for the user at this point, so a leftover blob is not worth failing the request for
+	if err = s.BlobStore.Delete(ctx, avatarKey(user.UserID)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	return nil
+}
+
+// Time zone of the user for features working with local dates and times; UTC unless the user has set one
+func UserLocation(ctx context.Context, sqlClient db.WrappedQuerier, userID int32) (*time.Location, error) {
+	prefs, err := getUserPreferences(ctx, sqlClient, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return time.LoadLocation(prefs.TimeZone)
+}
+
+// Users without a row in user_preferences have the defaults
+func getUserPreferences(ctx context.Context, sqlClient db.WrappedQuerier, userID int32) (db.UserPreference, error) {
+	prefs, err := sqlClient.GetUserPreferences(ctx, userID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return db.UserPreference{
+			UserID:          userID,
+			TimeZone:        defaultTimeZone,
+			Locale:          defaultLocale,
+			DefaultTodoSort: defaultTodoSort,
+			WeekStart:       defaultWeekStart,
+		}, nil
+	}
+
+	return prefs, err
+}
+
+func applyPreferencesPatch(prefs *db.UserPreference, patch map[string]json.RawMessage) error {
+	// Sorted so that the violations come out in a stable order
+	fields := make([]string, 0, len(patch))
+	for field := range patch {
+		fields = append(fields, field)
+	}
+	slices.Sort(fields)
+
+	var violations []PreferenceViolation
+	for _, field := range fields {
+		set, ok := preferenceSetters[field]
+		if !ok {
+			violations = append(violations, PreferenceViolation{Field: field, Message: "Unknown preference"})
+			continue
+		}
+
+		var value *string
+		if err := json.Unmarshal(patch[field], &value); err != nil {
+			violations = append(violations, PreferenceViolation{Field: field, Message: "Must be a string or null"})
+			continue
+		}
+
+		if msg := set(prefs, value); msg != "" {
+			violations = append(violations, PreferenceViolation{Field: field, Message: msg})
+		}
+	}
+
+	if len(violations) > 0 {
+		return &PreferencesValidationError{Violations: violations}
+	}
+
+	return nil
+}
+
+func setDisplayName(prefs *db.UserPreference, value *string) string {
+	if value == nil {
+		prefs.DisplayName = pgtype.Text{}
+		return ""
+	}
+
+	name := strings.TrimSpace(*value)
+	if name == "" || utf8.RuneCountInString(name) > maxDisplayNameLength {
+		return fmt.Sprintf("Must be between 1 and %d characters long", maxDisplayNameLength)
+	}
+
+	prefs.DisplayName = pgtype.Text{String: name, Valid: true}
+	return ""
+}
+
+func setTimeZone(prefs *db.UserPreference, value *string) string {
+	if value == nil {
+		prefs.TimeZone = defaultTimeZone
+		return ""
+	}
+
+	// LoadLocation also accepts "" and "Local", which are not time zones of the user
+	if _, err := time.LoadLocation(*value); err != nil || *value == "" || *value == "Local" {
+		return "Must be an IANA time zone name such as Europe/Berlin"
+	}
+
+	prefs.TimeZone = *value
+	return ""
+}
+
+func setLocale(prefs *db.UserPreference, value *string) string {
+	if value == nil {
+		prefs.Locale = defaultLocale
+		return ""
+	}
+
+	tag, err := language.Parse(*value)
+	if err != nil {
+		return "Must be a BCP 47 language tag such as en-US"
+	}
+
+	prefs.Locale = tag.String()
+	return ""
+}
+
+func setDefaultTodoSort(prefs *db.UserPreference, value *string) string {
+	if value == nil {
+		prefs.DefaultTodoSort = defaultTodoSort
+		return ""
+	}
+
+	if _, err := parseTodoSort(*value); err != nil {
+		return "Must be a comma separated list of " + strings.Join(db.TodoSortColumns, ", ") + ", each descending with a leading -"
+	}
+
+	prefs.DefaultTodoSort = *value
+	return ""
+}
+
+func setWeekStart(prefs *db.UserPreference, value *string) string {
+	if value == nil {
+		prefs.WeekStart = defaultWeekStart
+		return ""
+	}
+
+	if !slices.Contains(weekStarts, *value) {
+		return "Must be one of " + strings.Join(weekStarts, ", ")
+	}
+
+	prefs.WeekStart = *value
+	return ""
+}
+
+// One avatar per user, so a new upload simply replaces the previous one
+func avatarKey(userID pgtype.UUID) string {
+	return "avatars/" + utils.UUIDToString(userID)
+}
@@ -0,0 +1,295 @@
+package services_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"image"
+	"image/png"
+	"io"
+	"io/fs"
+	"strings"
+	"testing"
+	"todo-app/internal/db"
+	mock_db "todo-app/internal/db/_mock"
+	"todo-app/internal/services"
+	mock_services "todo-app/internal/services/_mock"
+	"todo-app/internal/utils"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestPreferencesService(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockQueries := mock_db.NewMockWrappedQuerier(ctrl)
+	mockBlobStore := mock_services.NewMockIBlobStore(ctrl)
+	preferencesService := services.NewPreferencesService(mockQueries, mockBlobStore)
+	preferencesService.MaxAvatarSize = 1024
+	preferencesService.MaxAvatarDimension = 64
+
+	uIDStr := "00010203-0405-0607-0809-0a0b0c0d0e0f"
+	uIDUuid, _ := utils.StringToUUID(uIDStr)
+	user := db.User{ID: 1, UserID: uIDUuid}
+	avatarKey := "avatars/" + uIDStr
+	prefs := db.UserPreference{
+		UserID:          user.ID,
+		DisplayName:     pgtype.Text{String: "Taro", Valid: true},
+		TimeZone:        "Asia/Tokyo",
+		Locale:          "ja",
+		DefaultTodoSort: "position",
+		WeekStart:       services.WeekStartSunday,
+	}
+
+	encodePNG := func(t *testing.T, size int) []byte {
+		var buf bytes.Buffer
+		require.NoError(t, png.Encode(&buf, image.NewGray(image.Rect(0, 0, size, size))))
+		return buf.Bytes()
+	}
+
+	t.Run("GetPreferences_Defaults", func(t *testing.T) {
+		ctx := context.Background()
+
+		mockQueries.EXPECT().
+			GetUserByUserID(ctx, uIDUuid).
+			Return(user, nil)
+
+		mockQueries.EXPECT().
+			GetUserPreferences(ctx, user.ID).
+			Return(db.UserPreference{}, pgx.ErrNoRows)
+
+		got, err := preferencesService.GetPreferences(ctx, uIDUuid)
+
+		require.NoError(t, err)
+		assert.Equal(t, &db.UserPreference{UserID: user.ID, TimeZone: "UTC", Locale: "en", DefaultTodoSort: "position", WeekStart: services.WeekStartMonday}, got)
+	})
+
+	t.Run("UpdatePreferences", func(t *testing.T) {
+		ctx := context.Background()
+		patch := map[string]json.RawMessage{
+			"display_name": json.RawMessage(`null`),
+			"time_zone":    json.RawMessage(`"Europe/Berlin"`),
+			"locale":       json.RawMessage(`"de-de"`),
+		}
+
+		mockQueries.EXPECT().
+			GetUserByUserID(ctx, uIDUuid).
+			Return(user, nil)
+
+		mockQueries.EXPECT().
+			GetUserPreferences(ctx, user.ID).
+			Return(prefs, nil)
+
+		// Preferences missing from the patch keep their current values
+		mockQueries.EXPECT().
+			UpsertUserPreferences(ctx, db.UpsertUserPreferencesParams{
+				UserID:          user.ID,
+				DisplayName:     pgtype.Text{},
+				TimeZone:        "Europe/Berlin",
+				Locale:          "de-DE",
+				DefaultTodoSort: "position",
+				WeekStart:       services.WeekStartSunday,
+			}).
+			Return(db.UserPreference{UserID: user.ID, TimeZone: "Europe/Berlin"}, nil)
+
+		got, err := preferencesService.UpdatePreferences(ctx, uIDUuid, patch)
+
+		require.NoError(t, err)
+		assert.Equal(t, "Europe/Berlin", got.TimeZone)
+	})
+
+	t.Run("UpdatePreferences_Invalid", func(t *testing.T) {
+		ctx := context.Background()
+		patch := map[string]json.RawMessage{
+			"time_zone":         json.RawMessage(`"Mars/Olympus_Mons"`),
+			"week_start":        json.RawMessage(`"friday"`),
+			"display_name":      json.RawMessage(`42`),
+			"default_todo_sort": json.RawMessage(`"-priority,id"`),
+			"theme":             json.RawMessage(`"dark"`),
+		}
+
+		mockQueries.EXPECT().
+			GetUserByUserID(ctx, uIDUuid).
+			Return(user, nil)
+
+		mockQueries.EXPECT().
+			GetUserPreferences(ctx, user.ID).
+			Return(prefs, nil)
+
+		got, err := preferencesService.UpdatePreferences(ctx, uIDUuid, patch)
+
+		var validationErr *services.PreferencesValidationError
+		require.ErrorAs(t, err, &validationErr)
+		assert.ErrorIs(t, err, utils.ErrInvalidPreferences)
+		assert.Nil(t, got)
+		assert.Equal(t, []services.PreferenceViolation{
+			{Field: "default_todo_sort", Message: "Must be a comma separated list of position, priority, due_at, created_at, updated_at, description, each descending with a leading -"},
+			{Field: "display_name", Message: "Must be a string or null"},
+			{Field: "theme", Message: "Unknown preference"},
+			{Field: "time_zone", Message: "Must be an IANA time zone name such as Europe/Berlin"},
+			{Field: "week_start", Message: "Must be one of saturday, sunday, monday"},
+		}, validationErr.Violations)
+	})
+
+	t.Run("PutAvatar", func(t *testing.T) {
+		ctx := context.Background()
+		avatar := encodePNG(t, 32)
+
+		mockQueries.EXPECT().
+			GetUserByUserID(ctx, uIDUuid).
+			Return(user, nil)
+
+		mockBlobStore.EXPECT().
+			Put(ctx, avatarKey, gomock.Any()).
+			DoAndReturn(func(ctx context.Context, key string, r io.Reader) error {
+				stored, err := io.ReadAll(r)
+				require.NoError(t, err)
+				assert.Equal(t, avatar, stored)
+				return nil
+			})
+
+		mockQueries.EXPECT().
+			UpdateUserAvatar(ctx, db.UpdateUserAvatarParams{
+				UserID:            user.ID,
+				AvatarKey:         pgtype.Text{String: avatarKey, Valid: true},
+				AvatarContentType: pgtype.Text{String: "image/png", Valid: true},
+			}).
+			Return(db.UserPreference{UserID: user.ID, AvatarKey: pgtype.Text{String: avatarKey, Valid: true}}, nil)
+
+		got, err := preferencesService.PutAvatar(ctx, uIDUuid, bytes.NewReader(avatar))
+
+		require.NoError(t, err)
+		assert.True(t, got.AvatarKey.Valid)
+	})
+
+	t.Run("PutAvatar_NotAnImage", func(t *testing.T) {
+		ctx := context.Background()
+
+		mockQueries.EXPECT().
+			GetUserByUserID(ctx, uIDUuid).
+			Return(user, nil)
+
+		got, err := preferencesService.PutAvatar(ctx, uIDUuid, strings.NewReader("<svg xmlns=\"http://www.w3.org/2000/svg\"></svg>"))
+
+		assert.Equal(t, utils.ErrInvalidAvatar, err)
+		assert.Nil(t, got)
+	})
+
+	t.Run("PutAvatar_TooManyBytes", func(t *testing.T) {
+		ctx := context.Background()
+
+		mockQueries.EXPECT().
+			GetUserByUserID(ctx, uIDUuid).
+			Return(user, nil)
+
+		got, err := preferencesService.PutAvatar(ctx, uIDUuid, bytes.NewReader(make([]byte, 1025)))
+
+		assert.Equal(t, utils.ErrAvatarTooLarge, err)
+		assert.Nil(t, got)
+	})
+
+	t.Run("PutAvatar_TooManyPixels", func(t *testing.T) {
+		ctx := context.Background()
+
+		mockQueries.EXPECT().
+			GetUserByUserID(ctx, uIDUuid).
+			Return(user, nil)
+
+		got, err := preferencesService.PutAvatar(ctx, uIDUuid, bytes.NewReader(encodePNG(t, 65)))
+
+		assert.Equal(t, utils.ErrAvatarTooLarge, err)
+		assert.Nil(t, got)
+	})
+
+	t.Run("GetAvatar", func(t *testing.T) {
+		ctx := context.Background()
+
+		mockQueries.EXPECT().
+			GetUserByUserID(ctx, uIDUuid).
+			Return(user, nil)
+
+		mockQueries.EXPECT().
+			GetUserPreferences(ctx, user.ID).
+			Return(db.UserPreference{AvatarKey: pgtype.Text{String: avatarKey, Valid: true}, AvatarContentType: pgtype.Text{String: "image/png", Valid: true}}, nil)
+
+		mockBlobStore.EXPECT().
+			Get(ctx, avatarKey).
+			Return(io.NopCloser(strings.NewReader("png")), nil)
+
+		avatar, contentType, err := preferencesService.GetAvatar(ctx, uIDUuid)
+
+		require.NoError(t, err)
+		defer avatar.Close()
+		assert.Equal(t, "image/png", contentType)
+	})
+
+	t.Run("GetAvatar_NotSet", func(t *testing.T) {
+		ctx := context.Background()
+
+		mockQueries.EXPECT().
+			GetUserByUserID(ctx, uIDUuid).
+			Return(user, nil)
+
+		mockQueries.EXPECT().
+			GetUserPreferences(ctx, user.ID).
+			Return(db.UserPreference{}, pgx.ErrNoRows)
+
+		_, _, err := preferencesService.GetAvatar(ctx, uIDUuid)
+
+		assert.Equal(t, utils.ErrNoRowsMatchedSQLC, err)
+	})
+
+	t.Run("DeleteAvatar", func(t *testing.T) {
+		ctx := context.Background()
+
+		mockQueries.EXPECT().
+			GetUserByUserID(ctx, uIDUuid).
+			Return(user, nil)
+
+		mockQueries.EXPECT().
+			ClearUserAvatar(ctx, user.ID).
+			Return(int64(1), nil)
+
+		mockBlobStore.EXPECT().
+			Delete(ctx, avatarKey).
+			Return(fs.ErrNotExist)
+
+		err := preferencesService.DeleteAvatar(ctx, uIDUuid)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("DeleteAvatar_NotSet", func(t *testing.T) {
+		ctx := context.Background()
+
+		mockQueries.EXPECT().
+			GetUserByUserID(ctx, uIDUuid).
+			Return(user, nil)
+
+		mockQueries.EXPECT().
+			ClearUserAvatar(ctx, user.ID).
+			Return(int64(0), nil)
+
+		err := preferencesService.DeleteAvatar(ctx, uIDUuid)
+
+		assert.Equal(t, utils.ErrNoRowsMatchedSQLC, err)
+	})
+
+	t.Run("UserLocation", func(t *testing.T) {
+		ctx := context.Background()
+
+		mockQueries.EXPECT().
+			GetUserPreferences(ctx, user.ID).
+			Return(prefs, nil)
+
+		loc, err := services.UserLocation(ctx, mockQueries, user.ID)
+
+		require.NoError(t, err)
+		assert.Equal(t, "Asia/Tokyo", loc.String())
+	})
+}
@@ -2,37 +2,179 @@ package services
 
 import (
 	"context"
+	"encoding/json"
+	"io"
+	"time"
 	"todo-app/internal/db"
+	"todo-app/internal/mailer"
+	"todo-app/internal/oidc"
 
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
 type IAuthService interface {
 	Register(ctx context.Context, req RegisterRequest) (*db.User, error)
-	Login(ctx context.Context, req LoginRequest, sessionID string) (string, string, error)
+	Login(ctx context.Context, req LoginRequest, sessionID string, client ClientInfo) (string, *TokenPair, error)
+	LoginTwoFactor(ctx context.Context, req TwoFactorLoginRequest, sessionID string, client ClientInfo) (string, *TokenPair, error)
+	RefreshToken(ctx context.Context, req RefreshTokenRequest, sessionID string) (string, *TokenPair, error)
+	Logout(ctx context.Context, userID, sessionID string) error
+}
+
+type IOIDCService interface {
+	StartLogin(ctx context.Context, providerName string) (*OIDCAuthRequest, error)
+	Login(ctx context.Context, providerName string, req OIDCCallbackRequest, authState OIDCAuthState, sessionID string, client ClientInfo) (string, *TokenPair, error)
 }
 
 type IUserService interface {
 	GetMe(ctx context.Context, userID pgtype.UUID) (*db.User, error)
 	UpdateUsername(ctx context.Context, userID pgtype.UUID, req UpdateUsernameRequest) error
-	DeleteUser(ctx context.Context, userID pgtype.UUID) error
+	DeleteUser(ctx context.Context, userID pgtype.UUID) (time.Time, error)
+	RestoreUser(ctx context.Context, userID pgtype.UUID) error
+}
+
+type IExportService interface {
+	WriteExport(ctx context.Context, userID pgtype.UUID, w io.Writer) error
+}
+
+type IPreferencesService interface {
+	GetPreferences(ctx context.Context, userID pgtype.UUID) (*db.UserPreference, error)
+	UpdatePreferences(ctx context.Context, userID pgtype.UUID, patch map[string]json.RawMessage) (*db.UserPreference, error)
+	PutAvatar(ctx context.Context, userID pgtype.UUID, r io.Reader) (*db.UserPreference, error)
+	GetAvatar(ctx context.Context, userID pgtype.UUID) (io.ReadCloser, string, error)
+	DeleteAvatar(ctx context.Context, userID pgtype.UUID) error
+}
+
+type IPasswordService interface {
+	ChangePassword(ctx context.Context, userID pgtype.UUID, sessionID string, req ChangePasswordRequest) error
+	ForgotPassword(ctx context.Context, req ForgotPasswordRequest, client ClientInfo) error
+	ResetPassword(ctx context.Context, req ResetPasswordRequest) error
+}
+
+type IEmailVerificationService interface {
+	VerifyEmail(ctx context.Context, token string) error
+	ResendVerification(ctx context.Context, userID pgtype.UUID) error
+	RequestEmailChange(ctx context.Context, userID pgtype.UUID, req ChangeEmailRequest) error
+	ConfirmEmailChange(ctx context.Context, token string) error
+}
+
+type ITwoFactorService interface {
+	StartTOTPEnrollment(ctx context.Context, userID pgtype.UUID) (*TOTPEnrollment, error)
+	ConfirmTOTPEnrollment(ctx context.Context, userID pgtype.UUID, req ConfirmTOTPRequest) ([]string, error)
+	DisableTOTP(ctx context.Context, userID pgtype.UUID, req DisableTOTPRequest) error
+}
+
+type ISessionService interface {
+	ListSessions(ctx context.Context, userID pgtype.UUID) ([]db.Session, error)
+	RevokeSession(ctx context.Context, userID pgtype.UUID, publicID string) error
+	RevokeAllSessions(ctx context.Context, userID pgtype.UUID) error
+}
+
+type IPersonalAccessTokenService interface {
+	CreateToken(ctx context.Context, userID pgtype.UUID, req CreatePersonalAccessTokenRequest) (*db.PersonalAccessToken, string, error)
+	ListTokens(ctx context.Context, userID pgtype.UUID) ([]db.PersonalAccessToken, error)
+	DeleteToken(ctx context.Context, userID pgtype.UUID, tokenID int32) error
+	Authenticate(ctx context.Context, plainToken string) (string, []string, error)
+}
+
+type IAdminService interface {
+	ListUsers(ctx context.Context, req ListUsersRequest) (*UserList, error)
+	GetUser(ctx context.Context, userID pgtype.UUID) (*UserDetail, error)
+	DisableUser(ctx context.Context, adminID, userID pgtype.UUID) error
+	EnableUser(ctx context.Context, userID pgtype.UUID) error
+	ForcePasswordReset(ctx context.Context, userID pgtype.UUID) error
 }
 
 type IPasswordHasher interface {
-	GenerateFromPassword(password []byte, cost int) ([]byte, error)
+	GenerateFromPassword(password []byte) ([]byte, error)
 	CompareHashAndPassword(hashedPassword []byte, password []byte) error
+	NeedsRehash(hashedPassword []byte) bool
 }
 
 type ITokenGenerator interface {
 	GenerateToken(userID, sessionID string) (string, error)
+	GenerateRefreshToken() (string, time.Time, error)
+	GenerateEmailVerificationToken(userID, email string) (string, error)
+	GenerateEmailChangeToken(userID, email, newEmail string) (string, error)
+	GenerateTwoFactorChallengeToken(userID, sessionID string) (string, error)
+	GenerateTodoCursor(userID string, cursor json.RawMessage) (string, error)
 	ValidateToken(tokenString string) (*JWTCustomClaims, error)
+	ValidateEmailToken(tokenString, tokenType string) (*JWTCustomClaims, error)
+	ValidateTodoCursor(tokenString string) (*JWTCustomClaims, error)
+	ValidateTwoFactorChallengeToken(tokenString string) (*JWTCustomClaims, error)
+	JWKS() *JWKS
+}
+
+type IRefreshTokenStore interface {
+	Save(ctx context.Context, tokenHash string, token db.RefreshToken) error
+	Get(ctx context.Context, tokenHash string) (*db.RefreshToken, error)
+	MarkUsed(ctx context.Context, tokenHash string, expiresAt time.Time) (bool, error)
+	RevokeFamily(ctx context.Context, sessionID string) error
+}
+
+type ISessionStore interface {
+	Create(ctx context.Context, session db.Session, expiresAt time.Time) error
+	Touch(ctx context.Context, sessionID string, lastSeenAt time.Time) (bool, error)
+	List(ctx context.Context, userID string) ([]db.Session, error)
+	FindByPublicID(ctx context.Context, userID, publicID string) (*db.Session, error)
+	Delete(ctx context.Context, userID, sessionID string) error
+}
+
+type ILoginAttemptStore interface {
+	LockedUntil(ctx context.Context, key string) (time.Time, error)
+	RecordFailure(ctx context.Context, key string, window time.Duration) (int, error)
+	Lock(ctx context.Context, key string, until time.Time) error
+	Reset(ctx context.Context, key string) error
+}
+
+type IOIDCProvider interface {
+	AuthCodeURL(ctx context.Context, state, nonce, codeChallenge string) (string, error)
+	Exchange(ctx context.Context, code, codeVerifier, nonce string) (*oidc.Claims, error)
+}
+
+type IBlobStore interface {
+	Put(ctx context.Context, key string, r io.Reader) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+}
+
+type IMailer interface {
+	Send(ctx context.Context, msg mailer.Message) error
 }
 
 type ITodoService interface {
 	CreateTodo(ctx context.Context, userID pgtype.UUID, req CreateTodoRequest) (*db.Todo, error)
-	ListTodos(ctx context.Context, userID pgtype.UUID) (*[]db.Todo, error)
-	SearchTodos(ctx context.Context, userID pgtype.UUID, keyword string) (*[]db.Todo, error)
+	ListTodos(ctx context.Context, userID pgtype.UUID, req ListTodosRequest) (*TodoPage, error)
+	SearchTodos(ctx context.Context, userID pgtype.UUID, req SearchTodosRequest) (*TodoPage, error)
 	UpdateTodo(ctx context.Context, userID pgtype.UUID, todoID int32, req UpdateTodoRequest) (*db.Todo, error)
+	ListOccurrences(ctx context.Context, userID pgtype.UUID, todoID int32, req ListOccurrencesRequest) ([]time.Time, error)
 	UpdateTodoPosition(ctx context.Context, userID pgtype.UUID, todoID int32, req UpdateTodoPositionRequest) (*db.Todo, error)
+	MoveTodo(ctx context.Context, userID pgtype.UUID, todoID int32, req MoveTodoRequest) (*db.Todo, error)
 	DeleteTodo(ctx context.Context, userID pgtype.UUID, todoID int32) error
 }
+
+type IListService interface {
+	CreateList(ctx context.Context, userID pgtype.UUID, req ListRequest) (*db.List, error)
+	ListLists(ctx context.Context, userID pgtype.UUID, req ListListsRequest) ([]db.List, error)
+	GetList(ctx context.Context, userID pgtype.UUID, listID int32) (*db.List, error)
+	UpdateList(ctx context.Context, userID pgtype.UUID, listID int32, req ListRequest) (*db.List, error)
+	ArchiveList(ctx context.Context, userID pgtype.UUID, listID int32) (*db.List, error)
+	UnarchiveList(ctx context.Context, userID pgtype.UUID, listID int32) (*db.List, error)
+	DeleteList(ctx context.Context, userID pgtype.UUID, listID int32) error
+}
+
+type ITagService interface {
+	CreateTag(ctx context.Context, userID pgtype.UUID, req TagRequest) (*db.Tag, error)
+	ListTags(ctx context.Context, userID pgtype.UUID) ([]db.Tag, error)
+	UpdateTag(ctx context.Context, userID pgtype.UUID, tagID int32, req TagRequest) (*db.Tag, error)
+	DeleteTag(ctx context.Context, userID pgtype.UUID, tagID int32) error
+	AttachTag(ctx context.Context, userID pgtype.UUID, todoID, tagID int32) (*db.Todo, error)
+	DetachTag(ctx context.Context, userID pgtype.UUID, todoID, tagID int32) (*db.Todo, error)
+}
+
+type ITodoItemService interface {
+	CreateItem(ctx context.Context, userID pgtype.UUID, todoID int32, req CreateTodoItemRequest) (*db.TodoItem, error)
+	ListItems(ctx context.Context, userID pgtype.UUID, todoID int32) (*[]db.TodoItem, error)
+	UpdateItem(ctx context.Context, userID pgtype.UUID, todoID, itemID int32, req UpdateTodoItemRequest) (*db.TodoItem, error)
+	UpdateItemPosition(ctx context.Context, userID pgtype.UUID, todoID, itemID int32, req UpdateTodoPositionRequest) (*db.TodoItem, error)
+	DeleteItem(ctx context.Context, userID pgtype.UUID, todoID, itemID int32) error
+}
@@ -0,0 +1,98 @@
+package services
+
+import (
+	"context"
+	"sort"
+	"todo-app/internal/db"
+	"todo-app/internal/utils"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type SessionService struct {
+	RefreshTokenStore IRefreshTokenStore
+	SessionStore      ISessionStore
+}
+
+// Client information recorded along with a session so that the user can tell their logins apart
+type ClientInfo struct {
+	UserAgent string
+	IP        string
+}
+
+func NewSessionService(refreshTokenStore IRefreshTokenStore, sessionStore ISessionStore) *SessionService {
+	return &SessionService{RefreshTokenStore: refreshTokenStore, SessionStore: sessionStore}
+}
+
+func (s *SessionService) ListSessions(ctx context.Context, userID pgtype.UUID) ([]db.Session, error) {
+	sessions, err := s.SessionStore.List(ctx, utils.UUIDToString(userID))
+	if err != nil {
+		return nil, err
+	}
+
+	// Most recently used first
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].LastSeenAt.After(sessions[j].LastSeenAt)
+	})
+
+	return sessions, nil
+}
+
+func (s *SessionService) RevokeSession(ctx context.Context, userID pgtype.UUID, publicID string) error {
+	userIDStr := utils.UUIDToString(userID)
+
+	session, err := s.SessionStore.FindByPublicID(ctx, userIDStr, publicID)
+	if err != nil {
+		if err == db.ErrSessionNotFound {
+			return utils.ErrNoRowsMatchedSQLC
+		}
+		return err
+	}
+
+	return revokeSession(ctx, s.RefreshTokenStore, s.SessionStore, userIDStr, session.ID)
+}
+
+func (s *SessionService) RevokeAllSessions(ctx context.Context, userID pgtype.UUID) error {
+	userIDStr := utils.UUIDToString(userID)
+
+	sessions, err := s.SessionStore.List(ctx, userIDStr)
+	if err != nil {
+		return err
+	}
+
+	for _, session := range sessions {
+		if err := revokeSession(ctx, s.RefreshTokenStore, s.SessionStore, userIDStr, session.ID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Revokes every session of the user except the one given (if any)
+func revokeSessions(ctx context.Context, refreshTokenStore IRefreshTokenStore, sessionStore ISessionStore, userID, exceptSessionID string) error {
+	sessions, err := sessionStore.List(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	for _, session := range sessions {
+		if session.ID == exceptSessionID {
+			continue
+		}
+		if err := revokeSession(ctx, refreshTokenStore, sessionStore, userID, session.ID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Deleting the session makes AuthMiddleware reject its access tokens immediately, and its refresh tokens go along with it
+func revokeSession(ctx context.Context, refreshTokenStore IRefreshTokenStore, sessionStore ISessionStore, userID, sessionID string) error {
+	if err := refreshTokenStore.RevokeFamily(ctx, sessionID); err != nil {
+		return err
+	}
+
+	return sessionStore.Delete(ctx, userID, sessionID)
+}
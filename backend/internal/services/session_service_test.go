@@ -0,0 +1,132 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"todo-app/internal/db"
+	"todo-app/internal/services"
+	mock_services "todo-app/internal/services/_mock"
+	"todo-app/internal/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestSessionService(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRefreshTokenStore := mock_services.NewMockIRefreshTokenStore(ctrl)
+	mockSessionStore := mock_services.NewMockISessionStore(ctrl)
+
+	sessionService := services.NewSessionService(mockRefreshTokenStore, mockSessionStore)
+
+	uIDStr := "00010203-0405-0607-0809-0a0b0c0d0e0f"
+	uIDUuid, _ := utils.StringToUUID(uIDStr)
+	now := time.Now()
+
+	t.Run("ListSessions", func(t *testing.T) {
+		ctx := context.Background()
+
+		mockSessionStore.EXPECT().
+			List(ctx, uIDStr).
+			Return([]db.Session{
+				{ID: "session-1", LastSeenAt: now.Add(-time.Hour)},
+				{ID: "session-2", LastSeenAt: now},
+			}, nil)
+
+		sessions, err := sessionService.ListSessions(ctx, uIDUuid)
+
+		require.NoError(t, err)
+		require.Len(t, sessions, 2)
+		assert.Equal(t, "session-2", sessions[0].ID)
+		assert.Equal(t, "session-1", sessions[1].ID)
+	})
+
+	t.Run("ListSessions_StoreError", func(t *testing.T) {
+		ctx := context.Background()
+
+		mockSessionStore.EXPECT().
+			List(ctx, uIDStr).
+			Return(nil, errors.New("redis error"))
+
+		sessions, err := sessionService.ListSessions(ctx, uIDUuid)
+
+		assert.Error(t, err)
+		assert.Nil(t, sessions)
+	})
+
+	t.Run("RevokeSession", func(t *testing.T) {
+		ctx := context.Background()
+		publicID := db.SessionPublicID("session-1")
+
+		mockSessionStore.EXPECT().
+			FindByPublicID(ctx, uIDStr, publicID).
+			Return(&db.Session{ID: "session-1", PublicID: publicID, UserID: uIDStr}, nil)
+
+		mockRefreshTokenStore.EXPECT().
+			RevokeFamily(ctx, "session-1").
+			Return(nil)
+
+		mockSessionStore.EXPECT().
+			Delete(ctx, uIDStr, "session-1").
+			Return(nil)
+
+		err := sessionService.RevokeSession(ctx, uIDUuid, publicID)
+
+		require.NoError(t, err)
+	})
+
+	t.Run("RevokeSession_NotFound", func(t *testing.T) {
+		ctx := context.Background()
+
+		mockSessionStore.EXPECT().
+			FindByPublicID(ctx, uIDStr, "unknown").
+			Return(nil, db.ErrSessionNotFound)
+
+		err := sessionService.RevokeSession(ctx, uIDUuid, "unknown")
+
+		assert.Equal(t, utils.ErrNoRowsMatchedSQLC, err)
+	})
+
+	t.Run("RevokeAllSessions", func(t *testing.T) {
+		ctx := context.Background()
+
+		mockSessionStore.EXPECT().
+			List(ctx, uIDStr).
+			Return([]db.Session{{ID: "session-1"}, {ID: "session-2"}}, nil)
+
+		for _, sessionID := range []string{"session-1", "session-2"} {
+			mockRefreshTokenStore.EXPECT().
+				RevokeFamily(ctx, sessionID).
+				Return(nil)
+
+			mockSessionStore.EXPECT().
+				Delete(ctx, uIDStr, sessionID).
+				Return(nil)
+		}
+
+		err := sessionService.RevokeAllSessions(ctx, uIDUuid)
+
+		require.NoError(t, err)
+	})
+
+	t.Run("RevokeAllSessions_StoreError", func(t *testing.T) {
+		ctx := context.Background()
+
+		mockSessionStore.EXPECT().
+			List(ctx, uIDStr).
+			Return([]db.Session{{ID: "session-1"}}, nil)
+
+		mockRefreshTokenStore.EXPECT().
+			RevokeFamily(ctx, "session-1").
+			Return(errors.New("redis error"))
+
+		err := sessionService.RevokeAllSessions(ctx, uIDUuid)
+
+		assert.Error(t, err)
+	})
+}
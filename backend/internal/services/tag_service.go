@@ -0,0 +1,157 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"todo-app/internal/db"
+	"todo-app/internal/utils"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const defaultTagColor = "#808080"
+
+type TagService struct {
+	SqlClient db.WrappedQuerier
+}
+
+// Color is #rrggbb, grey if omitted
+type TagRequest struct {
+	Name  string `json:"name" binding:"required,max=50"`
+	Color string `json:"color" binding:"omitempty,len=7,hexcolor"`
+}
+
+func NewTagService(sqlClient db.WrappedQuerier) *TagService {
+	return &TagService{SqlClient: sqlClient}
+}
+
+// A name already taken by another tag of the user is reported as a unique violation
+func (s *TagService) CreateTag(ctx context.Context, userID pgtype.UUID, req TagRequest) (*db.Tag, error) {
+	user, err := s.SqlClient.GetUserByUserID(ctx, userID)
+	if err != nil {
+		return nil, utils.ErrInvalidUID
+	}
+
+	tag, err := s.SqlClient.CreateTag(ctx, db.CreateTagParams{
+		UserID: user.ID,
+		Name:   strings.TrimSpace(req.Name),
+		Color:  tagColor(req.Color),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &tag, nil
+}
+
+func (s *TagService) ListTags(ctx context.Context, userID pgtype.UUID) ([]db.Tag, error) {
+	user, err := s.SqlClient.GetUserByUserID(ctx, userID)
+	if err != nil {
+		return nil, utils.ErrInvalidUID
+	}
+
+	tags, err := s.SqlClient.ListTags(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return tags, nil
+}
+
+// The todos with the tag show the new name and color right away
+func (s *TagService) UpdateTag(ctx context.Context, userID pgtype.UUID, tagID int32, req TagRequest) (*db.Tag, error) {
+	user, err := s.SqlClient.GetUserByUserID(ctx, userID)
+	if err != nil {
+		return nil, utils.ErrInvalidUID
+	}
+
+	tag, err := s.SqlClient.UpdateTag(ctx, db.UpdateTagParams{
+		ID:     tagID,
+		UserID: user.ID,
+		Name:   strings.TrimSpace(req.Name),
+		Color:  tagColor(req.Color),
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, utils.ErrNoRowsMatchedSQLC
+		}
+		return nil, err
+	}
+
+	return &tag, nil
+}
+
+func (s *TagService) DeleteTag(ctx context.Context, userID pgtype.UUID, tagID int32) error {
+	user, err := s.SqlClient.GetUserByUserID(ctx, userID)
+	if err != nil {
+		return utils.ErrInvalidUID
+	}
+
+	rows, err := s.SqlClient.DeleteTag(ctx, db.DeleteTagParams{ID: tagID, UserID: user.ID})
+	if err != nil {
+		return err
+	} else if rows == 0 {
+		return utils.ErrNoRowsMatchedSQLC
+	}
+
+	return nil
+}
+
+// Returns the todo with its tags; attaching a tag the todo already has changes nothing
+func (s *TagService) AttachTag(ctx context.Context, userID pgtype.UUID, todoID, tagID int32) (*db.Todo, error) {
+	user, err := s.SqlClient.GetUserByUserID(ctx, userID)
+	if err != nil {
+		return nil, utils.ErrInvalidUID
+	}
+
+	if _, err = s.SqlClient.GetTag(ctx, db.GetTagParams{ID: tagID, UserID: user.ID}); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, utils.ErrNoRowsMatchedSQLC
+		}
+		return nil, err
+	}
+
+	err = s.SqlClient.AttachTodoTag(ctx, db.AttachTodoTagParams{TodoID: todoID, TagID: tagID, UserID: user.ID})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.getTodo(ctx, todoID, user.ID)
+}
+
+// Returns the todo with its remaining tags; detaching a tag the todo does not have changes nothing
+func (s *TagService) DetachTag(ctx context.Context, userID pgtype.UUID, todoID, tagID int32) (*db.Todo, error) {
+	user, err := s.SqlClient.GetUserByUserID(ctx, userID)
+	if err != nil {
+		return nil, utils.ErrInvalidUID
+	}
+
+	err = s.SqlClient.DetachTodoTag(ctx, db.DetachTodoTagParams{TodoID: todoID, TagID: tagID, UserID: user.ID})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.getTodo(ctx, todoID, user.ID)
+}
+
+// Also tells a todo that is not the user's from a successful (no-op) change
+func (s *TagService) getTodo(ctx context.Context, todoID, userID int32) (*db.Todo, error) {
+	todo, err := s.SqlClient.GetTodo(ctx, db.GetTodoParams{ID: todoID, UserID: userID})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, utils.ErrNoRowsMatchedSQLC
+		}
+		return nil, err
+	}
+
+	return &todo, nil
+}
+
+func tagColor(color string) string {
+	if color == "" {
+		return defaultTagColor
+	}
+	return strings.ToLower(color)
+}
@@ -0,0 +1,164 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"todo-app/internal/db"
+	mock_db "todo-app/internal/db/_mock"
+	"todo-app/internal/services"
+	"todo-app/internal/utils"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestTagService(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockQueries := mock_db.NewMockWrappedQuerier(ctrl)
+	tagService := services.NewTagService(mockQueries)
+
+	uIDStr := "00010203-0405-0607-0809-0a0b0c0d0e0f"
+	uIDUuid, _ := utils.StringToUUID(uIDStr)
+	var tagID int32 = 5
+	var todoID int32 = 1
+
+	expectUser := func(ctx context.Context) {
+		mockQueries.EXPECT().
+			GetUserByUserID(ctx, uIDUuid).
+			Return(db.User{ID: 1}, nil)
+	}
+
+	t.Run("CreateTag", func(t *testing.T) {
+		ctx := context.Background()
+
+		expectUser(ctx)
+
+		mockQueries.EXPECT().
+			CreateTag(ctx, db.CreateTagParams{UserID: 1, Name: "work", Color: "#ff00aa"}).
+			Return(db.Tag{ID: tagID, UserID: 1, Name: "work", Color: "#ff00aa"}, nil)
+
+		tag, err := tagService.CreateTag(ctx, uIDUuid, services.TagRequest{Name: " work ", Color: "#FF00AA"})
+
+		require.NoError(t, err)
+		assert.Equal(t, "#ff00aa", tag.Color)
+	})
+
+	t.Run("CreateTag_DefaultColor", func(t *testing.T) {
+		ctx := context.Background()
+
+		expectUser(ctx)
+
+		mockQueries.EXPECT().
+			CreateTag(ctx, db.CreateTagParams{UserID: 1, Name: "work", Color: "#808080"}).
+			Return(db.Tag{ID: tagID, UserID: 1, Name: "work", Color: "#808080"}, nil)
+
+		tag, err := tagService.CreateTag(ctx, uIDUuid, services.TagRequest{Name: "work"})
+
+		require.NoError(t, err)
+		assert.Equal(t, "#808080", tag.Color)
+	})
+
+	t.Run("CreateTag_UserNotFound", func(t *testing.T) {
+		ctx := context.Background()
+
+		mockQueries.EXPECT().
+			GetUserByUserID(ctx, uIDUuid).
+			Return(db.User{}, errors.New("user not found"))
+
+		tag, err := tagService.CreateTag(ctx, uIDUuid, services.TagRequest{Name: "work"})
+
+		assert.Equal(t, utils.ErrInvalidUID, err)
+		assert.Nil(t, tag)
+	})
+
+	t.Run("UpdateTag_NotFound", func(t *testing.T) {
+		ctx := context.Background()
+
+		expectUser(ctx)
+
+		mockQueries.EXPECT().
+			UpdateTag(ctx, db.UpdateTagParams{ID: tagID, UserID: 1, Name: "home", Color: "#808080"}).
+			Return(db.Tag{}, pgx.ErrNoRows)
+
+		tag, err := tagService.UpdateTag(ctx, uIDUuid, tagID, services.TagRequest{Name: "home"})
+
+		assert.Equal(t, utils.ErrNoRowsMatchedSQLC, err)
+		assert.Nil(t, tag)
+	})
+
+	t.Run("DeleteTag_NotFound", func(t *testing.T) {
+		ctx := context.Background()
+
+		expectUser(ctx)
+
+		mockQueries.EXPECT().
+			DeleteTag(ctx, db.DeleteTagParams{ID: tagID, UserID: 1}).
+			Return(int64(0), nil)
+
+		err := tagService.DeleteTag(ctx, uIDUuid, tagID)
+
+		assert.Equal(t, utils.ErrNoRowsMatchedSQLC, err)
+	})
+
+	t.Run("AttachTag", func(t *testing.T) {
+		ctx := context.Background()
+
+		expectUser(ctx)
+
+		mockQueries.EXPECT().
+			GetTag(ctx, db.GetTagParams{ID: tagID, UserID: 1}).
+			Return(db.Tag{ID: tagID, UserID: 1, Name: "work"}, nil)
+
+		mockQueries.EXPECT().
+			AttachTodoTag(ctx, db.AttachTodoTagParams{TodoID: todoID, TagID: tagID, UserID: 1}).
+			Return(nil)
+
+		mockQueries.EXPECT().
+			GetTodo(ctx, db.GetTodoParams{ID: todoID, UserID: 1}).
+			Return(db.Todo{ID: todoID, Tags: []byte(`[{"id": 5, "name": "work", "color": "#808080"}]`)}, nil)
+
+		todo, err := tagService.AttachTag(ctx, uIDUuid, todoID, tagID)
+
+		require.NoError(t, err)
+		assert.Equal(t, todoID, todo.ID)
+	})
+
+	t.Run("AttachTag_TagNotFound", func(t *testing.T) {
+		ctx := context.Background()
+
+		expectUser(ctx)
+
+		mockQueries.EXPECT().
+			GetTag(ctx, db.GetTagParams{ID: tagID, UserID: 1}).
+			Return(db.Tag{}, pgx.ErrNoRows)
+
+		todo, err := tagService.AttachTag(ctx, uIDUuid, todoID, tagID)
+
+		assert.Equal(t, utils.ErrNoRowsMatchedSQLC, err)
+		assert.Nil(t, todo)
+	})
+
+	t.Run("DetachTag_TodoNotFound", func(t *testing.T) {
+		ctx := context.Background()
+
+		expectUser(ctx)
+
+		mockQueries.EXPECT().
+			DetachTodoTag(ctx, db.DetachTodoTagParams{TodoID: todoID, TagID: tagID, UserID: 1}).
+			Return(nil)
+
+		mockQueries.EXPECT().
+			GetTodo(ctx, db.GetTodoParams{ID: todoID, UserID: 1}).
+			Return(db.Todo{}, pgx.ErrNoRows)
+
+		todo, err := tagService.DetachTag(ctx, uIDUuid, todoID, tagID)
+
+		assert.Equal(t, utils.ErrNoRowsMatchedSQLC, err)
+		assert.Nil(t, todo)
+	})
+}
@@ -0,0 +1,133 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"todo-app/internal/db"
+	"todo-app/internal/utils"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type TodoItemService struct {
+	SqlClient db.WrappedQuerier
+}
+
+type CreateTodoItemRequest struct {
+	Description string `json:"description" binding:"required"`
+}
+
+type UpdateTodoItemRequest struct {
+	Description string `json:"description" binding:"required"`
+	Completed   bool   `json:"completed"`
+}
+
+func NewTodoItemService(sqlClient db.WrappedQuerier) *TodoItemService {
+	return &TodoItemService{SqlClient: sqlClient}
+}
+
+func (s *TodoItemService) CreateItem(ctx context.Context, userID pgtype.UUID, todoID int32, req CreateTodoItemRequest) (*db.TodoItem, error) {
+	if err := s.checkTodo(ctx, userID, todoID); err != nil {
+		return nil, err
+	}
+
+	item, err := s.SqlClient.CreateTodoItem(ctx, db.CreateTodoItemParams{
+		TodoID:      todoID,
+		Description: req.Description,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &item, nil
+}
+
+func (s *TodoItemService) ListItems(ctx context.Context, userID pgtype.UUID, todoID int32) (*[]db.TodoItem, error) {
+	if err := s.checkTodo(ctx, userID, todoID); err != nil {
+		return nil, err
+	}
+
+	items, err := s.SqlClient.ListTodoItems(ctx, todoID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &items, nil
+}
+
+func (s *TodoItemService) UpdateItem(ctx context.Context, userID pgtype.UUID, todoID, itemID int32, req UpdateTodoItemRequest) (*db.TodoItem, error) {
+	if err := s.checkTodo(ctx, userID, todoID); err != nil {
+		return nil, err
+	}
+
+	item, err := s.SqlClient.UpdateTodoItem(ctx, db.UpdateTodoItemParams{
+		ID:          itemID,
+		TodoID:      todoID,
+		Description: req.Description,
+		Completed:   req.Completed,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, utils.ErrNoRowsMatchedSQLC
+		}
+		return nil, err
+	}
+
+	return &item, nil
+}
+
+// Moves the item between two others of the same todo, the same way as UpdateTodoPosition
+func (s *TodoItemService) UpdateItemPosition(ctx context.Context, userID pgtype.UUID, todoID, itemID int32, req UpdateTodoPositionRequest) (*db.TodoItem, error) {
+	if err := s.checkTodo(ctx, userID, todoID); err != nil {
+		return nil, err
+	}
+
+	item, err := s.SqlClient.UpdateTodoItemPosition(ctx, db.UpdateTodoItemPositionParams{
+		ID:      itemID,
+		TodoID:  todoID,
+		Prevpos: pgtype.Numeric{Int: big.NewInt(req.Prevpos), Valid: true},
+		Nextpos: pgtype.Numeric{Int: big.NewInt(req.Nextpos), Valid: true},
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, utils.ErrNoRowsMatchedSQLC
+		}
+		return nil, err
+	}
+
+	return &item, nil
+}
+
+func (s *TodoItemService) DeleteItem(ctx context.Context, userID pgtype.UUID, todoID, itemID int32) error {
+	if err := s.checkTodo(ctx, userID, todoID); err != nil {
+		return err
+	}
+
+	rows, err := s.SqlClient.DeleteTodoItem(ctx, db.DeleteTodoItemParams{ID: itemID, TodoID: todoID})
+	if err != nil {
+		return err
+	} else if rows == 0 {
+		return utils.ErrNoRowsMatchedSQLC
+	}
+
+	return nil
+}
+
+// Items are only reachable through a todo of the user, so that nobody can see or change the checklists of others
+func (s *TodoItemService) checkTodo(ctx context.Context, userID pgtype.UUID, todoID int32) error {
+	user, err := s.SqlClient.GetUserByUserID(ctx, userID)
+	if err != nil {
+		return utils.ErrInvalidUID
+	}
+
+	if _, err = s.SqlClient.GetTodo(ctx, db.GetTodoParams{ID: todoID, UserID: user.ID}); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return utils.ErrNoRowsMatchedSQLC
+		}
+		return err
+	}
+
+	return nil
+}
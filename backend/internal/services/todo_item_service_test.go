@@ -0,0 +1,164 @@
+package services_test
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"todo-app/internal/db"
+	mock_db "todo-app/internal/db/_mock"
+	"todo-app/internal/services"
+	"todo-app/internal/utils"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestTodoItemService(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockQueries := mock_db.NewMockWrappedQuerier(ctrl)
+	todoItemService := services.NewTodoItemService(mockQueries)
+
+	uIDStr := "00010203-0405-0607-0809-0a0b0c0d0e0f"
+	uIDUuid, _ := utils.StringToUUID(uIDStr)
+	var todoID int32 = 1
+	var itemID int32 = 2
+
+	expectTodo := func(ctx context.Context, err error) {
+		mockQueries.EXPECT().
+			GetUserByUserID(ctx, uIDUuid).
+			Return(db.User{ID: 1}, nil)
+
+		mockQueries.EXPECT().
+			GetTodo(ctx, db.GetTodoParams{ID: todoID, UserID: 1}).
+			Return(db.Todo{ID: todoID}, err)
+	}
+
+	t.Run("CreateItem", func(t *testing.T) {
+		ctx := context.Background()
+		req := services.CreateTodoItemRequest{Description: "Passport"}
+
+		expectTodo(ctx, nil)
+
+		mockQueries.EXPECT().
+			CreateTodoItem(ctx, db.CreateTodoItemParams{TodoID: todoID, Description: req.Description}).
+			Return(db.TodoItem{ID: itemID, TodoID: todoID, Description: req.Description}, nil)
+
+		item, err := todoItemService.CreateItem(ctx, uIDUuid, todoID, req)
+
+		require.NoError(t, err)
+		assert.Equal(t, req.Description, item.Description)
+	})
+
+	t.Run("CreateItem_TodoNotFound", func(t *testing.T) {
+		ctx := context.Background()
+
+		// Someone else's todo looks the same as a missing one
+		expectTodo(ctx, pgx.ErrNoRows)
+
+		item, err := todoItemService.CreateItem(ctx, uIDUuid, todoID, services.CreateTodoItemRequest{Description: "Passport"})
+
+		assert.Equal(t, utils.ErrNoRowsMatchedSQLC, err)
+		assert.Nil(t, item)
+	})
+
+	t.Run("ListItems", func(t *testing.T) {
+		ctx := context.Background()
+
+		expectTodo(ctx, nil)
+
+		mockQueries.EXPECT().
+			ListTodoItems(ctx, todoID).
+			Return([]db.TodoItem{{ID: itemID, TodoID: todoID, Description: "Passport"}}, nil)
+
+		items, err := todoItemService.ListItems(ctx, uIDUuid, todoID)
+
+		require.NoError(t, err)
+		assert.Len(t, *items, 1)
+	})
+
+	t.Run("UpdateItem", func(t *testing.T) {
+		ctx := context.Background()
+		req := services.UpdateTodoItemRequest{Description: "Passport", Completed: true}
+
+		expectTodo(ctx, nil)
+
+		mockQueries.EXPECT().
+			UpdateTodoItem(ctx, db.UpdateTodoItemParams{ID: itemID, TodoID: todoID, Description: req.Description, Completed: true}).
+			Return(db.TodoItem{ID: itemID, TodoID: todoID, Description: req.Description, Completed: true}, nil)
+
+		item, err := todoItemService.UpdateItem(ctx, uIDUuid, todoID, itemID, req)
+
+		require.NoError(t, err)
+		assert.True(t, item.Completed)
+	})
+
+	t.Run("UpdateItem_NotFound", func(t *testing.T) {
+		ctx := context.Background()
+		req := services.UpdateTodoItemRequest{Description: "Passport", Completed: true}
+
+		expectTodo(ctx, nil)
+
+		mockQueries.EXPECT().
+			UpdateTodoItem(ctx, gomock.Any()).
+			Return(db.TodoItem{}, pgx.ErrNoRows)
+
+		item, err := todoItemService.UpdateItem(ctx, uIDUuid, todoID, itemID, req)
+
+		assert.Equal(t, utils.ErrNoRowsMatchedSQLC, err)
+		assert.Nil(t, item)
+	})
+
+	t.Run("UpdateItemPosition", func(t *testing.T) {
+		ctx := context.Background()
+		req := services.UpdateTodoPositionRequest{Prevpos: 100, Nextpos: 200}
+
+		expectTodo(ctx, nil)
+
+		mockQueries.EXPECT().
+			UpdateTodoItemPosition(ctx, db.UpdateTodoItemPositionParams{
+				ID:      itemID,
+				TodoID:  todoID,
+				Prevpos: pgtype.Numeric{Int: big.NewInt(100), Valid: true},
+				Nextpos: pgtype.Numeric{Int: big.NewInt(200), Valid: true},
+			}).
+			Return(db.TodoItem{ID: itemID, Position: pgtype.Numeric{Int: big.NewInt(150), Valid: true}}, nil)
+
+		item, err := todoItemService.UpdateItemPosition(ctx, uIDUuid, todoID, itemID, req)
+
+		require.NoError(t, err)
+		assert.Equal(t, int64(150), item.Position.Int.Int64())
+	})
+
+	t.Run("DeleteItem", func(t *testing.T) {
+		ctx := context.Background()
+
+		expectTodo(ctx, nil)
+
+		mockQueries.EXPECT().
+			DeleteTodoItem(ctx, db.DeleteTodoItemParams{ID: itemID, TodoID: todoID}).
+			Return(int64(1), nil)
+
+		err := todoItemService.DeleteItem(ctx, uIDUuid, todoID, itemID)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("DeleteItem_NotFound", func(t *testing.T) {
+		ctx := context.Background()
+
+		expectTodo(ctx, nil)
+
+		mockQueries.EXPECT().
+			DeleteTodoItem(ctx, db.DeleteTodoItemParams{ID: itemID, TodoID: todoID}).
+			Return(int64(0), nil)
+
+		err := todoItemService.DeleteItem(ctx, uIDUuid, todoID, itemID)
+
+		assert.Equal(t, utils.ErrNoRowsMatchedSQLC, err)
+	})
+}
@@ -0,0 +1,204 @@
+package services
+
+import (
+	"encoding/json"
+	"time"
+	"todo-app/internal/db"
+	"todo-app/internal/utils"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const (
+	defaultTodoPageLimit = 50
+	maxTodoPageLimit     = 200
+	// What cursors of search results are issued for, as those are ordered by relevance rather than a sort
+	searchTodosSort = "rank"
+)
+
+// Giving either a limit or a cursor asks for a page instead of every todo. The cursor is the next_cursor of the previous page.
+type PageRequest struct {
+	Limit  int32  `form:"limit" binding:"omitempty,min=1,max=200"`
+	Cursor string `form:"cursor"`
+}
+
+type TodoPage struct {
+	Todos []db.Todo
+	// Empty on the last page
+	NextCursor string
+	HasMore    bool
+	// Snippets of the search matches by todo id, with the matched words between \x02 and \x03
+	Headlines map[int32]string
+}
+
+// The sort and the sort values of the last todo of a page, which the next page starts after
+type todoCursor struct {
+	Sort        string     `json:"sort"`
+	ID          int32      `json:"id"`
+	ListID      int32      `json:"list_id"`
+	Position    string     `json:"position"`
+	Priority    int16      `json:"priority"`
+	DueAt       *time.Time `json:"due_at"`
+	CreatedAt   *time.Time `json:"created_at"`
+	UpdatedAt   *time.Time `json:"updated_at"`
+	Description *string    `json:"description,omitempty"`
+	Rank        float32    `json:"rank,omitempty"`
+}
+
+func (r PageRequest) Paginated() bool {
+	return r.Limit > 0 || r.Cursor != ""
+}
+
+// What to fetch for the request: one todo more than the limit tells whether another page follows
+func (s *TodoService) pageParams(userID pgtype.UUID, req PageRequest, sort string) (db.TodoPageParams, error) {
+	if !req.Paginated() {
+		return db.TodoPageParams{}, nil
+	}
+
+	limit := req.Limit
+	if limit == 0 {
+		limit = defaultTodoPageLimit
+	}
+	page := db.TodoPageParams{Limit: min(limit, maxTodoPageLimit) + 1}
+
+	if req.Cursor != "" {
+		cursor, err := s.decodeTodoCursor(userID, req.Cursor, sort)
+		if err != nil {
+			return db.TodoPageParams{}, err
+		}
+		if page.After, err = cursor.after(); err != nil {
+			return db.TodoPageParams{}, err
+		}
+		page.AfterRank = cursor.Rank
+	}
+
+	return page, nil
+}
+
+func (s *TodoService) todoPage(userID pgtype.UUID, todos []db.Todo, page db.TodoPageParams, sort string, sortKeys []db.TodoSortKey) (*TodoPage, error) {
+	if page.Limit == 0 || len(todos) < int(page.Limit) {
+		return &TodoPage{Todos: todos}, nil
+	}
+
+	todos = todos[:page.Limit-1]
+	cursor, err := newTodoCursor(todos[len(todos)-1], sort, sortKeys)
+	if err != nil {
+		return nil, err
+	}
+	token, err := s.encodeTodoCursor(userID, cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TodoPage{Todos: todos, NextCursor: token, HasMore: true}, nil
+}
+
+// Like todoPage, along with the headlines of the matches. The cursor keeps the rank of the last match.
+func (s *TodoService) searchPage(userID pgtype.UUID, matches []db.SearchTodosRow, page db.TodoPageParams) (*TodoPage, error) {
+	hasMore := page.Limit > 0 && len(matches) >= int(page.Limit)
+	if hasMore {
+		matches = matches[:page.Limit-1]
+	}
+
+	result := &TodoPage{Todos: make([]db.Todo, len(matches)), HasMore: hasMore, Headlines: make(map[int32]string, len(matches))}
+	for i, match := range matches {
+		result.Todos[i] = match.Todo
+		result.Headlines[match.Todo.ID] = match.Headline
+	}
+	if !hasMore {
+		return result, nil
+	}
+
+	last := matches[len(matches)-1]
+	cursor, err := newTodoCursor(last.Todo, searchTodosSort, nil)
+	if err != nil {
+		return nil, err
+	}
+	cursor.Rank = last.Rank
+	if result.NextCursor, err = s.encodeTodoCursor(userID, cursor); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// The description is only kept when sorting by it, as it can be long
+func newTodoCursor(last db.Todo, sort string, sortKeys []db.TodoSortKey) (todoCursor, error) {
+	position, err := last.Position.Value()
+	if err != nil {
+		return todoCursor{}, err
+	}
+
+	cursor := todoCursor{
+		Sort:      sort,
+		ID:        last.ID,
+		ListID:    last.ListID,
+		Priority:  last.Priority,
+		DueAt:     timestamptzPtr(last.DueAt),
+		CreatedAt: timestamptzPtr(last.CreatedAt),
+		UpdatedAt: timestamptzPtr(last.UpdatedAt),
+	}
+	if position, ok := position.(string); ok {
+		cursor.Position = position
+	}
+	for _, key := range sortKeys {
+		if key.Column == "description" {
+			cursor.Description = &last.Description
+		}
+	}
+
+	return cursor, nil
+}
+
+func (s *TodoService) encodeTodoCursor(userID pgtype.UUID, cursor todoCursor) (string, error) {
+	payload, err := json.Marshal(cursor)
+	if err != nil {
+		return "", err
+	}
+
+	return s.TokenGenerator.GenerateTodoCursor(utils.UUIDToString(userID), payload)
+}
+
+// A cursor only works for the user and the sort it was issued for
+func (s *TodoService) decodeTodoCursor(userID pgtype.UUID, token, sort string) (*todoCursor, error) {
+	claims, err := s.TokenGenerator.ValidateTodoCursor(token)
+	if err != nil || claims.TokenType != TokenTypeTodoCursor || claims.UserID != utils.UUIDToString(userID) {
+		return nil, utils.ErrInvalidCursor
+	}
+
+	var cursor todoCursor
+	if err := json.Unmarshal(claims.Cursor, &cursor); err != nil || cursor.Sort != sort {
+		return nil, utils.ErrInvalidCursor
+	}
+
+	return &cursor, nil
+}
+
+// The todo the next page starts after, with only its sort values set
+func (c *todoCursor) after() (*db.Todo, error) {
+	after := &db.Todo{
+		ID:        c.ID,
+		ListID:    c.ListID,
+		Priority:  c.Priority,
+		DueAt:     optionalTimestamptz(c.DueAt),
+		CreatedAt: optionalTimestamptz(c.CreatedAt),
+		UpdatedAt: optionalTimestamptz(c.UpdatedAt),
+	}
+	if c.Position != "" {
+		if err := after.Position.Scan(c.Position); err != nil {
+			return nil, utils.ErrInvalidCursor
+		}
+	}
+	if c.Description != nil {
+		after.Description = *c.Description
+	}
+
+	return after, nil
+}
+
+func optionalTimestamptz(t *time.Time) pgtype.Timestamptz {
+	if t == nil {
+		return pgtype.Timestamptz{}
+	}
+	return pgtype.Timestamptz{Time: *t, Valid: true}
+}
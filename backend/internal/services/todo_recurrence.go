@@ -0,0 +1,113 @@
+package services
+
+import (
+	"time"
+	"todo-app/internal/db"
+	"todo-app/internal/utils"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/teambition/rrule-go"
+)
+
+const (
+	defaultOccurrencesPreview = 5
+	maxOccurrencesPreview     = 50
+)
+
+// Validates an RRULE (RFC 5545) and returns it normalized; a missing or empty value is null.
+// The due date is the start of the recurrence, so the rule must not have a DTSTART of its own.
+// Todos are about days rather than hours, so rules repeating more often than daily are rejected.
+func parseRecurrence(value *string, dueAt pgtype.Timestamptz) (pgtype.Text, error) {
+	if value == nil || *value == "" {
+		return pgtype.Text{}, nil
+	}
+
+	opt, err := rrule.StrToROption(*value)
+	if err != nil || !opt.Dtstart.IsZero() || opt.Freq > rrule.DAILY || opt.Count < 0 || opt.Interval < 0 {
+		return pgtype.Text{}, utils.ErrInvalidRecurrence
+	}
+	if _, err = rrule.NewRRule(*opt); err != nil {
+		return pgtype.Text{}, utils.ErrInvalidRecurrence
+	}
+
+	if !dueAt.Valid {
+		return pgtype.Text{}, utils.ErrRecurrenceWithoutDueDate
+	}
+
+	return pgtype.Text{String: opt.RRuleString(), Valid: true}, nil
+}
+
+// The rule starting at the due date. It is evaluated in the user's time zone, so that e.g. a weekly todo
+// stays on the same weekday and local time across daylight saving time changes.
+func recurrenceRule(recurrence string, dueAt time.Time, loc *time.Location) (*rrule.RRule, *rrule.ROption, error) {
+	opt, err := rrule.StrToROption(recurrence)
+	if err != nil {
+		return nil, nil, err
+	}
+	opt.Dtstart = dueAt.In(loc)
+
+	rule, err := rrule.NewRRule(*opt)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return rule, opt, nil
+}
+
+// Occurrences after the due date of the todo, at most count of them
+func upcomingOccurrences(todo db.Todo, loc *time.Location, count int) ([]time.Time, error) {
+	occurrences := []time.Time{}
+	if !todo.Recurrence.Valid || !todo.DueAt.Valid {
+		return occurrences, nil
+	}
+
+	rule, _, err := recurrenceRule(todo.Recurrence.String, todo.DueAt.Time, loc)
+	if err != nil {
+		return nil, err
+	}
+
+	next := rule.Iterator()
+	for len(occurrences) < count {
+		t, ok := next()
+		if !ok {
+			break
+		}
+		if t.After(todo.DueAt.Time) {
+			occurrences = append(occurrences, t)
+		}
+	}
+
+	return occurrences, nil
+}
+
+// The todo following a completed one, due at the next occurrence of its rule; nil once the rule has ended.
+// The reminder keeps the same offset from the due date, and the rule is carried over with one occurrence less if it has a COUNT.
+func nextOccurrence(todo db.UpdateTodoParams, loc *time.Location) (*db.CreateTodoParams, error) {
+	rule, opt, err := recurrenceRule(todo.Recurrence.String, todo.DueAt.Time, loc)
+	if err != nil {
+		return nil, err
+	}
+
+	dueAt := rule.After(opt.Dtstart, false)
+	if dueAt.IsZero() {
+		return nil, nil
+	}
+
+	if opt.Count > 0 {
+		opt.Count--
+	}
+
+	next := &db.CreateTodoParams{
+		UserID:      todo.UserID,
+		Description: todo.Description,
+		DueAt:       pgtype.Timestamptz{Time: dueAt, Valid: true},
+		Recurrence:  pgtype.Text{String: opt.RRuleString(), Valid: true},
+		Priority:    todo.Priority,
+		Language:    todo.Language,
+	}
+	if todo.RemindAt.Valid {
+		next.RemindAt = pgtype.Timestamptz{Time: dueAt.Add(todo.RemindAt.Time.Sub(todo.DueAt.Time)), Valid: true}
+	}
+
+	return next, nil
+}
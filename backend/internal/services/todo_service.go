@@ -2,25 +2,119 @@ package services
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"math/big"
+	"slices"
+	"strings"
+	"time"
 	"todo-app/internal/db"
 	"todo-app/internal/utils"
+	"unicode"
+	"unicode/utf8"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
 type TodoService struct {
-	SqlClient db.WrappedQuerier
+	SqlClient      db.WrappedQuerier
+	TokenGenerator ITokenGenerator
 }
 
+// In rank order, as todos.priority stores the index
+var TodoPriorities = []string{"none", "low", "medium", "high", "urgent"}
+
+// The languages a todo can be written in, which are also the names of their text search configurations
+const (
+	languageEnglish  = "english"
+	languageJapanese = "japanese"
+)
+
+// Due and reminder times are RFC 3339 timestamps or dates (YYYY-MM-DD) in the user's time zone.
+// A due date means the end of that day and a reminder date the start of it.
+// Recurrence is an RRULE such as FREQ=WEEKLY;BYDAY=MO, starting at the due date.
+// The todo goes to the end of the given list, or of the inbox without one.
+// Without a language, descriptions with kana or kanji are taken as Japanese and the others as English.
 type CreateTodoRequest struct {
-	Description string `json:"description" binding:"required"`
+	Description string  `json:"description" binding:"required"`
+	ListID      *int32  `json:"list_id"`
+	DueAt       *string `json:"due_at"`
+	RemindAt    *string `json:"remind_at"`
+	Recurrence  *string `json:"recurrence"`
+	Priority    string  `json:"priority" binding:"omitempty,oneof=none low medium high urgent"`
+	Language    string  `json:"language" binding:"omitempty,oneof=english japanese"`
 }
 
+// Leaving out due_at, remind_at, recurrence or priority keeps it as it is, while null or "" clears any of the first three
 type UpdateTodoRequest struct {
-	Description string `json:"description" binding:"required"`
-	Completed   bool   `json:"completed"`
-	Position    int64  `json:"position" binding:"required"`
+	Description string  `json:"description" binding:"required"`
+	Completed   bool    `json:"completed"`
+	Position    int64   `json:"position" binding:"required"`
+	DueAt       *string `json:"due_at"`
+	RemindAt    *string `json:"remind_at"`
+	Recurrence  *string `json:"recurrence"`
+	Priority    string  `json:"priority" binding:"omitempty,oneof=none low medium high urgent"`
+	Language    string  `json:"language" binding:"omitempty,oneof=english japanese"`
+	// Completes the checklist items as well when the todo is completed
+	CompleteItems bool `json:"complete_items"`
+}
+
+// Both a missing field and null leave a pointer nil, so null is turned into "" to tell clearing a field from leaving it out
+func (r *UpdateTodoRequest) UnmarshalJSON(data []byte) error {
+	type plain UpdateTodoRequest
+	if err := json.Unmarshal(data, (*plain)(r)); err != nil {
+		return err
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	for name, field := range map[string]**string{"due_at": &r.DueAt, "remind_at": &r.RemindAt, "recurrence": &r.Recurrence} {
+		if value, ok := fields[name]; ok && string(value) == "null" {
+			*field = new(string)
+		}
+	}
+
+	return nil
+}
+
+// Tag names given as tag=a&tag=b, matching todos with all of them (default) or any of them
+type TagFilter struct {
+	Tags    []string `form:"tag"`
+	TagMode string   `form:"tag_mode" binding:"omitempty,oneof=all any"`
+}
+
+// Todos of the list, or of every list not archived without one.
+// Due, creation and update dates in the ranges [*_after, *_before); dates mean the start of that day in the user's time zone.
+// Q keeps the todos whose description contains it, ignoring case.
+// Sort is a comma separated list of columns, each descending with a leading "-", e.g. -priority,due_at;
+// without one the user's default sort applies
+type ListTodosRequest struct {
+	ListID        *int32 `form:"list_id"`
+	DueBefore     string `form:"due_before"`
+	DueAfter      string `form:"due_after"`
+	Overdue       bool   `form:"overdue"`
+	Completed     *bool  `form:"completed"`
+	CreatedAfter  string `form:"created_after"`
+	CreatedBefore string `form:"created_before"`
+	UpdatedAfter  string `form:"updated_after"`
+	UpdatedBefore string `form:"updated_before"`
+	Q             string `form:"q"`
+	Sort          string `form:"sort"`
+	TagFilter
+	PageRequest
+}
+
+type SearchTodosRequest struct {
+	Keyword string `form:"keyword" binding:"required"`
+	TagFilter
+	PageRequest
+}
+
+type ListOccurrencesRequest struct {
+	Count int `form:"count" binding:"omitempty,min=1,max=50"`
 }
 
 type UpdateTodoPositionRequest struct {
@@ -28,19 +122,55 @@ type UpdateTodoPositionRequest struct {
 	Nextpos int64 `json:"next_pos" binding:"required"`
 }
 
-func NewTodoService(sqlClient db.WrappedQuerier) *TodoService {
-	return &TodoService{SqlClient: sqlClient}
+// Neither position means the end of the list, and only the previous one right after it
+type MoveTodoRequest struct {
+	ListID  int32  `json:"list_id" binding:"required"`
+	Prevpos *int64 `json:"prev_pos"`
+	Nextpos *int64 `json:"next_pos"`
+}
+
+func NewTodoService(sqlClient db.WrappedQuerier, tokenGenerator ITokenGenerator) *TodoService {
+	return &TodoService{SqlClient: sqlClient, TokenGenerator: tokenGenerator}
 }
 
 func (s *TodoService) CreateTodo(ctx context.Context, userID pgtype.UUID, req CreateTodoRequest) (*db.Todo, error) {
+	if !validDescription(req.Description) {
+		return nil, utils.ErrInvalidDescription
+	}
+
 	user, err := s.SqlClient.GetUserByUserID(ctx, userID)
 	if err != nil {
 		return nil, utils.ErrInvalidUID
 	}
 
+	parseTime := s.timeParser(ctx, user.ID)
+	dueAt, err := parseTime(req.DueAt, true)
+	if err != nil {
+		return nil, err
+	}
+	remindAt, err := parseTime(req.RemindAt, false)
+	if err != nil {
+		return nil, err
+	}
+	recurrence, err := parseRecurrence(req.Recurrence, dueAt)
+	if err != nil {
+		return nil, err
+	}
+
+	listID, err := s.targetListID(ctx, user.ID, req.ListID)
+	if err != nil {
+		return nil, err
+	}
+
 	todo, err := s.SqlClient.CreateTodo(ctx, db.CreateTodoParams{
 		UserID:      user.ID,
+		ListID:      listID,
 		Description: req.Description,
+		DueAt:       dueAt,
+		RemindAt:    remindAt,
+		Recurrence:  recurrence,
+		Priority:    priorityRank(req.Priority),
+		Language:    todoLanguage(req.Language, req.Description),
 	})
 	if err != nil {
 		return nil, err
@@ -49,49 +179,289 @@ func (s *TodoService) CreateTodo(ctx context.Context, userID pgtype.UUID, req Cr
 	return &todo, nil
 }
 
-func (s *TodoService) ListTodos(ctx context.Context, userID pgtype.UUID) (*[]db.Todo, error) {
+// Every todo, or a page of them if the request asks for one
+func (s *TodoService) ListTodos(ctx context.Context, userID pgtype.UUID, req ListTodosRequest) (*TodoPage, error) {
 	user, err := s.SqlClient.GetUserByUserID(ctx, userID)
 	if err != nil {
 		return nil, utils.ErrInvalidUID
 	}
 
-	todos, err := s.SqlClient.ListTodos(ctx, user.ID)
+	parseTime := s.timeParser(ctx, user.ID)
+	dueBefore, err := parseTime(&req.DueBefore, false)
+	if err != nil {
+		return nil, err
+	}
+	dueAfter, err := parseTime(&req.DueAfter, false)
+	if err != nil {
+		return nil, err
+	}
+
+	filter, err := req.filter(parseTime)
+	if err != nil {
+		return nil, err
+	}
+
+	var listID pgtype.Int4
+	if req.ListID != nil {
+		listID = pgtype.Int4{Int32: *req.ListID, Valid: true}
+	}
+
+	sort := req.Sort
+	if sort == "" {
+		prefs, err := getUserPreferences(ctx, s.SqlClient, user.ID)
+		if err != nil {
+			return nil, err
+		}
+		sort = prefs.DefaultTodoSort
+	}
+	sortKeys, err := parseTodoSort(sort)
+	if err != nil {
+		return nil, err
+	}
+	page, err := s.pageParams(userID, req.PageRequest, sort)
 	if err != nil {
 		return nil, err
 	}
 
-	return &todos, nil
+	tags, matchAllTags := req.TagFilter.params()
+	params := db.ListTodosParams{
+		UserID:       user.ID,
+		ListID:       listID,
+		DueBefore:    dueBefore,
+		DueAfter:     dueAfter,
+		Overdue:      req.Overdue,
+		Tags:         tags,
+		MatchAllTags: matchAllTags,
+	}
+
+	var todos []db.Todo
+	// All todos in the manual order is what ListTodos is written for; anything else needs the query built
+	if filter == (db.TodoFilter{}) && page == (db.TodoPageParams{}) && slices.Equal(sortKeys, db.TodoManualSort) {
+		todos, err = s.SqlClient.ListTodos(ctx, params)
+	} else {
+		todos, err = s.SqlClient.ListTodosFiltered(ctx, params, filter, sortKeys, page)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return s.todoPage(userID, todos, page, sort, sortKeys)
 }
 
-func (s *TodoService) SearchTodos(ctx context.Context, userID pgtype.UUID, keyword string) (*[]db.Todo, error) {
+// The filters ListTodos has no parameters for
+func (r ListTodosRequest) filter(parseTime func(value *string, endOfDay bool) (pgtype.Timestamptz, error)) (db.TodoFilter, error) {
+	var filter db.TodoFilter
+	if r.Completed != nil {
+		filter.Completed = pgtype.Bool{Bool: *r.Completed, Valid: true}
+	}
+
+	var err error
+	for _, bound := range []struct {
+		value *string
+		dst   *pgtype.Timestamptz
+	}{
+		{&r.CreatedAfter, &filter.CreatedAfter},
+		{&r.CreatedBefore, &filter.CreatedBefore},
+		{&r.UpdatedAfter, &filter.UpdatedAfter},
+		{&r.UpdatedBefore, &filter.UpdatedBefore},
+	} {
+		if *bound.dst, err = parseTime(bound.value, false); err != nil {
+			return db.TodoFilter{}, err
+		}
+	}
+
+	filter.Query = strings.TrimSpace(r.Q)
+
+	return filter, nil
+}
+
+// Every match, or a page of them if the request asks for one, the most relevant first
+func (s *TodoService) SearchTodos(ctx context.Context, userID pgtype.UUID, req SearchTodosRequest) (*TodoPage, error) {
 	user, err := s.SqlClient.GetUserByUserID(ctx, userID)
 	if err != nil {
 		return nil, utils.ErrInvalidUID
 	}
 
-	todos, err := s.SqlClient.SearchTodos(ctx, db.SearchTodosParams{
-		UserID:    user.ID,
-		ToTsquery: keyword,
-	})
+	page, err := s.pageParams(userID, req.PageRequest, searchTodosSort)
+	if err != nil {
+		return nil, err
+	}
+
+	prefixOf, prefix := typeAheadPrefix(req.Keyword)
+	fuzzy := fuzzyKeyword(req.Keyword)
+	tags, matchAllTags := req.TagFilter.params()
+	params := db.SearchTodosParams{
+		UserID:       user.ID,
+		Keyword:      req.Keyword,
+		Prefix:       prefix,
+		PrefixOf:     prefixOf,
+		Fuzzy:        fuzzy,
+		Tags:         tags,
+		MatchAllTags: matchAllTags,
+	}
+	if fuzzy != "" {
+		params.Pattern = db.ContainsPattern(fuzzy)
+	}
+
+	var matches []db.SearchTodosRow
+	if page == (db.TodoPageParams{}) {
+		matches, err = s.SqlClient.SearchTodos(ctx, params)
+	} else {
+		matches, err = s.SqlClient.SearchTodosPage(ctx, params, page)
+	}
 	if err != nil {
 		return nil, err
 	}
 
-	return &todos, nil
+	return s.searchPage(userID, matches, page)
 }
 
+// The words of the keyword for the trigram fallback, without quotes and or. Nothing with an exclusion (-word),
+// as the fallback would find the todos excluded.
+func fuzzyKeyword(keyword string) string {
+	var words []string
+	for _, word := range strings.Fields(strings.ReplaceAll(keyword, `"`, " ")) {
+		if len(word) > 1 && strings.HasPrefix(word, "-") {
+			return ""
+		}
+		if !strings.EqualFold(word, "or") {
+			words = append(words, word)
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// The last word of the keyword if it is still being typed, along with the keyword before it.
+// That is a word ending the keyword on its own, and not an exclusion (-word) or the end of a phrase ("...").
+func typeAheadPrefix(keyword string) (string, string) {
+	start := len(keyword)
+	for start > 0 {
+		r, size := utf8.DecodeLastRuneInString(keyword[:start])
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			break
+		}
+		start -= size
+	}
+	if start == len(keyword) {
+		return "", ""
+	}
+	if r, _ := utf8.DecodeLastRuneInString(keyword[:start]); start > 0 && !unicode.IsSpace(r) {
+		return "", ""
+	}
+
+	return keyword[:start], keyword[start:]
+}
+
+// Completing a recurring todo creates its next occurrence, with a fresh copy of the checklist and the same tags, in the same transaction.
+// The rule moves over to the new todo, so that completing the todo again after reopening it does not create another one.
 func (s *TodoService) UpdateTodo(ctx context.Context, userID pgtype.UUID, todoID int32, req UpdateTodoRequest) (*db.Todo, error) {
+	if !validDescription(req.Description) {
+		return nil, utils.ErrInvalidDescription
+	}
+
 	user, err := s.SqlClient.GetUserByUserID(ctx, userID)
 	if err != nil {
 		return nil, utils.ErrInvalidUID
 	}
 
-	todo, err := s.SqlClient.UpdateTodo(ctx, db.UpdateTodoParams{
+	parseTime := s.timeParser(ctx, user.ID)
+	dueAt, err := parseTime(req.DueAt, true)
+	if err != nil {
+		return nil, err
+	}
+	remindAt, err := parseTime(req.RemindAt, false)
+	if err != nil {
+		return nil, err
+	}
+	// A due date left out is only known once the todo is read, so the rule is checked against it there
+	ruleDueAt := dueAt
+	if req.DueAt == nil {
+		ruleDueAt.Valid = true
+	}
+	recurrence, err := parseRecurrence(req.Recurrence, ruleDueAt)
+	if err != nil {
+		return nil, err
+	}
+
+	params := db.UpdateTodoParams{
 		ID:          todoID,
 		Description: req.Description,
 		Completed:   pgtype.Bool{Bool: req.Completed, Valid: true},
 		Position:    pgtype.Numeric{Int: big.NewInt(req.Position), Valid: true},
 		UserID:      user.ID,
+		DueAt:       dueAt,
+		RemindAt:    remindAt,
+		Recurrence:  recurrence,
+		Priority:    priorityRank(req.Priority),
+		Language:    todoLanguage(req.Language, req.Description),
+	}
+
+	var todo db.Todo
+	err = s.SqlClient.ExecTx(ctx, func(q db.WrappedQuerier) error {
+		current, err := q.GetTodoForUpdate(ctx, db.GetTodoForUpdateParams{ID: todoID, UserID: user.ID})
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return utils.ErrNoRowsMatchedSQLC
+			}
+			return err
+		}
+
+		if req.DueAt == nil {
+			params.DueAt = current.DueAt
+		}
+		if req.RemindAt == nil {
+			params.RemindAt = current.RemindAt
+		}
+		if req.Recurrence == nil {
+			params.Recurrence = current.Recurrence
+		}
+		if req.Priority == "" {
+			params.Priority = current.Priority
+		}
+		// Only detected again when the description changes, so that a language the user picked is not lost on other edits
+		if req.Language == "" && req.Description == current.Description {
+			params.Language = current.Language
+		}
+		if params.Recurrence.Valid && !params.DueAt.Valid {
+			return utils.ErrRecurrenceWithoutDueDate
+		}
+
+		if params.Recurrence.Valid && params.Completed.Bool && !current.Completed.Bool {
+			loc, err := UserLocation(ctx, q, user.ID)
+			if err != nil {
+				return err
+			}
+
+			next, err := nextOccurrence(params, loc)
+			if err != nil {
+				return err
+			}
+			if next != nil {
+				next.ListID = current.ListID
+				created, err := q.CreateTodo(ctx, *next)
+				if err != nil {
+					return err
+				}
+				if err = q.CopyTodoItems(ctx, db.CopyTodoItemsParams{ToTodoID: created.ID, FromTodoID: todoID}); err != nil {
+					return err
+				}
+				if err = q.CopyTodoTags(ctx, db.CopyTodoTagsParams{ToTodoID: created.ID, FromTodoID: todoID}); err != nil {
+					return err
+				}
+			}
+
+			params.Recurrence = pgtype.Text{}
+		}
+
+		// Before the todo itself is updated, so that it comes back with the new checklist progress
+		if req.CompleteItems && params.Completed.Bool {
+			if err = q.CompleteTodoItems(ctx, todoID); err != nil {
+				return err
+			}
+		}
+
+		todo, err = q.UpdateTodo(ctx, params)
+		return err
 	})
 	if err != nil {
 		return nil, err
@@ -100,6 +470,38 @@ func (s *TodoService) UpdateTodo(ctx context.Context, userID pgtype.UUID, todoID
 	return &todo, nil
 }
 
+// Previews the occurrences following the due date of a recurring todo; empty if the todo does not recur
+func (s *TodoService) ListOccurrences(ctx context.Context, userID pgtype.UUID, todoID int32, req ListOccurrencesRequest) ([]time.Time, error) {
+	user, err := s.SqlClient.GetUserByUserID(ctx, userID)
+	if err != nil {
+		return nil, utils.ErrInvalidUID
+	}
+
+	todo, err := s.SqlClient.GetTodo(ctx, db.GetTodoParams{ID: todoID, UserID: user.ID})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, utils.ErrNoRowsMatchedSQLC
+		}
+		return nil, err
+	}
+
+	if !todo.Recurrence.Valid {
+		return []time.Time{}, nil
+	}
+
+	loc, err := UserLocation(ctx, s.SqlClient, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	count := req.Count
+	if count == 0 {
+		count = defaultOccurrencesPreview
+	}
+
+	return upcomingOccurrences(todo, loc, min(count, maxOccurrencesPreview))
+}
+
 func (s *TodoService) UpdateTodoPosition(ctx context.Context, userID pgtype.UUID, todoID int32, req UpdateTodoPositionRequest) (*db.Todo, error) {
 	user, err := s.SqlClient.GetUserByUserID(ctx, userID)
 	if err != nil {
@@ -119,6 +521,31 @@ func (s *TodoService) UpdateTodoPosition(ctx context.Context, userID pgtype.UUID
 	return &todo, nil
 }
 
+// Moves the todo to another list (or within its own one), into the place between the given positions
+func (s *TodoService) MoveTodo(ctx context.Context, userID pgtype.UUID, todoID int32, req MoveTodoRequest) (*db.Todo, error) {
+	user, err := s.SqlClient.GetUserByUserID(ctx, userID)
+	if err != nil {
+		return nil, utils.ErrInvalidUID
+	}
+
+	todo, err := s.SqlClient.MoveTodo(ctx, db.MoveTodoParams{
+		ID:      todoID,
+		UserID:  user.ID,
+		ListID:  req.ListID,
+		Prevpos: optionalNumeric(req.Prevpos),
+		Nextpos: optionalNumeric(req.Nextpos),
+	})
+	if err != nil {
+		// Either the todo or the list is not the user's
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, utils.ErrNoRowsMatchedSQLC
+		}
+		return nil, err
+	}
+
+	return &todo, nil
+}
+
 func (s *TodoService) DeleteTodo(ctx context.Context, userID pgtype.UUID, todoID int32) error {
 	user, err := s.SqlClient.GetUserByUserID(ctx, userID)
 	if err != nil {
@@ -137,3 +564,122 @@ func (s *TodoService) DeleteTodo(ctx context.Context, userID pgtype.UUID, todoID
 
 	return nil
 }
+
+// Columns are checked against db.TodoSortColumns, and each may appear only once
+func parseTodoSort(sort string) ([]db.TodoSortKey, error) {
+	var keys []db.TodoSortKey
+	for _, field := range strings.Split(sort, ",") {
+		field = strings.TrimSpace(field)
+		key := db.TodoSortKey{Column: strings.TrimPrefix(field, "-"), Desc: strings.HasPrefix(field, "-")}
+
+		if !slices.Contains(db.TodoSortColumns, key.Column) {
+			return nil, utils.ErrInvalidSort
+		}
+		if slices.ContainsFunc(keys, func(k db.TodoSortKey) bool { return k.Column == key.Column }) {
+			return nil, utils.ErrInvalidSort
+		}
+		keys = append(keys, key)
+	}
+
+	return keys, nil
+}
+
+// Control characters are kept out, as search headlines mark the matched words with \x02 and \x03. Line breaks and tabs are fine.
+func validDescription(description string) bool {
+	return !strings.ContainsFunc(description, func(r rune) bool {
+		return unicode.IsControl(r) && r != '\n' && r != '\r' && r != '\t'
+	})
+}
+
+// An omitted priority is none
+func priorityRank(priority string) int16 {
+	return int16(max(slices.Index(TodoPriorities, priority), 0))
+}
+
+// The language given, or else the one the description is detected to be written in
+func todoLanguage(language, description string) string {
+	if language != "" {
+		return language
+	}
+	for _, r := range description {
+		if unicode.In(r, unicode.Hiragana, unicode.Katakana, unicode.Han) {
+			return languageJapanese
+		}
+	}
+	return languageEnglish
+}
+
+// Nil tags skip the filter. Duplicates are dropped, as matching all tags compares the number of distinct ones.
+func (f TagFilter) params() ([]string, bool) {
+	var tags []string
+	seen := map[string]bool{}
+	for _, tag := range f.Tags {
+		if tag = strings.TrimSpace(tag); tag != "" && !seen[tag] {
+			seen[tag] = true
+			tags = append(tags, tag)
+		}
+	}
+
+	return tags, tags != nil && f.TagMode != "any"
+}
+
+// The given list if it is the user's, otherwise the inbox
+func (s *TodoService) targetListID(ctx context.Context, userID int32, listID *int32) (int32, error) {
+	var list db.List
+	var err error
+	if listID == nil {
+		list, err = s.SqlClient.GetInboxList(ctx, userID)
+	} else {
+		list, err = s.SqlClient.GetList(ctx, db.GetListParams{ID: *listID, UserID: userID})
+	}
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, utils.ErrNoRowsMatchedSQLC
+		}
+		return 0, err
+	}
+
+	return list.ID, nil
+}
+
+func optionalNumeric(value *int64) pgtype.Numeric {
+	if value == nil {
+		return pgtype.Numeric{}
+	}
+	return pgtype.Numeric{Int: big.NewInt(*value), Valid: true}
+}
+
+// Returns a parser for optional timestamps or dates; a missing or empty value is null.
+// Dates are taken in the user's time zone, which is looked up once and only if a date is given.
+func (s *TodoService) timeParser(ctx context.Context, userID int32) func(value *string, endOfDay bool) (pgtype.Timestamptz, error) {
+	var loc *time.Location
+
+	return func(value *string, endOfDay bool) (pgtype.Timestamptz, error) {
+		if value == nil || *value == "" {
+			return pgtype.Timestamptz{}, nil
+		}
+
+		if t, err := time.Parse(time.RFC3339, *value); err == nil {
+			return pgtype.Timestamptz{Time: t, Valid: true}, nil
+		}
+
+		if _, err := time.Parse(time.DateOnly, *value); err != nil {
+			return pgtype.Timestamptz{}, utils.ErrInvalidDate
+		}
+
+		if loc == nil {
+			var err error
+			if loc, err = UserLocation(ctx, s.SqlClient, userID); err != nil {
+				return pgtype.Timestamptz{}, err
+			}
+		}
+
+		t, _ := time.ParseInLocation(time.DateOnly, *value, loc)
+		if endOfDay {
+			// AddDate keeps the wall clock, so days shortened or lengthened by DST still end at 23:59:59
+			t = t.AddDate(0, 0, 1).Add(-time.Second)
+		}
+
+		return pgtype.Timestamptz{Time: t, Valid: true}, nil
+	}
+}
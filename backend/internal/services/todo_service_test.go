@@ -2,14 +2,18 @@ package services_test
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"math/big"
 	"testing"
+	"time"
 	"todo-app/internal/db"
 	mock_db "todo-app/internal/db/_mock"
 	"todo-app/internal/services"
+	mock_services "todo-app/internal/services/_mock"
 	"todo-app/internal/utils"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -21,251 +25,1566 @@ func TestTodoService(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockQueries := mock_db.NewMockWrappedQuerier(ctrl)
-	todoService := services.NewTodoService(mockQueries)
+	mockTokenGen := mock_services.NewMockITokenGenerator(ctrl)
+	todoService := services.NewTodoService(mockQueries, mockTokenGen)
 
 	uIDStr := "00010203-0405-0607-0809-0a0b0c0d0e0f"
 	uIDUuid, _ := utils.StringToUUID(uIDStr)
 
+	// Runs the transaction body against the same mock
+	expectTx := func(ctx context.Context) {
+		mockQueries.EXPECT().
+			ExecTx(ctx, gomock.Any()).
+			DoAndReturn(func(ctx context.Context, fn func(q db.WrappedQuerier) error) error {
+				return fn(mockQueries)
+			})
+	}
+
 	t.Run("CreateTodo", func(t *testing.T) {
 		ctx := context.Background()
-		req := services.CreateTodoRequest{
-			Description: "Test todo",
-		}
+		req := services.CreateTodoRequest{
+			Description: "Test todo",
+			Priority:    "urgent",
+		}
+
+		mockQueries.EXPECT().
+			GetUserByUserID(ctx, uIDUuid).
+			Return(db.User{ID: 1}, nil)
+
+		mockQueries.EXPECT().
+			GetInboxList(ctx, int32(1)).
+			Return(db.List{ID: 10, UserID: 1, IsInbox: true}, nil)
+
+		mockQueries.EXPECT().
+			CreateTodo(ctx, db.CreateTodoParams{
+				UserID:      1,
+				ListID:      10,
+				Description: req.Description,
+				Priority:    4,
+				Language:    "english",
+			}).
+			Return(db.Todo{ID: 1, Description: req.Description}, nil)
+
+		todo, err := todoService.CreateTodo(ctx, uIDUuid, req)
+
+		require.NoError(t, err)
+		assert.Equal(t, req.Description, todo.Description)
+	})
+
+	t.Run("CreateTodo_Language", func(t *testing.T) {
+		tests := []struct {
+			req      services.CreateTodoRequest
+			language string
+		}{
+			{req: services.CreateTodoRequest{Description: "牛乳を買う"}, language: "japanese"},
+			{req: services.CreateTodoRequest{Description: "Buy ミルク"}, language: "japanese"},
+			{req: services.CreateTodoRequest{Description: "Café au lait"}, language: "english"},
+			// As given, whatever the description
+			{req: services.CreateTodoRequest{Description: "Sushi", Language: "japanese"}, language: "japanese"},
+		}
+
+		for _, tt := range tests {
+			ctx := context.Background()
+
+			mockQueries.EXPECT().
+				GetUserByUserID(ctx, uIDUuid).
+				Return(db.User{ID: 1}, nil)
+
+			mockQueries.EXPECT().
+				GetInboxList(ctx, int32(1)).
+				Return(db.List{ID: 10, UserID: 1, IsInbox: true}, nil)
+
+			mockQueries.EXPECT().
+				CreateTodo(ctx, db.CreateTodoParams{UserID: 1, ListID: 10, Description: tt.req.Description, Language: tt.language}).
+				Return(db.Todo{ID: 1, Description: tt.req.Description, Language: tt.language}, nil)
+
+			todo, err := todoService.CreateTodo(ctx, uIDUuid, tt.req)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.language, todo.Language)
+		}
+	})
+
+	t.Run("CreateTodo_UserNotFound", func(t *testing.T) {
+		ctx := context.Background()
+		req := services.CreateTodoRequest{
+			Description: "Test todo",
+		}
+
+		mockQueries.EXPECT().
+			GetUserByUserID(ctx, uIDUuid).
+			Return(db.User{}, errors.New("user not found"))
+
+		todo, err := todoService.CreateTodo(ctx, uIDUuid, req)
+
+		assert.Error(t, err)
+		assert.Nil(t, todo)
+	})
+
+	t.Run("CreateTodo_DBError", func(t *testing.T) {
+		ctx := context.Background()
+		req := services.CreateTodoRequest{
+			Description: "Test todo",
+		}
+
+		mockQueries.EXPECT().
+			GetUserByUserID(ctx, uIDUuid).
+			Return(db.User{ID: 1}, nil)
+
+		mockQueries.EXPECT().
+			GetInboxList(ctx, int32(1)).
+			Return(db.List{ID: 10, UserID: 1, IsInbox: true}, nil)
+
+		mockQueries.EXPECT().
+			CreateTodo(ctx, db.CreateTodoParams{
+				UserID:      1,
+				ListID:      10,
+				Description: req.Description,
+				Language:    "english",
+			}).
+			Return(db.Todo{}, errors.New("db error"))
+
+		todo, err := todoService.CreateTodo(ctx, uIDUuid, req)
+
+		assert.Error(t, err)
+		assert.Nil(t, todo)
+	})
+
+	t.Run("CreateTodo_DueDate", func(t *testing.T) {
+		ctx := context.Background()
+		dueAt := "2024-03-10"
+		remindAt := "2024-03-10T09:00:00+09:00"
+		req := services.CreateTodoRequest{
+			Description: "Test todo",
+			DueAt:       &dueAt,
+			RemindAt:    &remindAt,
+		}
+		newYork, _ := time.LoadLocation("America/New_York")
+
+		mockQueries.EXPECT().
+			GetUserByUserID(ctx, uIDUuid).
+			Return(db.User{ID: 1}, nil)
+
+		mockQueries.EXPECT().
+			GetUserPreferences(ctx, int32(1)).
+			Return(db.UserPreference{UserID: 1, TimeZone: "America/New_York"}, nil)
+
+		mockQueries.EXPECT().
+			GetInboxList(ctx, int32(1)).
+			Return(db.List{ID: 10, UserID: 1, IsInbox: true}, nil)
+
+		// Daylight saving time starts on this day, so it is an hour shorter but still ends at 23:59:59 local time
+		mockQueries.EXPECT().
+			CreateTodo(ctx, gomock.Any()).
+			DoAndReturn(func(ctx context.Context, arg db.CreateTodoParams) (db.Todo, error) {
+				assert.True(t, arg.DueAt.Time.Equal(time.Date(2024, 3, 10, 23, 59, 59, 0, newYork)))
+				assert.True(t, arg.DueAt.Time.Equal(time.Date(2024, 3, 11, 3, 59, 59, 0, time.UTC)))
+				assert.True(t, arg.RemindAt.Time.Equal(time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC)))
+				return db.Todo{ID: 1, Description: arg.Description, DueAt: arg.DueAt, RemindAt: arg.RemindAt}, nil
+			})
+
+		todo, err := todoService.CreateTodo(ctx, uIDUuid, req)
+
+		require.NoError(t, err)
+		assert.True(t, todo.DueAt.Valid)
+	})
+
+	t.Run("CreateTodo_InvalidDate", func(t *testing.T) {
+		ctx := context.Background()
+		dueAt := "10/03/2024"
+		req := services.CreateTodoRequest{
+			Description: "Test todo",
+			DueAt:       &dueAt,
+		}
+
+		mockQueries.EXPECT().
+			GetUserByUserID(ctx, uIDUuid).
+			Return(db.User{ID: 1}, nil)
+
+		todo, err := todoService.CreateTodo(ctx, uIDUuid, req)
+
+		assert.Equal(t, utils.ErrInvalidDate, err)
+		assert.Nil(t, todo)
+	})
+
+	t.Run("CreateTodo_InvalidDescription", func(t *testing.T) {
+		ctx := context.Background()
+		// Would mark "plants" as a search match in the headline
+		req := services.CreateTodoRequest{Description: "Water \x02plants\x03"}
+
+		todo, err := todoService.CreateTodo(ctx, uIDUuid, req)
+
+		assert.Equal(t, utils.ErrInvalidDescription, err)
+		assert.Nil(t, todo)
+	})
+
+	t.Run("CreateTodo_InList", func(t *testing.T) {
+		ctx := context.Background()
+		var listID int32 = 20
+		req := services.CreateTodoRequest{
+			Description: "Test todo",
+			ListID:      &listID,
+		}
+
+		mockQueries.EXPECT().
+			GetUserByUserID(ctx, uIDUuid).
+			Return(db.User{ID: 1}, nil)
+
+		mockQueries.EXPECT().
+			GetList(ctx, db.GetListParams{ID: listID, UserID: 1}).
+			Return(db.List{ID: listID, UserID: 1}, nil)
+
+		mockQueries.EXPECT().
+			CreateTodo(ctx, db.CreateTodoParams{
+				UserID:      1,
+				ListID:      listID,
+				Description: req.Description,
+				Language:    "english",
+			}).
+			Return(db.Todo{ID: 1, ListID: listID, Description: req.Description}, nil)
+
+		todo, err := todoService.CreateTodo(ctx, uIDUuid, req)
+
+		require.NoError(t, err)
+		assert.Equal(t, listID, todo.ListID)
+	})
+
+	t.Run("CreateTodo_ListNotFound", func(t *testing.T) {
+		ctx := context.Background()
+		var listID int32 = 1000
+		req := services.CreateTodoRequest{
+			Description: "Test todo",
+			ListID:      &listID,
+		}
+
+		mockQueries.EXPECT().
+			GetUserByUserID(ctx, uIDUuid).
+			Return(db.User{ID: 1}, nil)
+
+		// Someone else's list looks the same as a missing one
+		mockQueries.EXPECT().
+			GetList(ctx, db.GetListParams{ID: listID, UserID: 1}).
+			Return(db.List{}, pgx.ErrNoRows)
+
+		todo, err := todoService.CreateTodo(ctx, uIDUuid, req)
+
+		assert.Equal(t, utils.ErrNoRowsMatchedSQLC, err)
+		assert.Nil(t, todo)
+	})
+
+	t.Run("ListTodos", func(t *testing.T) {
+		ctx := context.Background()
+
+		mockQueries.EXPECT().
+			GetUserByUserID(ctx, uIDUuid).
+			Return(db.User{ID: 1}, nil)
+
+		mockQueries.EXPECT().
+			GetUserPreferences(ctx, int32(1)).
+			Return(db.UserPreference{}, pgx.ErrNoRows)
+
+		mockQueries.EXPECT().
+			ListTodos(ctx, db.ListTodosParams{UserID: 1}).
+			Return([]db.Todo{{ID: 1, Description: "Test todo"}}, nil)
+
+		todos, err := todoService.ListTodos(ctx, uIDUuid, services.ListTodosRequest{})
+
+		require.NoError(t, err)
+		assert.Len(t, todos.Todos, 1)
+	})
+
+	t.Run("ListTodos_DueDateFilters", func(t *testing.T) {
+		ctx := context.Background()
+		req := services.ListTodosRequest{
+			DueBefore: "2024-01-08",
+			DueAfter:  "2024-01-01",
+			Overdue:   true,
+		}
+
+		mockQueries.EXPECT().
+			GetUserByUserID(ctx, uIDUuid).
+			Return(db.User{ID: 1}, nil)
+
+		// Looked up once for both dates, and once more for the default sort
+		mockQueries.EXPECT().
+			GetUserPreferences(ctx, int32(1)).
+			Return(db.UserPreference{UserID: 1, TimeZone: "Asia/Tokyo", DefaultTodoSort: "position"}, nil).
+			Times(2)
+
+		mockQueries.EXPECT().
+			ListTodos(ctx, gomock.Any()).
+			DoAndReturn(func(ctx context.Context, arg db.ListTodosParams) ([]db.Todo, error) {
+				assert.True(t, arg.DueBefore.Time.Equal(time.Date(2024, 1, 7, 15, 0, 0, 0, time.UTC)))
+				assert.True(t, arg.DueAfter.Time.Equal(time.Date(2023, 12, 31, 15, 0, 0, 0, time.UTC)))
+				assert.True(t, arg.Overdue)
+				return []db.Todo{{ID: 1, Description: "Test todo"}}, nil
+			})
+
+		todos, err := todoService.ListTodos(ctx, uIDUuid, req)
+
+		require.NoError(t, err)
+		assert.Len(t, todos.Todos, 1)
+	})
+
+	t.Run("ListTodos_Sort", func(t *testing.T) {
+		ctx := context.Background()
+
+		mockQueries.EXPECT().
+			GetUserByUserID(ctx, uIDUuid).
+			Return(db.User{ID: 1}, nil)
+
+		mockQueries.EXPECT().
+			ListTodosFiltered(ctx, db.ListTodosParams{UserID: 1}, db.TodoFilter{}, []db.TodoSortKey{
+				{Column: "priority", Desc: true},
+				{Column: "due_at"},
+				{Column: "created_at"},
+			}, db.TodoPageParams{}).
+			Return([]db.Todo{{ID: 1, Priority: 4}}, nil)
+
+		todos, err := todoService.ListTodos(ctx, uIDUuid, services.ListTodosRequest{Sort: "-priority, due_at,created_at"})
+
+		require.NoError(t, err)
+		assert.Len(t, todos.Todos, 1)
+	})
+
+	t.Run("ListTodos_DefaultSortPreference", func(t *testing.T) {
+		ctx := context.Background()
+
+		mockQueries.EXPECT().
+			GetUserByUserID(ctx, uIDUuid).
+			Return(db.User{ID: 1}, nil)
+
+		mockQueries.EXPECT().
+			GetUserPreferences(ctx, int32(1)).
+			Return(db.UserPreference{UserID: 1, DefaultTodoSort: "-created_at"}, nil)
+
+		mockQueries.EXPECT().
+			ListTodosFiltered(ctx, db.ListTodosParams{UserID: 1}, db.TodoFilter{}, []db.TodoSortKey{{Column: "created_at", Desc: true}}, db.TodoPageParams{}).
+			Return([]db.Todo{{ID: 1}}, nil)
+
+		todos, err := todoService.ListTodos(ctx, uIDUuid, services.ListTodosRequest{})
+
+		require.NoError(t, err)
+		assert.Len(t, todos.Todos, 1)
+	})
+
+	t.Run("ListTodos_InvalidSort", func(t *testing.T) {
+		for _, sort := range []string{"id", "priority,-priority", "position;DROP TABLE todos", "-", ""} {
+			ctx := context.Background()
+
+			mockQueries.EXPECT().
+				GetUserByUserID(ctx, uIDUuid).
+				Return(db.User{ID: 1}, nil)
+
+			if sort == "" {
+				// An empty sort falls back to the preference, which cannot be empty either
+				mockQueries.EXPECT().
+					GetUserPreferences(ctx, int32(1)).
+					Return(db.UserPreference{UserID: 1, DefaultTodoSort: ","}, nil)
+			}
+
+			todos, err := todoService.ListTodos(ctx, uIDUuid, services.ListTodosRequest{Sort: sort})
+
+			assert.Equal(t, utils.ErrInvalidSort, err, sort)
+			assert.Nil(t, todos)
+		}
+	})
+
+	t.Run("ListTodos_Pages", func(t *testing.T) {
+		ctx := context.Background()
+		position := func(n int64) pgtype.Numeric { return pgtype.Numeric{Int: big.NewInt(n), Valid: true} }
+		var cursor []byte
+
+		// First page: one todo more than the limit is fetched to tell that another page follows
+		mockQueries.EXPECT().
+			GetUserByUserID(ctx, uIDUuid).
+			Return(db.User{ID: 1}, nil)
+
+		mockQueries.EXPECT().
+			ListTodosFiltered(ctx, db.ListTodosParams{UserID: 1}, db.TodoFilter{}, []db.TodoSortKey{{Column: "priority", Desc: true}}, db.TodoPageParams{Limit: 3}).
+			Return([]db.Todo{
+				{ID: 1, Priority: 4, Position: position(300)},
+				{ID: 2, Priority: 3, Position: position(150)},
+				{ID: 3, Priority: 3, Position: position(100)},
+			}, nil)
+
+		mockTokenGen.EXPECT().
+			GenerateTodoCursor(uIDStr, gomock.Any()).
+			DoAndReturn(func(userID string, payload json.RawMessage) (string, error) {
+				cursor = payload
+				return "cursor-token", nil
+			})
+
+		page, err := todoService.ListTodos(ctx, uIDUuid, services.ListTodosRequest{Sort: "-priority", PageRequest: services.PageRequest{Limit: 2}})
+
+		require.NoError(t, err)
+		assert.Len(t, page.Todos, 2)
+		assert.True(t, page.HasMore)
+		assert.Equal(t, "cursor-token", page.NextCursor)
+
+		// Last page: starts after the last todo of the first one
+		mockQueries.EXPECT().
+			GetUserByUserID(ctx, uIDUuid).
+			Return(db.User{ID: 1}, nil)
+
+		mockTokenGen.EXPECT().
+			ValidateTodoCursor("cursor-token").
+			DoAndReturn(func(token string) (*services.JWTCustomClaims, error) {
+				return &services.JWTCustomClaims{UserID: uIDStr, TokenType: services.TokenTypeTodoCursor, Cursor: cursor}, nil
+			})
+
+		mockQueries.EXPECT().
+			ListTodosFiltered(ctx, db.ListTodosParams{UserID: 1}, db.TodoFilter{}, []db.TodoSortKey{{Column: "priority", Desc: true}}, gomock.Any()).
+			DoAndReturn(func(ctx context.Context, arg db.ListTodosParams, filter db.TodoFilter, sort []db.TodoSortKey, page db.TodoPageParams) ([]db.Todo, error) {
+				assert.Equal(t, int32(3), page.Limit)
+				require.NotNil(t, page.After)
+				assert.Equal(t, int32(2), page.After.ID)
+				assert.Equal(t, int16(3), page.After.Priority)
+				after, _ := page.After.Position.Value()
+				assert.Equal(t, "150", after)
+				return []db.Todo{{ID: 3, Priority: 3, Position: position(100)}}, nil
+			})
+
+		page, err = todoService.ListTodos(ctx, uIDUuid, services.ListTodosRequest{Sort: "-priority", PageRequest: services.PageRequest{Limit: 2, Cursor: "cursor-token"}})
+
+		require.NoError(t, err)
+		assert.Len(t, page.Todos, 1)
+		assert.False(t, page.HasMore)
+		assert.Empty(t, page.NextCursor)
+	})
+
+	t.Run("ListTodos_InvalidCursor", func(t *testing.T) {
+		claims := []*services.JWTCustomClaims{
+			// Issued to another user
+			{UserID: "10111213-1415-1617-1819-1a1b1c1d1e1f", TokenType: services.TokenTypeTodoCursor, Cursor: []byte(`{"sort":"position","id":1,"position":"100"}`)},
+			// Issued for another sort
+			{UserID: uIDStr, TokenType: services.TokenTypeTodoCursor, Cursor: []byte(`{"sort":"-priority","id":1,"position":"100"}`)},
+			// Not a cursor
+			{UserID: uIDStr, TokenType: services.TokenTypeAccess},
+		}
+
+		for _, c := range claims {
+			ctx := context.Background()
+
+			mockQueries.EXPECT().
+				GetUserByUserID(ctx, uIDUuid).
+				Return(db.User{ID: 1}, nil)
+
+			mockTokenGen.EXPECT().
+				ValidateTodoCursor("cursor-token").
+				Return(c, nil)
+
+			page, err := todoService.ListTodos(ctx, uIDUuid, services.ListTodosRequest{Sort: "position", PageRequest: services.PageRequest{Cursor: "cursor-token"}})
+
+			assert.Equal(t, utils.ErrInvalidCursor, err)
+			assert.Nil(t, page)
+		}
+	})
+
+	t.Run("ListTodos_UserNotFound", func(t *testing.T) {
+		ctx := context.Background()
+
+		mockQueries.EXPECT().
+			GetUserByUserID(ctx, uIDUuid).
+			Return(db.User{}, errors.New("user not found"))
+
+		todos, err := todoService.ListTodos(ctx, uIDUuid, services.ListTodosRequest{})
+
+		assert.Error(t, err)
+		assert.Nil(t, todos)
+	})
+
+	t.Run("ListTodos_DBError", func(t *testing.T) {
+		ctx := context.Background()
+
+		mockQueries.EXPECT().
+			GetUserByUserID(ctx, uIDUuid).
+			Return(db.User{ID: 1}, nil)
+
+		mockQueries.EXPECT().
+			GetUserPreferences(ctx, int32(1)).
+			Return(db.UserPreference{}, pgx.ErrNoRows)
+
+		mockQueries.EXPECT().
+			ListTodos(ctx, db.ListTodosParams{UserID: 1}).
+			Return(nil, errors.New("db error"))
+
+		todos, err := todoService.ListTodos(ctx, uIDUuid, services.ListTodosRequest{})
+
+		assert.Error(t, err)
+		assert.Nil(t, todos)
+	})
+
+	t.Run("ListTodos_List", func(t *testing.T) {
+		ctx := context.Background()
+		var listID int32 = 20
+
+		mockQueries.EXPECT().
+			GetUserByUserID(ctx, uIDUuid).
+			Return(db.User{ID: 1}, nil)
+
+		mockQueries.EXPECT().
+			GetUserPreferences(ctx, int32(1)).
+			Return(db.UserPreference{}, pgx.ErrNoRows)
+
+		mockQueries.EXPECT().
+			ListTodos(ctx, db.ListTodosParams{UserID: 1, ListID: pgtype.Int4{Int32: listID, Valid: true}}).
+			Return([]db.Todo{{ID: 1, ListID: listID}}, nil)
+
+		todos, err := todoService.ListTodos(ctx, uIDUuid, services.ListTodosRequest{ListID: &listID})
+
+		require.NoError(t, err)
+		assert.Len(t, todos.Todos, 1)
+	})
+
+	t.Run("ListTodos_Tags", func(t *testing.T) {
+		ctx := context.Background()
+
+		mockQueries.EXPECT().
+			GetUserByUserID(ctx, uIDUuid).
+			Return(db.User{ID: 1}, nil)
+
+		mockQueries.EXPECT().
+			GetUserPreferences(ctx, int32(1)).
+			Return(db.UserPreference{}, pgx.ErrNoRows)
+
+		mockQueries.EXPECT().
+			ListTodos(ctx, db.ListTodosParams{UserID: 1, Tags: []string{"work", "home"}, MatchAllTags: true}).
+			Return([]db.Todo{{ID: 1}}, nil)
+
+		todos, err := todoService.ListTodos(ctx, uIDUuid, services.ListTodosRequest{
+			TagFilter: services.TagFilter{Tags: []string{" work", "home", "work", ""}},
+		})
+
+		require.NoError(t, err)
+		assert.Len(t, todos.Todos, 1)
+	})
+
+	t.Run("ListTodos_Filters", func(t *testing.T) {
+		completed, notCompleted := true, false
+		from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		to := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+		listID := int32(10)
+
+		tests := []struct {
+			name   string
+			req    services.ListTodosRequest
+			params db.ListTodosParams
+			filter db.TodoFilter
+		}{
+			{
+				name:   "completed",
+				req:    services.ListTodosRequest{Completed: &completed},
+				params: db.ListTodosParams{UserID: 1},
+				filter: db.TodoFilter{Completed: pgtype.Bool{Bool: true, Valid: true}},
+			},
+			{
+				name:   "not completed",
+				req:    services.ListTodosRequest{Completed: &notCompleted},
+				params: db.ListTodosParams{UserID: 1},
+				filter: db.TodoFilter{Completed: pgtype.Bool{Bool: false, Valid: true}},
+			},
+			{
+				name:   "created range",
+				req:    services.ListTodosRequest{CreatedAfter: "2024-01-01T00:00:00Z", CreatedBefore: "2024-02-01T00:00:00Z"},
+				params: db.ListTodosParams{UserID: 1},
+				filter: db.TodoFilter{
+					CreatedAfter:  pgtype.Timestamptz{Time: from, Valid: true},
+					CreatedBefore: pgtype.Timestamptz{Time: to, Valid: true},
+				},
+			},
+			{
+				name:   "updated after",
+				req:    services.ListTodosRequest{UpdatedAfter: "2024-01-01T00:00:00Z"},
+				params: db.ListTodosParams{UserID: 1},
+				filter: db.TodoFilter{UpdatedAfter: pgtype.Timestamptz{Time: from, Valid: true}},
+			},
+			{
+				name:   "updated before",
+				req:    services.ListTodosRequest{UpdatedBefore: "2024-02-01T00:00:00Z"},
+				params: db.ListTodosParams{UserID: 1},
+				filter: db.TodoFilter{UpdatedBefore: pgtype.Timestamptz{Time: to, Valid: true}},
+			},
+			{
+				name:   "query",
+				req:    services.ListTodosRequest{Q: "  50% off "},
+				params: db.ListTodosParams{UserID: 1},
+				filter: db.TodoFilter{Query: "50% off"},
+			},
+			{
+				name:   "completed and query",
+				req:    services.ListTodosRequest{Completed: &notCompleted, Q: "milk"},
+				params: db.ListTodosParams{UserID: 1},
+				filter: db.TodoFilter{Completed: pgtype.Bool{Bool: false, Valid: true}, Query: "milk"},
+			},
+			{
+				name: "every filter with list, due date and tags",
+				req: services.ListTodosRequest{
+					ListID:        &listID,
+					DueAfter:      "2024-01-01T00:00:00Z",
+					Completed:     &completed,
+					CreatedAfter:  "2024-01-01T00:00:00Z",
+					CreatedBefore: "2024-02-01T00:00:00Z",
+					UpdatedAfter:  "2024-01-01T00:00:00Z",
+					UpdatedBefore: "2024-02-01T00:00:00Z",
+					Q:             "milk",
+					TagFilter:     services.TagFilter{Tags: []string{"home"}, TagMode: "any"},
+				},
+				params: db.ListTodosParams{
+					UserID:   1,
+					ListID:   pgtype.Int4{Int32: listID, Valid: true},
+					DueAfter: pgtype.Timestamptz{Time: from, Valid: true},
+					Tags:     []string{"home"},
+				},
+				filter: db.TodoFilter{
+					Completed:     pgtype.Bool{Bool: true, Valid: true},
+					CreatedAfter:  pgtype.Timestamptz{Time: from, Valid: true},
+					CreatedBefore: pgtype.Timestamptz{Time: to, Valid: true},
+					UpdatedAfter:  pgtype.Timestamptz{Time: from, Valid: true},
+					UpdatedBefore: pgtype.Timestamptz{Time: to, Valid: true},
+					Query:         "milk",
+				},
+			},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				ctx := context.Background()
+
+				mockQueries.EXPECT().
+					GetUserByUserID(ctx, uIDUuid).
+					Return(db.User{ID: 1}, nil)
+
+				mockQueries.EXPECT().
+					GetUserPreferences(ctx, int32(1)).
+					Return(db.UserPreference{}, pgx.ErrNoRows)
+
+				mockQueries.EXPECT().
+					ListTodosFiltered(ctx, tt.params, tt.filter, db.TodoManualSort, db.TodoPageParams{}).
+					Return([]db.Todo{{ID: 1}}, nil)
+
+				todos, err := todoService.ListTodos(ctx, uIDUuid, tt.req)
+
+				require.NoError(t, err)
+				assert.Len(t, todos.Todos, 1)
+			})
+		}
+	})
+
+	t.Run("ListTodos_FiltersSortedPage", func(t *testing.T) {
+		ctx := context.Background()
+
+		mockQueries.EXPECT().
+			GetUserByUserID(ctx, uIDUuid).
+			Return(db.User{ID: 1}, nil)
+
+		mockQueries.EXPECT().
+			ListTodosFiltered(
+				ctx,
+				db.ListTodosParams{UserID: 1},
+				db.TodoFilter{Completed: pgtype.Bool{Bool: true, Valid: true}, Query: "milk"},
+				[]db.TodoSortKey{{Column: "updated_at", Desc: true}},
+				db.TodoPageParams{Limit: 21},
+			).
+			Return([]db.Todo{{ID: 1}}, nil)
+
+		completed := true
+		todos, err := todoService.ListTodos(ctx, uIDUuid, services.ListTodosRequest{
+			Completed:   &completed,
+			Q:           "milk",
+			Sort:        "-updated_at",
+			PageRequest: services.PageRequest{Limit: 20},
+		})
+
+		require.NoError(t, err)
+		assert.Len(t, todos.Todos, 1)
+		assert.False(t, todos.HasMore)
+	})
+
+	t.Run("ListTodos_InvalidFilterDate", func(t *testing.T) {
+		ctx := context.Background()
+
+		mockQueries.EXPECT().
+			GetUserByUserID(ctx, uIDUuid).
+			Return(db.User{ID: 1}, nil)
+
+		todos, err := todoService.ListTodos(ctx, uIDUuid, services.ListTodosRequest{UpdatedBefore: "yesterday"})
+
+		assert.Equal(t, utils.ErrInvalidDate, err)
+		assert.Nil(t, todos)
+	})
+
+	t.Run("SearchTodos", func(t *testing.T) {
+		ctx := context.Background()
+		keyword := "Test"
+
+		mockQueries.EXPECT().
+			GetUserByUserID(ctx, uIDUuid).
+			Return(db.User{ID: 1}, nil)
+
+		mockQueries.EXPECT().
+			SearchTodos(ctx, db.SearchTodosParams{
+				UserID:  1,
+				Keyword: keyword,
+				Prefix:  keyword,
+				Fuzzy:   keyword,
+				Pattern: "%Test%",
+			}).
+			Return([]db.SearchTodosRow{{Todo: db.Todo{ID: 1, Description: "Test todo"}, Rank: 0.06, Headline: "\x02Test\x03 todo"}}, nil)
+
+		todos, err := todoService.SearchTodos(ctx, uIDUuid, services.SearchTodosRequest{Keyword: keyword})
+
+		require.NoError(t, err)
+		assert.Len(t, todos.Todos, 1)
+		assert.Equal(t, map[int32]string{1: "\x02Test\x03 todo"}, todos.Headlines)
+	})
+
+	t.Run("SearchTodos_Keyword", func(t *testing.T) {
+		tests := []struct {
+			keyword string
+			params  db.SearchTodosParams
+		}{
+			// The last word is being typed
+			{keyword: "buy mil", params: db.SearchTodosParams{PrefixOf: "buy ", Prefix: "mil", Fuzzy: "buy mil", Pattern: "%buy mil%"}},
+			{keyword: "牛乳", params: db.SearchTodosParams{Prefix: "牛乳", Fuzzy: "牛乳", Pattern: "%牛乳%"}},
+			// Words already typed, phrases and operators match as they are
+			{keyword: "buy milk ", params: db.SearchTodosParams{Fuzzy: "buy milk", Pattern: "%buy milk%"}},
+			{keyword: `"buy milk"`, params: db.SearchTodosParams{Fuzzy: "buy milk", Pattern: "%buy milk%"}},
+			{keyword: "milk or bread", params: db.SearchTodosParams{PrefixOf: "milk or ", Prefix: "bread", Fuzzy: "milk bread", Pattern: "%milk bread%"}},
+			{keyword: "e-mai", params: db.SearchTodosParams{Fuzzy: "e-mai", Pattern: "%e-mai%"}},
+			// Wildcards only match themselves
+			{keyword: "50%", params: db.SearchTodosParams{Fuzzy: "50%", Pattern: `%50\%%`}},
+			// Whatever the syntax, text search takes it
+			{keyword: "a&", params: db.SearchTodosParams{Fuzzy: "a&", Pattern: "%a&%"}},
+			// The trigrams would find the excluded todos
+			{keyword: "buy -milk", params: db.SearchTodosParams{}},
+		}
+
+		for _, tt := range tests {
+			ctx := context.Background()
+			params := tt.params
+			params.UserID = 1
+			params.Keyword = tt.keyword
+
+			mockQueries.EXPECT().
+				GetUserByUserID(ctx, uIDUuid).
+				Return(db.User{ID: 1}, nil)
+
+			mockQueries.EXPECT().
+				SearchTodos(ctx, params).
+				Return(nil, nil)
+
+			todos, err := todoService.SearchTodos(ctx, uIDUuid, services.SearchTodosRequest{Keyword: tt.keyword})
+
+			require.NoError(t, err)
+			assert.Empty(t, todos.Todos)
+		}
+	})
+
+	t.Run("SearchTodos_Tags", func(t *testing.T) {
+		ctx := context.Background()
+
+		mockQueries.EXPECT().
+			GetUserByUserID(ctx, uIDUuid).
+			Return(db.User{ID: 1}, nil)
+
+		mockQueries.EXPECT().
+			SearchTodos(ctx, db.SearchTodosParams{
+				UserID:       1,
+				Keyword:      "Test",
+				Prefix:       "Test",
+				Fuzzy:        "Test",
+				Pattern:      "%Test%",
+				Tags:         []string{"work"},
+				MatchAllTags: false,
+			}).
+			Return([]db.SearchTodosRow{{Todo: db.Todo{ID: 1, Description: "Test todo"}}}, nil)
+
+		todos, err := todoService.SearchTodos(ctx, uIDUuid, services.SearchTodosRequest{
+			Keyword:   "Test",
+			TagFilter: services.TagFilter{Tags: []string{"work"}, TagMode: "any"},
+		})
+
+		require.NoError(t, err)
+		assert.Len(t, todos.Todos, 1)
+	})
+
+	t.Run("SearchTodos_Pages", func(t *testing.T) {
+		ctx := context.Background()
+		params := db.SearchTodosParams{UserID: 1, Keyword: "milk", Prefix: "milk", Fuzzy: "milk", Pattern: "%milk%"}
+		var cursor []byte
+
+		// First page, ranked by relevance
+		mockQueries.EXPECT().
+			GetUserByUserID(ctx, uIDUuid).
+			Return(db.User{ID: 1}, nil)
+
+		mockQueries.EXPECT().
+			SearchTodosPage(ctx, params, db.TodoPageParams{Limit: 3}).
+			Return([]db.SearchTodosRow{
+				{Todo: db.Todo{ID: 4}, Rank: 0.09, Headline: "\x02milk\x03 and \x02milk\x03"},
+				{Todo: db.Todo{ID: 2}, Rank: 0.06, Headline: "\x02milk\x03"},
+				{Todo: db.Todo{ID: 3}, Rank: 0.06, Headline: "oat \x02milk\x03"},
+			}, nil)
+
+		mockTokenGen.EXPECT().
+			GenerateTodoCursor(uIDStr, gomock.Any()).
+			DoAndReturn(func(userID string, payload json.RawMessage) (string, error) {
+				cursor = payload
+				return "cursor-token", nil
+			})
+
+		page, err := todoService.SearchTodos(ctx, uIDUuid, services.SearchTodosRequest{Keyword: "milk", PageRequest: services.PageRequest{Limit: 2}})
+
+		require.NoError(t, err)
+		assert.Equal(t, []db.Todo{{ID: 4}, {ID: 2}}, page.Todos)
+		assert.Equal(t, map[int32]string{4: "\x02milk\x03 and \x02milk\x03", 2: "\x02milk\x03"}, page.Headlines)
+		assert.True(t, page.HasMore)
+
+		// Last page: starts after the rank and id of the last match of the first one
+		mockQueries.EXPECT().
+			GetUserByUserID(ctx, uIDUuid).
+			Return(db.User{ID: 1}, nil)
+
+		mockTokenGen.EXPECT().
+			ValidateTodoCursor("cursor-token").
+			DoAndReturn(func(token string) (*services.JWTCustomClaims, error) {
+				return &services.JWTCustomClaims{UserID: uIDStr, TokenType: services.TokenTypeTodoCursor, Cursor: cursor}, nil
+			})
+
+		mockQueries.EXPECT().
+			SearchTodosPage(ctx, params, gomock.Any()).
+			DoAndReturn(func(ctx context.Context, arg db.SearchTodosParams, page db.TodoPageParams) ([]db.SearchTodosRow, error) {
+				require.NotNil(t, page.After)
+				assert.Equal(t, int32(2), page.After.ID)
+				assert.Equal(t, float32(0.06), page.AfterRank)
+				return []db.SearchTodosRow{{Todo: db.Todo{ID: 3}, Rank: 0.06, Headline: "oat \x02milk\x03"}}, nil
+			})
+
+		page, err = todoService.SearchTodos(ctx, uIDUuid, services.SearchTodosRequest{Keyword: "milk", PageRequest: services.PageRequest{Limit: 2, Cursor: "cursor-token"}})
+
+		require.NoError(t, err)
+		assert.Len(t, page.Todos, 1)
+		assert.False(t, page.HasMore)
+	})
+
+	t.Run("SearchTodos_ListCursor", func(t *testing.T) {
+		ctx := context.Background()
+
+		mockQueries.EXPECT().
+			GetUserByUserID(ctx, uIDUuid).
+			Return(db.User{ID: 1}, nil)
+
+		mockTokenGen.EXPECT().
+			ValidateTodoCursor("cursor-token").
+			Return(&services.JWTCustomClaims{UserID: uIDStr, TokenType: services.TokenTypeTodoCursor, Cursor: []byte(`{"sort":"position","id":1,"position":"100"}`)}, nil)
+
+		page, err := todoService.SearchTodos(ctx, uIDUuid, services.SearchTodosRequest{Keyword: "milk", PageRequest: services.PageRequest{Cursor: "cursor-token"}})
+
+		assert.Equal(t, utils.ErrInvalidCursor, err)
+		assert.Nil(t, page)
+	})
+
+	t.Run("SearchTodos_UserNotFound", func(t *testing.T) {
+		ctx := context.Background()
+		keyword := "Test"
+
+		mockQueries.EXPECT().
+			GetUserByUserID(ctx, uIDUuid).
+			Return(db.User{}, errors.New("user not found"))
+
+		todos, err := todoService.SearchTodos(ctx, uIDUuid, services.SearchTodosRequest{Keyword: keyword})
+
+		assert.Error(t, err)
+		assert.Nil(t, todos)
+	})
+
+	t.Run("SearchTodos_DBError", func(t *testing.T) {
+		ctx := context.Background()
+		keyword := "Test"
+
+		mockQueries.EXPECT().
+			GetUserByUserID(ctx, uIDUuid).
+			Return(db.User{ID: 1}, nil)
+
+		mockQueries.EXPECT().
+			SearchTodos(ctx, db.SearchTodosParams{
+				UserID:  1,
+				Keyword: keyword,
+				Prefix:  keyword,
+				Fuzzy:   keyword,
+				Pattern: "%Test%",
+			}).
+			Return(nil, errors.New("db error"))
+
+		todos, err := todoService.SearchTodos(ctx, uIDUuid, services.SearchTodosRequest{Keyword: keyword})
+
+		assert.Error(t, err)
+		assert.Nil(t, todos)
+	})
+
+	t.Run("UpdateTodo", func(t *testing.T) {
+		ctx := context.Background()
+		var todoID int32 = 1
+		req := services.UpdateTodoRequest{
+			Description: "Updated todo",
+			Completed:   true,
+			Position:    100,
+		}
+
+		mockQueries.EXPECT().
+			GetUserByUserID(ctx, uIDUuid).
+			Return(db.User{ID: 1}, nil)
+
+		expectTx(ctx)
+
+		mockQueries.EXPECT().
+			GetTodoForUpdate(ctx, db.GetTodoForUpdateParams{ID: todoID, UserID: 1}).
+			Return(db.Todo{ID: todoID}, nil)
+
+		mockQueries.EXPECT().
+			UpdateTodo(ctx, db.UpdateTodoParams{
+				ID:          todoID,
+				Description: req.Description,
+				Completed:   pgtype.Bool{Bool: req.Completed, Valid: true},
+				Position:    pgtype.Numeric{Int: big.NewInt(req.Position), Valid: true},
+				UserID:      1,
+				Language:    "english",
+			}).
+			Return(db.Todo{ID: todoID, Description: req.Description, Completed: pgtype.Bool{Bool: req.Completed, Valid: true}}, nil)
+
+		todo, err := todoService.UpdateTodo(ctx, uIDUuid, todoID, req)
+
+		require.NoError(t, err)
+		assert.Equal(t, req.Description, todo.Description)
+	})
+
+	t.Run("UpdateTodo_KeepsOmittedFields", func(t *testing.T) {
+		ctx := context.Background()
+		var todoID int32 = 1
+		dueAt := pgtype.Timestamptz{Time: time.Date(2024, 1, 31, 9, 0, 0, 0, time.UTC), Valid: true}
+		remindAt := pgtype.Timestamptz{Time: time.Date(2024, 1, 31, 8, 0, 0, 0, time.UTC), Valid: true}
+		var req services.UpdateTodoRequest
+		require.NoError(t, json.Unmarshal([]byte(`{"description": "Updated todo", "completed": true, "position": 100}`), &req))
+
+		mockQueries.EXPECT().
+			GetUserByUserID(ctx, uIDUuid).
+			Return(db.User{ID: 1}, nil)
+
+		expectTx(ctx)
+
+		mockQueries.EXPECT().
+			GetTodoForUpdate(ctx, db.GetTodoForUpdateParams{ID: todoID, UserID: 1}).
+			Return(db.Todo{ID: todoID, DueAt: dueAt, RemindAt: remindAt, Priority: 3}, nil)
+
+		mockQueries.EXPECT().
+			UpdateTodo(ctx, db.UpdateTodoParams{
+				ID:          todoID,
+				Description: req.Description,
+				Completed:   pgtype.Bool{Bool: true, Valid: true},
+				Position:    pgtype.Numeric{Int: big.NewInt(100), Valid: true},
+				UserID:      1,
+				DueAt:       dueAt,
+				RemindAt:    remindAt,
+				Priority:    3,
+				Language:    "english",
+			}).
+			Return(db.Todo{ID: todoID, DueAt: dueAt, RemindAt: remindAt}, nil)
+
+		todo, err := todoService.UpdateTodo(ctx, uIDUuid, todoID, req)
+
+		require.NoError(t, err)
+		assert.Equal(t, dueAt, todo.DueAt)
+	})
+
+	t.Run("UpdateTodo_KeepsLanguage", func(t *testing.T) {
+		ctx := context.Background()
+		var todoID int32 = 1
+		// Detection would take this description as English
+		req := services.UpdateTodoRequest{Description: "Sushi", Completed: true, Position: 100}
+
+		mockQueries.EXPECT().
+			GetUserByUserID(ctx, uIDUuid).
+			Return(db.User{ID: 1}, nil)
+
+		expectTx(ctx)
+
+		mockQueries.EXPECT().
+			GetTodoForUpdate(ctx, db.GetTodoForUpdateParams{ID: todoID, UserID: 1}).
+			Return(db.Todo{ID: todoID, Description: "Sushi", Language: "japanese"}, nil)
+
+		mockQueries.EXPECT().
+			UpdateTodo(ctx, gomock.Any()).
+			DoAndReturn(func(ctx context.Context, arg db.UpdateTodoParams) (db.Todo, error) {
+				assert.Equal(t, "japanese", arg.Language)
+				return db.Todo{ID: todoID, Language: arg.Language}, nil
+			})
+
+		todo, err := todoService.UpdateTodo(ctx, uIDUuid, todoID, req)
+
+		require.NoError(t, err)
+		assert.Equal(t, "japanese", todo.Language)
+	})
+
+	t.Run("UpdateTodo_DetectsLanguageOfNewDescription", func(t *testing.T) {
+		ctx := context.Background()
+		var todoID int32 = 1
+		req := services.UpdateTodoRequest{Description: "牛乳を買う", Position: 100}
+
+		mockQueries.EXPECT().
+			GetUserByUserID(ctx, uIDUuid).
+			Return(db.User{ID: 1}, nil)
+
+		expectTx(ctx)
+
+		mockQueries.EXPECT().
+			GetTodoForUpdate(ctx, db.GetTodoForUpdateParams{ID: todoID, UserID: 1}).
+			Return(db.Todo{ID: todoID, Description: "Buy milk", Language: "english"}, nil)
+
+		mockQueries.EXPECT().
+			UpdateTodo(ctx, gomock.Any()).
+			DoAndReturn(func(ctx context.Context, arg db.UpdateTodoParams) (db.Todo, error) {
+				assert.Equal(t, "japanese", arg.Language)
+				return db.Todo{ID: todoID, Language: arg.Language}, nil
+			})
+
+		todo, err := todoService.UpdateTodo(ctx, uIDUuid, todoID, req)
+
+		require.NoError(t, err)
+		assert.Equal(t, "japanese", todo.Language)
+	})
+
+	t.Run("UpdateTodo_ResetsPriority", func(t *testing.T) {
+		ctx := context.Background()
+		var todoID int32 = 1
+		req := services.UpdateTodoRequest{Description: "Updated todo", Position: 100, Priority: "none"}
+
+		mockQueries.EXPECT().
+			GetUserByUserID(ctx, uIDUuid).
+			Return(db.User{ID: 1}, nil)
+
+		expectTx(ctx)
+
+		mockQueries.EXPECT().
+			GetTodoForUpdate(ctx, db.GetTodoForUpdateParams{ID: todoID, UserID: 1}).
+			Return(db.Todo{ID: todoID, Priority: 3}, nil)
+
+		mockQueries.EXPECT().
+			UpdateTodo(ctx, gomock.Any()).
+			DoAndReturn(func(ctx context.Context, arg db.UpdateTodoParams) (db.Todo, error) {
+				assert.Equal(t, int16(0), arg.Priority)
+				return db.Todo{ID: todoID, Priority: arg.Priority}, nil
+			})
+
+		todo, err := todoService.UpdateTodo(ctx, uIDUuid, todoID, req)
+
+		require.NoError(t, err)
+		assert.Equal(t, int16(0), todo.Priority)
+	})
+
+	t.Run("UpdateTodo_ClearsDates", func(t *testing.T) {
+		ctx := context.Background()
+		var todoID int32 = 1
+		var req services.UpdateTodoRequest
+		require.NoError(t, json.Unmarshal([]byte(`{"description": "Updated todo", "position": 100, "due_at": null, "remind_at": ""}`), &req))
+
+		mockQueries.EXPECT().
+			GetUserByUserID(ctx, uIDUuid).
+			Return(db.User{ID: 1}, nil)
+
+		expectTx(ctx)
+
+		mockQueries.EXPECT().
+			GetTodoForUpdate(ctx, db.GetTodoForUpdateParams{ID: todoID, UserID: 1}).
+			Return(db.Todo{
+				ID:       todoID,
+				DueAt:    pgtype.Timestamptz{Time: time.Now(), Valid: true},
+				RemindAt: pgtype.Timestamptz{Time: time.Now(), Valid: true},
+			}, nil)
+
+		mockQueries.EXPECT().
+			UpdateTodo(ctx, db.UpdateTodoParams{
+				ID:          todoID,
+				Description: req.Description,
+				Completed:   pgtype.Bool{Valid: true},
+				Position:    pgtype.Numeric{Int: big.NewInt(100), Valid: true},
+				UserID:      1,
+				Language:    "english",
+			}).
+			Return(db.Todo{ID: todoID}, nil)
+
+		todo, err := todoService.UpdateTodo(ctx, uIDUuid, todoID, req)
+
+		require.NoError(t, err)
+		assert.False(t, todo.DueAt.Valid)
+	})
+
+	t.Run("UpdateTodo_InvalidDescription", func(t *testing.T) {
+		ctx := context.Background()
+		req := services.UpdateTodoRequest{Description: "Water \x02plants\x03", Position: 100}
+
+		todo, err := todoService.UpdateTodo(ctx, uIDUuid, 1, req)
+
+		assert.Equal(t, utils.ErrInvalidDescription, err)
+		assert.Nil(t, todo)
+	})
+
+	t.Run("UpdateTodo_MultilineDescription", func(t *testing.T) {
+		ctx := context.Background()
+		var todoID int32 = 1
+		req := services.UpdateTodoRequest{Description: "Groceries:\n\tmilk\r\n\teggs", Position: 100}
 
 		mockQueries.EXPECT().
 			GetUserByUserID(ctx, uIDUuid).
-			Return(db.User{ID: 1}, nil)
-
-		mockQueries.EXPECT().
-			CreateTodo(ctx, db.CreateTodoParams{
-				UserID:      1,
-				Description: req.Description,
-			}).
-			Return(db.Todo{ID: 1, Description: req.Description}, nil)
+			Return(db.User{}, errors.New("user not found"))
 
-		todo, err := todoService.CreateTodo(ctx, uIDUuid, req)
+		_, err := todoService.UpdateTodo(ctx, uIDUuid, todoID, req)
 
-		require.NoError(t, err)
-		assert.Equal(t, req.Description, todo.Description)
+		// Got past the description
+		assert.Equal(t, utils.ErrInvalidUID, err)
 	})
 
-	t.Run("CreateTodo_UserNotFound", func(t *testing.T) {
+	t.Run("UpdateTodo_UserNotFound", func(t *testing.T) {
 		ctx := context.Background()
-		req := services.CreateTodoRequest{
-			Description: "Test todo",
+		var todoID int32 = 1
+		req := services.UpdateTodoRequest{
+			Description: "Updated todo",
+			Completed:   true,
+			Position:    100,
 		}
 
 		mockQueries.EXPECT().
 			GetUserByUserID(ctx, uIDUuid).
 			Return(db.User{}, errors.New("user not found"))
 
-		todo, err := todoService.CreateTodo(ctx, uIDUuid, req)
+		todo, err := todoService.UpdateTodo(ctx, uIDUuid, todoID, req)
 
 		assert.Error(t, err)
 		assert.Nil(t, todo)
 	})
 
-	t.Run("CreateTodo_DBError", func(t *testing.T) {
+	t.Run("UpdateTodo_DBError", func(t *testing.T) {
 		ctx := context.Background()
-		req := services.CreateTodoRequest{
-			Description: "Test todo",
+		var todoID int32 = 1
+		req := services.UpdateTodoRequest{
+			Description: "Updated todo",
+			Completed:   true,
+			Position:    100,
 		}
 
 		mockQueries.EXPECT().
 			GetUserByUserID(ctx, uIDUuid).
 			Return(db.User{ID: 1}, nil)
 
+		expectTx(ctx)
+
 		mockQueries.EXPECT().
-			CreateTodo(ctx, db.CreateTodoParams{
-				UserID:      1,
+			GetTodoForUpdate(ctx, db.GetTodoForUpdateParams{ID: todoID, UserID: 1}).
+			Return(db.Todo{ID: todoID}, nil)
+
+		mockQueries.EXPECT().
+			UpdateTodo(ctx, db.UpdateTodoParams{
+				ID:          todoID,
 				Description: req.Description,
+				Completed:   pgtype.Bool{Bool: req.Completed, Valid: true},
+				Position:    pgtype.Numeric{Int: big.NewInt(req.Position), Valid: true},
+				UserID:      1,
+				Language:    "english",
 			}).
 			Return(db.Todo{}, errors.New("db error"))
 
-		todo, err := todoService.CreateTodo(ctx, uIDUuid, req)
+		todo, err := todoService.UpdateTodo(ctx, uIDUuid, todoID, req)
 
 		assert.Error(t, err)
 		assert.Nil(t, todo)
 	})
 
-	t.Run("ListTodos", func(t *testing.T) {
+	t.Run("UpdateTodo_NotFound", func(t *testing.T) {
 		ctx := context.Background()
+		var todoID int32 = 1
+		req := services.UpdateTodoRequest{
+			Description: "Updated todo",
+			Position:    100,
+		}
 
 		mockQueries.EXPECT().
 			GetUserByUserID(ctx, uIDUuid).
 			Return(db.User{ID: 1}, nil)
 
+		expectTx(ctx)
+
 		mockQueries.EXPECT().
-			ListTodos(ctx, int32(1)).
-			Return([]db.Todo{{ID: 1, Description: "Test todo"}}, nil)
+			GetTodoForUpdate(ctx, db.GetTodoForUpdateParams{ID: todoID, UserID: 1}).
+			Return(db.Todo{}, pgx.ErrNoRows)
 
-		todos, err := todoService.ListTodos(ctx, uIDUuid)
+		todo, err := todoService.UpdateTodo(ctx, uIDUuid, todoID, req)
 
-		require.NoError(t, err)
-		assert.Len(t, *todos, 1)
+		assert.Equal(t, utils.ErrNoRowsMatchedSQLC, err)
+		assert.Nil(t, todo)
 	})
 
-	t.Run("ListTodos_UserNotFound", func(t *testing.T) {
+	t.Run("UpdateTodo_CompleteRecurring", func(t *testing.T) {
 		ctx := context.Background()
+		var todoID int32 = 1
+		dueAt := "2024-01-31T18:00:00+09:00"
+		remindAt := "2024-01-31T17:00:00+09:00"
+		recurrence := "FREQ=MONTHLY;COUNT=3"
+		req := services.UpdateTodoRequest{
+			Description: "Pay rent",
+			Completed:   true,
+			Position:    100,
+			DueAt:       &dueAt,
+			RemindAt:    &remindAt,
+			Recurrence:  &recurrence,
+			Priority:    "high",
+		}
 
 		mockQueries.EXPECT().
 			GetUserByUserID(ctx, uIDUuid).
-			Return(db.User{}, errors.New("user not found"))
+			Return(db.User{ID: 1}, nil)
 
-		todos, err := todoService.ListTodos(ctx, uIDUuid)
+		expectTx(ctx)
 
-		assert.Error(t, err)
-		assert.Nil(t, todos)
-	})
+		mockQueries.EXPECT().
+			GetTodoForUpdate(ctx, db.GetTodoForUpdateParams{ID: todoID, UserID: 1}).
+			Return(db.Todo{ID: todoID, ListID: 20, Completed: pgtype.Bool{Bool: false, Valid: true}}, nil)
 
-	t.Run("ListTodos_DBError", func(t *testing.T) {
-		ctx := context.Background()
+		mockQueries.EXPECT().
+			GetUserPreferences(ctx, int32(1)).
+			Return(db.UserPreference{UserID: 1, TimeZone: "Asia/Tokyo"}, nil)
 
+		// February has no 31st, so the next occurrence is in March
 		mockQueries.EXPECT().
-			GetUserByUserID(ctx, uIDUuid).
-			Return(db.User{ID: 1}, nil)
+			CreateTodo(ctx, gomock.Any()).
+			DoAndReturn(func(ctx context.Context, arg db.CreateTodoParams) (db.Todo, error) {
+				assert.Equal(t, int32(1), arg.UserID)
+				assert.Equal(t, int32(20), arg.ListID)
+				assert.Equal(t, "Pay rent", arg.Description)
+				assert.True(t, arg.DueAt.Time.Equal(time.Date(2024, 3, 31, 9, 0, 0, 0, time.UTC)))
+				assert.True(t, arg.RemindAt.Time.Equal(time.Date(2024, 3, 31, 8, 0, 0, 0, time.UTC)))
+				assert.Equal(t, pgtype.Text{String: "FREQ=MONTHLY;COUNT=2", Valid: true}, arg.Recurrence)
+				assert.Equal(t, int16(3), arg.Priority)
+				return db.Todo{ID: 2}, nil
+			})
 
 		mockQueries.EXPECT().
-			ListTodos(ctx, int32(1)).
-			Return(nil, errors.New("db error"))
+			CopyTodoItems(ctx, db.CopyTodoItemsParams{ToTodoID: 2, FromTodoID: todoID}).
+			Return(nil)
 
-		todos, err := todoService.ListTodos(ctx, uIDUuid)
+		mockQueries.EXPECT().
+			CopyTodoTags(ctx, db.CopyTodoTagsParams{ToTodoID: 2, FromTodoID: todoID}).
+			Return(nil)
 
-		assert.Error(t, err)
-		assert.Nil(t, todos)
+		// The rule has moved over to the next occurrence
+		mockQueries.EXPECT().
+			UpdateTodo(ctx, gomock.Any()).
+			DoAndReturn(func(ctx context.Context, arg db.UpdateTodoParams) (db.Todo, error) {
+				assert.False(t, arg.Recurrence.Valid)
+				return db.Todo{ID: todoID, Completed: arg.Completed, DueAt: arg.DueAt}, nil
+			})
+
+		todo, err := todoService.UpdateTodo(ctx, uIDUuid, todoID, req)
+
+		require.NoError(t, err)
+		assert.True(t, todo.Completed.Bool)
 	})
 
-	t.Run("SearchTodos", func(t *testing.T) {
+	t.Run("UpdateTodo_CompleteRecurring_Stored", func(t *testing.T) {
 		ctx := context.Background()
-		keyword := "Test"
+		var todoID int32 = 1
+		dueAt := pgtype.Timestamptz{Time: time.Date(2024, 1, 31, 9, 0, 0, 0, time.UTC), Valid: true}
+		var req services.UpdateTodoRequest
+		require.NoError(t, json.Unmarshal([]byte(`{"description": "Pay rent", "completed": true, "position": 100}`), &req))
 
 		mockQueries.EXPECT().
 			GetUserByUserID(ctx, uIDUuid).
 			Return(db.User{ID: 1}, nil)
 
+		expectTx(ctx)
+
 		mockQueries.EXPECT().
-			SearchTodos(ctx, db.SearchTodosParams{
-				UserID:    1,
-				ToTsquery: keyword,
-			}).
-			Return([]db.Todo{{ID: 1, Description: "Test todo"}}, nil)
+			GetTodoForUpdate(ctx, db.GetTodoForUpdateParams{ID: todoID, UserID: 1}).
+			Return(db.Todo{
+				ID:         todoID,
+				ListID:     20,
+				DueAt:      dueAt,
+				Recurrence: pgtype.Text{String: "FREQ=MONTHLY;COUNT=3", Valid: true},
+				Completed:  pgtype.Bool{Bool: false, Valid: true},
+			}, nil)
 
-		todos, err := todoService.SearchTodos(ctx, uIDUuid, keyword)
+		mockQueries.EXPECT().
+			GetUserPreferences(ctx, int32(1)).
+			Return(db.UserPreference{UserID: 1, TimeZone: "Asia/Tokyo"}, nil)
 
-		require.NoError(t, err)
-		assert.Len(t, *todos, 1)
-	})
+		// The rule and the due date the body leaves out are those of the todo
+		mockQueries.EXPECT().
+			CreateTodo(ctx, gomock.Any()).
+			DoAndReturn(func(ctx context.Context, arg db.CreateTodoParams) (db.Todo, error) {
+				assert.True(t, arg.DueAt.Time.Equal(time.Date(2024, 3, 31, 9, 0, 0, 0, time.UTC)))
+				assert.Equal(t, pgtype.Text{String: "FREQ=MONTHLY;COUNT=2", Valid: true}, arg.Recurrence)
+				return db.Todo{ID: 2}, nil
+			})
 
-	t.Run("SearchTodos_UserNotFound", func(t *testing.T) {
-		ctx := context.Background()
-		keyword := "Test"
+		mockQueries.EXPECT().
+			CopyTodoItems(ctx, db.CopyTodoItemsParams{ToTodoID: 2, FromTodoID: todoID}).
+			Return(nil)
 
 		mockQueries.EXPECT().
-			GetUserByUserID(ctx, uIDUuid).
-			Return(db.User{}, errors.New("user not found"))
+			CopyTodoTags(ctx, db.CopyTodoTagsParams{ToTodoID: 2, FromTodoID: todoID}).
+			Return(nil)
 
-		todos, err := todoService.SearchTodos(ctx, uIDUuid, keyword)
+		mockQueries.EXPECT().
+			UpdateTodo(ctx, gomock.Any()).
+			DoAndReturn(func(ctx context.Context, arg db.UpdateTodoParams) (db.Todo, error) {
+				assert.Equal(t, dueAt, arg.DueAt)
+				assert.False(t, arg.Recurrence.Valid)
+				return db.Todo{ID: todoID, Completed: arg.Completed, DueAt: arg.DueAt}, nil
+			})
 
-		assert.Error(t, err)
-		assert.Nil(t, todos)
+		todo, err := todoService.UpdateTodo(ctx, uIDUuid, todoID, req)
+
+		require.NoError(t, err)
+		assert.True(t, todo.Completed.Bool)
 	})
 
-	t.Run("SearchTodos_DBError", func(t *testing.T) {
+	t.Run("UpdateTodo_ClearsDueDateOfRecurring", func(t *testing.T) {
 		ctx := context.Background()
-		keyword := "Test"
+		var todoID int32 = 1
+		var req services.UpdateTodoRequest
+		require.NoError(t, json.Unmarshal([]byte(`{"description": "Pay rent", "position": 100, "due_at": null}`), &req))
 
 		mockQueries.EXPECT().
 			GetUserByUserID(ctx, uIDUuid).
 			Return(db.User{ID: 1}, nil)
 
+		expectTx(ctx)
+
 		mockQueries.EXPECT().
-			SearchTodos(ctx, db.SearchTodosParams{
-				UserID:    1,
-				ToTsquery: keyword,
-			}).
-			Return(nil, errors.New("db error"))
+			GetTodoForUpdate(ctx, db.GetTodoForUpdateParams{ID: todoID, UserID: 1}).
+			Return(db.Todo{
+				ID:         todoID,
+				DueAt:      pgtype.Timestamptz{Time: time.Now(), Valid: true},
+				Recurrence: pgtype.Text{String: "FREQ=WEEKLY", Valid: true},
+			}, nil)
 
-		todos, err := todoService.SearchTodos(ctx, uIDUuid, keyword)
+		todo, err := todoService.UpdateTodo(ctx, uIDUuid, todoID, req)
 
-		assert.Error(t, err)
-		assert.Nil(t, todos)
+		assert.Equal(t, utils.ErrRecurrenceWithoutDueDate, err)
+		assert.Nil(t, todo)
 	})
 
-	t.Run("UpdateTodo", func(t *testing.T) {
+	t.Run("UpdateTodo_CompleteRecurring_LastOccurrence", func(t *testing.T) {
 		ctx := context.Background()
 		var todoID int32 = 1
+		dueAt := "2024-01-31T18:00:00+09:00"
+		recurrence := "FREQ=MONTHLY;COUNT=1"
 		req := services.UpdateTodoRequest{
-			Description: "Updated todo",
+			Description: "Pay rent",
 			Completed:   true,
 			Position:    100,
+			DueAt:       &dueAt,
+			Recurrence:  &recurrence,
 		}
 
 		mockQueries.EXPECT().
 			GetUserByUserID(ctx, uIDUuid).
 			Return(db.User{ID: 1}, nil)
 
+		expectTx(ctx)
+
 		mockQueries.EXPECT().
-			UpdateTodo(ctx, db.UpdateTodoParams{
-				ID:          todoID,
-				Description: req.Description,
-				Completed:   pgtype.Bool{Bool: req.Completed, Valid: true},
-				Position:    pgtype.Numeric{Int: big.NewInt(req.Position), Valid: true},
-				UserID:      1,
-			}).
-			Return(db.Todo{ID: todoID, Description: req.Description, Completed: pgtype.Bool{Bool: req.Completed, Valid: true}}, nil)
+			GetTodoForUpdate(ctx, db.GetTodoForUpdateParams{ID: todoID, UserID: 1}).
+			Return(db.Todo{ID: todoID}, nil)
+
+		mockQueries.EXPECT().
+			GetUserPreferences(ctx, int32(1)).
+			Return(db.UserPreference{}, pgx.ErrNoRows)
+
+		// No CreateTodo, as the rule has ended
+		mockQueries.EXPECT().
+			UpdateTodo(ctx, gomock.Any()).
+			Return(db.Todo{ID: todoID, Completed: pgtype.Bool{Bool: true, Valid: true}}, nil)
 
 		todo, err := todoService.UpdateTodo(ctx, uIDUuid, todoID, req)
 
 		require.NoError(t, err)
-		assert.Equal(t, req.Description, todo.Description)
+		assert.True(t, todo.Completed.Bool)
 	})
 
-	t.Run("UpdateTodo_UserNotFound", func(t *testing.T) {
+	t.Run("UpdateTodo_AlreadyCompletedRecurring", func(t *testing.T) {
 		ctx := context.Background()
 		var todoID int32 = 1
+		dueAt := "2024-01-31"
+		recurrence := "FREQ=WEEKLY"
 		req := services.UpdateTodoRequest{
-			Description: "Updated todo",
+			Description: "Water plants",
 			Completed:   true,
 			Position:    100,
+			DueAt:       &dueAt,
+			Recurrence:  &recurrence,
 		}
 
 		mockQueries.EXPECT().
 			GetUserByUserID(ctx, uIDUuid).
-			Return(db.User{}, errors.New("user not found"))
+			Return(db.User{ID: 1}, nil)
+
+		// For the date-only due date
+		mockQueries.EXPECT().
+			GetUserPreferences(ctx, int32(1)).
+			Return(db.UserPreference{}, pgx.ErrNoRows)
+
+		expectTx(ctx)
+
+		mockQueries.EXPECT().
+			GetTodoForUpdate(ctx, db.GetTodoForUpdateParams{ID: todoID, UserID: 1}).
+			Return(db.Todo{ID: todoID, Completed: pgtype.Bool{Bool: true, Valid: true}}, nil)
+
+		// Only the transition to completed creates the next occurrence
+		mockQueries.EXPECT().
+			UpdateTodo(ctx, gomock.Any()).
+			DoAndReturn(func(ctx context.Context, arg db.UpdateTodoParams) (db.Todo, error) {
+				assert.Equal(t, pgtype.Text{String: "FREQ=WEEKLY", Valid: true}, arg.Recurrence)
+				return db.Todo{ID: todoID, Recurrence: arg.Recurrence}, nil
+			})
 
 		todo, err := todoService.UpdateTodo(ctx, uIDUuid, todoID, req)
 
-		assert.Error(t, err)
-		assert.Nil(t, todo)
+		require.NoError(t, err)
+		assert.True(t, todo.Recurrence.Valid)
 	})
 
-	t.Run("UpdateTodo_DBError", func(t *testing.T) {
+	t.Run("UpdateTodo_CompleteItems", func(t *testing.T) {
 		ctx := context.Background()
 		var todoID int32 = 1
 		req := services.UpdateTodoRequest{
-			Description: "Updated todo",
-			Completed:   true,
-			Position:    100,
+			Description:   "Pack for the trip",
+			Completed:     true,
+			Position:      100,
+			CompleteItems: true,
 		}
 
 		mockQueries.EXPECT().
 			GetUserByUserID(ctx, uIDUuid).
 			Return(db.User{ID: 1}, nil)
 
+		expectTx(ctx)
+
 		mockQueries.EXPECT().
-			UpdateTodo(ctx, db.UpdateTodoParams{
-				ID:          todoID,
-				Description: req.Description,
-				Completed:   pgtype.Bool{Bool: req.Completed, Valid: true},
-				Position:    pgtype.Numeric{Int: big.NewInt(req.Position), Valid: true},
-				UserID:      1,
-			}).
-			Return(db.Todo{}, errors.New("db error"))
+			GetTodoForUpdate(ctx, db.GetTodoForUpdateParams{ID: todoID, UserID: 1}).
+			Return(db.Todo{ID: todoID, ItemsTotal: 5, ItemsCompleted: 3}, nil)
+
+		mockQueries.EXPECT().
+			CompleteTodoItems(ctx, todoID).
+			Return(nil)
+
+		mockQueries.EXPECT().
+			UpdateTodo(ctx, gomock.Any()).
+			Return(db.Todo{ID: todoID, Completed: pgtype.Bool{Bool: true, Valid: true}, ItemsTotal: 5, ItemsCompleted: 5}, nil)
 
 		todo, err := todoService.UpdateTodo(ctx, uIDUuid, todoID, req)
 
-		assert.Error(t, err)
-		assert.Nil(t, todo)
+		require.NoError(t, err)
+		assert.Equal(t, int32(5), todo.ItemsCompleted)
+	})
+
+	t.Run("UpdateTodo_InvalidRecurrence", func(t *testing.T) {
+		ctx := context.Background()
+		dueAt := "2024-01-31T18:00:00Z"
+		noDueAt := ""
+		cases := []struct {
+			recurrence string
+			dueAt      *string
+			want       error
+		}{
+			{recurrence: "FREQ=MINUTELY", dueAt: &dueAt, want: utils.ErrInvalidRecurrence},
+			{recurrence: "FREQ=FORTNIGHTLY", dueAt: &dueAt, want: utils.ErrInvalidRecurrence},
+			{recurrence: "DTSTART:20240101T000000Z\nRRULE:FREQ=DAILY", dueAt: &dueAt, want: utils.ErrInvalidRecurrence},
+			{recurrence: "FREQ=DAILY", dueAt: &noDueAt, want: utils.ErrRecurrenceWithoutDueDate},
+		}
+
+		for _, c := range cases {
+			req := services.UpdateTodoRequest{
+				Description: "Updated todo",
+				Position:    100,
+				DueAt:       c.dueAt,
+				Recurrence:  &c.recurrence,
+			}
+
+			mockQueries.EXPECT().
+				GetUserByUserID(ctx, uIDUuid).
+				Return(db.User{ID: 1}, nil)
+
+			todo, err := todoService.UpdateTodo(ctx, uIDUuid, 1, req)
+
+			assert.Equal(t, c.want, err, c.recurrence)
+			assert.Nil(t, todo)
+		}
+	})
+
+	t.Run("ListOccurrences", func(t *testing.T) {
+		ctx := context.Background()
+		var todoID int32 = 1
+
+		mockQueries.EXPECT().
+			GetUserByUserID(ctx, uIDUuid).
+			Return(db.User{ID: 1}, nil)
+
+		// Due on a Monday
+		mockQueries.EXPECT().
+			GetTodo(ctx, db.GetTodoParams{ID: todoID, UserID: 1}).
+			Return(db.Todo{
+				ID:         todoID,
+				DueAt:      pgtype.Timestamptz{Time: time.Date(2024, 1, 1, 23, 59, 59, 0, time.UTC), Valid: true},
+				Recurrence: pgtype.Text{String: "FREQ=WEEKLY;BYDAY=MO,WE", Valid: true},
+			}, nil)
+
+		mockQueries.EXPECT().
+			GetUserPreferences(ctx, int32(1)).
+			Return(db.UserPreference{}, pgx.ErrNoRows)
+
+		occurrences, err := todoService.ListOccurrences(ctx, uIDUuid, todoID, services.ListOccurrencesRequest{Count: 3})
+
+		require.NoError(t, err)
+		require.Len(t, occurrences, 3)
+		assert.True(t, occurrences[0].Equal(time.Date(2024, 1, 3, 23, 59, 59, 0, time.UTC)))
+		assert.True(t, occurrences[1].Equal(time.Date(2024, 1, 8, 23, 59, 59, 0, time.UTC)))
+		assert.True(t, occurrences[2].Equal(time.Date(2024, 1, 10, 23, 59, 59, 0, time.UTC)))
+	})
+
+	t.Run("ListOccurrences_NotRecurring", func(t *testing.T) {
+		ctx := context.Background()
+		var todoID int32 = 1
+
+		mockQueries.EXPECT().
+			GetUserByUserID(ctx, uIDUuid).
+			Return(db.User{ID: 1}, nil)
+
+		mockQueries.EXPECT().
+			GetTodo(ctx, db.GetTodoParams{ID: todoID, UserID: 1}).
+			Return(db.Todo{ID: todoID}, nil)
+
+		occurrences, err := todoService.ListOccurrences(ctx, uIDUuid, todoID, services.ListOccurrencesRequest{})
+
+		require.NoError(t, err)
+		assert.Empty(t, occurrences)
+	})
+
+	t.Run("ListOccurrences_NotFound", func(t *testing.T) {
+		ctx := context.Background()
+		var todoID int32 = 1
+
+		mockQueries.EXPECT().
+			GetUserByUserID(ctx, uIDUuid).
+			Return(db.User{ID: 1}, nil)
+
+		mockQueries.EXPECT().
+			GetTodo(ctx, db.GetTodoParams{ID: todoID, UserID: 1}).
+			Return(db.Todo{}, pgx.ErrNoRows)
+
+		occurrences, err := todoService.ListOccurrences(ctx, uIDUuid, todoID, services.ListOccurrencesRequest{})
+
+		assert.Equal(t, utils.ErrNoRowsMatchedSQLC, err)
+		assert.Nil(t, occurrences)
 	})
 
 	// Let's say a todo C[pos=300] has been moved inbetween A[pos=100] and B[pos=200]
@@ -341,6 +1660,53 @@ func TestTodoService(t *testing.T) {
 		assert.Nil(t, todo)
 	})
 
+	t.Run("MoveTodo", func(t *testing.T) {
+		ctx := context.Background()
+		var todoID int32 = 3
+		prevpos := int64(100)
+		req := services.MoveTodoRequest{
+			ListID:  20,
+			Prevpos: &prevpos,
+		}
+
+		mockQueries.EXPECT().
+			GetUserByUserID(ctx, uIDUuid).
+			Return(db.User{ID: 1}, nil)
+
+		mockQueries.EXPECT().
+			MoveTodo(ctx, db.MoveTodoParams{
+				ID:      todoID,
+				UserID:  1,
+				ListID:  20,
+				Prevpos: pgtype.Numeric{Int: big.NewInt(100), Valid: true},
+			}).
+			Return(db.Todo{ID: todoID, ListID: 20, Position: pgtype.Numeric{Int: big.NewInt(200), Valid: true}}, nil)
+
+		todo, err := todoService.MoveTodo(ctx, uIDUuid, todoID, req)
+
+		require.NoError(t, err)
+		assert.Equal(t, int32(20), todo.ListID)
+	})
+
+	t.Run("MoveTodo_NotFound", func(t *testing.T) {
+		ctx := context.Background()
+		var todoID int32 = 3
+
+		mockQueries.EXPECT().
+			GetUserByUserID(ctx, uIDUuid).
+			Return(db.User{ID: 1}, nil)
+
+		// Either the todo or the target list is not the user's
+		mockQueries.EXPECT().
+			MoveTodo(ctx, db.MoveTodoParams{ID: todoID, UserID: 1, ListID: 1000}).
+			Return(db.Todo{}, pgx.ErrNoRows)
+
+		todo, err := todoService.MoveTodo(ctx, uIDUuid, todoID, services.MoveTodoRequest{ListID: 1000})
+
+		assert.Equal(t, utils.ErrNoRowsMatchedSQLC, err)
+		assert.Nil(t, todo)
+	})
+
 	t.Run("DeleteTodo", func(t *testing.T) {
 		ctx := context.Background()
 		var todoID int32 = 1
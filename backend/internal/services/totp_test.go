@@ -0,0 +1,93 @@
+package services_test
+
+import (
+	"net/url"
+	"regexp"
+	"testing"
+	"time"
+	"todo-app/internal/services"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTOTP(t *testing.T) {
+	// The SHA-1 seed of the RFC 6238 test vectors ("12345678901234567890") in base32
+	rfcSecret := "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+	t.Run("RFC6238Vectors", func(t *testing.T) {
+		// The RFC lists 8 digit codes; the 6 digit codes are their last 6 digits
+		vectors := map[int64]string{
+			59:         "287082",
+			1111111109: "081804",
+			1111111111: "050471",
+			1234567890: "005924",
+			2000000000: "279037",
+		}
+
+		for unix, want := range vectors {
+			code, err := services.TOTPCode(rfcSecret, services.TOTPStep(time.Unix(unix, 0)))
+
+			require.NoError(t, err)
+			assert.Equal(t, want, code, "at %d", unix)
+		}
+	})
+
+	t.Run("ValidateTOTPCode", func(t *testing.T) {
+		now := time.Unix(1234567890, 0)
+		step := services.TOTPStep(now)
+
+		previous, err := services.TOTPCode(rfcSecret, step-1)
+		require.NoError(t, err)
+		tooOld, err := services.TOTPCode(rfcSecret, step-2)
+		require.NoError(t, err)
+
+		matched, ok := services.ValidateTOTPCode(rfcSecret, "005924", now)
+		assert.True(t, ok)
+		assert.Equal(t, step, matched)
+
+		// A code of the previous period is accepted to allow for clock drift
+		matched, ok = services.ValidateTOTPCode(rfcSecret, previous, now)
+		assert.True(t, ok)
+		assert.Equal(t, step-1, matched)
+
+		_, ok = services.ValidateTOTPCode(rfcSecret, tooOld, now)
+		assert.False(t, ok)
+		_, ok = services.ValidateTOTPCode(rfcSecret, "12345", now)
+		assert.False(t, ok)
+	})
+
+	t.Run("GenerateTOTPSecret", func(t *testing.T) {
+		secret, err := services.GenerateTOTPSecret()
+
+		require.NoError(t, err)
+		assert.Len(t, secret, 32)
+		_, err = services.TOTPCode(secret, 1)
+		assert.NoError(t, err)
+	})
+
+	t.Run("TOTPURI", func(t *testing.T) {
+		t.Setenv("TOTP_ISSUER", "Todo app")
+
+		uri, err := url.Parse(services.TOTPURI(rfcSecret, "test@example.com"))
+
+		require.NoError(t, err)
+		assert.Equal(t, "otpauth", uri.Scheme)
+		assert.Equal(t, "totp", uri.Host)
+		assert.Equal(t, "/Todo app:test@example.com", uri.Path)
+		assert.Equal(t, rfcSecret, uri.Query().Get("secret"))
+		assert.Equal(t, "Todo app", uri.Query().Get("issuer"))
+	})
+
+	t.Run("RecoveryCodes", func(t *testing.T) {
+		codes, err := services.GenerateRecoveryCodes()
+
+		require.NoError(t, err)
+		assert.Len(t, codes, 10)
+		for _, code := range codes {
+			assert.Regexp(t, regexp.MustCompile(`^[a-z2-7]{5}-[a-z2-7]{5}$`), code)
+		}
+		assert.Equal(t, "abcde-fghij", services.NormalizeRecoveryCode(" ABCDEFGHIJ "))
+		assert.Equal(t, "abcde-fghij", services.NormalizeRecoveryCode("abcde-fghij"))
+	})
+}
@@ -0,0 +1,120 @@
+package services
+
+import (
+	"context"
+	"time"
+	"todo-app/internal/db"
+	"todo-app/internal/utils"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type TwoFactorService struct {
+	SqlClient      db.WrappedQuerier
+	PasswordHasher IPasswordHasher
+}
+
+type TOTPEnrollment struct {
+	Secret     string
+	OtpauthURI string
+}
+
+type ConfirmTOTPRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+type DisableTOTPRequest struct {
+	Password string `json:"password" binding:"required"`
+}
+
+func NewTwoFactorService(sqlClient db.WrappedQuerier, passHasher IPasswordHasher) *TwoFactorService {
+	return &TwoFactorService{SqlClient: sqlClient, PasswordHasher: passHasher}
+}
+
+// Generates a new secret; 2FA stays disabled until the enrolment is confirmed with a code generated from it
+// Starting again before confirming replaces the secret
+func (s *TwoFactorService) StartTOTPEnrollment(ctx context.Context, userID pgtype.UUID) (*TOTPEnrollment, error) {
+	user, err := s.SqlClient.GetUserByUserID(ctx, userID)
+	if err != nil {
+		return nil, utils.ErrInvalidUID
+	}
+
+	if user.TotpEnabledAt.Valid {
+		return nil, utils.ErrTwoFactorAlreadyEnabled
+	}
+
+	secret, err := GenerateTOTPSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.SqlClient.StartTOTPEnrollment(ctx, db.StartTOTPEnrollmentParams{
+		TotpSecret: pgtype.Text{String: secret, Valid: true},
+		UserID:     user.UserID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &TOTPEnrollment{Secret: secret, OtpauthURI: TOTPURI(secret, user.Email)}, nil
+}
+
+// Enables 2FA and returns the recovery codes, which cannot be shown again since only their hashes are stored
+func (s *TwoFactorService) ConfirmTOTPEnrollment(ctx context.Context, userID pgtype.UUID, req ConfirmTOTPRequest) ([]string, error) {
+	user, err := s.SqlClient.GetUserByUserID(ctx, userID)
+	if err != nil {
+		return nil, utils.ErrInvalidUID
+	}
+
+	if user.TotpEnabledAt.Valid {
+		return nil, utils.ErrTwoFactorAlreadyEnabled
+	}
+	if !user.TotpSecret.Valid {
+		return nil, utils.ErrTwoFactorNotEnrolled
+	}
+
+	step, ok := ValidateTOTPCode(user.TotpSecret.String, req.Code, time.Now())
+	if !ok {
+		return nil, utils.ErrInvalidTwoFactorCode
+	}
+
+	codes, err := GenerateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+
+	codeHashes := make([]string, 0, len(codes))
+	for _, code := range codes {
+		codeHashes = append(codeHashes, utils.HashToken(code))
+	}
+
+	// The confirming code counts as used so that it cannot be replayed to log in
+	err = s.SqlClient.EnableTOTP(ctx, db.EnableTOTPParams{
+		TotpRecoveryCodes: codeHashes,
+		TotpLastUsedStep:  pgtype.Int8{Int64: step, Valid: true},
+		UserID:            user.UserID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+// Requires the password so that a stolen access token alone cannot turn 2FA off
+func (s *TwoFactorService) DisableTOTP(ctx context.Context, userID pgtype.UUID, req DisableTOTPRequest) error {
+	user, err := s.SqlClient.GetUserByUserID(ctx, userID)
+	if err != nil {
+		return utils.ErrInvalidUID
+	}
+
+	if !user.TotpSecret.Valid {
+		return utils.ErrTwoFactorNotEnabled
+	}
+
+	if err = s.PasswordHasher.CompareHashAndPassword(user.PasswordHash, []byte(req.Password)); err != nil {
+		return utils.ErrInvalidCurrentPassword
+	}
+
+	return s.SqlClient.DisableTOTP(ctx, user.UserID)
+}
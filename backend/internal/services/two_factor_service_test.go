@@ -0,0 +1,179 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"todo-app/internal/db"
+	mock_db "todo-app/internal/db/_mock"
+	"todo-app/internal/services"
+	mock_services "todo-app/internal/services/_mock"
+	"todo-app/internal/utils"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestTwoFactorService(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockQueries := mock_db.NewMockWrappedQuerier(ctrl)
+	mockPassHasher := mock_services.NewMockIPasswordHasher(ctrl)
+
+	twoFactorService := services.NewTwoFactorService(mockQueries, mockPassHasher)
+
+	uIDStr := "00010203-0405-0607-0809-0a0b0c0d0e0f"
+	uIDUuid, _ := utils.StringToUUID(uIDStr)
+	totpSecret := "JBSWY3DPEHPK3PXP"
+	user := db.User{UserID: uIDUuid, Email: "test@example.com", PasswordHash: []byte("hashedpassword")}
+	enrollingUser := user
+	enrollingUser.TotpSecret = pgtype.Text{String: totpSecret, Valid: true}
+	enabledUser := enrollingUser
+	enabledUser.TotpEnabledAt = pgtype.Timestamptz{Time: time.Now(), Valid: true}
+
+	t.Run("StartTOTPEnrollment", func(t *testing.T) {
+		ctx := context.Background()
+
+		mockQueries.EXPECT().
+			GetUserByUserID(ctx, uIDUuid).
+			Return(user, nil)
+
+		var storedSecret string
+		mockQueries.EXPECT().
+			StartTOTPEnrollment(ctx, gomock.Any()).
+			DoAndReturn(func(ctx context.Context, arg db.StartTOTPEnrollmentParams) error {
+				assert.Equal(t, uIDUuid, arg.UserID)
+				storedSecret = arg.TotpSecret.String
+				return nil
+			})
+
+		enrollment, err := twoFactorService.StartTOTPEnrollment(ctx, uIDUuid)
+
+		require.NoError(t, err)
+		assert.Equal(t, storedSecret, enrollment.Secret)
+		assert.Contains(t, enrollment.OtpauthURI, "otpauth://totp/")
+		assert.Contains(t, enrollment.OtpauthURI, "secret="+enrollment.Secret)
+	})
+
+	t.Run("StartTOTPEnrollment_AlreadyEnabled", func(t *testing.T) {
+		ctx := context.Background()
+
+		mockQueries.EXPECT().
+			GetUserByUserID(ctx, uIDUuid).
+			Return(enabledUser, nil)
+
+		enrollment, err := twoFactorService.StartTOTPEnrollment(ctx, uIDUuid)
+
+		assert.Equal(t, utils.ErrTwoFactorAlreadyEnabled, err)
+		assert.Nil(t, enrollment)
+	})
+
+	t.Run("ConfirmTOTPEnrollment", func(t *testing.T) {
+		ctx := context.Background()
+		code, err := services.TOTPCode(totpSecret, services.TOTPStep(time.Now()))
+		require.NoError(t, err)
+
+		mockQueries.EXPECT().
+			GetUserByUserID(ctx, uIDUuid).
+			Return(enrollingUser, nil)
+
+		var storedHashes []string
+		mockQueries.EXPECT().
+			EnableTOTP(ctx, gomock.Any()).
+			DoAndReturn(func(ctx context.Context, arg db.EnableTOTPParams) error {
+				assert.Equal(t, uIDUuid, arg.UserID)
+				assert.True(t, arg.TotpLastUsedStep.Valid)
+				storedHashes = arg.TotpRecoveryCodes
+				return nil
+			})
+
+		codes, err := twoFactorService.ConfirmTOTPEnrollment(ctx, uIDUuid, services.ConfirmTOTPRequest{Code: code})
+
+		require.NoError(t, err)
+		require.Len(t, codes, 10)
+		// Only the hashes of the recovery codes are stored
+		for i, code := range codes {
+			assert.Equal(t, utils.HashToken(code), storedHashes[i])
+		}
+	})
+
+	t.Run("ConfirmTOTPEnrollment_InvalidCode", func(t *testing.T) {
+		ctx := context.Background()
+
+		mockQueries.EXPECT().
+			GetUserByUserID(ctx, uIDUuid).
+			Return(enrollingUser, nil)
+
+		codes, err := twoFactorService.ConfirmTOTPEnrollment(ctx, uIDUuid, services.ConfirmTOTPRequest{Code: "000000x"})
+
+		assert.Equal(t, utils.ErrInvalidTwoFactorCode, err)
+		assert.Nil(t, codes)
+	})
+
+	t.Run("ConfirmTOTPEnrollment_NotStarted", func(t *testing.T) {
+		ctx := context.Background()
+
+		mockQueries.EXPECT().
+			GetUserByUserID(ctx, uIDUuid).
+			Return(user, nil)
+
+		codes, err := twoFactorService.ConfirmTOTPEnrollment(ctx, uIDUuid, services.ConfirmTOTPRequest{Code: "123456"})
+
+		assert.Equal(t, utils.ErrTwoFactorNotEnrolled, err)
+		assert.Nil(t, codes)
+	})
+
+	t.Run("DisableTOTP", func(t *testing.T) {
+		ctx := context.Background()
+		req := services.DisableTOTPRequest{Password: "current-password-1"}
+
+		mockQueries.EXPECT().
+			GetUserByUserID(ctx, uIDUuid).
+			Return(enabledUser, nil)
+
+		mockPassHasher.EXPECT().
+			CompareHashAndPassword(enabledUser.PasswordHash, []byte(req.Password)).
+			Return(nil)
+
+		mockQueries.EXPECT().
+			DisableTOTP(ctx, uIDUuid).
+			Return(nil)
+
+		err := twoFactorService.DisableTOTP(ctx, uIDUuid, req)
+
+		require.NoError(t, err)
+	})
+
+	t.Run("DisableTOTP_WrongPassword", func(t *testing.T) {
+		ctx := context.Background()
+		req := services.DisableTOTPRequest{Password: "wrong-password"}
+
+		mockQueries.EXPECT().
+			GetUserByUserID(ctx, uIDUuid).
+			Return(enabledUser, nil)
+
+		mockPassHasher.EXPECT().
+			CompareHashAndPassword(enabledUser.PasswordHash, []byte(req.Password)).
+			Return(errors.New("mismatched"))
+
+		err := twoFactorService.DisableTOTP(ctx, uIDUuid, req)
+
+		assert.Equal(t, utils.ErrInvalidCurrentPassword, err)
+	})
+
+	t.Run("DisableTOTP_NotEnabled", func(t *testing.T) {
+		ctx := context.Background()
+
+		mockQueries.EXPECT().
+			GetUserByUserID(ctx, uIDUuid).
+			Return(user, nil)
+
+		err := twoFactorService.DisableTOTP(ctx, uIDUuid, services.DisableTOTPRequest{Password: "current-password-1"})
+
+		assert.Equal(t, utils.ErrTwoFactorNotEnabled, err)
+	})
+}
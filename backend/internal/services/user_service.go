@@ -2,22 +2,38 @@ package services
 
 import (
 	"context"
+	"errors"
+	"time"
 	"todo-app/internal/db"
 	"todo-app/internal/utils"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
 type UserService struct {
-	SqlClient db.WrappedQuerier
+	SqlClient         db.WrappedQuerier
+	RefreshTokenStore IRefreshTokenStore
+	SessionStore      ISessionStore
+	// How long a deleted account can be restored before it is purged
+	DeletionGracePeriod time.Duration
 }
 
 type UpdateUsernameRequest struct {
 	Username string `json:"username" binding:"required"`
 }
 
-func NewUserService(sqlClient db.WrappedQuerier) *UserService {
-	return &UserService{SqlClient: sqlClient}
+func NewUserService(sqlClient db.WrappedQuerier, refreshTokenStore IRefreshTokenStore, sessionStore ISessionStore) *UserService {
+	return &UserService{
+		SqlClient:           sqlClient,
+		RefreshTokenStore:   refreshTokenStore,
+		SessionStore:        sessionStore,
+		DeletionGracePeriod: AccountDeletionGracePeriodFromEnv(),
+	}
+}
+
+func AccountDeletionGracePeriodFromEnv() time.Duration {
+	return time.Duration(envInt("ACCOUNT_DELETION_GRACE_PERIOD_DAY", 30)) * 24 * time.Hour
 }
 
 func (s *UserService) GetMe(ctx context.Context, userID pgtype.UUID) (*db.User, error) {
@@ -36,12 +52,53 @@ func (s *UserService) UpdateUsername(ctx context.Context, userID pgtype.UUID, re
 	})
 }
 
-func (s *UserService) DeleteUser(ctx context.Context, userID pgtype.UUID) error {
-	user, err := s.SqlClient.DeleteUser(ctx, userID)
+// Marks the account for deletion and signs out every session; the account is purged once the grace period has passed unless restored.
+// Returns the time after which it is purged.
+func (s *UserService) DeleteUser(ctx context.Context, userID pgtype.UUID) (time.Time, error) {
+	requestedAt, err := s.SqlClient.RequestUserDeletion(ctx, userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return time.Time{}, utils.ErrNoRowsMatchedSQLC
+		}
+		return time.Time{}, err
+	}
+
+	if err = revokeSessions(ctx, s.RefreshTokenStore, s.SessionStore, utils.UUIDToString(userID), ""); err != nil {
+		return time.Time{}, err
+	}
+
+	return requestedAt.Time.Add(s.DeletionGracePeriod), nil
+}
+
+// Logging in during the grace period restores the account, as asking for the deletion signed out every session.
+// Returns utils.ErrAccountDeleted once the grace period has passed, when the account only waits to be purged.
+func restoreOnLogin(ctx context.Context, sqlClient db.WrappedQuerier, user db.User, gracePeriod time.Duration) error {
+	if !user.DeletionRequestedAt.Valid {
+		return nil
+	}
+
+	rows, err := sqlClient.RestoreUser(ctx, db.RestoreUserParams{
+		UserID: user.UserID,
+		Cutoff: pgtype.Timestamptz{Time: time.Now().Add(-gracePeriod), Valid: true},
+	})
+	if err != nil {
+		return err
+	} else if rows == 0 {
+		return utils.ErrAccountDeleted
+	}
+
+	return nil
+}
+
+func (s *UserService) RestoreUser(ctx context.Context, userID pgtype.UUID) error {
+	rows, err := s.SqlClient.RestoreUser(ctx, db.RestoreUserParams{
+		UserID: userID,
+		Cutoff: pgtype.Timestamptz{Time: time.Now().Add(-s.DeletionGracePeriod), Valid: true},
+	})
 	if err != nil {
 		return err
-	} else if user.ID == 0 {
-		return utils.ErrNoRowsMatchedSQLC
+	} else if rows == 0 {
+		return utils.ErrAccountNotPendingDeletion
 	}
 
 	return nil
@@ -4,11 +4,15 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 	"todo-app/internal/db"
 	mock_db "todo-app/internal/db/_mock"
 	"todo-app/internal/services"
+	mock_services "todo-app/internal/services/_mock"
 	"todo-app/internal/utils"
 
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
@@ -19,10 +23,15 @@ func TestUserService(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockQueries := mock_db.NewMockWrappedQuerier(ctrl)
-	userService := services.NewUserService(mockQueries)
+	mockRefreshTokenStore := mock_services.NewMockIRefreshTokenStore(ctrl)
+	mockSessionStore := mock_services.NewMockISessionStore(ctrl)
+	userService := services.NewUserService(mockQueries, mockRefreshTokenStore, mockSessionStore)
+	userService.DeletionGracePeriod = 30 * 24 * time.Hour
 
 	uIDStr := "00010203-0405-0607-0809-0a0b0c0d0e0f"
 	uIDUuid, _ := utils.StringToUUID(uIDStr)
+	sessionID := "session-id-123"
+	requestedAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
 
 	t.Run("GetMe", func(t *testing.T) {
 		ctx := context.Background()
@@ -59,14 +68,55 @@ func TestUserService(t *testing.T) {
 		ctx := context.Background()
 
 		mockQueries.EXPECT().
-			DeleteUser(ctx, uIDUuid).
-			Return(db.User{ID: 1, UserID: uIDUuid}, nil)
+			RequestUserDeletion(ctx, uIDUuid).
+			Return(pgtype.Timestamptz{Time: requestedAt, Valid: true}, nil)
 
-		err := userService.DeleteUser(ctx, uIDUuid)
+		mockSessionStore.EXPECT().
+			List(ctx, uIDStr).
+			Return([]db.Session{{ID: sessionID, UserID: uIDStr}}, nil)
+
+		mockRefreshTokenStore.EXPECT().
+			RevokeFamily(ctx, sessionID).
+			Return(nil)
+
+		mockSessionStore.EXPECT().
+			Delete(ctx, uIDStr, sessionID).
+			Return(nil)
+
+		purgeAt, err := userService.DeleteUser(ctx, uIDUuid)
+
+		require.NoError(t, err)
+		assert.Equal(t, requestedAt.AddDate(0, 0, 30), purgeAt)
+	})
+
+	t.Run("RestoreUser", func(t *testing.T) {
+		ctx := context.Background()
+
+		mockQueries.EXPECT().
+			RestoreUser(ctx, gomock.Any()).
+			DoAndReturn(func(ctx context.Context, arg db.RestoreUserParams) (int64, error) {
+				assert.Equal(t, uIDUuid, arg.UserID)
+				assert.WithinDuration(t, time.Now().AddDate(0, 0, -30), arg.Cutoff.Time, time.Minute)
+				return 1, nil
+			})
+
+		err := userService.RestoreUser(ctx, uIDUuid)
 
 		require.NoError(t, err)
 	})
 
+	t.Run("RestoreUser_NotPendingDeletion", func(t *testing.T) {
+		ctx := context.Background()
+
+		mockQueries.EXPECT().
+			RestoreUser(ctx, gomock.Any()).
+			Return(int64(0), nil)
+
+		err := userService.RestoreUser(ctx, uIDUuid)
+
+		assert.Equal(t, utils.ErrAccountNotPendingDeletion, err)
+	})
+
 	t.Run("GetMe_UserNotFound", func(t *testing.T) {
 		ctx := context.Background()
 
@@ -115,10 +165,10 @@ func TestUserService(t *testing.T) {
 		ctx := context.Background()
 
 		mockQueries.EXPECT().
-			DeleteUser(ctx, uIDUuid).
-			Return(db.User{}, nil)
+			RequestUserDeletion(ctx, uIDUuid).
+			Return(pgtype.Timestamptz{}, pgx.ErrNoRows)
 
-		err := userService.DeleteUser(ctx, uIDUuid)
+		_, err := userService.DeleteUser(ctx, uIDUuid)
 
 		assert.Equal(t, utils.ErrNoRowsMatchedSQLC, err)
 	})
@@ -127,11 +177,12 @@ func TestUserService(t *testing.T) {
 		ctx := context.Background()
 
 		mockQueries.EXPECT().
-			DeleteUser(ctx, uIDUuid).
-			Return(db.User{}, errors.New("delete failed"))
+			RequestUserDeletion(ctx, uIDUuid).
+			Return(pgtype.Timestamptz{}, errors.New("update failed"))
 
-		err := userService.DeleteUser(ctx, uIDUuid)
+		_, err := userService.DeleteUser(ctx, uIDUuid)
 
 		assert.Error(t, err)
+		assert.NotEqual(t, utils.ErrNoRowsMatchedSQLC, err)
 	})
 }
@@ -7,8 +7,85 @@ var MsgUIDNotFoundInCtx = "UserID not found in context"
 var MsgInternalServerErr = "The server encountered unexpected error"
 var MsgInvalidReq = "Invalid request"
 var MsgInvalidEmailOrPswd = "Invalid email or password"
+var MsgInvalidRefreshToken = "Invalid or expired refresh token"
+var MsgRefreshTokenReused = "Refresh token has already been used"
+var MsgTooManyLoginAttempts = "Too many failed login attempts, try again later"
+var MsgTooManyPasswordResets = "Too many password reset requests, try again later"
+var MsgWeakPassword = "Password does not meet the requirements"
+var MsgInvalidCurrentPassword = "Current password is incorrect"
+var MsgInvalidResetToken = "Invalid or expired password reset token"
+var MsgInvalidVerificationToken = "Invalid or expired verification token"
+var MsgEmailAlreadyVerified = "Email address is already verified"
+var MsgEmailNotVerified = "Email address is not verified"
+var MsgEmailAlreadyInUse = "Email address is already in use"
+var MsgSameEmail = "New email address is the same as the current one"
+var MsgInvalidEmailChangeToken = "Invalid or expired email change token"
+var MsgInvalidChallengeToken = "Invalid or expired two-factor challenge"
+var MsgInvalidTwoFactorCode = "Invalid two-factor authentication code"
+var MsgTwoFactorAlreadyEnabled = "Two-factor authentication is already enabled"
+var MsgTwoFactorNotEnrolled = "Two-factor authentication enrolment has not been started"
+var MsgTwoFactorNotEnabled = "Two-factor authentication is not enabled"
+var MsgUnknownOIDCProvider = "Unknown login provider"
+var MsgOIDCLoginFailed = "Login with the provider failed"
+var MsgOIDCEmailNotVerified = "The provider did not report a verified email address"
+var MsgOIDCAccountNotLinkable = "An account with this email address already exists but its email is not verified"
+var MsgInsufficientScope = "The token does not have the required scope"
+var MsgSessionRequired = "This endpoint cannot be used with a personal access token"
+var MsgForbidden = "You do not have permission to access this resource"
+var MsgAccountDisabled = "This account has been disabled"
+var MsgAccountDeleted = "This account has been deleted"
+var MsgCannotModifySelf = "Admins cannot disable their own account"
+var MsgAccountNotPendingDeletion = "This account is not pending deletion or its grace period has passed"
+var MsgInvalidPreferences = "Preferences are invalid"
+var MsgInvalidAvatar = "Avatar must be a PNG, JPEG or GIF image"
+var MsgAvatarTooLarge = "Avatar image is too large"
+var MsgInvalidDate = "Dates must be RFC 3339 timestamps or dates in YYYY-MM-DD format"
+var MsgInvalidRecurrence = "Recurrence must be an RRULE repeating at most daily, such as FREQ=WEEKLY;BYDAY=MO"
+var MsgRecurrenceWithoutDueDate = "Recurring todos need a due date"
+var MsgInvalidDescription = "Description must not contain control characters other than line breaks and tabs"
+var MsgInboxList = "The inbox cannot be archived or deleted"
+var MsgTagAlreadyExists = "A tag with this name already exists"
+var MsgInvalidCursor = "Invalid or expired cursor"
+var MsgInvalidSort = "Sort must be a comma separated list of position, priority, due_at, created_at, updated_at or description, each descending with a leading -"
 
 var ErrUIDNotFoundInCtx = errors.New("userID not found in context")
 var ErrNoRowsMatchedSQLC = errors.New("no rows in result set")
 var ErrInvalidEmailOrPswd = errors.New("invalid email or password")
 var ErrInvalidUID = errors.New("invalid userID")
+var ErrInvalidRefreshToken = errors.New("invalid or expired refresh token")
+var ErrRefreshTokenReused = errors.New("refresh token reused")
+var ErrTooManyLoginAttempts = errors.New("too many failed login attempts")
+var ErrWeakPassword = errors.New("password does not meet the requirements")
+var ErrInvalidCurrentPassword = errors.New("current password is incorrect")
+var ErrInvalidResetToken = errors.New("invalid or expired password reset token")
+var ErrInvalidVerificationToken = errors.New("invalid or expired verification token")
+var ErrEmailAlreadyVerified = errors.New("email address is already verified")
+var ErrEmailAlreadyInUse = errors.New("email address is already in use")
+var ErrSameEmail = errors.New("new email address is the same as the current one")
+var ErrInvalidEmailChangeToken = errors.New("invalid or expired email change token")
+var ErrTwoFactorRequired = errors.New("two-factor authentication required")
+var ErrInvalidChallengeToken = errors.New("invalid or expired two-factor challenge")
+var ErrInvalidTwoFactorCode = errors.New("invalid two-factor authentication code")
+var ErrTwoFactorAlreadyEnabled = errors.New("two-factor authentication is already enabled")
+var ErrTwoFactorNotEnrolled = errors.New("two-factor authentication enrolment has not been started")
+var ErrTwoFactorNotEnabled = errors.New("two-factor authentication is not enabled")
+var ErrUnknownOIDCProvider = errors.New("unknown oidc provider")
+var ErrInvalidOIDCState = errors.New("invalid oidc state")
+var ErrOIDCLoginFailed = errors.New("oidc login failed")
+var ErrOIDCEmailNotVerified = errors.New("oidc email is not verified")
+var ErrOIDCAccountNotLinkable = errors.New("existing account with unverified email cannot be linked")
+var ErrInvalidPersonalAccessToken = errors.New("invalid or expired personal access token")
+var ErrAccountDisabled = errors.New("account is disabled")
+var ErrAccountDeleted = errors.New("account was deleted and its grace period has passed")
+var ErrCannotModifySelf = errors.New("admins cannot disable their own account")
+var ErrAccountNotPendingDeletion = errors.New("account is not pending deletion or its grace period has passed")
+var ErrInvalidPreferences = errors.New("preferences are invalid")
+var ErrInvalidAvatar = errors.New("avatar is not a supported image")
+var ErrAvatarTooLarge = errors.New("avatar image is too large")
+var ErrInvalidDate = errors.New("date is neither an rfc 3339 timestamp nor a yyyy-mm-dd date")
+var ErrInvalidRecurrence = errors.New("recurrence is not a supported rrule")
+var ErrRecurrenceWithoutDueDate = errors.New("recurring todo has no due date")
+var ErrInvalidDescription = errors.New("description contains control characters")
+var ErrInboxList = errors.New("the inbox cannot be archived or deleted")
+var ErrInvalidCursor = errors.New("invalid or expired cursor")
+var ErrInvalidSort = errors.New("sort is not a list of sortable todo columns")